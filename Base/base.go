@@ -0,0 +1,14 @@
+/*
+Package base は EESLISM の標準の EFL ファイル（曜日設定、給水温度、壁体材料リストなど）と
+標準の気象データを実行ファイルに埋め込みます。
+
+EFLファイルのディレクトリを指定しない場合、eeslism パッケージはこの FS を用います。
+*/
+package base
+
+import "embed"
+
+// FS は Base ディレクトリの EFL ファイルと気象データです。
+//
+//go:embed *.efl *.has
+var FS embed.FS
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+batchMain (Batch Run Command)
+
+`eeslism batch` サブコマンドです。基準の入力データファイルの値をケースごとに置き換えて並行して計算し、
+各ケースの集計値を1つの CSV にまとめます。
+
+ケースの表は CSV または JSON（拡張子で判定）で、置き換えは `<データセット名>.<要素名>.<キー>` の列で指定します。
+`$<名前>` の列は、入力データファイルの `$define <名前>` の値を置き換えます。
+例: `eeslism batch -j 8 --output Room_Tr room.txt variants.csv`
+
+  - `--out-dir`: ケースごとの入力データファイルと計算結果の出力先（`<out-dir>/<ケース名>/`）
+  - `-o`: 集計結果の CSV。省略した場合は `<out-dir>/results.csv`
+  - `--output`: 集計する変数（CONTL と同じ名前）。省略した場合は全ての室の室温
+  - `-j`: 同時に計算するケースの数。省略した場合は CPU 数
+*/
+func batchMain(args []string) {
+	parser := argparse.NewParser("eeslism batch", "Run parameter variants of an input data file in parallel")
+
+	filename := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Input data file name"})
+
+	table := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Variant table (.csv or .json)"})
+
+	efl_path := parser.String("", "efl", &argparse.Options{
+		Default: "Base",
+		Help:    "EFLファイルのディレクトリ"})
+
+	outDir := parser.String("", "out-dir", &argparse.Options{
+		Default: "batch",
+		Help:    "ケースごとの計算結果の出力先ディレクトリ"})
+
+	result := parser.String("o", "result", &argparse.Options{
+		Help: "集計結果のCSVファイル名"})
+
+	outputs := parser.StringList("", "output", &argparse.Options{
+		Help: "集計する変数（CONTLと同じ変数名）"})
+
+	workers := parser.Int("j", "jobs", &argparse.Options{
+		Default: runtime.NumCPU(),
+		Help:    "同時に計算するケースの数"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*table)
+	exitOnError(err)
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(*table)), ".")
+	variants, err := eeslism.ReadVariants(f, format)
+	f.Close()
+	exitOnError(err)
+
+	// Ctrl-C で中断した場合も、それまでに計算したケースの集計結果を書き出す
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	n := 0
+	results, err := eeslism.RunBatch(ctx, eeslism.BatchOptions{
+		Input:   *filename,
+		EflPath: eflPath(*efl_path),
+		OutDir:  *outDir,
+		Workers: *workers,
+		Outputs: *outputs,
+		Done: func(r *eeslism.BatchResult) {
+			n++
+			if r.Err != nil {
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s: %v\n", n, len(variants), r.Variant, r.Err)
+			} else {
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", n, len(variants), r.Variant)
+			}
+		},
+	}, variants)
+	exitOnError(err)
+
+	if *result == "" {
+		*result = filepath.Join(*outDir, "results.csv")
+	}
+	fo, err := os.Create(*result)
+	exitOnError(err)
+	exitOnError(eeslism.WriteBatchResults(fo, results))
+	exitOnError(fo.Close())
+}
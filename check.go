@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+checkMain (Input Check Command)
+
+`eeslism check` サブコマンドです。入力データファイルを計算せずに検査し（eeslism.Simulation.Check）、
+見つかった全ての誤りと警告を `ファイル名:行:列: error: データセット名: メッセージ` の形式で表示します。
+例: `eeslism check room.txt`
+
+  - 誤りがある場合は終了コード 1 で、誤りがない場合は（警告があっても）0 で終了します。
+  - `--static`: 名前の定義と参照の検査のみ行い、パーサーによる読み込み（Init）を行いません。
+  - `--no-warnings`: 警告を表示しません。
+  - `--json`: 検査結果を JSON の配列で出力します。
+  - `--encoding`: 入力データファイルの文字コード（auto、utf-8、shift_jis）です。
+  - `-D NAME=VALUE`: 入力データファイルの `$define` より優先するパラメータの値です（`--static` では用いません）。
+
+検査中のパーサーの表示（`=== ROOM` などや Eprint のメッセージ）は表示しません。
+*/
+func checkMain(args []string) {
+	parser := argparse.NewParser("eeslism check", "Check input data files without running the simulation")
+
+	inputs := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Input file (.txt, .json, .yaml)"})
+
+	efl_path := parser.String("", "efl", &argparse.Options{
+		Default: "Base",
+		Help:    "EFLファイルのディレクトリ"})
+
+	static := parser.Flag("", "static", &argparse.Options{
+		Help: "名前の定義と参照の検査のみ行う"})
+
+	noWarnings := parser.Flag("", "no-warnings", &argparse.Options{
+		Help: "警告を表示しない"})
+
+	asJSON := parser.Flag("", "json", &argparse.Options{
+		Help: "検査結果を JSON で出力する"})
+
+	encoding := parser.String("", "encoding", &argparse.Options{
+		Default: "auto",
+		Help:    "入力データファイル、EFLファイルの文字コード（auto、utf-8、shift_jis）"})
+
+	defines := parser.StringList("D", "define", &argparse.Options{
+		Help: "パラメータの値 NAME=VALUE（入力データファイルの $define より優先する）"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	defs, err := parseDefines(*defines)
+	exitOnError(err)
+	diags, err := checkInput(*inputs, eflPath(*efl_path), *encoding, defs, *static)
+	exitOnError(err)
+
+	if *noWarnings {
+		var errs []eeslism.Diagnostic
+		for _, d := range diags {
+			if d.Severity == eeslism.SeverityError {
+				errs = append(errs, d)
+			}
+		}
+		diags = errs
+	}
+
+	if *asJSON {
+		if diags == nil {
+			diags = []eeslism.Diagnostic{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		exitOnError(enc.Encode(diags))
+	} else {
+		exitOnError(eeslism.WriteDiagnostics(os.Stdout, diags))
+	}
+
+	if eeslism.HasErrors(diags) {
+		os.Exit(1)
+	}
+}
+
+// checkInput は入力データファイル name を検査します。検査中のパーサーの標準出力への表示は捨てます。
+func checkInput(name, efl_path, encoding string, defines map[string]string, static bool) ([]eeslism.Diagnostic, error) {
+	if static {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if src, err = eeslism.DecodeText(src, encoding); err != nil {
+			return nil, err
+		}
+		return eeslism.CheckInput(name, src)
+	}
+
+	devnull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer devnull.Close()
+	stdout := os.Stdout
+	os.Stdout = devnull
+	defer func() { os.Stdout = stdout }()
+
+	sim := eeslism.NewSimulation(name, efl_path)
+	sim.Defines, sim.Encoding = defines, encoding
+	return sim.Check()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+convertMain (Input Format Conversion Command)
+
+`eeslism convert` サブコマンドです。入力データファイルを JSON、YAML（eeslism.InputDocument）に、
+またはその逆に変換します。書式はファイル名の拡張子（.json、.yaml、.yml、その他は入力データファイル）で判定します。
+例: `eeslism convert room.txt room.json`、`eeslism convert room.json room.txt`
+
+  - 出力ファイル名が `-` の場合は標準出力に書き出します。書式は `--to` で指定します（省略した場合は JSON）。
+  - `--from`、`--to`: 拡張子によらず書式（text、json、yaml）を指定します。
+*/
+func convertMain(args []string) {
+	parser := argparse.NewParser("eeslism convert", "Convert an input data file to or from JSON/YAML")
+
+	input := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Input file (.txt, .json, .yaml)"})
+
+	output := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Output file (.txt, .json, .yaml) or - for standard output"})
+
+	from := parser.Selector("", "from", []string{"text", "json", "yaml"}, &argparse.Options{
+		Help: "入力の書式"})
+
+	to := parser.Selector("", "to", []string{"text", "json", "yaml"}, &argparse.Options{
+		Help: "出力の書式"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	if *from == "" {
+		*from = eeslism.InputFormat(*input)
+	}
+	if *to == "" {
+		*to = "json"
+		if *output != "-" {
+			*to = eeslism.InputFormat(*output)
+		}
+	}
+
+	// Shift_JIS の入力データファイルは UTF-8 に変換して読み込む
+	src, err := os.ReadFile(*input)
+	exitOnError(err)
+	src, err = eeslism.DecodeText(src, "auto")
+	exitOnError(err)
+	doc, err := eeslism.ReadInputDocument(bytes.NewReader(src), *from)
+	exitOnError(err)
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		out, err := os.Create(*output)
+		exitOnError(err)
+		defer out.Close()
+		w = out
+	}
+	exitOnError(doc.Write(w, *to))
+}
@@ -25,7 +25,6 @@ package eeslism
 import (
 	"fmt"
 	"math"
-	"os"
 )
 
 /*
@@ -509,6 +508,10 @@ func LP_COORDNT(
 
 	/*-----------------------------------------------------*/
 
+	// ここまでは付設障害物。以降の面の種類は n 番目から設定する
+	setPlaneKind(lp, PlaneSBLK)
+
+	n := len(lp)
 	for i := range obs {
 		if obs[i].fname == "rect" {
 			caWb = 0.0
@@ -827,12 +830,15 @@ func LP_COORDNT(
 			lp = append(lp, lp_k)
 		} else {
 			fmt.Printf("error--**COORDNT-lp\n")
-			os.Exit(1)
+			panic(&InputError{Section: "OBS", Keyword: obs[i].fname, Component: obs[i].obsname, Msg: "unknown obstacle type"})
 		}
 
 	}
 
 	/*--------------------------------------------------------*/
+	setPlaneKind(lp[n:], PlaneOBS)
+
+	n = len(lp)
 	for i := range tree {
 		if tree[i].treetype == "treeA" {
 			/*----1----*/
@@ -1643,11 +1649,14 @@ func LP_COORDNT(
 			lp = append(lp, lp_20)
 		} else {
 			fmt.Printf("error--**COORDNT-lp TREE \n")
-			os.Exit(1)
+			panic(&InputError{Section: "TREE", Keyword: tree[i].treetype, Component: tree[i].treename, Msg: "unknown tree type"})
 		}
 	}
 
+	setPlaneKind(lp[n:], PlaneTREE)
+
 	/*-------多角形の障害物の直接入力----------------------*/
+	n = len(lp)
 	for i := range poly {
 
 		if poly[i].polyknd == "OBS" {
@@ -1685,6 +1694,7 @@ func LP_COORDNT(
 			lp = append(lp, lp_k)
 		}
 	}
+	setPlaneKind(lp[n:], PlanePOLYGON)
 
 	return lp
 }
@@ -1834,6 +1844,9 @@ func OP_COORDNT(BDP []*BBDP, poly []*POLYGN) []*P_MENN {
 		}
 	}
 
+	setPlaneKind(op, PlaneRMP)
+
+	n := len(op)
 	for _, p := range poly {
 		if p.polyknd == "RMP" {
 
@@ -1865,6 +1878,7 @@ func OP_COORDNT(BDP []*BBDP, poly []*POLYGN) []*P_MENN {
 		}
 
 	}
+	setPlaneKind(op[n:], PlanePOLYGON)
 
 	return op
 }
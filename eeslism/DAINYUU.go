@@ -24,7 +24,6 @@ package eeslism
 
 import (
 	"fmt"
-	"os"
 )
 
 /*
@@ -75,6 +74,7 @@ func DAINYUU_MP(op []*P_MENN) []*P_MENN {
 			_mpw.wd = 0
 			_mpw.sbflg = 0 // 0=その他
 			_mpw.wlflg = 1 // 1=窓
+			_mpw.kind = PlaneWD
 
 			// 反射率、前面地面の反射率
 			_mpw.refg = _op.refg
@@ -160,7 +160,7 @@ func DAINYUU_GP(p *XYZ, O XYZ, E XYZ, ls float64, ms float64, ns float64) {
 		p.Z = ns * t
 	} else {
 		fmt.Println("error DAINYUU_GP")
-		os.Exit(1)
+		panic(&InputError{Section: "COORDNT", Keyword: "DAINYUU_GP", Msg: "line is parallel to the plane"})
 	}
 }
 
@@ -17,7 +17,6 @@ package eeslism
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 )
 
@@ -90,7 +89,7 @@ func HISASHI(fi *EeTokens, sb *sunblk) {
 		} else {
 			fmt.Printf("ERROR parameter----HISASI: %s\n", NAME)
 
-			os.Exit(1)
+			panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: sb.snbname, Msg: "HISASI"})
 		}
 	}
 }
@@ -171,7 +170,7 @@ func BARUKO(fi *EeTokens, sb *sunblk) {
 		} else {
 			fmt.Printf("ERROR parameter----WBARUKONI: %s\n", NAME)
 
-			os.Exit(1)
+			panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: sb.snbname, Msg: "BARUKONI"})
 		}
 	}
 }
@@ -240,7 +239,7 @@ func SODEK(fi *EeTokens, sb *sunblk) {
 		} else {
 			fmt.Printf("ERROR parameter----SODEKABE: %s\n", NAME)
 
-			os.Exit(1)
+			panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: sb.snbname, Msg: "SODEKABE"})
 		}
 	}
 }
@@ -305,7 +304,7 @@ func SCREEN(fi *EeTokens, sb *sunblk) {
 		} else {
 			fmt.Printf("ERROR paramater---MADOHIYOKE: %s\n", NAME)
 
-			os.Exit(1)
+			panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: sb.snbname, Msg: "MADOHIYOKE"})
 		}
 	}
 }
@@ -376,7 +375,7 @@ func rmpdata(fi *EeTokens) *RRMP {
 			rp.rgb[2] = fi.GetFloat()
 		} else {
 			fmt.Printf("ERROR parameter----RMP: %s\n", NAME)
-			os.Exit(1)
+			panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: rp.rmpname, Msg: "RMP"})
 		}
 	}
 
@@ -429,7 +428,7 @@ func rmpdata(fi *EeTokens) *RRMP {
 					wp.rgb[2] = line.GetFloat()
 				} else {
 					fmt.Printf("ERROR parameter----WD: %s\n", NAME)
-					os.Exit(1)
+					panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: wp.winname, Msg: "WD"})
 				}
 			}
 
@@ -556,7 +555,7 @@ func rectdata(fi *EeTokens, obs *OBS) {
 			obs.rgb[2] = fi.GetFloat()
 		} else {
 			fmt.Printf("ERROR parameter----OBS.rect: %s\n", NAME)
-			os.Exit(1)
+			panic(&InputError{Section: "OBS", Keyword: NAME, Component: obs.obsname, Msg: "rect"})
 		}
 	}
 }
@@ -646,7 +645,7 @@ func cubdata(fi *EeTokens, obs *OBS) {
 			obs.rgb[2] = fi.GetFloat()
 		} else {
 			fmt.Printf("ERROR parameter----OBS.cube: %s\n", NAME)
-			os.Exit(1)
+			panic(&InputError{Section: "OBS", Keyword: NAME, Component: obs.obsname, Msg: "cube"})
 		}
 	}
 }
@@ -724,7 +723,7 @@ func tridata(fi *EeTokens, obs *OBS) {
 			obs.rgb[2] = fi.GetFloat()
 		} else {
 			fmt.Printf("ERROR parameter----OBS.triangle: %s\n", NAME)
-			os.Exit(1)
+			panic(&InputError{Section: "OBS", Keyword: NAME, Component: obs.obsname, Msg: "triangle"})
 		}
 	}
 }
@@ -784,7 +783,7 @@ func dividdata(fi *EeTokens, monten *int, DE *float64) {
 		} else {
 			fmt.Printf("ERROR parameter----DIVID: %s\n", NAME)
 
-			os.Exit(1)
+			panic(&InputError{Section: "DIVID", Keyword: NAME})
 		}
 	}
 }
@@ -874,12 +873,12 @@ func treedata(fi *EeTokens, tree *[]*TREE) {
 					tred.W4 = line.GetFloat()
 				} else {
 					fmt.Printf("ERROR parameter----TREE: %s %s\n", tred.treename, NAME)
-					os.Exit(1)
+					panic(&InputError{Section: "TREE", Keyword: NAME, Component: tred.treename})
 				}
 			}
 		} else {
 			fmt.Printf("ERROR parameter----TREE: %s\n", tred.treetype)
-			os.Exit(1)
+			panic(&InputError{Section: "TREE", Keyword: tred.treetype, Component: tred.treename, Msg: "unknown tree type"})
 		}
 
 		*tree = append(*tree, tred)
@@ -989,7 +988,7 @@ func polydata(fi *EeTokens, poly *[]*POLYGN) {
 
 		if polyp.polyknd != "RMP" && polyp.polyknd != "OBS" {
 			fmt.Printf("ERROR parameter----POLYGON: %s  <RMP> or <OBS> \n", polyp.polyknd)
-			os.Exit(1)
+			panic(&InputError{Section: "POLYGON", Keyword: polyp.polyknd, Msg: "polygon kind must be RMP or OBS"})
 		}
 
 		// 頂点数
@@ -1032,7 +1031,7 @@ func polydata(fi *EeTokens, poly *[]*POLYGN) {
 				polyp.grpx = line.GetFloat()
 			} else {
 				fmt.Printf("ERROR parameter----POLYGON: %s\n", NAME)
-				os.Exit(1)
+				panic(&InputError{Section: "POLYGON", Keyword: NAME, Component: polyp.polyname})
 			}
 		}
 
@@ -1139,7 +1138,7 @@ func bdpdata(fi *EeTokens, bp *[]*BBDP, Exsf *EXSFS) {
 
 		if NAME != "BDP" {
 			fmt.Printf("error BDP\n")
-			os.Exit(1)
+			panic(&InputError{Section: "COORDNT", Keyword: NAME, Msg: "BDP expected"})
 		}
 
 		bbdp.bdpname = fi.GetToken()
@@ -1182,7 +1181,7 @@ func bdpdata(fi *EeTokens, bp *[]*BBDP, Exsf *EXSFS) {
 				}
 			} else {
 				fmt.Printf("ERROR parameter----BDP %s\n", NAME)
-				os.Exit(1)
+				panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: bbdp.bdpname})
 			}
 		}
 		fmt.Printf("DEBUG bdpdata: BDP %s: x0=%f y0=%f z0=%f Wa=%f Wb=%f exw=%f exh=%f\n",
@@ -1225,7 +1224,7 @@ func bdpdata(fi *EeTokens, bp *[]*BBDP, Exsf *EXSFS) {
 					SCREEN(fi, sb)
 				} else {
 					fmt.Printf("ERROR----\nhiyoke no syurui <HISASI> or <BARUKONI> or <SODEKABE> or <MADOHIYOKE> : %s \n", sb.sbfname)
-					os.Exit(1)
+					panic(&InputError{Section: "COORDNT", Keyword: sb.sbfname, Component: sb.snbname, Msg: "sunblock type must be HISASI, BARUKONI, SODEKABE or MADOHIYOKE"})
 				}
 
 				fi.SkipToEndOfLine()
@@ -1241,7 +1240,7 @@ func bdpdata(fi *EeTokens, bp *[]*BBDP, Exsf *EXSFS) {
 				bbdp.RMP = append(bbdp.RMP, rp)
 			} else {
 				fmt.Printf("ERROR----<SBLK> or <RMP> : %s \n", NAME)
-				os.Exit(1)
+				panic(&InputError{Section: "COORDNT", Keyword: NAME, Component: bbdp.bdpname, Msg: "SBLK or RMP expected"})
 			}
 		}
 
@@ -1446,7 +1445,7 @@ func obsdata(fi *EeTokens, obsn *int, obs *[]*OBS) {
 			tridata(line, obsp)
 		} else {
 			fmt.Printf("ERROR parameter----OBS : %s\n", obsp.fname)
-			os.Exit(1)
+			panic(&InputError{Section: "OBS", Keyword: obsp.fname, Component: obsp.obsname, Msg: "unknown obstacle type"})
 		}
 
 		*obs = append(*obs, obsp)
@@ -41,7 +41,7 @@ func errbekt_print(n, m int, a []bekt, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open errbektPrintf")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
@@ -84,7 +84,7 @@ func e_printf(n int, p []P_MENN, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open ePrintf")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
@@ -117,7 +117,7 @@ func mp_printf(n int, mp []*P_MENN, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open mpPrintf")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
@@ -141,7 +141,7 @@ func gp_printf(gp [][]XYZ, mp []P_MENN, mpn, lpn int, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open gpPrintf")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
@@ -165,7 +165,7 @@ func lp_printf(n int, lp []P_MENN, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open lpPrintf")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
@@ -187,7 +187,7 @@ func lp_shad_printf(lpn int, lp []P_MENN, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open lpShadPrintf")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
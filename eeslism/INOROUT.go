@@ -25,7 +25,6 @@ package eeslism
 
 import (
 	"fmt"
-	"os"
 )
 
 /*
@@ -84,7 +83,7 @@ func INOROUT(Px, Py, Pz float64, P0, P1, P2 XYZ, S, T *float64) {
 	} else {
 		fmt.Printf("error inorout\n0X=%f 0Y=%f 0Z=%f\n1X=%f 1Y=%f 1Z=%f\n2X=%f 2Y=%f 2Z=%f\n",
 			P0.X, P0.Y, P0.Z, P1.X, P1.Y, P1.Z, P2.X, P2.Y, P2.Z)
-		os.Exit(1)
+		panic(&InputError{Section: "COORDNT", Keyword: "INOROUT", Msg: "degenerate polygon"})
 	}
 
 	aa1 = Sx01
@@ -101,6 +100,6 @@ func INOROUT(Px, Py, Pz float64, P0, P1, P2 XYZ, S, T *float64) {
 		*S = (Pz - (*T)*Tz03 - P0.Z) / Sz01
 	} else {
 		fmt.Println("error inorout2")
-		os.Exit(1)
+		panic(&InputError{Section: "COORDNT", Keyword: "INOROUT", Msg: "degenerate polygon"})
 	}
 }
@@ -23,7 +23,6 @@ package eeslism
 
 import (
 	"fmt"
-	"os"
 )
 
 /*
@@ -51,39 +50,40 @@ CG（コンピュータグラフィックス）ソフトウェアで可視化す
   この関数は独自形式で出力しているため、
   汎用的なCGソフトウェアで利用するためには、
   Wavefront OBJ形式などへの変換が必要です。
+  同じ面を glTF、Wavefront OBJ で出力するには Simulation.WriteGLTF、WriteOBJ（ref: gltf.go）を用います。
 
 この関数は、建物のエネルギーシミュレーションにおいて、
 モデルの検証、日影・日射量分布の分析、
 および設計検討のための重要な可視化機能を提供します。
 */
-func HOUSING_PLACE(lpn, mpn int, lp, mp []*P_MENN, RET string) {
+func HOUSING_PLACE(lpn, mpn int, lp, mp []*P_MENN, RET string, out OutputSink) {
 
 	mlpn := lpn + mpn
 
 	// LPの位置データ用ファイル fp1
 	NAMAE1 := RET + "_placeLP.gchi"
-	fp1, err := os.Create(NAMAE1)
+	fp1, err := out.Create(NAMAE1)
 	if err != nil {
 		fmt.Println("File not open _placeLP.gchi")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp1.Close()
 
 	// OPの位置データ用ファイル fp2
 	NAMAE2 := RET + "_placeOP.gchi"
-	fp2, err := os.Create(NAMAE2)
+	fp2, err := out.Create(NAMAE2)
 	if err != nil {
 		fmt.Println("File not open _placeOP.gchi")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp2.Close()
 
 	// LPとOPの位置データ用ファイル fp3
 	NAMAE3 := RET + "_placeALL.gchi"
-	fp3, err := os.Create(NAMAE3)
+	fp3, err := out.Create(NAMAE3)
 	if err != nil {
 		fmt.Println("File not open _placeALL.gchi")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp3.Close()
 
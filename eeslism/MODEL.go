@@ -137,6 +137,7 @@ type P_MENN struct {
 	grp                 XYZ   //前面地面代表点
 	sbflg               int   //付設障害物フラグ　付設障害物の場合：１、その他：０
 	wlflg               int   //外表面の種類 窓：1 壁：0
+	kind                string //面の種類 RMP、WD、SBLK、OBS、TREE、POLYGON (ref: gltf.go)
 }
 
 //LP(ポリゴン)直接入力用
@@ -78,7 +78,7 @@ func MONTE_CARLO(
 	GP [][]XYZ,
 	gpn int,
 	nday int,
-	startday int,
+	startday int, rnd *glibcRand,
 ) {
 
 	var j, h, l, n, i, k, mlpn int
@@ -180,7 +180,7 @@ func MONTE_CARLO(
 			var ls, ms, ns, s float64
 
 			/*----------乱数の発生--------------*/
-			RAND(&a, &va)
+			RAND(&a, &va, rnd)
 
 			// ls: 3D空間におけるX軸方向の成分（東西方向）
 			// ms: 3D空間におけるY軸方向の成分（南北方向）
@@ -259,7 +259,7 @@ GR_MONTE_CARLO (Ground-Referenced Monte Carlo Simulation for Form Factors)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func GR_MONTE_CARLO(mp []*P_MENN, mpn int, lp []*P_MENN, lpn int, monten int, day int) {
+func GR_MONTE_CARLO(mp []*P_MENN, mpn int, lp []*P_MENN, lpn int, monten int, day int, rnd *glibcRand) {
 	var rp int
 	var i, n, l, mlpn, k, h int
 	var ls, ms, ns float64
@@ -294,7 +294,7 @@ func GR_MONTE_CARLO(mp []*P_MENN, mpn int, lp []*P_MENN, lpn int, monten int, da
 			// ランダムな太陽位置
 			// a: 太陽方位角
 			// va: 太陽高度
-			RAND(&a, &va)
+			RAND(&a, &va, rnd)
 
 			// ls: 3D空間におけるX軸方向の成分（東西方向）
 			// ms: 3D空間におけるY軸方向の成分（南北方向）
@@ -376,7 +376,7 @@ FFACTOR_LP (Form Factor Calculation for Light-Receiving Planes)
 日射熱取得の抑制、冷房負荷の軽減、
 昼光利用の最適化、および日影計算を行うための重要な役割を果たします。
 */
-func FFACTOR_LP(NUM int, LP []*P_MENN, MP []*P_MENN) {
+func FFACTOR_LP(NUM int, LP []*P_MENN, MP []*P_MENN, rnd *glibcRand) {
 
 	var l, i int
 	var a, va, x, y, z, Px, Py, Pz, U float64
@@ -441,7 +441,7 @@ func FFACTOR_LP(NUM int, LP []*P_MENN, MP []*P_MENN) {
 		for n := 0; n < NUM; n++ {
 
 			/*----------乱数の発生--------------*/
-			RAND(&a, &va)
+			RAND(&a, &va, rnd)
 
 			// ls: 3D空間におけるX軸方向の成分（東西方向）
 			// ms: 3D空間におけるY軸方向の成分（南北方向）
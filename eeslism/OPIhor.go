@@ -71,7 +71,7 @@ func OPIhor(
 	ulmp []*bekt,
 	gp [][]XYZ,
 	nday int,
-	monten int,
+	monten int, dayprn bool,
 ) {
 	var ls,
 		ms,
@@ -107,12 +107,8 @@ func TestOPIhor(t *testing.T) {
 			var bufFp bytes.Buffer
 			var bufFp1 bytes.Buffer
 
-			// dayprnの値を設定 (グローバル変数または引数として渡す必要がある)
-			// 現状、dayprnはグローバル変数として扱われているため、テスト前に設定
-			dayprn = tt.dayprn
-
 			// OPIhor関数を呼び出し
-			OPIhor(&bufFp, &bufFp1, len(lp), len(mp), mp, lp, tt.wd, ullp, ulmp, gp, 1, tt.monten)
+			OPIhor(&bufFp, &bufFp1, len(lp), len(mp), mp, lp, tt.wd, ullp, ulmp, gp, 1, tt.monten, tt.dayprn)
 
 			// 結果の検証
 			// ここでは、出力がエラーなく行われたことと、主要な計算結果が期待通りかを確認します。
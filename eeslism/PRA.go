@@ -26,7 +26,6 @@ package eeslism
 import (
 	"fmt"
 
-	"os"
 )
 
 /*
@@ -70,6 +69,6 @@ func PRA(U *float64, ls, ms, ns, x, y, z float64) {
 	} else {
 		fmt.Printf("ls=%f ms=%f ns=%f\n", ls, ms, ns)
 		fmt.Println("errorPRA")
-		os.Exit(1)
+		panic(&InputError{Section: "COORDNT", Keyword: "PRA", Msg: "zero direction vector"})
 	}
 }
@@ -42,9 +42,6 @@ type glibcRand struct {
 // RAND_MAX はC言語のRAND_MAXに相当する値（2^31-1）
 const RAND_MAX = 2147483647
 
-// グローバル乱数生成器（C版と同じデフォルトシード1で初期化）
-var cRand = newGlibcRand()
-
 // newGlibcRand creates a new glibc-compatible RNG with seed 1 (default)
 func newGlibcRand() *glibcRand {
 	g := &glibcRand{}
@@ -131,11 +128,11 @@ aは方位角、vは仰角です。
 日射熱取得の抑制、冷房負荷の軽減、
 昼光利用の最適化、および日影計算を行うための重要な役割を果たします。
 */
-func RAND(a, v *float64) {
+func RAND(a, v *float64, rnd *glibcRand) {
 	// a is azimuth, v is elevation
 	// glibc互換乱数生成器を使用（C版と同じ乱数シーケンス）
-	*a = 2.0 * math.Pi * cRand.Float64()
-	*v = mathAcos(mathSqrt(1.0 - cRand.Float64()))
+	*a = 2.0 * math.Pi * rnd.Float64()
+	*v = mathAcos(mathSqrt(1.0 - rnd.Float64()))
 
 	//TEST
 	// *a = math.Pi
@@ -8,9 +8,10 @@ import (
 
 func TestRAND(t *testing.T) {
 	const numTests = 1000
+	rnd := newGlibcRand()
 	for i := 0; i < numTests; i++ {
 		var a, v float64
-		RAND(&a, &v)
+		RAND(&a, &v, rnd)
 
 		if a < 0 || a > 2*math.Pi {
 			t.Errorf("Test %d: Azimuth 'a' out of range [0, 2*Pi], got %f", i, a)
@@ -33,7 +33,7 @@ func ZPRINT(lp []P_MENN, op []P_MENN, lpn, opn int, name string) {
 	fp, err := os.Create(name)
 	if err != nil {
 		fmt.Println("File not open")
-		os.Exit(1)
+		panic(err)
 	}
 	defer fp.Close()
 
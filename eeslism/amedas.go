@@ -0,0 +1,307 @@
+/*
+amedas.go (Expanded AMeDAS Weather Data Reader)
+
+拡張アメダス気象データ（標準年）を、EESLISM で定めた次のテキスト形式としたファイルから読み込みます。
+GDAT FILE の w= にこのファイル、station= に地点番号を指定すると、気象データファイル種別 'A' となります。
+
+配布元のバイナリ形式、テキスト形式のレコードは直接は読み込みません（レコードの形式を確認できる資料と
+データがないため）。地点ごとの値をこのテキスト形式に変換してから用います。
+
+	GDAT
+	    FILE w=EA_kanto.txt station=44132 ;
+
+ファイルは複数の地点を含むことができ、各地点は地点行と日別・要素別のデータ行からなります。
+各行の項目は空白またはカンマで区切ります。
+
+	地点行:   地点番号 地点名 緯度(度) 緯度(分) 経度(度) 経度(分) 標高[m]
+	データ行: 地点番号 年 月 日 要素番号 1時の値 ... 24時の値
+
+要素番号と値の単位は次のとおりです。各時刻の値は前1時間の値（時間終端値）です。
+
+  - 1: 気温 [0.1℃]
+  - 2: 絶対湿度 [0.1g/kg]
+  - 3: 水平面全天日射量 [0.01MJ/m2h]
+  - 4: 水平面大気放射量 [0.01MJ/m2h]
+  - 5: 風向（16方位、1:NNE ～ 16:N、0:静穏）
+  - 6: 風速 [0.1m/s]
+  - 7: 降水量 [0.1mm/h]（読み飛ばします）
+  - 8: 日照時間 [0.1h]（読み飛ばします）
+
+読み込みの方法は次のとおりです。
+
+  - 地点行から地点名、緯度、経度、標高を LOCAT に設定します。標準子午線は 135°とします。
+  - 水平面全天日射量は、前1時間の中央の太陽高度を用いて Erbs のモデルで法線面直達日射量と水平面天空日射量に分離します。
+  - 夜間放射量は水平面大気放射量から求めます（GDAT FILE -skyrd と同じ）。
+  - 気象データは HASP 形式と同じ日単位の配列（ref: hspwdread）に変換し、dt2wdata、wdatadiv で WDAT とします。
+  - 2/29 のデータ行は読み飛ばします。給水温度と地中温度の係数は気温から求めます（ref: wdGround）。
+  - この形式でないファイル（配布元のバイナリ形式など）はエラーとします。
+*/
+package eeslism
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 拡張アメダスの気象データ
+type AMEDAS struct {
+	Station int     // 地点番号
+	Name    string  // 地点名
+	Lat     float64 // 緯度[deg]
+	Lon     float64 // 経度[deg]
+	Elev    float64 // 標高[m]
+
+	Mon, Day [365]int                    // 各日の月日
+	Data     [365][amdNfield][25]float64 // 日毎の時刻別の値（添字 1～24）
+}
+
+// 拡張アメダスから求める値
+const (
+	amdT    = iota // 気温 [C]
+	amdX           // 絶対湿度 [kg/kg]
+	amdIg          // 水平面全天日射量 [W/m2]
+	amdIR          // 水平面大気放射量 [W/m2]
+	amdWdre        // 風向 (16方位)
+	amdWv          // 風速 [m/s]
+	amdIdn         // 法線面直達日射量 [W/m2]
+	amdIsky        // 水平面天空日射量 [W/m2]
+	amdNfield
+)
+
+// amdElements は要素番号 1～6 の値と、読み取った値の換算係数です。
+var amdElements = [...]struct {
+	k     int
+	scale float64
+}{
+	{amdT, 0.1},
+	{amdX, 0.0001},
+	{amdIg, 0.01e6 / 3600.0},
+	{amdIR, 0.01e6 / 3600.0},
+	{amdWdre, 1.0},
+	{amdWv, 0.1},
+}
+
+// amdLs は拡張アメダスの標準子午線 [deg] です。
+const amdLs = 135.0
+
+// ReadAMEDAS は拡張アメダスのファイルから地点番号 station の気象データを読み込みます。
+func ReadAMEDAS(r io.Reader, station int) (*AMEDAS, error) {
+	a := &AMEDAS{Station: station}
+	var seen [365][len(amdElements)]bool
+	found := false
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if strings.ContainsRune(sc.Text(), 0) {
+			return nil, fmt.Errorf("line %d: binary data (convert the records to the text layout of amedas.go)", line)
+		}
+		f := strings.FieldsFunc(sc.Text(), func(r rune) bool { return r == ' ' || r == '\t' || r == ',' || r == '\r' })
+		if len(f) == 0 {
+			continue
+		}
+		st, err := strconv.Atoi(f[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %q is not a station number", line, f[0])
+		}
+		if st != station {
+			continue
+		}
+
+		switch len(f) {
+		case 7:
+			v, err := epwFloats(f[2:7])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			a.Name = f[1]
+			a.Lat = v[0] + v[1]/60.0
+			a.Lon = v[2] + v[3]/60.0
+			a.Elev = v[4]
+			found = true
+		case 29:
+			v, err := epwFloats(f[1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			mon, day, elem := int(v[1]), int(v[2]), int(v[3])
+			if mon == 2 && day == 29 {
+				continue
+			}
+			if mon < 1 || mon > 12 || day < 1 || day > 31 {
+				return nil, fmt.Errorf("line %d: invalid date %d/%d", line, mon, day)
+			}
+			if elem < 1 || elem > 8 {
+				return nil, fmt.Errorf("line %d: invalid element number %d", line, elem)
+			}
+			if elem > len(amdElements) {
+				// 降水量、日照時間
+				continue
+			}
+			d := FNNday(mon, day) - 1
+			a.Mon[d], a.Day[d] = mon, day
+			seen[d][elem-1] = true
+			e := amdElements[elem-1]
+			for t := 1; t <= 24; t++ {
+				a.Data[d][e.k][t] = v[3+t] * e.scale
+			}
+		default:
+			return nil, fmt.Errorf("line %d: %d fields (7 for a station, 29 for data)", line, len(f))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("station %d is not found", station)
+	}
+	for d := range seen {
+		for e := range seen[d] {
+			if !seen[d][e] {
+				mon, day := 1, 1
+				for i := 0; i < d; i++ {
+					mon, day = monthday(mon, day)
+				}
+				return nil, fmt.Errorf("station %d: no data for %d/%d element %d", station, mon, day, e+1)
+			}
+		}
+	}
+	a.split()
+	return a, nil
+}
+
+// split は水平面全天日射量を法線面直達日射量と水平面天空日射量に分離します。
+// 天空日射量の比率は晴天指数から Erbs のモデルで求め、太陽高度は前1時間の中央の時刻の値とします。
+func (a *AMEDAS) split() {
+	Loc := LOCAT{Lat: a.Lat, Lon: a.Lon, Ls: amdLs}
+	Loc.Sunint()
+	Isc := 1370.0 // [W/m2]
+	for d := range a.Data {
+		nday := d + 1
+		Io := Isc * (1.0 + 0.033*math.Cos(2.0*math.Pi*float64(nday)/365.0))
+		v := &a.Data[d]
+		for t := 1; t <= 24; t++ {
+			Ig := v[amdIg][t]
+			Sh := amdSh(&Loc, nday, float64(t)-0.5)
+			if Sh < 0.05 || Ig <= 0.0 {
+				v[amdIdn][t], v[amdIsky][t] = 0.0, math.Max(0.0, Ig)
+				continue
+			}
+			Kt := math.Min(Ig/(Io*Sh), 1.0)
+			var Kd float64
+			switch {
+			case Kt <= 0.22:
+				Kd = 1.0 - 0.09*Kt
+			case Kt <= 0.80:
+				Kd = 0.9511 - 0.1604*Kt + 4.388*Kt*Kt - 16.638*Kt*Kt*Kt + 12.336*Kt*Kt*Kt*Kt
+			default:
+				Kd = 0.165
+			}
+			v[amdIsky][t] = Ig * Kd
+			v[amdIdn][t] = Ig * (1.0 - Kd) / Sh
+		}
+	}
+}
+
+// amdSh は通日 nday の時刻 tt [h] の太陽高度の正弦を返します。
+func amdSh(Loc *LOCAT, nday int, tt float64) float64 {
+	// Solpos は時刻が前回より前の場合に赤緯を更新する
+	Loc.__Solpos_Ttprev = math.Inf(1)
+	Sh, _, _, _, _ := Loc.Solpos(FNTtas(tt, FNE(nday), Loc.Lon, Loc.Ls), FNDecl(nday))
+	return Sh
+}
+
+// Location は地点名、緯度、経度、標準子午線、標高を Loc に設定します。
+func (a *AMEDAS) Location(Loc *LOCAT) {
+	Loc.Name = a.Name
+	Loc.Lat = a.Lat
+	Loc.Lon = a.Lon
+	Loc.Ls = amdLs
+	Loc.Elev = a.Elev
+}
+
+// ground は月平均給水温度と地中温度の係数を日平均気温から求めます（ref: wdGround）。
+func (a *AMEDAS) ground(Loc *LOCAT) {
+	nday := make([]int, len(a.Data))
+	Td := make([]float64, len(a.Data))
+	for d := range a.Data {
+		nday[d] = d + 1
+		for t := 1; t <= 24; t++ {
+			Td[d] += a.Data[d][amdT][t] / 24.0
+		}
+	}
+	wdGround(Loc, nday, Td)
+}
+
+// hour は通日 nday の時刻 tt の値を HASP 形式の気象データ（ref: hspwdread）の7要素で返します。
+func (a *AMEDAS) hour(nday, tt int) [7]float64 {
+	if tt == 0 {
+		nday, tt = nday-1, 24
+	}
+	if nday > 365 {
+		nday -= 365
+	}
+	if nday <= 0 {
+		nday += 365
+	}
+	v := &a.Data[nday-1]
+
+	T := v[amdT][tt]
+	RN := Sgm*mathPow(T+273.15, 4.0) - v[amdIR][tt]
+
+	// 日射量と夜間放射量は HASP 形式の単位 [kcal/m2h] とする
+	return [7]float64{T, v[amdX][tt], v[amdIdn][tt] * 0.86, v[amdIsky][tt] * 0.86, RN * 0.86, v[amdWdre][tt], v[amdWv][tt]}
+}
+
+/*
+amdwdread (Expanded AMeDAS Weather Data Reader)
+
+この関数は、拡張アメダスの気象データから通日 `nday` の1日分の気象データを読み込み、
+HASP 形式（`hspwdread`）と同じ配列 `dt` に格納します。
+最初の呼び出しで `Simc.Fwdata` の全体から地点番号 `Simc.Station` の地点を読み込み、地点情報を `Loc` に設定します。
+
+建築環境工学的な観点:
+  - **国内の標準年気象データ**: 拡張アメダス気象データは全国約 840 地点の標準年気象データであり、
+    HASP 形式への変換による丸めなしに、建設地に近い地点の気象データを用いることができます。
+  - **日射の直散分離**: 拡張アメダスは水平面全天日射量のみを含むため、
+    直達日射量と天空日射量に分離して傾斜面日射量の計算に用います。
+*/
+func (sim *Simulation) amdwdread(Simc *SIMCONTL, nday int, Loc *LOCAT, dt *[7][25]float64) (year int, mon int, day int, wkdy int) {
+	if sim.__amdwdread_amd == nil {
+		Simc.Fwdata.Seek(0, io.SeekStart)
+		a, err := ReadAMEDAS(Simc.Fwdata, Simc.Station)
+		if err != nil {
+			Eprint("<amdwdread>", err.Error(), sim.Ferr)
+			panic(&WeatherError{Section: "GDAT", Keyword: "FILE", Component: Simc.Wfname, Msg: err.Error(), Code: EXIT_WFILE})
+		}
+		a.Location(Loc)
+		if sim.Ferr != nil {
+			fmt.Fprintf(sim.Ferr, "\n------> <amdwdread> \n")
+			fmt.Fprintf(sim.Ferr, "\nStation=%d\tName=%s\tLat=%.4g\tLon=%.4g\tLs=%.4g\tElev=%.4g\n",
+				a.Station, Loc.Name, Loc.Lat, Loc.Lon, Loc.Ls, Loc.Elev)
+		}
+		sim.__amdwdread_amd = a
+	}
+	a := sim.__amdwdread_amd
+
+	if nday > 365 {
+		nday = nday - 365
+	}
+	if nday <= 0 {
+		nday = nday + 365
+	}
+
+	for t := 0; t < 25; t++ {
+		v := a.hour(nday, t)
+		for k := 0; k < 7; k++ {
+			dt[k][t] = v[k]
+		}
+	}
+
+	return 0, a.Mon[nday-1], a.Day[nday-1], 0
+}
@@ -0,0 +1,219 @@
+package eeslism
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// amdTestStation は拡張アメダスの1地点分の amedas.go のテキスト形式を返す。value は要素番号 elem の値
+func amdTestStation(station int, header string, leap bool, value func(nday, elem, hour int) float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s\n", station, header)
+	day := func(nday, mon, d int) {
+		for elem := 1; elem <= 8; elem++ {
+			fmt.Fprintf(&b, "%d 1995 %d %d %d", station, mon, d, elem)
+			for h := 1; h <= 24; h++ {
+				fmt.Fprintf(&b, " %g", value(nday, elem, h))
+			}
+			b.WriteString("\n")
+		}
+	}
+	mon, d := 1, 1
+	for nday := 1; nday <= 365; nday++ {
+		day(nday, mon, d)
+		if leap && mon == 2 && d == 28 {
+			day(0, 2, 29)
+		}
+		mon, d = monthday(mon, d)
+	}
+	return b.String()
+}
+
+// TestReadAMEDAS は地点の選択、地点情報、単位の換算、直散分離、2/29 の読み飛ばしと誤りを確認する
+func TestReadAMEDAS(t *testing.T) {
+	value := func(nday, elem, hour int) float64 {
+		switch elem {
+		case 1:
+			return float64(nday*10 + hour)
+		case 2:
+			return 55
+		case 3:
+			if hour >= 7 && hour <= 17 {
+				return 200
+			}
+			return 0
+		case 4:
+			return 108
+		case 5:
+			return 4
+		case 6:
+			return 23
+		}
+		return 0
+	}
+	file := amdTestStation(44131, "Other 35 0 139 0 10", false, func(int, int, int) float64 { return 1 }) +
+		amdTestStation(44132, "Tokyo 35 41.4 139 45.6 6.1", true, value)
+
+	if _, err := ReadAMEDAS(strings.NewReader(file), 44133); err == nil {
+		t.Error("unknown station: expected error")
+	}
+	a, err := ReadAMEDAS(strings.NewReader(file), 44132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var Loc LOCAT
+	a.Location(&Loc)
+	if Loc.Name != "Tokyo" || math.Abs(Loc.Lat-35.69) > 1e-9 || math.Abs(Loc.Lon-139.76) > 1e-9 || Loc.Ls != 135 || Loc.Elev != 6.1 {
+		t.Errorf("LOCAT = %+v", Loc)
+	}
+	if crlf, err := ReadAMEDAS(strings.NewReader(strings.ReplaceAll(file, "\n", "\r\n")), 44132); err != nil || crlf.Data != a.Data {
+		t.Errorf("CRLF: %v", err)
+	}
+	if a.Mon[59] != 3 || a.Day[59] != 1 || a.Data[59][amdT][1] != 60.1 {
+		t.Errorf("day 60 = %d/%d T=%g, want 3/1 T=60.1 (2/29 skipped)", a.Mon[59], a.Day[59], a.Data[59][amdT][1])
+	}
+
+	v := a.hour(1, 0)
+	if math.Abs(v[0]-3674.0*0.1) > 1e-9 {
+		t.Errorf("1/1 0:00 T = %g, want 367.4 (12/31 24:00)", v[0])
+	}
+	v = a.hour(2, 1)
+	T := 2.1
+	want := [7]float64{T, 0.0055, 0, 0, (Sgm*math.Pow(T+273.15, 4) - 300) * 0.86, 4, 2.3}
+	for k := range want {
+		if math.Abs(v[k]-want[k]) > 1e-9 {
+			t.Errorf("1/2 1:00 [%d] = %g, want %g", k, v[k], want[k])
+		}
+	}
+
+	// 直散分離した日射量は前1時間の中央の太陽高度で水平面全天日射量となる
+	Ig := 200 * 0.01e6 / 3600
+	Loc.Sunint()
+	for _, h := range []int{8, 12, 16} {
+		Sh := amdSh(&Loc, 172, float64(h)-0.5)
+		d := a.Data[171]
+		if got := d[amdIdn][h]*Sh + d[amdIsky][h]; math.Abs(got-Ig) > 1e-6 || d[amdIdn][h] <= 0 || d[amdIsky][h] <= 0 {
+			t.Errorf("6/21 %d:00 Idn=%g Isky=%g: Ihor = %g, want %g", h, d[amdIdn][h], d[amdIsky][h], got, Ig)
+		}
+	}
+
+	errs := map[string]string{
+		"missing day":     strings.Replace(file, "44132 1995 7 4 ", "44132 1995 7 5 ", -1),
+		"missing element": strings.Replace(file, "44132 1995 7 4 6 ", "44132 1995 7 4 7 ", 1),
+		"no station line": strings.Replace(file, "44132 Tokyo 35 41.4 139 45.6 6.1\n", "", 1),
+		"binary":          "\x00\x01\xac\xad\x00\x00\n" + file,
+		"fields":          strings.Replace(file, "44132 Tokyo 35 41.4 139 45.6 6.1", "44132 Tokyo 35 41.4 139 45.6", 1),
+	}
+	for name, s := range errs {
+		if _, err := ReadAMEDAS(strings.NewReader(s), 44132); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// TestSimulation_AMEDAS は HASP 形式から変換した拡張アメダスによる計算の気象データを確認する。
+// 気温、絶対湿度、風向、風速は HASP 形式の場合と一致する
+func TestSimulation_AMEDAS(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasp, err := os.ReadFile(filepath.Join(eflPath, "tokyo_3column_SI.has"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var days [366][7][25]float64
+	sim := NewSimulation("", "")
+	var Loc LOCAT
+	fp := bytes.NewReader(hasp)
+	for nday := 1; nday <= 365; nday++ {
+		sim.hspwdread(fp, nday, &Loc, &days[nday])
+	}
+	Loc.Sunint()
+	ea := amdTestStation(44132, "Tokyo 35 39.17 139 50.37 6.0", false, func(nday, elem, hour int) float64 {
+		Wd := WDAT{RNtype: 'C'}
+		dt2wdata(&Wd, hour, days[nday])
+		switch elem {
+		case 1:
+			return math.Round(Wd.T * 10)
+		case 2:
+			return math.Round(Wd.X * 1e4)
+		case 3:
+			Sh := amdSh(&Loc, nday, float64(hour)-0.5)
+			return math.Round(math.Max(0, Wd.Idn*Sh+Wd.Isky) * 3600 / 0.01e6)
+		case 4:
+			return math.Round(Wd.Rsky * 3600 / 0.01e6)
+		case 5:
+			return Wd.Wdre
+		case 6:
+			return math.Round(Wd.Wv * 10)
+		}
+		return 0
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ea.txt"), []byte(ea), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "input.txt")
+	b = []byte(strings.Replace(string(b), "w=tokyo_3column_SI.has", "w="+filepath.Join(dir, "ea.txt")+" station=44132", 1))
+	if err := os.WriteFile(input, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+	sim = NewSimulation(input, eflPath)
+	for _, s := range []*Simulation{ref, sim} {
+		if err := s.Init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sim.Simc.Wdtype != 'A' || sim.Simc.Station != 44132 || sim.Wd.RNtype != 'R' {
+		t.Fatalf("Wdtype = %c, Station = %d, RNtype = %c, want A, 44132, R", sim.Simc.Wdtype, sim.Simc.Station, sim.Wd.RNtype)
+	}
+
+	var Ihor, IhorRef float64
+	for !ref.Done() {
+		if err := ref.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+		r, w := ref.Wd, sim.Wd
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"T", w.T, r.T}, {"X", w.X, r.X}, {"Wv", w.Wv, r.Wv}, {"Wdre", w.Wdre, r.Wdre},
+			{"Rsky", w.Rsky, r.Rsky},
+		} {
+			if math.Abs(v.got-v.want) > 1e-6*math.Max(1, math.Abs(v.want)) && !(v.name == "Rsky" && math.Abs(v.got-v.want) < 2) {
+				t.Fatalf("%d/%d %d: %s = %g, want %g", sim.Daytm.Mon, sim.Daytm.Day, sim.Daytm.Tt, v.name, v.got, v.want)
+			}
+		}
+		Ihor += w.Idn*w.Sh + w.Isky
+		IhorRef += r.Idn*r.Sh + r.Isky
+	}
+	// 直散分離した日射量による期間の水平面全天日射量は元の値とほぼ等しい
+	if math.Abs(Ihor-IhorRef) > 0.05*IhorRef {
+		t.Errorf("Ihor total = %g, want %g", Ihor, IhorRef)
+	}
+	if sim.Loc.Name != "Tokyo" || sim.Loc.Elev != 6.0 || sim.Loc.Tgrav == 0 {
+		t.Errorf("LOCAT = %+v", *sim.Loc)
+	}
+	if err := sim.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+}
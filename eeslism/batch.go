@@ -0,0 +1,520 @@
+/*
+batch.go (Parametric Batch Run)
+
+1つの入力データファイルの値を置き換えた複数のケース（Variant）を並行して計算し、
+計算結果の集計値を1つの表にまとめるバッチ計算を定義します。
+
+値の置き換え（Override）は `<データセット名>.<要素名>.<キー>` で指定します。
+
+  - WALL.ExtWall.FPS=100: 壁体 ExtWall の材料 FPS の層の厚さを 100mm にする
+  - WINDOW.SouthWindow.t=0.5: 窓 SouthWindow の日射透過率を 0.5 にする
+  - ROOM.TestRoom.SouthWindow=DP6: 室 TestRoom の窓 SouthWindow を DP6 にする
+  - VENT.TestRoom.Vent=0.2: 室 TestRoom の換気量基準値を 0.2 にする（スケジュール名はそのまま）
+  - EQPCAT.testboi.Qo=8000: 機器カタログ testboi の定格能力を 8000W にする
+
+要素は、データセット内の論理行（ROOM では室の定義全体）のうち、
+先頭のトークンが要素名のもの（WALL では `-E:ExtWall` の `:` 以降、EQPCAT では2番目のトークン）です。
+要素内の `<キー>=` で始まるトークン、`<キー>-<厚さ>` の層、`<キー>` と一致するトークンを全て置き換えます。
+いずれもない場合は、要素名の直後に `<キー>=<値>` を追加します。
+
+変数表の `$<名前>` の列は、入力データファイルの `$define <名前>` より優先するパラメータの値（Variant.Defines）です。
+各ケースの入力データファイルは、`#include`、`$define` の前処理（eepreproc.go）の後に値を置き換えて書き出します。
+*/
+package eeslism
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// Variant はバッチ計算の1つのケースです。
+type Variant struct {
+	Name      string
+	Overrides []Override
+	Defines   map[string]string // `$define` より優先するパラメータ
+}
+
+// Override は入力データファイルの値の置き換えです。
+type Override struct {
+	Section string // データセット名（WALL、WINDOW、ROOM、VENT、EQPCAT など）
+	Name    string // 要素名
+	Key     string // キーワード、材料コード、または置き換えるトークン
+	Value   string
+}
+
+// ParseOverride は `<データセット名>.<要素名>.<キー>` 形式の key と値 value から Override を作成します。
+func ParseOverride(key, value string) (Override, error) {
+	s := strings.SplitN(key, ".", 3)
+	if len(s) != 3 || s[0] == "" || s[1] == "" || s[2] == "" {
+		return Override{}, fmt.Errorf("eeslism: invalid override %q (want SECTION.Name.Key)", key)
+	}
+	if value == "" || strings.ContainsAny(value, " \t\r\n;!") {
+		return Override{}, fmt.Errorf("eeslism: invalid value %q for %s", value, key)
+	}
+	return Override{Section: s[0], Name: s[1], Key: s[2], Value: value}, nil
+}
+
+// String は `<データセット名>.<要素名>.<キー>=<値>` 形式の文字列を返します。
+func (o Override) String() string {
+	return fmt.Sprintf("%s.%s.%s=%s", o.Section, o.Name, o.Key, o.Value)
+}
+
+// inputSections は入力データファイルのデータセット名です。
+var inputSections = []string{"TITLE", "GDAT", "SCHTB", "SCHNM", "EXSRF", "SUNBRK", "PCM", "WALL", "WINDOW",
+	"ROOM", "RAICH", "VENT", "RESI", "APPL", "VCFILE", "EQPCAT", "SYSCMP", "SYSPTH", "CONTL",
+	"DIVID", "COORDNT", "OBS", "TREE", "POLYGON", "SHDSCHTB"}
+
+// inputElement は入力データファイルの要素（論理行、または室の定義）のトークンの範囲です。
+type inputElement struct {
+	section    string
+	start, end int // tokens[start:end]。end は `;` または室の終わりの `*`
+	name       int // 要素名のトークンの位置
+}
+
+// nextToken は tokens[i] 以降の改行でない最初のトークンの位置を返します。
+func nextToken(tokens []string, i int) int {
+	for i < len(tokens) && tokens[i] == "\n" {
+		i++
+	}
+	return i
+}
+
+// inputElements は入力データファイルのトークン列から要素を取り出します。
+func inputElements(tokens []string) []inputElement {
+	var elems []inputElement
+	section := ""
+	room := -1 // ROOM で定義中の室の先頭の位置
+	for i := nextToken(tokens, 0); i < len(tokens); i = nextToken(tokens, i) {
+		tok := tokens[i]
+		switch {
+		case section == "":
+			if tok == "%s" || tok == "%sn" {
+				// スケジュールの定義は読み飛ばす
+				for i < len(tokens) && tokens[i] != ";" {
+					i++
+				}
+				i++
+			} else {
+				if slices.Contains(inputSections, tok) {
+					section = tok
+				}
+				i++
+			}
+		case tok == "*":
+			if section == "ROOM" && room >= 0 {
+				elems = append(elems, inputElement{section: section, start: room, end: i, name: room})
+				room = -1
+			} else {
+				section = ""
+			}
+			i++
+		default:
+			start := i
+			for i < len(tokens) && tokens[i] != ";" {
+				i++
+			}
+			switch {
+			case tok == "%s" || tok == "%sn":
+				// データセット内のスケジュールの定義
+			case section == "TITLE":
+				section = ""
+			case section == "ROOM":
+				if room < 0 {
+					room = start
+				}
+			case section == "EQPCAT":
+				elems = append(elems, inputElement{section: section, start: start, end: i, name: nextToken(tokens, start+1)})
+			default:
+				elems = append(elems, inputElement{section: section, start: start, end: i, name: start})
+			}
+			i++
+		}
+	}
+	return elems
+}
+
+// matchName は要素名のトークン tok が name と一致するかどうかを返します。
+// `-E:ExtWall` のような部位コード付きの壁体名は `:` 以降と比較します。
+func matchName(tok, name string) bool {
+	if tok == name {
+		return true
+	}
+	return strings.HasPrefix(tok, "-") && strings.HasSuffix(tok, ":"+name) && len(tok) == len(name)+3
+}
+
+// layerPattern は壁体の層 `<材料コード>-<厚さ>[/<分割数>]` です。
+var layerPattern = regexp.MustCompile(`^(.+)-([0-9.]+)(/[0-9]+)?$`)
+
+// apply は要素 e のトークンに置き換え o を適用し、新しいトークン列を返します。
+func (e inputElement) apply(tokens []string, o Override) []string {
+	found := false
+	for i := e.start; i < e.end; i++ {
+		tok := tokens[i]
+		if i == e.name || tok == "\n" {
+			continue
+		}
+		if strings.HasPrefix(tok, o.Key+"=") {
+			// `Vent=(0.12,Sched)` の括弧内は最初の値のみを置き換える
+			old := tok[len(o.Key)+1:]
+			value := o.Value
+			if strings.HasPrefix(old, "(") && !strings.HasPrefix(value, "(") {
+				if j := strings.IndexAny(old, ",)"); j != -1 {
+					value = "(" + value + old[j:]
+				}
+			}
+			tokens[i] = o.Key + "=" + value
+			found = true
+		} else if m := layerPattern.FindStringSubmatch(tok); m != nil && m[1] == o.Key {
+			tokens[i] = o.Key + "-" + o.Value + m[3]
+			found = true
+		} else if tok == o.Key {
+			tokens[i] = o.Value
+			found = true
+		}
+	}
+	if found {
+		return tokens
+	}
+
+	// 要素名の直後に追加する
+	tokens = append(tokens, "")
+	copy(tokens[e.name+2:], tokens[e.name+1:])
+	tokens[e.name+1] = o.Key + "=" + o.Value
+	return tokens
+}
+
+// ApplyOverrides は入力データ input に置き換え overrides を適用した入力データを返します。
+// 注釈文は除去されます。要素が見つからない置き換えがある場合はエラーを返します。
+func ApplyOverrides(input string, overrides []Override) (string, error) {
+	tokens := NewEeTokens(input).tokens
+	for _, o := range overrides {
+		n := 0
+		for {
+			// 置き換えでトークンの位置が変わるため、要素は毎回取り出し直す
+			elems := make([]inputElement, 0)
+			for _, e := range inputElements(tokens) {
+				if e.section == o.Section && e.name < len(tokens) && matchName(tokens[e.name], o.Name) {
+					elems = append(elems, e)
+				}
+			}
+			if n >= len(elems) {
+				break
+			}
+			tokens = elems[n].apply(tokens, o)
+			n++
+		}
+		if n == 0 {
+			return "", &InputError{Section: o.Section, Keyword: o.Key, Component: o.Name, Msg: "override target not found"}
+		}
+	}
+
+	var sb strings.Builder
+	for _, tok := range tokens {
+		if tok == "\n" {
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(tok)
+			sb.WriteString(" ")
+		}
+	}
+	return sb.String(), nil
+}
+
+// ReadVariants は CSV または JSON の表からケースを読み込みます。
+//
+// CSV では1行目が見出しで、`name` 列がケース名、その他の列が置き換えのキーです。
+// JSON ではケースごとに `{"name": "case1", "WALL.ExtWall.FPS": 100}` のオブジェクトを並べた配列です。
+// ケース名がない場合は `case<行番号>` とします。値が空の列は置き換えません。
+func ReadVariants(r io.Reader, format string) ([]Variant, error) {
+	var rows []map[string]string
+	switch format {
+	case "csv":
+		records, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, errors.New("eeslism: empty variant table")
+		}
+		for _, rec := range records[1:] {
+			row := make(map[string]string, len(rec))
+			for j, v := range rec {
+				row[strings.TrimSpace(records[0][j])] = strings.TrimSpace(v)
+			}
+			rows = append(rows, row)
+		}
+	case "json":
+		var objs []map[string]interface{}
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		if err := dec.Decode(&objs); err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			row := make(map[string]string, len(obj))
+			for k, v := range obj {
+				row[k] = fmt.Sprint(v)
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return nil, fmt.Errorf("eeslism: unknown variant table format %q", format)
+	}
+
+	variants := make([]Variant, 0, len(rows))
+	names := make(map[string]bool)
+	for i, row := range rows {
+		v := Variant{Name: row["name"]}
+		if v.Name == "" {
+			v.Name = fmt.Sprintf("case%03d", i+1)
+		}
+		if v.Name == "." || v.Name == ".." || strings.ContainsAny(v.Name, `/\`) {
+			return nil, fmt.Errorf("eeslism: invalid variant name %q", v.Name)
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("eeslism: duplicate variant name %q", v.Name)
+		}
+		names[v.Name] = true
+
+		keys := make([]string, 0, len(row))
+		for k := range row {
+			if k != "name" && row[k] != "" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if name, ok := strings.CutPrefix(k, "$"); ok {
+				if !paramNamePattern.MatchString(name) {
+					return nil, fmt.Errorf("eeslism: invalid parameter name %q", k)
+				}
+				if v.Defines == nil {
+					v.Defines = make(map[string]string)
+				}
+				v.Defines[name] = row[k]
+				continue
+			}
+			o, err := ParseOverride(k, row[k])
+			if err != nil {
+				return nil, err
+			}
+			v.Overrides = append(v.Overrides, o)
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// BatchOptions はバッチ計算の設定です。
+type BatchOptions struct {
+	Input   string   // 基準の入力データファイル
+	EflPath string   // EFLファイルのディレクトリ。空の場合は埋め込みの Base を用いる
+	OutDir  string   // ケースごとの入力データファイルと計算結果を書き出すディレクトリ
+	Workers int      // 同時に計算するケースの数。0 以下の場合は1
+	Outputs []string // 集計する変数（CONTL と同じ名前）。空の場合は全ての室の室温 `<室名>_Tr`
+
+	// Done は各ケースの計算が終わるごとに呼び出されます。nil でもかまいません。
+	Done func(r *BatchResult)
+}
+
+// BatchResult は1つのケースの計算結果です。
+type BatchResult struct {
+	Variant string
+	Err     error
+	Outputs []string   // 集計した変数
+	Stats   []VarStats // Outputs の各変数の集計値
+}
+
+// VarStats は変数の計算期間（助走期間を除く）の集計値です。
+type VarStats struct {
+	Mean, Min, Max float64
+	Sum            float64 // 時間積算値（値×時間[h]）。熱量 [W] の場合は [Wh]
+}
+
+// RunBatch はケース variants を opts.Workers 個ずつ並行して計算し、variants と同じ順で結果を返します。
+// 各ケースの入力データファイルと計算結果は `<OutDir>/<ケース名>/` に書き出します。
+// 入力データファイルから参照するファイル（csv=、WCSV の file=）は opts.Input のディレクトリから探します。
+// ctx がキャンセルされた場合は、計算中のケースを中断し、未計算のケースは ctx.Err() を結果とします。
+func RunBatch(ctx context.Context, opts BatchOptions, variants []Variant) ([]*BatchResult, error) {
+	input, err := os.ReadFile(opts.Input)
+	if err != nil {
+		return nil, &InputError{Component: opts.Input, Msg: "file not found"}
+	}
+	// ケースの入力データファイルは UTF-8 で書き出す
+	if input, err = decodeText(input, EncodingAuto); err != nil {
+		return nil, &InputError{Component: opts.Input, Msg: err.Error()}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	results := make([]*BatchResult, len(variants))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := runVariant(ctx, opts, string(input), variants[i])
+				results[i] = r
+				if opts.Done != nil {
+					mu.Lock()
+					opts.Done(r)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range variants {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, nil
+}
+
+// runVariant は1つのケースを計算します。
+func runVariant(ctx context.Context, opts BatchOptions, input string, v Variant) *BatchResult {
+	r := &BatchResult{Variant: v.Name}
+	if r.Err = ctx.Err(); r.Err != nil {
+		return r
+	}
+
+	// インクルードは元の入力データファイルのディレクトリから探すため、前処理の後の内容を書き出す
+	text, err := preprocessFile(opts.Input, input, opts.EflPath, v.Defines)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	text, err = ApplyOverrides(text, v.Overrides)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	dir := filepath.Join(opts.OutDir, v.Name)
+	if r.Err = os.MkdirAll(dir, 0755); r.Err != nil {
+		return r
+	}
+	file := filepath.Join(dir, filepath.Base(opts.Input))
+	if r.Err = os.WriteFile(file, []byte(text), 0644); r.Err != nil {
+		return r
+	}
+
+	sim := NewSimulation(file, opts.EflPath)
+	sim.RefDir = filepath.Dir(opts.Input)
+	if r.Err = sim.Init(); r.Err != nil {
+		return r
+	}
+	defer func() {
+		if err := sim.Finalize(); r.Err == nil {
+			r.Err = err
+		}
+	}()
+
+	r.Outputs = opts.Outputs
+	if len(r.Outputs) == 0 {
+		for _, rm := range sim.Rmvls.Room {
+			r.Outputs = append(r.Outputs, rm.Name+"_Tr")
+		}
+	}
+	for _, name := range r.Outputs {
+		if _, err := sim.Value(name); err != nil {
+			r.Err = err
+			return r
+		}
+	}
+
+	// 各時間ステップの値を集計する。ProgressFunc を設定するため、日付は標準出力に表示しない
+	r.Stats = make([]VarStats, len(r.Outputs))
+	for i := range r.Stats {
+		r.Stats[i] = VarStats{Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+	n := 0
+	sim.ProgressFunc = func(p Progress) {
+		if p.Warmup {
+			return
+		}
+		n++
+		for i, name := range r.Outputs {
+			x, _ := sim.Value(name)
+			st := &r.Stats[i]
+			st.Sum += x * sim.DTM / 3600.0
+			st.Min = math.Min(st.Min, x)
+			st.Max = math.Max(st.Max, x)
+		}
+	}
+	for !sim.Done() {
+		if r.Err = ctx.Err(); r.Err != nil {
+			return r
+		}
+		if r.Err = sim.Step(); r.Err != nil {
+			return r
+		}
+	}
+	for i := range r.Stats {
+		if n > 0 {
+			r.Stats[i].Mean = r.Stats[i].Sum * 3600.0 / sim.DTM / float64(n)
+		} else {
+			r.Stats[i] = VarStats{}
+		}
+	}
+	return r
+}
+
+// WriteBatchResults はバッチ計算の結果を CSV で w に書き出します。
+// 列はケース名、エラー、各変数の平均値、最小値、最大値、時間積算値です。
+func WriteBatchResults(w io.Writer, results []*BatchResult) error {
+	// 変数はケースによって異なる場合があるため、現れた順に全て並べる
+	var outputs []string
+	for _, r := range results {
+		for _, name := range r.Outputs {
+			if !slices.Contains(outputs, name) {
+				outputs = append(outputs, name)
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	header := []string{"name", "error"}
+	for _, name := range outputs {
+		header = append(header, name+"_mean", name+"_min", name+"_max", name+"_sum")
+	}
+	cw.Write(header)
+	for _, r := range results {
+		rec := make([]string, len(header))
+		rec[0] = r.Variant
+		if r.Err != nil {
+			rec[1] = r.Err.Error()
+		}
+		for i, name := range r.Outputs {
+			if r.Stats == nil {
+				break // 集計前のエラー
+			}
+			j := 2 + 4*slices.Index(outputs, name)
+			st := r.Stats[i]
+			for k, x := range []float64{st.Mean, st.Min, st.Max, st.Sum} {
+				rec[j+k] = strconv.FormatFloat(x, 'g', 8, 64)
+			}
+		}
+		cw.Write(rec)
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,270 @@
+package eeslism
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestApplyOverrides は入力データの値の置き換えを確認する
+func TestApplyOverrides(t *testing.T) {
+	input := `WALL
+	-E:ExtWall  RC-150 FPS-50/2 GPB-12 ;	! 外壁
+	-R:Roof     FPS-100 GPB-12 ;
+*
+WINDOW
+	SouthWindow  t=0.65 B=0.15 R=0.50 ;
+*
+ROOM
+	TestRoom  Vol=100.0
+		south: -E 15.5 ;
+			-W SouthWindow 4.5 ;
+	*
+*
+VENT
+	TestRoom  Vent=(0.12,VentSch) ;
+	%s -v VentSch 001-(1.0)-2400 ;
+*
+EQPCAT
+	BOI testboi Qo=5000 eff=0.85 ;
+*
+`
+	tests := []struct {
+		override string
+		value    string
+		want     string
+	}{
+		{"WALL.ExtWall.FPS", "100", "-E:ExtWall RC-150 FPS-100/2 GPB-12 ;"},
+		{"WALL.Roof.Eo", "0.9", "-R:Roof Eo=0.9 FPS-100 GPB-12 ;"},
+		{"WINDOW.SouthWindow.t", "0.4", "SouthWindow t=0.4 B=0.15 R=0.50 ;"},
+		{"ROOM.TestRoom.SouthWindow", "DP6", "-W DP6 4.5 ;"},
+		{"ROOM.TestRoom.Vol", "200", "TestRoom Vol=200"},
+		{"VENT.TestRoom.Vent", "0.2", "TestRoom Vent=(0.2,VentSch) ;"},
+		{"EQPCAT.testboi.Qo", "8000", "BOI testboi Qo=8000 eff=0.85 ;"},
+	}
+	for _, tt := range tests {
+		o, err := ParseOverride(tt.override, tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ApplyOverrides(input, []Override{o})
+		if err != nil {
+			t.Fatalf("%s: %v", o, err)
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: output does not contain %q:\n%s", o, tt.want, got)
+		}
+	}
+
+	_, err := ApplyOverrides(input, []Override{{Section: "WALL", Name: "NoSuchWall", Key: "FPS", Value: "100"}})
+	var ie *InputError
+	if !errors.As(err, &ie) || ie.Component != "NoSuchWall" {
+		t.Errorf("err = %v, want *InputError for NoSuchWall", err)
+	}
+
+	if _, err := ParseOverride("WALL.ExtWall", "100"); err == nil {
+		t.Error("ParseOverride without key: expected error")
+	}
+}
+
+// TestReadVariants は CSV と JSON のケースの表の読み込みを確認する
+func TestReadVariants(t *testing.T) {
+	want := []Variant{
+		{Name: "base"},
+		{Name: "case002", Overrides: []Override{
+			{"EQPCAT", "testboi", "Qo", "8000"},
+			{"WALL", "ExtWall", "FPS", "100"},
+		}},
+	}
+	for format, table := range map[string]string{
+		"csv":  "name,WALL.ExtWall.FPS,EQPCAT.testboi.Qo\nbase,,\n,100,8000\n",
+		"json": `[{"name": "base"}, {"WALL.ExtWall.FPS": 100, "EQPCAT.testboi.Qo": "8000"}]`,
+	} {
+		got, err := ReadVariants(strings.NewReader(table), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: %d variants, want %d", format, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Name != want[i].Name || len(got[i].Overrides) != len(want[i].Overrides) {
+				t.Errorf("%s: variant %d = %+v, want %+v", format, i, got[i], want[i])
+				continue
+			}
+			for j := range want[i].Overrides {
+				if got[i].Overrides[j] != want[i].Overrides[j] {
+					t.Errorf("%s: override = %v, want %v", format, got[i].Overrides[j], want[i].Overrides[j])
+				}
+			}
+		}
+	}
+
+	if _, err := ReadVariants(strings.NewReader("name\na\na\n"), "csv"); err == nil {
+		t.Error("duplicate variant name: expected error")
+	}
+
+	// `$` の列はパラメータ
+	got, err := ReadVariants(strings.NewReader("name,$U_WIN,WINDOW.SouthWindow.t\nlowe,1.9,0.4\n"), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Defines["U_WIN"] != "1.9" || len(got[0].Overrides) != 1 {
+		t.Errorf("variant with a parameter = %+v", got)
+	}
+	if _, err := ReadVariants(strings.NewReader("name,$1A\na,1\n"), "csv"); err == nil {
+		t.Error("invalid parameter name: expected error")
+	}
+}
+
+// TestRunBatch はケースを並行して計算し、置き換えが計算結果に反映されることを確認する
+func TestRunBatch(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variants := []Variant{
+		{Name: "base"},
+		{Name: "thick", Overrides: []Override{{"WALL", "ExtWall", "FPS", "100"}}},
+		{Name: "bad", Overrides: []Override{{"WALL", "NoSuchWall", "FPS", "100"}}},
+	}
+	opts := BatchOptions{
+		Input:   src,
+		EflPath: eflPath,
+		OutDir:  t.TempDir(),
+		Workers: 3,
+		Outputs: []string{"TestRoom_Tr"},
+	}
+	results, err := RunBatch(context.Background(), opts, variants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results[:2] {
+		if r.Err != nil || r.Variant != variants[i].Name {
+			t.Fatalf("result %d = %s, %v", i, r.Variant, r.Err)
+		}
+	}
+	base, thick := results[0].Stats[0], results[1].Stats[0]
+	if !(base.Min <= base.Mean && base.Mean <= base.Max) {
+		t.Errorf("base stats = %+v", base)
+	}
+	if thick.Mean <= base.Mean {
+		t.Errorf("TestRoom_Tr mean with thicker insulation = %g, want higher than %g", thick.Mean, base.Mean)
+	}
+	if results[2].Err == nil {
+		t.Error("variant with an unknown wall: expected error")
+	}
+
+	var b bytes.Buffer
+	if err := WriteBatchResults(&b, results); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || len(records[0]) != 6 || records[0][2] != "TestRoom_Tr_mean" {
+		t.Errorf("results table = %v", records)
+	}
+
+	// キャンセルした場合は計算しない
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, _ = RunBatch(ctx, opts, variants[:1])
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", results[0].Err)
+	}
+}
+
+// TestRunBatch_RefFiles はケースの計算で、基準の入力データファイルのディレクトリにある
+// csv= のスケジュールを参照できることを確認する
+func TestRunBatch_RefFiles(t *testing.T) {
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("hour,heating\n")
+	for i := 0; i < 8760; i++ {
+		mode := OFF_SW
+		if h := (i + 1) % 24; 7 <= h && h <= 18 {
+			mode = ON_SW
+		}
+		fmt.Fprintf(&b, "%d,%c\n", i+1, mode)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "metered.csv"), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := scheduleTestModel()
+	m.Schedules = []*Schedule{{Name: "HeatCSV", Switch: true, CSV: "metered.csv", Column: "heating"}}
+	m.Controls[0].Set = []Setting{{"HeatPath", "HeatCSV"}}
+	var in bytes.Buffer
+	if _, err := m.WriteTo(&in); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "room.txt")
+	if err := os.WriteFile(input, in.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := BatchOptions{Input: input, EflPath: eflPath, OutDir: t.TempDir()}
+	results, err := RunBatch(context.Background(), opts, []Variant{{Name: "base"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.Err != nil || len(r.Stats) != 1 {
+		t.Errorf("result = %v, %+v", r.Err, r.Stats)
+	}
+}
+
+// TestRunBatch_WCSVFile はケースの計算で、基準の入力データファイルのディレクトリにある
+// GDAT WCSV の file= の実測気象データを参照できることを確認する
+func TestRunBatch_WCSVFile(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1/1 1:00 から 1/8 0:00 までの1時間値
+	var b strings.Builder
+	b.WriteString("Time,Temp,x,Zero,Cloud,Wind\n")
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for tm := t0.Add(time.Hour); !tm.After(t0.AddDate(0, 0, 7)); tm = tm.Add(time.Hour) {
+		fmt.Fprintf(&b, "%s,5,0.003,0,5,1\n", tm.Format("2006/1/2 15:04"))
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "site.csv"), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	in, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := strings.Replace(string(in), "FILE w=tokyo_3column_SI.has ;",
+		"WCSV file=site.csv time=Time Lat=35.69 Lon=139.76 Ls=135 T=Temp x=x Idn=Zero Isky=Zero CC=Cloud Wv=Wind ;", 1)
+	input := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(input, []byte(s), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := BatchOptions{Input: input, EflPath: eflPath, OutDir: t.TempDir()}
+	results, err := RunBatch(context.Background(), opts, []Variant{{Name: "base"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.Err != nil || len(r.Stats) != 1 {
+		t.Errorf("result = %v, %+v", r.Err, r.Stats)
+	}
+}
@@ -24,16 +24,16 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
-	"os"
 )
 
-func bdhpri(ofile string, rmvls *RMVLS, exs *EXSFS) {
+func bdhpri(ofile string, rmvls *RMVLS, exs *EXSFS, out OutputSink, Ferr io.Writer) {
 	Nroom := len(rmvls.Room)
 	e := exs.Exs
 
 	file := ofile + "_bdh.es"
-	fp, err := os.Create(file)
+	fp, err := out.Create(file)
 	if err != nil {
 		fmt.Println("Error opening file:", err)
 		return
@@ -75,7 +75,7 @@ func bdhpri(ofile string, rmvls *RMVLS, exs *EXSFS) {
 
 			if r.A < 0.0 {
 				E := fmt.Sprintf("RmName=%s Ble=%c A=%.3f\n", room.Name, r.ble, r.A)
-				Errprint(1, "<bdhpri>", E)
+				Errprint(1, "<bdhpri>", E, Ferr)
 			}
 
 			if r.Rwall >= 0.0 {
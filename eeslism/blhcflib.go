@@ -19,6 +19,7 @@ package eeslism
 
 import (
 	"fmt"
+	"io"
 	"math"
 )
 
@@ -31,20 +32,20 @@ const (
 
 /*    熱伝達率に関する計算  */
 
-func Htrcf(alc, alo *float64, alotype AloType, Exs []*EXSF, Tr float64, N int, alr []float64, _Sd []*RMSRF, RMmrk *rune, Wd *WDAT) {
+func Htrcf(alc, alo *float64, alotype AloType, Exs []*EXSF, Tr float64, N int, alr []float64, _Sd []*RMSRF, RMmrk *rune, Wd *WDAT, debug bool, Ferr io.Writer) {
 	var n int
 	var alic float64
 	var hc *float64
 	//var dT float64
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("Htrcf Start\n")
 	}
 
 	for n = 0; n < N; n++ {
 		Sd := _Sd[n]
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("n=%d name=%s\n", n, get_string_or_null(Sd.Name))
 		}
 
@@ -57,18 +58,18 @@ func Htrcf(alc, alo *float64, alotype AloType, Exs []*EXSF, Tr float64, N int, a
 			}
 		}
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("alic=%f\n", alic)
 		}
 
 		hc = nil
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("Sd->alicsch\n")
 		}
 
 		if Sd.alicsch != nil {
-			if DEBUG {
+			if debug {
 				fmt.Printf("test\n")
 			}
 			hc = Sd.alicsch
@@ -77,7 +78,7 @@ func Htrcf(alc, alo *float64, alotype AloType, Exs []*EXSF, Tr float64, N int, a
 			}
 		}
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("hc set end\n")
 		}
 		if alic < 0.0 {
@@ -102,7 +103,7 @@ func Htrcf(alc, alo *float64, alotype AloType, Exs []*EXSF, Tr float64, N int, a
 			}
 		}
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("----- Htrcf n=%d mrk=%c alic=%f  Sd->alic=%f\n",
 				n, Sd.mrk, alic, Sd.alic)
 		}
@@ -139,12 +140,12 @@ func Htrcf(alc, alo *float64, alotype AloType, Exs []*EXSF, Tr float64, N int, a
 			}
 		}
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("----- Htrcf n=%2d ble=%c Ts=%.1f Tr=%.1f alic=%.3f alir=%.3f rmname=%s\n",
 				n, Sd.ble, Sd.Ts, Tr, Sd.alic, Sd.alir, Sd.room.Name)
 		}
 
-		if dayprn && Ferr != nil {
+		if Ferr != nil {
 			fmt.Fprintf(Ferr, "----- Htrcf n=%2d ble=%c Ts=%f Tr=%f alic=%f alir=%f rmname=%s\n",
 				n, Sd.ble, Sd.Ts, Tr, Sd.alic, Sd.alir, Sd.room.Name)
 		}
@@ -194,7 +195,7 @@ func alcvh(dT float64) float64 {
 
 /*  室内表面間放射熱伝達率の計算  */
 
-func Radcf0(Tsav float64, alrbold *float64, N int, Sd []*RMSRF, W, alr []float64) {
+func Radcf0(Tsav float64, alrbold *float64, N int, Sd []*RMSRF, W, alr []float64, debug bool) {
 	var n int
 	var alir, TA float64
 
@@ -202,7 +203,7 @@ func Radcf0(Tsav float64, alrbold *float64, N int, Sd []*RMSRF, W, alr []float64
 	alir = 4.0 * Sgm * mathPow(TA, 3.0)
 
 	/*****/
-	if DEBUG {
+	if debug {
 		fmt.Printf("----- Radcf0   alir=%f  alrbold=%f ALITOLE\n", alir, *alrbold)
 	}
 	/*****/
@@ -227,7 +228,7 @@ func Radcf0(Tsav float64, alrbold *float64, N int, Sd []*RMSRF, W, alr []float64
 
 /*  放射伝達係数の計算  */
 
-func radex(N int, Sd []*RMSRF, F, W []float64) {
+func radex(N int, Sd []*RMSRF, F, W []float64, debug bool, Ferr io.Writer) {
 	wk := make([]float64, N*N)
 	Ff := make([]float64, N*N)
 
@@ -238,7 +239,7 @@ func radex(N int, Sd []*RMSRF, F, W []float64) {
 			}
 			wk[l] = -F[l] * (1.0 - Sd[j].Ei) / Sd[j].Ei
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("j=%d F=%f Sd=%f wk=%f\n", j, F[l], Sd[j].Ei, wk[l])
 			}
 
@@ -248,21 +249,21 @@ func radex(N int, Sd []*RMSRF, F, W []float64) {
 		nn := n*N + n
 		wk[nn] += 1.0 / Sd[n].Ei
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("nn=%d Sd=%f wk=%f\n", nn, Sd[0].Ei, wk[nn])
 		}
 
 		Ff[nn] += 1.0
 	}
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("<radex>  wk\n")
 		Matprint(" %6.4f", N, wk)
 	}
 
-	Matinv(wk, N, N, "<radex>")
+	Matinv(wk, N, N, "<radex>", Ferr)
 
-	if DEBUG {
+	if debug {
 		fmt.Print("<radex>  wkinv\n")
 		Matprint(" %6.4f", N, wk)
 	}
@@ -277,7 +278,7 @@ func radex(N int, Sd []*RMSRF, F, W []float64) {
 		}
 	}
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("<radex>  W[i,j]\n")
 		Matprint(" %6.4f", N, W)
 	}
@@ -22,11 +22,6 @@ import (
 	"io"
 )
 
-var __Helmprint_id int = 0
-var __Helmsurfprint_id int = 0
-var __Helmdy_oldday int = -1
-var __Helmdyprint_id int = 0
-
 /*
 Helminit (Building Element Heat Loss/Gain Initialization)
 
@@ -171,7 +166,7 @@ Helmroom (Building Element Heat Loss/Gain Calculation for Rooms)
 - **熱損失・熱取得の計算 (helmrmsrt, helmq)**:
   - `helmrmsrt(Rm, Ta)`: 室内の表面温度を計算し、
     表面からの熱伝達（対流、放射）を評価します。
-  - `helmq(Room, Ta, xa)`: 室内の熱収支を計算し、
+  - `helmq(Room, Ta, xa, DTM)`: 室内の熱収支を計算し、
     日射熱取得、内部発熱、換気による熱損失・熱取得などを評価します。
 - **熱損失・熱取得の集計**: 各室で計算された要素別熱損失・熱取得は、
   `qelmsum`関数によって建物全体の熱損失・熱取得（`Qetotal.Qelm`）に集計されます。
@@ -185,7 +180,7 @@ Helmroom (Building Element Heat Loss/Gain Calculation for Rooms)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要な役割を果たします。
 */
-func Helmroom(Room []*ROOM, Qrm []*QRM, Qetotal *QETOTAL, Ta, xa float64) {
+func Helmroom(Room []*ROOM, Qrm []*QRM, Qetotal *QETOTAL, Ta, xa float64, DTM float64) {
 	qelmclear(&Qetotal.Qelm)
 
 	for i := range Room {
@@ -194,7 +189,7 @@ func Helmroom(Room []*ROOM, Qrm []*QRM, Qetotal *QETOTAL, Ta, xa float64) {
 		qe := &Rm.rmqe.qelm
 
 		helmrmsrt(Rm, Ta)
-		helmq(Room, Ta, xa)
+		helmq(Room, Ta, xa, DTM)
 
 		qe.slo = Qr.Solo
 		qe.slw = Qr.Solw
@@ -241,27 +236,28 @@ Helmprint (Building Element Heat Loss/Gain Time-Series Output)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func Helmprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64,
+func (sim *Simulation) Helmprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64,
 	Room []*ROOM, Qetotal *QETOTAL) {
 	var j int
 
-	if __Helmprint_id == 0 {
+	if sim.__Helmprint_id == 0 {
 		ttlprint(fo, mrk, Simc)
 
 		for j = 0; j < 2; j++ {
 			if j == 0 {
 				fmt.Fprintf(fo, "-cat\n")
 			}
-			helmrmprint(fo, __Helmprint_id, Room, Qetotal)
+			helmrmprint(fo, sim.__Helmprint_id, Room, Qetotal)
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
-			__Helmprint_id++
+			sim.__Helmprint_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d %5.2f\n", mon, day, time)
-	helmrmprint(fo, __Helmprint_id, Room, Qetotal)
+	helmrmprint(fo, sim.__Helmprint_id, Room, Qetotal)
 }
 
 /* ----------------------------------------------------- */
@@ -411,26 +407,27 @@ Helmsurfprint (Building Element Surface Heat Loss/Gain Time-Series Output)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func Helmsurfprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64, Room []*ROOM) {
+func (sim *Simulation) Helmsurfprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64, Room []*ROOM) {
 	var j int
 
-	if __Helmsurfprint_id == 0 {
+	if sim.__Helmsurfprint_id == 0 {
 		ttlprint(fo, mrk, Simc)
 
 		for j = 0; j < 2; j++ {
 			if j == 0 {
 				fmt.Fprintf(fo, "-cat\n")
 			}
-			helmsfprint(fo, __Helmsurfprint_id, Room)
+			helmsfprint(fo, sim.__Helmsurfprint_id, Room)
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
-			__Helmsurfprint_id++
+			sim.__Helmsurfprint_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d %5.2f\n", mon, day, time)
-	helmsfprint(fo, __Helmsurfprint_id, Room)
+	helmsfprint(fo, sim.__Helmsurfprint_id, Room)
 }
 
 /* ----------------------------------------------------- */
@@ -550,10 +547,10 @@ Helmdy (Building Element Heat Loss/Gain Daily Aggregation)
 この関数は、建物のエネルギー消費量を日単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための基礎的な役割を果たします。
 */
-func Helmdy(day int, Room []*ROOM, Qetotal *QETOTAL) {
-	if day != __Helmdy_oldday {
+func (sim *Simulation) Helmdy(day int, Room []*ROOM, Qetotal *QETOTAL) {
+	if day != sim.__Helmdy_oldday {
 		helmdyint(Room, Qetotal)
-		__Helmdy_oldday = day
+		sim.__Helmdy_oldday = day
 	}
 
 	for i := range Room {
@@ -624,26 +621,27 @@ Helmdyprint (Building Element Heat Loss/Gain Daily Output)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func Helmdyprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Room []*ROOM, Qetotal *QETOTAL) {
+func (sim *Simulation) Helmdyprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Room []*ROOM, Qetotal *QETOTAL) {
 	var j int
 
-	if __Helmdyprint_id == 0 {
+	if sim.__Helmdyprint_id == 0 {
 		ttldyprint(fo, mrk, Simc)
 
 		for j = 0; j < 2; j++ {
 			if j == 0 {
 				fmt.Fprintf(fo, "-cat\n")
 			}
-			helmrmdyprint(fo, __Helmdyprint_id, Room, Qetotal)
+			helmrmdyprint(fo, sim.__Helmdyprint_id, Room, Qetotal, sim.Cff_kWh)
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
-			__Helmdyprint_id++
+			sim.__Helmdyprint_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d\n", mon, day)
-	helmrmdyprint(fo, __Helmdyprint_id, Room, Qetotal)
+	helmrmdyprint(fo, sim.__Helmdyprint_id, Room, Qetotal, sim.Cff_kWh)
 }
 
 /* ----------------------------------------------------- */
@@ -672,7 +670,7 @@ helmrmdyprint (Building Element Heat Loss/Gain Room-wise Daily Output)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func helmrmdyprint(fo io.Writer, id int, _Room []*ROOM, Qetotal *QETOTAL) {
+func helmrmdyprint(fo io.Writer, id int, _Room []*ROOM, Qetotal *QETOTAL, Cff_kWh float64) {
 	var i int
 	var q *BHELM
 	var qh *QHELM
@@ -312,7 +312,7 @@ helmq (Building Element Heat Loss/Gain for Rooms)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要な役割を果たします。
 */
-func helmq(_Room []*ROOM, Ta, xa float64) {
+func helmq(_Room []*ROOM, Ta, xa float64, DTM float64) {
 	var q, Ts *BHELM
 	var qh *QHELM
 	var Sd *RMSRF
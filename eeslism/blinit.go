@@ -17,6 +17,7 @@
 package eeslism
 
 import (
+	"io"
 	"bufio"
 	"fmt"
 	"strconv"
@@ -55,7 +56,7 @@ Walldata (Wall Data Input)
 熱負荷計算、エネルギー消費量予測、
 省エネルギー対策の検討、および快適性評価を行うための重要なデータ入力機能を提供します。
 */
-func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm []*PCM) {
+func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm []*PCM, Ferr io.Writer) {
 	var s string
 	var i = -1
 	var j, jj, jw, Nlyr, k = 0, 0, 0, 0, -1
@@ -83,7 +84,7 @@ func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm
 			Wc := &W[j]
 			if Wl.Mcode == Wc.Mcode {
 				message := fmt.Sprintf("wbmlist.efl duplicate code=<%s>", Wl.Mcode)
-				Eprint("<Walldata>", message)
+				Eprint("<Walldata>", message, Ferr)
 			}
 		}
 
@@ -91,12 +92,12 @@ func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm
 		var err error
 		Wl.Cond, err = strconv.ParseFloat(s[1], 64)
 		if err != nil {
-			Eprint("<Walldata>", "wbmlist.efl Cond error")
+			Eprint("<Walldata>", "wbmlist.efl Cond error", Ferr)
 		}
 
 		Wl.Cro, err = strconv.ParseFloat(s[2], 64)
 		if err != nil {
-			Eprint("<Walldata>", "wbmlist.efl Cro error")
+			Eprint("<Walldata>", "wbmlist.efl Cro error", Ferr)
 		}
 
 		W = append(W, *Wl)
@@ -242,7 +243,7 @@ func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm
 					Wa.PVwallcat.Rcoloff = dt // 太陽電池から集熱器裏面までの熱抵抗 (集熱板が太陽電池一体型のとき)
 					Wa.PVwallcat.Kcoloff = 1. / Wa.PVwallcat.Rcoloff
 				default:
-					Eprint("<Walldata>", s)
+					Eprint("<Walldata>", s, Ferr)
 				}
 			} else {
 				layer = append(layer, s)
@@ -382,19 +383,19 @@ func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm
 					}
 				} else {
 					s = fmt.Sprintf("ble=%c name=%s 建築一体型空気集熱の熱貫流率Ku、Kdが未定義です", Wa.ble, Wa.name)
-					Eprint("<Walldata>", s)
+					Eprint("<Walldata>", s, Ferr)
 				}
 
 				if Wa.chrRinput == false && (Wa.Kc < 0. || Wa.Ksu < 0. || Wa.Ksd < 0.) {
 					s = fmt.Sprintf("ble=%c name=%s 建築一体型空気集熱の熱貫流率Kc、Kdd、Kudが未定義です",
 						Wa.ble, Wa.name)
-					Eprint("<Walldata>", s)
+					Eprint("<Walldata>", s, Ferr)
 				}
 
 				if Wa.Ip == -1 {
 					s = fmt.Sprintf("ble=%c name=%s 建築一体型空気集熱の空気流通層<P>が未定義です",
 						Wa.ble, Wa.name)
-					Eprint("<Walldata>", s)
+					Eprint("<Walldata>", s, Ferr)
 				}
 			} else {
 				// 壁種類 -> 床暖房等放射パネル
@@ -403,7 +404,7 @@ func Walldata(section *EeTokens, fbmlist string, Wall *[]*WALL, dfwl *DFWL, pcm
 		}
 
 		Wa.N = jw + 1
-		Walli(Nbm, W, Wa, pcm)
+		Walli(Nbm, W, Wa, pcm, Ferr)
 
 		*Wall = append(*Wall, Wa)
 		i++
@@ -441,7 +442,7 @@ Windowdata (Window Data Input)
 熱負荷計算、エネルギー消費量予測、
 省エネルギー対策の検討、および快適性評価を行うための重要なデータ入力機能を提供します。
 */
-func Windowdata(section *EeTokens, Window *[]*WINDOW) {
+func Windowdata(section *EeTokens, Window *[]*WINDOW, Ferr io.Writer) {
 	E := fmt.Sprintf(ERRFMT, "WINDOW")
 
 	var N int
@@ -479,7 +480,7 @@ func Windowdata(section *EeTokens, Window *[]*WINDOW) {
 			Wc := (*Window)[k]
 			if W.Name == Wc.Name {
 				ss := fmt.Sprintf("<WINDOW>  WindowName Already Defined  (%s)", W.Name)
-				Eprint("<Windowdata>", ss)
+				Eprint("<Windowdata>", ss, Ferr)
 			}
 		}
 
@@ -526,7 +527,7 @@ func Windowdata(section *EeTokens, Window *[]*WINDOW) {
 					W.Cidtype = strings.Trim(value, "'") // 入射角特性の種類
 				default:
 					Err := fmt.Sprintf("%s %s\n", E, s)
-					Eprint("<Windowdata>", Err)
+					Eprint("<Windowdata>", Err, Ferr)
 				}
 
 				//NOTE: 以下の項目を入力する箇所が不明
@@ -574,7 +575,7 @@ Snbkdata (Sunbreak Data Input)
 日射熱取得の抑制、冷房負荷の軽減、
 昼光利用の最適化、および日影計算を行うための重要なデータ入力機能を提供します。
 */
-func Snbkdata(section *EeTokens, dsn string, Snbk *[]*SNBK) {
+func Snbkdata(section *EeTokens, dsn string, Snbk *[]*SNBK, Ferr io.Writer) {
 	// 入力チェック用パターン文字列
 	typstr := []string{
 		"HWDTLR.", // 庇
@@ -641,7 +642,7 @@ func Snbkdata(section *EeTokens, dsn string, Snbk *[]*SNBK) {
 					Type = 9 // 格子ルーバー
 				} else {
 					E := fmt.Sprintf("`%s` is invalid", vs)
-					Eprint("<Snbkdata>", E)
+					Eprint("<Snbkdata>", E, Ferr)
 				}
 
 			case "window":
@@ -721,7 +722,7 @@ func Snbkdata(section *EeTokens, dsn string, Snbk *[]*SNBK) {
 			// 庇 or ルーバー
 			if string(code[:]) != typstr[Type-1] {
 				E := fmt.Sprintf("%s %s  type=%d %s\n", Er, fields[0], Type, string(code[:]))
-				Eprint("<Snbkdata>", E)
+				Eprint("<Snbkdata>", E, Ferr)
 			}
 		case 2, 6:
 			// 袖壁
@@ -733,7 +734,7 @@ func Snbkdata(section *EeTokens, dsn string, Snbk *[]*SNBK) {
 					}
 					if j == 3 {
 						E := fmt.Sprintf("%s %s  type=%d %s\n", Er, fields[0], Type, string(code[:]))
-						Eprint("<Snbkdata>", E)
+						Eprint("<Snbkdata>", E, Ferr)
 					}
 				}
 			}
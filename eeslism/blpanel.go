@@ -18,6 +18,7 @@
 package eeslism
 
 import (
+	"io"
 	"errors"
 	"fmt"
 )
@@ -29,7 +30,7 @@ const DEBUG_PANEL = false
 
 /*  輻射パネル有効熱容量流量  */
 
-func panelwp(rdpnl *RDPNL) {
+func panelwp(rdpnl *RDPNL, Ferr io.Writer) {
 	if rdpnl == nil {
 		panic("rdpnl is nil")
 	}
@@ -57,7 +58,7 @@ func panelwp(rdpnl *RDPNL) {
 	}
 
 	if eo.Control != OFF_SW && rdpnl.cmp.Elins[0].Upv != nil {
-		rdpnl.cG = eo.G * Spcheat(eo.Fluid)
+		rdpnl.cG = eo.G * Spcheat(eo.Fluid, Ferr)
 
 		if wall.WallType == WallType_P {
 			rdpnl.Wp = rdpnl.cG * rdpnl.effpnl / sd.A
@@ -18,6 +18,8 @@ package eeslism
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"math"
 	"os"
 	"path/filepath"
@@ -27,7 +29,7 @@ import (
 
 /*  壁体デ－タの入力  */
 
-func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune) {
+func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune, fsys fs.FS, Ferr io.Writer) {
 	N := PCMcount(fi)
 
 	s := "PCMdata --"
@@ -132,7 +134,7 @@ func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune) {
 					case "h":
 						PCMa.Chartable[0].tabletype = 'h'
 					default:
-						Eprint("<PCMdata>", s)
+						Eprint("<PCMdata>", s, Ferr)
 					}
 				case "conducttable":
 					PCMa.Chartable[1].filename = filepath.Join(filepath.Dir(dsn), s)
@@ -187,7 +189,7 @@ func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune) {
 				case "IterateJudge":
 					PCMa.IterateJudge = dt
 				default:
-					Eprint("<PCMdata>", s)
+					Eprint("<PCMdata>", s, Ferr)
 				}
 			} else {
 				switch s {
@@ -197,7 +199,7 @@ func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune) {
 				case "-pcmnode":
 					PCMa.AveTemp = 'n'
 				default:
-					Eprint("<PCMdata>", s)
+					Eprint("<PCMdata>", s, Ferr)
 				}
 			}
 
@@ -208,11 +210,13 @@ func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune) {
 
 		// テーブルの読み込み（見かけの比熱）
 		if PCMa.Spctype == 't' {
+			PCMa.Chartable[0].fsys = fsys
 			TableRead(&PCMa.Chartable[0])
 		}
 
 		// テーブルの読み込み（熱伝導率）
 		if PCMa.Condtype == 't' {
+			PCMa.Chartable[1].fsys = fsys
 			TableRead(&PCMa.Chartable[1])
 		}
 
@@ -285,13 +289,21 @@ func PCMdata(fi *EeTokens, dsn string, pcm *[]*PCM, pcmiterate *rune) {
 	}
 }
 
+// open は物性値テーブルのファイルを開きます。fsys が nil の場合はファイルシステムから開きます。
+func (ct *CHARTABLE) open() (io.ReadCloser, error) {
+	if ct.fsys == nil {
+		return os.Open(ct.filename)
+	}
+	return ct.fsys.Open(ct.filename)
+}
+
 // PCMの物性値テーブルの読み込み
 func TableRead(ct *CHARTABLE) {
 	if ct.filename == "" {
 		return
 	}
 
-	fp, err := os.Open(ct.filename)
+	fp, err := ct.open()
 	if err != nil {
 		fmt.Printf("<PCMdata> xxxx file not found %s xxxx\n", ct.filename)
 		return
@@ -326,7 +338,7 @@ func TableRead(ct *CHARTABLE) {
 	}
 
 	// Reopen the file
-	fp, err = os.Open(ct.filename)
+	fp, err = ct.open()
 	if err != nil {
 		fmt.Println("<PCMdata> ファイルのオープンに失敗")
 		return
@@ -15,7 +15,7 @@ func TestPCMdata(t *testing.T) {
 		var pcm []*PCM
 		var pcmiterate rune
 
-		PCMdata(fi, "test", &pcm, &pcmiterate)
+		PCMdata(fi, "test", &pcm, &pcmiterate, nil, nil)
 
 		if len(pcm) != 2 {
 			t.Fatalf("expected 2 PCM entries, got %d", len(pcm))
@@ -66,7 +66,7 @@ func TestPCMdata(t *testing.T) {
 		var pcm []*PCM
 		var pcmiterate rune
 
-		PCMdata(fi, "test", &pcm, &pcmiterate)
+		PCMdata(fi, "test", &pcm, &pcmiterate, nil, nil)
 
 		if len(pcm) != 1 {
 			t.Fatalf("expected 1 PCM entry, got %d", len(pcm))
@@ -142,7 +142,7 @@ func TestPCMdata(t *testing.T) {
 		var pcm []*PCM
 		var pcmiterate rune
 
-		PCMdata(fi, "test", &pcm, &pcmiterate)
+		PCMdata(fi, "test", &pcm, &pcmiterate, nil, nil)
 
 		if len(pcm) != 1 {
 			t.Fatalf("expected 1 PCM entry, got %d", len(pcm))
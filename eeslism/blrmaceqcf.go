@@ -15,11 +15,11 @@
 
 package eeslism
 
-/* -------------------------------------- */
+import "io"
 
-var __Rmhtrcf_count int
+/* -------------------------------------- */
 
-func Rmhtrcf(exs *EXSFS, emrk []rune, rooms []*ROOM, sds []*RMSRF, wd *WDAT) {
+func (sim *Simulation) Rmhtrcf(exs *EXSFS, emrk []rune, rooms []*ROOM, sds []*RMSRF, wd *WDAT) {
 	if rooms != nil {
 		for i := range rooms {
 			room := rooms[i]
@@ -28,17 +28,17 @@ func Rmhtrcf(exs *EXSFS, emrk []rune, rooms []*ROOM, sds []*RMSRF, wd *WDAT) {
 			sds := sds[brs : brs+n]
 
 			// 放射熱交換係数の計算
-			if __Rmhtrcf_count == 0 || emrk[0] == '*' {
-				radex(n, sds, room.F, room.Wradx)
+			if sim.__Rmhtrcf_count == 0 || emrk[0] == '*' {
+				radex(n, sds, room.F, room.Wradx, sim.debug(), sim.Ferr)
 			}
 
 			// 放射熱伝達率の入れ替え
-			Radcf0(room.Tsav, &room.alrbold, n, sds, room.Wradx, room.alr)
+			Radcf0(room.Tsav, &room.alrbold, n, sds, room.Wradx, room.alr, sim.debug())
 
-			Htrcf(room.alc, exs.Alosch, exs.Alotype, exs.Exs, room.Tr, n, room.alr, sds, &room.mrk, wd)
+			Htrcf(room.alc, exs.Alosch, exs.Alotype, exs.Exs, room.Tr, n, room.alr, sds, &room.mrk, wd, sim.debug(), sim.dlog())
 		}
 
-		__Rmhtrcf_count++
+		sim.__Rmhtrcf_count++
 	}
 
 	for _, sd := range sds {
@@ -77,7 +77,7 @@ func Rmhtrsmcf(_Sd []*RMSRF) {
 
 /* ----------------------------------------------------------------- */
 // 透過日射、相当外気温度の計算
-func Rmexct(Room []*ROOM, Sd []*RMSRF, Wd *WDAT, Exs []*EXSF, Snbk []*SNBK, Qrm []*QRM, nday, mt int) {
+func Rmexct(Room []*ROOM, Sd []*RMSRF, Wd *WDAT, Exs []*EXSF, Snbk []*SNBK, Qrm []*QRM, nday, mt int, debug bool) {
 	if len(Room) == 0 {
 		return
 	}
@@ -181,7 +181,7 @@ func Rmexct(Room []*ROOM, Sd []*RMSRF, Wd *WDAT, Exs []*EXSF, Snbk []*SNBK, Qrm
 					S := Snbk[sb]
 
 					// 日よけの影面積率 [-]
-					Fsdw = FNFsdw(S.Type, S.Ksi, e.Tazm, e.Tprof, S.D, S.W, S.H, S.W1, S.H1, S.W2, S.H2)
+					Fsdw = FNFsdw(S.Type, S.Ksi, e.Tazm, e.Tprof, S.D, S.W, S.H, S.W1, S.H1, S.W2, S.H2, debug)
 					Sdn.Fsdworg = Fsdw
 				} else {
 					Fsdw = 0.0
@@ -343,19 +343,19 @@ func Rmexct(Room []*ROOM, Sd []*RMSRF, Wd *WDAT, Exs []*EXSF, Snbk []*SNBK, Qrm
 /* ----------------------------------------------------------------- */
 
 // 室の係数、定数項の計算
-func Roomcf(mw []*MWALL, rooms []*ROOM, rdpnl []*RDPNL, wd *WDAT, exsf *EXSFS) {
+func Roomcf(mw []*MWALL, rooms []*ROOM, rdpnl []*RDPNL, wd *WDAT, exsf *EXSFS, DTM float64, Ferr io.Writer) {
 	for _, rdpnl := range rdpnl {
-		panelwp(rdpnl)
+		panelwp(rdpnl, Ferr)
 	}
 
 	// 壁体係数行列の作成（壁体数RMSRF分だけループ）
-	RMwlc(mw, exsf, wd)
+	RMwlc(mw, exsf, wd, DTM, Ferr)
 
 	for i := range rooms {
 		room := rooms[i]
 
-		RMcf(room)
-		RMrc(room) // 室の定数項の計算
+		RMcf(room, DTM, Ferr)
+		RMrc(room, DTM) // 室の定数項の計算
 
 		room.RMx = room.GRM / DTM
 		room.RMXC = room.RMx*room.xrold + (room.HL+room.AL)/Ro
@@ -451,7 +451,7 @@ func Rmsurftd(_Room []*ROOM, Sd []*RMSRF) {
 /*--------------------------------------------------------------------------------------------*/
 
 // 室の熱取得要素の計算
-func Qrmsim(Room []*ROOM, Wd *WDAT, Qrm []*QRM) {
+func Qrmsim(Room []*ROOM, Wd *WDAT, Qrm []*QRM, DTM float64) {
 	for i := range Room {
 		Q := Qrm[i]
 		rm := Room[i]
@@ -55,7 +55,7 @@ func TestRmhtrcf(t *testing.T) {
 		emrk[0] = '*'
 
 		// Execute
-		Rmhtrcf(exs, emrk, []*ROOM{room}, surfaces, wd)
+		NewSimulation("", "").Rmhtrcf(exs, emrk, []*ROOM{room}, surfaces, wd)
 
 		// Verify basic execution
 		if room.Name != "TestRoom" {
@@ -229,7 +229,7 @@ func TestRmexct(t *testing.T) {
 		}
 
 		// Execute
-		Rmexct([]*ROOM{room}, []*RMSRF{surface}, wd, []*EXSF{exs}, []*SNBK{}, []*QRM{qrm}, 1, 1)
+		Rmexct([]*ROOM{room}, []*RMSRF{surface}, wd, []*EXSF{exs}, []*SNBK{}, []*QRM{qrm}, 1, 1, false)
 
 		// Verify calculations
 		if surface.Te == 0.0 {
@@ -273,7 +273,7 @@ func TestRoomcf(t *testing.T) {
 		}
 
 		// Execute
-		Roomcf([]*MWALL{}, []*ROOM{room}, []*RDPNL{}, wd, exsf)
+		Roomcf([]*MWALL{}, []*ROOM{room}, []*RDPNL{}, wd, exsf, 3600.0, nil)
 
 		// Verify calculations
 		if room.RMx == 0.0 {
@@ -549,7 +549,7 @@ func TestQrmsim(t *testing.T) {
 		qrm := &QRM{}
 
 		// Execute
-		Qrmsim([]*ROOM{room}, wd, []*QRM{qrm})
+		Qrmsim([]*ROOM{room}, wd, []*QRM{qrm}, 3600.0)
 
 		// Verify calculations
 		expectedHums := room.Hc + room.Hr
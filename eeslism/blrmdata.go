@@ -17,9 +17,9 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"math"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -30,7 +30,7 @@ import (
 
 /*  室構成部材の入力  */
 
-func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SCHDL, Simc *SIMCONTL) {
+func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SCHDL, Simc *SIMCONTL, Ferr io.Writer) {
 	// var Wall, w *WALL
 	// var Window, W *WINDOW
 	// var Snbk, S *SNBK
@@ -113,7 +113,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 
 		/*****************************/
 
-		if DEBUG {
+		if Simc.Debug {
 			fmt.Printf("%s\n", s)
 		}
 
@@ -130,7 +130,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 			Rmchk := Rmvls.Room[l]
 			if Rm.Name == Rmchk.Name {
 				RmnameEr = fmt.Sprintf("Room=%s is already defined name", Rm.Name)
-				Eprint("<Roomdata>", RmnameEr)
+				Eprint("<Roomdata>", RmnameEr, Ferr)
 			}
 		}
 
@@ -161,14 +161,14 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 
 			for i := 0; i < len(line)-1; i++ {
 				s := line[i]
-				if DEBUG {
+				if Simc.Debug {
 					fmt.Printf("Roomdata1  s=%s\n", s)
 				}
 
 				var st int
 				st = strings.IndexRune(s, '=')
 				if st == -1 {
-					if DEBUG {
+					if Simc.Debug {
 						fmt.Printf("Roomdata2  s=%s\n", s)
 					}
 					/*******************/
@@ -245,8 +245,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 
 						if found == false {
 							err := fmt.Sprintf("Room=%s <window> %s", Rm.Name, s)
-							Eprint("<Roomdata>", err)
-							os.Exit(1)
+							Eprint("<Roomdata>", err, Ferr)
+							panic(&InputError{Section: "ROOM", Keyword: s, Component: Rm.Name, Msg: "window undefined in WINDOW"})
 						}
 
 						// 読み進めの記録
@@ -321,7 +321,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 						Sd.ble = BLEType(c)
 					} else if compnameStart {
 						c := Sd.ble
-						if DEBUG {
+						if Simc.Debug {
 							fmt.Printf("Roomdata3  s=%s  c=%c\n", s, c)
 						}
 
@@ -352,8 +352,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 
 							if j == len(Rmvls.Window) {
 								err := fmt.Sprintf("Room=%s <window> %s", Rm.Name, stt)
-								Eprint("<Roomdata>", err)
-								os.Exit(1)
+								Eprint("<Roomdata>", err, Ferr)
+								panic(&InputError{Section: "ROOM", Keyword: stt, Component: Rm.Name, Msg: "window undefined in WINDOW"})
 							}
 						} else {
 							// 読み取り中の部位が窓以外の場合
@@ -361,7 +361,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							for j := range Rmvls.Wall {
 								w := Rmvls.Wall[j]
 
-								if DEBUG {
+								if Simc.Debug {
 									fmt.Printf("!!!!Wall.name=%s  s=%s!!!!\n", get_string_or_null(w.name), s)
 								}
 
@@ -371,7 +371,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 									(w.ble == BLE_Ceil && Sd.ble == BLE_InnerFloor)
 
 								if w.name == s && bleMatch {
-									if DEBUG {
+									if Simc.Debug {
 										fmt.Printf("---- j=%d Wallname=%s n=%d\n", j, get_string_or_null(w.name), n)
 									}
 
@@ -386,8 +386,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 
 							if !found {
 								err := fmt.Sprintf("Room=%s <wall> ble=%c %s Undefined in <WALL>", Rm.Name, Sd.ble, s)
-								Eprint("<Roomdata>", err)
-								os.Exit(1)
+								Eprint("<Roomdata>", err, Ferr)
+								panic(&InputError{Section: "ROOM", Keyword: s, Component: Rm.Name, Msg: "wall undefined in WALL"})
 							}
 
 						}
@@ -407,19 +407,19 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 						} else if key == "flrsr" {
 							// 床の日射吸収比率
 							Rm.flrsr = nil
-							k, err = idsch(value, Schdl.Sch, "")
+							k, err = idsch(value, Schdl.Sch, "", Ferr)
 							if err == nil {
 								Rm.flrsr = &Schdl.Val[k]
 							} else {
-								Rm.flrsr = envptr(value, Simc, nil, nil, nil)
+								Rm.flrsr = envptr(value, Simc, nil, nil, nil, Ferr)
 							}
 						} else if key == "alc" {
 							// alc 室内表面熱伝達率[W/m2K]。
-							k, err = idsch(value, Schdl.Sch, "")
+							k, err = idsch(value, Schdl.Sch, "", Ferr)
 							if err == nil {
 								Rm.alc = &Schdl.Val[k]
 							} else {
-								Rm.alc = envptr(s[st+1:], Simc, nil, nil, nil)
+								Rm.alc = envptr(s[st+1:], Simc, nil, nil, nil, Ferr)
 							}
 						} else if key == "Hcap" {
 							// 室内空気に付加する熱容量 [J/K]
@@ -435,26 +435,26 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							}
 						} else if key == "MCAP" {
 							// 室内に置かれた物体の熱容量 [J/K]
-							k, err := idsch(value, Schdl.Sch, "")
+							k, err := idsch(value, Schdl.Sch, "", Ferr)
 							if err == nil {
 								Rm.MCAP = &Schdl.Val[k]
 							} else {
-								Rm.MCAP = envptr(value, Simc, nil, nil, nil)
+								Rm.MCAP = envptr(value, Simc, nil, nil, nil, Ferr)
 							}
 						} else if key == "CM" {
 							// 室内に置かれた物体と室内空気との間の熱コンダクタンス [W/K]
-							k, err = idsch(value, Schdl.Sch, "")
+							k, err = idsch(value, Schdl.Sch, "", Ferr)
 							if err == nil {
 								Rm.CM = &Schdl.Val[k]
 							} else {
-								Rm.CM = envptr(value, Simc, nil, nil, nil)
+								Rm.CM = envptr(value, Simc, nil, nil, nil, Ferr)
 							}
 						} else if key == "fsolm" { // 家具への日射吸収割合
-							k, err = idsch(value, Schdl.Sch, "")
+							k, err = idsch(value, Schdl.Sch, "", Ferr)
 							if err == nil {
 								Rm.fsolm = &Schdl.Val[k]
 							} else {
-								Rm.fsolm = envptr(value, Simc, nil, nil, nil)
+								Rm.fsolm = envptr(value, Simc, nil, nil, nil, Ferr)
 							}
 						} else if key == "PCMFurn" {
 							// PCM内臓家具の場合　(PCMname,mPCM)
@@ -484,19 +484,19 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							}
 							if Rm.PCM == nil {
 								Er = fmt.Sprintf("Roomname=%s %sが見つかりません", Rm.Name, Rm.PCMfurnname)
-								Eprint(Er, "<Roomdata>")
-								os.Exit(1)
+								Eprint(Er, "<Roomdata>", Ferr)
+								panic(&InputError{Section: "ROOM", Keyword: Rm.PCMfurnname, Component: Rm.Name, Msg: "PCM undefined in PCM"})
 							}
 						} else if key == "OTc" {
 							// 作用温度設定時の対流成分重み係数の設定
-							if k, err = idsch(value, Schdl.Sch, ""); err == nil {
+							if k, err = idsch(value, Schdl.Sch, "", Ferr); err == nil {
 								Rm.OTsetCwgt = &Schdl.Val[k]
 							} else {
-								Rm.OTsetCwgt = envptr(value, Simc, nil, nil, nil)
+								Rm.OTsetCwgt = envptr(value, Simc, nil, nil, nil, Ferr)
 							}
 						} else {
 							Err := fmt.Sprintf("Room=%s s=%s", Rm.Name, s)
-							Eprint("<Roomdata>", Err)
+							Eprint("<Roomdata>", Err, Ferr)
 						}
 					} else {
 						// -- 部位設定 --
@@ -518,8 +518,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							// 見つからない場合
 							if j == len(Exs) {
 								err := fmt.Sprintf("Room=%s <exsrf> %s\n", Rm.Name, s)
-								Eprint("<Roomdata>", err)
-								os.Exit(1)
+								Eprint("<Roomdata>", err, Ferr)
+								panic(&InputError{Section: "ROOM", Keyword: s, Component: Rm.Name, Msg: "external surface undefined in EXSRF"})
 							}
 						} else if strings.HasPrefix(s, "sb=") {
 							// 日よけの検索
@@ -533,8 +533,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							// 見つからない場合
 							if j == len(Rmvls.Snbk) {
 								err := fmt.Sprintf("Room=%s <Snbrk> %s\n", Rm.Name, s)
-								Eprint("<Roomdata>", err)
-								os.Exit(1)
+								Eprint("<Roomdata>", err, Ferr)
+								panic(&InputError{Section: "ROOM", Keyword: s, Component: Rm.Name, Msg: "sunbreak undefined in SUNBRK"})
 							}
 						} else if strings.HasPrefix(s, "r=") {
 							// 隣室名
@@ -547,7 +547,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							}
 						} else if strings.HasPrefix(s, "sw=") {
 							// 窓変更設定番号
-							Sd.fnsw, err = idscw(s[st+1:], Schdl.Scw, "")
+							Sd.fnsw, err = idscw(s[st+1:], Schdl.Scw, "", Ferr)
 							if err != nil {
 								panic(err)
 							}
@@ -556,24 +556,24 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							// 放射暖冷房パネル、部位一体型集熱器のときにSYSPTHでの要素名で使用する。
 							Sd.Name = s[st+1:]
 						} else if strings.HasPrefix(s, "alc=") {
-							if k, err := idsch(s[st+1:], Schdl.Sch, ""); err == nil {
+							if k, err := idsch(s[st+1:], Schdl.Sch, "", Ferr); err == nil {
 								Sd.alicsch = &Schdl.Val[k]
 							} else {
-								Sd.alicsch = envptr(s[st+1:], Simc, nil, nil, nil)
+								Sd.alicsch = envptr(s[st+1:], Simc, nil, nil, nil, Ferr)
 							}
 						} else if strings.HasPrefix(s, "alr=") {
-							if k, err := idsch(s[st+1:], Schdl.Sch, ""); err == nil {
+							if k, err := idsch(s[st+1:], Schdl.Sch, "", Ferr); err == nil {
 								Sd.alirsch = &Schdl.Val[k]
 							} else {
-								Sd.alirsch = envptr(s[st+1:], Simc, nil, nil, nil)
+								Sd.alirsch = envptr(s[st+1:], Simc, nil, nil, nil, Ferr)
 							}
 						} else if strings.HasPrefix(s, "fsol=") {
 							Rm.Nfsolfix++
 							Sd.ffix_flg = '*'
-							if k, err := idsch(s[st+1:], Schdl.Sch, ""); err == nil {
+							if k, err := idsch(s[st+1:], Schdl.Sch, "", Ferr); err == nil {
 								Sd.fsol = &Schdl.Val[k]
 							} else {
-								Sd.fsol = envptr(s[st+1:], Simc, nil, nil, nil)
+								Sd.fsol = envptr(s[st+1:], Simc, nil, nil, nil, Ferr)
 							}
 						} else if strings.HasPrefix(s, "rmp=") {
 							// RMP名
@@ -612,8 +612,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 							}
 						} else {
 							err := fmt.Sprintf("Room=%s ble=%c s=%s\n", Rm.Name, Sd.ble, s)
-							Eprint("<Roomdata>", err)
-							os.Exit(1)
+							Eprint("<Roomdata>", err, Ferr)
+							panic(&InputError{Section: "ROOM", Keyword: s, Component: Rm.Name, Msg: "unknown surface data"})
 						}
 					}
 				}
@@ -643,8 +643,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 					// 外壁の名前が見つからない場合
 					if Sd.exs == -1 {
 						err := fmt.Sprintf("Room=%s  (%s)\n --- %s", Rm.Name, dexsname, strings.Join(line, " "))
-						Eprint("<Roomdata>", err)
-						os.Exit(1)
+						Eprint("<Roomdata>", err, Ferr)
+						panic(&InputError{Section: "ROOM", Keyword: dexsname, Component: Rm.Name, Msg: "external surface undefined in EXSRF"})
 					}
 				}
 			case 'i', 'c', 'f':
@@ -747,7 +747,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 		if Sd.nxrmname != "" {
 			err := fmt.Sprintf("%s%s", Er, Sd.nxrmname)
 			var err2 error
-			Sd.nxrm, err2 = idroom(Sd.nxrmname, Rmvls.Room, err)
+			Sd.nxrm, err2 = idroom(Sd.nxrmname, Rmvls.Room, err, Ferr)
 			if err2 != nil {
 				panic(err2)
 			}
@@ -859,12 +859,12 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 				}
 
 				var err error
-				rsd.nxrm, err = idroom(rsd.nextroom.Name, Rmvls.Room, "")
+				rsd.nxrm, err = idroom(rsd.nextroom.Name, Rmvls.Room, "", Ferr)
 				if err != nil {
 					panic(err)
 				}
 				rsd.nxn = i
-				nxsd.nxrm, err = idroom(nxsd.nextroom.Name, Rmvls.Room, "")
+				nxsd.nxrm, err = idroom(nxsd.nextroom.Name, Rmvls.Room, "", Ferr)
 				if err != nil {
 					panic(err)
 				}
@@ -881,8 +881,8 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 
 		if rsd.nxn < 0 && rsd.mwtype == RMSRFMwType_C {
 			err := fmt.Sprintf("%s    room=%s  xxx  (%s):  -%c\n", Er, Rmvls.Room[rsd.rm].Name, Rmvls.Room[rsd.nxrm].Name, rsd.ble)
-			Eprint("<Roomdata>", err)
-			os.Exit(1)
+			Eprint("<Roomdata>", err, Ferr)
+			panic(&InputError{Section: "ROOM", Keyword: rsd.Name, Component: Rmvls.Room[rsd.rm].Name, Msg: "shared inner wall undefined in room " + Rmvls.Room[rsd.nxrm].Name})
 		}
 	}
 
@@ -890,7 +890,7 @@ func Roomdata(tokens *EeTokens, Exs []*EXSF, dfwl *DFWL, Rmvls *RMVLS, Schdl *SC
 	for _, rsd := range Rmvls.Sd {
 		if rsd.A <= 0.0 {
 			fmt.Printf("Room=%s  ble=%c  A=%f\n", rsd.room.Name, rsd.ble, rsd.A)
-			os.Exit(1)
+			panic(&InputError{Section: "ROOM", Keyword: "A", Component: rsd.room.Name, Msg: fmt.Sprintf("surface area A=%f must be positive", rsd.A)})
 		}
 	}
 
@@ -1233,7 +1233,7 @@ func Balloc(Sd []*RMSRF, Wall []*WALL, Mwall *[]*MWALL) {
 					fmt.Printf("<%s> name=%s PVcap=%g ですが、WALLで太陽電池付が指定されていません\n",
 						ssd.room.Name, ssd.Name, ssd.PVwall.PVcap)
 					ssd.PVwall.PVcap = FNAN
-					os.Exit(1)
+					panic(&InputError{Section: "ROOM", Keyword: "PVcap", Component: ssd.room.Name, Msg: "PVcap is given but the wall is not a PV wall"})
 				}
 			}
 
@@ -25,11 +25,9 @@ import (
 /* ---------------------------------------------------------------- */
 /* 室内表面温度の出力 */
 
-var __Rmsfprint_ic int
-
-func Rmsfprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Sd []*RMSRF) {
-	if __Rmsfprint_ic == 0 {
-		__Rmsfprint_ic++
+func (sim *Simulation) Rmsfprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Sd []*RMSRF) {
+	if sim.__Rmsfprint_ic == 0 {
+		sim.__Rmsfprint_ic++
 
 		var n int
 		for i := range Room {
@@ -78,11 +76,9 @@ func Rmsfprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*R
 /* ---------------------------------------------------------------- */
 /* 室内表面熱流の出力 */
 
-var __Rmsfqprint_ic int
-
-func Rmsfqprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Sd []*RMSRF) {
-	if __Rmsfqprint_ic == 0 {
-		__Rmsfqprint_ic++
+func (sim *Simulation) Rmsfqprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Sd []*RMSRF) {
+	if sim.__Rmsfqprint_ic == 0 {
+		sim.__Rmsfqprint_ic++
 
 		var n int
 		for i := range Room {
@@ -136,11 +132,9 @@ func Rmsfqprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*
 /* ---------------------------------------------------------------- */
 /* 室内表面熱伝達率の出力 */
 
-var __Rmsfaprint_ic int
-
-func Rmsfaprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Sd []*RMSRF) {
-	if __Rmsfaprint_ic == 0 {
-		__Rmsfaprint_ic++
+func (sim *Simulation) Rmsfaprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Sd []*RMSRF) {
+	if sim.__Rmsfaprint_ic == 0 {
+		sim.__Rmsfaprint_ic++
 
 		var n int
 		for i := range Room {
@@ -189,11 +183,10 @@ func Rmsfaprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*
 }
 
 /* 日積算壁体貫流熱取得の出力 */
-var __Dysfprint_ic int
 
-func Dysfprint(fo io.Writer, title string, Mon, Day int, Room []*ROOM) {
-	if __Dysfprint_ic == 0 {
-		__Dysfprint_ic++
+func (sim *Simulation) Dysfprint(fo io.Writer, title string, Mon, Day int, Room []*ROOM) {
+	if sim.__Dysfprint_ic == 0 {
+		sim.__Dysfprint_ic++
 
 		var n int
 		for i := range Room {
@@ -204,6 +197,7 @@ func Dysfprint(fo io.Writer, title string, Mon, Day int, Room []*ROOM) {
 		}
 
 		fmt.Fprintf(fo, "%s;\n %d\n", title, n)
+		yearttlprint(fo, sim.Simc)
 		fmt.Fprint(fo, "Mo\tNd\t")
 
 		for i := range Room {
@@ -226,6 +220,7 @@ func Dysfprint(fo io.Writer, title string, Mon, Day int, Room []*ROOM) {
 		fmt.Fprint(fo, "\n")
 	}
 
+	sim.yearprint(fo, sim.Simc, "\t")
 	fmt.Fprintf(fo, "%d\t%d\t", Mon, Day)
 
 	for i := range Room {
@@ -248,11 +243,9 @@ func Dysfprint(fo io.Writer, title string, Mon, Day int, Room []*ROOM) {
 
 /* 日よけの影面積の出力 */
 
-var __Shdprint_ic int
-
-func Shdprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSRF) {
-	if __Shdprint_ic == 0 {
-		__Shdprint_ic++
+func (sim *Simulation) Shdprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSRF) {
+	if sim.__Shdprint_ic == 0 {
+		sim.__Shdprint_ic++
 
 		var m int
 		for i := range Sd {
@@ -288,11 +281,9 @@ func Shdprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSR
 
 /* 壁体内部温度の出力 */
 
-var __Wallprint_ic int
-
-func Wallprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSRF) {
-	if __Wallprint_ic == 0 {
-		__Wallprint_ic++
+func (sim *Simulation) Wallprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSRF) {
+	if sim.__Wallprint_ic == 0 {
+		sim.__Wallprint_ic++
 		var m int
 		for i := range Sd {
 			Sdd := Sd[i]
@@ -339,14 +330,13 @@ func Wallprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMS
 /* ---------------------------------------------------------------- */
 
 /* 潜熱蓄熱材の状態値の出力 */
-var __PCMprint_ic int
 
-func PCMprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSRF) {
+func (sim *Simulation) PCMprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSRF) {
 	var Sdd *RMSRF
 	var pcmstate *PCMSTATE
 
-	if __PCMprint_ic == 0 {
-		__PCMprint_ic++
+	if sim.__PCMprint_ic == 0 {
+		sim.__PCMprint_ic++
 
 		Sdd = Sd[0]
 		m := 0
@@ -403,11 +393,9 @@ func PCMprint(fo io.Writer, title string, Mon, Day int, time float64, Sd []*RMSR
 
 /* 日射、室内熱取得の出力 */
 
-var __Qrmprint_ic int
-
-func Qrmprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Qrm []*QRM) {
-	if __Qrmprint_ic == 0 {
-		__Qrmprint_ic++
+func (sim *Simulation) Qrmprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*ROOM, Qrm []*QRM) {
+	if sim.__Qrmprint_ic == 0 {
+		sim.__Qrmprint_ic++
 
 		// 日射、室内発熱取得出力指定の部屋数を数える
 		var n int
@@ -454,11 +442,9 @@ func Qrmprint(fo io.Writer, title string, Mon, Day int, time float64, Room []*RO
 
 /* 日射、室内熱取得の出力 */
 
-var __Dyqrmprint_ic int
-
-func Dyqrmprint(fo io.Writer, title string, Mon int, Day int, Room []*ROOM, Trdav []float64, Qrmd []*QRM) {
-	if __Dyqrmprint_ic == 0 {
-		__Dyqrmprint_ic++
+func (sim *Simulation) Dyqrmprint(fo io.Writer, title string, Mon int, Day int, Room []*ROOM, Trdav []float64, Qrmd []*QRM) {
+	if sim.__Dyqrmprint_ic == 0 {
+		sim.__Dyqrmprint_ic++
 
 		var n int
 
@@ -470,6 +456,7 @@ func Dyqrmprint(fo io.Writer, title string, Mon int, Day int, Room []*ROOM, Trda
 		}
 
 		fmt.Fprintf(fo, "%s;\n %d\n", title, n)
+		yearttlprint(fo, sim.Simc)
 		fmt.Fprintf(fo, "Mo\tNd\t")
 
 		key := [16]string{"Tr", "tsol", "asol", "arn", "hums", "light", "apls",
@@ -487,6 +474,7 @@ func Dyqrmprint(fo io.Writer, title string, Mon int, Day int, Room []*ROOM, Trda
 		fmt.Fprintf(fo, "\n")
 	}
 
+	sim.yearprint(fo, sim.Simc, "\t")
 	fmt.Fprintf(fo, "%d\t%d\t", Mon, Day)
 
 	for i := range Room {
@@ -504,10 +492,8 @@ func Dyqrmprint(fo io.Writer, title string, Mon int, Day int, Room []*ROOM, Trda
 
 /* ---------------------------------------------------------------- */
 
-var __Qrmsum_oldday int
-
-func Qrmsum(Day int, _Room []*ROOM, Qrm []*QRM, Trdav []float64, Qrmd []*QRM) {
-	if Day != __Qrmsum_oldday {
+func (sim *Simulation) Qrmsum(Day int, _Room []*ROOM, Qrm []*QRM, Trdav []float64, Qrmd []*QRM) {
+	if Day != sim.__Qrmsum_oldday {
 		for i := range _Room {
 			Q := Qrmd[i]
 			T := &Trdav[i]
@@ -529,7 +515,7 @@ func Qrmsum(Day int, _Room []*ROOM, Qrm []*QRM, Trdav []float64, Qrmd []*QRM) {
 			Q.AE = 0.0
 			Q.AG = 0.0
 		}
-		__Qrmsum_oldday = Day
+		sim.__Qrmsum_oldday = Day
 	}
 
 	for i := range _Room {
@@ -538,7 +524,7 @@ func Qrmsum(Day int, _Room []*ROOM, Qrm []*QRM, Trdav []float64, Qrmd []*QRM) {
 		T := &Trdav[i]
 		Room := _Room[i]
 
-		scale := DTM / 3600.0
+		scale := sim.DTM / 3600.0
 
 		*T += Room.Tr * scale / 24.0
 		Q.Tsol += Qr.Tsol * scale
@@ -7,8 +7,9 @@ import (
 
 func TestQrmsum(t *testing.T) {
 	// Setup
-	DTM = 3600 // 1 hour
-	__Qrmsum_oldday = 0
+	sim := NewSimulation("", "")
+	sim.DTM = 3600 // 1 hour
+	sim.__Qrmsum_oldday = 0
 	Day1 := 1
 	Day2 := 2
 	_Room := []*ROOM{
@@ -23,7 +24,7 @@ func TestQrmsum(t *testing.T) {
 	}
 
 	// Execute for Day 1
-	Qrmsum(Day1, _Room, Qrm, Trdav, Qrmd)
+	sim.Qrmsum(Day1, _Room, Qrm, Trdav, Qrmd)
 
 	// Verify for Day 1
 	if Qrmd[0].Tsol != 100 {
@@ -31,7 +32,7 @@ func TestQrmsum(t *testing.T) {
 	}
 
 	// Execute for Day 1 again
-	Qrmsum(Day1, _Room, Qrm, Trdav, Qrmd)
+	sim.Qrmsum(Day1, _Room, Qrm, Trdav, Qrmd)
 
 	// Verify for Day 1 again
 	if Qrmd[0].Tsol != 200 {
@@ -39,7 +40,7 @@ func TestQrmsum(t *testing.T) {
 	}
 
 	// Execute for Day 2
-	Qrmsum(Day2, _Room, Qrm, Trdav, Qrmd)
+	sim.Qrmsum(Day2, _Room, Qrm, Trdav, Qrmd)
 
 	// Verify for Day 2
 	if Qrmd[0].Tsol != 100 {
@@ -21,25 +21,25 @@ import (
 )
 
 /* 室供給熱量、放射パネルについての出力 */
-var __Rmpnlprint_id int = 0
 
-func Rmpnlprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64, Room []*ROOM) {
+func (sim *Simulation) Rmpnlprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64, Room []*ROOM) {
 
-	if __Rmpnlprint_id == 0 {
+	if sim.__Rmpnlprint_id == 0 {
 		ttlprint(fo, mrk, Simc)
 
 		for j := 0; j < 2; j++ {
 			if j == 0 {
 				fmt.Fprintf(fo, "-cat\n")
 			}
-			rmqaprint(fo, __Rmpnlprint_id, Room)
+			rmqaprint(fo, sim.__Rmpnlprint_id, Room)
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
-			__Rmpnlprint_id++
+			sim.__Rmpnlprint_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d %5.2f\n", mon, day, time)
-	rmqaprint(fo, __Rmpnlprint_id, Room)
+	rmqaprint(fo, sim.__Rmpnlprint_id, Room)
 }
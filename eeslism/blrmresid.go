@@ -17,6 +17,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"regexp"
 	"strings"
@@ -26,7 +27,7 @@ import (
 /*
 居住者スケジュ－ルの入力              */
 
-func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMCONTL) {
+func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMCONTL, Ferr io.Writer) {
 	errFmt := fmt.Sprintf(ERRFMT, "RESI")
 
 	for fi.IsEnd() == false {
@@ -44,7 +45,7 @@ func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMC
 		}
 
 		errMsg := errFmt + s
-		i, err := idroom(s, rooms, errMsg)
+		i, err := idroom(s, rooms, errMsg, Ferr)
 		if err != nil {
 			panic(err)
 		}
@@ -75,18 +76,18 @@ func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMC
 
 					// 在室率設定値名
 					ss = match[2]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.Hmsch = &schdl.Val[k]
 					} else {
-						rm.Hmsch = envptr(ss, simc, nil, nil, nil)
+						rm.Hmsch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 
 					// 作業強度設定値名
 					sss = match[3]
-					if k, err := idsch(sss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(sss, schdl.Sch, "", Ferr); err == nil {
 						rm.Hmwksch = &schdl.Val[k]
 					} else {
-						rm.Hmwksch = envptr(sss, simc, nil, nil, nil)
+						rm.Hmwksch = envptr(sss, simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -99,26 +100,26 @@ func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMC
 				if len(match) == 4 {
 					// 代謝率(Met値)設定値名
 					ss = match[1]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.Metsch = &schdl.Val[k]
 					} else {
-						rm.Metsch = envptr(ss, simc, nil, nil, nil)
+						rm.Metsch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 
 					// 着衣量(Clo値)設定値名
 					sss = match[2]
-					if k, err := idsch(sss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(sss, schdl.Sch, "", Ferr); err == nil {
 						rm.Closch = &schdl.Val[k]
 					} else {
-						rm.Closch = envptr(sss, simc, nil, nil, nil)
+						rm.Closch = envptr(sss, simc, nil, nil, nil, Ferr)
 					}
 
 					// 室内風速設定値名
 					s4 = match[3]
-					if k, err := idsch(s4, schdl.Sch, ""); err == nil {
+					if k, err := idsch(s4, schdl.Sch, "", Ferr); err == nil {
 						rm.Wvsch = &schdl.Val[k]
 					} else {
-						rm.Wvsch = envptr(s4, simc, nil, nil, nil)
+						rm.Wvsch = envptr(s4, simc, nil, nil, nil, Ferr)
 					}
 
 					*pmvpri = 1
@@ -130,7 +131,7 @@ func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMC
 				}
 
 			default:
-				Eprint("<Residata>", errMsg)
+				Eprint("<Residata>", errMsg, Ferr)
 			}
 		}
 	}
@@ -140,7 +141,7 @@ func Residata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, pmvpri *int, simc *SIMC
 /*
 照明・機器利用スケジュ－ルの入力              */
 
-func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
+func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL, Ferr io.Writer) {
 	errFmt := fmt.Sprintf(ERRFMT, "APPL")
 
 	for fi.IsEnd() == false {
@@ -158,7 +159,7 @@ func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
 		}
 
 		errMsg := errFmt + s
-		i, err := idroom(s, rooms, errMsg)
+		i, err := idroom(s, rooms, errMsg, Ferr)
 		if err != nil {
 			panic(err)
 		}
@@ -192,10 +193,10 @@ func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
 
 					// 照明入力設定値名
 					ss = match[3]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.Lightsch = &schdl.Val[k]
 					} else {
-						rm.Lightsch = envptr(ss, simc, nil, nil, nil)
+						rm.Lightsch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -221,10 +222,10 @@ func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
 
 					// 設定値名
 					ss = match[3]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.Assch = &schdl.Val[k]
 					} else {
-						rm.Assch = envptr(ss, simc, nil, nil, nil)
+						rm.Assch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -244,10 +245,10 @@ func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
 
 					// 設定値名
 					ss = match[2]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.Alsch = &schdl.Val[k]
 					} else {
-						rm.Alsch = envptr(ss, simc, nil, nil, nil)
+						rm.Alsch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -267,10 +268,10 @@ func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
 
 					// 電力設定値名
 					ss = match[2]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.AEsch = &schdl.Val[k]
 					} else {
-						rm.AEsch = envptr(ss, simc, nil, nil, nil)
+						rm.AEsch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -290,17 +291,17 @@ func Appldata(fi *EeTokens, schdl *SCHDL, rooms []*ROOM, simc *SIMCONTL) {
 
 					// ガス設定値名
 					ss = match[2]
-					if k, err := idsch(ss, schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, schdl.Sch, "", Ferr); err == nil {
 						rm.AGsch = &schdl.Val[k]
 					} else {
-						rm.AGsch = envptr(ss, simc, nil, nil, nil)
+						rm.AGsch = envptr(ss, simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
 				}
 
 			default:
-				Eprint("<Appldata>", errMsg)
+				Eprint("<Appldata>", errMsg, Ferr)
 			}
 		}
 	}
@@ -18,7 +18,7 @@ func TestResidata(t *testing.T) {
 	Simc := &SIMCONTL{}
 
 	// Execute
-	Residata(fi, Schdl, Room, &pmvpri, Simc)
+	Residata(fi, Schdl, Room, &pmvpri, Simc, nil)
 
 	// Verify
 	if Room[0].Nhm != 2.0 {
@@ -57,7 +57,7 @@ func TestAppldata(t *testing.T) {
 	Simc := &SIMCONTL{}
 
 	// Execute
-	Appldata(fi, Schdl, Room, Simc)
+	Appldata(fi, Schdl, Room, Simc, nil)
 
 	// Verify
 	if Room[0].Light != 100.0 {
@@ -18,6 +18,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"math"
 )
@@ -72,7 +73,7 @@ func Windowschdlr(isw []ControlSWType, windows []*WINDOW, ds []*RMSRF) {
 /* --------------------------------------------- */
 
 // 室内発熱の計算
-func (Room *ROOM) Qischdlr() {
+func (Room *ROOM) Qischdlr(debug bool, Ferr io.Writer) {
 	Ht := [9]float64{92, 106, 119, 131, 145, 198, 226, 264, 383}
 	Hs24 := [9]float64{58, 62, 63, 64, 69, 76, 83, 99, 137}
 	d := [9]float64{3.5, 3.6, 4.0, 4.2, 4.4, 6.5, 7.0, 7.3, 6.3}
@@ -86,7 +87,7 @@ func (Room *ROOM) Qischdlr() {
 	Room.Ar = 0.0
 	Room.AL = 0.0
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("<Qischdlr> Name=%s\n", Room.Name)
 	}
 
@@ -98,10 +99,10 @@ func (Room *ROOM) Qischdlr() {
 
 			if wk < 0 || wk > 8 {
 				s := fmt.Sprintf("Room=%s wk=%d", Room.Name, wk)
-				Eprint("<Qischdlr>", s)
+				Eprint("<Qischdlr>", s, Ferr)
 			}
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("wk=%d\n", wk)
 			}
 
@@ -117,7 +118,7 @@ func (Room *ROOM) Qischdlr() {
 			Room.Hr = Q * 0.5
 			Room.HL = Ht[wk]*N - Q
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("Hc=%f Hr=%f HL=%f\n", Room.Hc, Room.Hr, Room.HL)
 			}
 		}
@@ -56,7 +56,7 @@ func TestQischdlr(t *testing.T) {
 	}
 
 	// Execute
-	Room.Qischdlr()
+	Room.Qischdlr(false, nil)
 
 	// Verify
 	if Room.Hc == 0 {
@@ -17,6 +17,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"regexp"
 	"strings"
@@ -57,7 +58,7 @@ Ventdata (Ventilation Data Input)
 この関数は、建物の換気計画をモデル化し、
 室内空気質、熱負荷、およびエネルギー消費量を評価するための重要なデータ入力機能を提供します。
 */
-func Ventdata(fi *EeTokens, Schdl *SCHDL, Room []*ROOM, Simc *SIMCONTL) {
+func Ventdata(fi *EeTokens, Schdl *SCHDL, Room []*ROOM, Simc *SIMCONTL, Ferr io.Writer) {
 	var Rm *ROOM
 	var name1, ss, E string
 	var k int
@@ -73,7 +74,7 @@ func Ventdata(fi *EeTokens, Schdl *SCHDL, Room []*ROOM, Simc *SIMCONTL) {
 		name1 = line[0]
 
 		// 室検索
-		i, err := idroom(name1, Room, E+name1)
+		i, err := idroom(name1, Room, E+name1, Ferr)
 		if err != nil {
 			panic(err)
 		}
@@ -99,10 +100,10 @@ func Ventdata(fi *EeTokens, Schdl *SCHDL, Room []*ROOM, Simc *SIMCONTL) {
 
 					// 換気量設定値名
 					ss = match[2]
-					if k, err := idsch(ss, Schdl.Sch, ""); err == nil {
+					if k, err := idsch(ss, Schdl.Sch, "", Ferr); err == nil {
 						Rm.Vesc = &Schdl.Val[k]
 					} else {
-						Rm.Vesc = envptr(ss, Simc, nil, nil, nil)
+						Rm.Vesc = envptr(ss, Simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -122,10 +123,10 @@ func Ventdata(fi *EeTokens, Schdl *SCHDL, Room []*ROOM, Simc *SIMCONTL) {
 
 					// 隙間風量設定値名
 					ss = match[2]
-					if k, err = idsch(ss, Schdl.Sch, ""); err == nil {
+					if k, err = idsch(ss, Schdl.Sch, "", Ferr); err == nil {
 						Rm.Visc = &Schdl.Val[k]
 					} else {
-						Rm.Visc = envptr(ss, Simc, nil, nil, nil)
+						Rm.Visc = envptr(ss, Simc, nil, nil, nil, Ferr)
 					}
 				} else {
 					fmt.Println("No match found.")
@@ -133,7 +134,7 @@ func Ventdata(fi *EeTokens, Schdl *SCHDL, Room []*ROOM, Simc *SIMCONTL) {
 
 			default:
 				err := fmt.Sprintf("Room=%s  %s", Rm.Name, key)
-				Eprint("<Ventedata>", err)
+				Eprint("<Ventedata>", err, Ferr)
 			}
 		}
 	}
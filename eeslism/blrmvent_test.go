@@ -18,7 +18,7 @@ func TestVentdata(t *testing.T) {
 	Simc := &SIMCONTL{}
 
 	// Execute
-	Ventdata(fi, Schdl, Room, Simc)
+	Ventdata(fi, Schdl, Room, Simc, nil)
 
 	// Verify
 	if Room[0].Gve != 1.0 {
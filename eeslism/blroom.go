@@ -16,7 +16,10 @@
 /*  room.c       */
 package eeslism
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /* ----------------------------------------------------------- */
 
@@ -53,7 +56,7 @@ RMcf (Room Coefficient Calculation)
 
 /* ----------------------------------------------------------- */
 
-func RMcf(Room *ROOM) {
+func RMcf(Room *ROOM, DTM float64, Ferr io.Writer) {
 	N := Room.N
 	for n := 0; n < N; n++ {
 		Sdn := Room.rsrf[n]
@@ -121,7 +124,7 @@ func RMcf(Room *ROOM) {
 	}
 
 	E := fmt.Sprintf("<RMcf> name=%s", Room.Name)
-	Matinv(XA, N, N, E)
+	Matinv(XA, N, N, E, Ferr)
 
 	for n := 0; n < N; n++ {
 		Sdn := Room.rsrf[n]
@@ -193,7 +196,7 @@ func RMcf(Room *ROOM) {
 
 	// 室温の係数
 	// 家具の熱容量の計算
-	FunCoeff(Room)
+	FunCoeff(Room, DTM)
 }
 
 /*
@@ -224,7 +227,7 @@ FunCoeff (Furniture Coefficient Calculation)
 この関数は、室内の熱的慣性や蓄熱性能を正確に評価するために不可欠であり、
 特に省エネルギー設計や快適性評価において重要な役割を果たします。
 */
-func FunCoeff(Room *ROOM) {
+func FunCoeff(Room *ROOM, DTM float64) {
 	// 室温の係数
 	// 家具の熱容量の計算
 	Room.FunHcap = 0.0
@@ -293,7 +296,7 @@ RMrc (Room Constant Term Calculation)
 この関数で計算される定数項は、室内の熱収支方程式を解く際に、
 室温の変動を引き起こす外部要因や内部要因を総合的に評価するために不可欠です。
 */
-func RMrc(Room *ROOM) {
+func RMrc(Room *ROOM, DTM float64) {
 	N := Room.N
 	XA := Room.XA
 	CRX := make([]float64, N)
@@ -461,7 +464,7 @@ RMwlc (Room Wall Coefficient Matrix Creation)
 この関数は、建物の熱的性能を詳細に評価し、特に蓄熱効果を考慮した省エネルギー設計や、
 快適な室内環境の実現に向けた壁体設計の検討に不可欠な役割を果たします。
 */
-func RMwlc(Mw []*MWALL, Exsfs *EXSFS, Wd *WDAT) {
+func RMwlc(Mw []*MWALL, Exsfs *EXSFS, Wd *WDAT, DTM float64, Ferr io.Writer) {
 	for i := range Mw {
 		var Mw *MWALL = Mw[i]
 		var Wall *WALL = Mw.wall
@@ -486,7 +489,7 @@ func RMwlc(Mw []*MWALL, Exsfs *EXSFS, Wd *WDAT) {
 		// 行列作成
 		Wallfdc(Mw.M, Mw.mp, Mw.res, Mw.cap, Wp, Mw.UX,
 			&Mw.uo, &Mw.um, &Mw.Pc, Wall.WallType, Sd, Wd, Exsfs, Wall,
-			Mw.Told, Mw.Toldd, Mw.sd.pcmstate)
+			Mw.Told, Mw.Toldd, Mw.sd.pcmstate, DTM, Ferr)
 	}
 }
 
@@ -519,7 +522,7 @@ RMwlt (Room Wall Temperature Calculation)
 この関数は、建物の熱的性能評価において、壁体内部の複雑な熱挙動をモデル化するために不可欠であり、
 特に高断熱・高気密住宅や、蓄熱性能を重視した建築物の設計・評価に重要な役割を果たします。
 */
-func RMwlt(Mw []*MWALL) {
+func RMwlt(Mw []*MWALL, debug bool) {
 	for i := range Mw {
 		Mw := Mw[i]
 		Sd := Mw.sd
@@ -540,7 +543,7 @@ func RMwlt(Mw []*MWALL) {
 		Room := Sd.room
 		Tie := (Sd.alic*Room.Tr + Sd.alir*Sd.Tmrt + Sd.RS) / Sd.ali
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("----- RMwlt i=%d room=%s ble=%c %s  Tie=%f Tee=%f\n", i, Sd.room.Name, Sd.ble, get_string_or_null(Sd.Name), Tie, Tee)
 		}
 
@@ -589,7 +592,7 @@ PCMの相変化を考慮した壁体内部温度の収束計算の初期段階
 特にPCMの潜熱蓄熱効果を最大限に活用した省エネルギー設計や、
 室温の安定化を図るためのシミュレーションにおいて重要な役割を果たします。
 */
-func RMwltd(Mw []*MWALL) {
+func RMwltd(Mw []*MWALL, debug bool) {
 	for i := range Mw {
 		var Mw *MWALL = Mw[i]
 		var Sd *RMSRF = Mw.sd
@@ -610,7 +613,7 @@ func RMwltd(Mw []*MWALL) {
 			// Tie
 			Tie := (Sd.alic*Room.Tr + Sd.alir*Sd.Tmrt + Sd.RS) / Sd.ali
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("----- RMwlt i=%d room=%s ble=%c %s  Tie=%f Tee=%f\n",
 					i, Sd.room.Name, Sd.ble, get_string_or_null(Sd.Name), Tie, Tee)
 			}
@@ -77,7 +77,7 @@ func TestRMcf(t *testing.T) {
 	room.alr[3] = 0.8 // alr[1][1]
 
 	// Execute
-	RMcf(room)
+	RMcf(room, 3600.0, nil)
 
 	// Verify basic calculations
 	if room.rsrf[0].FI == 0.0 {
@@ -125,7 +125,7 @@ func TestFunCoeff(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			FunCoeff(tt.room)
+			FunCoeff(tt.room, 3600.0)
 
 			if math.Abs(tt.room.FMT-tt.expected) > 1e-6 {
 				t.Errorf("FMT = %f, want %f", tt.room.FMT, tt.expected)
@@ -184,7 +184,7 @@ func TestRMrc(t *testing.T) {
 	room.XA[3] = 1.0
 
 	// Execute
-	RMrc(room)
+	RMrc(room, 3600.0)
 
 	// Verify calculations
 	expectedHGc := room.Hc + room.Lc + room.Ac + room.Qeqp*room.eqcv
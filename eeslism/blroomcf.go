@@ -52,13 +52,13 @@ eeroomcf (Room Coefficient Calculation for Energy Simulation)
 この関数は、建物の熱負荷計算、エネルギー消費量予測、
 省エネルギー対策の検討、および快適性評価を行うための重要な役割を果たします。
 */
-func eeroomcf(Wd *WDAT, Exs *EXSFS, Rmvls *RMVLS, nday int, mt int) {
+func (sim *Simulation) eeroomcf(Wd *WDAT, Exs *EXSFS, Rmvls *RMVLS, nday int, mt int) {
 	// 熱伝達率の計算
 
 	// 表面熱伝達率（対流・放射））の計算
-	Rmhtrcf(Exs, Rmvls.Emrk, Rmvls.Room, Rmvls.Sd, Wd)
+	sim.Rmhtrcf(Exs, Rmvls.Emrk, Rmvls.Room, Rmvls.Sd, Wd)
 
-	if DEBUG {
+	if sim.debug() {
 		// 表面熱伝達率の表示
 		xpralph(Rmvls.Room, Rmvls.Sd)
 	}
@@ -67,15 +67,15 @@ func eeroomcf(Wd *WDAT, Exs *EXSFS, Rmvls *RMVLS, nday int, mt int) {
 	Rmhtrsmcf(Rmvls.Sd)
 
 	// 透過日射、相当外気温度の計算
-	Rmexct(Rmvls.Room, Rmvls.Sd, Wd, Exs.Exs, Rmvls.Snbk, Rmvls.Qrm, nday, mt)
+	Rmexct(Rmvls.Room, Rmvls.Sd, Wd, Exs.Exs, Rmvls.Snbk, Rmvls.Qrm, nday, mt, sim.debug())
 
 	// 室の係数（壁体熱伝導等））、定数項の計算
-	Roomcf(Rmvls.Mw, Rmvls.Room, Rmvls.Rdpnl, Wd, Exs)
+	Roomcf(Rmvls.Mw, Rmvls.Room, Rmvls.Rdpnl, Wd, Exs, sim.DTM, sim.Ferr)
 
 	// C版 blroomcf.c では xprroom/xprxas はコメントアウトされている（デバッグ用出力）。
-	// C版に合わせて DEBUG 時のみ呼び出す（xpralph と同様の扱い）。
-	if DEBUG {
-		xprroom(Rmvls.Room)
-		xprxas(Rmvls.Room, Rmvls.Sd)
+	// C版に合わせて *debug 指定時のみ呼び出す（xpralph と同様の扱い）。
+	if sim.debug() {
+		xprroom(Rmvls.Room, sim.debug(), sim.Ferr)
+		xprxas(Rmvls.Room, Rmvls.Sd, sim.debug(), sim.Ferr)
 	}
 }
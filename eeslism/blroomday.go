@@ -22,12 +22,6 @@ import (
 	"io"
 )
 
-var __Roomday_oldday = INAN
-var __Roomday_oldMon = INAN
-
-var __Rmdyprint_id int = 0
-var __Rmmonprint_id int = 0
-
 /*
 Roomday (Room Daily and Monthly Data Aggregation)
 
@@ -60,12 +54,12 @@ Roomday (Room Daily and Monthly Data Aggregation)
 この関数は、室の熱的挙動とエネルギー消費量を多角的に分析し、
 快適性向上や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Simdayend int) {
+func (sim *Simulation) Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Simdayend int) {
 	Mo := Mon - 1
 	tt := ConvertHour(ttmm)
 
 	// 日集計
-	if Nday != __Roomday_oldday {
+	if Nday != sim.__Roomday_oldday {
 		for i := range Rm {
 			Room := Rm[i]
 
@@ -105,11 +99,11 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 			qdyint(&Rdpnl.PVdy)
 		}
 
-		__Roomday_oldday = Nday
+		sim.__Roomday_oldday = Nday
 	}
 
 	// 月集計
-	if Mon != __Roomday_oldMon {
+	if Mon != sim.__Roomday_oldMon {
 		//printf("リセット\n") ;
 		for i := range Rm {
 			Room := Rm[i]
@@ -150,7 +144,7 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 			qdyint(&Rdpnl.mPVdy)
 		}
 
-		__Roomday_oldMon = Mon
+		sim.__Roomday_oldMon = Mon
 	}
 
 	// 日集計
@@ -163,21 +157,21 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 
 		R := Room.rmld
 		if R != nil {
-			qdaysum(int64(ttmm), ON_SW, R.Qs, &R.Qdys)
-			qdaysum(int64(ttmm), ON_SW, R.Ql, &R.Qdyl)
-			qdaysum(int64(ttmm), ON_SW, R.Qt, &R.Qdyt)
+			qdaysum(int64(ttmm), ON_SW, R.Qs, &R.Qdys, sim.Cff_kWh)
+			qdaysum(int64(ttmm), ON_SW, R.Ql, &R.Qdyl, sim.Cff_kWh)
+			qdaysum(int64(ttmm), ON_SW, R.Qt, &R.Qdyt, sim.Cff_kWh)
 		}
 		for j := 0; j < Room.Nasup; j++ {
 			A := Room.Arsp[j]
-			qdaysum(int64(ttmm), ON_SW, A.Qs, &A.Qdys)
-			qdaysum(int64(ttmm), ON_SW, A.Ql, &A.Qdyl)
-			qdaysum(int64(ttmm), ON_SW, A.Qt, &A.Qdyt)
+			qdaysum(int64(ttmm), ON_SW, A.Qs, &A.Qdys, sim.Cff_kWh)
+			qdaysum(int64(ttmm), ON_SW, A.Ql, &A.Qdyl, sim.Cff_kWh)
+			qdaysum(int64(ttmm), ON_SW, A.Qt, &A.Qdyt, sim.Cff_kWh)
 		}
 
 		for j := 0; j < Room.N; j++ {
 			Sd := Room.rsrf[j]
 			svdaysum(int64(ttmm), ON_SW, Sd.Ts, &Sd.Tsdy)
-			qdaysum(int64(ttmm), ON_SW, Sd.Qi, &Sd.SQi)
+			qdaysum(int64(ttmm), ON_SW, Sd.Qi, &Sd.SQi, sim.Cff_kWh)
 		}
 	}
 
@@ -186,8 +180,8 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 
 		svdaysum(int64(ttmm), Rdpnl.cmp.Control, Rdpnl.Tpo, &Rdpnl.Tpody)
 		svdaysum(int64(ttmm), Rdpnl.cmp.Control, Rdpnl.Tpi, &Rdpnl.Tpidy)
-		qdaysum(int64(ttmm), Rdpnl.cmp.Control, Rdpnl.Q, &Rdpnl.Qdy)
-		qdaysumNotOpe(int64(ttmm), Rdpnl.sd[0].Iwall*Rdpnl.sd[0].A, &Rdpnl.Scoldy)
+		qdaysum(int64(ttmm), Rdpnl.cmp.Control, Rdpnl.Q, &Rdpnl.Qdy, sim.Cff_kWh)
+		qdaysumNotOpe(int64(ttmm), Rdpnl.sd[0].Iwall*Rdpnl.sd[0].A, &Rdpnl.Scoldy, sim.Cff_kWh)
 
 		control := OFF_SW
 		if Rdpnl.sd[0].PVwall.Power > 0. {
@@ -195,7 +189,7 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 		}
 
 		svdaysum(int64(ttmm), control, Rdpnl.sd[0].PVwall.TPV, &Rdpnl.TPVdy)
-		qdaysumNotOpe(int64(ttmm), Rdpnl.sd[0].PVwall.Power, &Rdpnl.PVdy)
+		qdaysumNotOpe(int64(ttmm), Rdpnl.sd[0].PVwall.Power, &Rdpnl.PVdy, sim.Cff_kWh)
 	}
 
 	// 月集計
@@ -210,21 +204,21 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 
 		R := Room.rmld
 		if R != nil {
-			qmonsum(Mon, Day, ttmm, ON_SW, R.Qs, &R.mQdys, Nday, Simdayend)
-			qmonsum(Mon, Day, ttmm, ON_SW, R.Ql, &R.mQdyl, Nday, Simdayend)
-			qmonsum(Mon, Day, ttmm, ON_SW, R.Qt, &R.mQdyt, Nday, Simdayend)
+			qmonsum(Mon, Day, ttmm, ON_SW, R.Qs, &R.mQdys, Nday, Simdayend, sim.Cff_kWh)
+			qmonsum(Mon, Day, ttmm, ON_SW, R.Ql, &R.mQdyl, Nday, Simdayend, sim.Cff_kWh)
+			qmonsum(Mon, Day, ttmm, ON_SW, R.Qt, &R.mQdyt, Nday, Simdayend, sim.Cff_kWh)
 		}
 		for j := 0; j < Room.Nasup; j++ {
 			A := Room.Arsp[j]
-			qmonsum(Mon, Day, ttmm, ON_SW, A.Qs, &A.mQdys, Nday, Simdayend)
-			qmonsum(Mon, Day, ttmm, ON_SW, A.Ql, &A.mQdyl, Nday, Simdayend)
-			qmonsum(Mon, Day, ttmm, ON_SW, A.Qt, &A.mQdyt, Nday, Simdayend)
+			qmonsum(Mon, Day, ttmm, ON_SW, A.Qs, &A.mQdys, Nday, Simdayend, sim.Cff_kWh)
+			qmonsum(Mon, Day, ttmm, ON_SW, A.Ql, &A.mQdyl, Nday, Simdayend, sim.Cff_kWh)
+			qmonsum(Mon, Day, ttmm, ON_SW, A.Qt, &A.mQdyt, Nday, Simdayend, sim.Cff_kWh)
 		}
 
 		for j := 0; j < Room.N; j++ {
 			Sd := Room.rsrf[j]
 			svmonsum(Mon, Day, ttmm, ON_SW, Sd.Ts, &Sd.mTsdy, Nday, Simdayend)
-			qmonsum(Mon, Day, ttmm, ON_SW, Sd.Qi, &Sd.mSQi, Nday, Simdayend)
+			qmonsum(Mon, Day, ttmm, ON_SW, Sd.Qi, &Sd.mSQi, Nday, Simdayend, sim.Cff_kWh)
 		}
 	}
 
@@ -233,8 +227,8 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 
 		svmonsum(Mon, Day, ttmm, Rdpnl.cmp.Control, Rdpnl.Tpo, &Rdpnl.mTpody, Nday, Simdayend)
 		svmonsum(Mon, Day, ttmm, Rdpnl.cmp.Control, Rdpnl.Tpi, &Rdpnl.mTpidy, Nday, Simdayend)
-		qmonsum(Mon, Day, ttmm, Rdpnl.cmp.Control, Rdpnl.Q, &Rdpnl.mQdy, Nday, Simdayend)
-		qmonsumNotOpe(Mon, Day, ttmm, Rdpnl.sd[0].Iwall*Rdpnl.sd[0].A, &Rdpnl.mScoldy, Nday, Simdayend)
+		qmonsum(Mon, Day, ttmm, Rdpnl.cmp.Control, Rdpnl.Q, &Rdpnl.mQdy, Nday, Simdayend, sim.Cff_kWh)
+		qmonsumNotOpe(Mon, Day, ttmm, Rdpnl.sd[0].Iwall*Rdpnl.sd[0].A, &Rdpnl.mScoldy, Nday, Simdayend, sim.Cff_kWh)
 
 		control := OFF_SW
 		if Rdpnl.sd[0].PVwall.Power > 0. {
@@ -242,7 +236,7 @@ func Roomday(Mon int, Day int, Nday int, ttmm int, Rm []*ROOM, Rdp []*RDPNL, Sim
 		}
 
 		svmonsum(Mon, Day, ttmm, control, Rdpnl.sd[0].PVwall.TPV, &Rdpnl.mTPVdy, Nday, Simdayend)
-		qmonsumNotOpe(Mon, Day, ttmm, Rdpnl.sd[0].PVwall.Power, &Rdpnl.mPVdy, Nday, Simdayend)
+		qmonsumNotOpe(Mon, Day, ttmm, Rdpnl.sd[0].PVwall.Power, &Rdpnl.mPVdy, Nday, Simdayend, sim.Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, control, Rdpnl.sd[0].PVwall.Power, &Rdpnl.mtPVdy[Mo][tt])
@@ -279,9 +273,9 @@ Rmdyprint (Room Daily Output)
 この関数は、室の熱的挙動とエネルギー消費量を日単位で詳細に分析し、
 快適性向上や省エネルギー対策の効果評価を行うための重要なデータ出力機能を提供します。
 */
-func Rmdyprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*ROOM) {
-	if __Rmdyprint_id == 0 && len(Rm) > 0 {
-		__Rmdyprint_id++
+func (sim *Simulation) Rmdyprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*ROOM) {
+	if sim.__Rmdyprint_id == 0 && len(Rm) > 0 {
+		sim.__Rmdyprint_id++
 
 		ttldyprint(fo, mrk, Simc)
 		fmt.Fprintf(fo, "-cat\n")
@@ -304,8 +298,8 @@ func Rmdyprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*ROO
 		fmt.Fprintf(fo, "*\n#\n")
 	}
 
-	if __Rmdyprint_id == 1 && len(Rm) > 0 {
-		__Rmdyprint_id++
+	if sim.__Rmdyprint_id == 1 && len(Rm) > 0 {
+		sim.__Rmdyprint_id++
 
 		for i := range Rm {
 			Room := Rm[i]
@@ -367,6 +361,7 @@ func Rmdyprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*ROO
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d\n", mon, day)
 
 	for i := range Rm {
@@ -449,12 +444,12 @@ Rmmonprint (Room Monthly Output)
 この関数は、室の熱的挙動とエネルギー消費量を月単位で詳細に分析し、
 快適性向上や省エネルギー対策の効果評価を行うための重要なデータ出力機能を提供します。
 */
-func Rmmonprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*ROOM) {
+func (sim *Simulation) Rmmonprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*ROOM) {
 
 	Nroom := len(Rm)
 
-	if __Rmmonprint_id == 0 && Nroom > 0 {
-		__Rmmonprint_id++
+	if sim.__Rmmonprint_id == 0 && Nroom > 0 {
+		sim.__Rmmonprint_id++
 
 		ttldyprint(fo, mrk, Simc)
 		fmt.Fprintf(fo, "-cat\n")
@@ -476,8 +471,8 @@ func Rmmonprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*RO
 		fmt.Fprintf(fo, "*\n#\n")
 	}
 
-	if __Rmmonprint_id == 1 && Nroom > 0 {
-		__Rmmonprint_id++
+	if sim.__Rmmonprint_id == 1 && Nroom > 0 {
+		sim.__Rmmonprint_id++
 
 		for i := 0; i < Nroom; i++ {
 			Room := Rm[i]
@@ -539,6 +534,7 @@ func Rmmonprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Rm []*RO
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d\n", mon, day)
 
 	for i := 0; i < Nroom; i++ {
@@ -819,7 +815,7 @@ func panelmonprt(fo io.Writer, id int, _Rdpnl []*RDPNL) {
 	}
 }
 
-func panelmtprt(fo io.Writer, id int, Rdpnl []*RDPNL, Mo int, tt int) {
+func panelmtprt(fo io.Writer, id int, Rdpnl []*RDPNL, Mo int, tt int, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(Rdpnl) > 0 {
@@ -36,7 +36,7 @@ func TestRoomday(t *testing.T) {
 			}
 		}()
 		
-		Roomday(Mon, Day, Nday, ttmm, rooms, rdpnls, Simdayend)
+		NewSimulation("", "").Roomday(Mon, Day, Nday, ttmm, rooms, rdpnls, Simdayend)
 		
 		// 実行後の基本確認（関数が正常終了したことを確認）
 		t.Logf("Roomday function completed without panic")
@@ -76,7 +76,7 @@ func TestRoomday(t *testing.T) {
 					}
 				}()
 				
-				Roomday(6, 15, 166, tt.ttmm, rooms, rdpnls, 0)
+				NewSimulation("", "").Roomday(6, 15, 166, tt.ttmm, rooms, rdpnls, 0)
 				t.Logf("Roomday executed at %s (%02d:%02d)", tt.desc, tt.hour, tt.minute)
 			})
 		}
@@ -114,7 +114,7 @@ func TestRoomday(t *testing.T) {
 					}
 				}()
 				
-				Roomday(season.mon, season.day, season.nday, 1200, rooms, rdpnls, 0)
+				NewSimulation("", "").Roomday(season.mon, season.day, season.nday, 1200, rooms, rdpnls, 0)
 				t.Logf("Roomday executed for %s (%d/%d)", season.desc, season.mon, season.day)
 			})
 		}
@@ -134,7 +134,7 @@ func TestRoomdayEdgeCases(t *testing.T) {
 			}
 		}()
 		
-		Roomday(6, 15, 166, 1200, rooms, rdpnls, 0)
+		NewSimulation("", "").Roomday(6, 15, 166, 1200, rooms, rdpnls, 0)
 		t.Logf("Roomday handled empty room list successfully")
 	})
 
@@ -155,7 +155,7 @@ func TestRoomdayEdgeCases(t *testing.T) {
 			}
 		}()
 		
-		Roomday(8, 20, 232, 1500, rooms, rdpnls, 0)
+		NewSimulation("", "").Roomday(8, 20, 232, 1500, rooms, rdpnls, 0)
 		t.Logf("Roomday handled single room successfully")
 	})
 
@@ -178,7 +178,7 @@ func TestRoomdayEdgeCases(t *testing.T) {
 			}
 		}()
 		
-		Roomday(9, 10, 253, 1000, rooms, rdpnls, 0)
+		NewSimulation("", "").Roomday(9, 10, 253, 1000, rooms, rdpnls, 0)
 		t.Logf("Roomday handled %d rooms successfully", len(rooms))
 	})
 
@@ -196,7 +196,7 @@ func TestRoomdayEdgeCases(t *testing.T) {
 			}
 		}()
 		
-		Roomday(2, 28, 59, 1200, rooms, rdpnls, 0)
+		NewSimulation("", "").Roomday(2, 28, 59, 1200, rooms, rdpnls, 0)
 		t.Logf("Roomday handled extreme temperature values successfully")
 	})
 
@@ -218,7 +218,7 @@ func TestRoomdayEdgeCases(t *testing.T) {
 		}()
 		
 		// 日終了フラグを立ててテスト
-		Roomday(12, 31, 365, 2359, rooms, rdpnls, 1)
+		NewSimulation("", "").Roomday(12, 31, 365, 2359, rooms, rdpnls, 1)
 		t.Logf("Roomday handled day end simulation successfully")
 	})
 }
@@ -253,7 +253,7 @@ func TestRoomdayBoundaryValues(t *testing.T) {
 					}
 				}()
 				
-				Roomday(6, 15, 166, bt.ttmm, rooms, rdpnls, 0)
+				NewSimulation("", "").Roomday(6, 15, 166, bt.ttmm, rooms, rdpnls, 0)
 				t.Logf("Roomday handled %s (%d) successfully", bt.desc, bt.ttmm)
 			})
 		}
@@ -289,7 +289,7 @@ func TestRoomdayBoundaryValues(t *testing.T) {
 					}
 				}()
 				
-				Roomday(bd.mon, bd.day, bd.nday, 1200, rooms, rdpnls, 0)
+				NewSimulation("", "").Roomday(bd.mon, bd.day, bd.nday, 1200, rooms, rdpnls, 0)
 				t.Logf("Roomday handled %s (%d/%d) successfully", bd.desc, bd.mon, bd.day)
 			})
 		}
@@ -313,7 +313,7 @@ func TestRoomdayIntegration(t *testing.T) {
 		}()
 		
 		// 複数室・複数パネルでのRoomday実行
-		Roomday(8, 15, 227, 1400, rooms, rdpnls, 0)
+		NewSimulation("", "").Roomday(8, 15, 227, 1400, rooms, rdpnls, 0)
 		
 		t.Logf("Multi-room multi-panel scenario completed: %d rooms, %d panels", 
 			len(rooms), len(rdpnls))
@@ -349,7 +349,7 @@ func TestRoomdayIntegration(t *testing.T) {
 				}
 			}(i, ttmm)
 			
-			Roomday(Mon, Day, Nday, ttmm, rooms, rdpnls, simdayend)
+			NewSimulation("", "").Roomday(Mon, Day, Nday, ttmm, rooms, rdpnls, simdayend)
 		}
 		
 		t.Logf("Complete daily cycle executed for room: %s", room.Name)
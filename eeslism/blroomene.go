@@ -199,7 +199,7 @@ PCMの相変化は非線形な熱的挙動を示すため、
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func PCMwlchk(counter int, Rmvls *RMVLS, Exsfs *EXSFS, Wd *WDAT, LDreset *int) {
+func PCMwlchk(counter int, Rmvls *RMVLS, Exsfs *EXSFS, Wd *WDAT, LDreset *int, DTM float64, debug bool, Ferr io.Writer) {
 	var Rmwlcreset int
 
 	Rmwlcreset = 0
@@ -214,7 +214,7 @@ func PCMwlchk(counter int, Rmvls *RMVLS, Exsfs *EXSFS, Wd *WDAT, LDreset *int) {
 	Rmsurftd(Rmvls.Room, Rmvls.Sd)
 
 	// 壁体内部温度の仮計算
-	RMwltd(Rmvls.Mw)
+	RMwltd(Rmvls.Mw, debug)
 
 	// PCM温度の収束判定
 	for i := range Rmvls.Room {
@@ -320,7 +320,7 @@ func PCMwlchk(counter int, Rmvls *RMVLS, Exsfs *EXSFS, Wd *WDAT, LDreset *int) {
 	}
 
 	if Rmwlcreset > 0 {
-		Roomcf(Rmvls.Mw, Rmvls.Room, Rmvls.Rdpnl, Wd, Exsfs)
+		Roomcf(Rmvls.Mw, Rmvls.Room, Rmvls.Rdpnl, Wd, Exsfs, DTM, Ferr)
 	}
 }
 
@@ -353,7 +353,7 @@ PCMの相変化は非線形な熱的挙動を示すため、
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func PCMfunchk(Room []*ROOM, Wd *WDAT, LDreset *int) {
+func PCMfunchk(Room []*ROOM, Wd *WDAT, LDreset *int, DTM float64) {
 	//var intI int
 	var tempTM float64
 
@@ -368,7 +368,7 @@ func PCMfunchk(Room []*ROOM, Wd *WDAT, LDreset *int) {
 				} else {
 					Room[intI].TM = (tempTM + Room[intI].TM) / 2.0
 				}
-				FunCoeff(Room[intI])
+				FunCoeff(Room[intI], DTM)
 
 				if Room[intI].FunHcap > 0.0 {
 					dblTemp := DTM / Room[intI].FunHcap
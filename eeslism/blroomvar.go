@@ -17,7 +17,10 @@
 
 package eeslism
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 Roomelm (Room Element Assignment)
@@ -155,7 +158,7 @@ Roomvar (Room System Equation Creation)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func Roomvar(_Room []*ROOM, _Rdpnl []*RDPNL) {
+func Roomvar(_Room []*ROOM, _Rdpnl []*RDPNL, Ferr io.Writer) {
 	for i := range _Room {
 		Room := _Room[i]
 
@@ -223,7 +226,7 @@ func Roomvar(_Room []*ROOM, _Rdpnl []*RDPNL) {
 
 		compnt := Rdpnl.cmp
 		G := compnt.Elouts[0].Lpath.G
-		cG := Spcheat(compnt.Elouts[0].Fluid) * G
+		cG := Spcheat(compnt.Elouts[0].Fluid, Ferr) * G
 		compnt.Elouts[0].Coeffo = cG
 		compnt.Elouts[0].Co = Rdpnl.EPC
 
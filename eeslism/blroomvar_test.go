@@ -75,7 +75,7 @@ func TestRoomvar(t *testing.T) {
 	_Rdpnl := []*RDPNL{}
 
 	// Execute
-	Roomvar(Room, _Rdpnl)
+	Roomvar(Room, _Rdpnl, nil)
 
 	// Verify
 	elout0 := Room[0].cmp.Elouts[0]
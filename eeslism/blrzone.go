@@ -24,7 +24,7 @@ import (
 
 /* ゾーン集計実施室の指定  */
 
-func Rzonedata(fi io.Reader, dsn string, Nroom int, Room []*ROOM, Nrzone *int, _Rzone []*RZONE) {
+func Rzonedata(fi io.Reader, dsn string, Nroom int, Room []*ROOM, Nrzone *int, _Rzone []*RZONE, Ferr io.Writer) {
 	scanner := bufio.NewScanner(fi)
 
 	for scanner.Scan() {
@@ -41,13 +41,13 @@ func Rzonedata(fi io.Reader, dsn string, Nroom int, Room []*ROOM, Nrzone *int, _
 		Rzone.Afloor = 0.0
 
 		for _, s := range fields[1:] {
-			if i, err := idroom(s, Room, ""); err != nil {
+			if i, err := idroom(s, Room, "", Ferr); err != nil {
 				Rm := Room[i]
 				Rzone.rm = append(Rzone.rm, Rm)
 				Rzone.Nroom++
 				Rzone.Afloor += Rm.FArea
 			} else {
-				Eprint(dsn, "<Rzinedata> room name")
+				Eprint(dsn, "<Rzinedata> room name", Ferr)
 			}
 		}
 
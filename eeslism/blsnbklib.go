@@ -56,9 +56,9 @@ FNFsdw (Function for Shaded Area Ratio of Window)
 日射熱取得の抑制、冷房負荷の軽減、
 昼光利用の最適化、および日影計算を行うための重要な役割を果たします。
 */
-func FNFsdw(Ksdw, Ksi int, Xazm, Xprf, D, Wr, Hr, Wi1, Hi1, Wi2, Hi2 float64) float64 {
+func FNFsdw(Ksdw, Ksi int, Xazm, Xprf, D, Wr, Hr, Wi1, Hi1, Wi2, Hi2 float64, debug bool) float64 {
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("----- FNFsdw  Ksdw=%d Ksi=%d Xazm=%f Xprf=%f D=%f Wr=%f Hr=%f Wi1=%f Hi1=%f Wi2=%f Hi2=%f\n",
 			Ksdw, Ksi, Xazm, Xprf, D, Wr, Hr, Wi1, Hi1, Wi2, Hi2)
 	}
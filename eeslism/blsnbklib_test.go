@@ -147,7 +147,7 @@ func TestFNFsdw(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			fsdw := FNFsdw(tt.typ, tt.ksi, tt.tazm, tt.tprof, tt.d, tt.w, tt.h, tt.w1, tt.h1, tt.w2, tt.h2)
+			fsdw := FNFsdw(tt.typ, tt.ksi, tt.tazm, tt.tprof, tt.d, tt.w, tt.h, tt.w1, tt.h1, tt.w2, tt.h2, false)
 
 			// Verify shadow factor is in valid range [0, 1]
 			if fsdw < 0.0 || fsdw > 1.0 {
@@ -325,7 +325,7 @@ func TestSunshadeEffectiveness(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Calculate shadow factor
 			fsdw := FNFsdw(tt.sunshade.Type, tt.sunshade.Ksi, tt.solarAzm, tt.solarProf,
-				tt.sunshade.D, tt.sunshade.W, tt.sunshade.H, 0.0, 0.0, 0.0, 0.0)
+				tt.sunshade.D, tt.sunshade.W, tt.sunshade.H, 0.0, 0.0, 0.0, 0.0, false)
 
 			// Evaluate effectiveness
 			var effectiveness string
@@ -378,7 +378,7 @@ func TestSunshadeTypes(t *testing.T) {
 			}
 
 			// Test shadow calculation for each type
-			fsdw := FNFsdw(snbk.Type, 0, 0.0, 45.0, snbk.D, snbk.W, snbk.H, 0.0, 0.0, 0.0, 0.0)
+			fsdw := FNFsdw(snbk.Type, 0, 0.0, 45.0, snbk.D, snbk.W, snbk.H, 0.0, 0.0, 0.0, 0.0, false)
 
 			if fsdw < 0.0 || fsdw > 1.0 {
 				t.Errorf("Shadow factor (%f) outside valid range for type %d", fsdw, tt.typ)
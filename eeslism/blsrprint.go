@@ -24,11 +24,9 @@ import (
 	"io"
 )
 
-var __Pmvprint_count = 0
-
-func Pmvprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time float64) {
+func (sim *Simulation) Pmvprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time float64) {
 	var Nr int
-	if __Pmvprint_count == 0 && Room != nil {
+	if sim.__Pmvprint_count == 0 && Room != nil {
 		for i := range Room {
 			Rm := Room[i]
 			if Rm.Metsch != nil {
@@ -48,7 +46,7 @@ func Pmvprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time fl
 
 		fmt.Fprintf(fpout, "\n")
 
-		__Pmvprint_count = 1
+		sim.__Pmvprint_count = 1
 	}
 
 	fmt.Fprintf(fpout, "%02d %02d %5.2f ", Mon, Day, time)
@@ -67,10 +65,8 @@ func Pmvprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time fl
 /*   室内温・湿度、室内表面平均温度の出力
  */
 
-var __Rmevprint_count = 0
-
-func Rmevprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time float64) {
-	if __Rmevprint_count == 0 {
+func (sim *Simulation) Rmevprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time float64) {
+	if sim.__Rmevprint_count == 0 {
 		fmt.Fprintf(fpout, "%s ;\n", title)
 		fmt.Fprintf(fpout, "%d室\t\t\t", len(Room))
 
@@ -80,7 +76,7 @@ func Rmevprint(fpout io.Writer, title string, Room []*ROOM, Mon, Day int, time f
 		}
 		fmt.Fprintf(fpout, "\n")
 
-		__Rmevprint_count = 1
+		sim.__Rmevprint_count = 1
 	}
 	/*======================================= */
 	fmt.Fprintf(fpout, "%d\t%d\t%.2f\t", Mon, Day, time)
@@ -21,10 +21,8 @@ import "math"
 
 /*   作用温度制御時の設定室内空気温度  */
 
-var __Rmotset_Pint int
-
-func Rmotset(_Room []*ROOM) {
-	Fotinit(_Room)
+func (sim *Simulation) Rmotset(_Room []*ROOM) {
+	sim.Fotinit(_Room)
 
 	for i := range _Room {
 		Room := _Room[i]
@@ -53,9 +51,9 @@ func Rmotset(_Room []*ROOM) {
 					rmpnl := Room.rmpnl[j]
 
 					var Twi float64
-					if __Rmotset_Pint == 0 {
+					if sim.__Rmotset_Pint == 0 {
 						Twi = rmpnl.sd.mw.Tw[rmpnl.sd.mw.mp]
-						__Rmotset_Pint = 1
+						sim.__Rmotset_Pint = 1
 					} else {
 						Twi = rmpnl.pnl.Tpi
 					}
@@ -80,10 +78,8 @@ func Rmotset(_Room []*ROOM) {
 
 /* -------------------------------------- */
 
-var __Fotinit_init int = 'i'
-
-func Fotinit(_Room []*ROOM) {
-	if __Fotinit_init == 'i' {
+func (sim *Simulation) Fotinit(_Room []*ROOM) {
+	if sim.__Fotinit_init == 'i' {
 		for i := range _Room {
 			Room := _Room[i]
 			if Room.rmld != nil {
@@ -94,7 +90,7 @@ func Fotinit(_Room []*ROOM) {
 				Room.rmld.FOPL = make([]float64, Room.Nrp)
 			}
 		}
-		__Fotinit_init = 'x'
+		sim.__Fotinit_init = 'x'
 	}
 }
 
@@ -8,7 +8,7 @@ import (
 
 func TestFotinit(t *testing.T) {
 	// Setup
-	__Fotinit_init = 'i'
+	sim := NewSimulation("", "")
 	Room := []*ROOM{
 		{
 			rmld: &RMLOAD{},
@@ -18,7 +18,7 @@ func TestFotinit(t *testing.T) {
 	}
 
 	// Execute
-	Fotinit(Room)
+	sim.Fotinit(Room)
 
 	// Verify
 	if len(Room[0].rmld.FOTN) != 2 {
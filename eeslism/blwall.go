@@ -17,6 +17,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"os"
 	"strconv"
@@ -57,7 +58,7 @@ Walli (Wall Initialization)
 熱負荷計算、エネルギー消費量予測、
 省エネルギー対策の検討、および快適性評価を行うための重要な初期設定機能を提供します。
 */
-func Walli(Nbm int, W []BMLST, Wl *WALL, pcm []*PCM) {
+func Walli(Nbm int, W []BMLST, Wl *WALL, pcm []*PCM, Ferr io.Writer) {
 	// int     i, j, k, m, N, M;
 	// double  Rwall, *C, *Rw, CAPwall;
 	var BM *BMLST
@@ -133,8 +134,8 @@ func Walli(Nbm int, W []BMLST, Wl *WALL, pcm []*PCM) {
 
 		if k == Nbm {
 			E := fmt.Sprintf("Material not found: %s", Welm.Code)
-			Eprint("<Walli>", E)
-			os.Exit(EXIT_WBMLST)
+			Eprint("<Walli>", E, Ferr)
+			panic(&InputError{Section: "WALL", Keyword: Welm.Code, Msg: "material not found", Code: EXIT_WBMLST})
 		}
 
 		// 熱伝導率、容積比熱のコピー
@@ -245,7 +246,7 @@ func Wallfdc(M int, mp int, res []float64, cap []float64,
 	Wp float64, UX []float64,
 	uo *float64, um *float64, Pc *float64, WallType WALLType,
 	Sd *RMSRF, Wd *WDAT,
-	Exsf *EXSFS, Wall *WALL, Told []float64, Twd []float64, _pcmstate []*PCMSTATE) {
+	Exsf *EXSFS, Wall *WALL, Told []float64, Twd []float64, _pcmstate []*PCMSTATE, DTM float64, Ferr io.Writer) {
 	var PCMf = 0
 	// double	Croa;				// 見かけの比熱
 	var ToldPCMave, ToldPCMNodeL, ToldPCMNodeR float64
@@ -526,7 +527,7 @@ func Wallfdc(M int, mp int, res []float64, cap []float64,
 		/***********/
 	}
 
-	Matinv(UX, M, M, "<Wallfdc>")
+	Matinv(UX, M, M, "<Wallfdc>", Ferr)
 
 	/*********************/
 	if PCMf == 5 {
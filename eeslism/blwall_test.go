@@ -29,7 +29,7 @@ func TestWalli(t *testing.T) {
 	var pcm []*PCM
 
 	// Execute
-	Walli(len(materials), materials, wall, pcm)
+	Walli(len(materials), materials, wall, pcm, nil)
 
 	// Verify basic calculations
 	if wall.Rwall <= 0.0 {
@@ -84,7 +84,7 @@ func TestWalli_WithPCM(t *testing.T) {
 	}
 
 	// Execute
-	Walli(len(materials), materials, wall, pcm)
+	Walli(len(materials), materials, wall, pcm, nil)
 
 	// Verify PCM flag is set
 	if !wall.PCMflg {
@@ -99,11 +99,6 @@ func TestWalli_WithPCM(t *testing.T) {
 }
 
 func TestWallfdc(t *testing.T) {
-	// Set global DTM for time step (seconds)
-	oldDTM := DTM
-	DTM = 3600.0 // 1 hour
-	defer func() { DTM = oldDTM }()
-
 	t.Run("Basic wall without PCM", func(t *testing.T) {
 		M := 3 // 3 internal nodes
 		mp := 1
@@ -143,7 +138,7 @@ func TestWallfdc(t *testing.T) {
 		}
 
 		// Execute
-		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate)
+		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate, 3600.0, nil)
 
 		// Verify outputs
 		if uo <= 0 {
@@ -198,7 +193,7 @@ func TestWallfdc(t *testing.T) {
 			Twd[i] = 20.0
 		}
 
-		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate)
+		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate, 3600.0, nil)
 
 		// For panel wall, Pc should be positive
 		if Pc <= 0 {
@@ -254,7 +249,7 @@ func TestWallfdc(t *testing.T) {
 		Told := []float64{18.0, 20.0, 22.0, 24.0}
 		Twd := []float64{19.0, 21.0, 23.0, 25.0}
 
-		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate)
+		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate, 3600.0, nil)
 
 		if uo <= 0 {
 			t.Errorf("uo should be positive for PCM wall, got %f", uo)
@@ -306,7 +301,7 @@ func TestWallfdc(t *testing.T) {
 			Twd[i] = 20.0
 		}
 
-		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate)
+		Wallfdc(M, mp, res, cap, Wp, UX, &uo, &um, &Pc, wall.WallType, nil, nil, nil, wall, Told, Twd, pcmstate, 3600.0, nil)
 
 		if uo <= 0 {
 			t.Errorf("uo should be positive for 5-layer wall, got %f", uo)
@@ -2,6 +2,7 @@ package eeslism
 
 import (
 	"io"
+	"io/fs"
 )
 
 // 壁体の材料定義
@@ -261,6 +262,7 @@ func NewWelm() *WELM {
 type CHARTABLE struct {
 	filename             string        // テーブル形式ファイルのファイル名
 	fp                   io.ReadCloser // `filename`の読み込みファイルポインタ
+	fsys                 fs.FS         // `filename`の読み込み元。nil の場合はファイルシステム
 	PCMchar              rune          // E:エンタルピー、C:熱伝導率
 	T                    []float64     // PCM温度[℃]
 	Chara                []float64     // 特性値（エンタルピー、熱伝導率）
@@ -0,0 +1,894 @@
+/*
+builder.go (Model Builder)
+
+入力データファイルを文字列として組み立てる代わりに、
+Go の型を用いてモデル（建物、スケジュール、機器、経路、制御）を構築する API を定義します。
+
+Model は各データセットに対応するフィールドを持ちます。
+
+  - GDAT: Weather, Out, Warmup, Start, End, Tinit, DTime, PrintStart, PrintEnd, Print, Calendar
+  - EXSRF: GroundReflectance, Alo, ExternalSurfaces
+  - WALL, WINDOW: Walls, Windows
+  - %s, %sn（スケジュール）: DaySchedules, Seasons, Weekdays, Schedules
+  - ROOM（RMSRF を含む）: Rooms
+  - EQPCAT, SYSCMP, SYSPTH, CONTL: Catalog, Components, Paths, Controls
+
+構築したモデルは入力データファイルの書式に変換され、テキストの入力データファイルと同じパーサー
+（Eesprera、Eespre、Eeinput）で読み込まれます。したがって、入力データの検証はテキストの場合と同一です。
+このほか Check で、名前の書式、重複、未定義の壁体・窓・外表面・室・機器カタログの参照を
+パーサーより先に検出します。
+出力の際は、区切り文字（空白、`;`、`!`）を含む値が別のトークンとして読み込まれないよう、全てのトークンを検査します。
+
+型を用意していないキーワードは各要素の Params で、
+型を用意していないデータセット（APPL、VENT など）は Sections で指定します。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Model は入力データファイルに相当するモデルです。NewModel で作成します。
+type Model struct {
+	Title string // 表題 (TITLE)
+
+	// GDAT
+	Weather    string       // 気象データファイル名 (FILE w=)
+	Out        string       // 計算結果出力ファイルセット名 (FILE out=)。空の場合は入力データファイル名
+	Warmup     MonthDay     // 助走計算開始日。ゼロ値の場合は助走計算を行わない
+	Start, End MonthDay     // 計算開始日、計算終了日
+	Tinit      float64      // 初期温度 [℃]
+	DTime      int          // 計算時間間隔 [s]
+	PrintStart MonthDay     // 毎時計算結果の出力開始日。ゼロ値の場合は出力しない
+	PrintEnd   MonthDay     // 毎時計算結果の出力終了日。ゼロ値の場合は PrintStart の1日のみ
+	Print      PrintOptions // 出力指定
+	Calendar   *Calendar    // 暦年と祝日 (CALENDAR)。nil の場合は dayweek.efl と WEEK による
+
+	// EXSRF
+	GroundReflectance float64 // 全面地物の日射反射率 (r=)
+	Alo               string  // 外表面総合熱伝達率の既定値 (alo=)。数値、スケジュール名または Calc。空の場合は省略
+	ExternalSurfaces  []*ExternalSurface
+
+	Wbmlist string // 壁体の材料定義リストファイル名 (WALL wbmlist=)。空の場合は wbmlist.efl
+	Walls   []*Wall
+	Windows []*Window
+
+	// スケジュール
+	DaySchedules []*DaySchedule // 1日の設定値、切換スケジュール (%s -v, %s -s)
+	Seasons      []*Season      // 季節設定 (%s -ssn)
+	Weekdays     []*Weekdays    // 曜日設定 (%s -wkd)
+	Schedules    []*Schedule    // 季節、曜日によるスケジュールの組み合わせ (%sn)
+
+	Rooms      []*Room
+	Sections   []*Section // 型を用意していないデータセット。ROOM の後に出力する
+	Catalog    []*Equipment
+	Components []*Component
+	Paths      []*Path
+	Controls   []*Control
+}
+
+// NewModel は表題 title のモデルを作成します。
+// 初期温度、計算時間間隔はパーサーの既定値（15℃、3600秒）で初期化されます。
+func NewModel(title string) *Model {
+	return &Model{
+		Title: title,
+		Tinit: 15.0,
+		DTime: 3600,
+	}
+}
+
+// MonthDay は月日です。
+type MonthDay struct {
+	Month, Day int
+}
+
+// String は `mm/dd` 形式の文字列を返します。
+func (d MonthDay) String() string {
+	return fmt.Sprintf("%d/%d", d.Month, d.Day)
+}
+
+// PrintOptions は GDAT PRINT の出力指定です。
+type PrintOptions struct {
+	Weather bool // 気象データ (*wd)
+	Rev     bool // 室内熱環境データ (*rev)
+	Helm    bool // 熱負荷要素 (*helm)
+	PMV     bool // 室内の PMV (*pmv)
+	Log     bool // 処理経過のログファイル (*log)
+	Debug   bool // 計算の経過 (*debug)
+}
+
+// Calendar は GDAT CALENDAR の暦年と休日です。ref: jpholiday.go
+type Calendar struct {
+	Year      int        // 暦年 (year=)
+	JPHoliday bool       // 日本の国民の祝日を休日とする (-jpholiday)
+	Holidays  []MonthDay // 会社の休業日など、追加の休日
+}
+
+// Params は型を用意していない `キーワード=値` 形式のパラメータです。キーワードの昇順に出力します。
+type Params map[string]string
+
+// ExternalSurface は外表面 (EXSRF) です。
+// 名前が Hor の場合は水平面、EarthSf の場合は地表面境界になります。
+type ExternalSurface struct {
+	Name    string
+	Azimuth float64 // 方位角 [°] (a=)。南を0とし、西回りを正とする
+	Params  Params  // t=（傾斜角）、alo=、Z=、d=、r= など
+}
+
+// Wall は壁体 (WALL) です。
+type Wall struct {
+	Ble    BLEType // 部位コード（E、R、F、i、c、f）。0 の場合は部位を指定しない
+	Name   string  // 壁体名。空の場合は部位の既定の壁体
+	Layers []Layer // 層構成（室内側から。屋根、天井は外表面側から）
+	Params Params  // Ei=、Eo=、as= など
+}
+
+// Layer は壁体の層です。
+type Layer struct {
+	Material  string  // 材料コード（wbmlist.efl）
+	Thickness float64 // 厚さ [mm]。0 の場合は中空層など材料コードのみを指定する
+	Div       int     // 層内の分割数。0 の場合は厚さから決める
+}
+
+// String は `RC-150`、`RC-150/3` 形式の文字列を返します。
+func (l Layer) String() string {
+	switch {
+	case l.Thickness == 0:
+		return l.Material
+	case l.Div > 0:
+		return fmt.Sprintf("%s-%s/%d", l.Material, modelFloat(l.Thickness), l.Div)
+	default:
+		return l.Material + "-" + modelFloat(l.Thickness)
+	}
+}
+
+// Window は窓 (WINDOW) です。T、B が 0 の場合は既定値を用います。
+type Window struct {
+	Name   string
+	T      float64 // 日射透過率 (t=)
+	B      float64 // 吸収日射取得率 (B=)
+	R      float64 // 熱抵抗 [m2K/W] (R=)
+	Params Params  // Ei=、Eo=、sunbrk= など
+}
+
+// DaySchedule は1日の設定値スケジュール、または切換スケジュールです。
+// 季節、曜日によらず毎日同じスケジュールとしても参照できます。
+type DaySchedule struct {
+	Name    string
+	Switch  bool // 切換スケジュール (-s)。false の場合は設定値スケジュール (-v)
+	Periods []SchedulePeriod
+}
+
+// SchedulePeriod はスケジュールの時間帯です。
+type SchedulePeriod struct {
+	Start, End int           // 開始時分、終了時分（hhmm）
+	Value      float64       // 設定値（設定値スケジュール）
+	Mode       ControlSWType // モード（切換スケジュール）
+}
+
+// Season は季節設定です。
+type Season struct {
+	Name    string
+	Periods [][2]MonthDay // 開始日、終了日の組
+}
+
+// Weekdays は曜日設定です。
+type Weekdays struct {
+	Name string
+	Days []string // 曜日（Mon、Tue、Wed、Thu、Fri、Sat、Sun、Hol）
+}
+
+// Schedule は季節、曜日による1日のスケジュールの組み合わせです。
+type Schedule struct {
+	Name    string
+	Switch  bool // 切換スケジュール (-s)。false の場合は設定値スケジュール (-v)
+	Entries []ScheduleEntry
+
+	// CSV の時系列によるスケジュール (csv=、col=)。CSV を指定する場合は Entries を用いない。ref: eschcsv.go
+	CSV    string // CSV ファイル名
+	Column string // 列名または 1 から始まる列番号
+}
+
+// ScheduleEntry は Season、Weekdays の日に用いる1日のスケジュールです。
+// Season、Weekdays が空の場合は全ての季節、曜日に用います。
+type ScheduleEntry struct {
+	Day      string // 1日のスケジュール名
+	Season   string // 季節設定名
+	Weekdays string // 曜日設定名
+}
+
+// Room は室 (ROOM) です。
+type Room struct {
+	Name     string
+	Vol      float64  // 室容積 [m3]
+	Params   Params   // alc=、Hcap=、fsolm= など
+	Flags    []string // *s、*q、*sfe、rsrnx など
+	Surfaces []*Surface
+}
+
+// Surface は室の部位 (RMSRF) です。
+type Surface struct {
+	Exsrf    string   // 外表面名。空の場合は直前の部位と同じ外表面
+	Ble      BLEType  // 部位コード（E、R、F、i、c、f、W）
+	Wall     string   // 壁体名または窓名。空の場合は部位の既定の壁体
+	Area     float64  // 面積 [m2]
+	NextRoom string   // 隣室名 (r=)
+	Params   Params   // fsol=、alc=、c=、i=、sb= など
+	Flags    []string // *p、*sfe、*shd
+}
+
+// Equipment は機器カタログ (EQPCAT) です。
+type Equipment struct {
+	Type    string   // 機器種別（BOI、REFA、PUMP など）
+	Name    string   // カタログ名
+	Params  Params   // Qo=、eff= など
+	Options []string // `キーワード=値` 以外の指定
+}
+
+// Component はシステム要素 (SYSCMP) です。
+type Component struct {
+	Name    string
+	Catalog string   // 機器カタログ名 (-c)
+	Room    string   // 設置室名 (-room)
+	Env     string   // 周辺温度 (-env)
+	Options []string // -L、-Tinit、-Nin など
+}
+
+// Path はシステム経路 (SYSPTH) です。
+type Path struct {
+	Name     string
+	Sys      string // システム分類 (-sys)
+	Fluid    string // 流体種別 (-f)
+	Branches []*Branch
+}
+
+// Branch は末端経路です。
+type Branch struct {
+	Name     string   // 末端経路名 (name=)。空の場合は省略
+	Flow     string   // 流量 [kg/s]。数値またはスケジュール名。空の場合は省略
+	Elements []string // 経路上の機器、室（`AirHandler[S]` のように出入口を指定できる）
+}
+
+// Control は制御 (CONTL) です。
+type Control struct {
+	If   string    // 条件式。空の場合は常に有効
+	Load string    // 負荷計算を行う機器名 (LOAD -e)。空の場合は省略
+	Set  []Setting // 設定
+}
+
+// Setting は制御の設定 `Name=Value` です。
+type Setting struct {
+	Name, Value string
+}
+
+// Section は型を用意していないデータセットです。
+type Section struct {
+	Name  string   // データセット名（APPL、VENT など）
+	Lines []string // 論理行（; で終わる）
+}
+
+// modelFloat は数値を入力データファイルの書式で返します。
+func modelFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// modelWriter は入力データファイルの書式で出力します。最初のエラーを保持します。
+type modelWriter struct {
+	w       io.Writer
+	n       int64
+	err     error
+	section string // 出力中のデータセット名（エラーの報告に用いる）
+}
+
+func (w *modelWriter) printf(format string, a ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, a...)
+	w.n += int64(n)
+	w.err = err
+}
+
+// begin はデータセット name の開始行を出力します。
+func (w *modelWriter) begin(name string) {
+	if w.err == nil {
+		w.err = checkToken(name, name)
+	}
+	w.section = name
+	w.printf("%s\n", name)
+}
+
+// words は tokens を1行に出力し、end で終えます。
+// 区切り文字を含むトークンは別のトークンとして読み込まれるため、出力せずに *InputError を保持します。
+func (w *modelWriter) words(indent, end string, tokens ...string) {
+	for _, tok := range tokens {
+		if w.err == nil {
+			w.err = checkWord(w.section, tok)
+		}
+	}
+	w.printf("%s%s%s\n", indent, strings.Join(tokens, " "), end)
+}
+
+// line は論理行を1行で出力します。
+func (w *modelWriter) line(indent string, tokens ...string) {
+	w.words(indent, " ;", tokens...)
+}
+
+// rawLine は Section の行 l を出力します。
+// `;` は行末にのみ置くことができ、`*` のみの行は ROOM、COORDNT のまとまりの終わりにのみ用いることができます。
+func (w *modelWriter) rawLine(l string) {
+	indent := "\t" + l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+	tokens := strings.Fields(l)
+	end := ""
+	if n := len(tokens); n > 0 && strings.HasSuffix(tokens[n-1], ";") {
+		tokens[n-1] = strings.TrimSuffix(tokens[n-1], ";")
+		if tokens[n-1] == "" {
+			tokens = tokens[:n-1]
+		}
+		end = " ;"
+	}
+	if len(tokens) == 1 && tokens[0] == "*" && end == "" {
+		if w.err == nil && !slices.Contains(blockSections, w.section) {
+			w.err = &InputError{Section: w.section, Keyword: l, Msg: "`*` ends the data set"}
+		}
+		w.printf("%s*\n", indent)
+		return
+	}
+	w.words(indent, end, tokens...)
+}
+
+// tokens は Params をキーワードの昇順で `キーワード=値` の列に変換します。
+func (p Params) tokens() []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tokens := make([]string, len(keys))
+	for i, k := range keys {
+		tokens[i] = k + "=" + p[k]
+	}
+	return tokens
+}
+
+// WriteTo はモデルを入力データファイルの書式で w に出力します。
+// 名前、Params の値、Section の行などが区切り文字を含み、1つのトークンとして読み込めない場合は
+// その要素以降を出力せずに *InputError を返します。
+func (m *Model) WriteTo(w io.Writer) (int64, error) {
+	mw := &modelWriter{w: w}
+
+	// 表題は空白を含むことができる
+	mw.begin("TITLE")
+	if mw.err == nil && strings.ContainsAny(m.Title, ";!\r\n") {
+		mw.err = &InputError{Section: "TITLE", Keyword: m.Title, Msg: "invalid character in title"}
+	}
+	mw.printf("\t%s ;\n\n", m.Title)
+
+	// GDAT
+	mw.begin("GDAT")
+	var file []string
+	if m.Weather != "" {
+		file = append(file, "w="+m.Weather)
+	}
+	if m.Out != "" {
+		file = append(file, "out="+m.Out)
+	}
+	if len(file) > 0 {
+		mw.line("\t", append([]string{"FILE"}, file...)...)
+	}
+	run := []string{"RUN"}
+	if m.Warmup != (MonthDay{}) {
+		run = append(run, "("+m.Warmup.String()+")")
+	}
+	run = append(run, m.Start.String()+"-"+m.End.String(),
+		"Tinit="+modelFloat(m.Tinit), "dTime="+strconv.Itoa(m.DTime))
+	mw.line("\t", run...)
+	if m.PrintStart != (MonthDay{}) {
+		print := []string{"PRINT", m.PrintStart.String()}
+		if m.PrintEnd != (MonthDay{}) {
+			print[1] += "-" + m.PrintEnd.String()
+		}
+		for _, opt := range []struct {
+			on  bool
+			key string
+		}{
+			{m.Print.Weather, "*wd"},
+			{m.Print.Rev, "*rev"},
+			{m.Print.Helm, "*helm"},
+			{m.Print.PMV, "*pmv"},
+			{m.Print.Log, "*log"},
+			{m.Print.Debug, "*debug"},
+		} {
+			if opt.on {
+				print = append(print, opt.key)
+			}
+		}
+		mw.line("\t", print...)
+	}
+	if c := m.Calendar; c != nil {
+		calendar := []string{"CALENDAR", "year=" + strconv.Itoa(c.Year)}
+		if c.JPHoliday {
+			calendar = append(calendar, "-jpholiday")
+		}
+		for _, d := range c.Holidays {
+			calendar = append(calendar, d.String())
+		}
+		mw.line("\t", calendar...)
+	}
+	mw.printf("*\n\n")
+
+	// スケジュール
+	mw.section = "SCHTB"
+	for _, d := range m.DaySchedules {
+		tokens := []string{"%s", "-v", d.Name}
+		if d.Switch {
+			tokens[1] = "-s"
+		}
+		for _, p := range d.Periods {
+			if d.Switch {
+				tokens = append(tokens, fmt.Sprintf("%04d-(%c)-%04d", p.Start, p.Mode, p.End))
+			} else {
+				tokens = append(tokens, fmt.Sprintf("%04d-(%s)-%04d", p.Start, modelFloat(p.Value), p.End))
+			}
+		}
+		mw.line("", tokens...)
+	}
+	for _, s := range m.Seasons {
+		tokens := []string{"%s", "-ssn", s.Name}
+		for _, p := range s.Periods {
+			tokens = append(tokens, p[0].String()+"-"+p[1].String())
+		}
+		mw.line("", tokens...)
+	}
+	for _, wk := range m.Weekdays {
+		mw.line("", append([]string{"%s", "-wkd", wk.Name}, wk.Days...)...)
+	}
+	mw.section = "SCHNM"
+	for _, s := range m.Schedules {
+		tokens := []string{"%sn", "-v", s.Name}
+		if s.Switch {
+			tokens[1] = "-s"
+		}
+		if s.CSV != "" {
+			tokens = append(tokens, "csv="+s.CSV, "col="+s.Column)
+		}
+		for _, e := range s.Entries {
+			token := e.Day
+			if e.Season != "" || e.Weekdays != "" {
+				token += ":" + e.Season
+			}
+			if e.Weekdays != "" {
+				token += "-" + e.Weekdays
+			}
+			tokens = append(tokens, token)
+		}
+		mw.line("", tokens...)
+	}
+	if len(m.DaySchedules)+len(m.Seasons)+len(m.Weekdays)+len(m.Schedules) > 0 {
+		mw.printf("\n")
+	}
+
+	// EXSRF
+	mw.begin("EXSRF")
+	exsrf := []string{"r=" + modelFloat(m.GroundReflectance)}
+	if m.Alo != "" {
+		exsrf = append(exsrf, "alo="+m.Alo)
+	}
+	mw.line("\t", exsrf...)
+	for _, e := range m.ExternalSurfaces {
+		tokens := append([]string{e.Name, "a=" + modelFloat(e.Azimuth)}, e.Params.tokens()...)
+		mw.line("\t", tokens...)
+	}
+	mw.printf("*\n\n")
+
+	// WALL
+	if len(m.Walls) > 0 {
+		mw.begin("WALL")
+		if m.Wbmlist != "" {
+			mw.line("\t", "wbmlist="+m.Wbmlist)
+		}
+		for _, wl := range m.Walls {
+			name := wl.Name
+			if wl.Ble != 0 {
+				name = "-" + string(rune(wl.Ble))
+				if wl.Name != "" {
+					name += ":" + wl.Name
+				}
+			}
+			tokens := append([]string{name}, wl.Params.tokens()...)
+			for _, l := range wl.Layers {
+				tokens = append(tokens, l.String())
+			}
+			mw.line("\t", tokens...)
+		}
+		mw.printf("*\n\n")
+	}
+
+	// WINDOW
+	if len(m.Windows) > 0 {
+		mw.begin("WINDOW")
+		for _, win := range m.Windows {
+			tokens := []string{win.Name}
+			if win.T != 0 {
+				tokens = append(tokens, "t="+modelFloat(win.T))
+			}
+			if win.B != 0 {
+				tokens = append(tokens, "B="+modelFloat(win.B))
+			}
+			tokens = append(tokens, "R="+modelFloat(win.R))
+			mw.line("\t", append(tokens, win.Params.tokens()...)...)
+		}
+		mw.printf("*\n\n")
+	}
+
+	// ROOM
+	if len(m.Rooms) > 0 {
+		mw.begin("ROOM")
+		for _, rm := range m.Rooms {
+			// 室の指定は最初の部位と同じ論理行に記述する
+			header := []string{rm.Name, "Vol=" + modelFloat(rm.Vol)}
+			header = append(header, rm.Params.tokens()...)
+			header = append(header, rm.Flags...)
+			mw.words("\t", "", header...)
+			named := false // 部位要素名を出力したか
+			for _, sd := range rm.Surfaces {
+				var tokens []string
+				if sd.Exsrf != "" {
+					tokens = append(tokens, sd.Exsrf+":")
+					named = true
+				} else if !named && sd.NextRoom != "" {
+					// 部位要素名より前の `キーワード=値` は室への設定になるため、隣室名を部位要素名とする
+					tokens = append(tokens, "("+sd.NextRoom+"):")
+					named = true
+				}
+				tokens = append(tokens, "-"+string(rune(sd.Ble)))
+				if sd.Wall != "" {
+					tokens = append(tokens, sd.Wall)
+				}
+				tokens = append(tokens, modelFloat(sd.Area))
+				if sd.NextRoom != "" {
+					tokens = append(tokens, "r="+sd.NextRoom)
+				}
+				tokens = append(tokens, sd.Params.tokens()...)
+				tokens = append(tokens, sd.Flags...)
+				mw.line("\t\t", tokens...)
+			}
+			mw.printf("\t*\n")
+		}
+		mw.printf("*\n\n")
+	}
+
+	for _, s := range m.Sections {
+		mw.begin(s.Name)
+		for _, l := range s.Lines {
+			mw.rawLine(l)
+		}
+		mw.printf("*\n\n")
+	}
+
+	// EQPCAT
+	if len(m.Catalog) > 0 {
+		mw.begin("EQPCAT")
+		for _, e := range m.Catalog {
+			tokens := append([]string{e.Type, e.Name}, e.Params.tokens()...)
+			mw.line("\t", append(tokens, e.Options...)...)
+		}
+		mw.printf("*\n\n")
+	}
+
+	// SYSCMP
+	if len(m.Components) > 0 {
+		mw.begin("SYSCMP")
+		for _, c := range m.Components {
+			tokens := []string{c.Name}
+			if c.Catalog != "" {
+				tokens = append(tokens, "-c", c.Catalog)
+			}
+			if c.Room != "" {
+				tokens = append(tokens, "-room", c.Room)
+			}
+			if c.Env != "" {
+				tokens = append(tokens, "-env", c.Env)
+			}
+			mw.line("\t", append(tokens, c.Options...)...)
+		}
+		mw.printf("*\n\n")
+	}
+
+	// SYSPTH
+	if len(m.Paths) > 0 {
+		mw.begin("SYSPTH")
+		for _, p := range m.Paths {
+			mw.words("\t", "", p.Name, "-sys", p.Sys, "-f", p.Fluid)
+			for i, b := range p.Branches {
+				tokens := []string{">"}
+				if b.Name != "" {
+					tokens = append(tokens, "name="+b.Name)
+				}
+				if b.Flow != "" {
+					tokens = append(tokens, "("+b.Flow+")")
+				}
+				tokens = append(tokens, b.Elements...)
+				tokens = append(tokens, ">")
+				if i == len(p.Branches)-1 {
+					mw.line("\t\t", tokens...)
+				} else {
+					mw.words("\t\t", "", tokens...)
+				}
+			}
+		}
+		mw.printf("*\n\n")
+	}
+
+	// CONTL
+	if len(m.Controls) > 0 {
+		mw.begin("CONTL")
+		for _, c := range m.Controls {
+			var tokens []string
+			if c.If != "" {
+				// 条件式は空白で区切られた複数のトークンからなる
+				tokens = append(tokens, "if")
+				tokens = append(tokens, strings.Fields("("+c.If+")")...)
+			}
+			if c.Load != "" {
+				tokens = append(tokens, "LOAD", "-e", c.Load)
+			}
+			for _, s := range c.Set {
+				tokens = append(tokens, s.Name+"="+s.Value)
+			}
+			mw.line("\t", tokens...)
+		}
+		mw.printf("*\n\n")
+	}
+
+	mw.printf("*\n")
+	return mw.n, mw.err
+}
+
+// Check はモデルの名前の書式、重複、参照を検査します。
+// 誤りがある場合は、最初に見つかった誤りを *InputError で返します。
+// 数値の範囲などの検証は、Simulation の Init でパーサーが行います。
+func (m *Model) Check() error {
+	seen := make(map[string]map[string]bool)
+	define := func(section, name string) error {
+		if err := checkToken(section, name); err != nil {
+			return err
+		}
+		if seen[section] == nil {
+			seen[section] = make(map[string]bool)
+		}
+		if seen[section][name] {
+			return &InputError{Section: section, Component: name, Msg: "duplicate name"}
+		}
+		seen[section][name] = true
+		return nil
+	}
+	refer := func(section, kind, name, from string) error {
+		if !seen[kind][name] {
+			return &InputError{Section: section, Keyword: name, Component: from, Msg: "undefined in " + kind}
+		}
+		return nil
+	}
+
+	if strings.ContainsAny(m.Title, ";!\n") {
+		return &InputError{Section: "TITLE", Keyword: m.Title, Msg: "invalid character in title"}
+	}
+	for _, name := range []string{m.Weather, m.Out} {
+		if name != "" {
+			if err := checkToken("GDAT", name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c := m.Calendar; c != nil {
+		if c.Year < 1 {
+			return &InputError{Section: "GDAT", Keyword: "CALENDAR", Msg: "year is required"}
+		}
+		if c.JPHoliday && (c.Year < JPHolidayFirstYear || c.Year > JPHolidayLastYear) {
+			return &InputError{Section: "GDAT", Keyword: "CALENDAR", Component: strconv.Itoa(c.Year),
+				Msg: fmt.Sprintf("Japanese holidays are available for %d-%d", JPHolidayFirstYear, JPHolidayLastYear)}
+		}
+	}
+
+	seen["EXSRF"] = make(map[string]bool)
+	for _, e := range m.ExternalSurfaces {
+		if err := define("EXSRF", e.Name); err != nil {
+			return err
+		}
+	}
+	if strings.ContainsAny(m.Wbmlist, " \t\r\n;!") {
+		return &InputError{Section: "WALL", Keyword: m.Wbmlist, Msg: "invalid character in wbmlist file name"}
+	}
+	walls := make(map[BLEType]map[string]bool)
+	for _, wl := range m.Walls {
+		if wl.Name == "" {
+			continue
+		}
+		if err := checkToken("WALL", wl.Name); err != nil {
+			return err
+		}
+		if walls[wl.Ble] == nil {
+			walls[wl.Ble] = make(map[string]bool)
+		}
+		if walls[wl.Ble][wl.Name] {
+			return &InputError{Section: "WALL", Component: wl.Name, Msg: "duplicate name"}
+		}
+		walls[wl.Ble][wl.Name] = true
+	}
+	seen["WINDOW"] = make(map[string]bool)
+	for _, win := range m.Windows {
+		if err := define("WINDOW", win.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range m.DaySchedules {
+		if err := define("SCHTB", d.Name); err != nil {
+			return err
+		}
+	}
+	for _, s := range m.Seasons {
+		if err := define("SSN", s.Name); err != nil {
+			return err
+		}
+	}
+	for _, wk := range m.Weekdays {
+		if err := define("WKD", wk.Name); err != nil {
+			return err
+		}
+		for _, d := range wk.Days {
+			if !slices.Contains(DAYweek[:], d) {
+				return &InputError{Section: "WKD", Keyword: d, Component: wk.Name, Msg: "invalid day of week"}
+			}
+		}
+	}
+	for _, s := range m.Schedules {
+		if err := define("SCHNM", s.Name); err != nil {
+			return err
+		}
+		if s.CSV != "" && (s.Column == "" || len(s.Entries) > 0) {
+			return &InputError{Section: "SCHNM", Keyword: "csv=" + s.CSV, Component: s.Name, Msg: "csv= requires col= and no day schedules"}
+		}
+		for _, e := range s.Entries {
+			if err := refer("SCHNM", "SCHTB", e.Day, s.Name); err != nil {
+				return err
+			}
+			if e.Season != "" {
+				if err := refer("SCHNM", "SSN", e.Season, s.Name); err != nil {
+					return err
+				}
+			}
+			if e.Weekdays != "" {
+				if err := refer("SCHNM", "WKD", e.Weekdays, s.Name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	seen["ROOM"] = make(map[string]bool)
+	for _, rm := range m.Rooms {
+		if err := define("ROOM", rm.Name); err != nil {
+			return err
+		}
+	}
+	for _, rm := range m.Rooms {
+		if len(rm.Surfaces) == 0 {
+			return &InputError{Section: "ROOM", Component: rm.Name, Msg: "no surfaces"}
+		}
+		for _, sd := range rm.Surfaces {
+			if sd.Exsrf != "" {
+				if err := refer("ROOM", "EXSRF", sd.Exsrf, rm.Name); err != nil {
+					return err
+				}
+			}
+			if sd.NextRoom != "" {
+				if err := refer("ROOM", "ROOM", sd.NextRoom, rm.Name); err != nil {
+					return err
+				}
+			}
+			switch {
+			case sd.Ble == BLE_Window:
+				if err := refer("ROOM", "WINDOW", sd.Wall, rm.Name); err != nil {
+					return err
+				}
+			case sd.Wall != "":
+				// 床と天井は相互に参照できる
+				found := walls[sd.Ble][sd.Wall] ||
+					(sd.Ble == BLE_Ceil && walls[BLE_InnerFloor][sd.Wall]) ||
+					(sd.Ble == BLE_InnerFloor && walls[BLE_Ceil][sd.Wall])
+				if !found {
+					return &InputError{Section: "ROOM", Keyword: sd.Wall, Component: rm.Name, Msg: "undefined in WALL"}
+				}
+			}
+		}
+	}
+
+	for _, e := range m.Catalog {
+		if err := define("EQPCAT", e.Name); err != nil {
+			return err
+		}
+	}
+	for _, c := range m.Components {
+		if err := define("SYSCMP", c.Name); err != nil {
+			return err
+		}
+		if c.Catalog != "" {
+			if err := refer("SYSCMP", "EQPCAT", c.Catalog, c.Name); err != nil {
+				return err
+			}
+		}
+		if c.Room != "" {
+			if err := refer("SYSCMP", "ROOM", c.Room, c.Name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, p := range m.Paths {
+		if err := define("SYSPTH", p.Name); err != nil {
+			return err
+		}
+		if len(p.Branches) == 0 {
+			return &InputError{Section: "SYSPTH", Component: p.Name, Msg: "no branches"}
+		}
+	}
+	return nil
+}
+
+// checkToken は name が入力データファイルの1つのトークンとして読み込めるかどうかを検査します。
+func checkToken(section, name string) error {
+	if name == "" {
+		return &InputError{Section: section, Msg: "empty name"}
+	}
+	if strings.ContainsAny(name, " \t\r\n;!*:=") {
+		return &InputError{Section: section, Keyword: name, Msg: "invalid character in name"}
+	}
+	return nil
+}
+
+// checkWord は tok が入力データファイルの1つのトークンとして読み込めるかどうかを検査します。
+// 空白、`;`、コメントの `!`（演算子 `!=` を除く）を含むトークンと、`*` のみのトークンは読み込めません。
+func checkWord(section, tok string) error {
+	if tok == "" {
+		return &InputError{Section: section, Msg: "empty token"}
+	}
+	if tok == "*" || strings.ContainsAny(strings.ReplaceAll(tok, "!=", ""), " \t\r\n;!") {
+		return &InputError{Section: section, Keyword: tok, Msg: "invalid character in token"}
+	}
+	return nil
+}
+
+// Simulation はモデルを入力データファイル name とするシミュレーションを作成します。
+// name はファイルシステムには書き出さず、出力ファイル名の決定にのみ用います。
+// PCM の物性値テーブルなど、モデルから参照するその他のファイルはファイルシステムから読み込みます。
+func (m *Model) Simulation(name, efl_path string) (*Simulation, error) {
+	if err := m.Check(); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if _, err := m.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	sim := NewSimulation(name, efl_path)
+	sim.FS = overlayFS{files: map[string][]byte{name: b.Bytes()}, base: osFS{}}
+	return sim, nil
+}
+
+// Validate はモデルを検査し、パーサーで読み込みます。
+// 出力はメモリ上に書き出して破棄します。誤りがある場合は Init と同じエラーを返します。
+func (m *Model) Validate(efl_path string) error {
+	sim, err := m.Simulation("model.txt", efl_path)
+	if err != nil {
+		return err
+	}
+	sim.Output = new(MemorySink)
+	return sim.Init()
+}
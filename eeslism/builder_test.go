@@ -0,0 +1,238 @@
+package eeslism
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// scheduleTestModel は simple_room_schedule_test.txt と同じモデルを構築する
+func scheduleTestModel() *Model {
+	m := NewModel("L1-04 Schedule Control Test")
+	m.Weather = "tokyo_3column_SI.has"
+	m.Warmup = MonthDay{1, 1}
+	m.Start, m.End = MonthDay{1, 1}, MonthDay{1, 7}
+	m.PrintStart, m.PrintEnd = MonthDay{1, 1}, MonthDay{1, 7}
+	m.Print.Weather = true
+
+	m.GroundReflectance = 0.2
+	m.ExternalSurfaces = []*ExternalSurface{
+		{Name: "south", Azimuth: 0},
+		{Name: "north", Azimuth: 180},
+		{Name: "east", Azimuth: 90},
+		{Name: "west", Azimuth: 270},
+		{Name: "Hor"},
+		{Name: "earth", Params: Params{"Z": "1.5"}},
+	}
+	m.Walls = []*Wall{
+		{Ble: BLE_ExternalWall, Name: "ExtWall", Layers: []Layer{{"RC", 150, 0}, {"FPS", 50, 0}, {"GPB", 12, 0}}},
+		{Ble: BLE_Roof, Name: "Roof", Layers: []Layer{{"FPS", 100, 0}, {"GPB", 12, 0}}},
+		{Ble: BLE_Floor, Name: "Floor", Layers: []Layer{{"GPB", 12, 0}, {"FPS", 100, 0}, {"RC", 150, 0}}},
+	}
+	m.Windows = []*Window{{Name: "SouthWindow", T: 0.65, B: 0.15, R: 0.50}}
+	m.Rooms = []*Room{{
+		Name:   "TestRoom",
+		Vol:    100.0,
+		Params: Params{"alc": "4.6"},
+		Flags:  []string{"*s"},
+		Surfaces: []*Surface{
+			{Exsrf: "south", Ble: BLE_ExternalWall, Area: 15.5},
+			{Ble: BLE_Window, Wall: "SouthWindow", Area: 4.5},
+			{Exsrf: "north", Ble: BLE_ExternalWall, Area: 20.0},
+			{Exsrf: "east", Ble: BLE_ExternalWall, Area: 12.5},
+			{Exsrf: "west", Ble: BLE_ExternalWall, Area: 12.5},
+			{Exsrf: "Hor", Ble: BLE_Roof, Area: 40.0},
+			{Exsrf: "earth", Ble: BLE_Floor, Area: 40.0},
+		},
+	}}
+
+	m.DaySchedules = []*DaySchedule{{
+		Name:    "HeatingSchedule",
+		Switch:  true,
+		Periods: []SchedulePeriod{{Start: 600, End: 2200, Mode: ON_SW}},
+	}}
+	m.Catalog = []*Equipment{{Type: "BOI", Name: "testboi", Params: Params{"Qo": "5000", "eff": "0.85"}}}
+	m.Components = []*Component{{Name: "Boiler1", Catalog: "testboi"}}
+	m.Paths = []*Path{{
+		Name: "HeatPath", Sys: "A", Fluid: "W",
+		Branches: []*Branch{{Flow: "0.01", Elements: []string{"Boiler1"}}},
+	}}
+	m.Controls = []*Control{
+		{Set: []Setting{{"HeatPath", "HeatingSchedule"}}},
+		{Load: "Boiler1", Set: []Setting{{"Boiler1_Tout", "45"}}},
+	}
+	return m
+}
+
+// TestModel_Simulation は Go で構築したモデルの計算結果が、
+// 同じ内容の入力データファイルの計算結果と一致することを確認する
+func TestModel_Simulation(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := NewSimulation(copySimulationInput(t, src, dir), eflPath).Run(); err != nil {
+		t.Fatal(err)
+	}
+	ref := readSimulationOutputs(t, dir)
+	if len(ref) == 0 {
+		t.Fatal("No output files generated")
+	}
+
+	sim, err := scheduleTestModel().Simulation("model/room.txt", eflPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(MemorySink)
+	sim.Output = out
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range ref {
+		outName := "model/room" + strings.TrimPrefix(name, "simple_room_schedule_test")
+		want = strings.ReplaceAll(want, "<dir>\\simple_room_schedule_test", "model\\room")
+		if got := string(out.Bytes(outName)); got != want {
+			t.Errorf("%s differs from the input data file run", outName)
+		}
+	}
+}
+
+// TestModel_WriteTo は入力データファイルの書式への変換を確認する
+func TestModel_WriteTo(t *testing.T) {
+	var b bytes.Buffer
+	if _, err := scheduleTestModel().WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\tRUN (1/1) 1/1-1/7 Tinit=15 dTime=3600 ;\n",
+		"\tPRINT 1/1-1/7 *wd ;\n",
+		"%s -s HeatingSchedule 0600-(-)-2200 ;\n",
+		"\t-E:ExtWall RC-150 FPS-50 GPB-12 ;\n",
+		"\tTestRoom Vol=100 alc=4.6 *s\n\t\tsouth: -E 15.5 ;\n",
+		"\t\t-W SouthWindow 4.5 ;\n",
+		"\tHeatPath -sys A -f W\n\t\t> (0.01) Boiler1 > ;\n",
+		"\tLOAD -e Boiler1 Boiler1_Tout=45 ;\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, b.String())
+		}
+	}
+}
+
+// TestModel_Check は名前の誤りと未定義の参照を検出することを確認する
+func TestModel_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(m *Model)
+		section string
+		keyword string
+	}{
+		{"undefined wall", func(m *Model) { m.Rooms[0].Surfaces[0].Wall = "NoSuchWall" }, "ROOM", "NoSuchWall"},
+		{"wall of other ble", func(m *Model) { m.Rooms[0].Surfaces[0].Wall = "Roof" }, "ROOM", "Roof"},
+		{"undefined window", func(m *Model) { m.Rooms[0].Surfaces[1].Wall = "NoSuchWindow" }, "ROOM", "NoSuchWindow"},
+		{"undefined external surface", func(m *Model) { m.Rooms[0].Surfaces[0].Exsrf = "sky" }, "ROOM", "sky"},
+		{"undefined catalog", func(m *Model) { m.Components[0].Catalog = "noboi" }, "SYSCMP", "noboi"},
+		{"invalid name", func(m *Model) { m.Rooms[0].Name = "Test Room" }, "ROOM", "Test Room"},
+		{"duplicate room", func(m *Model) { m.Rooms = append(m.Rooms, m.Rooms[0]) }, "ROOM", ""},
+		{"invalid day of week", func(m *Model) {
+			m.Weekdays = []*Weekdays{{Name: "Weekday", Days: []string{"Monday"}}}
+		}, "WKD", "Monday"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := scheduleTestModel()
+			tt.modify(m)
+			err := m.Check()
+			var ie *InputError
+			if !errors.As(err, &ie) {
+				t.Fatalf("err = %v, want *InputError", err)
+			}
+			if ie.Section != tt.section || ie.Keyword != tt.keyword {
+				t.Errorf("Section, Keyword = %q, %q, want %q, %q", ie.Section, ie.Keyword, tt.section, tt.keyword)
+			}
+		})
+	}
+
+	if err := scheduleTestModel().Check(); err != nil {
+		t.Errorf("Check() = %v", err)
+	}
+}
+
+// TestModel_WriteTo_Separator は区切り文字を含む値を出力せず、エラーとすることを確認する
+func TestModel_WriteTo_Separator(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(m *Model)
+		section string
+		keyword string
+	}{
+		{"params value with ;", func(m *Model) { m.Rooms[0].Params["alc"] = "4.6 ; *" }, "ROOM", "alc=4.6 ; *"},
+		{"params value with space", func(m *Model) { m.Catalog[0].Params["Qo"] = "5000 Qo=1" }, "EQPCAT", "Qo=5000 Qo=1"},
+		{"params value with newline", func(m *Model) {
+			m.ExternalSurfaces[0].Params = Params{"t": "90\n*\nROOM"}
+		}, "EXSRF", "t=90\n*\nROOM"},
+		{"flag with comment", func(m *Model) { m.Rooms[0].Flags = []string{"*s!"} }, "ROOM", "*s!"},
+		{"option", func(m *Model) { m.Components[0].Options = []string{"*"} }, "SYSCMP", "*"},
+		{"control", func(m *Model) { m.Controls[0].If = "a > 1) ; (b" }, "CONTL", ";"},
+		{"section line with ;", func(m *Model) {
+			m.Sections = []*Section{{Name: "VENT", Lines: []string{"TestRoom Vent=0.5 ; Inf=1 ;"}}}
+		}, "VENT", ";"},
+		{"section line with *", func(m *Model) {
+			m.Sections = []*Section{{Name: "VENT", Lines: []string{"TestRoom Vent=0.5 ;", "*", "SYSCMP"}}}
+		}, "VENT", "*"},
+		{"section name", func(m *Model) { m.Sections = []*Section{{Name: "VENT ;"}} }, "VENT ;", "VENT ;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := scheduleTestModel()
+			tt.modify(m)
+			var b bytes.Buffer
+			_, err := m.WriteTo(&b)
+			var ie *InputError
+			if !errors.As(err, &ie) {
+				t.Fatalf("err = %v, want *InputError", err)
+			}
+			if ie.Section != tt.section || ie.Keyword != tt.keyword {
+				t.Errorf("Section, Keyword = %q, %q, want %q, %q", ie.Section, ie.Keyword, tt.section, tt.keyword)
+			}
+			if strings.HasSuffix(b.String(), "*\n") {
+				t.Errorf("output is not stopped at the error:\n%s", b.String())
+			}
+			if _, err := m.Simulation("model.txt", ""); !errors.As(err, &ie) {
+				t.Errorf("Simulation: err = %v, want *InputError", err)
+			}
+		})
+	}
+
+	// `*` のみの行は COORDNT のまとまりの終わりとして出力できる
+	m := scheduleTestModel()
+	m.Sections = []*Section{{Name: "COORDNT", Lines: []string{"TestRoom -xyz 0 0 0 ;", "*"}}}
+	var b bytes.Buffer
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := "COORDNT\n\tTestRoom -xyz 0 0 0 ;\n\t*\n*\n"; !strings.Contains(b.String(), want) {
+		t.Errorf("output does not contain %q:\n%s", want, b.String())
+	}
+}
+
+// TestModel_Validate はパーサーが検出する誤りを Validate が返すことを確認する
+func TestModel_Validate(t *testing.T) {
+	if err := scheduleTestModel().Validate(""); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	m := scheduleTestModel()
+	m.GroundReflectance = 1.5
+	err := m.Validate("")
+	var ie *InputError
+	if !errors.As(err, &ie) || ie.Section != "EXSRF" {
+		t.Errorf("err = %v, want *InputError in EXSRF", err)
+	}
+}
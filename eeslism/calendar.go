@@ -0,0 +1,177 @@
+/*
+calendar.go (Actual-Calendar Simulation)
+
+GDAT RUN の計算期間に年を指定すると、実暦による計算となります。うるう年の 2/29 を含む実際の日付で、
+複数年にわたる計算ができます。
+
+	RUN (2018/12/25) 2019/1/1-2021/12/31 Tinit=15 dTime=3600 ;
+
+計算の方法は次のとおりです。
+
+  - 通日 nday は助走計算開始日の年（基準年 Simc.BaseYear）の 1/1 を 1 とする実暦の日数です。
+    助走計算開始日を月日のみで指定した場合は、計算開始日以前の直近の日付とします。
+  - 各日の曜日と祝日はその年の暦によります。GDAT CALENDAR の year= は用いません。
+    CALENDAR がない場合は、dayweek.efl、WEEK の祝日（固定の月日）のみを用います。
+  - 気象データ、スケジュール、PRINT の出力日などの 365 日の標準年による値は、その月日の値を用います。
+    2/29 は 2/28 の値を用います（曜日は 2/29 の曜日）。
+    実測気象データの年月日を用いるのは GDAT WCSV と、年の時刻の項目 (Y) を持つ VCFILE です。
+  - 日集計、月集計は年月日で区切ります。うるう年の 2 月の月集計は 2/29 までとします。
+  - 計算結果の日集計、月集計と時刻の項目 (-tmid) を持つファイルの各行には年を出力します（-tmid YMD、YMDT）。
+  - 周期定常計算 (-periodic) とは併用できません。
+*/
+package eeslism
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RUN の年を含む日付 (y/m/d)
+var runDateRe = regexp.MustCompile(`(\d+)/(\d+)/(\d+)`)
+
+// runMonthDay は GDAT RUN の日付 s の年を除いた月日を返します。例: `2019/1/1-2021/12/31` → `1/1-12/31`
+func runMonthDay(s string) string {
+	return runDateRe.ReplaceAllString(s, "$2/$3")
+}
+
+// gdataRunCalendar は GDAT RUN の論理行 fields から、実暦による計算の助走計算開始日、計算開始日、計算終了日を返します。
+// 計算期間に年を指定しない場合は ok = false となります。
+func gdataRunCalendar(fields []string) (startx, start, end time.Time, ok bool) {
+	fail := func(s, msg string) {
+		panic(&InputError{Section: "GDAT", Keyword: "RUN", Component: s, Msg: msg})
+	}
+	date := func(s string) (t time.Time, year bool) {
+		v := strings.Split(s, "/")
+		n := make([]int, len(v))
+		for i := range v {
+			var err error
+			if n[i], err = strconv.Atoi(v[i]); err != nil {
+				fail(s, "invalid date")
+			}
+		}
+		switch len(n) {
+		case 2:
+			// 月日のみ。年は呼び出し側で決める（2/29 を受け付けるうるう年とする）
+			n = append([]int{2000}, n...)
+		case 3:
+			year = true
+		default:
+			fail(s, "invalid date")
+		}
+		t = time.Date(n[0], time.Month(n[1]), n[2], 0, 0, 0, 0, time.UTC)
+		if n[0] < 1 || t.Month() != time.Month(n[1]) || t.Day() != n[2] {
+			fail(s, "invalid date")
+		}
+		return t, year
+	}
+
+	var warmup, period, periodic string
+	for i := 1; i < len(fields); i++ {
+		s := fields[i]
+		switch {
+		case strings.ContainsRune(s, '='), s == ";":
+		case s == "-periodic":
+			if i+1 < len(fields) {
+				periodic = fields[i+1]
+				i++
+			}
+		case strings.HasPrefix(s, "("):
+			warmup = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+		case strings.ContainsRune(s, '-'):
+			period = s
+		}
+	}
+	if periodic != "" && (runDateRe.MatchString(periodic) || runDateRe.MatchString(period)) {
+		fail(periodic, "-periodic cannot be used with the year of the period")
+	}
+	if !runDateRe.MatchString(period) {
+		if runDateRe.MatchString(warmup) {
+			fail(warmup, "the year of the period is required")
+		}
+		return startx, start, end, false
+	}
+
+	se := strings.SplitN(period, "-", 2)
+	var ys, ye bool
+	start, ys = date(se[0])
+	end, ye = date(se[1])
+	if !ys || !ye {
+		fail(period, "the year is required for both the start and end dates")
+	}
+	if end.Before(start) {
+		fail(period, "the end date is before the start date")
+	}
+
+	startx = start
+	if warmup != "" {
+		var yx bool
+		startx, yx = date(warmup)
+		if !yx {
+			// 計算開始日以前の直近の日付
+			m, d := startx.Month(), startx.Day()
+			for y := start.Year(); ; y-- {
+				if t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC); t.Month() == m && !t.After(start) {
+					startx = t
+					break
+				}
+			}
+		}
+		if startx.After(start) {
+			fail(warmup, "the warm-up date is after the start date")
+		}
+	}
+	return startx, start, end, true
+}
+
+// calendarNday は実暦による計算の日付 t の通日（基準年 BaseYear の 1/1 を 1 とする）を返します。
+func (Simc *SIMCONTL) calendarNday(t time.Time) int {
+	base := time.Date(Simc.BaseYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return int(t.Sub(base).Hours()/24) + 1
+}
+
+// Date は実暦による計算の通日 nday の年月日を返します。
+func (Simc *SIMCONTL) Date(nday int) (year, mon, day int) {
+	t := time.Date(Simc.BaseYear, time.January, nday, 0, 0, 0, 0, time.UTC)
+	return t.Year(), int(t.Month()), t.Day()
+}
+
+// dateKey は実暦による計算の通日 nday の年月日を yyyymmdd の整数とします。日集計、月集計の日の区切りに用います。
+func (Simc *SIMCONTL) dateKey(nday int) int {
+	y, m, d := Simc.Date(nday)
+	return y*10000 + m*100 + d
+}
+
+// standardDay は月日の 365 日の標準年の通日を返します。2/29 は 2/28 の通日とします。
+func standardDay(mon, day int) int {
+	if mon == 2 && day == 29 {
+		day = 28
+	}
+	return FNNday(mon, day)
+}
+
+// setDaywk は実暦による計算で、年 year の曜日と休日を Daywk に設定します。
+// leap が true の場合は 2/28 の通日の曜日を 2/29 の曜日とします。
+func (Simc *SIMCONTL) setDaywk(year int, leap bool) {
+	calendarDayweek(Simc.Calendar, year, Simc.Daywk)
+	if leap {
+		wd := time.Date(year, time.February, 29, 0, 0, 0, 0, time.UTC).Weekday()
+		Simc.Daywk[standardDay(2, 29)] = (int(wd) + 6) % 7
+	}
+}
+
+// dayweekCalendar は dayweek.efl、WEEK による曜日 daywk の祝日（7）を、実暦による計算に用いる
+// GDAT CALENDAR の論理行（追加の休日）とします。
+func dayweekCalendar(daywk []int) []string {
+	fields := []string{"CALENDAR"}
+	for M := 1; M <= 12; M++ {
+		for D := 1; D <= monthDays[M-1]; D++ {
+			if daywk[FNNday(M, D)] == 7 {
+				fields = append(fields, fmt.Sprintf("%d/%d", M, D))
+			}
+		}
+	}
+	return fields
+}
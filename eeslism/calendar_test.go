@@ -0,0 +1,212 @@
+package eeslism
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGdataRunCalendar は GDAT RUN の年を含む計算期間、助走計算開始日と誤りを確認する
+func TestGdataRunCalendar(t *testing.T) {
+	if s := runMonthDay("(2018/12/25)"); s != "(12/25)" {
+		t.Errorf("runMonthDay = %s", s)
+	}
+	if s := runMonthDay("2019/1/1-2021/12/31"); s != "1/1-12/31" {
+		t.Errorf("runMonthDay = %s", s)
+	}
+
+	date := func(y, m, d int) time.Time { return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC) }
+	for _, tt := range []struct {
+		line               string
+		startx, start, end time.Time
+	}{
+		{"RUN (2018/12/25) 2019/1/1-2021/12/31 Tinit=15 ;", date(2018, 12, 25), date(2019, 1, 1), date(2021, 12, 31)},
+		{"RUN 2020/2/29-2020/2/29 ;", date(2020, 2, 29), date(2020, 2, 29), date(2020, 2, 29)},
+		// 月日のみの助走計算開始日は計算開始日以前の直近の日付
+		{"RUN (12/25) 2020/1/1-2020/1/5 ;", date(2019, 12, 25), date(2020, 1, 1), date(2020, 1, 5)},
+		{"RUN (2/1) 2020/3/1-2020/3/5 ;", date(2020, 2, 1), date(2020, 3, 1), date(2020, 3, 5)},
+		{"RUN (2/29) 2022/3/1-2022/3/5 ;", date(2020, 2, 29), date(2022, 3, 1), date(2022, 3, 5)},
+	} {
+		startx, start, end, ok := gdataRunCalendar(strings.Fields(tt.line))
+		if !ok || !startx.Equal(tt.startx) || !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%s: %v %v %v %v", tt.line, startx, start, end, ok)
+		}
+	}
+	if _, _, _, ok := gdataRunCalendar(strings.Fields("RUN (1/1) 1/1-12/31 -periodic 1/1 ;")); ok {
+		t.Error("RUN without the year: expected ok = false")
+	}
+
+	for _, line := range []string{
+		"RUN (2019/1/1) 1/1-1/5 ;",
+		"RUN 2019/1/1-12/31 ;",
+		"RUN 2020/3/1-2020/2/1 ;",
+		"RUN 2019/2/29-2019/3/1 ;",
+		"RUN (2020/3/2) 2020/3/1-2020/3/5 ;",
+		"RUN 2019/1/1-2019/1/1 -periodic 1/1 ;",
+	} {
+		func() {
+			defer func() {
+				if _, ok := recover().(*InputError); !ok {
+					t.Errorf("%s: expected InputError", line)
+				}
+			}()
+			gdataRunCalendar(strings.Fields(line))
+		}()
+	}
+}
+
+// TestIsEndDayCalendar は実暦による計算の月末（うるう年の 2/29）と計算終了日を確認する
+func TestIsEndDayCalendar(t *testing.T) {
+	for _, tt := range []struct {
+		mon, day, dayend, simDayend int
+		want                        bool
+	}{
+		{2, 28, 20200228, 20211231, false},
+		{2, 29, 20200229, 20211231, true},
+		{2, 28, 20210228, 20211231, true},
+		{12, 31, 20201231, 20211231, true},
+		{5, 10, 20210510, 20210510, true},
+		{5, 10, 20200510, 20210510, false},
+	} {
+		if got := IsEndDay(tt.mon, tt.day, tt.dayend, tt.simDayend); got != tt.want {
+			t.Errorf("IsEndDay(%d, %d, %d, %d) = %v, want %v", tt.mon, tt.day, tt.dayend, tt.simDayend, got, tt.want)
+		}
+	}
+}
+
+// TestSimulation_Calendar は年末年始とうるう年の 2/29 を含む実暦による計算の日付、曜日、
+// スケジュール、実測気象データ (WCSV) と年を含む日集計、月集計を確認する
+func TestSimulation_Calendar(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2019/12/25 1:00 から 2020/3/3 0:00 までの1時間値。気温は日 + 時/100
+	var b strings.Builder
+	b.WriteString("Time,Temp,x,Zero,Cloud,Wind\n")
+	t0 := time.Date(2019, 12, 25, 0, 0, 0, 0, time.UTC)
+	for tm := t0.Add(time.Hour); !tm.After(time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC)); tm = tm.Add(time.Hour) {
+		d := tm.Add(-time.Hour)
+		fmt.Fprintf(&b, "%s,%g,0.005,0,5,1\n", tm.Format("2006/1/2 15:04"), float64(d.Day())+float64(d.Hour()+1)/100)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "site.csv"), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := strings.NewReplacer(
+		"FILE w=tokyo_3column_SI.has ;", "WCSV file=site.csv time=Time Lat=35.69 Lon=139.76 Ls=135 T=Temp x=x Idn=Zero Isky=Zero CC=Cloud Wv=Wind ;\n\tCALENDAR -jpholiday ;",
+		"RUN (1/1) 1/1-1/7 ;", "RUN (12/25) 2019/12/30-2020/3/2 ;",
+		"PRINT 1/1-1/7 *wd ;", "PRINT 1/1-1/7 *wd ;\n*\n\nSCHTB\n\t%s -wkd Weekday Mon Tue Wed Thu Fri ;\n\t%s -wkd Weekend Sat Sun Hol ;",
+		"HeatPath=HeatingSchedule ;", "HeatPath=HeatingSchedule ;\n\t%s -v Vwd 001-(1)-2400 ;\n\t%s -v Vwe 001-(2)-2400 ;\n\t%sn -v Occ Vwd:-Weekday Vwe:-Weekend ;",
+	).Replace(string(in))
+	input := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(input, []byte(s), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sim := NewSimulation(input, eflPath)
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if sim.Simc.BaseYear != 2019 || sim.Simc.Daystartx != 359 || sim.Simc.Daystart != 364 || sim.Simc.Dayend != 365+31+29+2 {
+		t.Fatalf("BaseYear %d Daystartx %d Daystart %d Dayend %d", sim.Simc.BaseYear, sim.Simc.Daystartx, sim.Simc.Daystart, sim.Simc.Dayend)
+	}
+	occ, err := idsch("Occ", sim.Schdl.Sch, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var days []string
+	for !sim.Done() {
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+		// 日の最後の時間ステップの後は翌日の日付となる
+		dt := sim.Daytm
+		if dt.Tt == 24 {
+			continue
+		}
+		if want := float64(dt.Day) + float64(dt.Tt)/100; math.Abs(sim.Wd.T-want) > 1e-9 {
+			t.Fatalf("%d/%d/%d %d: T = %g, want %g", dt.Year, dt.Mon, dt.Day, dt.Tt, sim.Wd.T, want)
+		}
+		if dt.Tt != 12 {
+			continue
+		}
+		days = append(days, fmt.Sprintf("%d/%d/%d", dt.Year, dt.Mon, dt.Day))
+
+		// 曜日（0: 月曜日、5: 土曜日、6: 日曜日、7: 祝日）と曜日によるスケジュール
+		want, ok := map[string]int{
+			"2019/12/29": 6,
+			"2020/1/1":   7,
+			"2020/1/6":   0,
+			"2020/2/24":  7, // 振替休日
+			"2020/2/28":  4,
+			"2020/2/29":  5,
+			"2020/3/2":   0,
+		}[days[len(days)-1]]
+		if !ok {
+			continue
+		}
+		if got := sim.Simc.Daywk[dt.DayOfYear]; got != want {
+			t.Errorf("%s: Daywk = %d, want %d", days[len(days)-1], got, want)
+		}
+		val := 1.0
+		if want >= 5 {
+			val = 2
+		}
+		if sim.Schdl.Val[occ] != val {
+			t.Errorf("%s: Occ = %g, want %g", days[len(days)-1], sim.Schdl.Val[occ], val)
+		}
+	}
+	if err := sim.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(days); n != 69 || days[0] != "2019/12/25" || days[66] != "2020/2/29" || days[n-1] != "2020/3/2" {
+		t.Errorf("days: %d %s ... %s", n, days[0], days[n-1])
+	}
+
+	// 日集計、月集計の年。うるう年の 2 月は 2/29 まで
+	ofname := strings.TrimSuffix(input, ".txt")
+	for _, tt := range []struct {
+		file string
+		rows []string
+	}{
+		{"_dwd.es", []string{"Yr\tMo\tNd\t", "2019\t12\t30\t", "2020\t1\t1\t", "2020\t2\t29\t", "2020\t3\t2\t"}},
+		{"_mwd.es", []string{"Yr\tMo\tNd\t", "2019\t12\t31\t", "2020\t1\t31\t", "2020\t2\t29\t", "2020\t3\t2\t"}},
+	} {
+		out, err := os.ReadFile(ofname + tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range tt.rows {
+			if !strings.Contains(string(out), "\n"+row) {
+				t.Errorf("%s: no row %q\n%s", tt.file, row, out)
+			}
+		}
+	}
+
+	// -tmid を持つ日別の出力
+	f, err := os.Open(ofname + "_dc.es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dc, err := ReadOutputTable(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(dc.Year); dc.Timeid != "YMD" || n != 64 || dc.Year[0] != 2019 || dc.Year[n-1] != 2020 || dc.Mon[n-3] != 2 || dc.Day[n-3] != 29 {
+		t.Errorf("_dc.es: -tmid %s, %d rows, Year %v", dc.Timeid, n, dc.Year)
+	}
+}
@@ -0,0 +1,1153 @@
+/*
+check.go (Input Checker)
+
+入力データファイルを計算せずに検査し、誤りと警告を入力データファイルの行・列とともに全て報告します。
+
+各データセットのパーサー（Roomdata、Compodata、Pathdata、Contrldata など）は、
+Eesprera、Eespre で注釈文を除き論理行を組み替えた後のトークンを読むため、誤りの位置（行）が分かりません。
+また、最初の誤りで panic するか、Eprint で表示して読み飛ばすため、全ての誤りを一度に知ることができません。
+Check は ParseInputDocument で元の入力データファイルのトークンの位置を記録し、
+名前の定義と参照を次のように検査します。
+
+  - ROOM: 壁体（WALL）、窓（WINDOW）、外表面（EXSRF）、日除け（SUNBRK）、隣室、PCM、スケジュールの参照
+  - RAICH、VENT、RESI、APPL: 室名とスケジュール（設定値名）の参照
+  - SCHNM: 1日の設定値、季節、曜日の参照
+  - SYSCMP: 機器カタログ（EQPCAT）、室、外表面の参照
+  - SYSPTH: システム要素（SYSCMP、室、`_OA`、`_CW`）と流量のスケジュールの参照
+  - CONTL: 経路、要素の変数、スケジュールの参照と条件式の書式
+  - 名前の重複（誤り）と、どこからも参照されない定義（警告）
+
+名前の検査で誤りがない場合は、さらに入力データファイルを実際のパーサーで読み込み（Init）、
+気象データファイルの有無などを含めて検査します。
+
+Simulation.Check は `#include`、`$define` の前処理（eepreproc.go）の後の内容を検査し、
+誤りの位置はインクルードしたファイルの行で報告します。インクルードしたファイルの定義は未使用の警告をしません。
+CheckInput は前処理を行わないため、`${...}` を含むトークンの参照は検査せず、
+`#include` がある場合は未定義の参照を警告とします。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Severity は検査結果の重大度です。
+type Severity int
+
+const (
+	SeverityError   Severity = iota + 1 // 誤り。計算できないか、意図しない結果になる
+	SeverityWarning                     // 警告。計算はできるが、誤りの可能性がある
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "unknown"
+}
+
+// MarshalText は重大度を "error"、"warning" で表します。
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic は検査で見つかった誤り、警告です。
+type Diagnostic struct {
+	File     string   `json:"file"`           // 入力データファイル名
+	Line     int      `json:"line,omitempty"` // 行（1から）。位置が分からない場合は 0
+	Col      int      `json:"col,omitempty"`  // 列（1から、バイト単位）
+	Len      int      `json:"len,omitempty"`  // 誤りのあるトークンの長さ（バイト単位）
+	Severity Severity `json:"severity"`       // 重大度
+	Section  string   `json:"section"`        // データセット名
+	Name     string   `json:"name,omitempty"` // 誤りのある名前、キーワード
+	Msg      string   `json:"message"`        // メッセージ
+}
+
+// String は `file:line:col: error: SECTION: message` の形式の文字列を返します。
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	b.WriteString(d.File)
+	if d.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", d.Line, d.Col)
+	}
+	fmt.Fprintf(&b, ": %s: ", d.Severity)
+	if d.Section != "" {
+		b.WriteString(d.Section + ": ")
+	}
+	b.WriteString(d.Msg)
+	return b.String()
+}
+
+// HasErrors は diags に誤り（SeverityError）が含まれるかどうかを返します。
+func HasErrors(diags []Diagnostic) bool {
+	return slices.ContainsFunc(diags, func(d Diagnostic) bool { return d.Severity == SeverityError })
+}
+
+// Check は入力データファイル sim.InFile を計算せずに検査し、見つかった全ての誤りと警告を位置の順に返します。
+// 入力データファイル、EFLファイル、気象データファイルは sim.FS、sim.EflFS から読み込み、出力ファイルは書き出しません。
+// sim の状態は変更しないため、Check の後に Run で計算できます。
+// 入力データファイルを読み込めない場合はエラーを返します。
+func (sim *Simulation) Check() ([]Diagnostic, error) {
+	fsys := sim.FS
+	if fsys == nil {
+		fsys = osFS{}
+	}
+	enc, err := ParseEncoding(sim.Encoding, EncodingAuto)
+	if err != nil {
+		return nil, err
+	}
+	fsys = decodingFS{fsys, enc}
+	src, err := fs.ReadFile(fsys, sim.InFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// 前処理の後の内容を検査する
+	format := InputFormat(sim.InFile)
+	text := string(src)
+	if format != "text" {
+		doc, err := ReadInputDocument(bytes.NewReader(src), format)
+		if err != nil {
+			return nil, err
+		}
+		text = doc.String()
+	}
+	eflfs := sim.EflFS
+	if eflfs == nil {
+		eflfs = eflFS(sim.EflPath)
+	}
+	eflfs = decodingFS{eflfs, enc}
+	text, lines, err := preprocessInput(sim.InFile, text, fsys, eflfs, sim.Defines)
+	var pe *preprocError
+	if errors.As(err, &pe) {
+		d := Diagnostic{File: pe.File, Line: pe.Line, Severity: SeverityError, Name: pe.Keyword, Msg: pe.Msg}
+		if format != "text" && pe.File == sim.InFile {
+			d.Line = 0
+		}
+		return []Diagnostic{d}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	c := emptyChecker(sim.InFile)
+	if c.doc, err = parseInputDocument(strings.NewReader(text), &c.pos); err != nil {
+		return nil, err
+	}
+	c.lines = lines
+	if format != "text" {
+		// JSON、YAML の場合は位置が分からない
+		c.pos, c.lines = docPositions{}, nil
+	}
+	c.check()
+	if HasErrors(c.diags) {
+		return c.sorted(), nil
+	}
+
+	// 名前の検査で見つからない誤りは、実際のパーサーで読み込んで検査する
+	s := NewSimulation(sim.InFile, sim.EflPath)
+	s.FS, s.EflFS, s.Defines = sim.FS, sim.EflFS, sim.Defines
+	s.Encoding = sim.Encoding
+	s.Output = new(MemorySink)
+	if err := s.Init(); err != nil {
+		c.fromError(err)
+	}
+	return c.sorted(), nil
+}
+
+// CheckInput は入力データファイルの内容 src の名前の定義と参照を検査します。
+// name は Diagnostic.File と書式（.json、.yaml の判定）に用います。パーサーによる検査は行いません。
+func CheckInput(name string, src []byte) ([]Diagnostic, error) {
+	c, err := newChecker(name, src)
+	if err != nil {
+		return nil, err
+	}
+	c.check()
+	return c.sorted(), nil
+}
+
+// checkDef は名前の定義です。
+type checkDef struct {
+	ref checkRef // 定義した位置
+}
+
+// checkRef は論理行のトークンです。
+type checkRef struct {
+	sec string
+	st  *Statement
+	i   int // st.tokens() の番号。-1 の場合はデータセット名
+}
+
+// checker は入力データファイルの検査の状態です。
+type checker struct {
+	file    string
+	doc     *InputDocument
+	pos     docPositions
+	lines   []srcLine // 前処理後の各行の元のファイルと行番号。nil の場合は前処理していない
+	include bool      // 前処理していない内容に `#include` があるかどうか
+	diags   []Diagnostic
+
+	defs  map[string]map[string]*checkDef // 種類（checkKinds）ごとの名前の定義
+	words map[string]bool                 // 定義以外で用いられた名前（未使用の警告の判定用）
+
+	firstWall string        // WALL の最初の壁体名
+	dfwl      map[byte]bool // 既定値の壁体（`-E` など）が定義された部位コード
+}
+
+// 名前の種類
+const (
+	kindEXSRF  = "EXSRF"
+	kindWALL   = "WALL"
+	kindWINDOW = "WINDOW"
+	kindSUNBRK = "SUNBRK"
+	kindPCM    = "PCM"
+	kindROOM   = "ROOM"
+	kindVCFILE = "VCFILE"
+	kindCAT    = "EQPCAT"
+	kindCOMP   = "SYSCMP"
+	kindPATH   = "SYSPTH"
+	kindSCH    = "schedule"        // 設定値スケジュール（SCHTB -v、SCHNM -v）
+	kindSCW    = "switch schedule" // 切換スケジュール（SCHTB -s、SCHNM -s）
+	kindDSCH   = "daily schedule"  // 1日の設定値スケジュール（SCHTB -v）
+	kindDSCW   = "daily switch"    // 1日の切換スケジュール（SCHTB -s）
+	kindSSN    = "season"          // 季節（SCHTB -ssn）
+	kindWKD    = "weekday"         // 曜日（SCHTB -wkd）
+)
+
+// envNames は CONTL などで参照できる外気の変数名です。ref: kynameptr
+var envNames = []string{"Ta", "xa", "RHa", "ha", "Twsup", "Ihol"}
+
+// wordPattern は名前の候補となる語です。
+var wordPattern = regexp.MustCompile(`[A-Za-z_][\w.]*`)
+
+func newChecker(name string, src []byte) (*checker, error) {
+	c := emptyChecker(name)
+	var err error
+	if format := InputFormat(name); format != "text" {
+		// JSON、YAML の場合は位置が分からない
+		c.doc, err = ReadInputDocument(bytes.NewReader(src), format)
+	} else {
+		c.doc, err = parseInputDocument(bytes.NewReader(src), &c.pos)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// source は検査する内容の行 line の、元のファイルと行番号を返します。
+func (c *checker) source(line int) srcLine {
+	if line > 0 && line <= len(c.lines) {
+		return c.lines[line-1]
+	}
+	return srcLine{c.file, line}
+}
+
+// refLine は ref の位置の行を返します。位置が分からない場合は 0 を返します。
+func (c *checker) refLine(ref checkRef) int {
+	if at := c.pos.stmts[ref.st]; ref.i >= 0 && ref.i < len(at) {
+		return at[ref.i].Line
+	}
+	return 0
+}
+
+// included は ref がインクルードしたファイルの中にあるかどうかを返します。
+func (c *checker) included(ref checkRef) bool {
+	line := c.refLine(ref)
+	return line > 0 && c.source(line).File != c.file
+}
+
+// hasInclude は前処理していない内容に `#include` があるかどうかを返します。
+func (c *checker) hasInclude() bool {
+	if c.lines != nil {
+		return false
+	}
+	for _, sec := range c.doc.Sections {
+		for _, st := range sec.Statements {
+			if strings.HasPrefix(st.Directive, "#include") {
+				return true
+			}
+		}
+		for _, b := range sec.Blocks {
+			for _, st := range b {
+				if strings.HasPrefix(st.Directive, "#include") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// emptyChecker は入力データファイル name の検査の状態を作成します。
+func emptyChecker(name string) *checker {
+	return &checker{
+		file:  name,
+		defs:  make(map[string]map[string]*checkDef),
+		words: make(map[string]bool),
+		dfwl:  make(map[byte]bool),
+	}
+}
+
+// report は ref の位置の診断を加えます。
+func (c *checker) report(sev Severity, ref checkRef, name, format string, a ...interface{}) {
+	d := Diagnostic{File: c.file, Severity: sev, Section: ref.sec, Name: name, Msg: fmt.Sprintf(format, a...)}
+	if ref.i >= 0 && ref.st != nil {
+		if at := c.pos.stmts[ref.st]; ref.i < len(at) {
+			d.Line, d.Col, d.Len = at[ref.i].Line, at[ref.i].Col, len(ref.st.tokens()[ref.i])
+		}
+	}
+	c.diags = append(c.diags, d)
+}
+
+// sorted は診断を位置の順に並べ、前処理した場合は元のファイルの位置にして返します。
+func (c *checker) sorted() []Diagnostic {
+	sort.SliceStable(c.diags, func(i, j int) bool {
+		a, b := c.diags[i], c.diags[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	if c.lines != nil {
+		for i := range c.diags {
+			if d := &c.diags[i]; d.Line > 0 {
+				at := c.source(d.Line)
+				d.File, d.Line = at.File, at.Line
+			}
+		}
+	}
+	return c.diags
+}
+
+// define は種類 kind の名前 name を ref で定義します。重複した場合は sev の診断を加えます。
+func (c *checker) define(kind, name string, ref checkRef, sev Severity) {
+	if c.defs[kind] == nil {
+		c.defs[kind] = make(map[string]*checkDef)
+	}
+	if d, ok := c.defs[kind][name]; ok {
+		if line := c.refLine(d.ref); line > 0 {
+			at := c.source(line)
+			if at.File != c.source(c.refLine(ref)).File {
+				c.report(sev, ref, name, "%s %q is already defined at %s:%d", kindNoun(kind), name, at.File, at.Line)
+			} else {
+				c.report(sev, ref, name, "%s %q is already defined at line %d", kindNoun(kind), name, at.Line)
+			}
+		} else {
+			c.report(sev, ref, name, "%s %q is already defined", kindNoun(kind), name)
+		}
+		return
+	}
+	c.defs[kind][name] = &checkDef{ref: ref}
+}
+
+// defined は種類 kind の名前 name が定義されているかどうかを返します。
+func (c *checker) defined(kind, name string) bool {
+	_, ok := c.defs[kind][name]
+	return ok
+}
+
+// refer は種類 kind の名前 name の参照を検査します。
+func (c *checker) refer(kind, name string, ref checkRef) bool {
+	if c.defined(kind, name) {
+		return true
+	}
+	c.undefined(ref, name, "%s %q is not defined in %s", kindNoun(kind), name, kindSection(kind))
+	return false
+}
+
+// undefined は ref の位置の名前 name が定義されていない誤りを加えます。
+// 前処理していない内容では、パラメータを含む名前は検査せず、`#include` がある場合は警告とします。
+func (c *checker) undefined(ref checkRef, name, format string, a ...interface{}) {
+	switch {
+	case strings.Contains(name, "${"):
+		// パラメータの値が分からない
+	case c.include:
+		c.report(SeverityWarning, ref, name, format+" (it may be in an included file)", a...)
+	default:
+		c.report(SeverityError, ref, name, format, a...)
+	}
+}
+
+// kindNoun は種類 kind のメッセージでの呼び方です。
+func kindNoun(kind string) string {
+	switch kind {
+	case kindEXSRF:
+		return "external surface"
+	case kindSUNBRK:
+		return "sunbreak"
+	case kindVCFILE:
+		return "vcfile"
+	case kindCAT:
+		return "catalog"
+	case kindCOMP:
+		return "component"
+	case kindPATH:
+		return "path"
+	}
+	return strings.ToLower(kind)
+}
+
+// kindSection は種類 kind の名前を定義するデータセットです。
+func kindSection(kind string) string {
+	switch kind {
+	case kindSCH, kindSCW, kindDSCH, kindDSCW, kindSSN, kindWKD:
+		return "SCHTB/SCHNM"
+	case kindCOMP:
+		return "SYSCMP or ROOM"
+	}
+	return kind
+}
+
+// statements は全ての論理行を、データセット名、ROOM・COORDNT のまとまりの番号とともに返します。
+func (c *checker) statements(f func(sec string, block int, st *Statement)) {
+	for _, sec := range c.doc.Sections {
+		for _, st := range sec.Statements {
+			if len(st.tokens()) > 0 {
+				f(sec.Name, -1, st)
+			}
+		}
+		for k, b := range sec.Blocks {
+			for _, st := range b {
+				if len(st.tokens()) > 0 {
+					f(sec.Name, k, st)
+				}
+			}
+		}
+	}
+}
+
+// check は全ての検査を行います。
+func (c *checker) check() {
+	c.include = c.hasInclude()
+	c.collect()
+	c.statements(c.checkStatement)
+	c.unused()
+}
+
+// isSchedule は論理行がスケジュールの定義（`%s`、`%sn`、SCHTB、SCHNM）かどうかを返します。
+func isSchedule(sec string, tokens []string) bool {
+	return tokens[0] == "%s" || tokens[0] == "%sn" || sec == "SCHTB" || sec == "SCHNM"
+}
+
+// collect は名前の定義を集めます。
+func (c *checker) collect() {
+	// システム要素として予め定義される名前 ref: Compodata
+	c.define(kindCOMP, CITYWATER_NAME, checkRef{i: -1}, SeverityError)
+	c.define(kindCOMP, OUTDRAIR_NAME, checkRef{i: -1}, SeverityError)
+
+	c.statements(func(sec string, block int, st *Statement) {
+		tokens := st.tokens()
+		ref := func(i int) checkRef { return checkRef{sec: sec, st: st, i: i} }
+
+		if isSchedule(sec, tokens) {
+			c.collectSchedule(sec, st)
+			return
+		}
+
+		switch sec {
+		case "EXSRF":
+			if paramKey(tokens[0]) == "" {
+				c.define(kindEXSRF, tokens[0], ref(0), SeverityError)
+			}
+		case "WALL":
+			if strings.HasPrefix(tokens[0], "wbmlist=") {
+				return // 材料定義リストの指定
+			}
+			name, ble := tokens[0], ""
+			if strings.HasPrefix(name, "-") && len(name) > 1 {
+				ble = name[1:2]
+				if len(name) > 2 && name[2] == ':' {
+					name = name[3:]
+				} else {
+					name = ""
+				}
+			}
+			if c.firstWall == "" {
+				c.firstWall = tokens[0]
+			}
+			if name != "" {
+				c.define(kindWALL, ble+":"+name, ref(0), SeverityError)
+			} else if ble != "" {
+				c.dfwl[ble[0]] = true
+			}
+		case "WINDOW":
+			c.define(kindWINDOW, tokens[0], ref(0), SeverityError)
+		case "SUNBRK":
+			c.define(kindSUNBRK, tokens[0], ref(0), SeverityError)
+		case "PCM":
+			c.define(kindPCM, tokens[0], ref(0), SeverityError)
+		case "VCFILE":
+			c.define(kindVCFILE, tokens[0], ref(0), SeverityError)
+		case "ROOM":
+			if c.isRoomHead(sec, block, st) {
+				c.define(kindROOM, tokens[0], ref(0), SeverityError)
+				c.define(kindCOMP, tokens[0], ref(0), SeverityError)
+			}
+			// 放射パネル
+			// 放射パネル。隣室と共有する部位では両方の室に同じ名前を指定する
+			for i, tok := range tokens {
+				if name, ok := strings.CutPrefix(tok, "i="); ok && !c.defined(kindCOMP, name) {
+					c.define(kindCOMP, name, ref(i), SeverityError)
+				}
+			}
+		case "EQPCAT":
+			if len(tokens) > 1 && !strings.HasPrefix(tokens[0], "-") {
+				c.define(kindCAT, tokens[1], ref(1), SeverityError)
+			}
+		case "SYSCMP":
+			c.collectComponent(sec, st)
+		case "SYSPTH":
+			c.define(kindPATH, tokens[0], ref(0), SeverityError)
+			for i, tok := range tokens {
+				switch {
+				case strings.HasPrefix(tok, "name="):
+					c.define(kindPATH, tok[5:], ref(i), SeverityError)
+				case !strings.ContainsAny(tok[:1], "(>[-") && strings.Contains(tok, ":"):
+					c.define(kindPATH, tok, ref(i), SeverityError)
+				}
+			}
+		}
+	})
+}
+
+// isRoomHead は論理行 st が ROOM のまとまりの最初の論理行（室名で始まる）かどうかを返します。
+func (c *checker) isRoomHead(sec string, block int, st *Statement) bool {
+	for _, s := range c.blockOf(sec, block) {
+		if len(s.tokens()) > 0 {
+			return s == st
+		}
+	}
+	return false
+}
+
+// blockOf はデータセット sec の block 番目のまとまりを返します。
+func (c *checker) blockOf(sec string, block int) []*Statement {
+	for _, s := range c.doc.Sections {
+		if s.Name == sec && block >= 0 && block < len(s.Blocks) {
+			return s.Blocks[block]
+		}
+	}
+	return nil
+}
+
+// collectSchedule はスケジュールの定義を集めます。ref: Schtable、Schdata
+func (c *checker) collectSchedule(sec string, st *Statement) {
+	tokens := st.tokens()
+	i := 0
+	named := sec == "SCHNM" || tokens[0] == "%sn"
+	if tokens[0] == "%s" || tokens[0] == "%sn" {
+		i = 1
+	}
+	if i+1 >= len(tokens) {
+		return
+	}
+	ref := checkRef{sec: sec, st: st, i: i + 1}
+	name := tokens[i+1]
+	switch tokens[i] {
+	case "-v", "VL":
+		if !named {
+			c.define(kindDSCH, name, ref, SeverityWarning)
+		}
+		c.define(kindSCH, name, ref, SeverityWarning)
+	case "-s", "SW":
+		if !named {
+			c.define(kindDSCW, name, ref, SeverityWarning)
+		}
+		c.define(kindSCW, name, ref, SeverityWarning)
+	case "-ssn", "SSN":
+		c.define(kindSSN, name, ref, SeverityWarning)
+	case "-wkd", "WKD":
+		c.define(kindWKD, name, ref, SeverityWarning)
+	}
+}
+
+// collectComponent は SYSCMP のシステム要素の名前を集めます。三方弁は `(名前1 名前2)` で2つ定義します。
+func (c *checker) collectComponent(sec string, st *Statement) {
+	tokens := st.tokens()
+	if strings.HasPrefix(tokens[0], "(") {
+		i := 0
+		name := strings.TrimPrefix(tokens[0], "(")
+		if name == "" && len(tokens) > 1 {
+			i, name = 1, tokens[1]
+		}
+		c.define(kindCOMP, name, checkRef{sec, st, i}, SeverityError)
+		if i+1 < len(tokens) {
+			name2, _, _ := strings.Cut(tokens[i+1], ")")
+			c.define(kindCOMP, name2, checkRef{sec, st, i + 1}, SeverityError)
+		}
+		return
+	}
+	if d, ok := c.defs[kindCOMP][tokens[0]]; ok && d.ref.sec != "SYSCMP" {
+		// 室、放射パネル、`_OA`、`_CW` の設定の追加
+		return
+	}
+	c.define(kindCOMP, tokens[0], checkRef{sec, st, 0}, SeverityError)
+}
+
+// checkStatement は論理行の参照を検査します。
+func (c *checker) checkStatement(sec string, block int, st *Statement) {
+	tokens := st.tokens()
+	for i, tok := range tokens {
+		if c.defRef(st, i) {
+			continue
+		}
+		for _, w := range wordPattern.FindAllString(tok, -1) {
+			c.words[w] = true
+			// `要素名_変数名`、`PCM名_含有率`
+			name, _, _ := strings.Cut(w, "_")
+			c.words[name] = true
+		}
+	}
+
+	if isSchedule(sec, tokens) {
+		if sec == "SCHNM" || tokens[0] == "%sn" {
+			c.checkSCHNM(sec, st)
+		}
+		return
+	}
+
+	switch sec {
+	case "WALL":
+		c.checkWall(sec, st)
+	case "ROOM":
+		// ROOM は室ごとにまとめて検査する
+		if c.isRoomHead(sec, block, st) {
+			c.checkRoom(sec, c.blockOf(sec, block))
+		}
+	case "RAICH", "VENT":
+		c.checkRoomSchedules(sec, st, map[string][]int{"Vent": {1}, "Inf": {1}})
+	case "RESI":
+		c.checkRoomSchedules(sec, st, map[string][]int{"H": {1, 2}, "comfrt": {0, 1, 2}})
+	case "APPL":
+		c.checkRoomSchedules(sec, st, map[string][]int{"L": {2}, "As": {2}, "Al": {1}, "AE": {1}, "AG": {1}})
+	case "SYSCMP":
+		c.checkComponent(sec, st)
+	case "SYSPTH":
+		c.checkPath(sec, st)
+	case "CONTL":
+		c.checkControl(sec, st)
+	}
+}
+
+// defRef は st の i 番目のトークンが名前の定義かどうかを返します。
+func (c *checker) defRef(st *Statement, i int) bool {
+	for _, m := range c.defs {
+		for _, d := range m {
+			if d.ref.st == st && d.ref.i == i {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNumber は s が数値かどうかを返します。
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// checkScheduleValue はスケジュール（設定値名）または数値 s を検査します。ref: envptr
+func (c *checker) checkScheduleValue(s string, ref checkRef) {
+	if isNumber(s) || c.defined(kindSCH, s) {
+		return
+	}
+	c.undefined(ref, s, "schedule %q is not defined in SCHTB/SCHNM", s)
+}
+
+// checkRoomSchedules は RAICH、VENT、RESI、APPL の論理行を検査します。
+// schedules はキーワードごとの `(値,値,...)` のうちスケジュール（設定値名）の位置です。
+func (c *checker) checkRoomSchedules(sec string, st *Statement, schedules map[string][]int) {
+	tokens := st.tokens()
+	c.refer(kindROOM, tokens[0], checkRef{sec, st, 0})
+	for i, tok := range tokens[1:] {
+		ref := checkRef{sec, st, i + 1}
+		key := paramKey(tok)
+		idx, ok := schedules[key]
+		if !ok {
+			c.report(SeverityWarning, ref, tok, "unknown keyword %q", tok)
+			continue
+		}
+		value := tok[len(key)+1:]
+		if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+			c.report(SeverityError, ref, tok, "%s must be (value,...)", key)
+			continue
+		}
+		values := strings.Split(value[1:len(value)-1], ",")
+		for _, k := range idx {
+			if k >= len(values) {
+				c.report(SeverityError, ref, tok, "%s needs %d values", key, idx[len(idx)-1]+1)
+				break
+			}
+			c.checkScheduleValue(values[k], ref)
+		}
+	}
+}
+
+// checkSCHNM は SCHNM、`%sn` のスケジュールの組み合わせ `1日の設定名:季節名-曜日名` を検査します。ref: Schdata
+func (c *checker) checkSCHNM(sec string, st *Statement) {
+	tokens := st.tokens()
+	i := 0
+	if tokens[0] == "%sn" {
+		i = 1
+	}
+	if i+1 >= len(tokens) {
+		return
+	}
+	day := kindDSCH
+	switch tokens[i] {
+	case "-v", "VL":
+	case "-s", "SW":
+		day = kindDSCW
+	default:
+		c.report(SeverityError, checkRef{sec, st, i}, tokens[i], "unknown schedule type %q (expected -v or -s)", tokens[i])
+		return
+	}
+	pattern := regexp.MustCompile(`^(\w+)(?::(\w*))?(?:-(\w+))?`)
+	for k := i + 2; k < len(tokens); k++ {
+		ref := checkRef{sec, st, k}
+		if strings.HasPrefix(tokens[k], "csv=") || strings.HasPrefix(tokens[k], "col=") {
+			// CSV の時系列によるスケジュール。ref: eschcsv.go
+			continue
+		}
+		m := pattern.FindStringSubmatch(tokens[k])
+		if m == nil {
+			c.report(SeverityError, ref, tokens[k], "invalid schedule combination %q", tokens[k])
+			continue
+		}
+		if m[1] != "" {
+			c.refer(day, m[1], ref)
+		}
+		if m[2] != "" {
+			c.refer(kindSSN, m[2], ref)
+		}
+		if m[3] != "" {
+			c.refer(kindWKD, m[3], ref)
+		}
+	}
+}
+
+// pcmPattern は WALL の層構成の PCM の指定 `材料名(PCM名_含有率)-厚さ` です。ref: Walli
+var pcmPattern = regexp.MustCompile(`\(([^_)]*)_[^)]*\)`)
+
+// checkWall は WALL の論理行の PCM の参照を検査します。
+// 見つからない PCM は Walli で無視されるため、誤りとします。
+func (c *checker) checkWall(sec string, st *Statement) {
+	for i, tok := range st.tokens() {
+		if m := pcmPattern.FindStringSubmatch(tok); m != nil {
+			c.refer(kindPCM, m[1], checkRef{sec, st, i})
+		}
+	}
+}
+
+// checkRoom は ROOM の1室のまとまりを検査します。ref: Roomdata
+func (c *checker) checkRoom(sec string, block []*Statement) {
+	compnameStart := false // 部位の設定を読み取り中かどうか
+	for _, st := range block {
+		tokens := st.tokens()
+		if len(tokens) == 0 {
+			continue
+		}
+		var ble byte     // 部位コード
+		bleAt := -1      // 部位コードのトークンの番号
+		dexsname := -1   // 外表面名を兼ねる部位名のトークンの番号
+		hasExs := false  // e= の指定があるかどうか
+		hasWall := false // 壁体名の指定があるかどうか
+		start := 0
+		if st == firstStatement(block) {
+			start = 1 // 室名
+		}
+		for i := start; i < len(tokens); i++ {
+			s := tokens[i]
+			ref := checkRef{sec, st, i}
+			key := ""
+			if k := strings.IndexByte(s, '='); k > 0 {
+				key = s[:k]
+			}
+
+			switch {
+			case key == "" && (s == "*s" || s == "*q" || s == "*sfe" || s == "*p" || s == "*shd" || s == "rsrnx"):
+			case key == "" && strings.Contains(s, "*"):
+				// 面積 `幅*高さ`
+			case key == "" && s[0] >= '0' && s[0] <= '9':
+				// 面積
+			case key == "" && s == "if":
+				j := i + 1
+				for j < len(tokens) && !strings.HasSuffix(tokens[j], ")") {
+					j++
+				}
+				if j+1 >= len(tokens) {
+					c.report(SeverityError, ref, s, "if needs (condition) and a window name")
+					i = len(tokens)
+					break
+				}
+				c.refer(kindWINDOW, tokens[j+1], checkRef{sec, st, j + 1})
+				i = j + 1
+			case key == "" && strings.HasSuffix(s, ":"):
+				name := strings.TrimSuffix(s, ":")
+				if strings.HasPrefix(name, "(") && strings.HasSuffix(name, ")") {
+					c.refer(kindROOM, name[1:len(name)-1], ref)
+				} else {
+					dexsname = i
+				}
+				compnameStart = true
+			case key == "" && s == "Fij":
+				// 形態係数は論理行の残りを読み飛ばす
+				i = len(tokens)
+			case key == "" && s[0] == '-':
+				if len(s) != 2 || !strings.ContainsRune("ERFicfW", rune(s[1])) {
+					c.report(SeverityError, ref, s, "invalid surface code %q (expected -E, -R, -F, -i, -c, -f or -W)", s)
+					break
+				}
+				ble, bleAt = s[1], i
+			case key == "" && compnameStart:
+				switch ble {
+				case 0:
+					c.report(SeverityError, ref, s, "surface code (-E, -W, ...) is required before %q", s)
+				case 'W':
+					name := s
+					if k := strings.IndexByte(s, ':'); k >= 0 {
+						name = s[k+1:]
+					}
+					c.refer(kindWINDOW, name, ref)
+				default:
+					hasWall = true
+					found := c.defined(kindWALL, string(ble)+":"+s) ||
+						(ble == 'c' && c.defined(kindWALL, "f:"+s)) ||
+						(ble == 'f' && c.defined(kindWALL, "c:"+s)) ||
+						c.defined(kindWALL, ":"+s)
+					if !found {
+						c.undefined(ref, s, "wall %q (-%c) is not defined in WALL", s, ble)
+					}
+				}
+			case key == "":
+				// 室のパラメータの前の語は読み飛ばされる
+			case !compnameStart:
+				switch key {
+				case "Vol", "Hcap", "Mxcap":
+				case "PCMFurn":
+					name, _, _ := strings.Cut(s[len(key)+1:], ",")
+					name = strings.TrimPrefix(name, "(")
+					c.refer(kindPCM, name, ref)
+				case "flrsr", "alc", "MCAP", "CM", "fsolm", "OTc":
+					c.checkScheduleValue(s[len(key)+1:], ref)
+				default:
+					c.report(SeverityWarning, ref, s, "unknown room keyword %q", key)
+				}
+			default:
+				value := s[len(key)+1:]
+				switch key {
+				case "A", "c", "PVcap", "Wsu", "Wsd", "Ndiv", "tnxt", "i", "rmp":
+				case "e":
+					hasExs = true
+					c.refer(kindEXSRF, value, ref)
+				case "sb":
+					c.refer(kindSUNBRK, value, ref)
+				case "r":
+					c.refer(kindROOM, value, ref)
+				case "sw":
+					c.refer(kindSCW, value, ref)
+				case "alc", "alr", "fsol":
+					c.checkScheduleValue(value, ref)
+				default:
+					c.report(SeverityError, ref, s, "unknown surface keyword %q", key)
+				}
+			}
+		}
+
+		// 壁体名の指定がなく、部位の既定値もない場合は Walldata の既定値の番号 0（最初の壁体）となる
+		if bleAt >= 0 && ble != 'W' && !hasWall && !c.dfwl[ble] && c.firstWall != "" {
+			first := c.firstWall
+			if len(first) > 2 && first[0] == '-' && first[2] == ':' {
+				c.words[first[3:]] = true
+			}
+			if len(first) < 2 || first[0] != '-' || first[1] != ble {
+				c.report(SeverityWarning, checkRef{sec, st, bleAt}, tokens[bleAt],
+					"no default wall for %s in WALL; the first wall %q is used", tokens[bleAt], first)
+			}
+		}
+
+		// 外部に面する部位の部位名は外表面名
+		if (ble == 'E' || ble == 'R' || ble == 'F' || ble == 'W') && !hasExs {
+			if dexsname >= 0 {
+				c.refer(kindEXSRF, strings.TrimSuffix(tokens[dexsname], ":"), checkRef{sec, st, dexsname})
+			}
+		}
+	}
+}
+
+// firstStatement は block の最初の論理行を返します。
+func firstStatement(block []*Statement) *Statement {
+	for _, st := range block {
+		if len(st.tokens()) > 0 {
+			return st
+		}
+	}
+	return nil
+}
+
+// checkComponent は SYSCMP の論理行を検査します。ref: Compodata
+func (c *checker) checkComponent(sec string, st *Statement) {
+	tokens := st.tokens()
+	opt := ""
+	for i := 1; i < len(tokens); i++ {
+		s := tokens[i]
+		ref := checkRef{sec, st, i}
+		if strings.HasPrefix(s, "-") && !isNumber(s) {
+			opt = s[1:]
+			switch opt {
+			case "c", "type", "Nin", "Nout", "in", "out", "L", "env", "room", "roomheff", "exs",
+				"Tinit", "hcc", "pfloor", "wet", "control", "monitor", "PCMweight":
+			case "S", "V":
+				// `*` までは機器の設定
+				for i+1 < len(tokens) && tokens[i+1] != "*" {
+					i++
+				}
+				i++
+			default:
+				c.report(SeverityWarning, ref, s, "unknown option %q", s)
+			}
+			continue
+		}
+		if strings.ContainsAny(s, "-=") {
+			// Ac=、PVcap=、Area= など
+			continue
+		}
+		switch opt {
+		case "c":
+			c.refer(kindCAT, s, ref)
+		case "room":
+			c.refer(kindROOM, s, ref)
+		case "roomheff":
+			c.refer(kindROOM, s, ref)
+			i++ // 室内部発熱の割合
+		case "exs":
+			c.refer(kindEXSRF, s, ref)
+		}
+	}
+}
+
+// checkPath は SYSPTH の論理行を検査します。ref: Pathdata
+func (c *checker) checkPath(sec string, st *Statement) {
+	tokens := st.tokens()
+	inBranch := false
+	for i := 1; i < len(tokens); i++ {
+		s := tokens[i]
+		ref := checkRef{sec, st, i}
+		switch {
+		case s == ">":
+			inBranch = !inBranch
+		case !inBranch && strings.HasPrefix(s, "-"):
+			if s != "-sys" && s != "-f" {
+				c.report(SeverityError, ref, s, "unknown option %q", s)
+			}
+			i++
+		case !inBranch:
+			c.report(SeverityError, ref, s, "%q outside a branch `> ... >`", s)
+		case strings.HasPrefix(s, "("):
+			v := strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+			if !isNumber(v) && !c.defined(kindSCH, v) {
+				c.checkVariable(v, ref)
+			}
+		case strings.HasPrefix(s, "["), strings.HasPrefix(s, "name="):
+		default:
+			elm := s
+			if k := strings.IndexByte(elm, '/'); k >= 0 {
+				elm = elm[k+1:]
+			}
+			if k := strings.IndexByte(elm, ':'); k >= 0 {
+				elm = elm[:k]
+			} else if k := strings.IndexByte(elm, '['); k >= 0 {
+				elm = elm[:k]
+			}
+			c.refer(kindCOMP, elm, ref)
+		}
+	}
+	if inBranch {
+		c.report(SeverityError, checkRef{sec, st, 0}, tokens[0], "branch of %s is not closed with `>`", tokens[0])
+	}
+}
+
+// checkVariable は CONTL などの変数名 s（`要素名_変数名`、経路名、外気の変数名）の要素名を検査します。ref: kynameptr
+func (c *checker) checkVariable(s string, ref checkRef) bool {
+	if c.defined(kindSCH, s) || c.defined(kindSCW, s) {
+		return true
+	}
+	name, _, _ := strings.Cut(s, "_")
+	if slices.Contains(envNames, name) {
+		return true
+	}
+	for _, kind := range []string{kindCOMP, kindPATH, kindEXSRF, kindVCFILE} {
+		if c.defined(kind, name) {
+			return true
+		}
+	}
+	c.undefined(ref, s, "%q is not a schedule, path, component or room", name)
+	return false
+}
+
+// checkControlValue は CONTL の右辺 s を検査します。ref: ctlrgtptr
+func (c *checker) checkControlValue(s string, ref checkRef) {
+	switch {
+	case isNumber(s), s == "ON", s == "OFF", s == "COOL", s == "HEAT", strings.HasPrefix(s, "'"):
+	default:
+		c.checkVariable(s, ref)
+	}
+}
+
+// controlOps は CONTL の条件式の比較演算子です。ref: ctifdecode
+var controlOps = []string{">", ">=", "<", "<=", "==", "!="}
+
+// checkControl は CONTL の論理行を検査します。ref: Contrldata
+func (c *checker) checkControl(sec string, st *Statement) {
+	tokens := st.tokens()
+	if tokens[0] == "TVALV" {
+		return
+	}
+	load := false
+	for i := 0; i < len(tokens); i++ {
+		s := tokens[i]
+		ref := checkRef{sec, st, i}
+		switch {
+		case s == "if" || s == "AND" || s == "OR":
+			j := i + 1
+			var parts []string
+			for ; j < len(tokens); j++ {
+				parts = append(parts, tokens[j])
+				if strings.HasSuffix(tokens[j], ")") {
+					break
+				}
+			}
+			cond := strings.Fields(strings.TrimSuffix(strings.TrimPrefix(strings.Join(parts, " "), "("), ")"))
+			if len(cond) < 3 {
+				c.report(SeverityError, ref, s, "condition must be ( left operator right ) separated by spaces")
+			} else {
+				condRef := checkRef{sec, st, i + 1}
+				lft, rgt, _ := strings.Cut(cond[0], "-")
+				c.checkVariable(lft, condRef)
+				if rgt != "" {
+					c.checkVariable(rgt, condRef)
+				}
+				if !slices.Contains(controlOps, cond[1]) {
+					c.report(SeverityError, condRef, cond[1], "unknown operator %q", cond[1])
+				}
+				c.checkControlValue(cond[2], condRef)
+			}
+			i = j
+		case strings.HasPrefix(s, "LOAD"):
+			load = true
+			if name, ok := strings.CutPrefix(s, "LOAD:"); ok && name != "H" && name != "C" {
+				c.refer(kindSCW, name, ref)
+			}
+		case s == "-e":
+			if i+1 < len(tokens) {
+				c.refer(kindCOMP, tokens[i+1], checkRef{sec, st, i + 1})
+			}
+			i++
+		case paramKey(s) != "" || strings.Contains(s, "="):
+			key, value, _ := strings.Cut(s, "=")
+			if load {
+				name, _, _ := strings.Cut(key, "_")
+				c.refer(kindCOMP, name, ref)
+				load = false
+			} else {
+				c.checkVariable(key, ref)
+			}
+			c.checkControlValue(value, ref)
+		default:
+			c.report(SeverityWarning, ref, s, "unknown control %q is ignored", s)
+		}
+	}
+}
+
+// unused は参照されない定義を警告します。
+func (c *checker) unused() {
+	for _, kind := range []string{kindWALL, kindWINDOW, kindSUNBRK, kindPCM, kindCAT, kindSCH, kindSCW, kindSSN, kindWKD} {
+		for name, d := range c.defs[kind] {
+			if d.ref.st == nil || c.included(d.ref) {
+				continue
+			}
+			if kind == kindWALL {
+				_, name, _ = strings.Cut(name, ":")
+			}
+			if !c.words[name] {
+				c.report(SeverityWarning, d.ref, name, "%s %q is not used", kindNoun(kind), name)
+			}
+		}
+	}
+	// SYSCMP の要素が SYSPTH のどの経路にもない
+	for name, d := range c.defs[kindCOMP] {
+		if d.ref.sec == "SYSCMP" && !c.words[name] && !c.included(d.ref) {
+			c.report(SeverityWarning, d.ref, name, "component %q is not used in SYSPTH", name)
+		}
+	}
+}
+
+// fromError は Init のエラーを診断に加えます。位置はエラーのキーワード、要素名のトークンから探します。
+func (c *checker) fromError(err error) {
+	var sec, keyword, component string
+	var ie *InputError
+	var we *WeatherError
+	switch {
+	case errors.As(err, &ie):
+		sec, keyword, component = ie.Section, ie.Keyword, ie.Component
+	case errors.As(err, &we):
+		sec, keyword, component = we.Section, we.Keyword, we.Component
+	}
+
+	ref := checkRef{sec: sec, i: -1}
+	find := func(name string) bool {
+		if name == "" {
+			return false
+		}
+		found := false
+		c.statements(func(s string, _ int, st *Statement) {
+			if found || (sec != "" && s != sec) {
+				return
+			}
+			for i, tok := range st.tokens() {
+				if tok == name || strings.HasSuffix(tok, "="+name) {
+					ref, found = checkRef{sec: s, st: st, i: i}, true
+					return
+				}
+			}
+		})
+		return found
+	}
+	if !find(keyword) && !find(component) {
+		// データセット名の位置
+		for _, s := range c.doc.Sections {
+			if s.Name == sec {
+				if at, ok := c.pos.sections[s]; ok {
+					c.diags = append(c.diags, Diagnostic{File: c.file, Line: at.Line, Col: at.Col, Len: len(sec),
+						Severity: SeverityError, Section: sec, Name: keyword, Msg: err.Error()})
+					return
+				}
+			}
+		}
+	}
+	c.report(SeverityError, ref, keyword, "%s", err.Error())
+}
+
+// WriteDiagnostics は診断を1行ずつ w に出力します。
+func WriteDiagnostics(w io.Writer, diags []Diagnostic) error {
+	for _, d := range diags {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
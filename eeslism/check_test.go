@@ -0,0 +1,203 @@
+package eeslism
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// checkModel は検査のテスト用の入力データファイル。checkModelErrors の誤りを含む
+const checkModel = `TITLE test ;
+GDAT
+	FILE w=tokyo_3column_SI.has ;
+	RUN (1/1) 1/1-1/2 ;
+*
+SCHTB
+	-v Occ 000-(1.0)-2400 ;
+*
+EXSRF
+	south a=0.0 ;
+	Hor ;
+*
+WALL
+	-E:ExtWall RC-150 ;
+	-R:Roof FPS-100 ;
+*
+ROOM
+	Room1 Vol=100
+		south: -E ExtWal 15.5 ;
+		Hor: -R Roof 40.0 ;
+	*
+*
+VENT
+	Room1 Vent=(0.5,Occupancy) Inf=(0.2,Occ) ;
+*
+EQPCAT
+	BOI boi Qo=5000 eff=0.85 ;
+*
+SYSCMP
+	Boiler1 -c boi ;
+*
+SYSPTH
+	HeatPath -sys A -f W
+		> (0.01) Boiler > ;
+*
+CONTL
+	if (Ta < 10) Boiler1_Tout=60 ;
+	Boilr_Tout=50 ;
+*
+END
+`
+
+// checkModelErrors は checkModel の誤りの位置とメッセージ
+var checkModelErrors = []string{
+	"19:13: error: ROOM: wall \"ExtWal\" (-E) is not defined in WALL",
+	"24:8: error: VENT: schedule \"Occupancy\" is not defined in SCHTB/SCHNM",
+	"34:12: error: SYSPTH: component \"Boiler\" is not defined in SYSCMP or ROOM",
+	"38:2: error: CONTL: \"Boilr\" is not a schedule, path, component or room",
+}
+
+// TestCheckInput は1回の検査で全ての誤りが行・列とともに報告されることを確認する
+func TestCheckInput(t *testing.T) {
+	diags, err := CheckInput("room.txt", []byte(checkModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			got = append(got, strings.TrimPrefix(d.String(), "room.txt:"))
+		}
+	}
+	if strings.Join(got, "\n") != strings.Join(checkModelErrors, "\n") {
+		t.Errorf("diagnostics:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(checkModelErrors, "\n"))
+	}
+
+	// JSON の場合は位置が分からないが、誤りは同じ
+	doc, err := ParseInputDocument(strings.NewReader(checkModel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := doc.Write(&b, "json"); err != nil {
+		t.Fatal(err)
+	}
+	diags, err = CheckInput("room.json", []byte(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			n++
+			if d.Line != 0 {
+				t.Errorf("%v: position for JSON input", d)
+			}
+		}
+	}
+	if n != len(checkModelErrors) {
+		t.Errorf("%d errors for JSON input, want %d", n, len(checkModelErrors))
+	}
+}
+
+// TestCheckInputWarnings は計算はできるが誤りの可能性がある入力が警告となることを確認する
+func TestCheckInputWarnings(t *testing.T) {
+	src := `WALL
+	wbmlist=mylist.efl ;
+	-E:ExtWall RC-150 ;
+	-i:Partition GPB-12 ;
+*
+ROOM
+	Room1 Vol=100 Foo=1
+		south: -E 15.5 ;
+		Hor: -R 40.0 ;
+	*
+*
+SCHTB
+	-v Unused 000-(1.0)-2400 ;
+*
+`
+	diags, err := CheckInput("room.txt", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"room.txt:4:2: warning: WALL: wall \"Partition\" is not used",
+		"room.txt:7:16: warning: ROOM: unknown room keyword \"Foo\"",
+		"room.txt:8:3: error: ROOM: external surface \"south\" is not defined in EXSRF",
+		"room.txt:9:3: error: ROOM: external surface \"Hor\" is not defined in EXSRF",
+		"room.txt:9:8: warning: ROOM: no default wall for -R in WALL; the first wall \"-E:ExtWall\" is used",
+		"room.txt:13:5: warning: SCHTB: schedule \"Unused\" is not used",
+	}
+	var got []string
+	for _, d := range diags {
+		got = append(got, d.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diagnostics:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestCheckInputTestdata は計算できる全ての入力データファイルに誤りが報告されないことを確認する
+func TestCheckInputTestdata(t *testing.T) {
+	root := "../tests/comparison/testdata"
+	if _, err := os.Stat(root); err != nil {
+		t.Skip("testdata not found")
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".txt" {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		diags, err := CheckInput(path, src)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			return nil
+		}
+		for _, d := range diags {
+			if d.Severity == SeverityError {
+				t.Error(d)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSimulationCheck は名前の検査で見つからない誤りがパーサーによる読み込みで報告されることを確認する
+func TestSimulationCheck(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Skip("test input not found")
+	}
+
+	sim := NewSimulation("room.txt", "")
+	sim.FS = fstest.MapFS{"room.txt": {Data: b}}
+	diags, err := sim.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HasErrors(diags) {
+		t.Errorf("errors for a valid input: %v", diags)
+	}
+
+	// 気象データファイルがない
+	bad := strings.Replace(string(b), "tokyo_3column_SI.has", "nosuch.has", 1)
+	sim.FS = fstest.MapFS{"room.txt": {Data: []byte(bad)}}
+	diags, err = sim.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) == 0 || diags[0].Severity != SeverityError || diags[0].Section != "GDAT" || diags[0].Line != 9 {
+		t.Errorf("no error at GDAT FILE for a missing weather file: %v", diags)
+	}
+}
@@ -645,9 +645,6 @@ func runSimulationAndCompare(t *testing.T, name, baseDir, eflPath string) {
 	cleanupGeneratedFiles(t, ".")
 
 	// シミュレーション実行
-	// 同一プロセスで Entry() を複数回呼ぶ際、C static 移植のパッケージ変数が
-	// 汚染されるためリセットする
-	ResetGlobalState()
 	t.Logf("Running simulation: %s", testFile)
 	Entry(testFile, absEflPath)
 
@@ -875,9 +872,6 @@ func runSimulationTestWithVariants(t *testing.T, name, baseDir, eflPath string)
 
 			cleanupGeneratedFiles(t, ".")
 
-			// 同一プロセスで Entry() を複数回呼ぶ際、C static 移植のパッケージ変数が
-			// 汚染されるためリセットする
-			ResetGlobalState()
 			t.Logf("Running variant simulation: %s", testFile)
 			Entry(testFile, absEflPath)
 
@@ -74,6 +74,7 @@ type CTLST struct {
 	Path     interface{}
 	Lft      CTLTYP
 	Rgt      CTLTYP
+	Ldname   string // LOADで設定する変数名（例: Room_Tr）。LOAD以外では空
 }
 
 type VPTR struct {
@@ -14,7 +14,6 @@ func runSingleTest(t *testing.T, testFile, eflPath string) {
 			t.Logf("Panic recovered: %v\nStack trace:\n%s", r, debug.Stack())
 		}
 	}()
-	ResetGlobalState()
 	Entry(testFile, eflPath)
 }
 
@@ -17,6 +17,7 @@ package eeslism
 
 import (
 	"fmt"
+	"io"
 )
 
 /*
@@ -43,7 +44,7 @@ xprtwallinit (Export Wall Temperature Initialization for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func xprtwallinit(Nmwall int, M []*MWALL) {
+func xprtwallinit(Nmwall int, M []*MWALL, debug bool, Ferr io.Writer) {
 	Max := 0
 	for j := 0; j < Nmwall; j++ {
 		if M[j].M > Max {
@@ -51,7 +52,7 @@ func xprtwallinit(Nmwall int, M []*MWALL) {
 		}
 	}
 
-	if DEBUG {
+	if debug {
 		fmt.Println("--- xprtwallinit")
 		for j := 0; j < Nmwall; j++ {
 			fmt.Printf("Told  j=%2d", j)
@@ -107,8 +108,8 @@ xprsolrd (Export Solar Radiation Data for Debugging)
 この関数は、建物の日射環境を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func xprsolrd(E []*EXSF) {
-	if DEBUG {
+func xprsolrd(E []*EXSF, debug bool, Ferr io.Writer) {
+	if debug {
 		fmt.Println("--- xprsolrd")
 		for i, Exs := range E {
 			fmt.Printf("EXSF[%2d]=%s  Id=%5.0f  Idif=%5.0f  Iw=%5.0f RN=%5.0f cinc=%5.3f\n",
@@ -207,8 +208,8 @@ xprxas (Export Room Surface Coefficients for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func xprxas(R []*ROOM, S []*RMSRF) {
-	if DEBUG {
+func xprxas(R []*ROOM, S []*RMSRF, debug bool, Ferr io.Writer) {
+	if debug {
 		fmt.Printf("--- xprxas\n")
 
 		for _, Room := range R {
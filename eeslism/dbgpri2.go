@@ -17,7 +17,10 @@
 
 package eeslism
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+)
 
 /*
 xprroom (Export Room Coefficients for Debugging)
@@ -49,7 +52,7 @@ xprroom (Export Room Coefficients for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func xprroom(R []*ROOM) {
+func xprroom(R []*ROOM, debug bool, Ferr io.Writer) {
 	var j int
 	var ARN []float64
 	var RMP []float64
@@ -61,7 +64,7 @@ func xprroom(R []*ROOM) {
 	if len(R) > 0 {
 		Room = R[0]
 	}
-	if DEBUG {
+	if debug {
 		fmt.Println("--- xprroom")
 		for i := range R {
 			Room = R[i]
@@ -223,12 +226,12 @@ xprvent (Export Room Ventilation Data for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func xprvent(R []*ROOM) {
+func xprvent(R []*ROOM, debug bool, Ferr io.Writer) {
 	var j int
 	var A *ACHIR
 	var Room *ROOM
 
-	if DEBUG {
+	if debug {
 		fmt.Println("--- xprvent")
 
 		for i := range R {
@@ -94,7 +94,7 @@ dprschtable (Display Schedule Table for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func (Schdl *SCHDL) dprschtable() {
+func (Schdl *SCHDL) dprschtable(debug bool, Ferr io.Writer) {
 
 	Ssn, Wkd, Dh, Dw := Schdl.Seasn, Schdl.Wkdy, Schdl.Dsch, Schdl.Dscw
 
@@ -103,7 +103,7 @@ func (Schdl *SCHDL) dprschtable() {
 	Nsc := len(Dh)
 	Nsw := len(Dw)
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("\n*** dprschtable  ***\n")
 		fmt.Printf("\n=== Schtable end  is=%d  iw=%d  sc=%d  sw=%d\n", Ns, Nw, Nsc, Nsw)
 
@@ -244,13 +244,13 @@ dprschdata (Display Schedule Data for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func dprschdata(Sh []SCH, Sw []SCH) {
+func dprschdata(Sh []SCH, Sw []SCH, debug bool, Ferr io.Writer) {
 	const dmax = 366
 
 	Nsc := len(Sh)
 	Nsw := len(Sw)
 
-	if DEBUG {
+	if debug {
 		fmt.Printf("\n*** dprschdata  ***\n")
 		fmt.Printf("\n== len(Sch)=%d   len(Scw)=%d\n", Nsc, Nsw)
 
@@ -350,7 +350,7 @@ dprachv (Display Room-to-Room Air Change for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func dprachv(Room []ROOM) {
+func dprachv(Room []ROOM, debug bool, Ferr io.Writer) {
 
 	f := func(s io.Writer) {
 		fmt.Fprintln(Ferr, "\n*** dprachv***")
@@ -367,7 +367,7 @@ func dprachv(Room []ROOM) {
 		}
 	}
 
-	if DEBUG {
+	if debug {
 		f(os.Stdout)
 	}
 
@@ -404,12 +404,12 @@ dprexsf (Display External Surface Data for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func (exsfs *EXSFS) dprexsf() {
+func (exsfs *EXSFS) dprexsf(debug bool, Ferr io.Writer) {
 	if exsfs.Exs == nil {
 		return
 	}
 
-	if DEBUG {
+	if debug {
 		fmt.Println("\n*** dprexsf ***")
 		for i, Exs := range exsfs.Exs {
 			fmt.Printf("%2d  %-11s  typ=%c Wa=%6.2f Wb=%5.2f Rg=%4.2f  z=%5.2f edf=%6.2e\n",
@@ -457,8 +457,8 @@ dprwwdata (Display Wall and Window Data for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func (Rmvls *RMVLS) dprwwdata() {
-	if DEBUG {
+func (Rmvls *RMVLS) dprwwdata(debug bool, Ferr io.Writer) {
+	if debug {
 		fmt.Printf("\n*** dprwwdata ***\nWALLdata\n")
 
 		for i, Wall := range Rmvls.Wall {
@@ -532,8 +532,8 @@ dprroomdata (Display Room Data for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func (Rmvls *RMVLS) dprroomdata() {
-	if DEBUG {
+func (Rmvls *RMVLS) dprroomdata(debug bool, Ferr io.Writer) {
+	if debug {
 		fmt.Printf("\n*** dprroomdata ***\n")
 
 		for i, Room := range Rmvls.Room {
@@ -614,8 +614,8 @@ dprballoc (Display Wall Allocation Data for Debugging)
 この関数は、建物の熱的挙動を詳細に分析し、
 シミュレーションの信頼性を確保するための重要なデバッグ機能を提供します。
 */
-func (Rmvls *RMVLS) dprballoc() {
-	if DEBUG {
+func (Rmvls *RMVLS) dprballoc(debug bool, Ferr io.Writer) {
+	if debug {
 		fmt.Println("\n*** dprballoc ***")
 
 		for mw, Mw := range Rmvls.Mw {
@@ -2,11 +2,12 @@
 package eeslism
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
-
-	"os"
 )
 
 /*
@@ -39,130 +40,193 @@ Entry (Main Entry Point for Building Energy Simulation)
 - **収束計算**: `LOOP_MAX`や`VAV_Count_MAX`といったループは、
   非線形な熱的挙動（PCMの相変化、VAVシステムの流量制御など）を持つシステムにおいて、
   計算の収束を確保するために用いられます。
-- **デバッグと検証**: `DEBUG`フラグや`Ferr`ファイルへの出力は、
+- **デバッグと検証**: デバッグ出力（`SIMCONTL.Debug`）やログファイル（`Simulation.Ferr`）への出力は、
   シミュレーションの途中の計算結果を確認し、
   モデルの妥当性を検証したり、問題の特定を行ったりするために用いられます。
 
 この関数は、建物のエネルギー性能を総合的に評価し、
 省エネルギー設計、快適性評価、
 および最適な設備システム設計を行うための中心的な役割を果たします。
+
+入力データの誤りなどでシミュレーションを継続できない場合は、
+`InputError`、`WeatherError`、`ConvergenceError`のいずれかを返します。
 */
-func Entry(InFile string, efl_path string) {
-	var s string
+func Entry(InFile string, efl_path string) error {
+	return EntryContext(context.Background(), InFile, efl_path)
+}
 
-	var Daytm DAYTM
-	var Simc *SIMCONTL
-	var Loc *LOCAT
-	var Wd, Wdd, Wdm WDAT
-	var dminute int
-	var Rmvls *RMVLS
-	var Eqcat *EQCAT
-	var Eqsys *EQSYS
-	var i int
+// EntryContext は ctx がキャンセルされた場合に計算を中断する Entry です。
+// 中断した場合は、それまでの計算結果を出力して ctx.Err() を返します。
+func EntryContext(ctx context.Context, InFile string, efl_path string) error {
+	return NewSimulation(InFile, efl_path).RunContext(ctx)
+}
 
-	/* ============================ */
-
-	var Compnt []*COMPNT
-	var Elout []*ELOUT
-	var Elin []*ELIN
-	var Syseq SYSEQ
-	var Mpath []*MPATH
-	var Plist []*PLIST
-	var Pelm []*PELM
-	var Contl []*CONTL
-	var Ctlif []*CTLIF
-	var Ctlst []*CTLST
-	var key int
-	var Exsf EXSFS       // 外皮
-	var Soldy []float64  // 日集計データ
-	var Solmon []float64 // 月集計データ
+/*
+Run (Run Simulation)
 
-	var uop []*bekt  // uop: opから見たopの位置
-	var ulp []*bekt  // ulp: opから見たlpの位置
-	var ullp []*bekt // ullp: lpから見たlpの位置
-	var ulmp []*bekt // ulmp: lpから見たmpの位置
+入力データの読み込みから結果の出力までのシミュレーション全体を実行します。
+処理の流れは Entry を参照してください。
+実行時の状態は全て sim が保持するため、
+異なる Simulation の Run は並行して呼び出すことができます。
 
-	/*---------------higuchi add-------------------start*/
+Run は Init、Done が true を返すまでの Step、Finalize を順に呼び出すことと同じです。
 
-	var obsn int = 0      // OBSの総数
-	var lpn int = 0       // LP(被受照面)の総数
-	var opn int = 0       // OP(受照面)の総数
-	var mpn int = 0       // MP(受照面)の総数　(OP+OPW)
-	var monten int = 1000 // モンテカルロ法の際の射出数
+シミュレーションを継続できない場合は、プロセスを終了せずにエラーを返します。
+*/
+func (sim *Simulation) Run() (err error) {
+	return sim.RunContext(context.Background())
+}
 
-	var DE float64 = 100.0 // 壁面の分割による微小四角形の辺の長さ
-	var co float64 = 0.0   // 壁面への太陽光線の入射角
+/*
+RunContext (Run Simulation with Context)
 
-	var wap []float64
-	var wip [][]float64
+ctx がキャンセルされた場合に計算を中断する Run です。
+キャンセルは時間ステップの間で確認し、中断した場合は Finalize と同様に
+それまでの計算結果を出力ファイルに書き出して閉じ、ctx.Err() を返します。
+*/
+func (sim *Simulation) RunContext(ctx context.Context) (err error) {
+	defer sim.catch(&err)
+
+	sim.init()
+	for !sim.Done() {
+		if err := ctx.Err(); err != nil {
+			sim.finalize()
+			return err
+		}
+		sim.step()
+	}
+	sim.finalize()
 
-	//var uop, ulp, ullp, ulmp *bekt
+	return nil
+}
 
-	var gp [][]XYZ
-	var gpn int = 50 // 地面の代表点の数（C版と同じデフォルト値）
+/*
+Init (Initialize Simulation)
 
-	var fp1 *os.File // _shadow.gchi : MPの影面積の出力
-	var fp2 *os.File // _I.gchi : MPの日射量の出力
-	var fp3 *os.File // _lwr.gchi : MPの長波長放射量の出力
-	var fp4 *os.File // _ffactor.gchi : MPの形態係数の出力
+入力データを読み込んでモデルを初期化し、最初の時間ステップを計算する直前の状態にします。
 
-	var BDP []*BBDP
-	var obs []*OBS
-	var tree []*TREE     /*-樹木データ-*/
-	var poly []*POLYGN   /*--POLYGON--*/
-	var shadtb []*SHADTB /*-LP面の日射遮蔽率スケジュール-*/
-	var op []*P_MENN     // OP面(受光面)
-	var lp []*P_MENN     // LP面(被受光面)
-	var mp []*P_MENN     // MP面(OP+OPW)
-	var Noplpmp NOPLPMP  // OP、LP、MPの定義数
+Init の後は Done が true を返すまで Step を繰り返し、最後に Finalize を呼び出します。
+Step と Step の間では、Daytm、Wd、Rmvls などのフィールドや Value で計算結果を参照し、
+SetValue、SetSwitch、SetSetpoint で次の時間ステップの気象データや設定値、
+経路の発停を上書きできます。
+*/
+func (sim *Simulation) Init() (err error) {
+	if sim.initialized {
+		return errors.New("eeslism: Init called twice")
+	}
+	defer sim.catch(&err)
 
-	var Datintvl int
-	var dcnt int
-	//var Sdstr []SHADSTR
+	sim.init()
+	return nil
+}
 
-	//var st *C.char
-	//	var Ipath string
+/*
+Step (Simulation Time Step)
 
-	BDP = nil
-	obs = nil
-	tree = nil
-	poly = nil
-	shadtb = nil
-	op = nil
-	lp = nil
-	mp = nil
+1時間ステップ分の計算を行います。
+気象データの読み込み、スケジュールと制御の設定、システム方程式の求解、
+時刻別の結果出力を行い、日の最後の時間ステップでは日集計・月集計を出力します。
 
-	// ここまで修正　satoh 2008/11/8
+エラーを返した後のシミュレーションは継続できません。
+出力ファイルにはそれまでの計算結果を書き出して閉じ、以降の Step、Finalize は同じエラーを返します。
+*/
+func (sim *Simulation) Step() (err error) {
+	switch {
+	case sim.err != nil:
+		return sim.err
+	case !sim.initialized:
+		return errors.New("eeslism: Step called before Init")
+	case sim.finalized || sim.Done():
+		return errors.New("eeslism: Step called after the end of the simulation period")
+	}
+	defer sim.catch(&err)
 
-	/*---------------higuchi add--------------------end*/
+	sim.step()
+	return nil
+}
 
-	Contl = nil
-	Ctlif = nil
-	Ctlst = nil
+// Done はシミュレーション期間の全ての時間ステップの計算が終わったかどうかを返します。
+func (sim *Simulation) Done() bool {
+	return sim.initialized && sim.nday > sim.Simc.Dayend
+}
 
-	Mpath = nil
-	Plist = nil
-	Pelm = nil
-	Elout = nil
-	Elin = nil
-	//Ferr = nil
+// RemainingSteps は残りの時間ステップ数を返します。
+func (sim *Simulation) RemainingSteps() int {
+	if !sim.initialized || sim.Done() {
+		return 0
+	}
+	return sim.mtb - sim.mt + 1 + (sim.Simc.Dayend-sim.nday)*sim.mtb
+}
 
-	Wd.EarthSurface = nil
-	Exsf.EarthSrfFlg = false
-	Exsf.Exs = nil
-	Soldy = nil
-	Fbmlist = ""
+/*
+Finalize (Finalize Simulation)
 
-	Rmvls = NewRMVLS()
-	Simc = NewSIMCONTL()
+月－時刻別集計値を出力し、出力ファイルを書き出して閉じます。
+シミュレーション期間の途中で呼び出した場合は、それまでの計算結果が出力されます。
+*/
+func (sim *Simulation) Finalize() (err error) {
+	switch {
+	case sim.err != nil:
+		return sim.err
+	case !sim.initialized:
+		return errors.New("eeslism: Finalize called before Init")
+	case sim.finalized:
+		return errors.New("eeslism: Finalize called twice")
+	}
+	defer sim.catch(&err)
 
-	Eqsys = NewEQSYS()
-	Loc = NewLOCAT()
-	Eqcat = NewEQCAT()
+	sim.finalize()
+	return nil
+}
 
-	/* ------------------------------------------------------ */
+// catch は panic で送出されたエラーを回収して *err に設定します。
+// 回収したエラーは sim.err に記録し、以降の Step、Finalize で返します。
+// 出力ファイルにはそれまでの計算結果を書き出して閉じます。
+func (sim *Simulation) catch(err *error) {
+	if r := recover(); r != nil {
+		recoverError(r, err)
+		sim.err = *err
+
+		// 出力ファイルを閉じる際のエラーは回収したエラーを優先する
+		defer func() { recover() }()
+		sim.closeOutputs()
+	}
+}
+
+// init は入力データを読み込み、モデルを初期化します。
+func (sim *Simulation) init() {
+	sim.initialized = true
+
+	var s string
+
+	InFile := sim.InFile
+
+	var key int
+
+	/*---------------higuchi add-------------------start*/
+
+	var obsn int = 0     // OBSの総数
+	var obs []*OBS       /*-障害物-*/
+	var tree []*TREE     /*-樹木データ-*/
+	var poly []*POLYGN   /*--POLYGON--*/
+	var shadtb []*SHADTB /*-LP面の日射遮蔽率スケジュール-*/
+	var Noplpmp NOPLPMP  // OP、LP、MPの定義数
 
-	Psyint()
+	sim.monten = 1000 // モンテカルロ法の際の射出数
+	sim.de = 100.0    // 壁面の分割による微小四角形の辺の長さ
+	sim.gpn = 50      // 地面の代表点の数（C版と同じデフォルト値）
+
+	/*---------------higuchi add--------------------end*/
+
+	sim.Rmvls = NewRMVLS()
+	sim.Simc = NewSIMCONTL()
+	sim.files()
+
+	sim.Eqsys = NewEQSYS()
+	sim.Loc = NewLOCAT()
+	sim.Eqcat = NewEQCAT()
+
+	/* ------------------------------------------------------ */
 
 	Ifile := InFile
 	s = Ifile
@@ -176,47 +240,46 @@ func Entry(InFile string, efl_path string) {
 	Ifile = filepath.Dir(Ifile)
 
 	// 注釈文の除去
-	bdata0 := Eesprera(s)
+	bdata0 := Eesprera(s, sim.Simc.FS, sim.Simc.EflFS, sim.Defines, sim.Simc.Output)
 
 	// スケジュ－ルデ－タの作成
 	EWKFile := strings.TrimSuffix(s, filepath.Ext(s))
-	bdata, schtba, schnma, week := Eespre(bdata0, EWKFile, &key) //key=`WEEK`が含まれているかどうか
+	bdata, schtba, schnma, week := Eespre(bdata0, EWKFile, &key, &sim.Fbmlist, sim.Simc.Output) //key=`WEEK`が含まれているかどうか
 
-	Simc.File = InFile
-	Simc.Loc = Loc
+	sim.Simc.File = InFile
+	sim.Simc.Loc = sim.Loc
 
 	// 建築・設備システムデータ入力
-	Schdl, Flout := Eeinput(
+	sim.Schdl, sim.flout = Eeinput(
 		EWKFile,
-		efl_path,
 		bdata, week, schtba, schnma,
-		Simc, &Exsf, Rmvls, Eqcat, Eqsys,
-		&Compnt,
-		&Elout,
-		&Elin,
-		&Mpath,
-		&Plist,
-		&Pelm,
-		&Contl,
-		&Ctlif,
-		&Ctlst,
-		&Wd,
-		&Daytm, key,
-		&obsn, &BDP, &obs, &tree, &shadtb, &poly, &monten, &gpn, &DE, &Noplpmp)
+		sim.Simc, &sim.Exsf, sim.Rmvls, sim.Eqcat, sim.Eqsys,
+		&sim.Compnt,
+		&sim.elout,
+		&sim.elin,
+		&sim.Mpath,
+		&sim.plist,
+		&sim.pelm,
+		&sim.Contl,
+		&sim.ctlif,
+		&sim.ctlst,
+		&sim.Wd,
+		&sim.Daytm, key,
+		&obsn, &sim.bdp, &obs, &tree, &shadtb, &poly, &sim.monten, &sim.gpn, &sim.de, &Noplpmp, sim.Fbmlist, &sim.Ferr)
 
 	// 最大収束回数のセット
-	LOOP_MAX := Simc.MaxIterate
-	VAV_Count_MAX := Simc.MaxIterate
+	sim.loopMax = sim.Simc.MaxIterate
+	sim.vavCountMax = sim.Simc.MaxIterate
 
 	// 動的カーテンの展開
-	for i := range Rmvls.Sd {
-		Sd := Rmvls.Sd[i]
+	for i := range sim.Rmvls.Sd {
+		Sd := sim.Rmvls.Sd[i]
 		if Sd.DynamicCode != "" {
-			ctifdecode(Sd.DynamicCode, Sd.Ctlif, Simc, Compnt, Mpath, &Wd, &Exsf, Schdl)
+			ctifdecode(Sd.DynamicCode, Sd.Ctlif, sim.Simc, sim.Compnt, sim.Mpath, &sim.Wd, &sim.Exsf, sim.Schdl, sim.Ferr)
 		}
 	}
 
-	if len(BDP) != 0 {
+	if len(sim.bdp) != 0 {
 
 		RET := STRCUT(s, ".")
 		RET1 := RET
@@ -230,36 +293,36 @@ func Entry(InFile string, efl_path string) {
 		RET14 += "_lwr.gchi"
 
 		var err error
-		if fp1, err = os.Create(RET); err != nil {
+		if sim.fp1, err = sim.Simc.create(RET); err != nil {
 			fmt.Println("File not open _shadow.gchi")
-			os.Exit(1)
+			panic(err)
 		}
 
-		if fp2, err = os.Create(RET1); err != nil {
+		if sim.fp2, err = sim.Simc.create(RET1); err != nil {
 			fmt.Println("File not open _I.gchi")
-			os.Exit(1)
+			panic(err)
 		}
 
-		if fp3, err = os.Create(RET14); err != nil {
+		if sim.fp3, err = sim.Simc.create(RET14); err != nil {
 			fmt.Println("File not open _lwr.gchi")
-			os.Exit(1)
+			panic(err)
 		}
 
-		if fp4, err = os.Create(RET3); err != nil {
+		if sim.fp4, err = sim.Simc.create(RET3); err != nil {
 			fmt.Println("File not open _ffactor.gchi")
-			os.Exit(1)
+			panic(err)
 		}
 
 		// 座標の変換
 		// 多面体、樹木、障害物、BDPの座標変換し、LP, OPに集約する
-		lp = LP_COORDNT(poly, tree, obs, BDP)
-		op = OP_COORDNT(BDP, poly)
+		sim.lp = LP_COORDNT(poly, tree, obs, sim.bdp)
+		sim.op = OP_COORDNT(sim.bdp, poly)
 
-		lpn = len(lp)
-		opn = len(op)
+		sim.lpn = len(sim.lp)
+		sim.opn = len(sim.op)
 
 		// LPの構造体に日毎の日射遮蔽率を代入
-		for _, _lp := range lp {
+		for _, _lp := range sim.lp {
 			for _, _shadtb := range shadtb {
 				if _lp.opname == _shadtb.lpname {
 					for k := 1; k < 366; k++ {
@@ -276,27 +339,27 @@ func Entry(InFile string, efl_path string) {
 
 		//---- mpの総数をカウント mpは、OP面+OPW面 ---------------
 		// OP面 = 授照面、OPW面 = 受照窓面
-		mpn = 0
-		for i := 0; i < opn; i++ {
-			mpn += 1
-			for j := 0; j < op[i].wd; j++ {
-				mpn += 1
+		sim.mpn = 0
+		for i := 0; i < sim.opn; i++ {
+			sim.mpn += 1
+			for j := 0; j < sim.op[i].wd; j++ {
+				sim.mpn += 1
 			}
 		}
 
 		//---窓壁のカウンター変数の初期化---
-		wap = make([]float64, opn)
-		wip = make([][]float64, opn)
-		for i := 0; i < opn; i++ {
-			if op[i].wd != 0 {
-				wip[i] = make([]float64, op[i].wd)
+		sim.wap = make([]float64, sim.opn)
+		sim.wip = make([][]float64, sim.opn)
+		for i := 0; i < sim.opn; i++ {
+			if sim.op[i].wd != 0 {
+				sim.wip[i] = make([]float64, sim.op[i].wd)
 			}
 		}
 
 		//---領域の確保   gp 地面の座標(X,Y,Z)---
-		gp = make([][]XYZ, mpn)
-		for i := 0; i < mpn; i++ {
-			gp[i] = make([]XYZ, gpn+1)
+		sim.gp = make([][]XYZ, sim.mpn)
+		for i := 0; i < sim.mpn; i++ {
+			sim.gp[i] = make([]XYZ, sim.gpn+1)
 		}
 
 		// //---領域の確保 mp---
@@ -304,76 +367,76 @@ func Entry(InFile string, efl_path string) {
 		// P_MENNinit(mp, mpn)
 
 		//----OP,OPWの構造体をMPへ代入する----
-		mp = DAINYUU_MP(op)
+		sim.mp = DAINYUU_MP(sim.op)
 
-		for i := 0; i < mpn; i++ {
-			fmt.Fprintf(fp1, "%s\n", mp[i].opname)
+		for i := 0; i < sim.mpn; i++ {
+			fmt.Fprintf(sim.fp1, "%s\n", sim.mp[i].opname)
 		}
 
 		//---ベクトルの向きを判別する変数の初期化---
 		//---opから見たopの位置---
-		uop = make([]*bekt, opn)
-		for i := range op {
-			uop[i] = Newbekt(op)
+		sim.uop = make([]*bekt, sim.opn)
+		for i := range sim.op {
+			sim.uop[i] = Newbekt(sim.op)
 		}
 
 		//---opから見たlpの位置---
-		ulp = make([]*bekt, opn)
-		for i := range op {
-			ulp[i] = Newbekt(lp)
+		sim.ulp = make([]*bekt, sim.opn)
+		for i := range sim.op {
+			sim.ulp[i] = Newbekt(sim.lp)
 		}
 
 		//---lpから見たlpの位置---
-		ullp = make([]*bekt, lpn)
-		for i := range lp {
-			ullp[i] = Newbekt(lp)
+		sim.ullp = make([]*bekt, sim.lpn)
+		for i := range sim.lp {
+			sim.ullp[i] = Newbekt(sim.lp)
 		}
 
 		//---lpから見たmpの位置---
-		ulmp = make([]*bekt, lpn)
-		for i := range lp {
-			ulmp[i] = Newbekt(mp)
+		sim.ulmp = make([]*bekt, sim.lpn)
+		for i := range sim.lp {
+			sim.ulmp[i] = Newbekt(sim.mp)
 		}
 
 		//------CG確認用データ作成-------
-		HOUSING_PLACE(lpn, mpn, lp, mp, RET15)
+		HOUSING_PLACE(sim.lpn, sim.mpn, sim.lp, sim.mp, RET15, sim.Simc.Output)
 
 		//----前面地面代表点および壁面の中心点Gを求める--------
-		GRGPOINT(mp, mpn)
-		for _, _lp := range lp {
+		GRGPOINT(sim.mp, sim.mpn)
+		for _, _lp := range sim.lp {
 			_lp.G = GDATA(_lp)
 		}
 
 		// 20170426 higuchi add 条件追加　形態係数を計算しないパターンを組み込んだ
-		if monten > 0 {
+		if sim.monten > 0 {
 			//---LPから見た天空に対する形態係数faia算出------
-			FFACTOR_LP(monten, lp, mp)
+			FFACTOR_LP(sim.monten, sim.lp, sim.mp, sim.rand)
 		}
 
 		// 各MP面に方位、日射吸収率等を壁体情報から取得
-		for _, _mp := range mp {
-			for j := range Rmvls.Sd {
-				if Rmvls.Sd[j].Sname == _mp.opname {
-					_mp.exs = Rmvls.Sd[j].exs
-					_mp.as = Rmvls.Sd[j].as
-					_mp.alo = Rmvls.Sd[j].alo
-					_mp.Eo = Rmvls.Sd[j].Eo
+		for _, _mp := range sim.mp {
+			for j := range sim.Rmvls.Sd {
+				if sim.Rmvls.Sd[j].Sname == _mp.opname {
+					_mp.exs = sim.Rmvls.Sd[j].exs
+					_mp.as = sim.Rmvls.Sd[j].as
+					_mp.alo = sim.Rmvls.Sd[j].alo
+					_mp.Eo = sim.Rmvls.Sd[j].Eo
 					break
 				}
 			}
 		}
 
 		// 前面地面の反射率を取得
-		for i := 0; i < mpn; i++ {
-			mp[i].refg = Exsf.Exs[mp[i].exs].Rg
+		for i := 0; i < sim.mpn; i++ {
+			sim.mp[i].refg = sim.Exsf.Exs[sim.mp[i].exs].Rg
 			//fmt.Printf("mp[%d].refg=%f\n", i, mp[i].refg)
 		}
 
 		//---面の裏か表かの判断をするためのベクトル値の算出--
-		URA(opn, opn, op, uop, op)  //--opから見たopの位置--
-		URA(lpn, lpn, lp, ullp, lp) //--lpから見たlpの位置--
-		URA(lpn, mpn, mp, ulmp, lp) //--lpから見たmpの位置--
-		URA(opn, lpn, lp, ulp, op)  //--opから見たlpの位置--
+		URA(sim.opn, sim.opn, sim.op, sim.uop, sim.op)  //--opから見たopの位置--
+		URA(sim.lpn, sim.lpn, sim.lp, sim.ullp, sim.lp) //--lpから見たlpの位置--
+		URA(sim.lpn, sim.mpn, sim.mp, sim.ulmp, sim.lp) //--lpから見たmpの位置--
+		URA(sim.opn, sim.lpn, sim.lp, sim.ulp, sim.op)  //--opから見たlpの位置--
 
 		// if test {
 		// 	/*---op,lp座標の確認-------*/
@@ -387,88 +450,88 @@ func Entry(InFile string, efl_path string) {
 		// 	errbekt_printf(lpn, mpn, ulmp, RET12)
 		// }
 
-		fmt.Fprintf(fp2, "M\nD\nmt\nname\ngl_shadow\nIsky\nIg\nIb\nIdf\nIdre\n")
-		fmt.Fprintf(fp3, "M\nD\nmt\nname\nRsky\nreff\nreffg\nReff\n")
+		fmt.Fprintf(sim.fp2, "M\nD\nmt\nname\ngl_shadow\nIsky\nIg\nIb\nIdf\nIdre\n")
+		fmt.Fprintf(sim.fp3, "M\nD\nmt\nname\nRsky\nreff\nreffg\nReff\n")
 
 	}
 
 	/*-----------------higuchi add-----------------------------end*/
 
-	if DEBUG {
+	if sim.debug() {
 		fmt.Println("eeinput end")
 
-		for i, Pe := range Pelm {
+		for i, Pe := range sim.pelm {
 			fmt.Printf("[%3d] Pelm=%s\n", i, Pe.Cmp.Name)
 		}
 
-		for i, Eo := range Elout {
+		for i, Eo := range sim.elout {
 			fmt.Printf("[%3d] Eo_cmp=%s\n", i, Eo.Cmp.Name)
 		}
 
 		fmt.Printf("Npelm=%d Ncmalloc=%d Ncompnt=%d Nelout=%d Nelin=%d\n",
-			len(Pelm), len(Compnt), len(Compnt), len(Elout), len(Elin))
+			len(sim.pelm), len(sim.Compnt), len(sim.Compnt), len(sim.elout), len(sim.elin))
 	}
 
-	Soldy = make([]float64, len(Exsf.Exs))
-	Solmon = make([]float64, len(Exsf.Exs))
+	sim.soldy = make([]float64, len(sim.Exsf.Exs))
+	sim.solmon = make([]float64, len(sim.Exsf.Exs))
 
-	DTM = float64(Simc.DTm)
-	dminute = int(float64(Simc.DTm) / 60.0)
-	Cff_kWh = DTM / 3600.0 / 1000.0
+	sim.DTM = float64(sim.Simc.DTm)
+	sim.dminute = int(float64(sim.Simc.DTm) / 60.0)
+	sim.Cff_kWh = sim.DTM / 3600.0 / 1000.0
 
-	for rm := range Rmvls.Room {
-		Rm := Rmvls.Room[rm]
+	for rm := range sim.Rmvls.Room {
+		Rm := sim.Rmvls.Room[rm]
 		Rm.Qeqp = 0.0
 	}
 
 	// スケジュール設定のデバッグ出力
-	Schdl.dprschtable()
+	sim.Schdl.dprschtable(sim.debug(), sim.Ferr)
 
 	// 外表面方位データのデバッグ出力
-	Exsf.dprexsf()
+	sim.Exsf.dprexsf(sim.debug(), sim.Ferr)
 
 	// 壁・窓のデバッグ出力
-	Rmvls.dprwwdata()
+	sim.Rmvls.dprwwdata(sim.debug(), sim.Ferr)
 
 	// 室のデバッグ出力
-	Rmvls.dprroomdata()
+	sim.Rmvls.dprroomdata(sim.debug(), sim.Ferr)
 
 	// 重量壁体のデバッグ出力
-	Rmvls.dprballoc()
+	sim.Rmvls.dprballoc(sim.debug(), sim.Ferr)
 
-	Simc.eeflopen(Flout, efl_path)
+	sim.Simc.eeflopen(sim.flout, sim.Ferr)
 
-	if DEBUG {
+	if sim.debug() {
 		fmt.Println("<<main>> eeflopen ")
 	}
 
-	if Ferr != nil {
-		fmt.Fprintln(Ferr, "\n<<main>> eeflopen end")
+	if sim.Ferr != nil {
+		fmt.Fprintln(sim.Ferr, "\n<<main>> eeflopen end")
 	}
 
 	// 壁体内部温度の初期値設定
-	Rmvls.Tinit()
+	sim.Rmvls.Tinit()
 
-	if DEBUG {
+	if sim.debug() {
 		fmt.Println("<<main>> Tinit")
 	}
 
-	if Ferr != nil {
-		fmt.Fprintln(Ferr, "\n<<main>> Tinit")
+	if sim.Ferr != nil {
+		fmt.Fprintln(sim.Ferr, "\n<<main>> Tinit")
 	}
 
 	// ボイラ機器仕様の初期化
-	Eqcat.Boicaint(Simc, Compnt, &Wd, &Exsf, Schdl)
+	sim.Eqcat.Boicaint(sim.Simc, sim.Compnt, &sim.Wd, &sim.Exsf, sim.Schdl, sim.Ferr)
 
 	// システム使用機器の初期設定
-	Eqsys.Mecsinit(Simc, Compnt, Exsf.Exs, &Wd, Rmvls)
+	sim.Eqsys.Mecsinit(sim.Simc, sim.Compnt, sim.Exsf.Exs, &sim.Wd, sim.Rmvls, sim.DTM, sim.Ferr)
 
-	if DEBUG {
+	if sim.debug() {
 		fmt.Println("<<main>> Mecsinit")
 	}
 
-	if Ferr != nil {
-		fmt.Fprintln(Ferr, "\n<<main>> Mecsinit")
+	if sim.Ferr != nil {
+		fmt.Fprintln(sim.Ferr, "\n<<main>> Mecsinit")
 	}
 
 	/*******************
@@ -477,643 +540,795 @@ func Entry(InFile string, efl_path string) {
 
 	*******************/
 
-	bdhpri(Simc.Ofname, Rmvls, &Exsf)
+	bdhpri(sim.Simc.Ofname, sim.Rmvls, &sim.Exsf, sim.Simc.Output, sim.Ferr)
 
 	// xprtwallinit (Rmvls.Nmwall, Rmvls.Mw);
 
 	/* --------------------------------------------------------- */
 
-	Daytm.Ddpri = 0
+	sim.Daytm.Ddpri = 0
 
-	if Simc.Sttmm < 0 {
-		Simc.Sttmm = dminute
+	if sim.Simc.Sttmm < 0 {
+		sim.Simc.Sttmm = sim.dminute
 	}
 
-	tt := Simc.Sttmm / 100
-	mm := Simc.Sttmm % 100
-	mta := (tt*60 + mm) / dminute
-	mtb := (24 * 60) / dminute
+	sim.tt = sim.Simc.Sttmm / 100
+	sim.mm = sim.Simc.Sttmm % 100
+	sim.mta = (sim.tt*60 + sim.mm) / sim.dminute
+	sim.mtb = (24 * 60) / sim.dminute
 	//mtb = 12;
 	// 110413 higuchi add  影面積をストアして、影計算を10日おきにする
-	Sdstr := make([]*SHADSTR, mpn)
-	for i := 0; i < mpn; i++ {
-		Sdstr[i] = new(SHADSTR)
-		Sdstr[i].sdsum = make([]float64, mtb)
-		for jj := 0; jj < mtb; jj++ {
-			Sdstr[i].sdsum[jj] = 0.0
+	sim.sdstr = make([]*SHADSTR, sim.mpn)
+	for i := 0; i < sim.mpn; i++ {
+		sim.sdstr[i] = new(SHADSTR)
+		sim.sdstr[i].sdsum = make([]float64, sim.mtb)
+		for jj := 0; jj < sim.mtb; jj++ {
+			sim.sdstr[i].sdsum[jj] = 0.0
 		}
 	}
 
-	dcnt = 0
+	sim.dcnt = 0
+
+	sim.nday = sim.Simc.Daystartx
+	sim.beginDay()
+	sim.nsteps = sim.RemainingSteps()
+}
 
-	for nday := Simc.Daystartx; nday <= Simc.Dayend; nday++ {
-		if dcnt == Datintvl {
-			dcnt = 0
-			MATINIT_sdstr(mpn, mtb, Sdstr)
+// beginDay は日ループの始めの処理を行い、当日の最初の時間ステップに進みます。
+// 計算する時間ステップがない日は、日集計を出力して翌日に進みます。
+func (sim *Simulation) beginDay() {
+	Simc := sim.Simc
+	Daytm := &sim.Daytm
+
+	for ; sim.nday <= Simc.Dayend; sim.nday++ {
+		if sim.dcnt == sim.datintvl {
+			sim.dcnt = 0
+			MATINIT_sdstr(sim.mpn, sim.mtb, sim.sdstr)
 		}
-		dcnt++
+		sim.dcnt++
 
-		if dayprn && Ferr != nil {
-			fmt.Fprintf(Ferr, "\n\n\t===== Dayly Loop =====\n\n")
+		if sim.dayprn && sim.Ferr != nil {
+			fmt.Fprintf(sim.Ferr, "\n\n\t===== Dayly Loop =====\n\n")
 		}
 
-		day := ((nday - 1) % 365) + 1
-		if Simc.Perio == 'y' {
-			day = Simc.Daystart
+		if Simc.BaseYear > 0 {
+			// 実暦による計算。気象データ、スケジュールなどは標準年の通日による（2/29 は 2/28）
+			sim.calendarDay()
+		} else {
+			sim.day = ((sim.nday - 1) % 365) + 1
+			if Simc.Perio == 'y' {
+				sim.day = Simc.Daystart
+			}
 		}
-		Daytm.DayOfYear = day
+		Daytm.DayOfYear = sim.day
 
-		dayprn = Simc.Dayprn[day] != 0
+		sim.dayprn = Simc.Dayprn[sim.day] != 0
 
-		if Simc.Perio != 'y' && nday > Simc.Daystartx {
+		if Simc.BaseYear == 0 && Simc.Perio != 'y' && sim.nday > Simc.Daystartx {
 			Daytm.Mon, Daytm.Day = monthday(Daytm.Mon, Daytm.Day)
 		}
 
-		if nday >= Simc.Daystart {
+		if sim.nday >= Simc.Daystart {
 			Daytm.Ddpri = 1
 		}
-		if Simc.Perio == 'y' && nday != Simc.Dayend {
+		if Simc.Perio == 'y' && sim.nday != Simc.Dayend {
 			Daytm.Ddpri = 0
 		}
 
-		if nday > Simc.Daystartx {
-			mta = 1
+		if sim.nday > Simc.Daystartx {
+			sim.mta = 1
 		}
 
-		for mt := mta; mt <= mtb; mt++ {
-			if dayprn && Ferr != nil {
-				fmt.Fprintf(Ferr, "\n\n\t===== Timely Loop =====\n\n")
-			}
+		sim.mt = sim.mta
+		if sim.mt <= sim.mtb {
+			return
+		}
+		sim.endDay()
+	}
+}
 
-			if mm >= 60 {
-				mm -= 60
-				tt++
-			}
+// calendarDay は実暦による計算の通日 nday の年月日と標準年の通日 day を設定します。
+// 年が変わる日と 2/29 の前後では、その年の暦の曜日によりスケジュールを作り直します。
+func (sim *Simulation) calendarDay() {
+	Simc := sim.Simc
+	Daytm := &sim.Daytm
+
+	year, mon, day := Simc.Date(sim.nday)
+	leap := mon == 2 && day == 29
+	if year != Daytm.Year || leap || (Daytm.Mon == 2 && Daytm.Day == 29) {
+		Simc.setDaywk(year, leap)
+		sim.Schdl.setDaywk(Simc.Daywk)
+	}
+	Daytm.Year, Daytm.Mon, Daytm.Day = year, mon, day
+	sim.day = standardDay(mon, day)
+}
 
-			if tt > 24 || (tt == 24 && mm > 0) {
-				tt -= 24
-			}
+// dayKeys は日集計、月集計の日の区切りに用いる当日と計算終了日の識別値を返します。
+// 実暦による計算では年月日 (yyyymmdd)、それ以外は標準年の通日です。ref: IsEndDay
+func (sim *Simulation) dayKeys() (day, dayend int) {
+	if sim.Simc.BaseYear > 0 {
+		return sim.Simc.dateKey(sim.nday), sim.Simc.dateKey(sim.Simc.Dayend)
+	}
+	return sim.day, sim.Simc.Dayend
+}
 
-			Daytm.Tt = tt
-			Daytm.Ttmm = tt*100 + mm
-			Daytm.Time = float64(Daytm.Ttmm) / 100.0
+// step は1時間ステップ分の計算を行います。
+func (sim *Simulation) step() {
+	var i int
+	var co float64 // 壁面への太陽光線の入射角
+
+	Daytm := &sim.Daytm
+	Simc := sim.Simc
+	Loc := sim.Loc
+	Wd := &sim.Wd
+	Exsf := &sim.Exsf
+	Rmvls := sim.Rmvls
+	Eqsys := sim.Eqsys
+	Schdl := sim.Schdl
+	Compnt := sim.Compnt
+	Mpath := sim.Mpath
+	Contl := sim.Contl
+	Elout := sim.elout
+	Elin := sim.elin
+	Syseq := &sim.syseq
+	Flout := sim.flout
+	Wdd := &sim.wdd
+	Wdm := &sim.wdm
+
+	if sim.dayprn && sim.Ferr != nil {
+		fmt.Fprintf(sim.Ferr, "\n\n\t===== Timely Loop =====\n\n")
+	}
 
-			if DEBUG {
-				fmt.Printf("<< main >> nday=%d mm=%d mt=%d  tt=%d mm=%d\n", nday, mm, mt, tt, mm)
-			}
+	if sim.mm >= 60 {
+		sim.mm -= 60
+		sim.tt++
+	}
 
-			//if (day == 16 && Daytm.ttmm == 800)
-			//  fmt.Printf("xxxxxx\n")
+	if sim.tt > 24 || (sim.tt == 24 && sim.mm > 0) {
+		sim.tt -= 24
+	}
 
-			Vcfinput(&Daytm, Simc.Nvcfile, Simc.Vcfile, Simc.Perio)
-			Weatherdt(Simc, &Daytm, Loc, &Wd, Exsf.Exs, Exsf.EarthSrfFlg)
+	Daytm.Tt = sim.tt
+	Daytm.Ttmm = sim.tt*100 + sim.mm
+	Daytm.Time = float64(Daytm.Ttmm) / 100.0
 
-			if dayprn && Ferr != nil {
-				fmt.Fprintf(Ferr, "\n\n\n---- date=%2d/%2d nday=%d day=%d time=%5.2f ----\n",
-					Daytm.Mon, Daytm.Day, nday, day, Daytm.Time)
-			}
+	if Simc.Debug {
+		fmt.Printf("<< main >> nday=%d mm=%d mt=%d  tt=%d mm=%d\n", sim.nday, sim.mm, sim.mt, sim.tt, sim.mm)
+	}
 
-			if DEBUG {
-				fmt.Printf("---- date=%2d %2d nday=%d day=%d time=%5.2f ----\n",
-					Daytm.Mon, Daytm.Day, nday, day, Daytm.Time)
-			}
+	//if (day == 16 && Daytm.ttmm == 800)
+	//  fmt.Printf("xxxxxx\n")
+
+	sim.Vcfinput(Daytm, Simc.Nvcfile, Simc.Vcfile, Simc.Perio)
+	sim.Weatherdt(Simc, Daytm, Loc, Wd, Exsf.Exs, Exsf.EarthSrfFlg)
 
-			if dayprn && Ferr != nil {
-				Flinprt(Eqsys.Flin)
+	// Step の間に上書きされた気象データの設定
+	sim.applyOverrides()
+
+	if sim.dayprn && sim.Ferr != nil {
+		fmt.Fprintf(sim.Ferr, "\n\n\n---- date=%2d/%2d nday=%d day=%d time=%5.2f ----\n",
+			Daytm.Mon, Daytm.Day, sim.nday, sim.day, Daytm.Time)
+	}
+
+	if Simc.Debug {
+		fmt.Printf("---- date=%2d %2d nday=%d day=%d time=%5.2f ----\n",
+			Daytm.Mon, Daytm.Day, sim.nday, sim.day, Daytm.Time)
+	}
+
+	if sim.dayprn && sim.Ferr != nil {
+		Flinprt(Eqsys.Flin, Simc.Debug, sim.Ferr)
+	}
+
+	/***   if (Daytm.ttmm == 100 )****/
+	if sim.mt == sim.mta {
+		if sim.ProgressFunc == nil {
+			fmt.Printf("%d/%d", Daytm.Mon, Daytm.Day)
+			if sim.nday < Simc.Daystart {
+				fmt.Printf(")")
+			}
+			if Daytm.Ddpri != 0 && Simc.Dayprn[sim.day] != 0 {
+				fmt.Printf(" *")
 			}
+			fmt.Printf("\n")
+		}
 
-			/***   if (Daytm.ttmm == 100 )****/
-			if mt == mta {
-				fmt.Printf("%d/%d", Daytm.Mon, Daytm.Day)
-				if nday < Simc.Daystart {
-					fmt.Printf(")")
-				}
-				if Daytm.Ddpri != 0 && Simc.Dayprn[day] != 0 {
-					fmt.Printf(" *")
-				}
-				fmt.Printf("\n")
-
-				/*------------------------higuchi add---形態係数の算出---------start*/
-				//fmt.Printf("nday=%d,day=%d\n",nday,day) ;
-				//fmt.Printf("bdpn=%d\n",bdpn) ;
-
-				// 20170426 higuchi add 形態係数を計算しない処理の追加
-				if len(BDP) != 0 && monten > 0 {
-					if nday == Simc.Daystartx {
-						fmt.Printf("form_factor calcuration start (monten=%d, lpn=%d, mpn=%d)\n", monten, lpn, mpn)
-						GR_MONTE_CARLO(mp, mpn, lp, lpn, monten, day)
-						MONTE_CARLO(mpn, lpn, monten, mp, lp, gp, gpn, day, Simc.Daystartx)
-						ffactor_printf(fp4, mpn, lpn, mp, lp, Daytm.Mon, Daytm.Day)
+		/*------------------------higuchi add---形態係数の算出---------start*/
+		//fmt.Printf("nday=%d,day=%d\n",nday,day) ;
+		//fmt.Printf("bdpn=%d\n",bdpn) ;
+
+		// 20170426 higuchi add 形態係数を計算しない処理の追加
+		if len(sim.bdp) != 0 && sim.monten > 0 {
+			if sim.nday == Simc.Daystartx {
+				fmt.Printf("form_factor calcuration start (monten=%d, lpn=%d, mpn=%d)\n", sim.monten, sim.lpn, sim.mpn)
+				GR_MONTE_CARLO(sim.mp, sim.mpn, sim.lp, sim.lpn, sim.monten, sim.day, sim.rand)
+				MONTE_CARLO(sim.mpn, sim.lpn, sim.monten, sim.mp, sim.lp, sim.gp, sim.gpn, sim.day, Simc.Daystartx, sim.rand)
+				ffactor_printf(sim.fp4, sim.mpn, sim.lpn, sim.mp, sim.lp, Daytm.Mon, Daytm.Day)
+				fmt.Printf("form_factor calcuration end\n")
+			} else {
+				for i := 0; i < sim.lpn; i++ {
+					k := sim.day - 1
+					if k == 0 {
+						k = 365
+					}
+					if sim.lp[i].shad[sim.day] != sim.lp[i].shad[k] {
+						fmt.Printf("form_factor calcuration start:shad[%d]=%f,shad[%d]=%f\n", sim.nday, sim.lp[i].shad[sim.day], k, sim.lp[i].shad[k])
+						GR_MONTE_CARLO(sim.mp, sim.mpn, sim.lp, sim.lpn, sim.monten, sim.day, sim.rand)
+						MONTE_CARLO(sim.mpn, sim.lpn, sim.monten, sim.mp, sim.lp, sim.gp, sim.gpn, sim.day, Simc.Daystartx, sim.rand)
+						ffactor_printf(sim.fp4, sim.mpn, sim.lpn, sim.mp, sim.lp, Daytm.Mon, Daytm.Day)
 						fmt.Printf("form_factor calcuration end\n")
-					} else {
-						for i := 0; i < lpn; i++ {
-							k := day - 1
-							if k == 0 {
-								k = 365
-							}
-							if lp[i].shad[day] != lp[i].shad[k] {
-								fmt.Printf("form_factor calcuration start:shad[%d]=%f,shad[%d]=%f\n", nday, lp[i].shad[day], k, lp[i].shad[k])
-								GR_MONTE_CARLO(mp, mpn, lp, lpn, monten, day)
-								MONTE_CARLO(mpn, lpn, monten, mp, lp, gp, gpn, day, Simc.Daystartx)
-								ffactor_printf(fp4, mpn, lpn, mp, lp, Daytm.Mon, Daytm.Day)
-								fmt.Printf("form_factor calcuration end\n")
-								break
-							}
-						}
+						break
 					}
-
-				}
-				/*------------------------higuchi add-----------------------end*/
-
-				if DEBUG {
-					fmt.Printf(" ** daymx=%d  Tgrav=%f  DT=%f  Tsupw=%f\n",
-						Loc.Daymxert, Loc.Tgrav, Loc.DTgr, Wd.Twsup)
 				}
 			}
 
-			// 傾斜面日射量の計算
-			Exsf.Exsfsol(&Wd)
+		}
+		/*------------------------higuchi add-----------------------end*/
 
-			/*==transplantation to eeslism from KAGExSUN by higuchi 070918==start*/
-			if len(BDP) != 0 {
+		if Simc.Debug {
+			fmt.Printf(" ** daymx=%d  Tgrav=%f  DT=%f  Tsupw=%f\n",
+				Loc.Daymxert, Loc.Tgrav, Loc.DTgr, Wd.Twsup)
+		}
+	}
 
-				MATINIT_sum(opn, op)
-				MATINIT_sum(mpn, mp)
-				MATINIT_sum(lpn, lp)
+	// 傾斜面日射量の計算
+	Exsf.Exsfsol(Wd)
 
-				ls := -Wd.Sw
-				ms := -Wd.Ss
-				ns := Wd.Sh
+	/*==transplantation to eeslism from KAGExSUN by higuchi 070918==start*/
+	if len(sim.bdp) != 0 {
 
-				if Wd.Sh > 0.0 {
+		MATINIT_sum(sim.opn, sim.op)
+		MATINIT_sum(sim.mpn, sim.mp)
+		MATINIT_sum(sim.lpn, sim.lp)
 
-					// 110413 higuchi add 下の条件
-					if dcnt == 1 {
+		ls := -Wd.Sw
+		ms := -Wd.Ss
+		ns := Wd.Sh
 
-						for j := 0; j < opn; j++ {
+		if Wd.Sh > 0.0 {
 
-							wap[j] = 0.0
-							for i := 0; i < op[j].wd; i++ {
-								wip[j][i] = 0.0
-							}
-							CINC(op[j], ls, ms, ns, &co)
-							if co > 0.0 {
-								SHADOW(j, DE, opn, lpn, ls, ms, ns, uop[j], ulp[j], op[j], op, lp, &wap[j], wip[j], day)
-							} else {
-								op[j].sum = 1.0
-								for i := 0; i < op[j].wd; i++ {
-									op[j].opw[i].sumw = 1.0
-								}
-							}
-						}
-						//fmt.Printf("dcnt1=%d\n",dcnt) ;
-						DAINYUU_SMO2(opn, mpn, op, mp, Sdstr, dcnt, mt)
+			// 110413 higuchi add 下の条件
+			if sim.dcnt == 1 {
 
-						// 20170426 higuchi add 条件追加
-						if dayprn {
-							// 陰面積の出力
-							shadow_printf(fp1, Daytm.Mon, Daytm.Day, Daytm.Time, mpn, mp)
-						}
+				for j := 0; j < sim.opn; j++ {
 
+					sim.wap[j] = 0.0
+					for i := 0; i < sim.op[j].wd; i++ {
+						sim.wip[j][i] = 0.0
+					}
+					CINC(sim.op[j], ls, ms, ns, &co)
+					if co > 0.0 {
+						SHADOW(j, sim.de, sim.opn, sim.lpn, ls, ms, ns, sim.uop[j], sim.ulp[j], sim.op[j], sim.op, sim.lp, &sim.wap[j], sim.wip[j], sim.day)
 					} else {
-						//fmt.Printf("dcnt2=%d\n",dcnt) ;
-						DAINYUU_SMO2(opn, mpn, op, mp, Sdstr, dcnt, mt)
-						// 20170426 higuchi add 条件追加
-						if dayprn {
-							// 陰面積の出力
-							shadow_printf(fp1, Daytm.Mon, Daytm.Day, Daytm.Time, mpn, mp)
+						sim.op[j].sum = 1.0
+						for i := 0; i < sim.op[j].wd; i++ {
+							sim.op[j].opw[i].sumw = 1.0
 						}
 					}
-
-					//SHADSTR *Sdstrd;
-					//Sdstrd = Sdstr;
-					//for (i = 0; i < mpn; i++, Sdstrd++)
-					//{
-					//  int m;
-					//  for (m = 0; m < mtb; m++)
-					//      printf("Sdstr[%d].sdsum[%d]=%f\n", i, m, Sdstrd->sdsum[m]);
-					//}
 				}
+				//fmt.Printf("dcnt1=%d\n",dcnt) ;
+				DAINYUU_SMO2(sim.opn, sim.mpn, sim.op, sim.mp, sim.sdstr, sim.dcnt, sim.mt)
 
 				// 20170426 higuchi add 条件追加
-				if dayprn {
-					fmt.Fprintf(fp2, "%d %d %5.2f\n", Daytm.Mon, Daytm.Day, Daytm.Time)
-					fmt.Fprintf(fp3, "%d %d %5.2f\n", Daytm.Mon, Daytm.Day, Daytm.Time)
+				if sim.dayprn {
+					// 陰面積の出力
+					shadow_printf(sim.fp1, Daytm.Mon, Daytm.Day, Daytm.Time, sim.mpn, sim.mp)
 				}
 
-				// 20170426 higuchi add 引数追加 dayprn,monten
-				OPIhor(fp2, fp3, lpn, mpn, mp, lp, &Wd, ullp, ulmp, gp, day, monten)
-				// DEBUG: Check mp values after OPIhor
-				if mpn > 0 {
-					fmt.Printf("DEBUG after OPIhor (%d/%d tt=%d): Idre=%f, Idf=%f, Iw=%f, Reff=%f, sum=%f\n",
-						Daytm.Mon, Daytm.Day, tt, mp[0].Idre, mp[0].Idf, mp[0].Iw, mp[0].Reff, mp[0].sum)
-				}
-				for i := range Rmvls.Sd {
-					if Rmvls.Sd[i].Sname != "" {
-						for j := 0; j < mpn; j++ {
-							if Rmvls.Sd[i].Sname == mp[j].opname {
-								Rmvls.Sd[i].Fsdw = mp[j].sum
-								//fmt.Printf("Sd->Fswd=%f\n", Rmvls.Sd[i].Fsdw)
-								Rmvls.Sd[i].Idre = mp[j].Idre
-								Rmvls.Sd[i].Idf = mp[j].Idf
-								Rmvls.Sd[i].Iw = mp[j].Iw
-								Rmvls.Sd[i].rn = mp[j].Reff
-								//fmt.Printf("Sd->ali=%f\n", Rmvls.Sd[i].ali)
-								break
-							}
-						}
-					}
+			} else {
+				//fmt.Printf("dcnt2=%d\n",dcnt) ;
+				DAINYUU_SMO2(sim.opn, sim.mpn, sim.op, sim.mp, sim.sdstr, sim.dcnt, sim.mt)
+				// 20170426 higuchi add 条件追加
+				if sim.dayprn {
+					// 陰面積の出力
+					shadow_printf(sim.fp1, Daytm.Mon, Daytm.Day, Daytm.Time, sim.mpn, sim.mp)
 				}
 			}
 
-			/*===============higuchi 070918============================end*/
-			if dayprn && Ferr != nil {
-				xprsolrd(Exsf.Exs)
+			// glTF 出力用の日影面積率の記録
+			if sim.GeometryShadow && sim.dayprn {
+				sim.recordShadow(Daytm)
 			}
 
-			if DEBUG {
-				xprsolrd(Exsf.Exs)
-				fmt.Println("<<main>> Exsfsol")
-			}
+			//SHADSTR *Sdstrd;
+			//Sdstrd = Sdstr;
+			//for (i = 0; i < mpn; i++, Sdstrd++)
+			//{
+			//  int m;
+			//  for (m = 0; m < mtb; m++)
+			//      printf("Sdstr[%d].sdsum[%d]=%f\n", i, m, Sdstrd->sdsum[m]);
+			//}
+		}
 
-			// 現時刻ステップのスケジュール作成
-			Eeschdlr(day, Daytm.Ttmm, Schdl, Rmvls)
+		// 20170426 higuchi add 条件追加
+		if sim.dayprn {
+			fmt.Fprintf(sim.fp2, "%d %d %5.2f\n", Daytm.Mon, Daytm.Day, Daytm.Time)
+			fmt.Fprintf(sim.fp3, "%d %d %5.2f\n", Daytm.Mon, Daytm.Day, Daytm.Time)
+		}
 
-			if DEBUG {
-				fmt.Println("<<main>>  Eeschdlr")
+		// 20170426 higuchi add 引数追加 dayprn,monten
+		OPIhor(sim.fp2, sim.fp3, sim.lpn, sim.mpn, sim.mp, sim.lp, Wd, sim.ullp, sim.ulmp, sim.gp, sim.day, sim.monten, sim.dayprn)
+		// DEBUG: Check mp values after OPIhor
+		if sim.mpn > 0 {
+			fmt.Printf("DEBUG after OPIhor (%d/%d tt=%d): Idre=%f, Idf=%f, Iw=%f, Reff=%f, sum=%f\n",
+				Daytm.Mon, Daytm.Day, sim.tt, sim.mp[0].Idre, sim.mp[0].Idf, sim.mp[0].Iw, sim.mp[0].Reff, sim.mp[0].sum)
+		}
+		for i := range Rmvls.Sd {
+			if Rmvls.Sd[i].Sname != "" {
+				for j := 0; j < sim.mpn; j++ {
+					if Rmvls.Sd[i].Sname == sim.mp[j].opname {
+						Rmvls.Sd[i].Fsdw = sim.mp[j].sum
+						//fmt.Printf("Sd->Fswd=%f\n", Rmvls.Sd[i].Fsdw)
+						Rmvls.Sd[i].Idre = sim.mp[j].Idre
+						Rmvls.Sd[i].Idf = sim.mp[j].Idf
+						Rmvls.Sd[i].Iw = sim.mp[j].Iw
+						Rmvls.Sd[i].rn = sim.mp[j].Reff
+						//fmt.Printf("Sd->ali=%f\n", Rmvls.Sd[i].ali)
+						break
+					}
+				}
 			}
+		}
+	}
 
-			// 制御で使用する状態値を計算する（集熱器の相当外気温度）
-			CalcControlStatus(Eqsys, Rmvls, &Wd, &Exsf)
+	/*===============higuchi 070918============================end*/
+	if sim.dayprn && sim.Ferr != nil {
+		xprsolrd(Exsf.Exs, Simc.Debug, sim.Ferr)
+	}
 
-			// 制御情報の更新
-			Contlschdlr(Contl, Mpath, Compnt)
+	if Simc.Debug {
+		xprsolrd(Exsf.Exs, Simc.Debug, sim.Ferr)
+		fmt.Println("<<main>> Exsfsol")
+	}
 
-			// 空調発停スケジュール設定が完了したら人体発熱を再計算
-			for _, rm := range Rmvls.Room {
-				rm.Qischdlr()
-			}
+	// 現時刻ステップのスケジュール作成
+	Eeschdlr(sim.day, Daytm.Ttmm, Schdl, Rmvls, Simc.Debug, sim.Ferr)
 
-			if DEBUG {
-				fmt.Println("<<main>> Contlschdlr")
-			}
+	if Simc.Debug {
+		fmt.Println("<<main>>  Eeschdlr")
+	}
 
-			/***
-			eloutprint(0, Nelout, Elout, Compnt);
-			*****/
+	// Step の間に上書きされた値の設定
+	sim.applyOverrides()
 
-			// カウンターリセット
-			Eqsys.VAVcountreset()
-			Eqsys.Valvcountreset()
-			Eqsys.Evaccountreset()
+	// 制御で使用する状態値を計算する（集熱器の相当外気温度）
+	CalcControlStatus(Eqsys, Rmvls, Wd, Exsf)
 
-			/*---- Satoh Debug VAV  2000/12/6 ----*/
-			// ここから: VAV 計算繰り返しループ
-			for j := 0; j < VAV_Count_MAX; j++ {
-				if DEBUG {
-					fmt.Printf("\n\n====== VAV LOOP Count=%d ======\n\n\n", j)
-				}
-				if dayprn && Ferr != nil {
-					fmt.Fprintf(Ferr, "\n\n====== VAV LOOP Count=%d ======\n\n\n", j)
-				}
+	// 制御情報の更新
+	Contlschdlr(Contl, Mpath, Compnt)
+	sim.applyOverrides()
+	Ldschdlr(Mpath, Compnt, sim.DTM)
 
-				VAVreset := 0
-				Valvreset := 0
+	// 空調発停スケジュール設定が完了したら人体発熱を再計算
+	for _, rm := range Rmvls.Room {
+		rm.Qischdlr(Simc.Debug, sim.Ferr)
+	}
 
-				// ポンプ流量設定（太陽電池ポンプのみ
-				Eqsys.Pumpflow()
+	if Simc.Debug {
+		fmt.Println("<<main>> Contlschdlr")
+	}
 
-				if DEBUG {
-					fmt.Println("<<main>> Pumpflow")
-				}
+	/***
+	eloutprint(0, Nelout, Elout, Compnt);
+	*****/
+
+	// カウンターリセット
+	Eqsys.VAVcountreset()
+	Eqsys.Valvcountreset()
+	Eqsys.Evaccountreset()
+
+	/*---- Satoh Debug VAV  2000/12/6 ----*/
+	// ここから: VAV 計算繰り返しループ
+	sim.loopCount, sim.vavCount = 0, 0
+	for j := 0; j < sim.vavCountMax; j++ {
+		sim.vavCount = j + 1
+		if Simc.Debug {
+			fmt.Printf("\n\n====== VAV LOOP Count=%d ======\n\n\n", j)
+		}
+		if sim.dayprn && sim.Ferr != nil {
+			fmt.Fprintf(sim.Ferr, "\n\n====== VAV LOOP Count=%d ======\n\n\n", j)
+		}
 
-				if Simc.Dayprn[day] != 0 && Ferr != nil {
-					fmt.Fprintln(Ferr, "<<main>> Pumpflow")
-				}
+		VAVreset := 0
+		Valvreset := 0
 
-				Pflow(Mpath, &Wd)
+		// ポンプ流量設定（太陽電池ポンプのみ
+		Eqsys.Pumpflow(Simc.Debug)
 
-				if DEBUG {
-					fmt.Println("<<main>> Pflow")
-				}
+		if Simc.Debug {
+			fmt.Println("<<main>> Pumpflow")
+		}
 
-				if dayprn && Ferr != nil {
-					fmt.Fprintln(Ferr, "<<main>> Pflow")
-				}
+		if Simc.Dayprn[sim.day] != 0 && sim.Ferr != nil {
+			fmt.Fprintln(sim.Ferr, "<<main>> Pumpflow")
+		}
 
-				/************
-				eloutprint(0, Nelout, Elout, Compnt);
-				***********/
+		Pflow(Mpath, Wd, Simc.Debug, sim.dlog())
 
-				Sysupv(Mpath, Rmvls)
+		if Simc.Debug {
+			fmt.Println("<<main>> Pflow")
+		}
 
-				if DEBUG {
-					fmt.Println("<<main>> Sysupv")
-				}
+		if sim.dayprn && sim.Ferr != nil {
+			fmt.Fprintln(sim.Ferr, "<<main>> Pflow")
+		}
 
-				if dayprn && Ferr != nil {
-					fmt.Fprintln(Ferr, "<<main>> Sysupv")
-				}
+		/************
+		eloutprint(0, Nelout, Elout, Compnt);
+		***********/
 
-				/*****
-				elinprint(0, Compnt, Elout, Elin);
-				***********/
+		Sysupv(Mpath, Rmvls, Simc.Debug, sim.dlog())
 
-				for i := range Rmvls.Room {
-					Rmvls.Emrk[i] = '!'
-				}
+		if Simc.Debug {
+			fmt.Println("<<main>> Sysupv")
+		}
 
-				for n := range Rmvls.Sd {
-					Rmvls.Sd[n].mrk = '!'
-				}
+		if sim.dayprn && sim.Ferr != nil {
+			fmt.Fprintln(sim.Ferr, "<<main>> Sysupv")
+		}
 
-				// システム使用機器特性式係数の計算
-				Eqsys.Mecscf()
+		/*****
+		elinprint(0, Compnt, Elout, Elin);
+		***********/
 
-				if DEBUG {
-					fmt.Println("<<main>> Mecscf")
-				}
+		for i := range Rmvls.Room {
+			Rmvls.Emrk[i] = '!'
+		}
 
-				/*======higuchi update 070918==========*/
-				eeroomcf(&Wd, &Exsf, Rmvls, nday, mt)
-				/*=====================================*/
+		for n := range Rmvls.Sd {
+			Rmvls.Sd[n].mrk = '!'
+		}
 
-				if DEBUG {
-					fmt.Println("<<main>> eeroomcf")
-				}
+		// システム使用機器特性式係数の計算
+		Eqsys.Mecscf(sim.DTM, Simc.Debug, sim.Ferr)
 
-				/*   作用温度制御時の設定室内空気温度  */
-				Rmotset(Rmvls.Room)
-				if DEBUG {
-					fmt.Println("<<main>> Rmotset End")
-				}
+		if Simc.Debug {
+			fmt.Println("<<main>> Mecscf")
+		}
 
-				/* 室、放射パネルのシステム方程式作成 */
-				Roomvar(Rmvls.Room, Rmvls.Rdpnl)
+		/*======higuchi update 070918==========*/
+		sim.eeroomcf(Wd, Exsf, Rmvls, sim.nday, sim.mt)
+		/*=====================================*/
 
-				if DEBUG {
-					fmt.Println("<<main>> Roomvar")
-					eloutprint(1, Elout, Compnt)
-					elinprint(1, Compnt, Elout, Elin)
-				}
+		if Simc.Debug {
+			fmt.Println("<<main>> eeroomcf")
+		}
 
-				if dayprn && Ferr != nil {
-					fmt.Fprintf(Ferr, "<<main>> Roomvar\n")
-					eloutfprint(1, Elout, Compnt)
-					elinfprint(1, Compnt, Elout, Elin)
-				}
-				//eloutprint(1, Nelout, Elout, Compnt);
+		/*   作用温度制御時の設定室内空気温度  */
+		sim.Rmotset(Rmvls.Room)
+		if Simc.Debug {
+			fmt.Println("<<main>> Rmotset End")
+		}
 
-				//hcldmodeinit(&Eqsys);
+		/* 室、放射パネルのシステム方程式作成 */
+		Roomvar(Rmvls.Room, Rmvls.Rdpnl, sim.Ferr)
 
-				// 収束計算
-				for i := 0; i < LOOP_MAX; i++ {
-					if i == 0 {
-						hcldwetmdreset(Eqsys)
-					}
+		if Simc.Debug {
+			fmt.Println("<<main>> Roomvar")
+			eloutprint(1, Elout, Compnt)
+			elinprint(1, Compnt, Elout, Elin)
+		}
 
-					if DEBUG {
-						fmt.Printf("再計算が必要な機器のループ %d\n", i)
-					}
+		if sim.dayprn && sim.Ferr != nil {
+			fmt.Fprintf(sim.Ferr, "<<main>> Roomvar\n")
+			eloutfprint(1, Elout, Compnt, sim.Ferr)
+			elinfprint(1, Compnt, Elout, Elin, sim.Ferr)
+		}
+		//eloutprint(1, Nelout, Elout, Compnt);
 
-					if dayprn && Ferr != nil {
-						fmt.Fprintf(Ferr, "再計算が必要な機器のループ %d\n\n\n", i)
-					}
+		//hcldmodeinit(&Eqsys);
 
-					LDreset := 0
-					DWreset := 0
-					TKreset := 0
-					BOIreset := 0
-					Evacreset := 0
-					PCMfunreset := 0
-
-					/********************************
-					if ( TKreset > 0 )
-					fmt.Printf("<< main >> nday=%d mt=%d  tt=%d mm=%d TKreset=%d\n",
-					nday, mt, tt, mm, TKreset );
-					****************************/
-
-					// 蓄熱槽特性式係数
-					Stankcfv(Eqsys.Stank)
-
-					// 特性式の係数
-					// DEBUG: Hcload スライスの長さを確認（1回だけ出力）
-					if j == 0 && Daytm.Mon == 4 && Daytm.Day == 15 {
-						fmt.Printf("DEBUG e79: Mon=%d Day=%d Time=%.1f len(Eqsys.Hcload)=%d\n", Daytm.Mon, Daytm.Day, Daytm.Time, len(Eqsys.Hcload))
-						for idx, hl := range Eqsys.Hcload {
-							if hl.Cmp != nil {
-								fmt.Printf("DEBUG e79: Hcload[%d] name=%s Wetmode=%v Wet=%v RHout=%.1f\n", idx, hl.Cmp.Name, hl.Wetmode, hl.Wet, hl.RHout)
-							} else {
-								fmt.Printf("DEBUG e79: Hcload[%d] Cmp=nil\n", idx)
-							}
-						}
-					}
-					Hcldcfv(Eqsys.Hcload)
+		// 収束計算
+		for i := 0; i < sim.loopMax; i++ {
+			if i == 0 {
+				hcldwetmdreset(Eqsys)
+			}
+			sim.loopCount = max(sim.loopCount, i+1)
 
-					// システム方程式の作成およびシステム変数の計算
-					Syseqv(Elout, &Syseq)
+			if Simc.Debug {
+				fmt.Printf("再計算が必要な機器のループ %d\n", i)
+			}
 
-					Sysvar(Compnt)
+			if sim.dayprn && sim.Ferr != nil {
+				fmt.Fprintf(sim.Ferr, "再計算が必要な機器のループ %d\n\n\n", i)
+			}
 
-					// 室温・湿度計算結果代入、室供給熱量計算
-					// およびパネル入口温度代入、パネル供給熱量計算
-					Roomene(Rmvls, Rmvls.Room, Rmvls.Rdpnl, &Exsf, &Wd)
+			LDreset := 0
+			DWreset := 0
+			TKreset := 0
+			BOIreset := 0
+			Evacreset := 0
+			PCMfunreset := 0
+
+			/********************************
+			if ( TKreset > 0 )
+			fmt.Printf("<< main >> nday=%d mt=%d  tt=%d mm=%d TKreset=%d\n",
+			nday, mt, tt, mm, TKreset );
+			****************************/
+
+			// 蓄熱槽特性式係数
+			Stankcfv(Eqsys.Stank, sim.Ferr)
+
+			// 特性式の係数
+			// DEBUG: Hcload スライスの長さを確認（1回だけ出力）
+			if j == 0 && Daytm.Mon == 4 && Daytm.Day == 15 {
+				fmt.Printf("DEBUG e79: Mon=%d Day=%d Time=%.1f len(Eqsys.Hcload)=%d\n", Daytm.Mon, Daytm.Day, Daytm.Time, len(Eqsys.Hcload))
+				for idx, hl := range Eqsys.Hcload {
+					if hl.Cmp != nil {
+						fmt.Printf("DEBUG e79: Hcload[%d] name=%s Wetmode=%v Wet=%v RHout=%.1f\n", idx, hl.Cmp.Name, hl.Wetmode, hl.Wet, hl.RHout)
+					} else {
+						fmt.Printf("DEBUG e79: Hcload[%d] Cmp=nil\n", idx)
+					}
+				}
+			}
+			Hcldcfv(Eqsys.Hcload, sim.Ferr)
 
-					// 室負荷の計算
-					Roomload(Rmvls.Room, &LDreset)
+			// システム方程式の作成およびシステム変数の計算
+			Syseqv(Elout, Syseq, Simc.Debug, sim.dlog())
 
-					// PCM家具の収束判定
-					PCMfunchk(Rmvls.Room, &Wd, &PCMfunreset)
+			Sysvar(Compnt)
 
-					// 壁体内部温度の計算と収束計算のチェック
-					if Rmvls.Pcmiterate == 'y' {
-						PCMwlchk(i, Rmvls, &Exsf, &Wd, &LDreset)
-					}
+			// 室温・湿度計算結果代入、室供給熱量計算
+			// およびパネル入口温度代入、パネル供給熱量計算
+			Roomene(Rmvls, Rmvls.Room, Rmvls.Rdpnl, Exsf, Wd)
 
-					// 供給熱量、エネルギーの計算
-					Boiene(Eqsys.Boi, &BOIreset)
+			// 室負荷の計算
+			Roomload(Rmvls.Room, &LDreset)
 
-					// 冷却熱量/加熱量、エネルギーの計算
-					Refaene(Eqsys.Refa, &LDreset)
+			// PCM家具の収束判定
+			PCMfunchk(Rmvls.Room, Wd, &PCMfunreset, sim.DTM)
 
-					// 空調負荷の計算
-					Hcldene(Eqsys.Hcload, &LDreset, &Wd)
+			// 壁体内部温度の計算と収束計算のチェック
+			if Rmvls.Pcmiterate == 'y' {
+				PCMwlchk(i, Rmvls, Exsf, Wd, &LDreset, sim.DTM, Simc.Debug, sim.Ferr)
+			}
 
-					// 供給熱量の計算
-					Hccdwreset(Eqsys.Hcc, &DWreset)
+			// 供給熱量、エネルギーの計算
+			Boiene(Eqsys.Boi, &BOIreset, sim.Ferr)
 
-					// 槽内水温、水温分布逆転の検討
-					Stanktss(Eqsys.Stank, &TKreset)
+			// 冷却熱量/加熱量、エネルギーの計算
+			Refaene(Eqsys.Refa, &LDreset, sim.Ferr)
 
-					// 内部温度、熱量の計算
-					Evacene(Eqsys.Evac, &Evacreset)
+			// 空調負荷の計算
+			Hcldene(Eqsys.Hcload, &LDreset, Wd)
 
-					if BOIreset+LDreset+DWreset+TKreset+Evacreset+PCMfunreset == 0 {
-						break
-					}
-				}
+			// 供給熱量の計算
+			Hccdwreset(Eqsys.Hcc, &DWreset, sim.Ferr)
 
-				if i == LOOP_MAX {
-					fmt.Printf("収束しませんでした。 MAX=%d\n", LOOP_MAX)
-				}
+			// 槽内水温、水温分布逆転の検討
+			Stanktss(Eqsys.Stank, &TKreset)
 
-				// 供給熱量の計算
-				Hccene(Eqsys.Hcc)
+			// 内部温度、熱量の計算
+			Evacene(Eqsys.Evac, &Evacreset)
 
-				// 風量の計算
-				VAVene(Eqsys.Vav, &VAVreset)
-				Valvene(Eqsys.Valv, &Valvreset)
+			if BOIreset+LDreset+DWreset+TKreset+Evacreset+PCMfunreset == 0 {
+				break
+			}
+		}
 
-				if VAVreset == 0 && Valvreset == 0 {
-					break
-				}
+		if i == sim.loopMax {
+			fmt.Printf("収束しませんでした。 MAX=%d\n", sim.loopMax)
+		}
 
-				// カウントアップ
-				Eqsys.VAVcountinc()
-				Eqsys.Valvcountinc()
+		// 供給熱量の計算
+		Hccene(Eqsys.Hcc)
 
-				// 風量が変わったら電気蓄熱暖房器の係数を再計算
-				Stheatcfv(Eqsys.Stheat)
-			}
-			// ここまで: VAV 計算繰り返しループ
+		// 風量の計算
+		VAVene(Eqsys.Vav, &VAVreset, sim.Ferr)
+		Valvene(Eqsys.Valv, &Valvreset, Simc.Debug)
 
-			// 太陽電池内蔵壁体の発電量計算
-			CalcPowerOutput(Rmvls.Sd, &Wd, &Exsf)
+		if VAVreset == 0 && Valvreset == 0 {
+			break
+		}
 
-			if Simc.Helmkey == 'y' {
-				Helmroom(Rmvls.Room, Rmvls.Qrm, &Rmvls.Qetotal, Wd.T, Wd.X)
-			}
+		// カウントアップ
+		Eqsys.VAVcountinc()
+		Eqsys.Valvcountinc()
 
-			/*************
-			fmt.Printf("xxxmain Pathheat\n")
-			Pathheat(Nmpath, Mpath)
-			************************/
+		// 風量が変わったら電気蓄熱暖房器の係数を再計算
+		Stheatcfv(Eqsys.Stheat, sim.DTM, sim.Ferr)
+	}
+	// ここまで: VAV 計算繰り返しループ
 
-			// 室の熱取得要素の計算
-			Qrmsim(Rmvls.Room, &Wd, Rmvls.Qrm)
+	// 太陽電池内蔵壁体の発電量計算
+	CalcPowerOutput(Rmvls.Sd, Wd, Exsf)
 
-			for rm := range Rmvls.Room {
-				Rmvls.Room[rm].Qeqp = 0.0
-			}
+	if Simc.Helmkey == 'y' {
+		Helmroom(Rmvls.Room, Rmvls.Qrm, &Rmvls.Qetotal, Wd.T, Wd.X, sim.DTM)
+	}
 
-			if DEBUG {
-				fmt.Printf("Mecsene st\n")
-			}
+	/*************
+	fmt.Printf("xxxmain Pathheat\n")
+	Pathheat(Nmpath, Mpath)
+	************************/
 
-			/*  システム使用機器の供給熱量、エネルギーの計算  */
-			Eqsys.Mecsene()
+	// 室の熱取得要素の計算
+	Qrmsim(Rmvls.Room, Wd, Rmvls.Qrm, sim.DTM)
 
-			/***********************
-			fmt.Printf("Mecsene en\n")
-			/***********************/
+	for rm := range Rmvls.Room {
+		Rmvls.Room[rm].Qeqp = 0.0
+	}
 
-			if DEBUG {
-				mecsxprint(Eqsys)
-			}
+	if Simc.Debug {
+		fmt.Printf("Mecsene st\n")
+	}
 
-			/* ------------------------------------------------ */
-			if DEBUG {
-				fmt.Printf("xxxmain 2\n")
-			}
+	/*  システム使用機器の供給熱量、エネルギーの計算  */
+	Eqsys.Mecsene(sim.DTM, sim.Ferr)
 
-			// 前時刻の室温の入れ替え、OT、MRTの計算
-			Rmsurft(Rmvls.Room, Rmvls.Sd)
+	/***********************
+	fmt.Printf("Mecsene en\n")
+	/***********************/
 
-			if DEBUG {
-				fmt.Printf("xxxmain 3\n")
-			}
+	if Simc.Debug {
+		mecsxprint(Eqsys)
+	}
 
-			//if (Daytm.Mon == 1 && Daytm.Day == 5 && fabs(Daytm.Time - 23.15) < 1.e-5)
-			//	printf("debug\n");
+	/* ------------------------------------------------ */
+	if Simc.Debug {
+		fmt.Printf("xxxmain 2\n")
+	}
 
-			// 壁体内部温度の計算（ヒステリシス考慮PCMの状態値もここで設定）
-			RMwlt(Rmvls.Mw)
+	// 前時刻の室温の入れ替え、OT、MRTの計算
+	Rmsurft(Rmvls.Room, Rmvls.Sd)
 
-			if DEBUG {
-				fmt.Printf("xxxmain 4\n")
-			}
+	if Simc.Debug {
+		fmt.Printf("xxxmain 3\n")
+	}
 
-			// PMV、SET*の計算
-			Rmcomfrt(Rmvls.Room)
+	//if (Daytm.Mon == 1 && Daytm.Day == 5 && fabs(Daytm.Time - 23.15) < 1.e-5)
+	//	printf("debug\n");
 
-			if DEBUG {
-				fmt.Printf("xxxmain 5\n")
-			}
+	// 壁体内部温度の計算（ヒステリシス考慮PCMの状態値もここで設定）
+	RMwlt(Rmvls.Mw, Simc.Debug)
 
-			//xprsolrd (Exsf.Nexs, Exsf.Exs);
+	if Simc.Debug {
+		fmt.Printf("xxxmain 4\n")
+	}
 
-			// 代表日の毎時計算結果のファイル出力
-			Eeprinth(&Daytm, Simc, Flout, Rmvls, &Exsf, Mpath, Eqsys, &Wd)
+	// PMV、SET*の計算
+	Rmcomfrt(Rmvls.Room)
 
-			if DEBUG {
-				fmt.Printf("xxxmain 6\n")
-			}
+	if Simc.Debug {
+		fmt.Printf("xxxmain 5\n")
+	}
 
-			if Daytm.Ddpri != 0 {
-				// 室の日集計、月集計
-				Roomday(Daytm.Mon, Daytm.Day, day, Daytm.Ttmm, Rmvls.Room, Rmvls.Rdpnl, Simc.Dayend)
-				if Simc.Helmkey == 'y' {
-					Helmdy(day, Rmvls.Room, &Rmvls.Qetotal)
-				}
+	//xprsolrd (Exsf.Nexs, Exsf.Exs);
 
-				Compoday(Daytm.Mon, Daytm.Day, day, Daytm.Ttmm, Eqsys, Simc.Dayend)
-				/**   if (Nqrmpri > 0)  **/
-				Qrmsum(Daytm.Day, Rmvls.Room, Rmvls.Qrm, Rmvls.Trdav, Rmvls.Qrmd)
+	// 代表日の毎時計算結果のファイル出力
+	sim.Eeprinth(Daytm, Simc, Flout, Rmvls, Exsf, Mpath, Eqsys, Wd)
 
-				if DEBUG {
-					fmt.Printf("xxxmain 7\n")
-				}
+	if Simc.Debug {
+		fmt.Printf("xxxmain 6\n")
+	}
 
-				// 気象データの日集計、月集計
-				Wdtsum(Daytm.Mon, Daytm.Day, day, Daytm.Ttmm, &Wd, Exsf.Exs, &Wdd, &Wdm, Soldy, Solmon, Simc)
-			}
-			if DEBUG {
-				fmt.Printf("xxxmain 8\n")
-			}
+	if Daytm.Ddpri != 0 {
+		day, dayend := sim.dayKeys()
 
-			if DEBUG {
-				Rmvls.xprtwsrf()
-				Rmvls.xprrmsrf()
-				Rmvls.xprtwall()
-			}
+		// 室の日集計、月集計
+		sim.Roomday(Daytm.Mon, Daytm.Day, day, Daytm.Ttmm, Rmvls.Room, Rmvls.Rdpnl, dayend)
+		if Simc.Helmkey == 'y' {
+			sim.Helmdy(day, Rmvls.Room, &Rmvls.Qetotal)
+		}
 
-			mm += dminute
+		sim.Compoday(Daytm.Mon, Daytm.Day, day, Daytm.Ttmm, Eqsys, dayend)
+		/**   if (Nqrmpri > 0)  **/
+		sim.Qrmsum(Daytm.Day, Rmvls.Room, Rmvls.Qrm, Rmvls.Trdav, Rmvls.Qrmd)
 
-			// 時刻ループの最後
+		if Simc.Debug {
+			fmt.Printf("xxxmain 7\n")
 		}
 
-		// 日集計の出力
-		Eeprintd(&Daytm, Simc, Flout, Rmvls, Exsf.Exs, Soldy, Eqsys, &Wdd)
-		/*****fmt.Printf("xxxmain 9\n")*****/
+		// 気象データの日集計、月集計
+		sim.Wdtsum(Daytm.Mon, Daytm.Day, day, Daytm.Ttmm, Wd, Exsf.Exs, Wdd, Wdm, sim.soldy, sim.solmon, Simc)
+	}
+	if Simc.Debug {
+		fmt.Printf("xxxmain 8\n")
+	}
+
+	if Simc.Debug {
+		Rmvls.xprtwsrf()
+		Rmvls.xprrmsrf()
+		Rmvls.xprtwall()
+	}
 
-		//if (Daytm.Mon == 4 && Daytm.Day == 25)
-		//	printf("debug\n");
+	for _, o := range sim.observers {
+		o.OnTimestep(sim.snapshot(Wd))
+	}
+
+	sim.nstep++
+	if sim.ProgressFunc != nil {
+		sim.ProgressFunc(sim.progress())
+	}
+
+	sim.mm += sim.dminute
+
+	// 時刻ループの最後
+	sim.mt++
+	if sim.mt > sim.mtb {
+		sim.endDay()
+		sim.nday++
+		sim.beginDay()
+	}
+}
 
-		// 月集計の出力
-		if IsEndDay(Daytm.Mon, Daytm.Day, Daytm.DayOfYear, Simc.Dayend) && Daytm.Ddpri != 0 {
-			//fmt.Printf("月集計出力\n")
-			Eeprintm(&Daytm, Simc, Flout, Rmvls, Exsf.Exs, Solmon, Eqsys, &Wdm)
+// endDay は日集計、月集計を出力します。
+func (sim *Simulation) endDay() {
+	Daytm := &sim.Daytm
+	Simc := sim.Simc
+	Exsf := &sim.Exsf
+	Rmvls := sim.Rmvls
+	Eqsys := sim.Eqsys
+	Flout := sim.flout
+	Wdd := &sim.wdd
+	Wdm := &sim.wdm
+
+	// 日集計の出力
+	sim.Eeprintd(Daytm, Simc, Flout, Rmvls, Exsf.Exs, sim.soldy, Eqsys, Wdd)
+	/*****fmt.Printf("xxxmain 9\n")*****/
+	if Daytm.Ddpri != 0 {
+		for _, o := range sim.observers {
+			o.OnDayEnd(sim.snapshot(Wdd))
 		}
+	}
+
+	//if (Daytm.Mon == 4 && Daytm.Day == 25)
+	//	printf("debug\n");
 
+	// 月集計の出力
+	if day, dayend := sim.dayKeys(); IsEndDay(Daytm.Mon, Daytm.Day, day, dayend) && Daytm.Ddpri != 0 {
+		//fmt.Printf("月集計出力\n")
+		sim.Eeprintm(Daytm, Simc, Flout, Rmvls, Exsf.Exs, sim.solmon, Eqsys, Wdm)
+		for _, o := range sim.observers {
+			o.OnMonthEnd(sim.snapshot(Wdm))
+		}
 	}
+}
+
+// finalize は月－時刻別集計値を出力し、出力ファイルを閉じます。
+func (sim *Simulation) finalize() {
+	sim.finalized = true
+
+	Simc := sim.Simc
+	Rmvls := sim.Rmvls
+	Eqsys := sim.Eqsys
+	Flout := sim.flout
+
 	// 月－時刻別集計値の出力
-	Eeprintmt(Simc, Flout, Eqsys, Rmvls.Rdpnl)
+	sim.Eeprintmt(Simc, Flout, Eqsys, Rmvls.Rdpnl)
+	for _, o := range sim.observers {
+		o.OnRunEnd(sim.snapshot(&sim.Wd))
+	}
 
-	if DEBUG {
+	if Simc.Debug {
 		fmt.Printf("メモリ領域の解放\n")
 	}
 
-	Eeflclose(Flout)
+	sim.closeOutputs()
+}
 
-	/*------------------higuchi add---------------------start*/
-	if len(BDP) != 0 {
+// closeOutputs は出力ファイルに計算結果を書き出して閉じます。
+// エラーで計算を中断した場合は、それまでの計算結果を書き出します。
+func (sim *Simulation) closeOutputs() {
+	if sim.closed || sim.Simc == nil || sim.Simc.Output == nil {
+		return
+	}
+	sim.closed = true
 
-		defer fp1.Close()
-		defer fp2.Close()
-		defer fp3.Close()
-		defer fp4.Close()
+	// 出力ファイル名を決める前に中断した場合は書き出さない
+	var flout []*FLOUT
+	for _, fl := range sim.flout {
+		if fl.F != nil {
+			flout = append(flout, fl)
+		}
 	}
+	Eeflclose(flout, sim.Ferr, sim.Simc.Output)
 
+	/*------------------higuchi add---------------------start*/
+	for _, fp := range []io.WriteCloser{sim.fp1, sim.fp2, sim.fp3, sim.fp4} {
+		if fp != nil {
+			fp.Close()
+		}
+	}
 	/*---------------------higuchi 1999.7.21-----------end*/
 }
 
@@ -1124,13 +1339,13 @@ Newbekt (New Bekt Object)
 「bekt」構造体は、ある面から見た他の面の相対的な位置関係を格納するために用いられます。
 
 建築環境工学的な観点:
-- **幾何学的関係のモデル化**: 建物の日影や日射は、
-  建物や周囲の障害物の幾何学的関係によって決まります。
-  この関数は、`ps`（位置関係を示す行列）を初期化することで、
-  ある面から見た他の面の相対的な位置関係をモデル化します。
-- **日影計算の基礎**: `uop`（opから見たopの位置）、`ulp`（opから見たlpの位置）、
-  `ullp`（lpから見たlpの位置）、`ulmp`（lpから見たmpの位置）といった`bekt`構造体は、
-  日影計算において、太陽光線が障害物によって遮られるかどうかを判定するために用いられます。
+  - **幾何学的関係のモデル化**: 建物の日影や日射は、
+    建物や周囲の障害物の幾何学的関係によって決まります。
+    この関数は、`ps`（位置関係を示す行列）を初期化することで、
+    ある面から見た他の面の相対的な位置関係をモデル化します。
+  - **日影計算の基礎**: `uop`（opから見たopの位置）、`ulp`（opから見たlpの位置）、
+    `ullp`（lpから見たlpの位置）、`ulmp`（lpから見たmpの位置）といった`bekt`構造体は、
+    日影計算において、太陽光線が障害物によって遮られるかどうかを判定するために用いられます。
 
 この関数は、建物の日射環境を正確にモデル化し、
 日射熱取得の抑制、冷房負荷の軽減、
@@ -1151,13 +1366,13 @@ matiniti (Matrix Initialization for Integer Array)
 この関数は、整数型の配列`A`の全ての要素をゼロに初期化します。
 
 建築環境工学的な観点:
-- **データ初期化の重要性**: シミュレーションでは、
-  計算の開始前に全ての変数を既知の状態に初期化することが重要です。
-  これにより、過去の計算結果が現在の計算に影響を与えたり、
-  未定義の値によるエラーが発生したりするのを防ぎます。
-- **カウンタやフラグのリセット**: この関数は、
-  例えば、日影計算における日影の有無を示すフラグや、
-  特定のイベントの発生回数をカウントするカウンタなどをリセットする際に用いられます。
+  - **データ初期化の重要性**: シミュレーションでは、
+    計算の開始前に全ての変数を既知の状態に初期化することが重要です。
+    これにより、過去の計算結果が現在の計算に影響を与えたり、
+    未定義の値によるエラーが発生したりするのを防ぎます。
+  - **カウンタやフラグのリセット**: この関数は、
+    例えば、日影計算における日影の有無を示すフラグや、
+    特定のイベントの発生回数をカウントするカウンタなどをリセットする際に用いられます。
 
 この関数は、シミュレーションの正確性と安定性を確保するための基本的な役割を果たします。
 */
@@ -1175,14 +1390,14 @@ P_MENNinit (P_MENN Initialization)
 「被受照面（Light-Receiving Plane, LP）」の幾何学的情報を格納するために用いられます。
 
 建築環境工学的な観点:
-- **幾何学的モデルの準備**: 建物の日射環境をシミュレーションする前に、
-  窓、壁、日よけ、障害物などの幾何学的情報を格納するデータ構造を準備します。
-  この関数は、`NewP_MENN`関数を呼び出すことで、
-  各`P_MENN`構造体をデフォルト値で初期化します。
-- **日影計算と日射量計算の基礎**: これらの構造体は、
-  太陽位置と組み合わせて、
-  窓面への影の形状と面積、
-  および各表面への日射入射量を計算するために用いられます。
+  - **幾何学的モデルの準備**: 建物の日射環境をシミュレーションする前に、
+    窓、壁、日よけ、障害物などの幾何学的情報を格納するデータ構造を準備します。
+    この関数は、`NewP_MENN`関数を呼び出すことで、
+    各`P_MENN`構造体をデフォルト値で初期化します。
+  - **日影計算と日射量計算の基礎**: これらの構造体は、
+    太陽位置と組み合わせて、
+    窓面への影の形状と面積、
+    および各表面への日射入射量を計算するために用いられます。
 
 この関数は、建物の日射環境を正確にモデル化し、
 日射熱取得の抑制、冷房負荷の軽減、
@@ -1221,9 +1436,9 @@ NewP_MENN (New P_MENN Object)
   - `sbflg`: 日よけフラグ。
   - `P`: 頂点座標。
   - `opw`: 窓のデータ。
-  といった、日影計算や日射量計算に必要な様々な幾何学的・熱的・光学的パラメータを格納します。
-  これらのパラメータをデフォルト値で初期化することで、
-  後続のデータ入力や計算が正しく行われるための準備が整います。
+    といった、日影計算や日射量計算に必要な様々な幾何学的・熱的・光学的パラメータを格納します。
+    これらのパラメータをデフォルト値で初期化することで、
+    後続のデータ入力や計算が正しく行われるための準備が整います。
 
 この関数は、建物の日射環境を正確にモデル化し、
 日射熱取得の抑制、冷房負荷の軽減、
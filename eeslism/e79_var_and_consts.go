@@ -21,26 +21,24 @@ e79_var_and_consts.go (Global Variables and Constants for Building Energy Simula
     これらの定数は、シミュレーションの正確性と物理的な妥当性を確保するために重要です。
 
 - **シミュレーション制御パラメータ**:
-  - `DTM`: 時間ステップの長さ [秒]。シミュレーションの細かさを決定します。
-  - `Cff_kWh`: エネルギー単位変換係数（ジュールからkWhへ）。
   - `VAVCountMAX`: VAVシステムの収束計算の最大反復回数。
+    時間ステップの長さ（`DTM`）やエネルギー単位変換係数（`Cff_kWh`）など、
+    入力データごとに決まる値は`Simulation`が保持します。
 
 - **デバッグと出力制御**:
-  - `DEBUG`: デバッグモードのON/OFFを切り替えるフラグ。
-  - `dayprn`: 日ごとの詳細出力のON/OFFを切り替えるフラグ。
-  - `Ferr`: エラーメッセージの出力先。
   - `SETprint`: SET（作用温度）の出力のON/OFFを切り替えるフラグ。
     これらのフラグは、シミュレーションの実行中に詳細な情報を取得し、
     モデルの検証や問題の特定を効率的に行うために用いられます。
-  - **共通データ**: `Fbmlist`（壁材料リストファイル名）など、
-    複数のモジュールで共有されるファイル名やデータが定義されます。
+    日ごとの詳細出力フラグ（`dayprn`）やログファイル（`Ferr`）、
+    壁材料リストファイル名（`Fbmlist`）は`Simulation`が、
+    デバッグ出力のON/OFF（`Debug`）は`SIMCONTL`が保持します。
 
 このファイルは、建物のエネルギーシミュレーションの基盤を形成し、
 計算の正確性、安定性、および保守性を向上させるための重要な役割を果たします。
 */
 package eeslism
 
-import "os"
+import "time"
 
 const (
 	ALO = 23.0
@@ -68,23 +66,21 @@ var (
 	Ro  = 2501000.0
 
 	G           = 9.8
-	DTM         = 0.0 // Assign the value of dTM here
-	Cff_kWh     = 0.0 // Assign the value of cff_kWh here
-	VAVCountMAX = 0   // Assign the value of VAV_Count_MAX here
+	VAVCountMAX = 0 // Assign the value of VAV_Count_MAX here
 
-	Fbmlist = "" // 壁材料リストファイル名 ref: wbmlist.md
-
-	DEBUG   = false
-	dayprn  = false
 	DAYweek = [8]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun", "Hol"}
 
-	Ferr = os.Stderr // Assuming you want to write errors to standard error
 	//DISPLAY_DELAY = 0 // Assign the value of DISPLAY_DELAY here
 	SETprint = false //  SET(体感温度)を出力する場合は true
 )
 
 // 月の末日かどうかをチェックする
+// 実暦による計算では Dayend（当日）、SimDayend（計算終了日）を年月日 (yyyymmdd) とし、うるう年の2月は29日までとする
 func IsEndDay(Mon, Day, Dayend, SimDayend int) bool {
+	if Dayend > 10000 {
+		return Day == time.Date(Dayend/10000, time.Month(Mon)+1, 0, 0, 0, 0, 0, time.UTC).Day() || Dayend == SimDayend
+	}
+
 	Nde := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
 	nday := SimDayend
 	if nday > 365 {
@@ -96,19 +92,3 @@ func IsEndDay(Mon, Day, Dayend, SimDayend int) bool {
 
 	return false
 }
-
-// ResetGlobalState はグローバル変数を初期状態にリセットします。
-// テスト間での状態分離を確保するためにテストで使用されます。
-func ResetGlobalState() {
-	// シミュレーション制御パラメータ
-	DTM = 0.0
-	Cff_kWh = 0.0
-	VAVCountMAX = 0
-	Fbmlist = ""
-	DEBUG = false
-	dayprn = false
-	SETprint = false
-
-	// 印刷・日集計状態変数
-	resetPrintStates()
-}
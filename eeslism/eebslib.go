@@ -1,6 +1,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"math"
 	"os"
@@ -8,7 +9,7 @@ import (
 )
 
 // 外表面方位デ－タの入力
-func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SIMCONTL) {
+func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SIMCONTL, Ferr io.Writer) {
 	var ename string
 	//var st *string
 	var dt, wa, wb, swa, cwa, swb, cwb float64
@@ -16,7 +17,7 @@ func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SI
 	var err error
 
 	// 外表面総合伝達率のデフォルト値
-	Exsf.Alosch = envptr(fmt.Sprintf("%f", ALO), Simc, nil, nil, nil)
+	Exsf.Alosch = envptr(fmt.Sprintf("%f", ALO), Simc, nil, nil, nil, Ferr)
 	Exsf.Alotype = Alotype_Fix // 固定値
 	Exsf.Exs = make([]*EXSF, 0)
 
@@ -32,13 +33,13 @@ func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SI
 			if value == "Calc" {
 				// 風速から計算する
 				Exsf.Alotype = Alotype_V
-			} else if k, err = idsch(value, Schdl.Sch, ""); err == nil {
+			} else if k, err = idsch(value, Schdl.Sch, "", Ferr); err == nil {
 				// スケジュールに基づいて値を変化させる
 				Exsf.Alosch = &Schdl.Val[k]
 				Exsf.Alotype = Alotype_Schedule
 			} else {
 				// 数値 or 内部変数名
-				Exsf.Alosch = envptr(value, Simc, nil, nil, nil)
+				Exsf.Alosch = envptr(value, Simc, nil, nil, nil, Ferr)
 				if Exsf.Alosch != nil {
 					Exsf.Alotype = Alotype_Schedule
 				}
@@ -50,7 +51,7 @@ func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SI
 			dfrg, err = readFloat(value)
 			if err != nil || dfrg < 0.0 || dfrg > 1.0 {
 				fmt.Fprintf(os.Stderr, "%s の設置値が不適切です", s)
-				os.Exit(1)
+				panic(&InputError{Section: "EXSRF", Keyword: s, Msg: "invalid ground reflectance"})
 			}
 		}
 	}
@@ -139,7 +140,7 @@ func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SI
 						}
 					}
 					if !found_flag {
-						Eprint("<Exsfdata>", s)
+						Eprint("<Exsfdata>", s, Ferr)
 					}
 				}
 			} else if key == "alo" {
@@ -151,10 +152,10 @@ func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SI
 				} else {
 					// スケジュール
 					ex.Alotype = Alotype_Schedule
-					if k, err = idsch(value, Schdl.Sch, ""); err == nil {
+					if k, err = idsch(value, Schdl.Sch, "", Ferr); err == nil {
 						ex.Alo = &Schdl.Val[k]
 					} else {
-						ex.Alo = envptr(value, Simc, nil, nil, nil)
+						ex.Alo = envptr(value, Simc, nil, nil, nil, Ferr)
 					}
 				}
 			} else {
@@ -179,7 +180,7 @@ func Exsfdata(section *EeTokens, dsn string, Exsf *EXSFS, Schdl *SCHDL, Simc *SI
 					// 土の熱拡散率 [m2/s]
 					ex.Erdff = dt
 				default:
-					Eprint("<Exsfdata>", s)
+					Eprint("<Exsfdata>", s, Ferr)
 				}
 			}
 		}
@@ -1,8 +1,8 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -45,7 +45,7 @@ Compodata (Component Data Input and Initialization)
 システム全体のエネルギー消費量予測、省エネルギー対策の検討、
 および最適な設備システム設計を行うための重要な役割を果たします。
 */
-func Compodata(f *EeTokens, Rmvls *RMVLS, Eqcat *EQCAT, Cmp *[]*COMPNT, Eqsys *EQSYS) {
+func Compodata(f *EeTokens, Rmvls *RMVLS, Eqcat *EQCAT, Cmp *[]*COMPNT, Eqsys *EQSYS, Ferr io.Writer) {
 	var (
 		Ni, No int
 		cio    ELIOType
@@ -74,8 +74,8 @@ func Compodata(f *EeTokens, Rmvls *RMVLS, Eqcat *EQCAT, Cmp *[]*COMPNT, Eqsys *E
 
 	// if fi, err := os.Open("bdata.ewk"); err != nil {
 	if f == nil {
-		Eprint("bdata.ewk", "<Compodata>")
-		os.Exit(EXIT_BDATA)
+		Eprint("bdata.ewk", "<Compodata>", Ferr)
+		panic(&InputError{Section: "SYSCMP", Msg: "bdata.ewk not available", Code: EXIT_BDATA})
 	}
 
 	// ----------------------------------------------------------
@@ -327,7 +327,7 @@ func Compodata(f *EeTokens, Rmvls *RMVLS, Eqcat *EQCAT, Cmp *[]*COMPNT, Eqsys *E
 					// 電気蓄熱暖房器の潜熱蓄熱材重量（kg）
 					cio = 'P'
 				default:
-					Eprint(errkey, s)
+					Eprint(errkey, s, Ferr)
 				}
 			} else if cio != 'V' && cio != 'S' && (strings.ContainsRune(s, '-') || strings.ContainsRune(s, '=')) {
 				// PVcap-4000 または PVcap=4000 形式をサポート
@@ -364,7 +364,7 @@ func Compodata(f *EeTokens, Rmvls *RMVLS, Eqcat *EQCAT, Cmp *[]*COMPNT, Eqsys *E
 				case 'c':
 					// `-c <カタログ名>`
 					if eqpcat(s, comp, Eqcat, Eqsys) {
-						Eprint(errkey, s)
+						Eprint(errkey, s, Ferr)
 					}
 				case 't':
 					// `-type <種類>`
@@ -402,7 +402,7 @@ func Compodata(f *EeTokens, Rmvls *RMVLS, Eqcat *EQCAT, Cmp *[]*COMPNT, Eqsys *E
 						Eqsys.Qmeas = append(Eqsys.Qmeas, NewQMEAS())
 					default:
 						if s != DIVERG_TYPE && s != DIVGAIR_TYPE {
-							Eprint(errkey, s)
+							Eprint(errkey, s, Ferr)
 						}
 					}
 
@@ -6,13 +6,11 @@ import (
 )
 
 /* システム要素機器の日集計処理 */
-var __Compoday_OldDay int = 0
-var __Compoday_OldMon int = 0
 
-func Compoday(Mon, Day, Nday, ttmm int, Eqsys *EQSYS, SimDayend int) {
+func (sim *Simulation) Compoday(Mon, Day, Nday, ttmm int, Eqsys *EQSYS, SimDayend int) {
 
 	// 日集計
-	if Nday != __Compoday_OldDay {
+	if Nday != sim.__Compoday_OldDay {
 		boidyint(Eqsys.Boi)
 		refadyint(Eqsys.Refa)
 		colldyint(Eqsys.Coll)
@@ -28,10 +26,10 @@ func Compoday(Mon, Day, Nday, ttmm int, Eqsys *EQSYS, SimDayend int) {
 		PVdyint(Eqsys.PVcmp)
 		Desidyint(Eqsys.Desi)
 
-		__Compoday_OldDay = Nday
+		sim.__Compoday_OldDay = Nday
 	}
 
-	if Mon != __Compoday_OldMon {
+	if Mon != sim.__Compoday_OldMon {
 		boimonint(Eqsys.Boi)
 		refamonint(Eqsys.Refa)
 		collmonint(Eqsys.Coll)
@@ -46,24 +44,24 @@ func Compoday(Mon, Day, Nday, ttmm int, Eqsys *EQSYS, SimDayend int) {
 		Qmeasmonint(Eqsys.Qmeas)
 		PVmonint(Eqsys.PVcmp)
 
-		__Compoday_OldMon = Mon
+		sim.__Compoday_OldMon = Mon
 	}
 
 	// 日集計
-	boiday(Mon, Day, ttmm, Eqsys.Boi, Nday, SimDayend)
-	refaday(Mon, Day, ttmm, Eqsys.Refa, Nday, SimDayend)
-	collday(Mon, Day, ttmm, Eqsys.Coll, Nday, SimDayend)
-	hccday(Mon, Day, ttmm, Eqsys.Hcc, Nday, SimDayend)
-	pipeday(Mon, Day, ttmm, Eqsys.Pipe, Nday, SimDayend)
-	hexday(Mon, Day, ttmm, Eqsys.Hex, Nday, SimDayend)
-	stankday(Mon, Day, ttmm, Eqsys.Stank, Nday, SimDayend)
-	pumpday(Mon, Day, ttmm, Eqsys.Pump, Nday, SimDayend)
-	hcldday(Mon, Day, ttmm, Nday, SimDayend, Eqsys.Hcload)
-	stheatday(Mon, Day, ttmm, Eqsys.Stheat, Nday, SimDayend)
-	Thexday(Mon, Day, ttmm, Eqsys.Thex, Nday, SimDayend)
-	Qmeasday(Mon, Day, ttmm, Eqsys.Qmeas, Nday, SimDayend)
-	PVday(Mon, Day, ttmm, Eqsys.PVcmp, Nday, SimDayend)
-	Desiday(Mon, Day, ttmm, Eqsys.Desi, Nday, SimDayend)
+	boiday(Mon, Day, ttmm, Eqsys.Boi, Nday, SimDayend, sim.Cff_kWh)
+	refaday(Mon, Day, ttmm, Eqsys.Refa, Nday, SimDayend, sim.Cff_kWh)
+	collday(Mon, Day, ttmm, Eqsys.Coll, Nday, SimDayend, sim.Cff_kWh)
+	hccday(Mon, Day, ttmm, Eqsys.Hcc, Nday, SimDayend, sim.Cff_kWh)
+	pipeday(Mon, Day, ttmm, Eqsys.Pipe, Nday, SimDayend, sim.Cff_kWh)
+	hexday(Mon, Day, ttmm, Eqsys.Hex, Nday, SimDayend, sim.Cff_kWh)
+	stankday(Mon, Day, ttmm, Eqsys.Stank, Nday, SimDayend, sim.Cff_kWh)
+	pumpday(Mon, Day, ttmm, Eqsys.Pump, Nday, SimDayend, sim.Cff_kWh)
+	hcldday(Mon, Day, ttmm, Nday, SimDayend, Eqsys.Hcload, sim.Cff_kWh)
+	stheatday(Mon, Day, ttmm, Eqsys.Stheat, Nday, SimDayend, sim.Cff_kWh)
+	Thexday(Mon, Day, ttmm, Eqsys.Thex, Nday, SimDayend, sim.Cff_kWh)
+	Qmeasday(Mon, Day, ttmm, Eqsys.Qmeas, Nday, SimDayend, sim.Cff_kWh)
+	PVday(Mon, Day, ttmm, Eqsys.PVcmp, Nday, SimDayend, sim.Cff_kWh)
+	Desiday(Mon, Day, ttmm, Eqsys.Desi, Nday, SimDayend, sim.Cff_kWh)
 
 	// 月集計
 	//boimon(Mon, Day, ttmm, Eqsys.Nboi, Eqsys.Boi);
@@ -83,10 +81,8 @@ func Compoday(Mon, Day, Nday, ttmm int, Eqsys *EQSYS, SimDayend int) {
 
 /* システム要素機器の日集計結果出力 */
 
-var __Compodyprt_id int
-
-func Compodyprt(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Eqsys *EQSYS, Rdpnl []*RDPNL) {
-	if __Compodyprt_id == 0 {
+func (sim *Simulation) Compodyprt(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Eqsys *EQSYS, Rdpnl []*RDPNL) {
+	if sim.__Compodyprt_id == 0 {
 		ttldyprint(fo, mrk, Simc)
 
 		for j := 0; j < 2; j++ {
@@ -94,58 +90,57 @@ func Compodyprt(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Eqsys *E
 				fmt.Fprintf(fo, "-cat\n")
 			}
 
-			boidyprt(fo, __Compodyprt_id, Eqsys.Boi)
-			refadyprt(fo, __Compodyprt_id, Eqsys.Refa)
-			colldyprt(fo, __Compodyprt_id, Eqsys.Coll)
-			hccdyprt(fo, __Compodyprt_id, Eqsys.Hcc)
-			pipedyprt(fo, __Compodyprt_id, Eqsys.Pipe)
-			hexdyprt(fo, __Compodyprt_id, Eqsys.Hex)
-			stankdyprt(fo, __Compodyprt_id, Eqsys.Stank)
-			pumpdyprt(fo, __Compodyprt_id, Eqsys.Pump)
-			hclddyprt(fo, __Compodyprt_id, Eqsys.Hcload)
-			stheatdyprt(fo, __Compodyprt_id, Eqsys.Stheat)
-			Qmeasdyprt(fo, __Compodyprt_id, Eqsys.Qmeas)
-			Thexdyprt(fo, __Compodyprt_id, Eqsys.Thex)
-			PVdyprt(fo, __Compodyprt_id, Eqsys.PVcmp)
-			Desidyprt(fo, __Compodyprt_id, Eqsys.Desi)
-
-			paneldyprt(fo, __Compodyprt_id, Rdpnl)
+			boidyprt(fo, sim.__Compodyprt_id, Eqsys.Boi)
+			refadyprt(fo, sim.__Compodyprt_id, Eqsys.Refa)
+			colldyprt(fo, sim.__Compodyprt_id, Eqsys.Coll)
+			hccdyprt(fo, sim.__Compodyprt_id, Eqsys.Hcc)
+			pipedyprt(fo, sim.__Compodyprt_id, Eqsys.Pipe)
+			hexdyprt(fo, sim.__Compodyprt_id, Eqsys.Hex)
+			stankdyprt(fo, sim.__Compodyprt_id, Eqsys.Stank, sim.Cff_kWh)
+			pumpdyprt(fo, sim.__Compodyprt_id, Eqsys.Pump)
+			hclddyprt(fo, sim.__Compodyprt_id, Eqsys.Hcload)
+			stheatdyprt(fo, sim.__Compodyprt_id, Eqsys.Stheat, sim.Cff_kWh)
+			Qmeasdyprt(fo, sim.__Compodyprt_id, Eqsys.Qmeas)
+			Thexdyprt(fo, sim.__Compodyprt_id, Eqsys.Thex)
+			PVdyprt(fo, sim.__Compodyprt_id, Eqsys.PVcmp)
+			Desidyprt(fo, sim.__Compodyprt_id, Eqsys.Desi)
+
+			paneldyprt(fo, sim.__Compodyprt_id, Rdpnl)
 
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
 
-			__Compodyprt_id++
+			sim.__Compodyprt_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d\n", mon, day)
 
-	boidyprt(fo, __Compodyprt_id, Eqsys.Boi)
-	refadyprt(fo, __Compodyprt_id, Eqsys.Refa)
-	colldyprt(fo, __Compodyprt_id, Eqsys.Coll)
-	hccdyprt(fo, __Compodyprt_id, Eqsys.Hcc)
-	pipedyprt(fo, __Compodyprt_id, Eqsys.Pipe)
-	hexdyprt(fo, __Compodyprt_id, Eqsys.Hex)
-	stankdyprt(fo, __Compodyprt_id, Eqsys.Stank)
-	pumpdyprt(fo, __Compodyprt_id, Eqsys.Pump)
-	hclddyprt(fo, __Compodyprt_id, Eqsys.Hcload)
-	stheatdyprt(fo, __Compodyprt_id, Eqsys.Stheat)
-	Qmeasdyprt(fo, __Compodyprt_id, Eqsys.Qmeas)
-	Thexdyprt(fo, __Compodyprt_id, Eqsys.Thex)
-	PVdyprt(fo, __Compodyprt_id, Eqsys.PVcmp)
-	Desidyprt(fo, __Compodyprt_id, Eqsys.Desi)
-
-	paneldyprt(fo, __Compodyprt_id, Rdpnl)
+	boidyprt(fo, sim.__Compodyprt_id, Eqsys.Boi)
+	refadyprt(fo, sim.__Compodyprt_id, Eqsys.Refa)
+	colldyprt(fo, sim.__Compodyprt_id, Eqsys.Coll)
+	hccdyprt(fo, sim.__Compodyprt_id, Eqsys.Hcc)
+	pipedyprt(fo, sim.__Compodyprt_id, Eqsys.Pipe)
+	hexdyprt(fo, sim.__Compodyprt_id, Eqsys.Hex)
+	stankdyprt(fo, sim.__Compodyprt_id, Eqsys.Stank, sim.Cff_kWh)
+	pumpdyprt(fo, sim.__Compodyprt_id, Eqsys.Pump)
+	hclddyprt(fo, sim.__Compodyprt_id, Eqsys.Hcload)
+	stheatdyprt(fo, sim.__Compodyprt_id, Eqsys.Stheat, sim.Cff_kWh)
+	Qmeasdyprt(fo, sim.__Compodyprt_id, Eqsys.Qmeas)
+	Thexdyprt(fo, sim.__Compodyprt_id, Eqsys.Thex)
+	PVdyprt(fo, sim.__Compodyprt_id, Eqsys.PVcmp)
+	Desidyprt(fo, sim.__Compodyprt_id, Eqsys.Desi)
+
+	paneldyprt(fo, sim.__Compodyprt_id, Rdpnl)
 
 }
 
 /* システム要素機器の月集計結果出力 */
 
-var __Compomonprt_id int
-
-func Compomonprt(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Eqsys *EQSYS, Rdpnl []*RDPNL) {
-	if __Compomonprt_id == 0 {
+func (sim *Simulation) Compomonprt(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Eqsys *EQSYS, Rdpnl []*RDPNL) {
+	if sim.__Compomonprt_id == 0 {
 		ttldyprint(fo, mrk, Simc)
 
 		for j := 0; j < 2; j++ {
@@ -153,55 +148,54 @@ func Compomonprt(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, Eqsys *
 				fmt.Fprintf(fo, "-cat\n")
 			}
 
-			boimonprt(fo, __Compomonprt_id, Eqsys.Boi)
-			refamonprt(fo, __Compomonprt_id, Eqsys.Refa)
-			collmonprt(fo, __Compomonprt_id, Eqsys.Coll)
-			hccmonprt(fo, __Compomonprt_id, Eqsys.Hcc)
-			pipemonprt(fo, __Compomonprt_id, Eqsys.Pipe)
-			hexmonprt(fo, __Compomonprt_id, Eqsys.Hex)
-			stankmonprt(fo, __Compomonprt_id, Eqsys.Stank)
-			pumpmonprt(fo, __Compomonprt_id, Eqsys.Pump)
-			hcldmonprt(fo, __Compomonprt_id, Eqsys.Hcload)
-			stheatmonprt(fo, __Compomonprt_id, Eqsys.Stheat)
-			Qmeasmonprt(fo, __Compomonprt_id, Eqsys.Qmeas)
-			Thexmonprt(fo, __Compomonprt_id, Eqsys.Thex)
-			PVmonprt(fo, __Compomonprt_id, Eqsys.PVcmp)
-
-			panelmonprt(fo, __Compomonprt_id, Rdpnl)
+			boimonprt(fo, sim.__Compomonprt_id, Eqsys.Boi)
+			refamonprt(fo, sim.__Compomonprt_id, Eqsys.Refa)
+			collmonprt(fo, sim.__Compomonprt_id, Eqsys.Coll)
+			hccmonprt(fo, sim.__Compomonprt_id, Eqsys.Hcc)
+			pipemonprt(fo, sim.__Compomonprt_id, Eqsys.Pipe)
+			hexmonprt(fo, sim.__Compomonprt_id, Eqsys.Hex)
+			stankmonprt(fo, sim.__Compomonprt_id, Eqsys.Stank, sim.Cff_kWh)
+			pumpmonprt(fo, sim.__Compomonprt_id, Eqsys.Pump)
+			hcldmonprt(fo, sim.__Compomonprt_id, Eqsys.Hcload)
+			stheatmonprt(fo, sim.__Compomonprt_id, Eqsys.Stheat, sim.Cff_kWh)
+			Qmeasmonprt(fo, sim.__Compomonprt_id, Eqsys.Qmeas)
+			Thexmonprt(fo, sim.__Compomonprt_id, Eqsys.Thex)
+			PVmonprt(fo, sim.__Compomonprt_id, Eqsys.PVcmp)
+
+			panelmonprt(fo, sim.__Compomonprt_id, Rdpnl)
 
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
 
-			__Compomonprt_id++
+			sim.__Compomonprt_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d\n", mon, day)
 
-	boimonprt(fo, __Compomonprt_id, Eqsys.Boi)
-	refamonprt(fo, __Compomonprt_id, Eqsys.Refa)
-	collmonprt(fo, __Compomonprt_id, Eqsys.Coll)
-	hccmonprt(fo, __Compomonprt_id, Eqsys.Hcc)
-	pipemonprt(fo, __Compomonprt_id, Eqsys.Pipe)
-	hexmonprt(fo, __Compomonprt_id, Eqsys.Hex)
-	stankmonprt(fo, __Compomonprt_id, Eqsys.Stank)
-	pumpmonprt(fo, __Compomonprt_id, Eqsys.Pump)
-	hcldmonprt(fo, __Compomonprt_id, Eqsys.Hcload)
-	stheatmonprt(fo, __Compomonprt_id, Eqsys.Stheat)
-	Qmeasmonprt(fo, __Compomonprt_id, Eqsys.Qmeas)
-	Thexmonprt(fo, __Compomonprt_id, Eqsys.Thex)
-	PVmonprt(fo, __Compomonprt_id, Eqsys.PVcmp)
-
-	panelmonprt(fo, __Compomonprt_id, Rdpnl)
+	boimonprt(fo, sim.__Compomonprt_id, Eqsys.Boi)
+	refamonprt(fo, sim.__Compomonprt_id, Eqsys.Refa)
+	collmonprt(fo, sim.__Compomonprt_id, Eqsys.Coll)
+	hccmonprt(fo, sim.__Compomonprt_id, Eqsys.Hcc)
+	pipemonprt(fo, sim.__Compomonprt_id, Eqsys.Pipe)
+	hexmonprt(fo, sim.__Compomonprt_id, Eqsys.Hex)
+	stankmonprt(fo, sim.__Compomonprt_id, Eqsys.Stank, sim.Cff_kWh)
+	pumpmonprt(fo, sim.__Compomonprt_id, Eqsys.Pump)
+	hcldmonprt(fo, sim.__Compomonprt_id, Eqsys.Hcload)
+	stheatmonprt(fo, sim.__Compomonprt_id, Eqsys.Stheat, sim.Cff_kWh)
+	Qmeasmonprt(fo, sim.__Compomonprt_id, Eqsys.Qmeas)
+	Thexmonprt(fo, sim.__Compomonprt_id, Eqsys.Thex)
+	PVmonprt(fo, sim.__Compomonprt_id, Eqsys.PVcmp)
+
+	panelmonprt(fo, sim.__Compomonprt_id, Rdpnl)
 }
 
 /* システム要素機器の年集計結果出力 */
 
-var __Compomtprt_id int = 0
-
-func Compomtprt(fo io.Writer, mrk string, Simc *SIMCONTL, Eqsys *EQSYS, Rdpnl []*RDPNL) {
-	if __Compomtprt_id == 0 {
+func (sim *Simulation) Compomtprt(fo io.Writer, mrk string, Simc *SIMCONTL, Eqsys *EQSYS, Rdpnl []*RDPNL) {
+	if sim.__Compomtprt_id == 0 {
 		ttlmtprint(fo, mrk, Simc)
 
 		for j := 0; j < 2; j++ {
@@ -209,32 +203,32 @@ func Compomtprt(fo io.Writer, mrk string, Simc *SIMCONTL, Eqsys *EQSYS, Rdpnl []
 				fmt.Fprintf(fo, "-cat\n")
 			}
 
-			stheatmtprt(fo, __Compomtprt_id, Eqsys.Stheat, 0, 0)
-			boimtprt(fo, __Compomtprt_id, Eqsys.Boi, 0, 0)
-			refamtprt(fo, __Compomtprt_id, Eqsys.Refa, 0, 0)
-			pumpmtprt(fo, __Compomtprt_id, Eqsys.Pump, 0, 0)
-			PVmtprt(fo, __Compomtprt_id, Eqsys.PVcmp, 0, 0)
-			hcldmtprt(fo, __Compomtprt_id, 0, 0, Eqsys.Hcload)
-			panelmtprt(fo, __Compomtprt_id, Rdpnl, 0, 0)
+			stheatmtprt(fo, sim.__Compomtprt_id, Eqsys.Stheat, 0, 0, sim.Cff_kWh)
+			boimtprt(fo, sim.__Compomtprt_id, Eqsys.Boi, 0, 0, sim.Cff_kWh)
+			refamtprt(fo, sim.__Compomtprt_id, Eqsys.Refa, 0, 0, sim.Cff_kWh)
+			pumpmtprt(fo, sim.__Compomtprt_id, Eqsys.Pump, 0, 0, sim.Cff_kWh)
+			PVmtprt(fo, sim.__Compomtprt_id, Eqsys.PVcmp, 0, 0, sim.Cff_kWh)
+			hcldmtprt(fo, sim.__Compomtprt_id, 0, 0, Eqsys.Hcload, sim.Cff_kWh)
+			panelmtprt(fo, sim.__Compomtprt_id, Rdpnl, 0, 0, sim.Cff_kWh)
 
 			if j == 0 {
 				fmt.Fprintf(fo, "*\n#\n")
 			}
 
-			__Compomtprt_id++
+			sim.__Compomtprt_id++
 		}
 	}
 
 	for mo := 1; mo <= 12; mo++ {
 		for tt := 1; tt <= 24; tt++ {
 			fmt.Fprintf(fo, "%02d %02d\n", mo, tt)
-			stheatmtprt(fo, __Compomtprt_id, Eqsys.Stheat, mo, tt)
-			boimtprt(fo, __Compomtprt_id, Eqsys.Boi, mo, tt)
-			refamtprt(fo, __Compomtprt_id, Eqsys.Refa, mo, tt)
-			pumpmtprt(fo, __Compomtprt_id, Eqsys.Pump, mo, tt)
-			PVmtprt(fo, __Compomtprt_id, Eqsys.PVcmp, mo, tt)
-			hcldmtprt(fo, __Compomtprt_id, mo, tt, Eqsys.Hcload)
-			panelmtprt(fo, __Compomtprt_id, Rdpnl, mo, tt)
+			stheatmtprt(fo, sim.__Compomtprt_id, Eqsys.Stheat, mo, tt, sim.Cff_kWh)
+			boimtprt(fo, sim.__Compomtprt_id, Eqsys.Boi, mo, tt, sim.Cff_kWh)
+			refamtprt(fo, sim.__Compomtprt_id, Eqsys.Refa, mo, tt, sim.Cff_kWh)
+			pumpmtprt(fo, sim.__Compomtprt_id, Eqsys.Pump, mo, tt, sim.Cff_kWh)
+			PVmtprt(fo, sim.__Compomtprt_id, Eqsys.PVcmp, mo, tt, sim.Cff_kWh)
+			hcldmtprt(fo, sim.__Compomtprt_id, mo, tt, Eqsys.Hcload, sim.Cff_kWh)
+			panelmtprt(fo, sim.__Compomtprt_id, Rdpnl, mo, tt, sim.Cff_kWh)
 		}
 	}
 }
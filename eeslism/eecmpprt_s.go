@@ -5,9 +5,6 @@ import (
 	"io"
 )
 
-var __Hcmpprint_id int = 0
-var __Hstkprint_id int = 0
-
 /*
 Hcmpprint (Hourly Component Output)
 
@@ -35,10 +32,10 @@ Hcmpprint (Hourly Component Output)
 各設備機器の運転状況とエネルギー消費量を時刻ごとに詳細に分析し、
 省エネルギー対策の効果評価や、最適な設備システム設計を行うための重要なデータ出力機能を提供します。
 */
-func Hcmpprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64, Eqsys *EQSYS, Rdpnl []*RDPNL) {
+func (sim *Simulation) Hcmpprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time float64, Eqsys *EQSYS, Rdpnl []*RDPNL) {
 	var j int
 
-	if __Hcmpprint_id == 0 {
+	if sim.__Hcmpprint_id == 0 {
 		ttlprint(fo, mrk, Simc)
 
 		for j = 0; j < 2; j++ {
@@ -46,25 +43,25 @@ func Hcmpprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time floa
 				fmt.Fprintln(fo, "-cat")
 			}
 
-			boiprint(fo, __Hcmpprint_id, Eqsys.Boi)
-			refaprint(fo, __Hcmpprint_id, Eqsys.Refa)
-			collprint(fo, __Hcmpprint_id, Eqsys.Coll)
-			hccprint(fo, __Hcmpprint_id, Eqsys.Hcc)
-			pipeprint(fo, __Hcmpprint_id, Eqsys.Pipe)
-			hexprint(fo, __Hcmpprint_id, Eqsys.Hex)
-			stankcmpprt(fo, __Hcmpprint_id, Eqsys.Stank)
-			pumpprint(fo, __Hcmpprint_id, Eqsys.Pump)
-			hcldprint(fo, __Hcmpprint_id, Eqsys.Hcload)
-			vavprint(fo, __Hcmpprint_id, Eqsys.Vav)
-			stheatprint(fo, __Hcmpprint_id, Eqsys.Stheat)
-			Thexprint(fo, __Hcmpprint_id, Eqsys.Thex)
-			Qmeasprint(fo, __Hcmpprint_id, Eqsys.Qmeas)
-			PVprint(fo, __Hcmpprint_id, Eqsys.PVcmp)
-			Desiprint(fo, __Hcmpprint_id, Eqsys.Desi)
-			Evacprint(fo, __Hcmpprint_id, Eqsys.Evac)
+			boiprint(fo, sim.__Hcmpprint_id, Eqsys.Boi)
+			refaprint(fo, sim.__Hcmpprint_id, Eqsys.Refa)
+			collprint(fo, sim.__Hcmpprint_id, Eqsys.Coll)
+			hccprint(fo, sim.__Hcmpprint_id, Eqsys.Hcc)
+			pipeprint(fo, sim.__Hcmpprint_id, Eqsys.Pipe)
+			hexprint(fo, sim.__Hcmpprint_id, Eqsys.Hex)
+			stankcmpprt(fo, sim.__Hcmpprint_id, Eqsys.Stank)
+			pumpprint(fo, sim.__Hcmpprint_id, Eqsys.Pump)
+			hcldprint(fo, sim.__Hcmpprint_id, Eqsys.Hcload)
+			vavprint(fo, sim.__Hcmpprint_id, Eqsys.Vav)
+			stheatprint(fo, sim.__Hcmpprint_id, Eqsys.Stheat)
+			Thexprint(fo, sim.__Hcmpprint_id, Eqsys.Thex)
+			Qmeasprint(fo, sim.__Hcmpprint_id, Eqsys.Qmeas)
+			PVprint(fo, sim.__Hcmpprint_id, Eqsys.PVcmp)
+			Desiprint(fo, sim.__Hcmpprint_id, Eqsys.Desi)
+			Evacprint(fo, sim.__Hcmpprint_id, Eqsys.Evac)
 
 			if SIMUL_BUILDG {
-				panelprint(fo, __Hcmpprint_id, Rdpnl)
+				panelprint(fo, sim.__Hcmpprint_id, Rdpnl)
 			}
 
 			if j == 0 {
@@ -72,30 +69,31 @@ func Hcmpprint(fo io.Writer, mrk string, Simc *SIMCONTL, mon, day int, time floa
 				fmt.Fprintln(fo, "#")
 			}
 
-			__Hcmpprint_id++
+			sim.__Hcmpprint_id++
 		}
 	}
 
+	sim.yearprint(fo, Simc, " ")
 	fmt.Fprintf(fo, "%02d %02d %5.2f\n", mon, day, time)
-	boiprint(fo, __Hcmpprint_id, Eqsys.Boi)
-	refaprint(fo, __Hcmpprint_id, Eqsys.Refa)
-	collprint(fo, __Hcmpprint_id, Eqsys.Coll)
-	hccprint(fo, __Hcmpprint_id, Eqsys.Hcc)
-	pipeprint(fo, __Hcmpprint_id, Eqsys.Pipe)
-	hexprint(fo, __Hcmpprint_id, Eqsys.Hex)
-	stankcmpprt(fo, __Hcmpprint_id, Eqsys.Stank)
-	pumpprint(fo, __Hcmpprint_id, Eqsys.Pump)
-	hcldprint(fo, __Hcmpprint_id, Eqsys.Hcload)
-	vavprint(fo, __Hcmpprint_id, Eqsys.Vav)
-	stheatprint(fo, __Hcmpprint_id, Eqsys.Stheat)
-	Thexprint(fo, __Hcmpprint_id, Eqsys.Thex)
-	Qmeasprint(fo, __Hcmpprint_id, Eqsys.Qmeas)
-	PVprint(fo, __Hcmpprint_id, Eqsys.PVcmp)
-	Desiprint(fo, __Hcmpprint_id, Eqsys.Desi)
-	Evacprint(fo, __Hcmpprint_id, Eqsys.Evac)
+	boiprint(fo, sim.__Hcmpprint_id, Eqsys.Boi)
+	refaprint(fo, sim.__Hcmpprint_id, Eqsys.Refa)
+	collprint(fo, sim.__Hcmpprint_id, Eqsys.Coll)
+	hccprint(fo, sim.__Hcmpprint_id, Eqsys.Hcc)
+	pipeprint(fo, sim.__Hcmpprint_id, Eqsys.Pipe)
+	hexprint(fo, sim.__Hcmpprint_id, Eqsys.Hex)
+	stankcmpprt(fo, sim.__Hcmpprint_id, Eqsys.Stank)
+	pumpprint(fo, sim.__Hcmpprint_id, Eqsys.Pump)
+	hcldprint(fo, sim.__Hcmpprint_id, Eqsys.Hcload)
+	vavprint(fo, sim.__Hcmpprint_id, Eqsys.Vav)
+	stheatprint(fo, sim.__Hcmpprint_id, Eqsys.Stheat)
+	Thexprint(fo, sim.__Hcmpprint_id, Eqsys.Thex)
+	Qmeasprint(fo, sim.__Hcmpprint_id, Eqsys.Qmeas)
+	PVprint(fo, sim.__Hcmpprint_id, Eqsys.PVcmp)
+	Desiprint(fo, sim.__Hcmpprint_id, Eqsys.Desi)
+	Evacprint(fo, sim.__Hcmpprint_id, Eqsys.Evac)
 
 	if SIMUL_BUILDG {
-		panelprint(fo, __Hcmpprint_id, Rdpnl)
+		panelprint(fo, sim.__Hcmpprint_id, Rdpnl)
 	}
 
 }
@@ -125,15 +123,15 @@ Hstkprint (Hourly Storage Tank Output)
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要なデータ出力機能を提供します。
 */
-func Hstkprint(fo io.Writer, title string, mon int, day int, time float64, Eqsys *EQSYS) {
-	if __Hstkprint_id == 0 {
+func (sim *Simulation) Hstkprint(fo io.Writer, title string, mon int, day int, time float64, Eqsys *EQSYS) {
+	if sim.__Hstkprint_id == 0 {
 		fmt.Fprintf(fo, "%s ;\n", title)
-		stankivprt(fo, __Hstkprint_id, Eqsys.Stank)
-		__Hstkprint_id++
+		stankivprt(fo, sim.__Hstkprint_id, Eqsys.Stank)
+		sim.__Hstkprint_id++
 	}
 	if len(Eqsys.Stank) > 0 {
 		fmt.Fprintf(fo, "%02d %02d %5.2f  ", mon, day, time)
-		stankivprt(fo, __Hstkprint_id, Eqsys.Stank)
+		stankivprt(fo, sim.__Hstkprint_id, Eqsys.Stank)
 	}
 	fmt.Fprintln(fo, " ;")
 }
@@ -3,6 +3,7 @@ package eeslism
 import (
 	"errors"
 	"fmt"
+	"io"
 )
 
 func cmpprint(id, N int, cmp []COMPNT, Elout []*ELOUT, Elin []*ELIN) {
@@ -51,7 +52,7 @@ func eloutprint(id int, E []*ELOUT, cmp []*COMPNT) {
 	}
 }
 
-func eloutfprint(id int, E []*ELOUT, cmp []*COMPNT) {
+func eloutfprint(id int, E []*ELOUT, cmp []*COMPNT, Ferr io.Writer) {
 	if id == 1 {
 		fmt.Fprintf(Ferr, "ELOUT\n  n         id fld contl sysld Cmp   G      cfo    cfin\n")
 	}
@@ -133,7 +134,7 @@ func elinprint(id int, C []*COMPNT, eo []*ELOUT, ei []*ELIN) {
 	}
 }
 
-func elinfprint(id int, C []*COMPNT, eo []*ELOUT, ei []*ELIN) {
+func elinfprint(id int, C []*COMPNT, eo []*ELOUT, ei []*ELIN, Ferr io.Writer) {
 	var E *ELIN
 	var Eo *ELOUT
 	var o, v int
@@ -167,7 +167,7 @@ qdaysum (Daily Summary Accumulation for Heat/Cooling Quantities)
 この関数は、建物の熱負荷を日単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要な役割を果たします。
 */
-func qdaysum(time int64, control ControlSWType, Q float64, Qd *QDAY) {
+func qdaysum(time int64, control ControlSWType, Q float64, Qd *QDAY, Cff_kWh float64) {
 	if control != OFF_SW {
 		if Q > Q_EPSILON {
 			Qd.H += Q
@@ -210,7 +210,7 @@ qmonsum (Monthly Summary Accumulation for Heat/Cooling Quantities)
 この関数は、建物の熱負荷を月単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要な役割を果たします。
 */
-func qmonsum(Mon int, Day int, time int, control ControlSWType, Q float64, Qd *QDAY, Dayend int, SimDayend int) {
+func qmonsum(Mon int, Day int, time int, control ControlSWType, Q float64, Qd *QDAY, Dayend int, SimDayend int, Cff_kWh float64) {
 	MoNdTt := int64(1000000*Mon + 10000*Day + time)
 
 	if control != OFF_SW {
@@ -257,7 +257,7 @@ qdaysumNotOpe (Daily Summary Accumulation for Heat/Cooling Quantities, Including
 この関数は、建物の熱負荷を日単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要な役割を果たします。
 */
-func qdaysumNotOpe(time int64, Q float64, Qd *QDAY) {
+func qdaysumNotOpe(time int64, Q float64, Qd *QDAY, Cff_kWh float64) {
 	if Q > Q_EPSILON {
 		Qd.H += Q
 		maxmark(&Qd.Hmx, &Qd.Hmxtime, Q, time)
@@ -300,7 +300,7 @@ qmonsumNotOpe (Monthly Summary Accumulation for Heat/Cooling Quantities, Includi
 この関数は、建物の熱負荷を月単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要な役割を果たします。
 */
-func qmonsumNotOpe(Mon int, Day int, time int, Q float64, Qd *QDAY, Dayend int, SimDayend int) {
+func qmonsumNotOpe(Mon int, Day int, time int, Q float64, Qd *QDAY, Dayend int, SimDayend int, Cff_kWh float64) {
 	MoNdTt := int64(1000000*Mon + 10000*Day + time)
 
 	if Q > Q_EPSILON {
@@ -370,7 +370,7 @@ edaysum (Daily Summary Accumulation for Energy Quantities)
 この関数は、建物のエネルギー消費量を日単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要な役割を果たします。
 */
-func edaysum(time int, control ControlSWType, E float64, Ed *EDAY) {
+func edaysum(time int, control ControlSWType, E float64, Ed *EDAY, Cff_kWh float64) {
 	if control != OFF_SW {
 		Ed.D += E
 		maxmark(&Ed.Mx, &Ed.Mxtime, E, int64(time))
@@ -406,7 +406,7 @@ emonsum (Monthly Summary Accumulation for Energy Quantities)
 この関数は、建物のエネルギー消費量を月単位で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要な役割を果たします。
 */
-func emonsum(Mon, Day, time int, control ControlSWType, E float64, Ed *EDAY, Dayend, SimDayend int) {
+func emonsum(Mon, Day, time int, control ControlSWType, E float64, Ed *EDAY, Dayend, SimDayend int, Cff_kWh float64) {
 	var MoNdTt int64 = int64(1000000*Mon + 10000*Day + time)
 
 	if control != OFF_SW {
@@ -0,0 +1,142 @@
+/*
+eeencoding.go (Character Encoding)
+
+入力データファイル、EFLファイル、VCFILE のファイルなどの文字コード（UTF-8、Shift_JIS）の変換を定義します。
+
+C版の入力データファイルや基礎データファイル（reflist.efl など）には Shift_JIS のものと UTF-8 のものがあり、
+室名やスケジュール名はバイト列として比較されます。Simulation.FS、Simulation.EflFS から読み込むファイルは、
+Simulation.Encoding の文字コードから UTF-8 に変換してから読み込みます。
+
+  - "auto"（既定）: UTF-8 として正しいファイルは UTF-8、それ以外は Shift_JIS とみなします。
+  - "utf-8": 変換しません。
+  - "shift_jis": Shift_JIS（CP932）から変換します。
+
+いずれの場合も先頭の BOM は除きます。計算結果などの出力ファイルは Simulation.OutputEncoding の文字コードで書き出します。
+Shift_JIS で表せない文字は `?` に置き換えます。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// 文字コードの名前
+const (
+	EncodingAuto     = "auto"
+	EncodingUTF8     = "utf-8"
+	EncodingShiftJIS = "shift_jis"
+)
+
+// utf8BOM は UTF-8 の BOM です。
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseEncoding は文字コードの名前 name を EncodingAuto、EncodingUTF8、EncodingShiftJIS のいずれかにします。
+// 空の場合は def を返します。
+func ParseEncoding(name, def string) (string, error) {
+	switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_") {
+	case "":
+		return def, nil
+	case "auto":
+		return EncodingAuto, nil
+	case "utf_8", "utf8":
+		return EncodingUTF8, nil
+	case "shift_jis", "sjis", "cp932", "windows_31j", "ms932":
+		return EncodingShiftJIS, nil
+	}
+	return "", fmt.Errorf("unknown encoding %q (expected auto, utf-8 or shift_jis)", name)
+}
+
+// DecodeText は文字コード enc（ParseEncoding の名前。空の場合は "auto"）の内容 b を UTF-8 にして返します。
+func DecodeText(b []byte, enc string) ([]byte, error) {
+	e, err := ParseEncoding(enc, EncodingAuto)
+	if err != nil {
+		return nil, err
+	}
+	return decodeText(b, e)
+}
+
+// decodeText は文字コード enc の内容 b を UTF-8 にして返します。
+func decodeText(b []byte, enc string) ([]byte, error) {
+	b = bytes.TrimPrefix(b, utf8BOM)
+	switch enc {
+	case EncodingUTF8:
+		return b, nil
+	case EncodingAuto, "":
+		if utf8.Valid(b) {
+			return b, nil
+		}
+	}
+	return japanese.ShiftJIS.NewDecoder().Bytes(b)
+}
+
+// decodingFS はファイルの内容を文字コード enc から UTF-8 に変換して読み込む fs.FS です。
+type decodingFS struct {
+	fsys fs.FS
+	enc  string
+}
+
+func (f decodingFS) Open(name string) (fs.File, error) {
+	fi, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if st, err := fi.Stat(); err == nil && st.IsDir() {
+		return fi, nil
+	}
+	defer fi.Close()
+
+	b, err := io.ReadAll(fi)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = decodeText(b, f.enc); err != nil {
+		return nil, &fs.PathError{Op: "decode", Path: name, Err: err}
+	}
+	return &overlayFile{Reader: bytes.NewReader(b), name: path.Base(name)}, nil
+}
+
+// encodingSink は出力ファイルを UTF-8 から文字コード enc に変換して書き出す OutputSink です。
+type encodingSink struct {
+	out OutputSink
+	enc encoding.Encoding
+}
+
+// newEncodingSink は文字コード enc で out に書き出す OutputSink を返します。enc が UTF-8 の場合は out を返します。
+func newEncodingSink(out OutputSink, enc string) OutputSink {
+	if enc != EncodingShiftJIS {
+		return out
+	}
+	return encodingSink{out: out, enc: japanese.ShiftJIS}
+}
+
+func (s encodingSink) Create(name string) (io.WriteCloser, error) {
+	w, err := s.out.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return &encodingWriter{Writer: transform.NewWriter(w, encoding.ReplaceUnsupported(s.enc.NewEncoder())), w: w}, nil
+}
+
+// encodingWriter は文字コードを変換して w に書き出します。Close で変換途中の内容を書き出してから w を閉じます。
+type encodingWriter struct {
+	*transform.Writer
+	w io.WriteCloser
+}
+
+func (e *encodingWriter) Close() error {
+	err := e.Writer.Close()
+	if cerr := e.w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package eeslism
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// TestDecodeText は文字コードの判定と UTF-8 への変換を確認する
+func TestDecodeText(t *testing.T) {
+	sjis, err := japanese.ShiftJIS.NewEncoder().String("居間 Vol=100 ;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		src, enc, want string
+	}{
+		{"居間 Vol=100 ;", "", "居間 Vol=100 ;"},
+		{"\xEF\xBB\xBF居間 Vol=100 ;", "auto", "居間 Vol=100 ;"},
+		{sjis, "auto", "居間 Vol=100 ;"},
+		{sjis, "Shift-JIS", "居間 Vol=100 ;"},
+		{sjis, "utf8", sjis},
+	}
+	for _, tt := range tests {
+		got, err := DecodeText([]byte(tt.src), tt.enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("DecodeText(%q, %q) = %q, want %q", tt.src, tt.enc, got, tt.want)
+		}
+	}
+	if _, err := DecodeText(nil, "euc-jp"); err == nil {
+		t.Error("unknown encoding: expected error")
+	}
+}
+
+// TestSimulationEncoding は Shift_JIS の入力データファイルの日本語の室名が UTF-8 の場合と同じく読み込まれ、
+// 出力ファイルが指定した文字コードで書き出されることを確認する
+func TestSimulationEncoding(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Skip("test input not found")
+	}
+	input := strings.ReplaceAll(string(b), "TestRoom", "居間")
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(data, outEnc string) *MemorySink {
+		t.Helper()
+		sim := NewSimulation("room.txt", "")
+		sim.FS = fstest.MapFS{"room.txt": {Data: []byte(data)}}
+		sim.OutputEncoding = outEnc
+		out := new(MemorySink)
+		sim.Output = out
+		if err := sim.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	want := string(run(input, "").Bytes("room_rm.es"))
+	if !strings.Contains(want, "居間") {
+		t.Fatalf("room name not in the output:\n%.300s", want)
+	}
+	if got := string(run(sjis, "").Bytes("room_rm.es")); got != want {
+		t.Error("output for the Shift_JIS input differs from the UTF-8 input")
+	}
+
+	got, err := DecodeText(run(input, "shift_jis").Bytes("room_rm.es"), "shift_jis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Error("Shift_JIS output differs from the UTF-8 output")
+	}
+
+	sim := NewSimulation("room.txt", "")
+	sim.FS = fstest.MapFS{"room.txt": {Data: []byte(input)}}
+	sim.Encoding = "latin1"
+	if err := sim.Init(); err == nil || !strings.Contains(err.Error(), "unknown encoding") {
+		t.Errorf("unknown encoding: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+eeerror.go (Simulation Errors)
+
+このファイルは、シミュレーションを継続できない場合に
+`Simulation.Run`（および`Entry`）が返すエラーの型を定義します。
+
+C版のEESLISMでは、入力データの誤りや気象データの不整合を検出すると
+エラーメッセージを出力して`exit()`していました。
+Go版ではプロセスを終了させず、以下のエラーを呼び出し元に返します。
+  - `InputError`: 建築・設備データやEFLファイルなど入力データの誤り
+  - `WeatherError`: 気象データファイルの読み込み失敗や日付の不整合
+  - `ConvergenceError`: 連立方程式が解けないなど数値計算の破綻
+
+いずれのエラーも、C版の終了コード（`EXIT_*`）を`ExitCode`で返します。
+コマンドラインから実行する場合は、この値をプロセスの終了コードとします。
+*/
+package eeslism
+
+import (
+	"fmt"
+	"io/fs"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// InputError は入力データの誤りを表します。
+type InputError struct {
+	Section   string // 入力データのセクション名（例: ROOM, EQPCAT）。特定できない場合は空
+	Keyword   string // 誤りを検出したキーワードまたはデータ
+	Component string // 誤りのある室、機器、ファイルなどの名前
+	Msg       string // 詳細なメッセージ
+	Code      int    // 終了コード（EXIT_*）
+}
+
+func (e *InputError) Error() string {
+	return errorString("input error", e.Section, e.Keyword, e.Component, e.Msg)
+}
+
+// ExitCode はコマンドラインから実行した場合の終了コードを返します。
+func (e *InputError) ExitCode() int {
+	return exitCode(e.Code)
+}
+
+// WeatherError は気象データの読み込みの失敗や、計算日と気象データの日付の不整合を表します。
+type WeatherError struct {
+	Section   string // 気象データを指定したセクション名（通常は GDAT）
+	Keyword   string // 誤りを検出したキーワードまたはデータ
+	Component string // 気象データファイル名
+	Msg       string // 詳細なメッセージ
+	Code      int    // 終了コード（EXIT_*）
+}
+
+func (e *WeatherError) Error() string {
+	return errorString("weather error", e.Section, e.Keyword, e.Component, e.Msg)
+}
+
+// ExitCode はコマンドラインから実行した場合の終了コードを返します。
+func (e *WeatherError) ExitCode() int {
+	return exitCode(e.Code)
+}
+
+// ConvergenceError は連立方程式の係数行列が特異であるなど、数値計算を継続できないことを表します。
+type ConvergenceError struct {
+	Section   string // 関係する入力データのセクション名。特定できない場合は空
+	Keyword   string // エラーを検出した計算処理
+	Component string // 関係する室、機器などの名前
+	Msg       string // 詳細なメッセージ
+	Code      int    // 終了コード（EXIT_*）
+}
+
+func (e *ConvergenceError) Error() string {
+	return errorString("convergence error", e.Section, e.Keyword, e.Component, e.Msg)
+}
+
+// ExitCode はコマンドラインから実行した場合の終了コードを返します。
+func (e *ConvergenceError) ExitCode() int {
+	return exitCode(e.Code)
+}
+
+func errorString(kind, section, keyword, component, msg string) string {
+	var b strings.Builder
+	b.WriteString("eeslism: ")
+	b.WriteString(kind)
+	if section != "" {
+		fmt.Fprintf(&b, " section=%s", section)
+	}
+	if keyword != "" {
+		fmt.Fprintf(&b, " keyword=%s", keyword)
+	}
+	if component != "" {
+		fmt.Fprintf(&b, " component=%s", component)
+	}
+	if msg != "" {
+		b.WriteString(": ")
+		b.WriteString(msg)
+	}
+	return b.String()
+}
+
+// 終了コードが指定されていないエラーは、C版の exit(1) と同じく 1 とする
+func exitCode(code int) int {
+	if code == 0 {
+		return 1
+	}
+	return code
+}
+
+/*
+recoverError (Recover Simulation Error)
+
+シミュレーション中に panic で送出されたエラーを回収し、*err に設定します。
+`InputError`などの型付きエラーはそのまま返します。
+パース処理などで送出された error や文字列は、入力データの誤りとして`InputError`に変換します。
+出力ファイルが作成できないなどのファイル操作のエラーはそのまま返します。
+ランタイムエラー（配列の範囲外参照など）は、スタックトレースを付けたエラーとして返します。
+*/
+func recoverError(r interface{}, err *error) {
+	switch e := r.(type) {
+	case *InputError, *WeatherError, *ConvergenceError:
+		*err = e.(error)
+	case runtime.Error:
+		*err = fmt.Errorf("eeslism: internal error: %v\n%s", e, debug.Stack())
+	case *fs.PathError:
+		*err = e
+	case error:
+		*err = &InputError{Msg: e.Error()}
+	default:
+		*err = &InputError{Msg: fmt.Sprint(e)}
+	}
+}
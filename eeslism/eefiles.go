@@ -1,10 +1,11 @@
 package eeslism
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"io"
+	"io/fs"
 	"strings"
 )
 
@@ -19,6 +20,7 @@ eeflopen (Energy Simulation File Open)
     外気温度、湿度、日射量などの気象データが不可欠です。
     `Simc.Wfname`で指定された気象データファイル（通常は年間気象データ）をオープンし、
     シミュレーション中に気象データを読み込めるようにします。
+    気象データファイルは EFLファイルと同じ FS（`Simc.EflFS`）から読み込みます。
     `Simc.Fwdata`と`Simc.Fwdata2`の二つのファイルポインターは、
     気象データを複数回読み込む必要がある場合（例えば、異なる計算モジュールで同時にアクセスする場合）に用いられます。
   - **supw.eflファイルのオープン**: `supw.efl`は、
@@ -37,32 +39,32 @@ eeflopen (Energy Simulation File Open)
 この関数は、建物のエネルギーシミュレーションのデータ入出力の基盤を形成し、
 シミュレーションの正確性、安定性、および再現性を向上させるための重要な役割を果たします。
 */
-func (Simc *SIMCONTL) eeflopen(Flout []*FLOUT, efl_path string) {
+func (Simc *SIMCONTL) eeflopen(Flout []*FLOUT, Ferr io.Writer) {
 	// 気象データファイルを開く
-	if Simc.Wdtype == 'H' {
-		var fullpath string
-		if filepath.IsAbs(Simc.Wfname) {
-			fullpath = Simc.Wfname
-		} else {
-			fullpath = filepath.Join(efl_path, Simc.Wfname)
-		}
-
-		var err error
-		Simc.Fwdata, err = os.Open(fullpath)
-		if err != nil {
-			Eprint("<eeflopen>", fullpath)
-			os.Exit(EXIT_WFILE)
+	if Simc.Wdtype == 'H' || Simc.Wdtype == 'P' || Simc.Wdtype == 'A' {
+		wdata, err := Simc.readEfl(Simc.Wfname)
+		if errors.Is(err, fs.ErrInvalid) {
+			// EFLファイルのディレクトリの外を指す名前（`..` を含むなど）
+			panic(&InputError{Section: "GDAT", Keyword: "FILE", Component: Simc.Wfname, Msg: err.Error()})
 		}
-		Simc.Fwdata2, err = os.Open(fullpath)
 		if err != nil {
-			Eprint("<eeflopen>", fullpath)
-			os.Exit(EXIT_WFILE)
+			Eprint("<eeflopen>", Simc.Wfname, Ferr)
+			panic(&WeatherError{Section: "GDAT", Keyword: "FILE", Component: Simc.Wfname, Msg: err.Error(), Code: EXIT_WFILE})
 		}
+		Simc.Fwdata = bytes.NewReader(wdata)
+		Simc.Fwdata2 = bytes.NewReader(wdata)
+	}
 
-		Simc.Ftsupw, err = ioutil.ReadFile(filepath.Join(efl_path, "supw.efl"))
-		if err != nil {
-			Eprint("<eeflopen>", "supw.efl")
-			os.Exit(EXIT_SUPW)
+	if Simc.Wdtype == 'C' {
+		Simc.wcsvopen(Ferr)
+	}
+
+	// EPW、拡張アメダス、CSV の給水温度は気温から求める（ref: wdGround）
+	if Simc.Wdtype == 'H' {
+		var err error
+		if Simc.Ftsupw, err = Simc.readEfl("supw.efl"); err != nil {
+			Eprint("<eeflopen>", "supw.efl", Ferr)
+			panic(&InputError{Component: "supw.efl", Msg: err.Error(), Code: EXIT_SUPW})
 		}
 	}
 
@@ -102,18 +104,18 @@ Eeflclose (Energy Simulation File Close)
 この関数は、建物のエネルギーシミュレーションのデータ入出力の最終ステップであり、
 シミュレーション結果の完全性と信頼性を確保するための重要な役割を果たします。
 */
-func Eeflclose(Flout []*FLOUT) {
+func Eeflclose(Flout []*FLOUT, Ferr io.Writer, out OutputSink) {
 	var fl *FLOUT
 
-	if Ferr != nil {
-		Ferr.Close()
+	if c, ok := Ferr.(io.Closer); ok {
+		c.Close()
 	}
 
 	for _, fl = range Flout {
-		fo, err := os.Create(fl.Fname)
+		fo, err := out.Create(fl.Fname)
 		if err != nil {
-			Eprint("<eeflopen>", fl.Fname)
-			os.Exit(EXIT_WFILE)
+			Eprint("<eeflopen>", fl.Fname, Ferr)
+			panic(err)
 		}
 		defer fo.Close()
 
@@ -0,0 +1,237 @@
+/*
+eefs.go (File Access)
+
+入力データファイル、EFLファイルの読み込みと、出力ファイルの書き出しの抽象化を定義します。
+
+  - 入力データファイル、PCMの物性値テーブル、VCFILE のファイルは Simulation.FS から読み込みます。
+    既定ではファイル名をそのまま os.Open に渡します。
+  - 曜日設定（dayweek.efl）、給水温度（supw.efl）、壁体材料リスト（wbmlist.efl）、
+    冷凍機（reflist.efl）、ポンプ・ファン（pumpfanlst.efl）のリスト、
+    および気象データファイルは Simulation.EflFS から読み込みます。
+    既定では EflPath のディレクトリ、EflPath が空の場合は実行ファイルに埋め込まれた Base です。
+  - 計算結果（.es）、ログ（.log）、作業ファイル（.ewk）、日影計算の結果（.gchi）は
+    Simulation.Output に書き出します。既定ではファイルシステムに書き出します。
+  - 読み込むファイルは Simulation.Encoding から UTF-8 に、出力ファイルは Simulation.OutputEncoding に
+    文字コードを変換します。ref: eeencoding.go
+
+これにより、ファイルシステムを用いない試験や WebAssembly での実行、
+アーカイブからの実行が可能になります。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/archlabjp/eeslism-go/Base"
+)
+
+// OutputSink は出力ファイルの書き出し先です。
+type OutputSink interface {
+	// Create は出力ファイル name を作成します。
+	// name は入力データファイル名の拡張子を除いた部分に接尾辞（`_rm.es` など）を付けたものです。
+	Create(name string) (io.WriteCloser, error)
+}
+
+// FileSink は出力ファイルをファイルシステムに書き出す OutputSink です。
+type FileSink struct{}
+
+// Create はファイル name を作成します。
+func (FileSink) Create(name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+// MemorySink は出力ファイルをメモリ上に保持する OutputSink です。
+// 同じ名前のファイルを再度作成した場合は、内容を置き換えます。
+type MemorySink struct {
+	mu    sync.Mutex
+	files map[string]*bytes.Buffer
+}
+
+// Create はメモリ上にファイル name を作成します。
+func (m *MemorySink) Create(name string) (io.WriteCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(map[string]*bytes.Buffer)
+	}
+	b := new(bytes.Buffer)
+	m.files[name] = b
+	return memFile{b}, nil
+}
+
+// Names は作成されたファイル名を昇順で返します。
+func (m *MemorySink) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bytes はファイル name の内容のコピーを返します。ファイルがない場合は nil を返します。
+func (m *MemorySink) Bytes(name string) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.files[name]; ok {
+		return append([]byte{}, b.Bytes()...)
+	}
+	return nil
+}
+
+type memFile struct {
+	*bytes.Buffer
+}
+
+func (memFile) Close() error { return nil }
+
+// osFS はファイル名をそのまま os.Open に渡す fs.FS です。
+// os.DirFS と異なり、絶対パスや `..` を含む相対パスも扱います。
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+// dirFS はディレクトリ dir からの相対パスでファイルを開く fs.FS です。
+// os.DirFS と同じく、`..` を含む名前など fs.ValidPath でない名前は fs.ErrInvalid とし、dir の外のファイルは開きません。
+type dirFS string
+
+func (dir dirFS) Open(name string) (fs.File, error) {
+	return os.DirFS(string(dir)).Open(name)
+}
+
+// overlayFS は files のファイルをメモリ上から、その他のファイルを base から読み込む fs.FS です。
+// base が nil の場合は files のファイルのみを読み込みます。
+type overlayFS struct {
+	files map[string][]byte
+	base  fs.FS
+}
+
+func (f overlayFS) Open(name string) (fs.File, error) {
+	if data, ok := f.files[name]; ok {
+		return &overlayFile{Reader: bytes.NewReader(data), name: path.Base(name)}, nil
+	}
+	if f.base == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return f.base.Open(name)
+}
+
+// OverlayFS はファイル名と内容の files をメモリ上から、その他のファイルを base から読み込む fs.FS を返します。
+// base が nil の場合は files のファイルのみを読み込みます。Simulation の FS、EflFS に用います。
+func OverlayFS(files map[string][]byte, base fs.FS) fs.FS {
+	return overlayFS{files: files, base: base}
+}
+
+// overlayFile は overlayFS で開いたメモリ上のファイルです。fs.FileInfo を兼ねます。
+type overlayFile struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *overlayFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *overlayFile) Close() error               { return nil }
+func (f *overlayFile) Name() string               { return f.name }
+func (f *overlayFile) Mode() fs.FileMode          { return 0444 }
+func (f *overlayFile) ModTime() time.Time         { return time.Time{} }
+func (f *overlayFile) IsDir() bool                { return false }
+func (f *overlayFile) Sys() interface{}           { return nil }
+
+// eflFS は EFLファイルのディレクトリ efl_path の fs.FS を返します。
+// efl_path が空の場合は埋め込みの Base を返します。
+func eflFS(efl_path string) fs.FS {
+	if efl_path != "" {
+		return dirFS(efl_path)
+	}
+	return base.FS
+}
+
+// files は Simulation の設定から入出力先を決定し、Simc に設定します。
+func (sim *Simulation) files() {
+	Simc := sim.Simc
+
+	Simc.FS = sim.FS
+	if Simc.FS == nil {
+		Simc.FS = osFS{}
+	}
+	Simc.RefDir = sim.RefDir
+
+	Simc.EflFS = sim.EflFS
+	if Simc.EflFS == nil {
+		Simc.EflFS = eflFS(sim.EflPath)
+	}
+
+	Simc.Output = sim.Output
+	if Simc.Output == nil {
+		Simc.Output = FileSink{}
+	}
+
+	// 文字コードの変換
+	enc, err := ParseEncoding(sim.Encoding, EncodingAuto)
+	if err != nil {
+		panic(&InputError{Keyword: "Encoding", Msg: err.Error()})
+	}
+	Simc.FS = decodingFS{Simc.FS, enc}
+	Simc.EflFS = decodingFS{Simc.EflFS, enc}
+
+	outEnc, err := ParseEncoding(sim.OutputEncoding, EncodingUTF8)
+	if err != nil || outEnc == EncodingAuto {
+		panic(&InputError{Keyword: "OutputEncoding", Msg: fmt.Sprintf("invalid output encoding %q", sim.OutputEncoding)})
+	}
+	Simc.Output = newEncodingSink(Simc.Output, outEnc)
+}
+
+// readFile は入力データファイルと同じ FS からファイル name を読み込みます。
+// FS が設定されていない場合はファイルシステムから読み込みます。
+func (Simc *SIMCONTL) readFile(name string) ([]byte, error) {
+	if Simc.FS == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(Simc.FS, name)
+}
+
+// readEfl は EFLファイル name を読み込みます。
+// name が絶対パスの場合は、入力データファイルと同じ FS から読み込みます。
+func (Simc *SIMCONTL) readEfl(name string) ([]byte, error) {
+	if filepath.IsAbs(name) || Simc.EflFS == nil {
+		return Simc.readFile(name)
+	}
+	return fs.ReadFile(Simc.EflFS, filepath.ToSlash(name))
+}
+
+// readRef は入力データファイルから参照するファイル name を、入力データファイルのディレクトリ（RefDir）、
+// EFLファイルのディレクトリの順に探して読み込みます（`#include` と同じ）。
+func (Simc *SIMCONTL) readRef(name string) ([]byte, error) {
+	if !path.IsAbs(name) && !strings.Contains(name, ":") {
+		dir := strings.ReplaceAll(Simc.RefDir, `\`, "/")
+		if dir == "" {
+			dir = path.Dir(strings.ReplaceAll(Simc.File, `\`, "/"))
+		}
+		file := path.Join(dir, name)
+		if b, err := Simc.readFile(file); err == nil {
+			return b, nil
+		}
+	}
+	return Simc.readEfl(name)
+}
+
+// create は出力ファイル name を作成します。
+func (Simc *SIMCONTL) create(name string) (io.WriteCloser, error) {
+	if Simc.Output == nil {
+		return os.Create(name)
+	}
+	return Simc.Output.Create(name)
+}
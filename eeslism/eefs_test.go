@@ -0,0 +1,58 @@
+package eeslism
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestSimulation_FS は入力データファイルを fs.FS から読み込み、出力を MemorySink に書き出した結果が
+// ファイルシステムを用いた場合と同じになることを確認する。EFLファイルは埋め込みの Base を用いる
+func TestSimulation_FS(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := NewSimulation(copySimulationInput(t, src, dir), eflPath).Run(); err != nil {
+		t.Fatal(err)
+	}
+	ref := readSimulationOutputs(t, dir)
+
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := NewSimulation("model/room.txt", "")
+	sim.FS = fstest.MapFS{"model/room.txt": {Data: b}}
+	out := new(MemorySink)
+	sim.Output = out
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range ref {
+		outName := "model/room" + strings.TrimPrefix(name, "simple_room_schedule_test")
+		got := string(out.Bytes(outName))
+		if got == "" {
+			t.Errorf("%s is not written to the sink (%v)", outName, out.Names())
+			continue
+		}
+		want = strings.ReplaceAll(want, "<dir>\\simple_room_schedule_test", "model\\room")
+		if got != want {
+			t.Errorf("%s differs from the file system run", outName)
+		}
+	}
+	for _, name := range []string{"model/room.log", "model/roombdata0.ewk"} {
+		if out.Bytes(name) == nil {
+			t.Errorf("%s is not written to the sink", name)
+		}
+	}
+	if files, _ := filepath.Glob("model*"); len(files) != 0 {
+		t.Errorf("files written to the file system: %v", files)
+	}
+}
@@ -2,7 +2,7 @@ package eeslism
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -47,10 +47,10 @@ Gdata (General Data Input for Simulation Control)
 この関数は、建物のエネルギーシミュレーションの実行を制御し、
 シミュレーションの正確性、効率性、および出力内容を決定するための重要な役割を果たします。
 */
-func Gdata(section *EeTokens, File string, wfname *string,
+func Gdata(section *EeTokens, Simc *SIMCONTL, File string, wfname *string,
 	ofname *string, dtm *int, sttmm *int, dayxs *int, days *int, daye *int,
 	Tini *float64, pday []int, wdpri *int, revpri *int, pmvpri *int,
-	helmkey *rune, MaxIterate *int, Daytm *DAYTM, Wd *WDAT, perio *rune) {
+	helmkey *rune, debug *bool, MaxIterate *int, Daytm *DAYTM, Wd *WDAT, perio *rune, Ferr *io.Writer, out OutputSink) {
 	var s, ss, ce, dd string
 	var st int
 	var Ms, Ds, Mxs, Dxs, Me, De int
@@ -93,6 +93,13 @@ func Gdata(section *EeTokens, File string, wfname *string,
 								panic(err)
 							}
 							*wfname = dd
+						} else if s1 == "station" {
+							// 拡張アメダスの地点番号。ref: amedas.go
+							n, err := strconv.Atoi(s2)
+							if err != nil || n <= 0 {
+								panic(&InputError{Section: "GDAT", Keyword: "FILE", Msg: fmt.Sprintf("station=%s: invalid station number", s2)})
+							}
+							Simc.Station = n
 						} else if s1 == "out" {
 							_, err = fmt.Sscanf(s2, "%s", &ss)
 							if err != nil {
@@ -111,7 +118,7 @@ func Gdata(section *EeTokens, File string, wfname *string,
 								*ofname = ss
 							}
 						} else {
-							Eprint("<Gdata>", s)
+							Eprint("<Gdata>", s, *Ferr)
 						}
 					}
 				}
@@ -123,9 +130,16 @@ func Gdata(section *EeTokens, File string, wfname *string,
 		} else if line[0] == "RUN" {
 			*Tini = 15.0
 
+			// 計算期間に年を指定した場合は、実暦による計算とする。ref: calendar.go
+			if startx, start, end, ok := gdataRunCalendar(line); ok {
+				Simc.BaseYear = startx.Year()
+				Simc.runStartx, Simc.runStart, Simc.runEnd = startx, start, end
+			}
+
 			var err error
 			for i := 1; i < len(line); i++ {
-				s = line[i]
+				// 実暦による計算の年は gdataRunCalendar で読み取る
+				s = runMonthDay(line[i])
 				if strings.HasPrefix(s, "Tinit") {
 					kv := strings.SplitN(s, "=", 2)
 					*Tini, err = strconv.ParseFloat(kv[1], 64)
@@ -209,7 +223,7 @@ func Gdata(section *EeTokens, File string, wfname *string,
 						Daytm.Day = Dxs
 					}
 				} else {
-					Eprint("<Gdata>", s)
+					Eprint("<Gdata>", s, *Ferr)
 				}
 
 				if ce != "" {
@@ -231,7 +245,7 @@ func Gdata(section *EeTokens, File string, wfname *string,
 				case "*log":
 					logprn = 1
 				case "*debug":
-					DEBUG = true
+					*debug = true
 				default:
 					if !strings.ContainsRune(s, '-') {
 						var Ms, Ds int
@@ -257,10 +271,19 @@ func Gdata(section *EeTokens, File string, wfname *string,
 					}
 				}
 			}
+		} else if line[0] == "CALENDAR" {
+			// 暦年と祝日。曜日の設定は Eeinput で行う。ref: CalendarDayweek
+			Simc.Calendar = line
+		} else if line[0] == "WCSV" {
+			// CSV の気象データ。ref: wcsv.go
+			var err error
+			if Simc.Wcsv, err = ParseWCSV(line); err != nil {
+				panic(&InputError{Section: "GDAT", Keyword: "WCSV", Msg: err.Error()})
+			}
 		} else if line[0] == "*" {
 			break
 		} else {
-			Eprint("<Gdata>", s)
+			Eprint("<Gdata>", s, *Ferr)
 		}
 	}
 
@@ -268,15 +291,17 @@ func Gdata(section *EeTokens, File string, wfname *string,
 	s = filepath.Join(*ofname + ".log")
 
 	// Open the file for writing
-	var err error
-	Ferr, err = os.Create(s)
+	f, err := out.Create(s)
 	if err != nil {
 		// Handle error
+		return
 	}
 
 	if logprn == 0 {
 		// Close the file and set ferr to nil if logprn is 0
-		Ferr.Close()
-		Ferr = nil
+		f.Close()
+		*Ferr = nil
+	} else {
+		*Ferr = f
 	}
 }
@@ -3,9 +3,7 @@ package eeslism
 import (
 	"bufio"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"io"
 	"strings"
 )
 
@@ -360,6 +358,13 @@ func (t *EeTokens) GetSection() *EeTokens {
 	return section
 }
 
+// emptySection は要素のないデータセット name（`*` のみ）のトークンを返します。
+func emptySection(name string) *EeTokens {
+	t := NewEeTokens(name + "\n*\n")
+	t.GetToken()
+	return t.GetSection()
+}
+
 /*
 IsEnd (Check if End of Tokens)
 
@@ -512,7 +517,7 @@ Eeinput (Energy Simulation Input Data Processing)
 この関数は、建物のエネルギーシミュレーションのデータ準備とモデル構築の全体を統括し、
 シミュレーションの正確性、効率性、および再現性を確保するための重要な役割を果たします。
 */
-func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string, Simc *SIMCONTL,
+func Eeinput(Ipath string, bdata, week, schtba, schnma string, Simc *SIMCONTL,
 	Exsf *EXSFS, Rmvls *RMVLS, Eqcat *EQCAT, Eqsys *EQSYS,
 	Compnt *[]*COMPNT,
 	Elout *[]*ELOUT,
@@ -529,7 +534,7 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 	tree *[]*TREE,
 	shadtb *[]*SHADTB,
 	poly *[]*POLYGN, monten *int, gpn *int, DE *float64,
-	Noplpmp *NOPLPMP) (*SCHDL, []*FLOUT) {
+	Noplpmp *NOPLPMP, Fbmlist string, Ferr *io.Writer) (*SCHDL, []*FLOUT) {
 
 	if Simc == nil {
 		panic("Simc is nil")
@@ -649,17 +654,86 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 
 	var err error
 
+	// 入力を正規化することで後処理を簡単にする
+	tokens := NewEeTokens(bdata)
+
+	// -------------------------------------------------------
+	// GDATデータセットの読み取り
+	// -------------------------------------------------------
+	// 曜日の設定に GDAT の CALENDAR と RUN の計算期間の年を用いるため、他のデータセットより先に読み取る
+	for !tokens.IsEnd() {
+		if tokens.GetToken() != "GDAT" {
+			continue
+		}
+		section := tokens.GetSection()
+		Wd.RNtype = 'C'
+		Wd.Intgtsupw = 'N'
+		Simc.Perio = 'n' // 周期定常計算フラグを'n'に初期化
+		Gdata(section, Simc, Simc.File, &Simc.Wfname, &Simc.Ofname, &dtm, &Simc.Sttmm,
+			&daystartx, &daystart, &dayend, &Twallinit, Simc.Dayprn,
+			&wdpri, &revpri, &pmvpri, &Simc.Helmkey, &Simc.Debug, &Simc.MaxIterate, Daytm, Wd, &Simc.Perio, Ferr, Simc.Output)
+
+		// 気象データファイル名からファイル種別を判定
+		if Simc.Wcsv != nil {
+			// CSV の気象データ
+			if Simc.Wfname != "" {
+				panic(&InputError{Section: "GDAT", Keyword: "WCSV", Component: Simc.Wfname, Msg: "FILE w= and WCSV cannot be used together"})
+			}
+			Simc.Wdtype = 'C'
+			Simc.Wfname = Simc.Wcsv.File
+		} else if Simc.Wfname == "" {
+			Simc.Wdtype = 'E'
+		} else if Simc.Station > 0 {
+			// 拡張アメダスは夜間放射量を水平面大気放射量から求める
+			Simc.Wdtype = 'A'
+			Wd.RNtype = 'R'
+		} else if isEPW(Simc.Wfname) {
+			// EPW は夜間放射量を水平面大気放射量から求める
+			Simc.Wdtype = 'P'
+			Wd.RNtype = 'R'
+		} else {
+			Simc.Wdtype = 'H'
+		}
+
+		// 初期温度 (15[deg])
+		Rmvls.Twallinit = Twallinit
+
+		// 計算時間間隔 [s]
+		Simc.DTm = dtm
+
+		Simc.Unit = "t_C x_kg/kg r_% q_W e_W"
+		Simc.Unitdy = "Q_kWh E_kWh"
+
+		fmt.Printf("== File  Output=%s\n", Simc.Ofname)
+	}
+	tokens.Reset()
+
 	// -------------------------------------------------------
 	// 曜日設定ファイルの読み取り
 	// -------------------------------------------------------
-	var fi_dayweek []byte
-	if fi_dayweek, err = ioutil.ReadFile(filepath.Join(efl_path, "dayweek.efl")); err != nil {
-		Eprint("<Eeinput>", "dayweek.efl")
-		os.Exit(EXIT_DAYWEK)
+	// GDAT CALENDAR を指定した場合は、dayweek.efl と WEEK の代わりに暦年の曜日と祝日を用いる
+	// 実暦による計算（GDAT RUN の計算期間に年を指定）では、通日を助走計算開始日の年の 1/1 からの実暦の日数とする
+	if Simc.Calendar != nil && Simc.BaseYear == 0 {
+		Simc.Year = CalendarDayweek(Simc.Calendar, Simc.Daywk)
+	} else if Simc.Calendar == nil {
+		var fi_dayweek []byte
+		if fi_dayweek, err = Simc.readEfl("dayweek.efl"); err != nil {
+			Eprint("<Eeinput>", "dayweek.efl", *Ferr)
+			panic(&InputError{Component: "dayweek.efl", Msg: err.Error(), Code: EXIT_DAYWEK})
+		}
+		Dayweek(string(fi_dayweek), week, Simc.Daywk, key, *Ferr)
+		if Simc.BaseYear > 0 {
+			Simc.Calendar = dayweekCalendar(Simc.Daywk)
+		}
+	}
+	if Simc.BaseYear > 0 {
+		// 実暦による計算では各年の暦による。計算期間の各年の祝日を確かめ、最初の年の曜日とする
+		for y := Simc.runEnd.Year(); y >= Simc.BaseYear; y-- {
+			Simc.setDaywk(y, false)
+		}
 	}
-	Dayweek(string(fi_dayweek), week, Simc.Daywk, key)
 
-	if DEBUG {
+	if Simc.Debug {
 		dprdayweek(Simc.Daywk)
 	}
 
@@ -675,8 +749,27 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 	// -------------------------------------------------------
 	Schdata(schnma, "schnm", Simc.Daywk, Schdl)
 
-	// 入力を正規化することで後処理を簡単にする
-	tokens := NewEeTokens(bdata)
+	// SYSCMP、SYSPTH の読み込みと、室の要素の割り当て。
+	// SYSCMP、SYSPTH がない室のみのモデルでも、空のデータセットとして読み込んで室の要素を割り当てる
+	var hasSyscmp, hasSyspth bool
+	syscmp := func(section *EeTokens) {
+		hasSyscmp = true
+		Compodata(section, Rmvls, Eqcat, Compnt, Eqsys, *Ferr)
+		Elmalloc(*Compnt, Eqcat, Eqsys, Elout, Elin, *Ferr)
+	}
+	syspth := func(section *EeTokens) {
+		hasSyspth = true
+		Pathdata(section, Simc, Wd, *Compnt, Schdl, Mpath, Plist, Pelm, Eqsys, Elout, Elin, *Ferr)
+		Roomelm(Rmvls.Room, Rmvls.Rdpnl)
+
+		// 変数の割り当て
+		Hclelm(Eqsys.Hcload)
+		Thexelm(Eqsys.Thex)
+		Desielm(Eqsys.Desi)
+		Evacelm(Eqsys.Evac)
+
+		Qmeaselm(Eqsys.Qmeas)
+	}
 
 	for !tokens.IsEnd() {
 		s := tokens.GetToken()
@@ -698,31 +791,8 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 			Simc.Title = strings.Join(line, " ")
 			fmt.Printf("%s\n", Simc.Title)
 		case "GDAT":
-			section := tokens.GetSection()
-			Wd.RNtype = 'C'
-			Wd.Intgtsupw = 'N'
-			Simc.Perio = 'n' // 周期定常計算フラグを'n'に初期化
-			Gdata(section, Simc.File, &Simc.Wfname, &Simc.Ofname, &dtm, &Simc.Sttmm,
-				&daystartx, &daystart, &dayend, &Twallinit, Simc.Dayprn,
-				&wdpri, &revpri, &pmvpri, &Simc.Helmkey, &Simc.MaxIterate, Daytm, Wd, &Simc.Perio)
-
-			// 気象データファイル名からファイル種別を判定
-			if Simc.Wfname == "" {
-				Simc.Wdtype = 'E'
-			} else {
-				Simc.Wdtype = 'H'
-			}
-
-			// 初期温度 (15[deg])
-			Rmvls.Twallinit = Twallinit
-
-			// 計算時間間隔 [s]
-			Simc.DTm = dtm
-
-			Simc.Unit = "t_C x_kg/kg r_% q_W e_W"
-			Simc.Unitdy = "Q_kWh E_kWh"
-
-			fmt.Printf("== File  Output=%s\n", Simc.Ofname)
+			// 先に読み取り済み
+			tokens.GetSection()
 		// case "SCHTB":
 		// 	// SCHDBデータセットの読み取り
 		// 	//Schtable(schtba, Schdl)
@@ -733,16 +803,16 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 		case "EXSRF":
 			// EXSRFデータセットの読み取り
 			section := tokens.GetSection()
-			Exsfdata(section, s, Exsf, Schdl, Simc)
+			Exsfdata(section, s, Exsf, Schdl, Simc, *Ferr)
 
 		case "SUNBRK":
 			// 日よけの読み込み
 			section := tokens.GetSection()
-			Snbkdata(section, s, &Rmvls.Snbk)
+			Snbkdata(section, s, &Rmvls.Snbk, *Ferr)
 
 		case "PCM":
 			section := tokens.GetSection()
-			PCMdata(section, Ipath, &Rmvls.PCM, &Rmvls.Pcmiterate)
+			PCMdata(section, Ipath, &Rmvls.PCM, &Rmvls.Pcmiterate, Simc.FS, *Ferr)
 
 		case "WALL":
 			if Fbmlist == "" {
@@ -751,73 +821,63 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 				File = Fbmlist
 			}
 
-			var fullpath string
-			if filepath.IsAbs(File) {
-				fullpath = File
+			// wbmlist= で指定した材料定義リストは入力データファイルのディレクトリからも探す
+			var fbmContent []byte
+			if Fbmlist == "" {
+				fbmContent, err = Simc.readEfl(File)
 			} else {
-				fullpath = filepath.Join(efl_path, File)
+				fbmContent, err = Simc.readRef(File)
 			}
-
-			var fbmContent []byte
-			if fbmContent, err = ioutil.ReadFile(fullpath); err != nil {
-				Eprint("<Eeinput>", "wbmlist.efl")
-				os.Exit(EXIT_WBMLST)
+			if err != nil {
+				Eprint("<Eeinput>", "wbmlist.efl", *Ferr)
+				panic(&InputError{Section: "WALL", Component: File, Msg: err.Error(), Code: EXIT_WBMLST})
 			}
 			/*******************/
 
 			section := tokens.GetSection()
-			Walldata(section, string(fbmContent), &Rmvls.Wall, &dfwl, Rmvls.PCM)
+			Walldata(section, string(fbmContent), &Rmvls.Wall, &dfwl, Rmvls.PCM, *Ferr)
 
 		case "WINDOW":
 			section := tokens.GetSection()
-			Windowdata(section, &Rmvls.Window)
+			Windowdata(section, &Rmvls.Window, *Ferr)
 
 		case "ROOM":
-			Roomdata(tokens, Exsf.Exs, &dfwl, Rmvls, Schdl, Simc)
+			Roomdata(tokens, Exsf.Exs, &dfwl, Rmvls, Schdl, Simc, *Ferr)
 			Balloc(Rmvls.Sd, Rmvls.Wall, &Rmvls.Mw)
 
 		case "RAICH", "VENT":
 			section := tokens.GetSection()
-			Ventdata(section, Schdl, Rmvls.Room, Simc)
+			Ventdata(section, Schdl, Rmvls.Room, Simc, *Ferr)
 
 		case "RESI":
 			section := tokens.GetSection()
-			Residata(section, Schdl, Rmvls.Room, &pmvpri, Simc)
+			Residata(section, Schdl, Rmvls.Room, &pmvpri, Simc, *Ferr)
 
 		case "APPL":
 			section := tokens.GetSection()
-			Appldata(section, Schdl, Rmvls.Room, Simc)
+			Appldata(section, Schdl, Rmvls.Room, Simc, *Ferr)
 
 		case "VCFILE":
 			section := tokens.GetSection()
-			Vcfdata(section, Simc)
+			Vcfdata(section, Simc, *Ferr)
 
 		case "EQPCAT":
 			section := tokens.GetSection()
-			Eqcadata(section, Eqcat, efl_path)
+			Eqcadata(section, Eqcat, Simc.EflFS, *Ferr)
 
 		case "SYSCMP": // 接続用のノードを設定している
 			/*****Flwindata(Flwin, Nflwin,  Wd);********/
-			section := tokens.GetSection()
-			Compodata(section, Rmvls, Eqcat, Compnt, Eqsys)
-			Elmalloc(*Compnt, Eqcat, Eqsys, Elout, Elin)
+			syscmp(tokens.GetSection())
 
 		case "SYSPTH": // 接続パスの設定をしている
-			section := tokens.GetSection()
-			Pathdata(section, Simc, Wd, *Compnt, Schdl, Mpath, Plist, Pelm, Eqsys, Elout, Elin)
-			Roomelm(Rmvls.Room, Rmvls.Rdpnl)
-
-			// 変数の割り当て
-			Hclelm(Eqsys.Hcload)
-			Thexelm(Eqsys.Thex)
-			Desielm(Eqsys.Desi)
-			Evacelm(Eqsys.Evac)
-
-			Qmeaselm(Eqsys.Qmeas)
+			if !hasSyscmp {
+				syscmp(emptySection("SYSCMP"))
+			}
+			syspth(tokens.GetSection())
 
 		case "CONTL":
 			section := tokens.GetSection()
-			Contrldata(section, Contl, Ctlif, Ctlst, Simc, *Compnt, *Mpath, Wd, Exsf, Schdl)
+			Contrldata(section, Contl, Ctlif, Ctlst, Simc, *Compnt, *Mpath, Wd, Exsf, Schdl, *Ferr)
 
 		/*--------------higuchi add-------------------start*/
 
@@ -901,10 +961,17 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 
 		default:
 			Err = Err + "  " + s
-			Eprint("<Eeinput>", Err)
+			Eprint("<Eeinput>", Err, *Ferr)
 		}
 	}
 
+	if !hasSyscmp {
+		syscmp(emptySection("SYSCMP"))
+	}
+	if !hasSyspth {
+		syspth(emptySection("SYSPTH"))
+	}
+
 	/*--------------higuchi 070918-------------------start-*/
 	if len(*bp) != 0 {
 		fmt.Printf("deviding of wall mm: %f\n", *DE)
@@ -923,21 +990,33 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 	// シミュレーション設定
 	//----------------------------------------------------
 
-	if daystart > dayend {
-		dayend = dayend + 365
-	}
-	Nday = dayend - daystart + 1
+	if Simc.BaseYear > 0 {
+		// 実暦による計算
+		daystartx = Simc.calendarNday(Simc.runStartx)
+		daystart = Simc.calendarNday(Simc.runStart)
+		dayend = Simc.calendarNday(Simc.runEnd)
+		Nday = dayend - daystartx + 1
+	} else {
+		if daystart > dayend {
+			dayend = dayend + 365
+		}
+		Nday = dayend - daystart + 1
 
-	if daystartx > daystart {
-		daystart = daystart + 365
-	}
+		if daystartx > daystart {
+			daystart = daystart + 365
+		}
 
-	Nday += daystart - daystartx
+		Nday += daystart - daystartx
+	}
 	Simc.Dayend = daystartx + Nday - 1
 	Simc.Daystartx = daystartx
 	Simc.Daystart = daystart
 
 	Simc.Timeid = []rune{'M', 'D', 'T'}
+	if Simc.BaseYear > 0 {
+		// 実暦による計算では各行の時刻に年を出力する
+		Simc.Timeid = []rune{'Y', 'M', 'D', 'T'}
+	}
 
 	Simc.Ntimedyprt = Simc.Dayend - Simc.Daystart + 1
 	Simc.Dayntime = 24 * 3600 / dtm
@@ -945,7 +1024,12 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 
 	for nday = Simc.Daystart; nday <= Simc.Dayend; nday++ {
 		// NOTE: オリジナルコードはバッファーオーバーランしているので、`%366`を追加
-		if Simc.Dayprn[nday%366] != 0 {
+		d := nday % 366
+		if Simc.BaseYear > 0 {
+			_, M, D := Simc.Date(nday)
+			d = standardDay(M, D)
+		}
+		if Simc.Dayprn[d] != 0 {
 			Simc.Ntimehrprt += Simc.Dayntime
 		}
 	}
@@ -1057,6 +1141,9 @@ func Eeinput(Ipath string, efl_path string, bdata, week, schtba, schnma string,
 		addFlout(PRTMWD) // 月別計算値(気象データ月集計値出力)
 	}
 
+	// CSV の時系列によるスケジュールの読み込み（計算時間間隔が必要）
+	Simc.Schcsv(Schdl)
+
 	// DEBUG
 	fmt.Printf("読み取りデータ数\n")
 	fmt.Printf("SHDSCHTB: %d\n", len(*shadtb))    // 落葉スケジュール
@@ -1,6 +1,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"strconv"
 	"strings"
@@ -38,7 +39,7 @@ envptr (Environment Pointer Setting)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func envptr(s string, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Exsf *EXSFS) *float64 {
+func envptr(s string, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Exsf *EXSFS, Ferr io.Writer) *float64 {
 	var err error
 	var vptr VPTR
 	var dmy []*MPATH
@@ -52,7 +53,7 @@ func envptr(s string, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Exsf *EXSFS) *
 		}
 		val = CreateConstantValuePointer(num)
 	} else {
-		vptr, _, err = kynameptr(s, Simc, Compnt, dmy, Wd, Exsf)
+		vptr, _, err = kynameptr(s, Simc, Compnt, dmy, Wd, Exsf, Ferr)
 		if err == nil && vptr.Type == VAL_CTYPE {
 			val = vptr.Ptr.(*float64)
 		} else {
@@ -1,6 +1,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 	"regexp"
 	"strings"
@@ -57,6 +58,7 @@ func Pathdata(
 	Eqsys *EQSYS,
 	Elout *[]*ELOUT,
 	Elin *[]*ELIN,
+	Ferr io.Writer,
 ) {
 	var C *COMPNT
 	var stank *STANK
@@ -68,7 +70,7 @@ func Pathdata(
 	var i, j, m, ncv, iswc int
 
 	errkey := "Pathdata"
-	if DEBUG {
+	if Simc.Debug {
 		fmt.Printf("\n")
 		for i := range Compnt {
 
@@ -87,7 +89,7 @@ func Pathdata(
 			continue
 		}
 
-		if DEBUG {
+		if Simc.Debug {
 			fmt.Printf("eepathdat.c  ss=%s\n", ss)
 		}
 
@@ -126,7 +128,7 @@ func Pathdata(
 				break
 			}
 
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("eepathdat.c  s=%s\n", s)
 			}
 
@@ -144,7 +146,7 @@ func Pathdata(
 					//（W：水系統、A：空気系統で温・湿度とも計算、a：空気系統で温度のみ計算。
 					Mpath.Fluid = FliudType(ss[0])
 				} else {
-					Errprint(1, errkey, s)
+					Errprint(1, errkey, s, Ferr)
 				}
 			} else if s == ">" {
 				//
@@ -164,7 +166,7 @@ func Pathdata(
 						break
 					}
 
-					if DEBUG {
+					if Simc.Debug {
 						fmt.Printf("eepathdat.c  s=%s\n", s)
 					}
 
@@ -182,21 +184,21 @@ func Pathdata(
 
 							if Go, err := readFloat(ss); err == nil {
 								Plist.Go = CreateConstantValuePointer(Go)
-								if DEBUG {
+								if Simc.Debug {
 									fmt.Printf("Go=%f\n", *Plist.Go)
 								}
 							} else {
-								if DEBUG {
+								if Simc.Debug {
 									fmt.Printf("s=%s ss=%s\n", s, ss)
 								}
 
-								if j, err = idsch(ss, Schdl.Sch, ""); err == nil {
+								if j, err = idsch(ss, Schdl.Sch, "", Ferr); err == nil {
 									Plist.Go = &Schdl.Val[j]
 								} else {
-									Plist.Go = envptr(ss, Simc, Compnt, Wd, nil)
+									Plist.Go = envptr(ss, Simc, Compnt, Wd, nil, Ferr)
 								}
 
-								if DEBUG {
+								if Simc.Debug {
 									fmt.Printf("Go=%f\n", *Plist.Go)
 								}
 							}
@@ -217,7 +219,7 @@ func Pathdata(
 						if i == 1 {
 							// 指定されていたのが数値なので、流量比率に入力された固定値を設定する
 							Plist.Rate = CreateConstantValuePointer(Go)
-							if DEBUG {
+							if Simc.Debug {
 								fmt.Printf("rate=%f\n", *Plist.Rate)
 							}
 						} else {
@@ -228,19 +230,19 @@ func Pathdata(
 								panic(err)
 							}
 
-							if DEBUG {
+							if Simc.Debug {
 								fmt.Printf("s=%s ss=%s\n", s, ss)
 							}
 
 							// 設定値スケジュールの検索
-							if j, err := idsch(ss, Schdl.Sch, ""); err == nil {
+							if j, err := idsch(ss, Schdl.Sch, "", Ferr); err == nil {
 								// 設定値スケジュールが見つかったので、スケジュール設定値へのポインタを指定
 								Plist.Rate = &Schdl.Val[j]
 							} else {
-								Plist.Rate = envptr(ss, Simc, Compnt, Wd, nil)
+								Plist.Rate = envptr(ss, Simc, Compnt, Wd, nil, Ferr)
 							}
 
-							if DEBUG {
+							if Simc.Debug {
 								fmt.Printf("rate=%f\n", *Plist.Rate)
 							}
 						}
@@ -341,7 +343,7 @@ func Pathdata(
 								for i := 0; i < stank.Nin; i++ {
 									if stank.Pthcon[i] == co {
 										var err error
-										if iswc, err = idscw(stv, Schdl.Scw, ""); err == nil {
+										if iswc, err = idscw(stv, Schdl.Scw, "", Ferr); err == nil {
 											stank.Batchcon[i] = Schdl.Isw[iswc]
 										}
 									}
@@ -373,9 +375,9 @@ func Pathdata(
 							id++
 						}
 
-						Errprint(err, errkey, elm)
+						Errprint(err, errkey, elm, Ferr)
 
-						if DEBUG {
+						if Simc.Debug {
 							fmt.Printf("<<Pathdata>> Mp=%s  elm=%s Npelm=%d\n", Mpath.Name, elm, len(*Plm))
 						}
 					}
@@ -389,19 +391,19 @@ func Pathdata(
 				//Pelmpre = nil
 				id = 0
 			} else {
-				Errprint(1, errkey, s)
+				Errprint(1, errkey, s, Ferr)
 			}
 		}
 		// PLIST読み込み用ループ 終了
 
-		if DEBUG {
+		if Simc.Debug {
 			// 読み取ったMPATHの順番と流体種別を表示
 			fmt.Printf("<<Pathdata>>  Mpath=%d fliud=%c\n", len(*M), Mpath.Fluid)
 		}
 
 		// 流体種別が空気の場合: 空気系統用の絶対湿度経路へのコピーを行う必要がある
 		if Mpath.Fluid == AIR_FLD {
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("<<Pathdata  a>> Mp=%s  Npelm=%d\n", Mpath.Name, len(*Plm))
 			}
 
@@ -431,7 +433,7 @@ func Pathdata(
 	}
 	// 最外ループ: MPATHの読み込み 完了
 
-	if DEBUG {
+	if Simc.Debug {
 		if len(*M) > 0 {
 			plistprint(*M, *Plm, *Elout, *Elin)
 		}
@@ -444,7 +446,7 @@ func Pathdata(
 
 	// すべてのMPATHについてのループ
 	for i, Mpath := range *M {
-		if DEBUG {
+		if Simc.Debug {
 			fmt.Printf("1----- MAX=%d  i=%d\n", len(*M), i)
 		}
 
@@ -455,14 +457,14 @@ func Pathdata(
 		//
 		for j, Plist := range Mpath.Plist {
 
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("eepath.c  Mpath->Nlpath=%d\n", len(Mpath.Plist))
 				fmt.Printf("<<Pathdata>>  i=%d Mpath=%d  j=%d Plist=%d\n", i, i, j, j)
 			}
 
 			for m, Pelm := range Plist.Pelm {
 
-				if DEBUG {
+				if Simc.Debug {
 					fmt.Printf("<<Pathdata>>  m=%d  pelm=%d  %s\n", m, m, Pelm.Cmp.Name)
 					fmt.Printf("MAX=%d  m=%d\n", len(Plist.Pelm), m)
 				}
@@ -498,7 +500,7 @@ func Pathdata(
 
 				if idci {
 					// システム要素入力端割当
-					pelmci(Mpath.Fluid, Pelm, errkey)
+					pelmci(Mpath.Fluid, Pelm, errkey, Ferr)
 					if Pelm.In != nil {
 						Pelm.In.Lpath = Plist
 					}
@@ -506,7 +508,7 @@ func Pathdata(
 
 				if idco {
 					// システム要素出力端割当
-					pelmco(Mpath.Fluid, Pelm, errkey)
+					pelmco(Mpath.Fluid, Pelm, errkey, Ferr)
 
 					if Pelm.Out != nil {
 						Pelm.Out.Lpath = Plist
@@ -516,7 +518,7 @@ func Pathdata(
 			}
 		}
 
-		if DEBUG {
+		if Simc.Debug {
 			plistprint((*M)[i:i+1], *Plm, *Elout, *Elin)
 			fmt.Printf("i=%d\n", i)
 		}
@@ -531,14 +533,14 @@ func Pathdata(
 
 			Plist := Mpath.Plist[0]
 
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("<<Pathdata>>   Plist->type=%c\n", Plist.Type)
 			}
 
 			if Plist.Type == IN_LPTP {
 				Mpath.Type = THR_PTYP
 
-				if DEBUG {
+				if Simc.Debug {
 					fmt.Printf("<<Pathdata>>   Mpath->type=%c\n", Mpath.Type)
 				}
 			} else {
@@ -549,7 +551,7 @@ func Pathdata(
 				}
 			}
 
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("<<Pathdata>> test end\n")
 			}
 
@@ -570,7 +572,7 @@ func Pathdata(
 
 			Mpath.Type = BRC_PTYP
 
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("<<Pathdata>> Mpath i=%d  type=%c\n", i, Mpath.Type)
 			}
 
@@ -580,7 +582,7 @@ func Pathdata(
 				Pelm_0 := Plist.Pelm[0]
 				etyp_0 := Pelm_0.Cmp.Eqptype
 
-				if DEBUG {
+				if Simc.Debug {
 					fmt.Printf("<<Pathdata>> Plist j=%d name=%s eqptype=%s\n", j, Pelm_0.Cmp.Name, etyp_0)
 				}
 
@@ -613,19 +615,19 @@ func Pathdata(
 					Pelm.In.Upo = PelmPrev.Out
 				}
 
-				if DEBUG {
+				if Simc.Debug {
 					fmt.Printf("<<Pathdata>> Plist MAX=%d  j=%d  type=%c\n", len(Mpath.Plist), j, Plist.Type)
 				}
 			}
 		}
 		Mpath.Ncv = ncv
 
-		if DEBUG {
+		if Simc.Debug {
 			fmt.Printf("2----- MAX=%d  i=%d\n", len(*M), i)
 		}
 	}
 
-	if DEBUG {
+	if Simc.Debug {
 		if len(*M) > 0 {
 			mpi := *M
 			plistprint(mpi, *Plm, *Elout, *Elin)
@@ -638,13 +640,13 @@ func Pathdata(
 	// 未知流量等の構造解析
 	pflowstrct(*M)
 
-	if DEBUG {
+	if Simc.Debug {
 		if len(*M) > 0 {
 			plistprint(*M, *Plm, *Elout, *Elin)
 		}
 	}
 
-	if DEBUG {
+	if Simc.Debug {
 		fmt.Printf("\n")
 		for i = range Compnt {
 			C := Compnt[i]
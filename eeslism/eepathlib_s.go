@@ -1,6 +1,7 @@
 package eeslism
 
 import (
+	"io"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,7 +12,7 @@ import (
 /* ----------------------------------------------- */
 
 // システム要素出力端割当
-func pelmco(pflow FliudType, Pelm *PELM, errkey string) {
+func pelmco(pflow FliudType, Pelm *PELM, errkey string, Ferr io.Writer) {
 	var Nout int
 	err := 0
 	var cmp *COMPNT
@@ -111,13 +112,13 @@ func pelmco(pflow FliudType, Pelm *PELM, errkey string) {
 		}
 	}
 
-	Errprint(err, errkey+" <pelmco>", cmp.Name)
+	Errprint(err, errkey+" <pelmco>", cmp.Name, Ferr)
 }
 
 /* ----------------------------------------------- */
 
 // システム要素入力端割当
-func pelmci(pflow FliudType, Pelm *PELM, errkey string) {
+func pelmci(pflow FliudType, Pelm *PELM, errkey string, Ferr io.Writer) {
 	err := 0
 	cmp := Pelm.Cmp
 	Nin := cmp.Nin
@@ -282,7 +283,7 @@ func pelmci(pflow FliudType, Pelm *PELM, errkey string) {
 		}
 	}
 
-	Errprint(err, errkey+" <pelmci>", cmp.Name)
+	Errprint(err, errkey+" <pelmci>", cmp.Name, Ferr)
 }
 
 /* ----------------------------------------------- */
@@ -2,8 +2,8 @@ package eeslism
 
 import (
 	"fmt"
+	"io"
 	"math"
-	"os"
 )
 
 /*
@@ -47,7 +47,7 @@ Pflow (Path Flow Calculation)
 エネルギー消費量予測、省エネルギー対策の検討、
 および最適な設備システム設計を行うための重要な役割を果たします。
 */
-func Pflow(_Mpath []*MPATH, Wd *WDAT) {
+func Pflow(_Mpath []*MPATH, Wd *WDAT, debug bool, Ferr io.Writer) {
 	var i, j, n, NG int
 	//var mpi *MPATH
 	var pl *PLIST
@@ -65,7 +65,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 	if len(_Mpath) > 0 {
 		for m, Mpath := range _Mpath {
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("m=%d mMAX=%d name=%s\n", m, len(_Mpath), Mpath.Name)
 			}
 
@@ -79,7 +79,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 			for i, Plist := range Mpath.Plist {
 				Plist.G = 0.0
 
-				if DEBUG {
+				if debug {
 					fmt.Printf("i=%d iMAX=%d name=%s\n", i, len(Mpath.Plist), get_string_or_null(Plist.Name))
 				}
 
@@ -97,7 +97,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 						if vc == nil || vc.Org == 'y' {
 							if vc.X < 0.0 {
 								s = fmt.Sprintf("%s のバルブ開度 %f が不正です。", vc.Name, vc.X)
-								Eprint("<Pflow>", s)
+								Eprint("<Pflow>", s, Ferr)
 							}
 							Plist.G = vc.X * *Plist.Go
 						} else {
@@ -176,7 +176,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 			A := make([]float64, NG*NG)
 
 			for i = 0; i < NG; i++ {
-				if DEBUG {
+				if debug {
 					fmt.Printf("i=%d iMAX=%d\n", i, NG)
 				}
 
@@ -187,7 +187,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 					continue
 				}
 
-				if DEBUG {
+				if debug {
 					fmt.Printf("<Pflow> Name=%s\n", cmp.Name)
 				}
 
@@ -199,7 +199,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 				for j = 0; j < cmp.Nin && j < len(cmp.Elins); j++ {
 					eli = cmp.Elins[j]
 
-					if DEBUG {
+					if debug {
 						fmt.Printf("j=%d jMAX=%d\n", j, cmp.Nin)
 					}
 
@@ -219,8 +219,8 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 
 						if n < 0 || n >= NG {
 							Err = fmt.Sprintf("n=%d", n)
-							Eprint("<Pflow>", Err)
-							os.Exit(EXIT_PFLOW)
+							Eprint("<Pflow>", Err, Ferr)
+							panic(&ConvergenceError{Keyword: "Pflow", Component: cmp.Name, Msg: Err, Code: EXIT_PFLOW})
 						}
 
 						A[i*NG+n] = 1.0
@@ -242,8 +242,8 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 
 						if n < 0 || n >= NG {
 							Err = fmt.Sprintf(Err, "n=%d", n)
-							Eprint("<Pflow>", Err)
-							os.Exit(EXIT_PFLOW)
+							Eprint("<Pflow>", Err, Ferr)
+							panic(&ConvergenceError{Keyword: "Pflow", Component: cmp.Name, Msg: Err, Code: EXIT_PFLOW})
 						}
 
 						A[i*NG+n] = -1.0
@@ -253,7 +253,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 
 			if NG > 0 {
 
-				if DEBUG {
+				if debug {
 					for i = 0; i < NG; i++ {
 						fmt.Printf("%s\t", Mpath.Cbcmp[i].Name)
 
@@ -265,7 +265,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 					}
 				}
 
-				if dayprn && Ferr != nil {
+				if Ferr != nil {
 					for i = 0; i < NG; i++ {
 						fmt.Fprintf(Ferr, "%s\t", Mpath.Cbcmp[i].Name)
 
@@ -278,20 +278,20 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 				}
 
 				if NG > 1 {
-					Matinv(A, NG, NG, "<Pflow>")
+					Matinv(A, NG, NG, "<Pflow>", Ferr)
 					Matmalv(A, Y, NG, NG, X)
 				} else {
 					X[0] = Y[0] / A[0]
 				}
 
-				if DEBUG {
+				if debug {
 					fmt.Printf("<Pflow>  Flow Rate\n")
 					for i = 0; i < NG; i++ {
 						fmt.Printf("\t%6.2f\n", X[i])
 					}
 				}
 
-				if dayprn && Ferr != nil {
+				if Ferr != nil {
 					for i = 0; i < NG; i++ {
 						fmt.Fprintf(Ferr, "\t\t%.2g\n", X[i])
 					}
@@ -305,7 +305,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 
 			for i, Plist := range Mpath.Plist {
 
-				if DEBUG {
+				if debug {
 					fmt.Printf("<< Pflow >> e i=%d iMAX=%d control=%c G=%.5f\n",
 						i, len(Mpath.Plist), Plist.Control, Plist.G)
 				}
@@ -327,7 +327,7 @@ func Pflow(_Mpath []*MPATH, Wd *WDAT) {
 						Pelm.Out.G = Plist.G
 					}
 
-					if DEBUG {
+					if debug {
 						if Pelm.Out != nil {
 							G0 = Pelm.Out.G
 						} else {
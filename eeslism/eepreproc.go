@@ -0,0 +1,348 @@
+/*
+eepreproc.go (Include and Parameter Substitution)
+
+入力データファイルの前処理（インクルードとパラメータの置換）を定義します。
+Eesprera が注釈文を除く前に行います。
+
+  - `#include "walls.txt"`: ファイル walls.txt の内容をこの行に挿入します。
+    ファイルは、このファイルのディレクトリからの相対パス、次に EFLファイルのディレクトリから探します。
+    インクルードしたファイルの中でも `#include`、`$define` を使えます。
+  - `$define U_WIN 2.33`: パラメータ U_WIN を定義します。値は行の残り（注釈文を除く）です。
+    Simulation.Defines（コマンドラインの `-D`）で定義された値が優先します。
+  - `${U_WIN}`: パラメータの値に置き換えます。`${U_WIN*1.1}` のように、数値とパラメータの
+    四則演算（`+ - * /` と括弧）を書くと計算結果に置き換えます。
+
+`#include`、`$define` は行の先頭（空白を除く）に書きます。これらの行は空行になるため、
+インクルードしない場合は前処理の前後で行番号は変わりません。
+*/
+package eeslism
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// srcLine は前処理後の行の、元のファイルと行番号（1から）です。
+type srcLine struct {
+	File string
+	Line int
+}
+
+// preprocError は前処理の誤りです。
+type preprocError struct {
+	srcLine
+	Keyword string // 誤りのある指令、パラメータ名
+	Msg     string
+}
+
+func (e *preprocError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// inputError は e を InputError に変換します。
+func (e *preprocError) inputError() *InputError {
+	return &InputError{Keyword: e.Keyword, Component: e.File, Msg: fmt.Sprintf("line %d: %s", e.Line, e.Msg)}
+}
+
+// preprocessor は入力データファイルの前処理の状態です。
+type preprocessor struct {
+	fsys    fs.FS             // 入力データファイル、インクルードするファイルの読み込み元
+	eflfs   fs.FS             // EFLファイルの読み込み元。nil の場合は探さない
+	defines map[string]string // $define より優先するパラメータ
+	params  map[string]string // $define で定義されたパラメータ
+	files   []string          // 読み込み中のファイル（循環の検出用）
+
+	out   strings.Builder
+	lines []srcLine
+}
+
+// paramNamePattern はパラメータ名です。
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// preprocessInput はファイル file の内容 text の `#include`、`$define`、`${...}` を処理した内容と、
+// その各行の元のファイルと行番号を返します。注釈文はそのまま残します。
+func preprocessInput(file, text string, fsys, eflfs fs.FS, defines map[string]string) (string, []srcLine, error) {
+	p := &preprocessor{fsys: fsys, eflfs: eflfs, defines: defines, params: make(map[string]string)}
+	if err := p.file(file, text); err != nil {
+		return "", nil, err
+	}
+	return p.out.String(), p.lines, nil
+}
+
+// preprocessFile は入力データファイル file の内容 text を、インクルードするファイルをファイルシステムの
+// file のディレクトリ、EFLファイルのディレクトリ efl_path から探して前処理します。
+// 入力データファイルを別のディレクトリにコピーして計算する場合（バッチ計算、FMU）に用います。
+func preprocessFile(file, text, efl_path string, defines map[string]string) (string, error) {
+	text, _, err := preprocessInput(file, text, decodingFS{osFS{}, EncodingAuto},
+		decodingFS{eflFS(efl_path), EncodingAuto}, defines)
+	if pe, ok := err.(*preprocError); ok {
+		return "", pe.inputError()
+	}
+	return text, err
+}
+
+// file はファイル file の内容 text を処理します。
+func (p *preprocessor) file(file, text string) error {
+	p.files = append(p.files, file)
+	defer func() { p.files = p.files[:len(p.files)-1] }()
+
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		if err := p.line(srcLine{file, n}, sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// emit は前処理後の1行を出力します。
+func (p *preprocessor) emit(at srcLine, s string) {
+	p.out.WriteString(s)
+	p.out.WriteByte('\n')
+	p.lines = append(p.lines, at)
+}
+
+// line は位置 at の1行 text を処理します。
+func (p *preprocessor) line(at srcLine, text string) error {
+	code := processLine(text)
+	comment := text[len(code):]
+
+	fields := strings.Fields(code)
+	if len(fields) == 0 || (fields[0] != "#include" && fields[0] != "$define") {
+		s, err := p.expand(at, code)
+		if err != nil {
+			return err
+		}
+		p.emit(at, s+comment)
+		return nil
+	}
+
+	directive := fields[0]
+	arg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(code), directive))
+	fail := func(format string, a ...interface{}) error {
+		return &preprocError{srcLine: at, Keyword: directive, Msg: fmt.Sprintf(format, a...)}
+	}
+
+	if directive == "$define" {
+		i := strings.IndexFunc(arg, unicode.IsSpace)
+		if i < 0 {
+			return fail("no value for %q", arg)
+		}
+		name, value := arg[:i], strings.TrimSpace(arg[i:])
+		if !paramNamePattern.MatchString(name) {
+			return fail("invalid parameter name %q", name)
+		}
+		value, err := p.expand(at, value)
+		if err != nil {
+			return err
+		}
+		p.params[name] = value
+		p.emit(at, "")
+		return nil
+	}
+
+	// #include
+	name, err := p.expand(at, arg)
+	if err != nil {
+		return err
+	}
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		name = name[1 : len(name)-1]
+	}
+	if name == "" {
+		return fail("no file name")
+	}
+	incl, b, err := p.open(at.File, name)
+	if err != nil {
+		return fail("%s", err)
+	}
+	for _, f := range p.files {
+		if f == incl {
+			return fail("circular include of %q", name)
+		}
+	}
+	return p.file(incl, string(b))
+}
+
+// open はファイル from からインクルードするファイル name を探し、そのパスと内容を返します。
+func (p *preprocessor) open(from, name string) (string, []byte, error) {
+	file := name
+	if !path.IsAbs(name) && !strings.Contains(name, ":") {
+		file = path.Join(path.Dir(strings.ReplaceAll(from, `\`, "/")), name)
+	}
+	if b, err := fs.ReadFile(p.fsys, file); err == nil {
+		return file, b, nil
+	}
+	if p.eflfs != nil && fs.ValidPath(name) {
+		if b, err := fs.ReadFile(p.eflfs, name); err == nil {
+			return name, b, nil
+		}
+	}
+	return "", nil, fmt.Errorf("file %q not found", name)
+}
+
+// expand は位置 at の s の `${...}` を置き換えます。
+func (p *preprocessor) expand(at srcLine, s string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return "", &preprocError{srcLine: at, Keyword: s[i:], Msg: "missing `}`"}
+		}
+		expr := strings.TrimSpace(s[i+2 : i+j])
+		v, err := p.value(expr)
+		if err != nil {
+			return "", &preprocError{srcLine: at, Keyword: s[i : i+j+1], Msg: err.Error()}
+		}
+		b.WriteString(s[:i])
+		b.WriteString(v)
+		s = s[i+j+1:]
+	}
+}
+
+// param はパラメータ name の値を返します。
+func (p *preprocessor) param(name string) (string, bool) {
+	if v, ok := p.defines[name]; ok {
+		return v, true
+	}
+	v, ok := p.params[name]
+	return v, ok
+}
+
+// value は `${...}` の中の式 expr の値を返します。
+// expr がパラメータ名の場合はその値を、それ以外は四則演算の結果を返します。
+func (p *preprocessor) value(expr string) (string, error) {
+	if paramNamePattern.MatchString(expr) {
+		if v, ok := p.param(expr); ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("parameter %q is not defined", expr)
+	}
+	e := &paramExpr{s: expr, p: p}
+	v, err := e.sum()
+	if err == nil && e.skip() < len(e.s) {
+		err = fmt.Errorf("unexpected %q in %q", e.s[e.i:], expr)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64), nil
+}
+
+// paramExpr は `${...}` の四則演算の式の読み取りの状態です。
+type paramExpr struct {
+	s string
+	i int
+	p *preprocessor
+}
+
+// skip は空白を読み飛ばし、次の文字の位置を返します。
+func (e *paramExpr) skip() int {
+	for e.i < len(e.s) && (e.s[e.i] == ' ' || e.s[e.i] == '\t') {
+		e.i++
+	}
+	return e.i
+}
+
+// sum は `項 { (+|-) 項 }` を読み取ります。
+func (e *paramExpr) sum() (float64, error) {
+	v, err := e.product()
+	for err == nil && e.skip() < len(e.s) && (e.s[e.i] == '+' || e.s[e.i] == '-') {
+		op := e.s[e.i]
+		e.i++
+		var w float64
+		if w, err = e.product(); op == '+' {
+			v += w
+		} else {
+			v -= w
+		}
+	}
+	return v, err
+}
+
+// product は `因子 { (*|/) 因子 }` を読み取ります。
+func (e *paramExpr) product() (float64, error) {
+	v, err := e.factor()
+	for err == nil && e.skip() < len(e.s) && (e.s[e.i] == '*' || e.s[e.i] == '/') {
+		op := e.s[e.i]
+		e.i++
+		var w float64
+		if w, err = e.factor(); err != nil {
+			break
+		}
+		if op == '*' {
+			v *= w
+		} else if w == 0 {
+			err = fmt.Errorf("division by zero in %q", e.s)
+		} else {
+			v /= w
+		}
+	}
+	return v, err
+}
+
+// factor は数値、パラメータ名、`(式)`、`-因子` を読み取ります。
+func (e *paramExpr) factor() (float64, error) {
+	if e.skip() >= len(e.s) {
+		return 0, fmt.Errorf("incomplete expression %q", e.s)
+	}
+	switch c := e.s[e.i]; {
+	case c == '-' || c == '+':
+		e.i++
+		v, err := e.factor()
+		if c == '-' {
+			v = -v
+		}
+		return v, err
+	case c == '(':
+		e.i++
+		v, err := e.sum()
+		if err != nil {
+			return 0, err
+		}
+		if e.skip() >= len(e.s) || e.s[e.i] != ')' {
+			return 0, fmt.Errorf("missing `)` in %q", e.s)
+		}
+		e.i++
+		return v, nil
+	case c == '_' || unicode.IsLetter(rune(c)):
+		start := e.i
+		for e.i < len(e.s) && (e.s[e.i] == '_' || unicode.IsLetter(rune(e.s[e.i])) || unicode.IsDigit(rune(e.s[e.i]))) {
+			e.i++
+		}
+		name := e.s[start:e.i]
+		s, ok := e.p.param(name)
+		if !ok {
+			return 0, fmt.Errorf("parameter %q is not defined", name)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return 0, fmt.Errorf("parameter %q is not a number: %q", name, s)
+		}
+		return v, nil
+	default:
+		start := e.i
+		for e.i < len(e.s) && (e.s[e.i] == '.' || unicode.IsDigit(rune(e.s[e.i])) ||
+			e.s[e.i] == 'e' || e.s[e.i] == 'E' ||
+			(e.i > start && (e.s[e.i] == '-' || e.s[e.i] == '+') && (e.s[e.i-1] == 'e' || e.s[e.i-1] == 'E'))) {
+			e.i++
+		}
+		v, err := strconv.ParseFloat(e.s[start:e.i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q in %q", e.s[start:e.i], e.s)
+		}
+		return v, nil
+	}
+}
@@ -0,0 +1,193 @@
+package eeslism
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestPreprocessInput はインクルード、パラメータの置換と、各行の元のファイルと行番号を確認する
+func TestPreprocessInput(t *testing.T) {
+	fsys := fstest.MapFS{
+		"model/room.txt": {Data: []byte(`$define U_WIN 2.33	! 窓の熱貫流率
+$define TAU 0.65
+#include "lib/walls.txt"
+WINDOW
+	SouthWindow t=${TAU} K=${ U_WIN } R=${(1/U_WIN - 0.17) * 2} ;	! ${U_WIN} は置き換えない
+*
+#include common.txt
+`)},
+		"model/lib/walls.txt": {Data: []byte(`WALL
+	-E:${WALL} RC-150 ;
+*
+`)},
+	}
+	eflfs := fstest.MapFS{"common.txt": {Data: []byte("$define WALL ExtWall\n")}}
+
+	text, lines, err := preprocessInput("model/room.txt", string(fsys["model/room.txt"].Data), fsys, eflfs,
+		map[string]string{"TAU": "0.4", "WALL": "Wall1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `
+
+WALL
+	-E:Wall1 RC-150 ;
+*
+WINDOW
+	SouthWindow t=0.4 K=2.33 R=0.5183690987124463 ;	! ${U_WIN} は置き換えない
+*
+
+`
+	if text != want {
+		t.Errorf("text:\n%s\nwant:\n%s", text, want)
+	}
+	wantLines := []srcLine{
+		{"model/room.txt", 1}, {"model/room.txt", 2},
+		{"model/lib/walls.txt", 1}, {"model/lib/walls.txt", 2}, {"model/lib/walls.txt", 3},
+		{"model/room.txt", 4}, {"model/room.txt", 5}, {"model/room.txt", 6},
+		{"common.txt", 1},
+	}
+	if len(lines) != len(wantLines) {
+		t.Fatalf("lines = %v", lines)
+	}
+	for i := range lines {
+		if lines[i] != wantLines[i] {
+			t.Errorf("line %d: %v, want %v", i+1, lines[i], wantLines[i])
+		}
+	}
+}
+
+// TestPreprocessInputErrors は前処理の誤りが行番号とともに報告されることを確認する
+func TestPreprocessInputErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("#include b.txt\n")},
+		"b.txt": {Data: []byte("\n#include a.txt\n")},
+	}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"WALL\n\t-E:${WALL} RC-150 ;\n", `room.txt:2: parameter "WALL" is not defined`},
+		{"$define A 1\n\tt=${A/(A-1)} ;\n", "room.txt:2: division by zero"},
+		{"$define A x\n\tt=${A*2} ;\n", `room.txt:2: parameter "A" is not a number`},
+		{"\tt=${1 +} ;\n", "room.txt:1: incomplete expression"},
+		{"\tt=${2 3} ;\n", "room.txt:1: unexpected"},
+		{"\tt=${A ;\n", "room.txt:1: missing `}`"},
+		{"$define 1A 2\n", `room.txt:1: invalid parameter name "1A"`},
+		{"$define A\n", `room.txt:1: no value for "A"`},
+		{"#include nosuch.txt\n", `room.txt:1: file "nosuch.txt" not found`},
+		{"#include a.txt\n", `b.txt:2: circular include of "a.txt"`},
+	}
+	for _, tt := range tests {
+		_, _, err := preprocessInput("room.txt", tt.input, fsys, nil, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+// TestSimulationPreprocess はインクルードとパラメータを用いた入力データファイルが、
+// 置き換えた後の入力データファイルと同じく読み込まれることを確認する
+func TestSimulationPreprocess(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Skip("test input not found")
+	}
+	input := string(b)
+	walls := "WALL\n\t-E:ExtWall  RC-150 FPS-50 GPB-12 ;\n\t-R:Roof     FPS-100 GPB-12 ;\n\t-F:Floor    GPB-12 FPS-100 RC-150 ;\n*\n"
+	if !strings.Contains(input, walls) {
+		t.Fatal("WALL not found in the test input")
+	}
+
+	bdata0 := func(files fstest.MapFS, defines map[string]string) string {
+		t.Helper()
+		sim := NewSimulation("room.txt", "")
+		sim.FS, sim.Defines = files, defines
+		out := new(MemorySink)
+		sim.Output = out
+		if err := sim.Init(); err != nil {
+			t.Fatal(err)
+		}
+		return string(out.Bytes("roombdata0.ewk"))
+	}
+	want := bdata0(fstest.MapFS{"room.txt": {Data: b}}, nil)
+
+	lib := strings.Replace(walls, "FPS-50", "FPS-${FPS}", 1)
+	param := strings.Replace(input, walls, "$define FPS 100\n#include \"lib/walls.txt\"\n", 1)
+	param = strings.Replace(param, "tokyo_3column_SI.has", "${WEATHER}", 1)
+	got := bdata0(fstest.MapFS{"room.txt": {Data: []byte(param)}, "lib/walls.txt": {Data: []byte(lib)}},
+		map[string]string{"FPS": "50", "WEATHER": "tokyo_3column_SI.has"})
+	if got != want {
+		t.Errorf("bdata0 differs:\n%s\nwant:\n%s", got, want)
+	}
+
+	// 誤りはインクルードしたファイルの行で報告する
+	sim := NewSimulation("room.txt", "")
+	sim.FS = fstest.MapFS{
+		"room.txt":      {Data: []byte(strings.Replace(input, walls, "#include \"lib/walls.txt\"\n", 1))},
+		"lib/walls.txt": {Data: []byte(walls + "VENT\n\tNoRoom Vent=(0.5,NoSch) ;\n*\n")},
+	}
+	diags, err := sim.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			errs = append(errs, d.String())
+		}
+	}
+	if len(errs) != 2 || !strings.HasPrefix(errs[0], "lib/walls.txt:7:2: error: VENT: ") || !strings.HasPrefix(errs[1], "lib/walls.txt:7:9: ") {
+		t.Errorf("diagnostics: %v", errs)
+	}
+	for _, d := range diags {
+		if d.Severity == SeverityWarning && d.File != "room.txt" {
+			t.Errorf("warning for an included file: %v", d)
+		}
+	}
+
+	// パラメータが定義されていない
+	sim.FS = fstest.MapFS{"room.txt": {Data: []byte(strings.Replace(input, "tokyo_3column_SI.has", "${WEATHER}", 1))}}
+	diags, err = sim.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 1 || diags[0].Line != 9 || !strings.Contains(diags[0].Msg, `"WEATHER" is not defined`) {
+		t.Errorf("diagnostics for an undefined parameter: %v", diags)
+	}
+}
+
+// TestInputDocumentDirective は前処理の指令の行が JSON への変換で保持され、名前の検査を妨げないことを確認する
+func TestInputDocumentDirective(t *testing.T) {
+	src := "$define U 2.33 ! 熱貫流率\n#include walls.txt\nWINDOW\n\tW1 t=${U} ;\n*\nROOM\n\tRoom1 Vol=100\n\t\tsouth: -E ExtWall 15.5 ;\n\t*\n*\n"
+	doc, err := ParseInputDocument(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := doc.Write(&b, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"directive":"$define U 2.33","comment":"熱貫流率"`) {
+		t.Errorf("json:\n%s", b.String())
+	}
+	back, err := ReadInputDocument(strings.NewReader(b.String()), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := back.String(); !strings.HasPrefix(text, "$define U 2.33 ! 熱貫流率\n#include walls.txt\n") {
+		t.Errorf("text:\n%s", text)
+	}
+
+	// 前処理しない検査では、インクルードしたファイルにあるかもしれない名前は警告とする
+	diags, err := CheckInput("room.txt", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if HasErrors(diags) {
+		t.Errorf("errors without preprocessing: %v", diags)
+	}
+}
@@ -4,11 +4,6 @@ import (
 	"fmt"
 )
 
-var __Eeprintd_ic int = 0
-var __Wdtdprint_ic int = 0
-var __Wdtprint_ic int = 0
-var __Wdtmprint_ic int = 0
-
 /*
 Eeprinth (Energy Simulation Hourly Print)
 
@@ -45,7 +40,7 @@ Eeprinth (Energy Simulation Hourly Print)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func Eeprinth(Daytm *DAYTM, Simc *SIMCONTL, flout []*FLOUT, Rmvls *RMVLS, Exsfst *EXSFS, Mpath []*MPATH, Eqsys *EQSYS, Wd *WDAT) {
+func (sim *Simulation) Eeprinth(Daytm *DAYTM, Simc *SIMCONTL, flout []*FLOUT, Rmvls *RMVLS, Exsfst *EXSFS, Mpath []*MPATH, Eqsys *EQSYS, Wd *WDAT) {
 	if Daytm.Ddpri != 0 && Simc.Dayprn[Daytm.DayOfYear] != 0 {
 		title := Simc.Title
 		Mon := Daytm.Mon
@@ -54,62 +49,62 @@ func Eeprinth(Daytm *DAYTM, Simc *SIMCONTL, flout []*FLOUT, Rmvls *RMVLS, Exsfst
 
 		for i, flo := range flout {
 
-			if DEBUG {
+			if Simc.Debug {
 				fmt.Printf("Eeprinth MAX=%d flo[%d]=%s\n", len(flout), i, flo.Idn)
 			}
 
 			switch flo.Idn {
 			case PRTHWD:
-				if DEBUG {
+				if Simc.Debug {
 					fmt.Println("<Eeprinth> xprsolrd")
 				}
 				// 気象データの出力
-				Wdtprint(flo.F, title, Mon, Day, time, Wd, Exsfst)
+				sim.Wdtprint(flo.F, title, Mon, Day, time, Wd, Exsfst)
 			case PRTCOMP: // 毎時機器の出力
-				Hcmpprint(flo.F, string(PRTCOMP), Simc, Mon, Day, time, Eqsys, Rmvls.Rdpnl)
+				sim.Hcmpprint(flo.F, string(PRTCOMP), Simc, Mon, Day, time, Eqsys, Rmvls.Rdpnl)
 			case PRTPATH: // システム経路の温湿度出力
-				Pathprint(flo.F, title, Mon, Day, time, Mpath)
+				sim.Pathprint(flo.F, title, Mon, Day, time, Mpath)
 			case PRTHRSTANK: // 蓄熱槽内温度分布の出力
-				Hstkprint(flo.F, title, Mon, Day, time, Eqsys)
+				sim.Hstkprint(flo.F, title, Mon, Day, time, Eqsys)
 			default:
 				if SIMUL_BUILDG { // these blocks are only compiled in debug builds
 					switch flo.Idn {
 					case PRTREV:
 						// 毎時室温、MRTの出力
-						Rmevprint(flo.F, title, Rmvls.Room, Mon, Day, time)
+						sim.Rmevprint(flo.F, title, Rmvls.Room, Mon, Day, time)
 					case PRTHROOM:
 						// 放射パネルの出力
-						Rmpnlprint(flo.F, string(PRTHROOM), Simc, Mon, Day, time, Rmvls.Room)
+						sim.Rmpnlprint(flo.F, string(PRTHROOM), Simc, Mon, Day, time, Rmvls.Room)
 					case PRTHELM:
 						// 要素別熱損失・熱取得
-						Helmprint(flo.F, string(PRTHELM), Simc, Mon, Day, time, Rmvls.Room, &Rmvls.Qetotal)
+						sim.Helmprint(flo.F, string(PRTHELM), Simc, Mon, Day, time, Rmvls.Room, &Rmvls.Qetotal)
 					case PRTHELMSF:
 						// 要素別熱損失・熱取得
-						Helmsurfprint(flo.F, string(PRTHELMSF), Simc, Mon, Day, time, Rmvls.Room)
+						sim.Helmsurfprint(flo.F, string(PRTHELMSF), Simc, Mon, Day, time, Rmvls.Room)
 					case PRTPMV:
 						// PMV計算
-						Pmvprint(flo.F, title, Rmvls.Room, Mon, Day, time)
+						sim.Pmvprint(flo.F, title, Rmvls.Room, Mon, Day, time)
 					case PRTQRM:
 						// 日射、室内熱取得の出力
-						Qrmprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Qrm)
+						sim.Qrmprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Qrm)
 					case PRTRSF:
 						// 室内表面温度の出力
-						Rmsfprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Sd)
+						sim.Rmsfprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Sd)
 					case PRTSHD:
 						// 日よけの影面積の出力
-						Shdprint(flo.F, title, Mon, Day, time, Rmvls.Sd)
+						sim.Shdprint(flo.F, title, Mon, Day, time, Rmvls.Sd)
 					case PRTWAL:
 						// 壁体内部温度の出力
-						Wallprint(flo.F, title, Mon, Day, time, Rmvls.Sd)
+						sim.Wallprint(flo.F, title, Mon, Day, time, Rmvls.Sd)
 					case PRTPCM:
 						// 潜熱蓄熱材の状態値の出力
-						PCMprint(flo.F, title, Mon, Day, time, Rmvls.Sd)
+						sim.PCMprint(flo.F, title, Mon, Day, time, Rmvls.Sd)
 					case PRTSFQ:
 						// 室内表面熱流の出力
-						Rmsfqprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Sd)
+						sim.Rmsfqprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Sd)
 					case PRTSFA:
 						// 室内表面熱伝達率の出力
-						Rmsfaprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Sd)
+						sim.Rmsfaprint(flo.F, title, Mon, Day, time, Rmvls.Room, Rmvls.Sd)
 					}
 				}
 			}
@@ -144,7 +139,7 @@ Eeprintd (Energy Simulation Daily Print)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func Eeprintd(Daytm *DAYTM, Simc *SIMCONTL, flout []*FLOUT, Rmvls *RMVLS, Exs []*EXSF, Soldy []float64, Eqsys *EQSYS, Wdd *WDAT) {
+func (sim *Simulation) Eeprintd(Daytm *DAYTM, Simc *SIMCONTL, flout []*FLOUT, Rmvls *RMVLS, Exs []*EXSF, Soldy []float64, Eqsys *EQSYS, Wdd *WDAT) {
 	if Daytm.Ddpri != 0 {
 		title := Simc.Title
 		Mon := int(Daytm.Mon)
@@ -154,30 +149,34 @@ func Eeprintd(Daytm *DAYTM, Simc *SIMCONTL, flout []*FLOUT, Rmvls *RMVLS, Exs []
 			switch flo.Idn {
 			case PRTDWD:
 				// 気象データ日集計値出力
-				Wdtdprint(flo.F, title, Mon, Day, Wdd, Exs, Soldy)
+				sim.Wdtdprint(flo.F, title, Mon, Day, Wdd, Exs, Soldy)
 			case PRTWK:
 				// 計算年月日出力
-				if __Eeprintd_ic == 0 {
+				if sim.__Eeprintd_ic == 0 {
+					if Simc.BaseYear > 0 {
+						fmt.Fprint(flo.F, "Yr ")
+					}
 					fmt.Fprintf(flo.F, "Mo Nd Day Week\n")
-					__Eeprintd_ic = 1
+					sim.__Eeprintd_ic = 1
 				}
 
+				sim.yearprint(flo.F, Simc, " ")
 				fmt.Fprintf(flo.F, "%2d %2d %3d %s\n", Mon, Day, Daytm.DayOfYear, DAYweek[Simc.Daywk[Daytm.DayOfYear]])
 			case PRTDYCOMP:
 				// システム要素機器の日集計結果出力
-				Compodyprt(flo.F, string(PRTDYCOMP), Simc, Mon, Day, Eqsys, Rmvls.Rdpnl)
+				sim.Compodyprt(flo.F, string(PRTDYCOMP), Simc, Mon, Day, Eqsys, Rmvls.Rdpnl)
 			case PRTDYRM:
 				// 部屋ごとの熱集計結果出力
-				Rmdyprint(flo.F, string(PRTDYRM), Simc, Mon, Day, Rmvls.Room)
+				sim.Rmdyprint(flo.F, string(PRTDYRM), Simc, Mon, Day, Rmvls.Room)
 			case PRTDYHELM:
 				// 要素別熱損失・熱取得（日積算値出力）
-				Helmdyprint(flo.F, string(PRTDYHELM), Simc, Mon, Day, Rmvls.Room, &Rmvls.Qetotal)
+				sim.Helmdyprint(flo.F, string(PRTDYHELM), Simc, Mon, Day, Rmvls.Room, &Rmvls.Qetotal)
 			case PRTDQR:
 				// 日射、室内熱取得の出力
-				Dyqrmprint(flo.F, title, Mon, Day, Rmvls.Room, Rmvls.Trdav, Rmvls.Qrmd)
+				sim.Dyqrmprint(flo.F, title, Mon, Day, Rmvls.Room, Rmvls.Trdav, Rmvls.Qrmd)
 			case PRTDYSF:
 				// 日積算壁体貫流熱取得の出力
-				Dysfprint(flo.F, title, Mon, Day, Rmvls.Room)
+				sim.Dysfprint(flo.F, title, Mon, Day, Rmvls.Room)
 			}
 		}
 	}
@@ -206,7 +205,7 @@ Eeprintm (Energy Simulation Monthly Print)
 空調システムの設計、運用、省エネルギー対策の検討、
 および快適性評価を行うための重要なデータ出力機能を提供します。
 */
-func Eeprintm(daytm *DAYTM, simc *SIMCONTL, flout []*FLOUT, rmvls *RMVLS, exs []*EXSF, solmon []float64, eqsys *EQSYS, wdm *WDAT) {
+func (sim *Simulation) Eeprintm(daytm *DAYTM, simc *SIMCONTL, flout []*FLOUT, rmvls *RMVLS, exs []*EXSF, solmon []float64, eqsys *EQSYS, wdm *WDAT) {
 	var title string
 	var mon, day int
 	title = simc.Title
@@ -217,13 +216,13 @@ func Eeprintm(daytm *DAYTM, simc *SIMCONTL, flout []*FLOUT, rmvls *RMVLS, exs []
 			switch flo.Idn {
 			case PRTMWD:
 				// 気象データ月集計値出力
-				Wdtmprint(flo.F, title, mon, day, wdm, exs, solmon)
+				sim.Wdtmprint(flo.F, title, mon, day, wdm, exs, solmon)
 			case PRTMNCOMP:
 				// システム要素機器の月集計結果出力
-				Compomonprt(flo.F, string(PRTMNCOMP), simc, mon, day, eqsys, rmvls.Rdpnl)
+				sim.Compomonprt(flo.F, string(PRTMNCOMP), simc, mon, day, eqsys, rmvls.Rdpnl)
 			case PRTMNRM:
 				// 部屋ごとの熱集計結果出力
-				Rmmonprint(flo.F, string(PRTMNRM), simc, mon, day, rmvls.Room)
+				sim.Rmmonprint(flo.F, string(PRTMNRM), simc, mon, day, rmvls.Room)
 			}
 		}
 	}
@@ -251,10 +250,10 @@ Eeprintmt (Energy Simulation Monthly-Time-of-Day Print)
 この関数は、建物のエネルギー消費量を月・時刻別で詳細に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要なデータ出力機能を提供します。
 */
-func Eeprintmt(simc *SIMCONTL, flout []*FLOUT, eqsys *EQSYS, rdpnl []*RDPNL) {
+func (sim *Simulation) Eeprintmt(simc *SIMCONTL, flout []*FLOUT, eqsys *EQSYS, rdpnl []*RDPNL) {
 	for _, flo := range flout {
 		if flo.Idn == PRTMTCOMP {
-			Compomtprt(flo.F, string(PRTMNCOMP), simc, eqsys, rdpnl)
+			sim.Compomtprt(flo.F, string(PRTMNCOMP), simc, eqsys, rdpnl)
 		}
 	}
 }
@@ -1,10 +1,12 @@
 package eeslism
 
+import "io"
+
 /* 経路に沿ったシステム要素の熱量計算 */
 
-func Pathheat(Mpath []*MPATH) {
+func Pathheat(Mpath []*MPATH, Ferr io.Writer) {
 	for _, mpath := range Mpath {
-		c := Spcheat(mpath.Fluid)
+		c := Spcheat(mpath.Fluid, Ferr)
 		for _, Pli := range mpath.Plist {
 			cG := c * Pli.G
 			for _, Pelm := range Pli.Pelm {
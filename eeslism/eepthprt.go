@@ -5,8 +5,6 @@ import (
 	"io"
 )
 
-var __Pathprint_id int = 0
-
 /*
 Pathprint (Path Print)
 
@@ -39,10 +37,10 @@ Pathprint (Path Print)
 熱搬送システムや空調システムの運転状況とエネルギーフローを詳細に分析し、
 省エネルギー対策の効果評価や、最適な設備システム設計を行うための重要なデータ出力機能を提供します。
 */
-func Pathprint(fo io.Writer, title string, mon int, day int, time float64, _Mpath []*MPATH) {
+func (sim *Simulation) Pathprint(fo io.Writer, title string, mon int, day int, time float64, _Mpath []*MPATH) {
 	// ** ヘッダーの出力 **
-	if __Pathprint_id == 0 {
-		__Pathprint_id++
+	if sim.__Pathprint_id == 0 {
+		sim.__Pathprint_id++
 		fmt.Fprintf(fo, "%s ;\n", title)
 		fmt.Fprintf(fo, "%d\n", len(_Mpath))
 
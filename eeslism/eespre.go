@@ -4,7 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
@@ -14,24 +14,53 @@ import (
 /*        注釈文の除去             */
 
 // Eesprera removes comments from the input file.
-func Eesprera(file string) string {
+// 注釈文を除く前に `#include`、`$define`、`${...}` を処理する（ref: eepreproc.go）。
+// インクルードするファイルは fsys、次に eflfs から探し、defines は `$define` より優先する。
+func Eesprera(file string, fsys, eflfs fs.FS, defines map[string]string, out OutputSink) string {
 	// 設定ファイルを開く
-	fi, err := os.Open(file)
+	fi, err := fsys.Open(file)
 	if err != nil {
 		fmt.Printf("File not found '%s'\n", file)
-		os.Exit(1)
+		panic(&InputError{Component: file, Msg: "file not found"})
 	}
 	defer fi.Close()
 
+	// JSON、YAML の入力データファイルは入力データファイルの書式に変換する
+	var text string
+	if format := InputFormat(file); format != "text" {
+		doc, err := ReadInputDocument(fi, format)
+		if err != nil {
+			panic(&InputError{Component: file, Msg: err.Error()})
+		}
+		text = doc.String()
+	} else {
+		b, err := io.ReadAll(fi)
+		if err != nil {
+			panic(err)
+		}
+		text = string(b)
+	}
+
+	// インクルードとパラメータの置換
+	text, _, err = preprocessInput(file, text, fsys, eflfs, defines)
+	if err != nil {
+		if e, ok := err.(*preprocError); ok {
+			panic(e.inputError())
+		}
+		panic(err)
+	}
+	r := strings.NewReader(text)
+
 	// 注釈文の除去語の設定ファイルを作成
 	RET := strings.TrimSuffix(file, filepath.Ext(file))
 	fb := new(strings.Builder)
 
-	scanner := bufio.NewScanner(fi)
+	scanner := bufio.NewScanner(r)
 
 	// 各行を処理
 	for scanner.Scan() {
-		processedLine := processLine(scanner.Text())
+		// 全角スペースは半角に置き換える
+		processedLine := strings.ReplaceAll(processLine(scanner.Text()), "\u3000", " ")
 		if processedLine != "" {
 			_, err := fb.WriteString(processedLine + "\n")
 			if err != nil {
@@ -77,13 +106,13 @@ func Eesprera(file string) string {
 	// fmt.Fprintln(fb, " ")
 
 	//互換性のために出力
-	fbo, err := os.Create(strings.Join([]string{RET, "bdata0.ewk"}, ""))
+	fbo, err := out.Create(strings.Join([]string{RET, "bdata0.ewk"}, ""))
 	if err != nil {
 		fmt.Println("Error creating file: ", err)
 	} else {
 		fmt.Fprint(fbo, fb)
+		defer fbo.Close()
 	}
-	defer fbo.Close()
 
 	return fb.String()
 }
@@ -115,7 +144,7 @@ func processLine(line string) string {
 //   (2) %sから始まる論理行のみを収録したテキスト -> schtba.ewk
 //   (3) %snから始まる論理行のみを収録したテキスト -> schenma.ewk
 //   (4) WEEKデータセット -> week.ewk
-func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, string) {
+func Eespre(bdata0 string, Ipath string, key *int, Fbmlist *string, out OutputSink) (string, string, string, string) {
 	fi := strings.NewReader(bdata0) //bdata0.ewk 相当
 
 	syspth := 0
@@ -128,8 +157,6 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 	fsn := new(strings.Builder) //schenma.ewk 相当 => %sn を拾う
 	fw := new(strings.Builder)  //week.ewk 相当
 
-	var st int
-
 	var section_marker = []string{"TITLE", "GDAT", "RUN", "PRINT", "SCHTB", "EXSRF", "PCM",
 		"WALL", "WINDOW", "SUNBRK", "ROOM", "RESI", "APPL", "VENT", "SYSCMP", "SYSPTH", "CONTL"}
 
@@ -190,16 +217,7 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 			continue
 		}
 
-		// 壁体の材料定義リストを指定
-		if strings.HasPrefix(s, "wbmlist=") {
-			if st = strings.IndexRune(s, ';'); st != -1 {
-				s = s[:st+1]
-			} else {
-				fmt.Fscanf(fi, "%*s")
-			}
-
-			Fbmlist = s[8:]
-		} else if s == "WEEK" {
+		if s == "WEEK" {
 			*key = 1
 			line := tokens.GetLogicalLine()
 			for _, item := range line {
@@ -214,6 +232,19 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 			tokens.RestorePos(pos)
 			line := tokens.GetLogicalLine()
 
+			// 壁体の材料定義リストを指定
+			// `WALL wbmlist=<filename> ;` は WALL と同じ論理行になるため、ここで取り除く
+			if i := slices.IndexFunc(line, func(s string) bool { return strings.HasPrefix(s, "wbmlist=") }); i >= 0 {
+				*Fbmlist = strings.TrimSuffix(line[i][8:], ";")
+				line = slices.Delete(line, i, i+1)
+				if n := len(line); n > 0 && line[n-1] == ";" && (n == 1 || slices.Contains(section_marker, line[0])) {
+					line = line[:n-1]
+				}
+				if len(line) == 0 {
+					continue
+				}
+			}
+
 			// `*` で終わる場合は空セクションを挿入
 			// SYSCMPでは `_OA101	-type FLI	-V t=Ta x=xa * ;`のように*で終端することがある
 			if line[len(line)-1] == "*" {
@@ -235,7 +266,7 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 	fmt.Fprintln(fsn, "*")
 
 	// ファイルに保存する(互換性のため)
-	fbo, err := os.Create(strings.Join([]string{Ipath, "bdata.ewk"}, ""))
+	fbo, err := out.Create(strings.Join([]string{Ipath, "bdata.ewk"}, ""))
 	if err != nil {
 		fmt.Println("Error creating file: ", err)
 	} else {
@@ -244,7 +275,7 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 	}
 
 	// SCHTBデータセット
-	fso, err := os.Create(strings.Join([]string{Ipath, "schtba.ewk"}, ""))
+	fso, err := out.Create(strings.Join([]string{Ipath, "schtba.ewk"}, ""))
 	if err != nil {
 		fmt.Println("Error creating file: ", err)
 	} else {
@@ -253,7 +284,7 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 	}
 
 	// SCHNMAデータセット
-	fsno, err := os.Create(strings.Join([]string{Ipath, "schnma.ewk"}, ""))
+	fsno, err := out.Create(strings.Join([]string{Ipath, "schnma.ewk"}, ""))
 	if err != nil {
 		fmt.Println("Error creating file: ", err)
 	} else {
@@ -262,7 +293,7 @@ func Eespre(bdata0 string, Ipath string, key *int) (string, string, string, stri
 	}
 
 	// WEEKデータセット
-	fwo, err := os.Create(strings.Join([]string{Ipath, "week.ewk"}, ""))
+	fwo, err := out.Create(strings.Join([]string{Ipath, "week.ewk"}, ""))
 	if err != nil {
 		fmt.Println("Error creating file: ", err)
 	} else {
@@ -1,5 +1,7 @@
 package eeslism
 
+import "io"
+
 var idmrkc = []FliudType{
 	AIRt_FLD,  //'t' 空気（温度）
 	AIRx_FLD,  //'x' 空気（湿度）
@@ -13,6 +15,7 @@ func Elmalloc(
 	Eqsys *EQSYS,
 	Elo *[]*ELOUT,
 	Eli *[]*ELIN,
+	Ferr io.Writer,
 ) {
 	var cmp []*COMPNT
 	var Hcc *HCC
@@ -448,7 +451,7 @@ func Elmalloc(
 			Flin[flinIdx].Cmp = Compnt
 			Flin[flinIdx].Name = name
 
-			flindat(Flin[flinIdx])
+			flindat(Flin[flinIdx], Ferr)
 
 			elins := NewElinSlice(Compnt.Nout)
 			*Eli = append(*Eli, elins...)
@@ -482,12 +485,12 @@ func Elmalloc(
 				Hcload[hcloadIdx].RMACFlg = 'Y'
 
 				// エアコンの機器スペックを読み込む
-				rmacdat(Hcload[hcloadIdx])
+				rmacdat(Hcload[hcloadIdx], Ferr)
 			} else if c == RMACD_TYPE {
 				Hcload[hcloadIdx].RMACFlg = 'y'
 
 				// エアコンの機器スペックを読み込む
-				rmacddat(Hcload[hcloadIdx])
+				rmacddat(Hcload[hcloadIdx], Ferr)
 			}
 
 			/*---- Roh Debug for a constant outlet humidity model of wet coil  2003/4/25 ----*/
@@ -790,7 +793,7 @@ func Elmalloc(
 				Compnt.Elins = append(Compnt.Elins, Elout.Elins...)
 			}
 		} else {
-			Errprint(1, "Elmalloc ", string(c))
+			Errprint(1, "Elmalloc ", string(c), Ferr)
 		}
 
 		for i = 0; i < Compnt.Nout; i++ {
@@ -2,10 +2,11 @@ package eeslism
 
 import (
 	"fmt"
+	"io"
 )
 
 // システム方程式の作成およびシステム変数の計算
-func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
+func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ, debug bool, Ferr io.Writer) {
 	var eleq, elosv []*ELOUT
 	var sysmcf, syscv, Y []float64
 	var i, m, n, Nsv int
@@ -40,12 +41,12 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 
 	for i, Elout := range _Elout {
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("xxx syseqv  Eo name=%s control=%c sysld=%c i=%d MAX=%d\n",
 				Elout.Cmp.Name, Elout.Control, Elout.Sysld, i, Nelout)
 		}
 
-		if dayprn && Ferr != nil {
+		if Ferr != nil {
 			fmt.Fprintf(Ferr, "xxx syseqv  Eo name=%s control=%c sysld=%c i=%d MAX=%d\n",
 				Elout.Cmp.Name, Elout.Control, Elout.Sysld, i, Nelout)
 		}
@@ -58,7 +59,7 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 		}
 
 		if Elout.Control == ON_SW {
-			if DEBUG {
+			if debug {
 				fmt.Printf("ON_SW = [i=%d m=%d n=%d] %s  G=%f\n", i, m, n, Elout.Cmp.Name, Elout.G)
 			}
 
@@ -94,12 +95,12 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 		a := sysmcf[Nsv*i : Nsv*i+Nsv]
 		b := syscv[i : i+1]
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("xxx syseqv Elout=%d %s Ni=%d cfo=%f\n",
 				i, elout.Cmp.Name, elout.Ni, elout.Coeffo)
 		}
 
-		if dayprn && Ferr != nil {
+		if Ferr != nil {
 			fmt.Fprintf(Ferr, "xxx syseqv Elout=%d %s Ni=%d cfo=%f\n",
 				i, elout.Cmp.Name, elout.Ni, elout.Coeffo)
 		}
@@ -135,13 +136,13 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 			}
 
 			if elov != nil {
-				if DEBUG {
+				if debug {
 					fmt.Printf("xxx syseqv Elout=%d %s  in=%d elov=%s  control=%c sys=%f\n",
 						i, elout.Cmp.Name, j,
 						elov.Cmp.Name, elov.Control, elov.Sysv)
 				}
 
-				if dayprn && Ferr != nil {
+				if Ferr != nil {
 					fmt.Fprintf(Ferr, "xxx syseqv Elout=%d %s  in=%d elov=%s  control=%c sys=%f\n",
 						i, elout.Cmp.Name, j,
 						elov.Cmp.Name, elov.Control, elov.Sysv)
@@ -157,12 +158,12 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 				} else if elov.Control == LOAD_SW ||
 					elov.Control == FLWIN_SW ||
 					elov.Control == BATCH_SW {
-					if DEBUG {
+					if debug {
 						fmt.Printf("xxx syseqv elov=%s  control=%c sys=%f\n",
 							elov.Cmp.Name, elov.Control, elov.Sysv)
 					}
 
-					if dayprn && Ferr != nil {
+					if Ferr != nil {
 						fmt.Fprintf(Ferr, "xxx syseqv elov=%s  control=%c sys=%f\n",
 							elov.Cmp.Name, elov.Control, elov.Sysv)
 					}
@@ -171,14 +172,14 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 				}
 			}
 		}
-		if DEBUG {
+		if debug {
 			fmt.Printf("xx syseqv  i=%d  b=%f\n", i, b[0])
 		}
 	}
 
 	/********* 連立方程式 ***********/
 
-	if DEBUG {
+	if debug {
 		Seqprint("%.6g\t", Nsv, sysmcf, "%.6g", syscv)
 
 		//for ( i = 0; i < Nsv; i++ )
@@ -201,7 +202,7 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 	}
 
 	if Nsv > 0 {
-		Matinv(sysmcf, Nsv, Nsv, "<Syseqv>")
+		Matinv(sysmcf, Nsv, Nsv, "<Syseqv>", Ferr)
 		Matmalv(sysmcf, syscv, Nsv, Nsv, Y)
 	}
 
@@ -211,12 +212,12 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 		} else if mrk[i] == LOAD_EQV {
 			elosv[i].Load = Y[i]
 		}
-		if DEBUG {
+		if debug {
 			fmt.Printf("%d: %s = %f\n", i, elosv[i].Cmp.Name, Y[i])
 		}
 	}
 
-	if DEBUG {
+	if debug {
 		for i = 0; i < Nsv; i++ {
 			fmt.Printf("Y[%d]=%.5f  mrk=%c  Elo=%s\n",
 				i, Y[i], mrk[i], elosv[i].Cmp.Name)
@@ -224,7 +225,7 @@ func Syseqv(_Elout []*ELOUT, Syseq *SYSEQ) {
 		fmt.Printf("\n")
 	}
 
-	if dayprn && Ferr != nil {
+	if Ferr != nil {
 		for i = 0; i < Nsv; i++ {
 			fmt.Fprintf(Ferr, "Y[%d]=%6.3f  mrk=%c  Elo=%s\n",
 				i, Y[i], mrk[i], elosv[i].Cmp.Name)
@@ -2,12 +2,13 @@ package eeslism
 
 import (
 	"fmt"
+	"io"
 )
 
 // Upo, Upv の書き換え
 // NOTE: おそらく、 Upoは経路要素における上流の要素を指す。
 //       Upvは計算時に参照すべき上流要素を指す。多くの場合は Upo == Upv だと考えらえる。
-func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
+func Sysupv(Mpath []*MPATH, Rmvls *RMVLS, debug bool, Ferr io.Writer) {
 	var Rdpnl *RDPNL
 	var Nrdpnl int
 	var up *ELOUT
@@ -15,17 +16,17 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 	for m, mpath := range Mpath {
 		/* 停止要素のシステム方程式からの除外 */
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("\n\n<< Sysupv >> m=%d  MAX=%d\n", m, len(Mpath))
 		}
 
 		for i, plist := range mpath.Plist {
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("\n<<Sysupv>  i=%d  iMAX=%d\n", i, len(mpath.Plist))
 				fmt.Printf("OFF_SW=%c  Plist->control=%c\n", OFF_SW, plist.Control)
 			}
-			if dayprn && Ferr != nil {
+			if Ferr != nil {
 				fmt.Fprintf(Ferr, "\n<<Sysupv>  i=%d  iMAX=%d\n", i, len(mpath.Plist))
 				fmt.Fprintf(Ferr, "OFF_SW=%c  Plist->control=%c\n", OFF_SW, plist.Control)
 			}
@@ -52,13 +53,13 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 				// 末端経路内の要素のループ
 				for j := pelmStartIdx; j < len(plist.Pelm); j++ {
 					pelm = plist.Pelm[j]
-					if DEBUG {
+					if debug {
 						fmt.Printf("\n<< sysupv >> pelm=%d %s  MAX=%d\n", j, pelm.Cmp.Name, len(plist.Pelm))
 						if pelm.Out != nil {
 							fmt.Printf("<< Sysupv >> Pelm->out->control=%c\n", pelm.Out.Control)
 						}
 					}
-					if dayprn && Ferr != nil {
+					if Ferr != nil {
 						fmt.Fprintf(Ferr, "\n<< sysupv >> pelm=%d %s  MAX=%d\n", j, pelm.Cmp.Name, len(plist.Pelm))
 						if pelm.Out != nil {
 							fmt.Fprintf(Ferr, "<< Sysupv >> Pelm->out->control=%c\n", pelm.Out.Control)
@@ -74,20 +75,20 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 							}
 						}
 					} else if pelm.Out.Control != OFF_SW {
-						if DEBUG {
+						if debug {
 							fmt.Printf("<<<<<< Pelm->out->control=%c FLWIN_SW=%c\n", pelm.Out.Control, FLWIN_SW)
 						}
-						if dayprn && Ferr != nil {
+						if Ferr != nil {
 							fmt.Fprintf(Ferr, "<<<<<< Pelm->out->control=%c FLWIN_SW=%c\n", pelm.Out.Control, FLWIN_SW)
 						}
 						if pelm.Out.Control == FLWIN_SW {
 							up = pelm.Out
 						} else {
-							if DEBUG {
+							if debug {
 								fmt.Printf("up->cmp->name=%s\n", up.Cmp.Name)
 							}
 
-							if dayprn && Ferr != nil {
+							if Ferr != nil {
 								fmt.Fprintf(Ferr, "up->cmp->name=%s\n", up.Cmp.Name)
 							}
 
@@ -96,7 +97,7 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 								pelm.In.Upv = up
 							}
 
-							if DEBUG {
+							if debug {
 								fmt.Printf("<< Sysupv >> pelm=%s up=%s\n", pelm.Cmp.Name, pelm.In.Upv.Cmp.Name)
 							}
 
@@ -108,13 +109,13 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 					} else if plist.Batch && j == 0 {
 						up = pelm.Out
 					} else {
-						if DEBUG {
+						if debug {
 							fmt.Printf("<Sysupv> 1\n")
 						}
 						if pelm.In != nil {
 							pelm.In.Upv = nil
 						}
-						if DEBUG {
+						if debug {
 							fmt.Printf("<Sysupv> 2\n")
 						}
 					}
@@ -146,7 +147,7 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 		/* 分岐要素のシステム方程式からの除外 */
 
 		for i, Plist := range mpath.Plist {
-			if DEBUG {
+			if debug {
 				fmt.Printf("  Sysupv  BRC  i=%d\n", i)
 			}
 			if Plist.Type == DIVERG_LPTP {
@@ -216,7 +217,7 @@ func Sysupv(Mpath []*MPATH, Rmvls *RMVLS) {
 			}
 		}
 
-		if DEBUG {
+		if debug {
 			fmt.Printf("  Sysupv end  ========\n")
 		}
 	}
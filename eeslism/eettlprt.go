@@ -125,6 +125,22 @@ func ttldyprint(fo io.Writer, fileid string, simc *SIMCONTL) {
 
 /* ---------------------------------------------------- */
 
+// yearprint は実暦による計算（-tmid Y...）の場合に、計算結果の各行の時刻の前に年と区切り文字 sep を出力します。
+func (sim *Simulation) yearprint(fo io.Writer, simc *SIMCONTL, sep string) {
+	if simc != nil && simc.BaseYear > 0 {
+		fmt.Fprintf(fo, "%d%s", sim.Daytm.Year, sep)
+	}
+}
+
+// yearttlprint は実暦による計算の場合に、タブ区切りの計算結果の見出し行に年の列 Yr を出力します。
+func yearttlprint(fo io.Writer, simc *SIMCONTL) {
+	if simc != nil && simc.BaseYear > 0 {
+		fmt.Fprint(fo, "Yr\t")
+	}
+}
+
+/* ---------------------------------------------------- */
+
 /*
 ttlmtprint (Title Print for Monthly-Time-of-Day Output)
 
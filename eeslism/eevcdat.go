@@ -1,16 +1,16 @@
 package eeslism
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strings"
 )
 
 /* 境界条件・負荷仮想機器の要素機器データ入力ファイル設定 */
 
-func Vcfdata(fi *EeTokens, simcon *SIMCONTL) {
+func Vcfdata(fi *EeTokens, simcon *SIMCONTL, Ferr io.Writer) {
 	var (
 		s      string
 		errFmt = "(vcfileint)"
@@ -72,7 +72,7 @@ func Vcfdata(fi *EeTokens, simcon *SIMCONTL) {
 				vcfile.Fname = fi.GetToken()
 			default:
 				e := fmt.Sprintf("Vcfile=%s %s %s", vcfile.Name, errFmt, s)
-				Eprint("<Vcfdata>", e)
+				Eprint("<Vcfdata>", e, Ferr)
 			}
 		}
 		vcIdx++
@@ -83,14 +83,14 @@ func Vcfdata(fi *EeTokens, simcon *SIMCONTL) {
 	for i := 0; i < simcon.Nvcfile; i++ {
 		vcfile := &simcon.Vcfile[i]
 
-		if f, err := os.Open(vcfile.Fname); err != nil {
-			Eprint("<Vcfdata>", vcfile.Fname)
-			os.Exit(EXIT_VCFILE)
+		if b, err := simcon.readFile(vcfile.Fname); err != nil {
+			Eprint("<Vcfdata>", vcfile.Fname, Ferr)
+			panic(&InputError{Section: "VCFILE", Component: vcfile.Fname, Msg: err.Error(), Code: EXIT_VCFILE})
 		} else {
-			vcfile.Fi = f
+			vcfile.Fi = bytes.NewReader(b)
 		}
 
-		esondat(vcfile.Fi, &vcfile.Estl)
+		esondat(vcfile.Fi, &vcfile.Estl, Ferr)
 		N := vcfile.Estl.Ndata
 		if N > 0 {
 			vcfile.Tlist = make([]TLIST, N)
@@ -112,7 +112,7 @@ func Vcfdata(fi *EeTokens, simcon *SIMCONTL) {
 			}
 		}
 
-		esoint(vcfile.Fi, "esoint", 1, &vcfile.Estl, vcfile.Tlist)
+		esoint(vcfile.Fi, "esoint", 1, &vcfile.Estl, vcfile.Tlist, Ferr)
 		vcfile.Ad, _ = vcfile.Fi.Seek(0, io.SeekCurrent)
 
 		if simcon.Wdtype == 'E' {
@@ -147,7 +147,7 @@ func VCFcount(fi *EeTokens) int {
 
 /* 境界条件・負荷仮想機器の要素機器データとしての入力処理 */
 
-func flindat(Flin *FLIN) {
+func flindat(Flin *FLIN, Ferr io.Writer) {
 	var s string
 	n := 0
 	//Err := fmt.Sprintf(ERRFMT, "(flindat)")
@@ -169,8 +169,8 @@ func flindat(Flin *FLIN) {
 				n++
 			}
 		} else {
-			Eprint("<flindat>", string(s))
-			os.Exit(EXIT_FLIN)
+			Eprint("<flindat>", string(s), Ferr)
+			panic(&InputError{Section: "SYSCMP", Keyword: s, Component: Flin.Cmp.Name, Msg: "invalid FLI parameter", Code: EXIT_FLIN})
 		}
 	}
 
@@ -194,13 +194,13 @@ func flindat(Flin *FLIN) {
 
 /* 境界条件・負荷仮想機器の要素機器データのポインター設定 */
 
-func Flinint(Flin []*FLIN, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
+func Flinint(Flin []*FLIN, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Ferr io.Writer) {
 	for _, flin := range Flin {
 		// fmt.Printf("<<Flinint>>  i=%d  namet=%s\n", i, Flin[i].namet)
 
-		flin.Vart = envptr(flin.Namet, Simc, Compnt, Wd, nil)
+		flin.Vart = envptr(flin.Namet, Simc, Compnt, Wd, nil, Ferr)
 		if flin.Awtype == 'A' {
-			flin.Varx = envptr(flin.Namex, Simc, Compnt, Wd, nil)
+			flin.Varx = envptr(flin.Namex, Simc, Compnt, Wd, nil, Ferr)
 		}
 	}
 }
@@ -237,9 +237,7 @@ func vcfptr(key []string, Simc *SIMCONTL) (VPTR, error) {
 
 /* 境界条件・負荷仮想機器のデータファイル入力 */
 
-var __Vcfinput_Mon, __Vcfinput_Day, __Vcfinput_Time int
-
-func Vcfinput(Daytm *DAYTM, Nvcfile int, Vcfile []VCFILE, perio rune) {
+func (sim *Simulation) Vcfinput(Daytm *DAYTM, Nvcfile int, Vcfile []VCFILE, perio rune) {
 	var Tmdt TMDT
 	TMDTinit(&Tmdt)
 
@@ -248,7 +246,7 @@ func Vcfinput(Daytm *DAYTM, Nvcfile int, Vcfile []VCFILE, perio rune) {
 		iderr := 0
 		vcfile := &Vcfile[i]
 
-		if vcfile.Estl.Tid == 'M' && __Vcfinput_Mon != Daytm.Mon {
+		if vcfile.Estl.Tid == 'M' && sim.__Vcfinput_Mon != Daytm.Mon {
 			idend = tmdata(vcfile, &Tmdt, Daytm, perio)
 			if idend != 0 {
 				if Daytm.Mon == Tmdt.Mon {
@@ -257,7 +255,7 @@ func Vcfinput(Daytm *DAYTM, Nvcfile int, Vcfile []VCFILE, perio rune) {
 					iderr = 1
 				}
 			}
-		} else if vcfile.Estl.Tid == 'd' && __Vcfinput_Day != Daytm.Day {
+		} else if vcfile.Estl.Tid == 'd' && sim.__Vcfinput_Day != Daytm.Day {
 			idend = tmdata(vcfile, &Tmdt, Daytm, perio)
 			if idend != 0 {
 				if Daytm.Day == Tmdt.Day {
@@ -266,7 +264,7 @@ func Vcfinput(Daytm *DAYTM, Nvcfile int, Vcfile []VCFILE, perio rune) {
 					iderr = 1
 				}
 			}
-		} else if vcfile.Estl.Tid == 'h' && __Vcfinput_Time != Daytm.Ttmm {
+		} else if vcfile.Estl.Tid == 'h' && sim.__Vcfinput_Time != Daytm.Ttmm {
 			idend = tmdata(vcfile, &Tmdt, Daytm, perio)
 			if idend != 0 {
 				if Daytm.Mon == Tmdt.Mon && Daytm.Day == Tmdt.Day && Daytm.Ttmm == Tmdt.Time {
@@ -278,23 +276,23 @@ func Vcfinput(Daytm *DAYTM, Nvcfile int, Vcfile []VCFILE, perio rune) {
 		}
 		if idend == 0 {
 			E := fmt.Sprintf("Vcfinput file-end: %s\n", vcfile.Fname)
-			Eprint("<Vcfinput>", E)
+			Eprint("<Vcfinput>", E, sim.Ferr)
 		}
 
 		if iderr != 0 {
 			E := fmt.Sprintf("Vcfinput xxx file=%s prog_MM/DD/TM=%d/%d/%d file_MM/DD/TM=%d/%d/%d\n",
 				vcfile.Fname, Daytm.Mon, Daytm.Day, Daytm.Ttmm, Tmdt.Mon, Tmdt.Day, Tmdt.Time)
-			Eprint("<Vcfinput>", E)
+			Eprint("<Vcfinput>", E, sim.Ferr)
 		}
 	}
 
-	__Vcfinput_Mon, __Vcfinput_Day, __Vcfinput_Time = Daytm.Mon, Daytm.Day, Daytm.Ttmm
+	sim.__Vcfinput_Mon, sim.__Vcfinput_Day, sim.__Vcfinput_Time = Daytm.Mon, Daytm.Day, Daytm.Ttmm
 }
 
 /********************************************************************/
 
-func Flinprt(Fl []*FLIN) {
-	if DEBUG {
+func Flinprt(Fl []*FLIN, debug bool, Ferr io.Writer) {
+	if debug {
 		for i, f := range Fl {
 			fmt.Printf("<< Flinprt >> Flin i=%d  %s %s = %.2g\n", i, f.Name, f.Namet, *f.Vart)
 		}
@@ -41,7 +41,7 @@ esondat (Energy Simulation Output Data Input)
 省エネルギー設計、快適性評価、
 および運用改善のための意思決定を支援するための重要な役割を果たします。
 */
-func esondat(fi io.Reader, Estl *ESTL) {
+func esondat(fi io.Reader, Estl *ESTL, Ferr io.Writer) {
 	var s string
 	var i, j, Nparm, Ndat int
 	var catnm, C *CATNM
@@ -135,7 +135,7 @@ func esondat(fi io.Reader, Estl *ESTL) {
 			if s[len(s)-1] == '#' {
 				Estl.Flid = s
 			} else {
-				Eprint("<esondat>", s)
+				Eprint("<esondat>", s, Ferr)
 			}
 		}
 	}
@@ -184,7 +184,7 @@ esoint (Energy Simulation Output Data Initialization)
 省エネルギー設計、快適性評価、
 および運用改善のための意思決定を支援するための重要な役割を果たします。
 */
-func esoint(fi io.Reader, err string, Ntime int, Estl *ESTL, _Tlist []TLIST) {
+func esoint(fi io.Reader, err string, Ntime int, Estl *ESTL, _Tlist []TLIST, Ferr io.Writer) {
 	var nm, id string
 	var V *rune
 	var st int
@@ -222,7 +222,7 @@ func esoint(fi io.Reader, err string, Ntime int, Estl *ESTL, _Tlist []TLIST) {
 				Tlist.Stype = 'm'
 			default:
 				s := fmt.Sprintf("xxxx %s xxx  %s %s %c %c %c\n", err, nm, id, id[len(id)-1], Tlist.Vtype, Tlist.Ptype)
-				Eprint("<esoint>", s)
+				Eprint("<esoint>", s, Ferr)
 			}
 		}
 
@@ -0,0 +1,331 @@
+/*
+epw.go (EnergyPlus Weather File Reader)
+
+EnergyPlus の気象データファイル（EPW）を読み込みます。
+GDAT FILE の w= に拡張子 .epw のファイルを指定すると、気象データファイル種別 'P' となります。
+
+	GDAT
+	    FILE w=JPN_Tokyo.Hyakuri.477150_IWEC.epw ;
+
+読み込みの方法は次のとおりです。
+
+  - ヘッダーの LOCATION 行から地名、緯度、経度、標準子午線（時差×15°）、標高を LOCAT に設定します。
+  - 乾球温度、露点温度（欠測の場合は相対湿度）、法線面直達日射量、水平面天空日射量、水平面大気放射量、
+    風向、風速、全雲量を HASP 形式と同じ日単位の配列（ref: hspwdread）に変換し、dt2wdata、wdatadiv で WDAT とします。
+  - EPW の時刻 h（1～24）は h-1 時～h 時の時間帯を表す時間終端値であるため、そのまま tt=h の値とします。
+    0 時の値は前日の 24 時の値です。
+  - 夜間放射量は水平面大気放射量から求めます（GDAT FILE -skyrd と同じ）。大気放射量が欠測の時刻は雲量から求めます。
+  - 2/29 を含むうるう年のファイルは 2/29 を読み飛ばします。1 時間より短い間隔のファイルは扱いません。
+  - 給水温度と地中温度の係数は supw.efl を用いず、月平均気温、日平均気温から求めます（ref: EPW.ground）。
+*/
+package eeslism
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EPW の気象データ
+type EPW struct {
+	City string  // 地名 (LOCATION)
+	Lat  float64 // 緯度[deg]
+	Lon  float64 // 経度[deg]
+	TZ   float64 // 時差[h]
+	Elev float64 // 標高[m]
+
+	Mon, Day [365]int                    // 各日の月日
+	Data     [365][epwNfield][25]float64 // 日毎の時刻別の値（添字 1～24）
+}
+
+// EPW から読み取る値
+const (
+	epwT    = iota // 乾球温度 [C]
+	epwX           // 絶対湿度 [kg/kg]
+	epwIdn         // 法線面直達日射量 [W/m2]
+	epwIsky        // 水平面天空日射量 [W/m2]
+	epwIR          // 水平面大気放射量 [W/m2]。欠測は FNAN
+	epwCC          // 全雲量 (10分比)。欠測は FNAN
+	epwWdre        // 風向 (16方位)
+	epwWv          // 風速 [m/s]
+	epwNfield
+)
+
+// isEPW は気象データファイル name が EPW であるかを拡張子から判定します。
+func isEPW(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".epw")
+}
+
+// ReadEPW は EPW を読み込みます。
+func ReadEPW(r io.Reader) (*EPW, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := readCSVRows(b)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &EPW{}
+	var seen [365]bool
+	loc := false
+	for i, row := range rows {
+		line := i + 1
+		if len(row) == 0 {
+			continue
+		}
+		switch strings.ToUpper(strings.TrimSpace(row[0])) {
+		case "LOCATION":
+			if len(row) < 10 {
+				return nil, fmt.Errorf("line %d: LOCATION requires 10 fields", line)
+			}
+			e.City = strings.TrimSpace(row[1])
+			v, err := epwFloats(row[6:10])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: LOCATION: %v", line, err)
+			}
+			e.Lat, e.Lon, e.TZ, e.Elev = v[0], v[1], v[2], v[3]
+			loc = true
+			continue
+		case "DATA PERIODS":
+			if len(row) > 2 {
+				if n, err := strconv.Atoi(strings.TrimSpace(row[2])); err == nil && n != 1 {
+					return nil, fmt.Errorf("line %d: %d records per hour is not supported (hourly data only)", line, n)
+				}
+			}
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSpace(row[0])); err != nil {
+			// DESIGN CONDITIONS、GROUND TEMPERATURES などのヘッダー行
+			continue
+		}
+
+		if len(row) < 23 {
+			return nil, fmt.Errorf("line %d: data record requires at least 23 fields", line)
+		}
+		v, err := epwFloats(row[1:4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		mon, day, hour := int(v[0]), int(v[1]), int(v[2])
+		if mon == 2 && day == 29 {
+			continue
+		}
+		if mon < 1 || mon > 12 || day < 1 || day > 31 || hour < 1 || hour > 24 {
+			return nil, fmt.Errorf("line %d: invalid date %d/%d hour %d", line, mon, day, hour)
+		}
+		nday := FNNday(mon, day)
+		d := nday - 1
+		e.Mon[d], e.Day[d] = mon, day
+		seen[d] = true
+
+		if err := e.record(&e.Data[d], hour, row); err != nil {
+			return nil, fmt.Errorf("line %d (%d/%d %d:00): %v", line, mon, day, hour, err)
+		}
+	}
+
+	if !loc {
+		return nil, fmt.Errorf("LOCATION is not found")
+	}
+	for d := range seen {
+		if !seen[d] {
+			mon, day := 1, 1
+			for i := 0; i < d; i++ {
+				mon, day = monthday(mon, day)
+			}
+			return nil, fmt.Errorf("no data for %d/%d", mon, day)
+		}
+	}
+	return e, nil
+}
+
+// record は EPW の1行を時刻 hour の値として dt に格納します。
+func (e *EPW) record(dt *[epwNfield][25]float64, hour int, row []string) error {
+	field := func(i int, missing float64) (float64, bool, error) {
+		v, err := strconv.ParseFloat(strings.TrimSpace(row[i]), 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return v, v < missing, nil
+	}
+
+	T, ok, err := field(6, 99.9)
+	if err != nil || !ok {
+		return fmt.Errorf("dry bulb temperature is missing")
+	}
+	dt[epwT][hour] = T
+
+	// 絶対湿度は露点温度から求め、露点温度が欠測の場合は相対湿度から求める
+	if Tdp, ok, err := field(7, 99.9); err == nil && ok {
+		dt[epwX][hour] = FNXp(FNPws(Tdp))
+	} else if RH, ok, err := field(8, 999); err == nil && ok {
+		dt[epwX][hour] = FNXtr(T, RH)
+	} else {
+		return fmt.Errorf("dew point and relative humidity are missing")
+	}
+
+	for _, f := range []struct {
+		k, i int
+		name string
+	}{
+		{epwIdn, 14, "direct normal radiation"},
+		{epwIsky, 15, "diffuse horizontal radiation"},
+	} {
+		v, ok, err := field(f.i, 9999)
+		if err != nil || !ok {
+			return fmt.Errorf("%s is missing", f.name)
+		}
+		dt[f.k][hour] = v
+	}
+
+	dt[epwIR][hour] = FNAN
+	if v, ok, err := field(12, 9999); err == nil && ok {
+		dt[epwIR][hour] = v
+	}
+	dt[epwCC][hour] = FNAN
+	if v, ok, err := field(22, 99); err == nil && ok {
+		dt[epwCC][hour] = v
+	}
+	if dt[epwIR][hour] == FNAN && dt[epwCC][hour] == FNAN {
+		return fmt.Errorf("horizontal infrared radiation and total sky cover are missing")
+	}
+
+	Wv, ok, err := field(21, 999)
+	if err != nil || !ok {
+		return fmt.Errorf("wind speed is missing")
+	}
+	dt[epwWv][hour] = Wv
+
+	dt[epwWdre][hour] = 0.0
+	if Wa, ok, err := field(20, 999); err == nil && ok {
+		dt[epwWdre][hour] = wdre16(Wa, Wv)
+	}
+	return nil
+}
+
+// epwFloats は s を数値に変換します。
+func epwFloats(s []string) ([]float64, error) {
+	v := make([]float64, len(s))
+	for i := range s {
+		var err error
+		if v[i], err = strconv.ParseFloat(strings.TrimSpace(s[i]), 64); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Location は地名、緯度、経度、標準子午線、標高を Loc に設定します。
+func (e *EPW) Location(Loc *LOCAT) {
+	Loc.Name = e.City
+	Loc.Lat = e.Lat
+	Loc.Lon = e.Lon
+	Loc.Ls = e.TZ * 15.0
+	Loc.Elev = e.Elev
+}
+
+// ground は月平均給水温度と地中温度の係数を日平均気温から求めます（ref: wdGround）。
+func (e *EPW) ground(Loc *LOCAT) {
+	nday := make([]int, len(e.Data))
+	Td := make([]float64, len(e.Data))
+	for d := range e.Data {
+		nday[d] = d + 1
+		for t := 1; t <= 24; t++ {
+			Td[d] += e.Data[d][epwT][t] / 24.0
+		}
+	}
+	wdGround(Loc, nday, Td)
+}
+
+// hour は通日 nday の時刻 tt の値を HASP 形式の気象データ（ref: hspwdread）の7要素で返します。
+// 夜間放射量は大気放射量から求め、大気放射量が欠測の場合は雲量から求めます。
+func (e *EPW) hour(nday, tt int) [7]float64 {
+	if tt == 0 {
+		nday, tt = nday-1, 24
+	}
+	if nday > 365 {
+		nday -= 365
+	}
+	if nday <= 0 {
+		nday += 365
+	}
+	v := &e.Data[nday-1]
+
+	T, X := v[epwT][tt], v[epwX][tt]
+	Tabs4 := mathPow(T+273.15, 4.0)
+	var RN float64
+	if v[epwIR][tt] != FNAN {
+		RN = Sgm*Tabs4 - v[epwIR][tt]
+	} else {
+		Br := 0.51 + 0.209*mathSqrt(FNPwx(X))
+		RN = (1.0 - 0.62*v[epwCC][tt]/10.0) * (1.0 - Br) * Sgm * Tabs4
+	}
+
+	// 日射量と夜間放射量は HASP 形式の単位 [kcal/m2h] とする
+	return [7]float64{T, X, v[epwIdn][tt] * 0.86, v[epwIsky][tt] * 0.86, RN * 0.86, v[epwWdre][tt], v[epwWv][tt]}
+}
+
+/*
+epwwdread (EPW Weather Data Reader)
+
+この関数は、EPW の気象データから通日 `nday` の1日分の気象データを読み込み、
+HASP 形式（`hspwdread`）と同じ配列 `dt` に格納します。
+最初の呼び出しで `Simc.Fwdata` の全体を読み込み、地点情報を `Loc` に設定します。
+
+建築環境工学的な観点:
+  - **海外の気象データ**: EPW は世界各地の標準年気象データとして広く配布されており、
+    HASP 形式への変換なしに海外の建物の熱負荷計算を行うことができます。
+  - **時刻の扱い**: EPW の各時刻の値は前1時間の値（時間終端値）であり、
+    HASP 形式の気象データと同じ扱いとなります。
+
+この関数は、`dt2wdata`、`wdatadiv` による気象データの処理を
+HASP 形式と共通にするための役割を果たします。
+*/
+func (sim *Simulation) epwwdread(Simc *SIMCONTL, nday int, Loc *LOCAT, dt *[7][25]float64) (year int, mon int, day int, wkdy int) {
+	if sim.__epwwdread_epw == nil {
+		Simc.Fwdata.Seek(0, io.SeekStart)
+		e, err := ReadEPW(Simc.Fwdata)
+		if err != nil {
+			Eprint("<epwwdread>", err.Error(), sim.Ferr)
+			panic(&WeatherError{Section: "GDAT", Keyword: "FILE", Component: Simc.Wfname, Msg: err.Error(), Code: EXIT_WFILE})
+		}
+		e.Location(Loc)
+		if sim.Ferr != nil {
+			fmt.Fprintf(sim.Ferr, "\n------> <epwwdread> \n")
+			fmt.Fprintf(sim.Ferr, "\nName=%s\tLat=%.4g\tLon=%.4g\tLs=%.4g\tElev=%.4g\n",
+				Loc.Name, Loc.Lat, Loc.Lon, Loc.Ls, Loc.Elev)
+		}
+		sim.__epwwdread_epw = e
+	}
+	e := sim.__epwwdread_epw
+
+	if nday > 365 {
+		nday = nday - 365
+	}
+	if nday <= 0 {
+		nday = nday + 365
+	}
+
+	for t := 0; t < 25; t++ {
+		v := e.hour(nday, t)
+		for k := 0; k < 7; k++ {
+			dt[k][t] = v[k]
+		}
+	}
+
+	return 0, e.Mon[nday-1], e.Day[nday-1], 0
+}
+
+// wdread は気象データファイル種別に応じて通日 nday の1日分の時刻別の気象データを dt に読み込みます。
+func (sim *Simulation) wdread(Simc *SIMCONTL, fp io.ReadSeeker, nday int, Loc *LOCAT, dt *[7][25]float64) (year int, mon int, day int, wkdy int) {
+	switch Simc.Wdtype {
+	case 'P':
+		return sim.epwwdread(Simc, nday, Loc, dt)
+	case 'A':
+		return sim.amdwdread(Simc, nday, Loc, dt)
+	}
+	return sim.hspwdread(fp, nday, Loc, dt)
+}
@@ -0,0 +1,204 @@
+package eeslism
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const epwTestHeader = `LOCATION,Tokyo,-,JPN,HASP,476620,35.652832,139.839478,9.0,6.0
+DESIGN CONDITIONS,0
+TYPICAL/EXTREME PERIODS,0
+GROUND TEMPERATURES,0
+HOLIDAYS/DAYLIGHT SAVINGS,No,0,0,0
+COMMENTS 1,converted from tokyo_3column_SI.has
+COMMENTS 2,
+DATA PERIODS,1,1,Data,Sunday, 1/ 1,12/31
+`
+
+// epwTestRow は EPW の1行を返す。露点温度 99.9 は欠測として相対湿度を用いる
+func epwTestRow(mon, day, hour int, T, Tdp, RH, IR, Idn, Isky, Wa, Wv, CC float64) string {
+	return fmt.Sprintf("2001,%d,%d,%d,60,?,%.10g,%.10g,%.10g,101325,9999,9999,%.10g,9999,%.10g,%.10g,999999,999999,999999,999999,%.10g,%.10g,%.10g,%.10g,9999,9999,9,999999999,0,0,0,0,0,0,0\n",
+		mon, day, hour, T, Tdp, RH, IR, Idn, Isky, Wa, Wv, CC, CC)
+}
+
+// haspToEPW は HASP 形式の気象データを、同じ気象データとなる EPW に変換する。
+// 夜間放射量は雲量から求めた大気放射量とする
+func haspToEPW(t *testing.T, hasp []byte) string {
+	t.Helper()
+	sim := NewSimulation("", "")
+	var Loc LOCAT
+	var b strings.Builder
+	b.WriteString(epwTestHeader)
+	fp := bytes.NewReader(hasp)
+	for nday := 1; nday <= 365; nday++ {
+		var dt [7][25]float64
+		_, mon, day, _ := sim.hspwdread(fp, nday, &Loc, &dt)
+		for tt := 1; tt <= 24; tt++ {
+			Wd := WDAT{RNtype: 'C'}
+			dt2wdata(&Wd, tt, dt)
+			b.WriteString(epwTestRow(mon, day, tt, Wd.T, 99.9, Wd.RH, Wd.Rsky, Wd.Idn, Wd.Isky, dt[5][tt]*22.5, Wd.Wv, Wd.CC))
+		}
+	}
+	return b.String()
+}
+
+// TestReadEPW は地点情報、湿度、風向、大気放射量の欠測、2/29 の読み飛ばしと誤りを確認する
+func TestReadEPW(t *testing.T) {
+	rows := func(leap bool, row func(mon, day, hour int) string) string {
+		var b strings.Builder
+		b.WriteString(epwTestHeader)
+		mon, day := 1, 1
+		for nday := 1; nday <= 365; nday++ {
+			for hour := 1; hour <= 24; hour++ {
+				b.WriteString(row(mon, day, hour))
+			}
+			if leap && mon == 2 && day == 28 {
+				for hour := 1; hour <= 24; hour++ {
+					b.WriteString(epwTestRow(2, 29, hour, 50, 99.9, 50, 300, 0, 0, 0, 0, 0))
+				}
+			}
+			mon, day = monthday(mon, day)
+		}
+		return b.String()
+	}
+	basic := func(mon, day, hour int) string {
+		return epwTestRow(mon, day, hour, float64(hour), 5.0, 999, 300, 500, 100, 90, 2.0, 5)
+	}
+
+	e, err := ReadEPW(strings.NewReader(rows(true, basic)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var Loc LOCAT
+	e.Location(&Loc)
+	if Loc.Name != "Tokyo" || Loc.Lat != 35.652832 || Loc.Lon != 139.839478 || Loc.Ls != 135.0 || Loc.Elev != 6.0 {
+		t.Errorf("LOCAT = %+v", Loc)
+	}
+	if e.Mon[59] != 3 || e.Day[59] != 1 || e.Data[59][epwT][1] != 1.0 {
+		t.Errorf("day 60 = %d/%d T=%g, want 3/1 T=1 (2/29 skipped)", e.Mon[59], e.Day[59], e.Data[59][epwT][1])
+	}
+	if X, want := e.Data[0][epwX][1], FNXp(FNPws(5.0)); X != want {
+		t.Errorf("X = %g, want %g (dew point 5C)", X, want)
+	}
+
+	v := e.hour(1, 0)
+	if v[0] != 24.0 {
+		t.Errorf("1/1 0:00 T = %g, want 24 (12/31 24:00)", v[0])
+	}
+	v = e.hour(1, 12)
+	want := [7]float64{12, e.Data[0][epwX][12], 500 * 0.86, 100 * 0.86, (Sgm*math.Pow(12+273.15, 4) - 300) * 0.86, 4, 2}
+	for k := range want {
+		if math.Abs(v[k]-want[k]) > 1e-9 {
+			t.Errorf("1/1 12:00 [%d] = %g, want %g", k, v[k], want[k])
+		}
+	}
+
+	// 大気放射量の欠測は雲量から、北風は 16、静穏は 0 とする
+	e, err = ReadEPW(strings.NewReader(rows(false, func(mon, day, hour int) string {
+		Wv := 3.0
+		if hour == 2 {
+			Wv = 0
+		}
+		return epwTestRow(mon, day, hour, 10, 99.9, 60, 9999, 0, 0, 355, Wv, 10)
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Wd := WDAT{RNtype: 'C'}
+	dt := [7][25]float64{{0, 10}, {0, FNXtr(10, 60)}, {}, {}, {0, 10}, {}, {}}
+	dt2wdata(&Wd, 1, dt)
+	if v := e.hour(10, 1); math.Abs(v[4]-Wd.RN*0.86) > 1e-9 || v[5] != 16 {
+		t.Errorf("RN = %g, want %g, Wdre = %g, want 16", v[4], Wd.RN*0.86, v[5])
+	}
+	if v := e.hour(10, 2); v[5] != 0 {
+		t.Errorf("calm Wdre = %g, want 0", v[5])
+	}
+
+	errs := map[string]string{
+		"no LOCATION": strings.TrimPrefix(rows(false, basic), strings.SplitAfter(epwTestHeader, "\n")[0]),
+		"missing day": strings.Replace(rows(false, basic), "2001,7,4,", "2001,7,5,", -1),
+		"missing T": rows(false, func(mon, day, hour int) string {
+			return epwTestRow(mon, day, hour, 99.9, 5, 50, 300, 0, 0, 0, 0, 0)
+		}),
+		"missing humidity": rows(false, func(mon, day, hour int) string {
+			return epwTestRow(mon, day, hour, 10, 99.9, 999, 300, 0, 0, 0, 0, 0)
+		}),
+		"sub-hourly": strings.Replace(rows(false, basic), "DATA PERIODS,1,1,", "DATA PERIODS,1,4,", 1),
+	}
+	for name, s := range errs {
+		if _, err := ReadEPW(strings.NewReader(s)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// TestSimulation_EPW は HASP 形式から変換した EPW による計算の気象データが HASP 形式の場合と一致することを確認する
+func TestSimulation_EPW(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasp, err := os.ReadFile(filepath.Join(eflPath, "tokyo_3column_SI.has"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	epw := filepath.Join(dir, "tokyo.epw")
+	if err := os.WriteFile(epw, []byte(haspToEPW(t, hasp)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "input.txt")
+	b = []byte(strings.Replace(string(b), "w=tokyo_3column_SI.has", "w="+epw, 1))
+	if err := os.WriteFile(input, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+	sim := NewSimulation(input, eflPath)
+	for _, s := range []*Simulation{ref, sim} {
+		if err := s.Init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sim.Simc.Wdtype != 'P' || sim.Wd.RNtype != 'R' {
+		t.Fatalf("Wdtype = %c, RNtype = %c, want P, R", sim.Simc.Wdtype, sim.Wd.RNtype)
+	}
+
+	for !ref.Done() {
+		if err := ref.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+		r, w := ref.Wd, sim.Wd
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"T", w.T, r.T}, {"X", w.X, r.X}, {"Idn", w.Idn, r.Idn}, {"Isky", w.Isky, r.Isky},
+			{"Rsky", w.Rsky, r.Rsky}, {"Wv", w.Wv, r.Wv}, {"Sh", w.Sh, r.Sh},
+		} {
+			if math.Abs(v.got-v.want) > 1e-6*math.Max(1, math.Abs(v.want)) {
+				t.Fatalf("%d/%d %d: %s = %g, want %g", sim.Daytm.Mon, sim.Daytm.Day, sim.Daytm.Tt, v.name, v.got, v.want)
+			}
+		}
+	}
+	if sim.Loc.Name != "Tokyo" || sim.Loc.Ls != 135.0 || sim.Loc.Tgrav == 0 {
+		t.Errorf("LOCAT = %+v", *sim.Loc)
+	}
+	if err := sim.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+}
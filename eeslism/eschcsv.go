@@ -0,0 +1,222 @@
+/*
+eschcsv.go (CSV Time Series Schedule)
+
+SCHNM（`%sn`）の設定値、切換スケジュールに、CSV ファイルの列の時系列（8760 時間値など）を用います。
+
+	%sn -v OccRate csv=occupancy.csv col=Occupancy ;
+	%sn -s ACmode csv=hvac.csv col=3 ;
+
+キーワードは次のとおりです。
+
+  - csv=: CSV ファイル名。入力データファイルと同じディレクトリ、次に EFL のディレクトリから探します（ref: SIMCONTL.readRef）。
+  - col=: 列。見出し行の列名、または 1 から始まる列番号です。
+    指定した列の1行目が数値でない場合は、1行目を見出し行とします。
+  - 行数は 1 年 365 日分の 1 日あたり整数個（8760 行: 1 時間、35040 行: 15 分など）とし、
+    1 行目は 1/1 0:00 からの最初の区間（1 時間値の場合は 1:00 の値）とします。時刻の列は用いません。
+  - 設定値スケジュール（-v）は計算時間間隔（GDAT RUN dTime）に合わせて、
+    CSV の間隔より短い場合は前後の値を直線補間し、長い場合は時間ステップ内の値を平均します。
+  - 切換スケジュール（-s）は時刻を含む区間の値の1文字目を切換モードとします。
+
+csv= を指定したスケジュールは、季節、曜日による1日のスケジュールの組み合わせを持ちません。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CSV の時系列によるスケジュール
+type SCHCSV struct {
+	file   string          // CSV ファイル名 (csv=)
+	column string          // 列名または列番号 (col=)
+	step   int             // CSV の間隔 [s]
+	dtm    int             // 計算時間間隔 [s]
+	val    []float64       // 設定値の時系列
+	mode   []ControlSWType // 切換モードの時系列
+}
+
+const schcsvDays = 365 // CSV の時系列の日数
+
+// parseSchcsv は SCHNM の `csv=`、`col=` を読み取ります。csv= がない場合は nil を返します。
+func parseSchcsv(name string, fields []string) *SCHCSV {
+	var c *SCHCSV
+	column := ""
+	for _, f := range fields {
+		if v, ok := strings.CutPrefix(f, "csv="); ok {
+			c = &SCHCSV{file: strings.TrimSuffix(v, ";")}
+		} else if v, ok := strings.CutPrefix(f, "col="); ok {
+			column = strings.TrimSuffix(v, ";")
+		}
+	}
+	if c == nil {
+		if column != "" {
+			panic(&InputError{Section: "SCHNM", Keyword: "col=", Component: name, Msg: "col= requires csv="})
+		}
+		return nil
+	}
+	if c.file == "" || column == "" {
+		panic(&InputError{Section: "SCHNM", Keyword: "csv=", Component: name, Msg: "csv= and col= are required"})
+	}
+	c.column = column
+	return c
+}
+
+// Schcsv は csv= を指定したスケジュールの CSV ファイルを読み込みます。
+// 計算時間間隔 Simc.DTm が決まった後（GDAT の読み取り後）に呼び出します。
+func (Simc *SIMCONTL) Schcsv(Schdl *SCHDL) {
+	files := map[string][][]string{}
+	load := func(S *SCH, sw bool) {
+		c := S.csv
+		if c == nil {
+			return
+		}
+		rows, ok := files[c.file]
+		if !ok {
+			b, err := Simc.readRef(c.file)
+			if err != nil {
+				panic(&InputError{Section: "SCHNM", Keyword: "csv=" + c.file, Component: S.name, Msg: err.Error()})
+			}
+			if rows, err = readCSVRows(b); err != nil {
+				panic(&InputError{Section: "SCHNM", Keyword: "csv=" + c.file, Component: S.name, Msg: err.Error()})
+			}
+			files[c.file] = rows
+		}
+		if err := c.load(rows, sw, Simc.DTm); err != nil {
+			panic(&InputError{Section: "SCHNM", Keyword: "csv=" + c.file, Component: S.name, Msg: err.Error()})
+		}
+	}
+	for i := range Schdl.Sch {
+		load(&Schdl.Sch[i], false)
+	}
+	for i := range Schdl.Scw {
+		load(&Schdl.Scw[i], true)
+	}
+}
+
+// readCSVRows は CSV の全ての行を返します。空行は除きます。
+func readCSVRows(b []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var rows [][]string
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		rows = append(rows, row)
+	}
+}
+
+// load は CSV の行 rows から列 c.column の時系列を取り出します。
+func (c *SCHCSV) load(rows [][]string, sw bool, dtm int) error {
+	if len(rows) == 0 {
+		return fmt.Errorf("no rows")
+	}
+
+	// 列の検索
+	col := -1
+	header := false
+	if n, err := strconv.Atoi(c.column); err == nil {
+		col = n - 1
+		if col < 0 {
+			return fmt.Errorf("invalid column %q", c.column)
+		}
+	}
+	for i, s := range rows[0] {
+		if strings.TrimSpace(s) == c.column {
+			col, header = i, true
+			break
+		}
+	}
+	if col < 0 {
+		return fmt.Errorf("column %q is not found", c.column)
+	}
+	if !header && col < len(rows[0]) {
+		if sw {
+			// 切換モードは数値でないため、行数で見出し行を判断する
+			header = len(rows)%schcsvDays != 0 && (len(rows)-1)%schcsvDays == 0
+		} else if _, err := strconv.ParseFloat(strings.TrimSpace(rows[0][col]), 64); err != nil {
+			header = true
+		}
+	}
+	if header {
+		rows = rows[1:]
+	}
+
+	// 行数から CSV の間隔を求める
+	n := len(rows)
+	if n == 0 || n%schcsvDays != 0 || 86400%(n/schcsvDays) != 0 {
+		return fmt.Errorf("%d rows: the number of rows must be %d days of hourly or sub-hourly values (e.g. 8760)", n, schcsvDays)
+	}
+	c.step = 86400 / (n / schcsvDays)
+	c.dtm = dtm
+
+	for i, row := range rows {
+		s := ""
+		if col < len(row) {
+			s = strings.TrimSpace(row[col])
+		}
+		if s == "" {
+			return fmt.Errorf("row %d: column %q is empty", i+1, c.column)
+		}
+		if sw {
+			c.mode = append(c.mode, ControlSWType(s[0]))
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("row %d: %q is not a number", i+1, s)
+		}
+		c.val = append(c.val, v)
+	}
+	return nil
+}
+
+// seconds は通日 day、時分 ttmm の 1/1 0:00 からの秒数を返します。
+func (c *SCHCSV) seconds(day, ttmm int) int {
+	return ((day-1)*24+ttmm/100)*3600 + ttmm%100*60
+}
+
+// value は通日 day、時分 ttmm で終わる時間ステップの設定値を返します。
+func (c *SCHCSV) value(day, ttmm int) float64 {
+	n := len(c.val)
+	at := func(k int) float64 { return c.val[((k%n)+n)%n] }
+	t := c.seconds(day, ttmm)
+
+	if c.dtm > c.step {
+		// 時間ステップ内の値の平均
+		k0 := int(math.Floor(float64(t-c.dtm) / float64(c.step)))
+		k1 := int(math.Floor(float64(t)/float64(c.step))) - 1
+		sum := 0.0
+		for k := k0; k <= k1; k++ {
+			sum += at(k)
+		}
+		return sum / float64(k1-k0+1)
+	}
+
+	// k 行目の値は (k+1)*step の時刻の値として直線補間する
+	x := float64(t)/float64(c.step) - 1
+	k := int(math.Floor(x))
+	f := x - float64(k)
+	return at(k)*(1-f) + at(k+1)*f
+}
+
+// switchMode は通日 day、時分 ttmm を含む区間の切換モードを返します。
+func (c *SCHCSV) switchMode(day, ttmm int) ControlSWType {
+	n := len(c.mode)
+	k := (c.seconds(day, ttmm)+c.step-1)/c.step - 1
+	return c.mode[((k%n)+n)%n]
+}
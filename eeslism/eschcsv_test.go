@@ -0,0 +1,210 @@
+package eeslism
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSCHCSVValue は計算時間間隔への補間と平均、切換モードの区間を確認する
+func TestSCHCSVValue(t *testing.T) {
+	hourly := &SCHCSV{step: 3600, val: make([]float64, 8760)}
+	for i := range hourly.val {
+		hourly.val[i] = float64(i)
+	}
+	quarter := &SCHCSV{step: 900, dtm: 3600, val: make([]float64, 35040)}
+	for i := range quarter.val {
+		quarter.val[i] = float64(i)
+	}
+	tests := []struct {
+		c         *SCHCSV
+		dtm       int
+		day, ttmm int
+		want      float64
+	}{
+		{hourly, 3600, 1, 100, 0},    // 1行目は 1:00 の値
+		{hourly, 3600, 2, 2400, 47},  // 2日目の 24:00
+		{hourly, 900, 1, 115, 0.25},  // 1:00 と 2:00 の間の直線補間
+		{hourly, 900, 1, 30, 4379.5}, // 0:00 は前年の 12/31 24:00（最終行）
+		{quarter, 3600, 1, 100, 1.5}, // 0:15〜1:00 の4行の平均
+		{quarter, 3600, 365, 2400, 35037.5},
+	}
+	for _, tt := range tests {
+		tt.c.dtm = tt.dtm
+		if got := tt.c.value(tt.day, tt.ttmm); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("step %d dtm %d day %d %04d: got %g, want %g", tt.c.step, tt.dtm, tt.day, tt.ttmm, got, tt.want)
+		}
+	}
+
+	sw := &SCHCSV{step: 3600, mode: make([]ControlSWType, 8760)}
+	for i := range sw.mode {
+		sw.mode[i] = OFF_SW
+	}
+	sw.mode[8] = ON_SW // 8:00〜9:00
+	for ttmm, want := range map[int]ControlSWType{800: OFF_SW, 815: ON_SW, 900: ON_SW, 915: OFF_SW} {
+		if got := sw.switchMode(1, ttmm); got != want {
+			t.Errorf("%04d: mode %c, want %c", ttmm, got, want)
+		}
+	}
+}
+
+// TestSCHCSVLoad は見出し行、列の指定と行数の誤りを確認する
+func TestSCHCSVLoad(t *testing.T) {
+	rows := func(header []string, n int, cell func(i int) []string) [][]string {
+		var r [][]string
+		if header != nil {
+			r = append(r, header)
+		}
+		for i := 0; i < n; i++ {
+			r = append(r, cell(i))
+		}
+		return r
+	}
+	hourly := func(i int) []string { return []string{fmt.Sprint(i + 1), fmt.Sprint(i % 24), "-"} }
+
+	tests := []struct {
+		rows    [][]string
+		column  string
+		sw      bool
+		want    string // エラー
+		first   float64
+		nvalues int
+	}{
+		{rows([]string{"hour", "occ", "mode"}, 8760, hourly), "occ", false, "", 0, 8760},
+		{rows([]string{"hour", "occ", "mode"}, 8760, hourly), "2", false, "", 0, 8760},
+		{rows(nil, 8760, hourly), "2", false, "", 0, 8760},
+		{rows([]string{"hour", "occ", "mode"}, 8760, hourly), "mode", true, "", 0, 8760},
+		{rows([]string{"hour", "occ", "mode"}, 8760, hourly), "3", true, "", 0, 8760},
+		{rows(nil, 35040, hourly), "2", false, "", 0, 35040},
+		{rows([]string{"hour", "occ"}, 8760, hourly), "people", false, `column "people" is not found`, 0, 0},
+		{rows([]string{"hour", "occ"}, 8784, hourly), "occ", false, "8784 rows", 0, 0},
+		{rows([]string{"hour", "occ", "mode"}, 8760, hourly), "mode", false, `row 1: "-" is not a number`, 0, 0},
+	}
+	for i, tt := range tests {
+		c := &SCHCSV{column: tt.column}
+		err := c.load(tt.rows, tt.sw, 3600)
+		if tt.want != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("%d: error %v, want %q", i, err, tt.want)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+		if tt.sw {
+			if len(c.mode) != tt.nvalues || c.mode[0] != ON_SW {
+				t.Errorf("%d: %d modes, first %c", i, len(c.mode), c.mode[0])
+			}
+		} else if len(c.val) != tt.nvalues || c.val[0] != tt.first || c.step != 86400*365/tt.nvalues {
+			t.Errorf("%d: %d values, first %g, step %d", i, len(c.val), c.val[0], c.step)
+		}
+	}
+}
+
+// TestSCHCSVSimulation は SCHNM の csv= のスケジュールを名前で参照して計算できることを確認する
+func TestSCHCSVSimulation(t *testing.T) {
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("hour,occ,heating\n")
+	for i := 0; i < 8760; i++ {
+		mode := OFF_SW
+		if h := (i + 1) % 24; 7 <= h && h <= 18 {
+			mode = ON_SW
+		}
+		fmt.Fprintf(&b, "%d,%d,%c\n", i+1, i, mode)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "metered.csv"), []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := scheduleTestModel()
+	m.Schedules = []*Schedule{
+		{Name: "Occ", CSV: "metered.csv", Column: "occ"},
+		{Name: "HeatCSV", Switch: true, CSV: "metered.csv", Column: "heating"},
+	}
+	m.Controls[0].Set = []Setting{{"HeatPath", "HeatCSV"}}
+	sim, err := m.Simulation(filepath.Join(dir, "room.txt"), "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Output = new(MemorySink)
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	occ, heat := -1, -1
+	for i, s := range sim.Schdl.Sch {
+		if s.name == "Occ" {
+			occ = i
+		}
+	}
+	for i, s := range sim.Schdl.Scw {
+		if s.name == "HeatCSV" {
+			heat = i
+		}
+	}
+	if occ < 0 || heat < 0 {
+		t.Fatalf("schedules not found: Occ %d, HeatCSV %d", occ, heat)
+	}
+
+	for !sim.Done() {
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+		// Step の後の日付は 24:00 の計算で翌日に進んでいるため、24:00 は確認しない
+		d := sim.Daytm
+		if d.Mon != 1 || d.Day != 3 || d.Ttmm == 2400 {
+			continue
+		}
+		if want := float64(48 + d.Ttmm/100 - 1); sim.Schdl.Val[occ] != want {
+			t.Errorf("1/3 %04d: Occ %g, want %g", d.Ttmm, sim.Schdl.Val[occ], want)
+		}
+		want := OFF_SW
+		if h := d.Ttmm / 100; 7 <= h && h <= 18 {
+			want = ON_SW
+		}
+		if sim.Schdl.Isw[heat] != want {
+			t.Errorf("1/3 %04d: HeatCSV %c, want %c", d.Ttmm, sim.Schdl.Isw[heat], want)
+		}
+	}
+	if err := sim.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSCHCSVErrors は CSV ファイルがない場合と col= がない場合の誤りを確認する
+func TestSCHCSVErrors(t *testing.T) {
+	for _, s := range []*Schedule{
+		{Name: "Occ", CSV: "missing.csv", Column: "occ"},
+		{Name: "Occ", CSV: "metered.csv"},
+	} {
+		m := scheduleTestModel()
+		m.Schedules = []*Schedule{s}
+		if _, err := m.Simulation(filepath.Join(t.TempDir(), "room.txt"), "../Base"); err == nil {
+			if s.Column == "" {
+				t.Errorf("%+v: Simulation should fail", s)
+			}
+			continue
+		}
+		if s.Column != "" {
+			t.Errorf("%+v: Simulation should not fail before Init", s)
+		}
+	}
+
+	m := scheduleTestModel()
+	m.Schedules = []*Schedule{{Name: "Occ", CSV: "missing.csv", Column: "occ"}}
+	sim, err := m.Simulation(filepath.Join(t.TempDir(), "room.txt"), "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Output = new(MemorySink)
+	err = sim.Init()
+	if err == nil || !strings.Contains(err.Error(), "csv=missing.csv") {
+		t.Errorf("Init error %v, want csv=missing.csv", err)
+	}
+}
@@ -18,6 +18,7 @@
 package eeslism
 
 import (
+	"io"
 	"bufio"
 	"fmt"
 	"regexp"
@@ -27,7 +28,7 @@ import (
 
 /* 曜日の設定  */
 
-func Dayweek(fi string, week string, daywk []int, key int) {
+func Dayweek(fi string, week string, daywk []int, key int, Ferr io.Writer) {
 	var s string
 	var d, id, M, D int
 
@@ -58,7 +59,7 @@ func Dayweek(fi string, week string, daywk []int, key int) {
 		}
 	}
 	if id == 8 {
-		Eprint("<Dayweek>", s)
+		Eprint("<Dayweek>", s, Ferr)
 	}
 
 	// 開始日と終了日
@@ -246,7 +247,6 @@ func Schdata(schnma string, dsn string, daywk []int, Schdl *SCHDL) {
 	fi := strings.NewReader(schnma)
 
 	var err error
-	const dmax = 366
 
 	Seasn := Schdl.Seasn
 	Wkdy := Schdl.Wkdy
@@ -277,8 +277,11 @@ func Schdata(schnma string, dsn string, daywk []int, Schdl *SCHDL) {
 		S := SCH{
 			name: fields[1], // 設定値名 or 切替設定名
 		}
-		for d := range S.day {
-			S.day[d] = -1
+
+		// CSV の時系列によるスケジュール
+		S.csv = parseSchcsv(S.name, fields[2:])
+		if S.csv != nil {
+			fields = fields[:2]
 		}
 
 		// ';' まで繰り返す
@@ -351,58 +354,17 @@ func Schdata(schnma string, dsn string, daywk []int, Schdl *SCHDL) {
 				}
 			}
 
-			// ループ回数
-			var N int
-			if is >= 0 {
-				// ** 季節設定がある場合 **
-				N = Seasn[is].N
-			} else {
-				// ** 季節設定がない場合 **
-				N = 1
-			}
-
-			// 年間スケジュールの作成ループ
-			for k := 0; k < N; k++ {
-				var ds, de int
-				if is >= 0 {
-					// ** 季節設定がある場合 **
-					// ex) `TrsetC:Winter`
-					// ex) `ACSWLDwd:Winter-Weekday`
-					Sn := Seasn[is]
-					ds = Sn.sday[k] //開始日
-					de = Sn.eday[k] //終了日
-
-					if ds > de {
-						de += 365 // 年末跨ぎ
-					}
-				} else {
-					// ** 季節設定がない場合場合 **
-					// ex) `TrsetC`
-					// ex) `ACSWLDwd:-Weekday`
-					ds = 1    // 開始日 NOTE: 配列インデックス1-366を想定 (Fortran譲りか)
-					de = dmax // 終了日
-				}
-
-				for day := ds; day <= de; day++ {
-					d := day
-					if day > 365 {
-						d = day - 365 // NOTE: d=1に戻る条件になっている
-					}
-
-					// 曜日指定が無い or 指定曜日であることを確認
-					if wkday == nil || wkday.wday[daywk[d]] {
-						if dmod == 'v' {
-							S.day[d] = sc
-						} else if dmod == 'w' {
-							S.day[d] = sw
-						} else {
-							panic(dmod)
-						}
-					}
-				}
+			// 季節、曜日による指定の追加
+			def := schdef{is: is, wkday: wkday, sc: sc}
+			if dmod == 'w' {
+				def.sc = sw
 			}
+			S.defs = append(S.defs, def)
 		}
 
+		// 年間スケジュールの作成
+		S.setDays(Seasn, daywk)
+
 		// 年間スケジュールに追加
 		if dmod == 'v' {
 			Schdl.Sch = append(Schdl.Sch, S)
@@ -418,6 +380,77 @@ func Schdata(schnma string, dsn string, daywk []int, Schdl *SCHDL) {
 	Schdl.Isw = make([]ControlSWType, len(Schdl.Scw))
 }
 
+// setDays は季節、曜日による指定 defs と曜日 daywk から、通日ごとの一日のスケジュール day を作成します。
+func (S *SCH) setDays(Seasn []SEASN, daywk []int) {
+	const dmax = 366
+
+	for d := range S.day {
+		S.day[d] = -1
+	}
+
+	for _, def := range S.defs {
+		// ループ回数
+		var N int
+		if def.is >= 0 {
+			// ** 季節設定がある場合 **
+			N = Seasn[def.is].N
+		} else {
+			// ** 季節設定がない場合 **
+			N = 1
+		}
+
+		// 年間スケジュールの作成ループ
+		for k := 0; k < N; k++ {
+			var ds, de int
+			if def.is >= 0 {
+				// ** 季節設定がある場合 **
+				// ex) `TrsetC:Winter`
+				// ex) `ACSWLDwd:Winter-Weekday`
+				Sn := Seasn[def.is]
+				ds = Sn.sday[k] //開始日
+				de = Sn.eday[k] //終了日
+
+				if ds > de {
+					de += 365 // 年末跨ぎ
+				}
+			} else {
+				// ** 季節設定がない場合場合 **
+				// ex) `TrsetC`
+				// ex) `ACSWLDwd:-Weekday`
+				ds = 1    // 開始日 NOTE: 配列インデックス1-366を想定 (Fortran譲りか)
+				de = dmax // 終了日
+			}
+
+			for day := ds; day <= de; day++ {
+				d := day
+				if day > 365 {
+					d = day - 365 // NOTE: d=1に戻る条件になっている
+				}
+
+				// 曜日指定が無い or 指定曜日であることを確認
+				if def.wkday == nil || def.wkday.wday[daywk[d]] {
+					S.day[d] = def.sc
+				}
+			}
+		}
+	}
+}
+
+// setDaywk は曜日 daywk により、季節、曜日で指定した年間スケジュール（SCHNM）を作り直します。
+// 実暦による計算で年ごとに曜日が変わる場合に用います。
+func (Schdl *SCHDL) setDaywk(daywk []int) {
+	for i := range Schdl.Sch {
+		if Schdl.Sch[i].defs != nil {
+			Schdl.Sch[i].setDays(Schdl.Seasn, daywk)
+		}
+	}
+	for i := range Schdl.Scw {
+		if Schdl.Scw[i].defs != nil {
+			Schdl.Scw[i].setDays(Schdl.Seasn, daywk)
+		}
+	}
+}
+
 /* ------------------------------------------------------------ */
 
 /*  季節、曜日によるスケジュ－ル表の組み合わせ名へのスケジュ－ル名の追加  */
@@ -17,21 +17,33 @@
 
 package eeslism
 
+import (
+	"io"
+)
+
 /* -------------------------------------------------------------------------- */
 
-func Eeschdlr(day, ttmm int, Schdl *SCHDL, Rmvls *RMVLS) {
+func Eeschdlr(day, ttmm int, Schdl *SCHDL, Rmvls *RMVLS, debug bool, Ferr io.Writer) {
 	//r := Rmvls.Room
 
 	for j := range Schdl.Sch {
-		Schdl.Val[j] = schval(day, ttmm, &Schdl.Sch[j], Schdl.Dsch)
+		if c := Schdl.Sch[j].csv; c != nil {
+			Schdl.Val[j] = c.value(day, ttmm)
+		} else {
+			Schdl.Val[j] = schval(day, ttmm, &Schdl.Sch[j], Schdl.Dsch)
+		}
 	}
 
 	for j := range Schdl.Scw {
-		Schdl.Isw[j] = scwmode(day, ttmm, &Schdl.Scw[j], Schdl.Dscw)
+		if c := Schdl.Scw[j].csv; c != nil {
+			Schdl.Isw[j] = c.switchMode(day, ttmm)
+		} else {
+			Schdl.Isw[j] = scwmode(day, ttmm, &Schdl.Scw[j], Schdl.Dscw)
+		}
 	}
 
 	if SIMUL_BUILDG {
-		if DEBUG {
+		if debug {
 			xprschval(Schdl.Val, Schdl.Isw)
 		}
 
@@ -39,9 +51,9 @@ func Eeschdlr(day, ttmm int, Schdl *SCHDL, Rmvls *RMVLS) {
 		Vtschdlr(Rmvls.Room)
 		Aichschdlr(Schdl.Val, Rmvls.Room)
 
-		if DEBUG {
+		if debug {
 			xprqin(Rmvls.Room)
-			xprvent(Rmvls.Room)
+			xprvent(Rmvls.Room, debug, Ferr)
 		}
 	}
 }
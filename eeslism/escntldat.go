@@ -18,6 +18,7 @@
 package eeslism
 
 import (
+	"io"
 	"errors"
 	"fmt"
 	"strconv"
@@ -56,7 +57,7 @@ func getConditionString(fi *EeTokens) string {
 func Contrldata(fi *EeTokens, Ct *[]*CONTL, Ci *[]*CTLIF,
 	Cs *[]*CTLST,
 	Simc *SIMCONTL, Compnt []*COMPNT,
-	Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL) {
+	Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL, Ferr io.Writer) {
 	//loadcmp, cmp := (*COMPNT)(nil), (*COMPNT)(nil)
 	// varcontl, Contl, ctl := (*CONTL)(nil), (*CONTL)(nil), (*CONTL)(nil)
 	// ctlif, Ctlif, cti := (*CTLIF)(nil), (*CTLIF)(nil), (*CTLIF)(nil)
@@ -99,7 +100,7 @@ func Contrldata(fi *EeTokens, Ct *[]*CONTL, Ci *[]*CTLIF,
 				Contl.Type = 'c'
 				Contl.Cif = Ctlif
 				condStr := getConditionString(fi)
-				ctifdecode(condStr, Ctlif, Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+				ctifdecode(condStr, Ctlif, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 				*Ci = append(*Ci, Ctlif)
 			} else if s == "AND" {
 				Ctlif := NewCTLIF()
@@ -110,14 +111,14 @@ func Contrldata(fi *EeTokens, Ct *[]*CONTL, Ci *[]*CTLIF,
 					Contl.AndAndCif = Ctlif
 				}
 				condStr := getConditionString(fi)
-				ctifdecode(condStr, Ctlif, Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+				ctifdecode(condStr, Ctlif, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 				*Ci = append(*Ci, Ctlif)
 			} else if s == "OR" {
 				Ctlif := NewCTLIF()
 				Contl.Type = 'c'
 				Contl.OrCif = Ctlif
 				condStr := getConditionString(fi)
-				ctifdecode(condStr, Ctlif, Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+				ctifdecode(condStr, Ctlif, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 				*Ci = append(*Ci, Ctlif)
 			} else if strings.HasPrefix(s, "LOAD") {
 				loadcmp = nil
@@ -131,11 +132,11 @@ func Contrldata(fi *EeTokens, Ct *[]*CONTL, Ci *[]*CTLIF,
 						*load = Cload
 					} else {
 						var iderr error
-						i, iderr = idscw(s[5:], Schdl.Scw, "")
+						i, iderr = idscw(s[5:], Schdl.Scw, "", Ferr)
 						if iderr == nil {
 							load = &Schdl.Isw[i]
 						} else {
-							Eprint("<Contrldata>", s)
+							Eprint("<Contrldata>", s, Ferr)
 						}
 					}
 				} else {
@@ -152,37 +153,40 @@ func Contrldata(fi *EeTokens, Ct *[]*CONTL, Ci *[]*CTLIF,
 				ss := strings.SplitN(s, "=", 2)
 				key, value := ss[0], ss[1]
 				var err error
+				var ldname string
 				if load != nil {
-					vptr, err = loadptr(loadcmp, load, key, Compnt)
+					vptr, err = loadptr(loadcmp, load, key, Compnt, Ferr)
 					load = nil
+					ldname = key
 				} else {
-					vptr, vpath, err = ctlvptr(key, Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+					vptr, vpath, err = ctlvptr(key, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 				}
 				if err == nil {
 					Ctlst := Contl.Cst
 					Ctlst.Type = vptr.Type
 					Ctlst.PathType = vpath.Type
 					Ctlst.Path = vpath.Ptr
+					Ctlst.Ldname = ldname
 					if Ctlst.Type == VAL_CTYPE {
 						Ctlst.Lft.V = vptr.Ptr.(*float64)
 					} else {
 						Ctlst.Lft.S = vptr.Ptr.(*ControlSWType)
 					}
-					err = ctlrgtptr(value, &Ctlst.Rgt, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ctlst.Type)
+					err = ctlrgtptr(value, &Ctlst.Rgt, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ctlst.Type, Ferr)
 				}
 
 				if err != nil {
 					Err := fmt.Sprintf("%s = %s", s[:st], s[st+1:])
-					Eprint("<Contrldata>", Err)
+					Eprint("<Contrldata>", Err, Ferr)
 					// エラーがあった場合、C版と同じくCstをnilにする
 					// これにより、Contlschdlrでif Contl.Cst != nilチェックを通過しない
 					Contl.Cst = nil
 				}
 			} else if s == "TVALV" {
 				flag_ignore = true
-				ValvControl(fi, Compnt, Schdl, Simc, Wd, &vptr)
+				ValvControl(fi, Compnt, Schdl, Simc, Wd, &vptr, Ferr)
 			} else {
-				Eprint("<Contrldata>", s)
+				Eprint("<Contrldata>", s, Ferr)
 			}
 
 			s = fi.GetToken()
@@ -235,7 +239,7 @@ func ContrlCount(fi *EeTokens) (Nif, N int) {
 /*  制御条件式 (lft1 - lft2 ? rgt ) に関するポインター */
 
 func ctifdecode(_s string, ctlif *CTLIF, Simc *SIMCONTL, Compnt []*COMPNT,
-	Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL) {
+	Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL, Ferr io.Writer) {
 	var lft, op, rgt string // 左変数, 演算子, 右変数
 	var err int
 	var vptr VPTR
@@ -253,7 +257,7 @@ func ctifdecode(_s string, ctlif *CTLIF, Simc *SIMCONTL, Compnt []*COMPNT,
 	}
 
 	// 演算対象の変数 その1を設定
-	vptr, _, _ = ctlvptr(lft, Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+	vptr, _, _ = ctlvptr(lft, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 
 	ctlif.Type = vptr.Type // 演算の種類を設定
 	ctlif.Nlft = 1
@@ -265,13 +269,13 @@ func ctifdecode(_s string, ctlif *CTLIF, Simc *SIMCONTL, Compnt []*COMPNT,
 
 	// 演算対象の変数 その2を設定
 	if st != -1 {
-		vptr, _, _ = ctlvptr(lft[st:], Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+		vptr, _, _ = ctlvptr(lft[st:], Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 
 		if vptr.Type == VAL_CTYPE && ctlif.Type == vptr.Type {
 			ctlif.Nlft = 2
 			ctlif.Lft2.V = vptr.Ptr.(*float64)
 		} else {
-			Eprint("<ctifdecode>", lft[st+1:])
+			Eprint("<ctifdecode>", lft[st+1:], Ferr)
 		}
 	}
 
@@ -293,16 +297,16 @@ func ctifdecode(_s string, ctlif *CTLIF, Simc *SIMCONTL, Compnt []*COMPNT,
 		err = 1
 	}
 
-	Errprint(err, "<ctifdecode>", _s)
+	Errprint(err, "<ctifdecode>", _s, Ferr)
 
-	ctlrgtptr(rgt, &ctlif.Rgt, Simc, Compnt, Mpath, Wd, Exsf, Schdl, ctlif.Type)
+	ctlrgtptr(rgt, &ctlif.Rgt, Simc, Compnt, Mpath, Wd, Exsf, Schdl, ctlif.Type, Ferr)
 }
 
 /* ------------------------------------------------------ */
 
 /*  条件式、設定式の右辺（定数、またはスケジュール設定値のポインター） */
 
-func ctlrgtptr(s string, rgt *CTLTYP, Simc *SIMCONTL, Compnt []*COMPNT, Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL, _type VPtrType) error {
+func ctlrgtptr(s string, rgt *CTLTYP, Simc *SIMCONTL, Compnt []*COMPNT, Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL, _type VPtrType, Ferr io.Writer) error {
 	var vptr VPTR
 	var err error
 
@@ -331,7 +335,7 @@ func ctlrgtptr(s string, rgt *CTLTYP, Simc *SIMCONTL, Compnt []*COMPNT, Mpath []
 				rgt.S = new(ControlSWType)
 				*rgt.S = ControlSWType(s[1])
 			} else {
-				vptr, _, err = ctlvptr(s, Simc, Compnt, Mpath, Wd, Exsf, Schdl)
+				vptr, _, err = ctlvptr(s, Simc, Compnt, Mpath, Wd, Exsf, Schdl, Ferr)
 				if _type == vptr.Type {
 					if _type == VAL_CTYPE {
 						rgt.V = vptr.Ptr.(*float64)
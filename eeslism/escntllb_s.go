@@ -18,23 +18,24 @@
 package eeslism
 
 import (
+	"io"
 	"errors"
 	"strings"
 )
 
 /*  システム変数名、内部変数名、スケジュール名のポインター  */
 
-func ctlvptr(s string, Simc *SIMCONTL, Compnt []*COMPNT, Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL) (VPTR, VPTR, error) {
+func ctlvptr(s string, Simc *SIMCONTL, Compnt []*COMPNT, Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL, Ferr io.Writer) (VPTR, VPTR, error) {
 	var err error
 	var vptr, vpath VPTR
 
-	if i, err2 := idsch(s, Schdl.Sch, ""); err2 == nil {
+	if i, err2 := idsch(s, Schdl.Sch, "", Ferr); err2 == nil {
 		// 年間の設定値スケジュールへのポインターを作成する
 		vptr = VPTR{
 			Ptr:  &Schdl.Val[i],
 			Type: VAL_CTYPE,
 		}
-	} else if i, iderr := idscw(s, Schdl.Scw, ""); iderr == nil {
+	} else if i, iderr := idscw(s, Schdl.Scw, "", Ferr); iderr == nil {
 		// 年間の切替スケジュールへのポインターを作成する
 		vptr = VPTR{
 			Ptr:  &Schdl.Isw[i],
@@ -42,7 +43,7 @@ func ctlvptr(s string, Simc *SIMCONTL, Compnt []*COMPNT, Mpath []*MPATH, Wd *WDA
 		}
 	} else {
 		// 経路名、システム変数名、内部変数名のポインターを作成する
-		vptr, vpath, err = kynameptr(s, Simc, Compnt, Mpath, Wd, Exsf)
+		vptr, vpath, err = kynameptr(s, Simc, Compnt, Mpath, Wd, Exsf, Ferr)
 	}
 
 	//Errprint(1, "<ctlvptr>", s)
@@ -65,7 +66,7 @@ func strkey(s string) ([]string, int) {
 
 // 経路名、システム変数名、内部変数名のポインターを作成する
 func kynameptr(s string, Simc *SIMCONTL, _Compnt []*COMPNT,
-	Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS) (VPTR, VPTR, error) {
+	Mpath []*MPATH, Wd *WDAT, Exsf *EXSFS, Ferr io.Writer) (VPTR, VPTR, error) {
 	var err error
 	var vptr, vpath VPTR
 
@@ -185,7 +186,7 @@ func kynameptr(s string, Simc *SIMCONTL, _Compnt []*COMPNT,
 							case VALV_TYPE, TVALV_TYPE:
 								vptr, err = valv_vptr(key, Compnt.Eqp.(*VALV))
 							default:
-								Eprint("CONTL", Compnt.Name)
+								Eprint("CONTL", Compnt.Name, Ferr)
 							}
 						}
 						break
@@ -198,7 +199,7 @@ func kynameptr(s string, Simc *SIMCONTL, _Compnt []*COMPNT,
 	}
 
 	if err != nil {
-		Eprint("<kynameptr>", s)
+		Eprint("<kynameptr>", s, Ferr)
 	}
 
 	return vptr, vpath, err
@@ -328,7 +329,7 @@ func compntvptr(nk int, key []string, Compnt *COMPNT) (VPTR, error) {
 // 負荷計算を行うシステム要素の設定システム変数のポインターを作成します。
 // 負荷計算を行うシステム要素の設定システム変数のポインターを作成し、 vtr に保存します。
 // 内部では、 boildptr, refaldptr, hcldptr, pipeldsptr, rdpnlldsptr,roomldptr に処理を委譲します。
-func loadptr(loadcmp *COMPNT, load *ControlSWType, s string, _Compnt []*COMPNT) (VPTR, error) {
+func loadptr(loadcmp *COMPNT, load *ControlSWType, s string, _Compnt []*COMPNT, Ferr io.Writer) (VPTR, error) {
 	var Room *ROOM
 	var key []string
 	var idmrk byte = ' '
@@ -372,7 +373,7 @@ func loadptr(loadcmp *COMPNT, load *ControlSWType, s string, _Compnt []*COMPNT)
 						if Room.rmld == nil {
 							Room.rmld = new(RMLOAD)
 							if Room.rmld == nil {
-								Ercalloc(1, "roomldptr")
+								Ercalloc(1, "roomldptr", Ferr)
 							}
 
 							key = strings.Split(s, "_")
@@ -21,6 +21,7 @@ import (
 	"io"
 )
 
+// Contlschdlr は経路、システム要素の制御を初期化し、CONTLで指定された制御を設定します。
 func Contlschdlr(_Contl []*CONTL, Mpath []*MPATH, _Compnt []*COMPNT) {
 
 	// 全ての経路、機器を停止で初期化
@@ -101,7 +102,11 @@ func Contlschdlr(_Contl []*CONTL, Mpath []*MPATH, _Compnt []*COMPNT) {
 			}
 		}
 	}
+}
 
+// Ldschdlr は Contlschdlr で設定された負荷計算の設定値と経路の発停から、
+// システム要素の制御とバッチ運転の経路の流量を設定します。
+func Ldschdlr(Mpath []*MPATH, _Compnt []*COMPNT, DTM float64) {
 	for i := range _Compnt {
 		Compnt := _Compnt[i]
 
@@ -156,7 +161,7 @@ func Contlschdlr(_Contl []*CONTL, Mpath []*MPATH, _Compnt []*COMPNT) {
 	for _, Mp := range Mpath {
 		for _, Pli := range Mp.Plist {
 			if Pli.Batch {
-				Pli.lpathschbat()
+				Pli.lpathschbat(DTM)
 			}
 		}
 	}
@@ -260,7 +265,7 @@ func lpathschd(control ControlSWType, pelm []*PELM) {
 
 /* 蓄熱槽のバッチ給水、排水時の設定  */
 
-func (Plist *PLIST) lpathschbat() {
+func (Plist *PLIST) lpathschbat(DTM float64) {
 	var j, k, i, jt, ifl int
 	var batop ControlSWType
 	var Tsout, Gbat float64
@@ -343,7 +348,7 @@ func (Plist *PLIST) lpathschbat() {
 
 /* --------------------------------------------------- */
 
-func contlxprint(Ncontl int, C *CONTL, out io.Writer) {
+func contlxprint(Ncontl int, C *CONTL, out io.Writer, debug bool, Ferr io.Writer) {
 	var i int
 	var cif *CTLIF
 	var cst *CTLST
@@ -351,7 +356,7 @@ func contlxprint(Ncontl int, C *CONTL, out io.Writer) {
 	var Contl *CONTL
 
 	Contl = C
-	if DEBUG {
+	if debug {
 		fmt.Fprintln(out, "contlxprint --- Contlschdlr")
 
 		for i = 0; i < Ncontl; i++ {
@@ -18,6 +18,7 @@
 package eeslism
 
 import (
+	"io"
 	"errors"
 	"fmt"
 )
@@ -92,7 +93,7 @@ func iddsw(code string, Dscw []DSCW) (int, error) {
 //
 // スケジュールcodeを Sch から検索し、インデックス番号を返す
 // ただし、検索しても見つからない場合は -1 を返す
-func idsch(code string, Sch []SCH, err string) (int, error) {
+func idsch(code string, Sch []SCH, err string, Ferr io.Writer) (int, error) {
 	N := len(Sch)
 
 	if N != len(Sch) {
@@ -107,7 +108,7 @@ func idsch(code string, Sch []SCH, err string) (int, error) {
 	}
 
 	if err != "" {
-		Eprint("<idsch>", err)
+		Eprint("<idsch>", err, Ferr)
 	}
 	return -1, errors.New("Schedule Not Found")
 }
@@ -116,7 +117,7 @@ func idsch(code string, Sch []SCH, err string) (int, error) {
 
 // スケジュールcodeを Scw から検索し、インデックス番号を返す
 // ただし、検索しても見つからない場合は -1 を返す
-func idscw(code string, Scw []SCH, err string) (int, error) {
+func idscw(code string, Scw []SCH, err string, Ferr io.Writer) (int, error) {
 	N := len(Scw)
 
 	if N != len(Scw) {
@@ -131,7 +132,7 @@ func idscw(code string, Scw []SCH, err string) (int, error) {
 	}
 
 	if err != "" {
-		Eprint("<idscw>", err)
+		Eprint("<idscw>", err, Ferr)
 	}
 	return -1, errors.New("Schedule Not Found")
 }
@@ -140,7 +141,7 @@ func idscw(code string, Scw []SCH, err string) (int, error) {
 
 // 室名 `code` に一致する部屋を 部屋の一覧 `Room` から検索し、その番号を返す
 // ただし、検索しても見つからない場合はエラーを返す
-func idroom(code string, rooms []*ROOM, err string) (int, error) {
+func idroom(code string, rooms []*ROOM, err string, Ferr io.Writer) (int, error) {
 	for j := range rooms {
 		_Room := rooms[j]
 		if code == _Room.Name {
@@ -149,7 +150,7 @@ func idroom(code string, rooms []*ROOM, err string) (int, error) {
 	}
 
 	E := fmt.Sprintf("Room=%s %s", code, err)
-	Eprint("<idroom>", E)
+	Eprint("<idroom>", E, Ferr)
 
 	return -1, errors.New("Room Not Found")
 }
@@ -0,0 +1,242 @@
+/*
+estable.go (Output Table Reader)
+
+計算結果のファイルのうち、`-tmid` と `#` の見出しを持つ表形式のファイル
+（室の時刻別 `_rm`、機器の時刻別 `_sc`、日集計 `_dr`・`_dc`、月集計 `_mr`・`_mc`、月・時刻別 `_mt` など）を
+列ごとの値として読み込みます。
+
+ファイルの構成は次のとおりです（ttlprint、ttldyprint、ttlmtprint が出力するヘッダー）。
+
+	_rm#
+	-t タイトル ;
+	-w 気象データファイル名
+	-tid h             （h: 時刻別、d: 日別、M: 月別）
+	-tmid MDT          （各行の時刻の項目。Y: 年、M: 月、D: 日、T: 時刻。Y は実暦による計算の場合）
+	-dtm 3600
+	-Ntime 168
+	-cat
+	ROOM 1
+	 TestRoom 5 4 4 0 0 0   （要素名、パラメータ数、データ数、...）
+	*
+	#
+	TestRoom_Tr t f ...     （データ数の組の 変数名 単位 書式）
+	01 01  1.00             （時刻）
+	14.94 ...               （データ）
+	-999
+*/
+package eeslism
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// OutputTable は計算結果のファイルの表です。
+type OutputTable struct {
+	Title   string // -t タイトル
+	Weather string // -w 気象データファイル名
+	Tid     string // -tid データの時間間隔（h: 時刻別、d: 日別、M: 月別）
+	Timeid  string // -tmid 各行の時刻の項目（Y: 年、M: 月、D: 日、T: 時刻）
+	Dtm     int    // -dtm 計算時間間隔 [s]
+	Ntime   int    // -Ntime データの行数
+
+	// 各行の時刻。Timeid に含まれない項目は nil
+	Year []int
+	Mon  []int
+	Day  []int
+	Time []float64 // 時刻 [h]。日別の最大・最小の発生時刻などは列のデータとして読み込む
+
+	Columns []OutputColumn
+}
+
+// OutputColumn は計算結果の表の1つの列です。
+type OutputColumn struct {
+	Name   string    // 変数名（例: `Room_Tr`）
+	Unit   string    // 単位の記号（例: `t` 温度、`q` 熱量、`H` 時間数）
+	Format string    // 書式（f: 実数、d: 整数、c: 文字）
+	Values []float64 // 数値の列。書式が c の場合は NaN
+	Text   []string  // 書式が c の列の文字（例: 機器の運転状態 `F`、`x`）
+}
+
+// Column は変数 name の列を返します。見つからない場合は nil を返します。
+func (t *OutputTable) Column(name string) *OutputColumn {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// ReadOutputTable は計算結果のファイルを読み込みます。
+// `-tmid` または `#` の見出しを持たないファイル（`_wd`、`_sfq` など）はエラーとなります。
+func ReadOutputTable(r io.Reader) (*OutputTable, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	sc.Split(bufio.ScanWords)
+	next := func() (string, bool) {
+		if sc.Scan() {
+			return sc.Text(), true
+		}
+		return "", false
+	}
+	nextInt := func(what string) (int, error) {
+		s, _ := next()
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("eeslism: output table: invalid %s %q", what, s)
+		}
+		return n, nil
+	}
+
+	flid, ok := next()
+	if !ok || !strings.HasSuffix(flid, "#") || len(flid) == 1 {
+		return nil, errors.New("eeslism: output table: not an output table file")
+	}
+
+	// ヘッダー
+	t := new(OutputTable)
+	ndata := 0
+header:
+	for {
+		s, ok := next()
+		if !ok {
+			return nil, errors.New("eeslism: output table: unexpected end of header")
+		}
+		var err error
+		switch s {
+		case "#":
+			break header
+		case "-t":
+			var words []string
+			for {
+				w, ok := next()
+				if !ok || w == ";" {
+					break
+				}
+				if strings.HasSuffix(w, ";") {
+					words = append(words, strings.TrimSuffix(w, ";"))
+					break
+				}
+				words = append(words, w)
+			}
+			t.Title = strings.Join(words, " ")
+		case "-w":
+			t.Weather, _ = next()
+		case "-tid":
+			t.Tid, _ = next()
+		case "-tmid":
+			t.Timeid, _ = next()
+		case "-dtm":
+			t.Dtm, err = nextInt("-dtm")
+		case "-Ntime":
+			t.Ntime, err = nextInt("-Ntime")
+		case "-u":
+			for {
+				if w, ok := next(); !ok || w == ";" {
+					break
+				}
+			}
+		case "-cat":
+			// 要素の種類ごとに、要素名、パラメータ数、データ数、残りのパラメータ
+			for {
+				s, ok := next()
+				if !ok || s == "*" {
+					break
+				}
+				n, err := nextInt(s)
+				if err != nil {
+					return nil, err
+				}
+				for i := 0; i < n; i++ {
+					name, _ := next()
+					nparm, err := nextInt(name)
+					if err != nil {
+						return nil, err
+					}
+					ndat, err := nextInt(name)
+					if err != nil {
+						return nil, err
+					}
+					for j := 0; j < nparm-1; j++ {
+						next()
+					}
+					ndata += ndat
+				}
+			}
+		default:
+			// -ver、-dtf、-wdloc など。値は読み飛ばす
+			if strings.HasPrefix(s, "-") {
+				next()
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.Timeid == "" {
+		return nil, errors.New("eeslism: output table: no -tmid")
+	}
+
+	// 列の見出し
+	t.Columns = make([]OutputColumn, ndata)
+	for i := range t.Columns {
+		c := &t.Columns[i]
+		c.Name, _ = next()
+		c.Unit, _ = next()
+		var ok bool
+		if c.Format, ok = next(); !ok {
+			return nil, errors.New("eeslism: output table: unexpected end of column names")
+		}
+	}
+
+	// データ
+	for row := 1; ; row++ {
+		s, ok := next()
+		if !ok || s == "-999" {
+			break
+		}
+		for k, id := range t.Timeid {
+			if k > 0 {
+				s, _ = next()
+			}
+			x, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("eeslism: output table: row %d: invalid time %q", row, s)
+			}
+			switch id {
+			case 'Y':
+				t.Year = append(t.Year, int(x))
+			case 'M':
+				t.Mon = append(t.Mon, int(x))
+			case 'D':
+				t.Day = append(t.Day, int(x))
+			case 'T':
+				t.Time = append(t.Time, x)
+			}
+		}
+		for i := range t.Columns {
+			c := &t.Columns[i]
+			s, ok := next()
+			if !ok {
+				return nil, fmt.Errorf("eeslism: output table: %s: unexpected end of data", c.Name)
+			}
+			if c.Format == "c" {
+				c.Text = append(c.Text, s)
+				c.Values = append(c.Values, math.NaN())
+				continue
+			}
+			x, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("eeslism: output table: %s: invalid value %q", c.Name, s)
+			}
+			c.Values = append(c.Values, x)
+		}
+	}
+	return t, sc.Err()
+}
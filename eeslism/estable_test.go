@@ -0,0 +1,69 @@
+package eeslism
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReadOutputTable は計算結果のファイルの表の読み込みを確認する
+func TestReadOutputTable(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+	out := new(MemorySink)
+	sim.Output = out
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+	file := func(suffix string) []byte {
+		for _, name := range out.Names() {
+			if strings.HasSuffix(name, suffix) {
+				return out.Bytes(name)
+			}
+		}
+		t.Fatalf("no %s output", suffix)
+		return nil
+	}
+
+	rm, err := ReadOutputTable(bytes.NewReader(file("_rm.es")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rm.Title != "L1-04 Schedule Control Test" || rm.Tid != "h" || rm.Timeid != "MDT" || rm.Ntime != 168 {
+		t.Errorf("header = %q %q %q %d", rm.Title, rm.Tid, rm.Timeid, rm.Ntime)
+	}
+	if len(rm.Time) != 168 || rm.Mon[167] != 1 || rm.Day[167] != 7 || rm.Time[167] != 24 {
+		t.Errorf("time = %d rows, last %d/%d %g", len(rm.Time), rm.Mon[len(rm.Mon)-1], rm.Day[len(rm.Day)-1], rm.Time[len(rm.Time)-1])
+	}
+	tr := rm.Column("TestRoom_Tr")
+	if tr == nil || tr.Unit != "t" || len(tr.Values) != 168 || tr.Values[0] < 0 || tr.Values[0] > 40 {
+		t.Errorf("TestRoom_Tr = %+v", tr)
+	}
+
+	sc, err := ReadOutputTable(bytes.NewReader(file("_sc.es")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := sc.Column("Boiler1_c")
+	if c == nil || len(c.Text) != 168 || c.Text[0] != "x" || !math.IsNaN(c.Values[0]) {
+		t.Errorf("Boiler1_c = %+v", c)
+	}
+
+	dr, err := ReadOutputTable(bytes.NewReader(file("_dr.es")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dr.Time != nil || len(dr.Day) != 7 || len(dr.Columns) != 24 {
+		t.Errorf("_dr: %d days, %d columns", len(dr.Day), len(dr.Columns))
+	}
+
+	if _, err := ReadOutputTable(bytes.NewReader(file("_wd.es"))); err == nil {
+		t.Error("_wd.es: expected error")
+	}
+}
@@ -0,0 +1,643 @@
+/*
+fmu.go (FMI 2.0 Co-Simulation FMU)
+
+このファイルは、EESLISMの入力データファイルを FMI 2.0 Co-Simulation の FMU として
+書き出す関数と、FMU の共有ライブラリ（fmi パッケージ）から呼び出される実行時の処理を定義します。
+
+FMU は以下のファイルからなる zip ファイルです。
+  - modelDescription.xml: 入出力変数の定義
+  - binaries/<platform>/<modelIdentifier>.so: `-buildmode=c-shared` でビルドした fmi パッケージ
+  - resources/fmu.json: 入出力変数とEESLISMの変数名の対応（FMUConfig）
+  - resources/<入力データファイル>、resources/Base/: 入力データファイルとEFLファイル
+  - resources/files/: 入力データファイルから読み込んだその他のファイル（csv= のスケジュール、WCSV、EFLファイルのディレクトリ以外の気象データなど）
+
+入出力変数は CONTL データと同じ名前（`Ta`、`<室名>_Tr`、`<経路名>` など）で指定します。
+LOAD で指定した負荷計算の設定値は、FMU では `LOAD.<名前>` という変数名になります。
+経路やシステム要素の発停などの切替変数は Boolean、それ以外は Real の変数になります。
+入力変数に値を設定すると、以降の時間ステップでは気象データやスケジュール、制御による値の代わりに
+その値が用いられます（Simulation.SetValue などと同じ）。
+
+FMU の時刻 0 はシミュレーション期間（助走期間を含む）の最初の時間ステップの開始時刻です。
+fmi2DoStep の通信ステップ幅は計算時間間隔（dTime）の整数倍でなければなりません。
+*/
+package eeslism
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	base "github.com/archlabjp/eeslism-go/Base"
+)
+
+// FMU の変数の種類
+const (
+	FMUValue    = "value"    // 数値（CONTL の変数名）
+	FMUSwitch   = "switch"   // 切替（CONTL の変数名）
+	FMUSetpoint = "setpoint" // LOAD で指定した負荷計算の設定値
+)
+
+// FMUVariable は FMU の入出力変数を表します。
+type FMUVariable struct {
+	Name           string  `json:"name"`           // EESLISMの変数名（CONTL と同じ）
+	Kind           string  `json:"kind"`           // FMUValue、FMUSwitch、FMUSetpoint。空の場合は変数名から判定する
+	Causality      string  `json:"causality"`      // "input" または "output"
+	ValueReference uint32  `json:"valueReference"` // FMI の値参照
+	Start          float64 `json:"start"`          // 入力変数の初期値
+}
+
+// FMIName は modelDescription.xml での変数名を返します。
+func (v *FMUVariable) FMIName() string {
+	if v.Kind == FMUSetpoint {
+		return "LOAD." + v.Name
+	}
+	return v.Name
+}
+
+// FMUConfig は FMU の resources/fmu.json の内容です。
+type FMUConfig struct {
+	ModelName string        `json:"modelName"`
+	GUID      string        `json:"guid"`
+	Input     string        `json:"input"`    // resources 内の入力データファイル名
+	Base      string        `json:"base"`     // resources 内のEFLディレクトリ名
+	StepSize  float64       `json:"stepSize"` // 計算時間間隔 [s]
+	Steps     int           `json:"steps"`    // 時間ステップ数
+	Variables []FMUVariable `json:"variables"`
+
+	// Files は入力データファイルから読み込んだその他のファイルの、FMU を作成したときのファイル名と
+	// resources 内のファイル名の対応です。実行時はこのファイル名で resources 内のファイルを読み込みます。
+	Files map[string]string `json:"files,omitempty"`
+
+	// RefDir は FMU を作成したときの入力データファイルのディレクトリです。
+	// 入力データファイルから参照するファイル（csv= など）は Files によりこのディレクトリから読み込みます。
+	RefDir string `json:"refDir,omitempty"`
+}
+
+// FMUOptions は ExportFMU の設定です。
+type FMUOptions struct {
+	InFile    string        // 入力データファイル名
+	EflPath   string        // EFLファイルのディレクトリ。空の場合は埋め込みの Base
+	Output    string        // 出力する FMU のファイル名。空の場合は入力データファイル名の拡張子を .fmu にしたもの
+	Library   string        // fmi パッケージの共有ライブラリ。空の場合は go build でビルドする
+	Variables []FMUVariable // 入出力変数
+}
+
+/*
+ExportFMU (Export FMI 2.0 Co-Simulation FMU)
+
+入力データファイルと EFL ファイルを FMI 2.0 Co-Simulation の FMU に書き出します。
+入出力変数の名前と種類を確認するため、入力データファイルを一時ディレクトリで読み込み、
+シミュレーションを初期化します。入力変数の初期値には最初の時間ステップの値を用います。
+FMU には `#include` を展開した後の入力データファイルを書き出します。
+*/
+func ExportFMU(opt FMUOptions) error {
+	if opt.Output == "" {
+		opt.Output = strings.TrimSuffix(opt.InFile, filepath.Ext(opt.InFile)) + ".fmu"
+	}
+
+	modelName := strings.TrimSuffix(filepath.Base(opt.InFile), filepath.Ext(opt.InFile))
+	cfg := FMUConfig{
+		ModelName: modelName,
+		Input:     filepath.Base(opt.InFile),
+		Base:      "Base",
+	}
+
+	// 変数の確認と初期値の取得
+	dir, err := os.MkdirTemp("", "eeslism-fmu")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// インクルードは元の入力データファイルのディレクトリから探すため、前処理の後の内容を書き出す
+	input, err := os.ReadFile(opt.InFile)
+	if err != nil {
+		return &InputError{Component: opt.InFile, Msg: "file not found"}
+	}
+	if input, err = decodeText(input, EncodingAuto); err != nil {
+		return &InputError{Component: opt.InFile, Msg: err.Error()}
+	}
+	text, err := preprocessFile(opt.InFile, string(input), opt.EflPath, nil)
+	if err != nil {
+		return err
+	}
+	in := filepath.Join(dir, cfg.Input)
+	if err := os.WriteFile(in, []byte(text), 0644); err != nil {
+		return err
+	}
+	if cfg.RefDir, err = filepath.Abs(filepath.Dir(opt.InFile)); err != nil {
+		return err
+	}
+	refs := &recordFS{base: osFS{}, files: make(map[string][]byte)}
+	sim := NewSimulation(in, opt.EflPath)
+	sim.FS = refs
+	sim.RefDir = cfg.RefDir
+	if err := sim.Init(); err != nil {
+		return err
+	}
+	defer sim.Finalize()
+	cfg.StepSize = sim.DTM
+	cfg.Steps = sim.RemainingSteps()
+
+	// 入力変数の初期値は最初の時間ステップの値とする（気象データや LOAD の設定値は Step で設定される）
+	if err := sim.Step(); err != nil {
+		return err
+	}
+
+	// 入力データファイル以外に読み込んだファイル
+	delete(refs.files, in)
+	refNames := make([]string, 0, len(refs.files))
+	for name := range refs.files {
+		refNames = append(refNames, name)
+	}
+	sort.Strings(refNames)
+	for i, name := range refNames {
+		if cfg.Files == nil {
+			cfg.Files = make(map[string]string)
+		}
+		cfg.Files[name] = fmt.Sprintf("files/%d_%s", i+1, path.Base(filepath.ToSlash(name)))
+	}
+
+	names := make(map[string]bool)
+	for i, v := range opt.Variables {
+		if v.Causality != "input" && v.Causality != "output" {
+			return fmt.Errorf("eeslism: %s: causality must be input or output", v.Name)
+		}
+		if v.Kind == "" {
+			if _, err := sim.Value(v.Name); err == nil {
+				v.Kind = FMUValue
+			} else if _, err := sim.Switch(v.Name); err == nil {
+				v.Kind = FMUSwitch
+			} else {
+				return err
+			}
+		}
+		if v.Start, err = fmuValue(sim, &v); err != nil {
+			return err
+		}
+		if names[v.FMIName()] {
+			return fmt.Errorf("eeslism: duplicate FMU variable %q", v.FMIName())
+		}
+		names[v.FMIName()] = true
+		v.ValueReference = uint32(i)
+		cfg.Variables = append(cfg.Variables, v)
+	}
+
+	cfgJSON, err := json.MarshalIndent(&cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(cfgJSON)
+	cfg.GUID = fmt.Sprintf("{%x-%x-%x-%x-%x}", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+	if cfgJSON, err = json.MarshalIndent(&cfg, "", "  "); err != nil {
+		return err
+	}
+
+	md, err := fmuModelDescription(&cfg)
+	if err != nil {
+		return err
+	}
+
+	// 共有ライブラリ
+	platform, ext, err := fmuPlatform()
+	if err != nil {
+		return err
+	}
+	lib := opt.Library
+	if lib == "" {
+		lib = filepath.Join(dir, "eeslism_fmu"+ext)
+		cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", lib, "github.com/archlabjp/eeslism-go/fmi")
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("eeslism: building the FMU library failed (%v); build it with `go build -buildmode=c-shared ./fmi` and specify it", err)
+		}
+	}
+
+	// zip ファイルの作成
+	f, err := os.Create(opt.Output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	if err := zipBytes(zw, "modelDescription.xml", md); err != nil {
+		return err
+	}
+	if err := zipFile(zw, "binaries/"+platform+"/"+fmuModelIdentifier(modelName)+ext, lib); err != nil {
+		return err
+	}
+	if err := zipBytes(zw, "resources/fmu.json", cfgJSON); err != nil {
+		return err
+	}
+	if err := zipBytes(zw, "resources/"+cfg.Input, []byte(text)); err != nil {
+		return err
+	}
+	for _, name := range refNames {
+		if err := zipBytes(zw, "resources/"+cfg.Files[name], refs.files[name]); err != nil {
+			return err
+		}
+	}
+	var efl fs.FS = base.FS
+	if opt.EflPath != "" {
+		efl = os.DirFS(opt.EflPath)
+	}
+	err = fs.WalkDir(efl, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) == ".go" {
+			return err
+		}
+		b, err := fs.ReadFile(efl, path)
+		if err != nil {
+			return err
+		}
+		return zipBytes(zw, "resources/"+cfg.Base+"/"+path, b)
+	})
+	if err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// fmuModelIdentifier はモデル名から FMI の modelIdentifier（C の識別子）を作成します。
+func fmuModelIdentifier(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 || b[0] >= '0' && b[0] <= '9' {
+		b = append([]byte{'_'}, b...)
+	}
+	return string(b)
+}
+
+// fmuPlatform は共有ライブラリを格納する binaries 以下のディレクトリ名と拡張子を返します。
+func fmuPlatform() (string, string, error) {
+	bits := "64"
+	switch runtime.GOARCH {
+	case "amd64", "arm64":
+	case "386":
+		bits = "32"
+	default:
+		return "", "", fmt.Errorf("eeslism: FMU export is not supported on %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return "linux" + bits, ".so", nil
+	case "darwin":
+		return "darwin" + bits, ".dylib", nil
+	case "windows":
+		return "win" + bits, ".dll", nil
+	}
+	return "", "", fmt.Errorf("eeslism: FMU export is not supported on %s/%s", runtime.GOOS, runtime.GOARCH)
+}
+
+// modelDescription.xml の要素
+type fmiModelDescription struct {
+	XMLName                  xml.Name `xml:"fmiModelDescription"`
+	FmiVersion               string   `xml:"fmiVersion,attr"`
+	ModelName                string   `xml:"modelName,attr"`
+	GUID                     string   `xml:"guid,attr"`
+	GenerationTool           string   `xml:"generationTool,attr"`
+	VariableNamingConvention string   `xml:"variableNamingConvention,attr"`
+	NumberOfEventIndicators  int      `xml:"numberOfEventIndicators,attr"`
+	CoSimulation             struct {
+		ModelIdentifier                        string `xml:"modelIdentifier,attr"`
+		CanHandleVariableCommunicationStepSize bool   `xml:"canHandleVariableCommunicationStepSize,attr"`
+		CanNotUseMemoryManagementFunctions     bool   `xml:"canNotUseMemoryManagementFunctions,attr"`
+	}
+	DefaultExperiment struct {
+		StartTime float64 `xml:"startTime,attr"`
+		StopTime  float64 `xml:"stopTime,attr"`
+		StepSize  float64 `xml:"stepSize,attr"`
+	}
+	ModelVariables struct {
+		ScalarVariable []fmiScalarVariable
+	}
+	ModelStructure struct {
+		Outputs struct {
+			Unknown []struct {
+				Index int `xml:"index,attr"`
+			}
+		}
+	}
+}
+
+type fmiScalarVariable struct {
+	Name           string `xml:"name,attr"`
+	ValueReference uint32 `xml:"valueReference,attr"`
+	Causality      string `xml:"causality,attr"`
+	Variability    string `xml:"variability,attr"`
+	Real           *struct {
+		Start *float64 `xml:"start,attr"`
+	} `xml:",omitempty"`
+	Boolean *struct {
+		Start *bool `xml:"start,attr"`
+	} `xml:",omitempty"`
+}
+
+func fmuModelDescription(cfg *FMUConfig) ([]byte, error) {
+	var md fmiModelDescription
+	md.FmiVersion = "2.0"
+	md.ModelName = cfg.ModelName
+	md.GUID = cfg.GUID
+	md.GenerationTool = "eeslism-go"
+	md.VariableNamingConvention = "flat"
+	md.CoSimulation.ModelIdentifier = fmuModelIdentifier(cfg.ModelName)
+	md.CoSimulation.CanHandleVariableCommunicationStepSize = true
+	md.CoSimulation.CanNotUseMemoryManagementFunctions = true
+	md.DefaultExperiment.StopTime = cfg.StepSize * float64(cfg.Steps)
+	md.DefaultExperiment.StepSize = cfg.StepSize
+
+	for i := range cfg.Variables {
+		v := &cfg.Variables[i]
+		sv := fmiScalarVariable{
+			Name:           v.FMIName(),
+			ValueReference: v.ValueReference,
+			Causality:      v.Causality,
+		}
+		start := v.Start
+		if v.Kind == FMUSwitch {
+			sv.Variability = "discrete"
+			sv.Boolean = &struct {
+				Start *bool `xml:"start,attr"`
+			}{}
+			if v.Causality == "input" {
+				b := start != 0
+				sv.Boolean.Start = &b
+			}
+		} else {
+			sv.Variability = "continuous"
+			sv.Real = &struct {
+				Start *float64 `xml:"start,attr"`
+			}{}
+			if v.Causality == "input" {
+				sv.Real.Start = &start
+			}
+		}
+		md.ModelVariables.ScalarVariable = append(md.ModelVariables.ScalarVariable, sv)
+
+		if v.Causality == "output" {
+			md.ModelStructure.Outputs.Unknown = append(md.ModelStructure.Outputs.Unknown, struct {
+				Index int `xml:"index,attr"`
+			}{i + 1})
+		}
+	}
+
+	b, err := xml.MarshalIndent(&md, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// fmuValue は変数 v の現在の値を返します。切替変数は OFF のとき 0、それ以外は 1 とします。
+func fmuValue(sim *Simulation, v *FMUVariable) (float64, error) {
+	switch v.Kind {
+	case FMUValue:
+		return sim.Value(v.Name)
+	case FMUSetpoint:
+		return sim.Setpoint(v.Name)
+	case FMUSwitch:
+		sw, err := sim.Switch(v.Name)
+		if err != nil || sw == OFF_SW {
+			return 0, err
+		}
+		return 1, nil
+	}
+	return 0, fmt.Errorf("eeslism: %s: unknown kind %q", v.Name, v.Kind)
+}
+
+// setFMUValue は変数 v の値を x で上書きします。
+func setFMUValue(sim *Simulation, v *FMUVariable, x float64) error {
+	switch v.Kind {
+	case FMUValue:
+		return sim.SetValue(v.Name, x)
+	case FMUSetpoint:
+		return sim.SetSetpoint(v.Name, x)
+	case FMUSwitch:
+		sw := OFF_SW
+		if x != 0 {
+			sw = ON_SW
+		}
+		return sim.SetSwitch(v.Name, sw)
+	}
+	return fmt.Errorf("eeslism: %s: unknown kind %q", v.Name, v.Kind)
+}
+
+// recordFS は base から読み込んだファイルの内容を files に記録する fs.FS です。
+type recordFS struct {
+	base  fs.FS
+	files map[string][]byte
+}
+
+func (f *recordFS) Open(name string) (fs.File, error) {
+	b, err := fs.ReadFile(f.base, name)
+	if err != nil {
+		return nil, err
+	}
+	f.files[name] = b
+	return &overlayFile{Reader: bytes.NewReader(b), name: path.Base(name)}, nil
+}
+
+func copyFile(src, dst string) error {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, b, 0644)
+}
+
+func zipBytes(zw *zip.Writer, name string, b []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func zipFile(zw *zip.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+/*
+FMUInstance (FMU Instance)
+
+FMU の実行時のインスタンスです。fmi パッケージの fmi2Instantiate で作成されます。
+
+入力データファイルは一時ディレクトリにコピーして計算するため、
+計算結果の出力ファイルは Dir が返すディレクトリに作成されます。
+このディレクトリは Free で削除します。
+*/
+type FMUInstance struct {
+	cfg  FMUConfig
+	dir  string
+	sim  *Simulation
+	time float64
+	vars map[uint32]*FMUVariable
+	init map[uint32]float64 // 初期化前に設定された入力変数の値
+}
+
+// NewFMUInstance は FMU を展開した resources ディレクトリから FMU のインスタンスを作成します。
+// guid が modelDescription.xml の GUID と一致しない場合はエラーを返します。
+func NewFMUInstance(resources, guid string) (*FMUInstance, error) {
+	b, err := os.ReadFile(filepath.Join(resources, "fmu.json"))
+	if err != nil {
+		return nil, err
+	}
+	fi := &FMUInstance{
+		vars: make(map[uint32]*FMUVariable),
+		init: make(map[uint32]float64),
+	}
+	if err := json.Unmarshal(b, &fi.cfg); err != nil {
+		return nil, err
+	}
+	if guid != fi.cfg.GUID {
+		return nil, fmt.Errorf("eeslism: GUID %s does not match the FMU (%s)", guid, fi.cfg.GUID)
+	}
+	for i := range fi.cfg.Variables {
+		v := &fi.cfg.Variables[i]
+		fi.vars[v.ValueReference] = v
+	}
+
+	// FMU を作成したときのファイル名で resources 内のファイルを読み込む
+	files := make(map[string][]byte, len(fi.cfg.Files))
+	for name, res := range fi.cfg.Files {
+		if files[name], err = os.ReadFile(filepath.Join(resources, filepath.FromSlash(res))); err != nil {
+			return nil, err
+		}
+	}
+
+	if fi.dir, err = os.MkdirTemp("", "eeslism-fmu"); err != nil {
+		return nil, err
+	}
+	in := filepath.Join(fi.dir, fi.cfg.Input)
+	if err := copyFile(filepath.Join(resources, fi.cfg.Input), in); err != nil {
+		os.RemoveAll(fi.dir)
+		return nil, err
+	}
+	fi.sim = NewSimulation(in, filepath.Join(resources, fi.cfg.Base))
+	fi.sim.FS = overlayFS{files: files, base: osFS{}}
+	fi.sim.RefDir = fi.cfg.RefDir
+	return fi, nil
+}
+
+// Free は計算結果の出力ファイルを作成したディレクトリを削除します（fmi2FreeInstance）。
+func (fi *FMUInstance) Free() error {
+	return os.RemoveAll(fi.dir)
+}
+
+// Dir は計算結果の出力ファイルを作成するディレクトリを返します。
+func (fi *FMUInstance) Dir() string {
+	return fi.dir
+}
+
+// Init はシミュレーションを初期化し、初期化前に設定された入力変数の値を設定します（fmi2ExitInitializationMode）。
+func (fi *FMUInstance) Init() error {
+	if err := fi.sim.Init(); err != nil {
+		return err
+	}
+	for vr, x := range fi.init {
+		if err := setFMUValue(fi.sim, fi.vars[vr], x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fi *FMUInstance) variable(vr uint32) (*FMUVariable, error) {
+	v, ok := fi.vars[vr]
+	if !ok {
+		return nil, fmt.Errorf("eeslism: unknown value reference %d", vr)
+	}
+	return v, nil
+}
+
+// Get は値参照 vr の変数の値を返します。切替変数は OFF のとき 0、それ以外は 1 です。
+func (fi *FMUInstance) Get(vr uint32) (float64, error) {
+	v, err := fi.variable(vr)
+	if err != nil {
+		return 0, err
+	}
+	if !fi.sim.initialized {
+		if x, ok := fi.init[vr]; ok {
+			return x, nil
+		}
+		return v.Start, nil
+	}
+	return fmuValue(fi.sim, v)
+}
+
+// Set は値参照 vr の入力変数の値を x で上書きします。
+func (fi *FMUInstance) Set(vr uint32, x float64) error {
+	v, err := fi.variable(vr)
+	if err != nil {
+		return err
+	}
+	if v.Causality != "input" {
+		return fmt.Errorf("eeslism: %s is not an input", v.FMIName())
+	}
+	if !fi.sim.initialized {
+		fi.init[vr] = x
+		return nil
+	}
+	return setFMUValue(fi.sim, v, x)
+}
+
+// DoStep は時刻 t から通信ステップ幅 h の計算を行います（fmi2DoStep）。
+func (fi *FMUInstance) DoStep(t, h float64) error {
+	dt := fi.cfg.StepSize
+	if math.Abs(t-fi.time) > 1e-6*dt {
+		return fmt.Errorf("eeslism: communication point %g does not match the FMU time %g", t, fi.time)
+	}
+	n := math.Round(h / dt)
+	if n < 1 || math.Abs(n*dt-h) > 1e-6*dt {
+		return fmt.Errorf("eeslism: communication step size %g is not a multiple of the time step %g", h, dt)
+	}
+	for i := 0; i < int(n); i++ {
+		if fi.sim.Done() {
+			return errors.New("eeslism: the simulation period has ended")
+		}
+		if err := fi.sim.Step(); err != nil {
+			return err
+		}
+		fi.time += dt
+	}
+	return nil
+}
+
+// Terminate は計算結果を出力します（fmi2Terminate）。
+func (fi *FMUInstance) Terminate() error {
+	return fi.sim.Finalize()
+}
@@ -0,0 +1,267 @@
+package eeslism
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExportFMU は FMU の zip ファイルの構成と modelDescription.xml を確認し、
+// resources から作成した FMUInstance で計算できることを確認する
+func TestExportFMU(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// EFLファイルのディレクトリ以外の気象データ
+	wdir := t.TempDir()
+	weather := filepath.Join(wdir, "weather.has")
+	if err := copyFile(filepath.Join(eflPath, "tokyo_3column_SI.has"), weather); err != nil {
+		t.Fatal(err)
+	}
+	in := copySimulationInput(t, src, t.TempDir())
+	b, err := os.ReadFile(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = []byte(strings.Replace(string(b), "w=tokyo_3column_SI.has", "w="+weather, 1))
+	if err := os.WriteFile(in, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.so")
+	if err := os.WriteFile(lib, []byte("library"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fmu := filepath.Join(dir, "room.fmu")
+	err = ExportFMU(FMUOptions{
+		InFile:  in,
+		EflPath: eflPath,
+		Output:  fmu,
+		Library: lib,
+		Variables: []FMUVariable{
+			{Name: "Ta", Causality: "input"},
+			{Name: "HeatPath", Causality: "input"},
+			{Name: "Boiler1_Tout", Kind: FMUSetpoint, Causality: "input"},
+			{Name: "Boiler1_Twout", Causality: "output"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zip ファイルの展開
+	ext := filepath.Join(dir, "fmu")
+	files := unzipFMU(t, fmu, ext)
+	platform, so, _ := fmuPlatform()
+	for _, name := range []string{
+		"modelDescription.xml",
+		"binaries/" + platform + "/simple_room_schedule_test" + so,
+		"resources/fmu.json",
+		"resources/simple_room_schedule_test.txt",
+		"resources/Base/supw.efl",
+		"resources/files/1_weather.has",
+	} {
+		if !files[name] {
+			t.Errorf("%s is not in the FMU", name)
+		}
+	}
+
+	// FMU は作成した環境の気象データを参照しない
+	if err := os.Remove(weather); err != nil {
+		t.Fatal(err)
+	}
+
+	// modelDescription.xml
+	b, err = os.ReadFile(filepath.Join(ext, "modelDescription.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var md fmiModelDescription
+	if err := xml.Unmarshal(b, &md); err != nil {
+		t.Fatal(err)
+	}
+	if md.FmiVersion != "2.0" || md.CoSimulation.ModelIdentifier != "simple_room_schedule_test" {
+		t.Errorf("fmiVersion = %s, modelIdentifier = %s", md.FmiVersion, md.CoSimulation.ModelIdentifier)
+	}
+	if md.DefaultExperiment.StepSize != 3600 || md.DefaultExperiment.StopTime != 7*24*3600 {
+		t.Errorf("DefaultExperiment = %+v", md.DefaultExperiment)
+	}
+	var names []string
+	for _, sv := range md.ModelVariables.ScalarVariable {
+		names = append(names, sv.Name)
+	}
+	if got := strings.Join(names, ","); got != "Ta,HeatPath,LOAD.Boiler1_Tout,Boiler1_Twout" {
+		t.Errorf("variables = %s", got)
+	}
+	if sv := md.ModelVariables.ScalarVariable[1]; sv.Boolean == nil {
+		t.Error("HeatPath is not Boolean")
+	}
+	if sv := md.ModelVariables.ScalarVariable[2]; sv.Real == nil || sv.Real.Start == nil || *sv.Real.Start != 45 {
+		t.Error("LOAD.Boiler1_Tout start is not 45")
+	}
+	if u := md.ModelStructure.Outputs.Unknown; len(u) != 1 || u[0].Index != 4 {
+		t.Errorf("Outputs = %+v", u)
+	}
+
+	// FMUInstance
+	resources := filepath.Join(ext, "resources")
+	if _, err := NewFMUInstance(resources, "{wrong}"); err == nil {
+		t.Error("NewFMUInstance with wrong GUID: expected error")
+	}
+	fi, err := NewFMUInstance(resources, md.GUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fi.Set(2, 60); err != nil {
+		t.Fatal(err)
+	}
+	if err := fi.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fi.Set(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := fi.Set(3, 0); err == nil {
+		t.Error("Set of output: expected error")
+	}
+	if err := fi.DoStep(0, 2*3600); err != nil {
+		t.Fatal(err)
+	}
+	if Twout, _ := fi.Get(3); Twout != 60 {
+		t.Errorf("Boiler1_Twout = %g, want 60", Twout)
+	}
+	if err := fi.DoStep(0, 3600); err == nil {
+		t.Error("DoStep at wrong time: expected error")
+	}
+	if err := fi.DoStep(2*3600, 1800); err == nil {
+		t.Error("DoStep with step size not a multiple of dTime: expected error")
+	}
+	if err := fi.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(fi.Dir(), "simple_room_schedule_test_rm.es")); err != nil {
+		t.Error(err)
+	}
+	if err := fi.Free(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fi.Dir()); !os.IsNotExist(err) {
+		t.Errorf("%s is not removed by Free", fi.Dir())
+	}
+}
+
+// unzipFMU は FMU の zip ファイル fmu を dir に展開し、含まれるファイル名を返す
+func unzipFMU(t *testing.T, fmu, dir string) map[string]bool {
+	t.Helper()
+	zr, err := zip.OpenReader(fmu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	files := make(map[string]bool)
+	for _, f := range zr.File {
+		files[f.Name] = true
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(f.Name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+// TestExportFMU_Include は `#include` を使う入力データファイルの FMU を作成し、計算できることを確認する
+func TestExportFMU_Include(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WALL データを walls.txt に分ける
+	s := string(b)
+	i := strings.Index(s, "WALL\n")
+	j := i + strings.Index(s[i:], "*\n") + 2
+	in := filepath.Join(t.TempDir(), "room.txt")
+	if err := os.WriteFile(filepath.Join(filepath.Dir(in), "walls.txt"), []byte(s[i:j]), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(in, []byte(s[:i]+"#include \"walls.txt\"\n"+s[j:]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	lib := filepath.Join(dir, "lib.so")
+	if err := os.WriteFile(lib, []byte("library"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fmu := filepath.Join(dir, "room.fmu")
+	err = ExportFMU(FMUOptions{
+		InFile:    in,
+		EflPath:   eflPath,
+		Output:    fmu,
+		Library:   lib,
+		Variables: []FMUVariable{{Name: "TestRoom_Tr", Causality: "output"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ext := filepath.Join(dir, "fmu")
+	unzipFMU(t, fmu, ext)
+	resources := filepath.Join(ext, "resources")
+	b, err = os.ReadFile(filepath.Join(resources, "room.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "#include") || !strings.Contains(string(b), "-E:ExtWall") {
+		t.Errorf("room.txt in the FMU:\n%s", b)
+	}
+	var cfg FMUConfig
+	b, err = os.ReadFile(filepath.Join(resources, "fmu.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := NewFMUInstance(resources, cfg.GUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fi.Free()
+	if err := fi.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fi.DoStep(0, 24*3600); err != nil {
+		t.Fatal(err)
+	}
+	if Tr, _ := fi.Get(0); Tr < 0 || Tr > 40 {
+		t.Errorf("TestRoom_Tr = %g", Tr)
+	}
+	if err := fi.Terminate(); err != nil {
+		t.Fatal(err)
+	}
+}
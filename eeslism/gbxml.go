@@ -0,0 +1,847 @@
+/*
+gbxml.go (gbXML Import)
+
+BIM ツールが書き出す gbXML（https://www.gbxml.org/）を Model に変換します（ImportGbXML）。
+
+  - Space: ROOM の室。室容積は Volume、ない場合は室を囲む面の座標から求めます。
+  - Surface: 室の部位 (RMSRF)。surfaceType により次のように変換します。
+  - ExteriorWall、Roof、RaisedFloor: 外壁 (-E)、屋根 (-R)、外気に接する床 (-F)。
+    面の法線から方位角、傾斜角を求め、同じ向きの面で1つの外表面 (EXSRF) を用います。
+    面と窓の座標は COORDNT の BDP、RMP、WD とし、部位の rmp= で対応付けます。
+  - UndergroundWall、UndergroundSlab、SlabOnGrade、UndergroundCeiling: 外表面 earth（Z=1.5）に接する部位。
+  - InteriorWall、InteriorFloor、Ceiling: 内壁 (-i)、床 (-f)、天井 (-c)。隣接する両方の室の部位とし、r= で相互に参照します。
+  - Shade: OBS の長方形の障害物 (rect)。
+  - Opening: 窓 (-W)。面積は部位の面積から除きます。窓の仕様は WindowType の U-value、
+    SolarHeatGainCoeff、Transmittance から WINDOW を作成します。ドアは部位の一部とします。
+  - Construction、Layer、Material: 壁体 (WALL)。材料は wbmlist.efl の材料コード、材料名、
+    代表的な英語名と照合し、対応する材料のないものは報告します。
+
+座標の単位は gbXML の lengthUnit、室容積の単位は volumeUnit に従い、m、m3 に換算します。
+COORDNT、OBS の面は長方形で表すため、長方形でない面は面内の外接長方形で近似します。
+*/
+package eeslism
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+// gbXML の要素のうち、変換に用いるもの
+type gbXML struct {
+	LengthUnit    string           `xml:"lengthUnit,attr"`
+	VolumeUnit    string           `xml:"volumeUnit,attr"`
+	Campus        gbCampus         `xml:"Campus"`
+	Constructions []gbConstruction `xml:"Construction"`
+	Layers        []gbLayer        `xml:"Layer"`
+	Materials     []gbMaterial     `xml:"Material"`
+	WindowTypes   []gbWindowType   `xml:"WindowType"`
+}
+
+type gbCampus struct {
+	Name      string       `xml:"Name"`
+	Buildings []gbBuilding `xml:"Building"`
+	Surfaces  []gbSurface  `xml:"Surface"`
+}
+
+type gbBuilding struct {
+	Name   string    `xml:"Name"`
+	Spaces []gbSpace `xml:"Space"`
+}
+
+type gbSpace struct {
+	ID     string  `xml:"id,attr"`
+	Name   string  `xml:"Name"`
+	Volume gbValue `xml:"Volume"`
+}
+
+type gbSurface struct {
+	ID              string      `xml:"id,attr"`
+	SurfaceType     string      `xml:"surfaceType,attr"`
+	ConstructionRef string      `xml:"constructionIdRef,attr"`
+	Name            string      `xml:"Name"`
+	Adjacent        []gbRef     `xml:"AdjacentSpaceId"`
+	Points          []gbPoint   `xml:"PlanarGeometry>PolyLoop>CartesianPoint"`
+	Openings        []gbOpening `xml:"Opening"`
+
+	adiabatic bool // 隣室のない断熱境界の面（IDF の Adiabatic）
+}
+
+type gbOpening struct {
+	ID            string    `xml:"id,attr"`
+	OpeningType   string    `xml:"openingType,attr"`
+	WindowTypeRef string    `xml:"windowTypeIdRef,attr"`
+	Name          string    `xml:"Name"`
+	Points        []gbPoint `xml:"PlanarGeometry>PolyLoop>CartesianPoint"`
+}
+
+type gbConstruction struct {
+	ID     string  `xml:"id,attr"`
+	Name   string  `xml:"Name"`
+	Layers []gbRef `xml:"LayerId"`
+
+	params Params // 外気、地盤に接する壁体の as=、Eo=、Ei=（IDF の材料の吸収率）
+}
+
+type gbLayer struct {
+	ID        string  `xml:"id,attr"`
+	Materials []gbRef `xml:"MaterialId"`
+}
+
+type gbMaterial struct {
+	ID        string  `xml:"id,attr"`
+	Name      string  `xml:"Name"`
+	Thickness gbValue `xml:"Thickness"`
+}
+
+type gbWindowType struct {
+	ID            string    `xml:"id,attr"`
+	Name          string    `xml:"Name"`
+	UValue        gbValue   `xml:"U-value"`
+	SHGC          gbValue   `xml:"SolarHeatGainCoeff"`
+	Transmittance []gbValue `xml:"Transmittance"`
+}
+
+// gbRef は `*IdRef` 属性による参照です。
+type gbRef struct {
+	Space    string `xml:"spaceIdRef,attr"`
+	Layer    string `xml:"layerIdRef,attr"`
+	Material string `xml:"materialIdRef,attr"`
+}
+
+// gbValue は単位などの属性を持つ数値です。
+type gbValue struct {
+	Unit  string `xml:"unit,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// float は数値を返します。値がない場合は ok が false です。
+func (v gbValue) float() (f float64, ok bool) {
+	s := strings.TrimSpace(v.Value)
+	if s == "" {
+		return 0, false
+	}
+	if _, err := fmt.Sscan(s, &f); err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+type gbPoint struct {
+	Coordinates []float64 `xml:"Coordinate"`
+}
+
+// gbLengthUnits は gbXML の長さの単位の m への換算係数です。
+var gbLengthUnits = map[string]float64{
+	"": 1, "Meters": 1, "Centimeters": 0.01, "Millimeters": 0.001, "Kilometers": 1000,
+	"Feet": 0.3048, "Inches": 0.0254, "Yards": 0.9144, "Miles": 1609.344,
+}
+
+// gbVolumeUnits は gbXML の体積の単位の m3 への換算係数です。
+var gbVolumeUnits = map[string]float64{
+	"": 1, "CubicMeters": 1, "CubicCentimeters": 1e-6, "CubicMillimeters": 1e-9,
+	"CubicFeet": 0.028316846592, "CubicInches": 1.6387064e-5, "CubicYards": 0.764554857984,
+}
+
+// gbFilmResistance は窓の U-value から熱抵抗 R= を求める際に除く表面熱伝達抵抗 [m2K/W] です。
+// 室内側 9 W/m2K、屋外側 23 W/m2K とします。
+const gbFilmResistance = 1/9.0 + 1/23.0
+
+// gbMaterialKeywords は wbmlist.efl の材料コードに対応する材料名の英単語です。先に一致したものを用います。
+var gbMaterialKeywords = []struct{ word, code string }{
+	{"gypsum", "GPB"}, {"plasterboard", "GPB"}, {"plywood", "WDB"},
+	{"glass wool", "GWL"}, {"fiberglass", "GWL"}, {"rock wool", "RWB"}, {"mineral wool", "RWB"},
+	{"extruded polystyrene", "BL3"}, {"polystyrene", "FPS"}, {"urethane", "REU"}, {"polyurethane", "REU"},
+	{"phenolic", "F11"}, {"mortar", "MOL"}, {"lightweight concrete", "LRC"}, {"concrete", "RC"},
+	{"brick", "NBR"}, {"tile", "TLE"}, {"carpet", "CAR"}, {"steel", "STL"},
+	{"aluminum", "ALM"}, {"aluminium", "ALM"}, {"timber", "WD1"}, {"wood", "WD1"}, {"air", "as1"},
+}
+
+// wbmEntry は wbmlist.efl の材料です。
+type wbmEntry struct {
+	Code, Name string
+}
+
+// gbImporter は gbXML からモデルへの変換の状態です。
+type gbImporter struct {
+	doc      *gbXML
+	scale    float64 // 座標の m への換算係数
+	wbm      []wbmEntry
+	model    *Model
+	warnings []string
+
+	names     map[string]map[string]bool // 種類ごとの使用済みの名前
+	rooms     map[string]*Room           // Space の id から室
+	volumes   map[string]float64         // Space の id から面の座標による室容積
+	exsrf     map[string]string          // 方位角、傾斜角から外表面名
+	walls     map[string]string          // 部位と Construction の id から壁体名
+	windows   map[string]string          // WindowType の id から窓名
+	materials map[string]string          // Material の id から材料コード
+
+	coordnt []string // COORDNT の論理行
+	obs     []string // OBS の論理行
+}
+
+// ImportGbXML は gbXML を読み込み、ROOM、EXSRF、WALL、WINDOW、COORDNT、OBS のモデルに変換します。
+// 材料は EFLファイルのディレクトリ efl_path（空の場合は埋め込みの Base）の wbmlist.efl と照合します。
+// 対応する材料がない、長方形でない面を近似したなど、変換の際の注意を warnings に返します。
+// 返すモデルの気象データファイル、計算期間（1/1-12/31）は必要に応じて変更してください。
+func ImportGbXML(r io.Reader, efl_path string) (m *Model, warnings []string, err error) {
+	var doc gbXML
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		enc, err := ParseEncoding(label, EncodingUTF8)
+		if err != nil {
+			return nil, err
+		}
+		if enc == EncodingShiftJIS {
+			return transform.NewReader(input, japanese.ShiftJIS.NewDecoder()), nil
+		}
+		return input, nil
+	}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("gbXML: %w", err)
+	}
+
+	scale, ok := gbLengthUnits[doc.LengthUnit]
+	if !ok {
+		return nil, nil, fmt.Errorf("gbXML: unknown lengthUnit %q", doc.LengthUnit)
+	}
+	if _, ok := gbVolumeUnits[doc.VolumeUnit]; !ok {
+		return nil, nil, fmt.Errorf("gbXML: unknown volumeUnit %q", doc.VolumeUnit)
+	}
+
+	b, err := fs.ReadFile(decodingFS{eflFS(efl_path), EncodingAuto}, "wbmlist.efl")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	title := doc.Campus.Name
+	if len(doc.Campus.Buildings) > 0 && doc.Campus.Buildings[0].Name != "" {
+		title = doc.Campus.Buildings[0].Name
+	}
+	if title == "" {
+		title = "gbXML"
+	}
+	im := newGbImporter(&doc, scale, title)
+	im.wbm = readWbmlist(string(b))
+	if err := im.convert(); err != nil {
+		return nil, nil, err
+	}
+	return im.model, im.warnings, nil
+}
+
+// newGbImporter は表題 title のモデルへの変換を準備します。
+func newGbImporter(doc *gbXML, scale float64, title string) *gbImporter {
+	m := NewModel(strings.NewReplacer(";", " ", "!", " ", "\n", " ").Replace(title))
+	m.Start, m.End = MonthDay{1, 1}, MonthDay{12, 31}
+	return &gbImporter{
+		doc:       doc,
+		scale:     scale,
+		model:     m,
+		names:     make(map[string]map[string]bool),
+		rooms:     make(map[string]*Room),
+		volumes:   make(map[string]float64),
+		exsrf:     make(map[string]string),
+		walls:     make(map[string]string),
+		windows:   make(map[string]string),
+		materials: make(map[string]string),
+	}
+}
+
+// readWbmlist は wbmlist.efl の内容 text の材料コードと材料名（注釈文）を返します。
+func readWbmlist(text string) []wbmEntry {
+	var list []wbmEntry
+	for _, s := range strings.Split(text, "\n") {
+		if strings.HasPrefix(s, "*") {
+			break
+		}
+		code, name := s, ""
+		if i := strings.IndexRune(s, '!'); i >= 0 {
+			code, name = s[:i], strings.TrimSpace(strings.TrimLeft(s[i:], "!"))
+		}
+		fields := strings.Fields(code)
+		if len(fields) == 0 {
+			continue
+		}
+		list = append(list, wbmEntry{Code: fields[0], Name: name})
+	}
+	return list
+}
+
+func (im *gbImporter) warnf(format string, a ...interface{}) {
+	im.warnings = append(im.warnings, fmt.Sprintf(format, a...))
+}
+
+// name は種類 kind の中で重複しない名前を返します。s の入力データファイルで使えない文字は `_` に置き換えます。
+func (im *gbImporter) name(kind, s, fallback string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || strings.ContainsRune(";!*:=(),<>[]\"'", r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(s))
+	if s == "" {
+		s = fallback
+	}
+	if im.names[kind] == nil {
+		im.names[kind] = make(map[string]bool)
+	}
+	name := s
+	for i := 2; im.names[kind][name]; i++ {
+		name = fmt.Sprintf("%s_%d", s, i)
+	}
+	im.names[kind][name] = true
+	return name
+}
+
+// convert は室、部位の順に変換します。
+func (im *gbImporter) convert() error {
+	m := im.model
+	var spaces []gbSpace
+	for _, bd := range im.doc.Campus.Buildings {
+		spaces = append(spaces, bd.Spaces...)
+	}
+	if len(spaces) == 0 {
+		return fmt.Errorf("gbXML: no Space")
+	}
+	for _, sp := range spaces {
+		rm := &Room{Name: im.name("ROOM", sp.Name, sp.ID)}
+		im.rooms[sp.ID] = rm
+		m.Rooms = append(m.Rooms, rm)
+	}
+
+	for _, s := range im.doc.Campus.Surfaces {
+		im.surface(s)
+	}
+
+	var rooms []*Room
+	for _, sp := range spaces {
+		rm := im.rooms[sp.ID]
+		if v, ok := sp.Volume.float(); ok && v > 0 {
+			rm.Vol = gbRound(v*gbVolumeUnits[im.doc.VolumeUnit], 3)
+		} else {
+			rm.Vol = gbRound(math.Abs(im.volumes[sp.ID]), 3)
+		}
+		if len(rm.Surfaces) == 0 {
+			im.warnf("space %q has no surfaces; skipped", sp.ID)
+			continue
+		}
+		if rm.Vol == 0 {
+			im.warnf("space %q: no Volume", sp.ID)
+		}
+		rooms = append(rooms, rm)
+	}
+	m.Rooms = rooms
+
+	if len(im.coordnt) > 0 {
+		m.Sections = append(m.Sections, &Section{Name: "COORDNT", Lines: im.coordnt})
+	}
+	if len(im.obs) > 0 {
+		m.Sections = append(m.Sections, &Section{Name: "OBS", Lines: im.obs})
+	}
+	return nil
+}
+
+// points は座標を m に換算して返します。
+func (im *gbImporter) points(pts []gbPoint) []XYZ {
+	var ps []XYZ
+	for _, p := range pts {
+		if len(p.Coordinates) != 3 {
+			continue
+		}
+		ps = append(ps, XYZ{p.Coordinates[0] * im.scale, p.Coordinates[1] * im.scale, p.Coordinates[2] * im.scale})
+	}
+	return ps
+}
+
+// surface は Surface を室の部位などに変換します。
+func (im *gbImporter) surface(s gbSurface) {
+	pts := im.points(s.Points)
+	normal, area := gbNewell(pts)
+	if len(pts) < 3 || area == 0 {
+		im.warnf("surface %q has no PolyLoop; skipped", s.ID)
+		return
+	}
+
+	var rooms []*Room
+	var spaceIDs []string
+	for _, adj := range s.Adjacent {
+		rm, ok := im.rooms[adj.Space]
+		if !ok {
+			im.warnf("surface %q: space %q not found", s.ID, adj.Space)
+			continue
+		}
+		rooms = append(rooms, rm)
+		spaceIDs = append(spaceIDs, adj.Space)
+	}
+
+	// 室容積を面の座標から求める（法線は最初の室から外向き）
+	for i, id := range spaceIDs {
+		v := gbSignedVolume(pts)
+		if i > 0 {
+			v = -v
+		}
+		im.volumes[id] += v
+	}
+
+	if s.SurfaceType == "Shade" {
+		im.shade(s, pts, normal, area)
+		return
+	}
+	if len(rooms) == 0 {
+		im.warnf("surface %q (%s) has no adjacent space; skipped", s.ID, s.SurfaceType)
+		return
+	}
+
+	switch s.SurfaceType {
+	case "ExteriorWall":
+		im.exterior(s, rooms[0], BLE_ExternalWall, pts, normal, area)
+	case "Roof":
+		im.exterior(s, rooms[0], BLE_Roof, pts, normal, area)
+	case "RaisedFloor":
+		im.exterior(s, rooms[0], BLE_Floor, pts, normal, area)
+	case "UndergroundWall":
+		im.ground(s, rooms[0], BLE_ExternalWall, area)
+	case "UndergroundSlab", "SlabOnGrade":
+		im.ground(s, rooms[0], BLE_Floor, area)
+	case "UndergroundCeiling":
+		im.ground(s, rooms[0], BLE_Roof, area)
+	case "InteriorWall", "InteriorFloor", "Ceiling":
+		im.interior(s, rooms, normal, area)
+	default:
+		im.warnf("surface %q: surfaceType %q is not supported; skipped", s.ID, s.SurfaceType)
+	}
+}
+
+// exterior は外気に接する面を室 rm の部位と COORDNT の面に変換します。
+func (im *gbImporter) exterior(s gbSurface, rm *Room, ble BLEType, pts []XYZ, normal XYZ, area float64) {
+	azimuth, tilt := gbOrientation(normal)
+	rmp := im.name("RMP", s.Name, s.ID)
+	sd := &Surface{
+		Exsrf:  im.externalSurface(azimuth, tilt),
+		Ble:    ble,
+		Wall:   im.wall(ble, s.ConstructionRef, s.ID, false),
+		Params: Params{"rmp": rmp},
+	}
+	rm.Surfaces = append(rm.Surfaces, sd)
+
+	pl := gbRect(pts, azimuth, tilt)
+	if math.Abs(pl.w*pl.h-area) > 0.01*area {
+		im.warnf("surface %q is not a rectangle; approximated by %sx%s m", s.ID, gbFloat(pl.w), gbFloat(pl.h))
+	}
+
+	lines := []string{
+		fmt.Sprintf("BDP %s -xyz %s %s %s -WA %s -WB %s -WH %s %s ;", rmp,
+			gbFloat(pl.o.X), gbFloat(pl.o.Y), gbFloat(pl.o.Z), modelFloat(azimuth), modelFloat(tilt), gbFloat(pl.w), gbFloat(pl.h)),
+		fmt.Sprintf("\tRMP %s %s -xyb 0 0 -WH %s %s ;", rmp, sd.Wall, gbFloat(pl.w), gbFloat(pl.h)),
+	}
+
+	// 窓
+	wall := area
+	for _, op := range s.Openings {
+		switch op.OpeningType {
+		case "FixedWindow", "OperableWindow", "FixedSkylight", "OperableSkylight", "SlidingDoor":
+		case "NonSlidingDoor":
+			im.warnf("opening %q (%s) is included in the surface %q", op.ID, op.OpeningType, s.ID)
+			continue
+		default:
+			im.warnf("opening %q: openingType %q is not supported; skipped", op.ID, op.OpeningType)
+			continue
+		}
+		ops := im.points(op.Points)
+		_, oa := gbNewell(ops)
+		if len(ops) < 3 || oa == 0 {
+			im.warnf("opening %q has no PolyLoop; skipped", op.ID)
+			continue
+		}
+		wd := im.name("RMP", op.Name, op.ID)
+		wall -= oa
+		rm.Surfaces = append(rm.Surfaces, &Surface{
+			Ble:    BLE_Window,
+			Wall:   im.window(op.WindowTypeRef),
+			Area:   gbRound(oa, 3),
+			Params: Params{"rmp": wd},
+		})
+		x, y, ww, wh := pl.bounds(ops)
+		lines = append(lines, fmt.Sprintf("\t\tWD %s -xyr %s %s -WH %s %s ;", wd, gbFloat(x), gbFloat(y), gbFloat(ww), gbFloat(wh)))
+	}
+	if wall <= 0 {
+		im.warnf("surface %q: openings are larger than the surface", s.ID)
+		wall = 0
+	}
+	sd.Area = gbRound(wall, 3)
+
+	im.coordnt = append(im.coordnt, append(lines, "*")...)
+}
+
+// ground は地盤に接する面を室 rm の部位に変換します。外表面は earth（地中深さ 1.5 m）とします。
+func (im *gbImporter) ground(s gbSurface, rm *Room, ble BLEType, area float64) {
+	if im.exsrf["earth"] == "" {
+		im.exsrf["earth"] = im.name("EXSRF", "earth", "earth")
+		im.model.ExternalSurfaces = append(im.model.ExternalSurfaces,
+			&ExternalSurface{Name: im.exsrf["earth"], Params: Params{"Z": "1.5"}})
+	}
+	rm.Surfaces = append(rm.Surfaces, &Surface{
+		Exsrf: im.exsrf["earth"],
+		Ble:   ble,
+		Wall:  im.wall(ble, s.ConstructionRef, s.ID, false),
+		Area:  gbRound(area, 3),
+	})
+	if len(s.Openings) > 0 {
+		im.warnf("surface %q: openings in %s are not supported; skipped", s.ID, s.SurfaceType)
+	}
+}
+
+// interior は室の間の面を両方の室の部位に変換します。
+// 床、天井は、法線（最初の室から外向き）が下向きの場合に最初の室の床とします。
+func (im *gbImporter) interior(s gbSurface, rooms []*Room, normal XYZ, area float64) {
+	bles := []BLEType{BLE_InnerWall, BLE_InnerWall}
+	reverse := false
+	if s.SurfaceType != "InteriorWall" {
+		if normal.Z < 0 {
+			bles = []BLEType{BLE_InnerFloor, BLE_Ceil}
+			reverse = true // 層を上側から並べる
+		} else {
+			bles = []BLEType{BLE_Ceil, BLE_InnerFloor}
+		}
+	}
+	if len(rooms) < 2 && !s.adiabatic {
+		im.warnf("surface %q (%s) has only one adjacent space; imported without r=", s.ID, s.SurfaceType)
+	}
+	for i, rm := range rooms[:min(len(rooms), 2)] {
+		sd := &Surface{
+			Ble:  bles[i],
+			Wall: im.wall(bles[i], s.ConstructionRef, s.ID, reverse),
+			Area: gbRound(area, 3),
+		}
+		if len(rooms) > 1 {
+			sd.NextRoom = rooms[1-i].Name
+		}
+		rm.Surfaces = append(rm.Surfaces, sd)
+	}
+	if len(s.Openings) > 0 {
+		im.warnf("surface %q: openings in %s are not supported; skipped", s.ID, s.SurfaceType)
+	}
+}
+
+// shade は Shade を OBS の長方形の障害物 (rect) に変換します。
+func (im *gbImporter) shade(s gbSurface, pts []XYZ, normal XYZ, area float64) {
+	azimuth, tilt := gbOrientation(normal)
+	pl := gbRect(pts, azimuth, tilt)
+	if math.Abs(pl.w*pl.h-area) > 0.01*area {
+		im.warnf("shade %q is not a rectangle; approximated by %sx%s m", s.ID, gbFloat(pl.w), gbFloat(pl.h))
+	}
+	im.obs = append(im.obs, fmt.Sprintf("rect %s -xyz %s %s %s -WH %s %s -WaWb %s %s ;", im.name("RMP", s.Name, s.ID),
+		gbFloat(pl.o.X), gbFloat(pl.o.Y), gbFloat(pl.o.Z), gbFloat(pl.w), gbFloat(pl.h), modelFloat(azimuth), modelFloat(tilt)))
+}
+
+// externalSurface は方位角 azimuth、傾斜角 tilt の外表面名を返します。
+// 鉛直面の東西南北は east、west、south、north、水平面は Hor とします。
+func (im *gbImporter) externalSurface(azimuth, tilt float64) string {
+	key := modelFloat(azimuth) + "/" + modelFloat(tilt)
+	if name, ok := im.exsrf[key]; ok {
+		return name
+	}
+	e := &ExternalSurface{Azimuth: azimuth}
+	a := int(math.Round(math.Mod(azimuth+360, 360)))
+	switch {
+	case tilt == 0:
+		e.Name, e.Azimuth = "Hor", 0
+	case tilt == 90:
+		switch a {
+		case 0:
+			e.Name = "south"
+		case 90:
+			e.Name = "west"
+		case 180:
+			e.Name = "north"
+		case 270:
+			e.Name = "east"
+		default:
+			e.Name = fmt.Sprintf("a%d", a)
+		}
+	default:
+		e.Name = fmt.Sprintf("a%dt%d", a, int(math.Round(tilt)))
+		e.Params = Params{"t": modelFloat(tilt)}
+	}
+	e.Name = im.name("EXSRF", e.Name, e.Name)
+	im.exsrf[key] = e.Name
+	im.model.ExternalSurfaces = append(im.model.ExternalSurfaces, e)
+	return e.Name
+}
+
+// wall は Construction の id の部位 ble の壁体名を返します。
+// gbXML の層は屋外側（内壁、床、天井は最初の室の反対側）からの順です。
+// 天井、屋根の壁体は屋外側から、その他は室内側から並べます。reverse の場合は逆にします。
+func (im *gbImporter) wall(ble BLEType, id, surface string, reverse bool) string {
+	key := string(ble) + "/" + id
+	if ble == BLE_Ceil || ble == BLE_InnerFloor {
+		// 床と天井は相互に参照できる。層は上側からの順とする
+		key = "c/" + id
+	}
+	if name, ok := im.walls[key]; ok {
+		return name
+	}
+
+	var c *gbConstruction
+	for i := range im.doc.Constructions {
+		if im.doc.Constructions[i].ID == id {
+			c = &im.doc.Constructions[i]
+		}
+	}
+	if c == nil {
+		im.warnf("surface %q: construction %q not found", surface, id)
+		c = &gbConstruction{ID: id}
+	}
+
+	wl := &Wall{Ble: ble, Name: im.name("WALL/"+key[:1], c.Name, c.ID)}
+	if ble == BLE_ExternalWall || ble == BLE_Roof || ble == BLE_Floor {
+		wl.Params = c.params
+	}
+	for _, lr := range c.Layers {
+		for _, l := range im.doc.Layers {
+			if l.ID != lr.Layer {
+				continue
+			}
+			for _, mr := range l.Materials {
+				wl.Layers = append(wl.Layers, im.material(mr.Material))
+			}
+		}
+	}
+	if len(wl.Layers) == 0 {
+		im.warnf("construction %q has no layers", c.ID)
+	}
+	inside := ble != BLE_Roof && ble != BLE_Ceil
+	if inside != reverse {
+		for i, j := 0, len(wl.Layers)-1; i < j; i, j = i+1, j-1 {
+			wl.Layers[i], wl.Layers[j] = wl.Layers[j], wl.Layers[i]
+		}
+	}
+	im.walls[key] = wl.Name
+	im.model.Walls = append(im.model.Walls, wl)
+	return wl.Name
+}
+
+// material は Material の id の層を返します。材料コードは wbmlist.efl の材料コード、材料名、
+// gbMaterialKeywords の順に照合します。対応する材料がない場合は、報告して材料名をそのまま用います。
+func (im *gbImporter) material(id string) Layer {
+	var mat gbMaterial
+	found := false
+	for _, mt := range im.doc.Materials {
+		if mt.ID == id {
+			mat, found = mt, true
+		}
+	}
+	if !found {
+		im.warnf("material %q not found", id)
+		mat.ID = id
+	}
+
+	var l Layer
+	if t, ok := mat.Thickness.float(); ok && t > 0 {
+		scale, ok := gbLengthUnits[mat.Thickness.Unit]
+		if !ok {
+			im.warnf("material %q: unknown thickness unit %q", id, mat.Thickness.Unit)
+			scale = 1
+		}
+		l.Thickness = gbRound(t*scale*1000, 1)
+	}
+
+	if code, ok := im.materials[id]; ok {
+		l.Material = code
+		return l
+	}
+	name := strings.TrimSpace(mat.Name)
+	l.Material = im.matchMaterial(name, mat.ID)
+	if l.Material == "" {
+		im.warnf("material %q (%s) is not in wbmlist.efl", name, mat.ID)
+		l.Material = im.name("MATERIAL", name, mat.ID)
+	}
+	im.materials[id] = l.Material
+	return l
+}
+
+// matchMaterial は材料名 name、id に対応する wbmlist.efl の材料コードを返します。ない場合は空です。
+func (im *gbImporter) matchMaterial(name, id string) string {
+	for _, s := range []string{name, id} {
+		for _, e := range im.wbm {
+			if s != "" && strings.EqualFold(e.Code, s) {
+				return e.Code
+			}
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	for _, e := range im.wbm {
+		if e.Name == name {
+			return e.Code
+		}
+	}
+	for _, e := range im.wbm {
+		if e.Name != "" && (strings.Contains(name, e.Name) || strings.Contains(e.Name, name)) {
+			return e.Code
+		}
+	}
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), " ") + " "
+	for _, k := range gbMaterialKeywords {
+		if !strings.Contains(words, " "+k.word+" ") {
+			continue
+		}
+		for _, e := range im.wbm {
+			if e.Code == k.code {
+				return e.Code
+			}
+		}
+	}
+	return ""
+}
+
+// window は WindowType の id の窓名を返します。WindowType がない場合は単板ガラス相当の窓とします。
+func (im *gbImporter) window(id string) string {
+	if name, ok := im.windows[id]; ok {
+		return name
+	}
+	var wt *gbWindowType
+	for i := range im.doc.WindowTypes {
+		if im.doc.WindowTypes[i].ID == id {
+			wt = &im.doc.WindowTypes[i]
+		}
+	}
+	win := &Window{T: 0.79, B: 0.04, R: gbRound(math.Max(1/6.0-gbFilmResistance, 0), 3)}
+	if wt == nil {
+		if id != "" {
+			im.warnf("window type %q not found", id)
+		}
+		win.Name = im.name("WINDOW", "Window", "Window")
+	} else {
+		win.Name = im.name("WINDOW", wt.Name, wt.ID)
+		shgc, hasSHGC := wt.SHGC.float()
+		if u, ok := wt.UValue.float(); ok && u > 0 {
+			win.R = gbRound(math.Max(1/u-gbFilmResistance, 0), 3)
+		} else {
+			im.warnf("window type %q has no U-value", wt.ID)
+		}
+		tau, hasTau := 0.0, false
+		for _, t := range wt.Transmittance {
+			if t.Type == "Solar" {
+				tau, hasTau = t.float()
+			}
+		}
+		switch {
+		case hasTau && hasSHGC:
+			win.T, win.B = tau, gbRound(math.Max(shgc-tau, 0), 3)
+		case hasTau:
+			win.T = tau
+		case hasSHGC:
+			win.T, win.B = shgc, 0
+		default:
+			im.warnf("window type %q has no SolarHeatGainCoeff", wt.ID)
+		}
+	}
+	im.windows[id] = win.Name
+	im.model.Windows = append(im.model.Windows, win)
+	return win.Name
+}
+
+// gbPlane は COORDNT の BDP、OBS の rect と同じ面の座標系です。
+// o は長方形の左下の頂点、u、v は屋外から見て右向き、上向きの単位ベクトルです。
+type gbPlane struct {
+	o, u, v XYZ
+	w, h    float64
+}
+
+// gbRect は方位角 azimuth、傾斜角 tilt の多角形 ps の面内の外接長方形を返します。
+func gbRect(ps []XYZ, azimuth, tilt float64) gbPlane {
+	a, t := azimuth*math.Pi/180, tilt*math.Pi/180
+	n := XYZ{-math.Sin(t) * math.Sin(a), -math.Sin(t) * math.Cos(a), math.Cos(t)}
+	pl := gbPlane{o: ps[0], u: XYZ{math.Cos(a), -math.Sin(a), 0}}
+	pl.v = gbCross(n, pl.u)
+	x, y, w, h := pl.bounds(ps)
+	pl.o = XYZ{ps[0].X + x*pl.u.X + y*pl.v.X, ps[0].Y + x*pl.u.Y + y*pl.v.Y, ps[0].Z + x*pl.u.Z + y*pl.v.Z}
+	pl.w, pl.h = w, h
+	return pl
+}
+
+// bounds は多角形 ps の面内の外接長方形の左下の頂点の座標 x、y と巾 w、高さ h を返します。
+func (pl gbPlane) bounds(ps []XYZ) (x, y, w, h float64) {
+	x0, y0, x1, y1 := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range ps {
+		d := XYZ{p.X - pl.o.X, p.Y - pl.o.Y, p.Z - pl.o.Z}
+		px, py := gbDot(d, pl.u), gbDot(d, pl.v)
+		x0, y0, x1, y1 = math.Min(x0, px), math.Min(y0, py), math.Max(x1, px), math.Max(y1, py)
+	}
+	return x0, y0, x1 - x0, y1 - y0
+}
+
+// gbNewell は多角形 ps の単位法線ベクトル（頂点が反時計回りに見える側）と面積を返します。
+func gbNewell(ps []XYZ) (XYZ, float64) {
+	var n XYZ
+	for i, p := range ps {
+		q := ps[(i+1)%len(ps)]
+		n.X += (p.Y - q.Y) * (p.Z + q.Z)
+		n.Y += (p.Z - q.Z) * (p.X + q.X)
+		n.Z += (p.X - q.X) * (p.Y + q.Y)
+	}
+	l := math.Sqrt(gbDot(n, n))
+	if l == 0 {
+		return n, 0
+	}
+	return XYZ{n.X / l, n.Y / l, n.Z / l}, l / 2
+}
+
+// gbSignedVolume は原点と多角形 ps による錐体の符号付き体積です。閉じた面の和が体積になります。
+func gbSignedVolume(ps []XYZ) float64 {
+	v := 0.0
+	for i := 1; i+1 < len(ps); i++ {
+		v += gbDot(ps[0], gbCross(ps[i], ps[i+1]))
+	}
+	return v / 6
+}
+
+// gbOrientation は外向きの法線 n の方位角（南を0、西回りを正）と傾斜角 [°] を 0.1° 単位で返します。
+func gbOrientation(n XYZ) (azimuth, tilt float64) {
+	tilt = gbRound(math.Acos(math.Max(-1, math.Min(1, n.Z)))*180/math.Pi, 1)
+	if tilt == 0 || tilt == 180 {
+		return 0, tilt
+	}
+	azimuth = gbRound(math.Atan2(-n.X, -n.Y)*180/math.Pi, 1)
+	if azimuth <= -180 {
+		azimuth += 360
+	}
+	return azimuth, tilt
+}
+
+func gbDot(a, b XYZ) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func gbCross(a, b XYZ) XYZ {
+	return XYZ{a.Y*b.Z - a.Z*b.Y, a.Z*b.X - a.X*b.Z, a.X*b.Y - a.Y*b.X}
+}
+
+// gbRound は v を小数点以下 digits 桁に丸めます。
+func gbRound(v float64, digits int) float64 {
+	p := math.Pow10(digits)
+	r := math.Round(v*p) / p
+	if r == 0 {
+		return 0 // -0 を除く
+	}
+	return r
+}
+
+// gbFloat は座標、寸法 [m] を mm 単位に丸めた文字列を返します。
+func gbFloat(v float64) string {
+	return modelFloat(gbRound(v, 3))
+}
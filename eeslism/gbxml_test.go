@@ -0,0 +1,121 @@
+package eeslism
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestImportGbXML は gbXML の室、部位、壁体、窓、座標の変換と、材料の照合を確認する
+func TestImportGbXML(t *testing.T) {
+	f, err := os.Open("testdata/two_rooms.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, warnings, err := ImportGbXML(f, "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\tTwo Room House ;\n",
+		"\tsouth a=0 ;\n\tnorth a=180 ;\n\twest a=90 ;\n\tHor a=0 ;\n\tearth a=0 Z=1.5 ;\n\teast a=-90 ;\n",
+		"\t-E:Exterior_Wall GPB-12 FPS-50 RC-150 ;\n", // 室内側から
+		"\t-i:Partition GPB-12 as1 GPB-12 ;\n",
+		"\t-R:Roof FPS-50 GPB-12 ;\n", // 屋外側から
+		"\t-F:Slab Acme_Screed-50 RC-150 ;\n",
+		"\tDouble_Glazing t=0.6 B=0.1 R=0.19 ;\n",
+		"\tLiving_Room Vol=60\n\t\tsouth: -E Exterior_Wall 12 rmp=sf-1 ;\n\t\t-W Double_Glazing 3 rmp=Living_South_Window ;\n",
+		"\t\t-i Partition 12 r=Bedroom ;\n",
+		"\t\tearth: -F Slab 20 ;\n",
+		"\tBedroom Vol=36\n\t\t(Living_Room): -i Partition 12 r=Living_Room ;\n",
+		"\tBDP sf-1 -xyz 0 0 0 -WA 0 -WB 90 -WH 5 3 ;\n\t\tRMP sf-1 Exterior_Wall -xyb 0 0 -WH 5 3 ;\n\t\t\tWD Living_South_Window -xyr 1 1 -WH 2 1.5 ;\n\t*\n",
+		"\tBDP sf-2 -xyz 5 4 0 -WA 180 -WB 90 -WH 5 3 ;\n",
+		"\tBDP sf-5 -xyz 0 0 3 -WA 0 -WB 0 -WH 5 4 ;\n",
+		"OBS\n\trect sf-12 -xyz 1 -0.6 2.7 -WH 2 0.6 -WaWb 0 0 ;\n*\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, b.String())
+		}
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], `"door-1"`) ||
+		warnings[1] != `material "Acme Screed" (m-unknown) is not in wbmlist.efl` {
+		t.Errorf("warnings: %q", warnings)
+	}
+
+	// 対応する材料を指定し、最小構成の機器を加えると、日影、形態係数の計算を含めて計算できる
+	for _, wl := range m.Walls {
+		for i := range wl.Layers {
+			if wl.Layers[i].Material == "Acme_Screed" {
+				wl.Layers[i].Material = "MOL"
+			}
+		}
+	}
+	m.Weather = "tokyo_3column_SI.has"
+	m.Start, m.End = MonthDay{7, 1}, MonthDay{7, 2}
+	m.PrintStart = MonthDay{7, 2}
+	m.Catalog = []*Equipment{{Type: "BOI", Name: "minboi", Params: Params{"Qo": "100"}}}
+	m.Components = []*Component{{Name: "MinBoiler", Catalog: "minboi"}}
+	m.Paths = []*Path{{Name: "MinPath", Sys: "A", Fluid: "W",
+		Branches: []*Branch{{Flow: "0.01", Elements: []string{"MinBoiler"}}}}}
+	m.DaySchedules = []*DaySchedule{{Name: "Stop", Switch: true, Periods: []SchedulePeriod{{Start: 0, End: 2400, Mode: OFF_SW}}}}
+	m.Controls = []*Control{{Set: []Setting{{"MinPath", "Stop"}}}}
+	sim, err := m.Simulation("house.txt", "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(MemorySink)
+	sim.Output = out
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out.Bytes("house_rm.es")), "Bedroom") {
+		t.Error("room output not found")
+	}
+}
+
+// TestImportGbXMLErrors は読み込めない gbXML の誤りを確認する
+func TestImportGbXMLErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"<gbXML", "gbXML: XML syntax error"},
+		{`<gbXML lengthUnit="Furlongs"/>`, `unknown lengthUnit "Furlongs"`},
+		{`<gbXML><Campus/></gbXML>`, "gbXML: no Space"},
+	}
+	for _, tt := range tests {
+		_, _, err := ImportGbXML(strings.NewReader(tt.src), "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+// TestGbOrientation は外向きの法線から方位角（南を0、西回りを正）と傾斜角を求めることを確認する
+func TestGbOrientation(t *testing.T) {
+	s := 1 / 2.0
+	tests := []struct {
+		n             XYZ
+		azimuth, tilt float64
+	}{
+		{XYZ{0, -1, 0}, 0, 90},
+		{XYZ{-1, 0, 0}, 90, 90},
+		{XYZ{1, 0, 0}, -90, 90},
+		{XYZ{0, 1, 0}, 180, 90},
+		{XYZ{0, 0, 1}, 0, 0},
+		{XYZ{0, 0, -1}, 0, 180},
+		{XYZ{0, -s, 0.8660254037844386}, 0, 30},
+	}
+	for _, tt := range tests {
+		a, tilt := gbOrientation(tt.n)
+		if a != tt.azimuth || tilt != tt.tilt {
+			t.Errorf("gbOrientation(%v) = %v, %v, want %v, %v", tt.n, a, tilt, tt.azimuth, tt.tilt)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+/*
+gltf.go (Shading Geometry Export)
+
+日影計算に用いる面（LP_COORDNT、OP_COORDNT で座標変換した後の LP、MP）を、
+汎用の 3D ビューアで確認できる glTF 2.0（WriteGLTF）、Wavefront OBJ（WriteOBJ）に書き出します。
+HOUSING_PLACE の _placeALL.gchi と同じ面を出力し、長時間の計算の前に建物、障害物の配置を確認するために用います。
+
+  - 面ごとに1つのノード（OBJ では1つのオブジェクト）とし、名前は面の名前（P_MENN.opname）とします。
+  - 色は面の種類（P_MENN.kind）ごとに決まり、マテリアル名は種類の名前とします。
+    RMP: 建物の受照面、WD: 窓、SBLK: 付設障害物（庇、袖壁など）、OBS: 外部障害物、
+    TREE: 樹木、POLYGON: 多角形の直接入力。
+  - EESLISM の座標（X: 東、Y: 北、Z: 上）は、glTF、OBJ の慣例に合わせて Y 軸を上とする座標（X: 東、Y: 上、Z: 南）に変換します。
+  - 窓は壁と同じ平面にあるため、表示がちらつかないように法線方向に windowOffset だけずらします。
+
+Simulation.GeometryShadow が true の場合は、詳細出力日（GDAT PRINT）の太陽が出ている時間ステップごとに
+MP の日影面積率を記録し、glTF に時刻ごとのシーン（名前は `月/日 時:分`）を追加します。
+シーンの MP の頂点色（COLOR_0）は日影面積率に応じて暗くなり、ノードの extras.shadow に日影面積率を出力します。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// 面の種類 (P_MENN.kind)
+const (
+	PlaneRMP     = "RMP"     // 建物の受照面（BDP の RMP）
+	PlaneWD      = "WD"      // 窓（RMP の WD）
+	PlaneSBLK    = "SBLK"    // 付設障害物（BDP の SBLK）
+	PlaneOBS     = "OBS"     // 外部障害物
+	PlaneTREE    = "TREE"    // 樹木
+	PlanePOLYGON = "POLYGON" // 多角形の直接入力（POLYGON）
+)
+
+// 面の種類ごとの色 R,G,B,A
+var planeColors = []struct {
+	kind string
+	rgba [4]float64
+}{
+	{PlaneRMP, [4]float64{0.85, 0.85, 0.85, 1.0}},
+	{PlaneWD, [4]float64{0.45, 0.70, 0.90, 0.6}},
+	{PlaneSBLK, [4]float64{0.90, 0.60, 0.25, 1.0}},
+	{PlaneOBS, [4]float64{0.60, 0.55, 0.50, 1.0}},
+	{PlaneTREE, [4]float64{0.25, 0.60, 0.25, 1.0}},
+	{PlanePOLYGON, [4]float64{0.65, 0.50, 0.80, 1.0}},
+}
+
+// 窓を壁の法線方向にずらす距離 [m]
+const windowOffset = 0.005
+
+// 日影面積率が 1 の場合の頂点色の明るさ
+const shadowBrightness = 0.25
+
+// setPlaneKind は pm の面の種類を kind にします。
+func setPlaneKind(pm []*P_MENN, kind string) {
+	for _, p := range pm {
+		p.kind = kind
+	}
+}
+
+// 日影面積率の記録
+type shadowFrame struct {
+	name string    // 月/日 時:分
+	sum  []float64 // MP の日影面積率
+}
+
+// recordShadow は現在の時間ステップの MP の日影面積率を記録します。
+func (sim *Simulation) recordShadow(Daytm *DAYTM) {
+	sum := make([]float64, len(sim.mp))
+	for i, mp := range sim.mp {
+		sum[i] = mp.sum
+	}
+	sim.shadowFrames = append(sim.shadowFrames, shadowFrame{
+		name: fmt.Sprintf("%02d/%02d %02d:%02d", Daytm.Mon, Daytm.Day, Daytm.Ttmm/100, Daytm.Ttmm%100),
+		sum:  sum,
+	})
+}
+
+// 書き出す面
+type geometryPlane struct {
+	name    string
+	kind    string
+	vertex  []XYZ // Y 軸を上とする座標の頂点
+	mpIndex int   // MP のインデックス。LP の場合は -1
+}
+
+// geometryPlanes は LP、MP の順に書き出す面を返します。頂点が3未満の面は除きます。
+func (sim *Simulation) geometryPlanes() ([]geometryPlane, error) {
+	if !sim.initialized {
+		return nil, errors.New("eeslism: geometry export called before Init")
+	}
+	if len(sim.lp)+len(sim.mp) == 0 {
+		return nil, errors.New("eeslism: no COORDNT, OBS, TREE or POLYGON geometry")
+	}
+
+	planes := make([]geometryPlane, 0, len(sim.lp)+len(sim.mp))
+	add := func(p *P_MENN, mpIndex int) {
+		if len(p.P) < 3 {
+			return
+		}
+		var d XYZ
+		if p.kind == PlaneWD {
+			d = XYZ{p.e.X * windowOffset, p.e.Y * windowOffset, p.e.Z * windowOffset}
+		}
+		vertex := make([]XYZ, len(p.P))
+		for i, v := range p.P {
+			vertex[i] = XYZ{geometryRound(v.X + d.X), geometryRound(v.Z + d.Z), geometryRound(-(v.Y + d.Y))}
+		}
+		planes = append(planes, geometryPlane{name: p.opname, kind: p.kind, vertex: vertex, mpIndex: mpIndex})
+	}
+	for _, p := range sim.lp {
+		add(p, -1)
+	}
+	for i, p := range sim.mp {
+		add(p, i)
+	}
+	return planes, nil
+}
+
+// geometryRound は座標を 1/1000 mm に丸めます。座標変換の誤差による -0 は 0 とします。
+func geometryRound(v float64) float64 {
+	return math.Round(v*1e6)/1e6 + 0
+}
+
+// planeMaterial は面の種類 kind のマテリアルのインデックスを返します。
+func planeMaterial(kind string) int {
+	for i, c := range planeColors {
+		if c.kind == kind {
+			return i
+		}
+	}
+	return 0
+}
+
+// glTF 2.0 の JSON の要素のうち、出力に用いるもの
+type gltfDoc struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Materials   []gltfMaterial   `json:"materials"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type gltfScene struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name   string     `json:"name"`
+	Mesh   int        `json:"mesh"`
+	Extras gltfExtras `json:"extras"`
+}
+
+type gltfExtras struct {
+	Type   string   `json:"type"`
+	Shadow *float64 `json:"shadow,omitempty"` // 日影面積率
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Material   int            `json:"material"`
+}
+
+type gltfMaterial struct {
+	Name        string  `json:"name"`
+	PBR         gltfPBR `json:"pbrMetallicRoughness"`
+	AlphaMode   string  `json:"alphaMode,omitempty"`
+	DoubleSided bool    `json:"doubleSided"`
+}
+
+type gltfPBR struct {
+	BaseColorFactor [4]float64 `json:"baseColorFactor"`
+	MetallicFactor  float64    `json:"metallicFactor"`
+	RoughnessFactor float64    `json:"roughnessFactor"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri"`
+}
+
+const (
+	gltfFloat        = 5126  // componentType FLOAT
+	gltfArrayBuffer  = 34962 // target ARRAY_BUFFER
+	gltfBase64Header = "data:application/octet-stream;base64,"
+)
+
+// gltfWriter は glTF の JSON とバッファを組み立てます。
+type gltfWriter struct {
+	doc gltfDoc
+	buf bytes.Buffer
+}
+
+// vec3 は VEC3 の FLOAT のアクセサを追加し、そのインデックスを返します。
+func (g *gltfWriter) vec3(v []XYZ, minmax bool) int {
+	offset := g.buf.Len()
+	min := []float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+	max := []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+	for _, p := range v {
+		f := [3]float32{float32(p.X), float32(p.Y), float32(p.Z)}
+		binary.Write(&g.buf, binary.LittleEndian, f)
+		for i := range f {
+			min[i] = float32(math.Min(float64(min[i]), float64(f[i])))
+			max[i] = float32(math.Max(float64(max[i]), float64(f[i])))
+		}
+	}
+
+	g.doc.BufferViews = append(g.doc.BufferViews, gltfBufferView{
+		ByteOffset: offset, ByteLength: g.buf.Len() - offset, Target: gltfArrayBuffer})
+	a := gltfAccessor{BufferView: len(g.doc.BufferViews) - 1, ComponentType: gltfFloat, Count: len(v), Type: "VEC3"}
+	if minmax {
+		a.Min, a.Max = min, max
+	}
+	g.doc.Accessors = append(g.doc.Accessors, a)
+	return len(g.doc.Accessors) - 1
+}
+
+// mesh はメッシュとそれを参照するノードを追加し、ノードのインデックスを返します。
+func (g *gltfWriter) mesh(p geometryPlane, attributes map[string]int, shadow *float64) int {
+	g.doc.Meshes = append(g.doc.Meshes, gltfMesh{
+		Name:       p.name,
+		Primitives: []gltfPrimitive{{Attributes: attributes, Material: planeMaterial(p.kind)}},
+	})
+	g.doc.Nodes = append(g.doc.Nodes, gltfNode{
+		Name: p.name, Mesh: len(g.doc.Meshes) - 1, Extras: gltfExtras{Type: p.kind, Shadow: shadow}})
+	return len(g.doc.Nodes) - 1
+}
+
+// triangles は多角形を扇形に三角形分割した頂点の並びを返します。
+func triangles(vertex []XYZ) []XYZ {
+	t := make([]XYZ, 0, 3*(len(vertex)-2))
+	for i := 1; i+1 < len(vertex); i++ {
+		t = append(t, vertex[0], vertex[i], vertex[i+1])
+	}
+	return t
+}
+
+/*
+WriteGLTF (Write Shading Geometry as glTF)
+
+Init の後の LP、MP の面を glTF 2.0 の JSON（.gltf、バッファは data URI で埋め込み）で w に書き出します。
+最初のシーン（COORDNT）は全ての面を含み、GeometryShadow が true の場合は記録した時刻ごとのシーンが続きます。
+COORDNT、OBS、TREE、POLYGON のいずれもない場合はエラーを返します。
+*/
+func (sim *Simulation) WriteGLTF(w io.Writer) error {
+	planes, err := sim.geometryPlanes()
+	if err != nil {
+		return err
+	}
+
+	g := new(gltfWriter)
+	g.doc.Asset = gltfAsset{Version: "2.0", Generator: "EESLISM Go"}
+	for _, c := range planeColors {
+		m := gltfMaterial{Name: c.kind, PBR: gltfPBR{BaseColorFactor: c.rgba, RoughnessFactor: 1.0}, DoubleSided: true}
+		if c.rgba[3] < 1.0 {
+			m.AlphaMode = "BLEND"
+		}
+		g.doc.Materials = append(g.doc.Materials, m)
+	}
+
+	// 全ての面
+	position := make([]int, len(planes))
+	scene := gltfScene{Name: "COORDNT"}
+	for i, p := range planes {
+		vertex := triangles(p.vertex)
+		position[i] = g.vec3(vertex, true)
+		scene.Nodes = append(scene.Nodes, g.mesh(p, map[string]int{"POSITION": position[i]}, nil))
+	}
+	g.doc.Scenes = append(g.doc.Scenes, scene)
+
+	// 時刻ごとの日影面積率
+	base := scene.Nodes
+	for _, f := range sim.shadowFrames {
+		scene := gltfScene{Name: f.name}
+		for i, p := range planes {
+			if p.mpIndex < 0 {
+				// LP は日影面積率を求めないため、最初のシーンと同じメッシュのノードとする
+				g.doc.Nodes = append(g.doc.Nodes, g.doc.Nodes[base[i]])
+				scene.Nodes = append(scene.Nodes, len(g.doc.Nodes)-1)
+				continue
+			}
+			sum := f.sum[p.mpIndex]
+			c := 1.0 - (1.0-shadowBrightness)*sum
+			color := make([]XYZ, g.doc.Accessors[position[i]].Count)
+			for j := range color {
+				color[j] = XYZ{c, c, c}
+			}
+			attributes := map[string]int{"POSITION": position[i], "COLOR_0": g.vec3(color, false)}
+			scene.Nodes = append(scene.Nodes, g.mesh(p, attributes, &sum))
+		}
+		g.doc.Scenes = append(g.doc.Scenes, scene)
+	}
+
+	g.doc.Buffers = []gltfBuffer{{
+		ByteLength: g.buf.Len(),
+		URI:        gltfBase64Header + base64.StdEncoding.EncodeToString(g.buf.Bytes()),
+	}}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(&g.doc)
+}
+
+/*
+WriteOBJ (Write Shading Geometry as Wavefront OBJ)
+
+Init の後の LP、MP の面を Wavefront OBJ で obj に、面の種類ごとのマテリアルを MTL で mtl に書き出します。
+mtllib は OBJ から参照する MTL のファイル名です。OBJ には日影面積率を出力しません。
+*/
+func (sim *Simulation) WriteOBJ(obj, mtl io.Writer, mtllib string) error {
+	planes, err := sim.geometryPlanes()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range planeColors {
+		fmt.Fprintf(mtl, "newmtl %s\nKd %.3f %.3f %.3f\nd %.3f\n\n", c.kind, c.rgba[0], c.rgba[1], c.rgba[2], c.rgba[3])
+	}
+
+	fmt.Fprintf(obj, "# EESLISM Go\nmtllib %s\n", mtllib)
+	n := 1
+	for _, p := range planes {
+		fmt.Fprintf(obj, "o %s\nusemtl %s\n", p.name, p.kind)
+		for _, v := range p.vertex {
+			fmt.Fprintf(obj, "v %g %g %g\n", v.X, v.Y, v.Z)
+		}
+		fmt.Fprint(obj, "f")
+		for i := range p.vertex {
+			fmt.Fprintf(obj, " %d", n+i)
+		}
+		fmt.Fprintln(obj)
+		n += len(p.vertex)
+	}
+	return nil
+}
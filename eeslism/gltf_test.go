@@ -0,0 +1,147 @@
+package eeslism
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// geometryTestSimulation は gbXML の2室のモデルに庇、樹木、多角形の障害物を加え、
+// 日影計算を含めて計算できる Simulation を返す
+func geometryTestSimulation(t *testing.T) *Simulation {
+	t.Helper()
+	f, err := os.Open("testdata/two_rooms.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, _, err := ImportGbXML(f, "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wl := range m.Walls {
+		for i := range wl.Layers {
+			if wl.Layers[i].Material == "Acme_Screed" {
+				wl.Layers[i].Material = "MOL"
+			}
+		}
+	}
+	for _, s := range m.Sections {
+		if s.Name == "COORDNT" {
+			s.Lines = append(s.Lines[:1], append([]string{"\tSBLK HISASI Eaves -xy 0.5 2.7 -DW 0.5 4 -a 90 ;"}, s.Lines[1:]...)...)
+		}
+	}
+	m.Sections = append(m.Sections,
+		&Section{Name: "TREE", Lines: []string{"treeA SouthTree -xyz 2 -6 0 -WH1 0.3 2 -WH2 3 1.5 -WH3 4 2 -W4 2 ;"}},
+		&Section{Name: "POLYGON", Lines: []string{"OBS 4 Fence fence -xyz 6 -3 0 8 -3 0 8 -3 1.5 6 -3 1.5 -ref 0.3 ;"}})
+
+	m.Weather = "tokyo_3column_SI.has"
+	m.Start, m.End = MonthDay{7, 1}, MonthDay{7, 1}
+	m.PrintStart = MonthDay{7, 1}
+	m.Catalog = []*Equipment{{Type: "BOI", Name: "minboi", Params: Params{"Qo": "100"}}}
+	m.Components = []*Component{{Name: "MinBoiler", Catalog: "minboi"}}
+	m.Paths = []*Path{{Name: "MinPath", Sys: "A", Fluid: "W",
+		Branches: []*Branch{{Flow: "0.01", Elements: []string{"MinBoiler"}}}}}
+	m.DaySchedules = []*DaySchedule{{Name: "Stop", Switch: true, Periods: []SchedulePeriod{{Start: 0, End: 2400, Mode: OFF_SW}}}}
+	m.Controls = []*Control{{Set: []Setting{{"MinPath", "Stop"}}}}
+	sim, err := m.Simulation("house.txt", "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Output = new(MemorySink)
+	return sim
+}
+
+// TestWriteGLTF は面の名前、種類ごとのマテリアル、時刻ごとの日影面積率のシーンを確認する
+func TestWriteGLTF(t *testing.T) {
+	sim := geometryTestSimulation(t)
+	if err := sim.WriteGLTF(new(bytes.Buffer)); err == nil {
+		t.Error("WriteGLTF before Init should fail")
+	}
+	sim.GeometryShadow = true
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := sim.WriteGLTF(&b); err != nil {
+		t.Fatal(err)
+	}
+	var doc gltfDoc
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Asset.Version != "2.0" || len(doc.Materials) != len(planeColors) {
+		t.Errorf("asset %+v, %d materials", doc.Asset, len(doc.Materials))
+	}
+
+	// 最初のシーンは全ての面を含み、ノードの名前は面の名前
+	kinds := map[string]string{}
+	for _, n := range doc.Scenes[0].Nodes {
+		kinds[doc.Nodes[n].Name] = doc.Nodes[n].Extras.Type
+	}
+	for name, kind := range map[string]string{
+		"sf-1": PlaneRMP, "Living_South_Window": PlaneWD, "Eaves": PlaneSBLK,
+		"sf-12": PlaneOBS, "SouthTree": PlaneTREE, "Fence": PlanePOLYGON,
+	} {
+		if kinds[name] != kind {
+			t.Errorf("node %s: type %q, want %q", name, kinds[name], kind)
+		}
+	}
+
+	// 時刻ごとのシーン（詳細出力日の日中）
+	if len(doc.Scenes) < 2 || doc.Scenes[1].Name != "07/01 05:00" {
+		t.Fatalf("%d scenes, want shadow scenes from 07/01 05:00", len(doc.Scenes))
+	}
+	for _, s := range doc.Scenes[1:] {
+		if len(s.Nodes) != len(doc.Scenes[0].Nodes) {
+			t.Errorf("scene %s: %d nodes", s.Name, len(s.Nodes))
+		}
+		for _, n := range s.Nodes {
+			node := doc.Nodes[n]
+			colored := doc.Meshes[node.Mesh].Primitives[0].Attributes["COLOR_0"] != 0
+			if (node.Extras.Shadow != nil) != colored {
+				t.Errorf("scene %s node %s: shadow %v, COLOR_0 %v", s.Name, node.Name, node.Extras.Shadow, colored)
+			}
+		}
+	}
+
+	// バッファは埋め込みで、アクセサはバッファ内に収まる
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(doc.Buffers[0].URI, gltfBase64Header))
+	if err != nil || len(data) != doc.Buffers[0].ByteLength {
+		t.Fatalf("buffer: %d bytes, byteLength %d, %v", len(data), doc.Buffers[0].ByteLength, err)
+	}
+	for i, a := range doc.Accessors {
+		v := doc.BufferViews[a.BufferView]
+		if v.ByteLength != a.Count*12 || v.ByteOffset+v.ByteLength > len(data) {
+			t.Errorf("accessor %d: count %d, bufferView %+v", i, a.Count, v)
+		}
+	}
+}
+
+// TestWriteOBJ は OBJ の面とマテリアルを確認する
+func TestWriteOBJ(t *testing.T) {
+	sim := geometryTestSimulation(t)
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	var obj, mtl bytes.Buffer
+	if err := sim.WriteOBJ(&obj, &mtl, "house.mtl"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"mtllib house.mtl\n", "o Eaves\nusemtl SBLK\n", "o Living_South_Window\nusemtl WD\n", "o Fence\nusemtl POLYGON\n"} {
+		if !strings.Contains(obj.String(), want) {
+			t.Errorf("OBJ does not contain %q", want)
+		}
+	}
+	// 南面の壁 sf-1 (y=0) は Y 軸を上とする座標で z=0、高さ 3 m
+	if !strings.Contains(obj.String(), "o sf-1\nusemtl RMP\nv 0 0 0\nv 0 3 0\n") {
+		t.Errorf("sf-1 vertices:\n%s", obj.String()[:200])
+	}
+	if !strings.Contains(mtl.String(), "newmtl TREE\nKd 0.250 0.600 0.250\n") {
+		t.Errorf("MTL:\n%s", mtl.String())
+	}
+}
@@ -0,0 +1,545 @@
+/*
+idf.go (EnergyPlus IDF Import)
+
+EnergyPlus の IDF の建物の形状と壁体の構成を Model に変換します（ImportIDF）。
+次のオブジェクトを gbXML の要素に置き換え、gbXML と同じ変換（gbxml.go）を用います。
+
+  - Zone: ROOM の室。室容積は Volume、autocalculate の場合は室を囲む面の座標から求めます。
+  - BuildingSurface:Detailed: 室の部位。Outside Boundary Condition により次のように変換します。
+  - Outdoors: 外壁 (-E)、屋根 (-R)、外気に接する床 (-F)。座標は COORDNT の BDP、RMP、WD とします。
+  - Ground、Foundation など: 外表面 earth（Z=1.5）に接する部位。
+  - Surface、Zone: 内壁 (-i)、床 (-f)、天井 (-c)。隣接する両方の室の部位とし、r= で相互に参照します。
+    対になる2つの面は、先に現れた面のみを用います。
+  - Adiabatic: 隣室を指定しない内壁、床、天井（隣室温度係数 c=1）。
+  - FenestrationSurface:Detailed: 窓 (-W)。Door は部位の一部とします。
+  - Shading:Site:Detailed、Shading:Building:Detailed、Shading:Zone:Detailed: OBS の長方形の障害物 (rect)。
+  - Construction、Material、Material:NoMass、Material:AirGap: 壁体 (WALL)。IDF の材料は、
+    熱伝導率と容積比熱を材料定義リスト（wbmlist.efl の書式）に出力し、WALL の wbmlist= で参照します。
+    熱抵抗のみの材料は、熱抵抗が等しい厚さ 10 mm、空気の容積比熱の層とします
+    （壁体の節点の両側の層の熱容量が 0 の場合は計算できないため）。外気に接する壁体の as=、Eo=、Ei= は屋外側、室内側の層の吸収率とします。
+  - WindowMaterial:SimpleGlazingSystem: 窓 (WINDOW)。U-Factor から熱抵抗 R=、SHGC から日射透過率 t= を求めます。
+
+頂点の座標は GlobalGeometryRules の Vertex Entry Direction、Coordinate System に従います。
+Relative の場合は Zone の原点と Direction of Relative North、Building の North Axis により絶対座標に換算します。
+*/
+package eeslism
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IDFWbmlist は ImportIDF が返すモデルの材料定義リストの既定のファイル名です。
+const IDFWbmlist = "wbmlist_idf.efl"
+
+// 熱抵抗のみの材料（Material:NoMass、Material:AirGap）の層の厚さ [m] と容積比熱 [kJ/m3K]
+const (
+	idfNoMassThickness = 0.01
+	idfNoMassCro       = 1.2
+)
+
+// idfObject は IDF のオブジェクトです。
+type idfObject struct {
+	Class  string   // オブジェクトの種類
+	Fields []string // フィールド（Class を除く）
+}
+
+// field はフィールド i を返します。ない場合は空です。
+func (o idfObject) field(i int) string {
+	if i >= 0 && i < len(o.Fields) {
+		return o.Fields[i]
+	}
+	return ""
+}
+
+// float はフィールド i の数値を返します。空、autocalculate などの場合は ok が false です。
+func (o idfObject) float(i int) (f float64, ok bool) {
+	f, err := strconv.ParseFloat(o.field(i), 64)
+	return f, err == nil
+}
+
+// parseIDF は IDF の内容 text をオブジェクトに分割します。`!` から行末は注釈文です。
+func parseIDF(text string) []idfObject {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.IndexByte(line, '!'); i >= 0 {
+			line = line[:i]
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	var objects []idfObject
+	for _, s := range strings.Split(b.String(), ";") {
+		fields := strings.Split(s, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if fields[0] == "" {
+			continue
+		}
+		objects = append(objects, idfObject{Class: fields[0], Fields: fields[1:]})
+	}
+	return objects
+}
+
+// idfBoundaryConditions は BuildingSurface:Detailed の Outside Boundary Condition の値です。
+// Space Name（EnergyPlus 9.6 以降）のフィールドの有無の判定に用います。
+var idfBoundaryConditions = []string{
+	"Adiabatic", "Surface", "Zone", "Outdoors", "Foundation", "Ground", "GroundFCfactorMethod",
+	"OtherSideCoefficients", "OtherSideConditionsModel",
+	"GroundSlabPreprocessorAverage", "GroundSlabPreprocessorCore", "GroundSlabPreprocessorPerimeter",
+	"GroundBasementPreprocessorAverageWall", "GroundBasementPreprocessorAverageFloor",
+	"GroundBasementPreprocessorUpperWall", "GroundBasementPreprocessorLowerWall",
+}
+
+// idfUnsupported は変換しない形状のオブジェクトです。
+var idfUnsupported = []string{
+	"Wall:Detailed", "RoofCeiling:Detailed", "Floor:Detailed", "Wall:Exterior", "Wall:Adiabatic",
+	"Wall:Underground", "Wall:Interzone", "Roof", "Ceiling:Adiabatic", "Ceiling:Interzone",
+	"Floor:GroundContact", "Floor:Adiabatic", "Floor:Interzone", "Window", "Door", "GlazedDoor",
+	"Window:Interzone", "Door:Interzone", "GlazedDoor:Interzone", "Shading:Site", "Shading:Building",
+	"Shading:Overhang", "Shading:Overhang:Projection", "Shading:Fin", "Shading:Fin:Projection",
+}
+
+// idfZone は Zone の原点と向きです。
+type idfZone struct {
+	name   string
+	north  float64 // Direction of Relative North [°]
+	origin XYZ
+}
+
+// idfImporter は IDF から gbXML の要素への変換の状態です。
+type idfImporter struct {
+	*gbImporter
+	objects     map[string][]idfObject // 種類（大文字）ごとのオブジェクト
+	zones       map[string]*idfZone    // Zone 名（大文字）から Zone
+	surfaces    map[string]idfObject   // BuildingSurface:Detailed の名前（大文字）から面
+	absorptance map[string][2]string   // 材料名（大文字）から長波長、日射の吸収率
+	windowTypes map[string]bool        // 窓の Construction 名（大文字）。SimpleGlazingSystem でないものは false
+	canonical   map[string]string      // 種類と名前（大文字）から IDF の名前。IDF の名前は大文字小文字を区別しない
+	relative    bool                   // 座標が Zone の原点からの相対座標かどうか
+	clockwise   bool                   // 頂点が時計回りかどうか
+	north       float64                // Building の North Axis [°]
+	wbmlist     strings.Builder        // IDF の材料の材料定義リスト
+}
+
+// ImportIDF は EnergyPlus の IDF を読み込み、ROOM、EXSRF、WALL、WINDOW、COORDNT、OBS のモデルに変換します。
+// wbmlist は IDF の材料に EFLファイルのディレクトリ efl_path（空の場合は埋め込みの Base）の
+// wbmlist.efl を加えた材料定義リストです。モデルの WALL は wbmlist= で IDFWbmlist を参照するため、
+// 入力データファイルと同じディレクトリにこの名前で保存するか、Model.Wbmlist を変更してください。
+// 対応しないオブジェクト、長方形でない面を近似したなど、変換の際の注意を warnings に返します。
+func ImportIDF(r io.Reader, efl_path string) (m *Model, wbmlist []byte, warnings []string, err error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if b, err = DecodeText(b, "auto"); err != nil {
+		return nil, nil, nil, err
+	}
+	base, err := fs.ReadFile(decodingFS{eflFS(efl_path), EncodingAuto}, "wbmlist.efl")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	im := &idfImporter{
+		objects:     make(map[string][]idfObject),
+		zones:       make(map[string]*idfZone),
+		surfaces:    make(map[string]idfObject),
+		absorptance: make(map[string][2]string),
+		windowTypes: make(map[string]bool),
+		canonical:   make(map[string]string),
+		relative:    true,
+	}
+	for _, o := range parseIDF(string(b)) {
+		class := strings.ToUpper(o.Class)
+		im.objects[class] = append(im.objects[class], o)
+	}
+
+	title := "IDF"
+	if bd := im.objects["BUILDING"]; len(bd) > 0 {
+		if bd[0].field(0) != "" {
+			title = bd[0].field(0)
+		}
+		im.north, _ = bd[0].float(1)
+	}
+	im.gbImporter = newGbImporter(&gbXML{}, 1, title)
+	if gr := im.objects["GLOBALGEOMETRYRULES"]; len(gr) > 0 {
+		im.clockwise = strings.EqualFold(gr[0].field(1), "Clockwise")
+		switch strings.ToUpper(gr[0].field(2)) {
+		case "", "RELATIVE":
+		case "WORLD", "ABSOLUTE":
+			im.relative = false
+		default:
+			im.warnf("GlobalGeometryRules: unknown Coordinate System %q; Relative is assumed", gr[0].field(2))
+		}
+	}
+
+	// 材料定義リストに材料コードを重複させない
+	for _, e := range readWbmlist(string(base)) {
+		im.name("MATERIAL", e.Code, e.Code)
+	}
+	fmt.Fprintf(&im.wbmlist, "! EnergyPlus IDF の材料\n! 熱伝導率[W/mK] 容積比熱[kJ/m3K] ! 材料名\n")
+
+	im.readMaterials()
+	im.readConstructions()
+	if err := im.readGeometry(); err != nil {
+		return nil, nil, nil, err
+	}
+	for _, class := range idfUnsupported {
+		if n := len(im.objects[strings.ToUpper(class)]); n > 0 {
+			im.warnf("%d %s object(s) are not supported; skipped", n, class)
+		}
+	}
+
+	if err := im.convert(); err != nil {
+		return nil, nil, nil, err
+	}
+	im.model.Wbmlist = IDFWbmlist
+	fmt.Fprintf(&im.wbmlist, "\n! wbmlist.efl\n%s", base)
+	return im.model, []byte(im.wbmlist.String()), im.warnings, nil
+}
+
+// readMaterials は Material、Material:NoMass、Material:AirGap を材料定義リストと gbXML の材料に変換します。
+func (im *idfImporter) readMaterials() {
+	type material struct {
+		class     string
+		thickness int    // 厚さ [m] のフィールド。-1 の場合は熱抵抗のみの材料
+		cond, cro int    // 熱伝導率、密度、比熱または熱抵抗のフィールド
+		abs       [2]int // 長波長、日射の吸収率のフィールド
+	}
+	for _, mc := range []material{
+		{"MATERIAL", 2, 3, 4, [2]int{6, 7}},
+		{"MATERIAL:NOMASS", -1, 2, -1, [2]int{3, 4}},
+		{"MATERIAL:AIRGAP", -1, 1, -1, [2]int{-1, -1}},
+	} {
+		for _, o := range im.objects[mc.class] {
+			name := o.field(0)
+			code := im.name("MATERIAL", strings.NewReplacer("-", "_", "/", "_", "<", "_", ">", "_").Replace(name), "Material")
+			mat := gbMaterial{ID: name, Name: name}
+
+			var cond, cro float64
+			if mc.thickness >= 0 {
+				t, ok1 := o.float(mc.thickness)
+				k, ok2 := o.float(mc.cond)
+				rho, ok3 := o.float(mc.cro)
+				cp, ok4 := o.float(mc.cro + 1)
+				if !ok1 || !ok2 || !ok3 || !ok4 || t <= 0 || k <= 0 {
+					im.warnf("%s %q: invalid Thickness, Conductivity, Density or Specific Heat", o.Class, name)
+					continue
+				}
+				mat.Thickness = gbValue{Unit: "Meters", Value: o.field(mc.thickness)}
+				cond, cro = k, rho*cp/1000
+			} else {
+				rs, ok := o.float(mc.cond)
+				if !ok || rs <= 0 {
+					im.warnf("%s %q: invalid Thermal Resistance", o.Class, name)
+					continue
+				}
+				mat.Thickness = gbValue{Unit: "Meters", Value: modelFloat(idfNoMassThickness)}
+				cond, cro = idfNoMassThickness/rs, idfNoMassCro
+			}
+			fmt.Fprintf(&im.wbmlist, "%s\t%s\t%s\t! %s\n", code,
+				strconv.FormatFloat(cond, 'g', 6, 64), strconv.FormatFloat(cro, 'g', 6, 64), name)
+
+			im.doc.Materials = append(im.doc.Materials, mat)
+			im.doc.Layers = append(im.doc.Layers, gbLayer{ID: name, Materials: []gbRef{{Material: name}}})
+			im.gbImporter.materials[name] = code
+			im.canonical["MATERIAL/"+strings.ToUpper(name)] = name
+			im.absorptance[name] = [2]string{o.field(mc.abs[0]), o.field(mc.abs[1])}
+		}
+	}
+}
+
+// readConstructions は Construction を gbXML の Construction、WindowType に変換します。
+func (im *idfImporter) readConstructions() {
+	glazing := make(map[string]idfObject) // WindowMaterial:SimpleGlazingSystem
+	other := make(map[string]bool)        // その他の WindowMaterial
+	for class, objects := range im.objects {
+		for _, o := range objects {
+			if class == "WINDOWMATERIAL:SIMPLEGLAZINGSYSTEM" {
+				glazing[strings.ToUpper(o.field(0))] = o
+			} else if strings.HasPrefix(class, "WINDOWMATERIAL:") {
+				other[strings.ToUpper(o.field(0))] = true
+			}
+		}
+	}
+
+	for _, o := range im.objects["CONSTRUCTION"] {
+		name := o.field(0)
+		id := strings.ToUpper(name)
+		im.canonical["CONSTRUCTION/"+id] = name
+		var layers []string
+		for _, l := range o.Fields[1:] {
+			if l != "" {
+				layers = append(layers, strings.ToUpper(l))
+			}
+		}
+		if len(layers) == 0 {
+			im.warnf("construction %q has no layers", name)
+			continue
+		}
+
+		// 窓
+		if g, ok := glazing[layers[0]]; ok {
+			if len(layers) > 1 {
+				im.warnf("construction %q: only the WindowMaterial:SimpleGlazingSystem %q is used", name, g.field(0))
+			}
+			im.doc.WindowTypes = append(im.doc.WindowTypes, gbWindowType{
+				ID:     name,
+				Name:   name,
+				UValue: gbValue{Value: g.field(1)},
+				SHGC:   gbValue{Value: g.field(2)},
+			})
+			im.windowTypes[id] = true
+			continue
+		}
+		if other[layers[0]] {
+			im.windowTypes[id] = false
+			continue
+		}
+
+		c := gbConstruction{ID: name, Name: name, params: Params{}}
+		for i, l := range layers {
+			mat, ok := im.canonical["MATERIAL/"+l]
+			if !ok {
+				im.warnf("construction %q: material %q not found", name, o.Fields[i+1])
+				continue
+			}
+			c.Layers = append(c.Layers, gbRef{Layer: mat})
+		}
+		outside := im.absorptance[im.canonical["MATERIAL/"+layers[0]]]
+		inside := im.absorptance[im.canonical["MATERIAL/"+layers[len(layers)-1]]]
+		for _, p := range []struct{ key, value string }{{"as", outside[1]}, {"Eo", outside[0]}, {"Ei", inside[0]}} {
+			if _, err := strconv.ParseFloat(p.value, 64); err == nil {
+				c.params[p.key] = p.value
+			}
+		}
+		im.doc.Constructions = append(im.doc.Constructions, c)
+	}
+}
+
+// readGeometry は Zone、BuildingSurface:Detailed、FenestrationSurface:Detailed、Shading:*:Detailed を
+// gbXML の Space、Surface、Opening に変換します。
+func (im *idfImporter) readGeometry() error {
+	if len(im.objects["ZONE"]) == 0 {
+		return fmt.Errorf("IDF: no Zone")
+	}
+	var spaces []gbSpace
+	for _, o := range im.objects["ZONE"] {
+		z := &idfZone{name: o.field(0)}
+		z.north, _ = o.float(1)
+		z.origin.X, _ = o.float(2)
+		z.origin.Y, _ = o.float(3)
+		z.origin.Z, _ = o.float(4)
+		id := strings.ToUpper(z.name)
+		im.zones[id] = z
+
+		sp := gbSpace{ID: z.name, Name: z.name}
+		if v, ok := o.float(8); ok && v > 0 {
+			sp.Volume = gbValue{Value: o.field(8)}
+		}
+		if n, ok := o.float(6); ok && n != 1 {
+			im.warnf("zone %q: Multiplier %s is ignored", z.name, o.field(6))
+		}
+		spaces = append(spaces, sp)
+	}
+	im.doc.Campus.Buildings = []gbBuilding{{Name: im.model.Title, Spaces: spaces}}
+
+	for _, o := range im.objects["BUILDINGSURFACE:DETAILED"] {
+		im.surfaces[strings.ToUpper(o.field(0))] = o
+	}
+
+	// 窓
+	openings := make(map[string][]gbOpening) // 面の名前（大文字）から窓
+	for _, o := range im.objects["FENESTRATIONSURFACE:DETAILED"] {
+		name := o.field(0)
+		nv := 8 // Number of Vertices
+		if (len(o.Fields)-nv-1)%3 != 0 {
+			nv = 9 // EnergyPlus 8 以前は Shading Control Name がある
+		}
+		base, ok := im.surfaces[strings.ToUpper(o.field(3))]
+		if !ok {
+			im.warnf("fenestration %q: building surface %q not found; skipped", name, o.field(3))
+			continue
+		}
+		op := gbOpening{ID: name, Name: name, OpeningType: o.field(1)}
+		switch strings.ToUpper(o.field(1)) {
+		case "WINDOW":
+			op.OpeningType = "FixedWindow"
+		case "GLASSDOOR":
+			op.OpeningType = "SlidingDoor"
+		case "DOOR":
+			op.OpeningType = "NonSlidingDoor"
+		}
+		construction := strings.ToUpper(o.field(2))
+		if im.windowTypes[construction] {
+			op.WindowTypeRef = im.canonical["CONSTRUCTION/"+construction]
+		} else if op.OpeningType != "NonSlidingDoor" {
+			im.warnf("fenestration %q: construction %q is not a WindowMaterial:SimpleGlazingSystem; single glazing is assumed", name, o.field(2))
+		}
+		if n, ok := o.float(nv - 1); ok && n != 1 {
+			im.warnf("fenestration %q: Multiplier %s is ignored", name, o.field(nv-1))
+		}
+		op.Points = im.points(o, nv+1, im.zones[strings.ToUpper(base.field(3))], true)
+		openings[strings.ToUpper(base.field(0))] = append(openings[strings.ToUpper(base.field(0))], op)
+	}
+
+	// 面
+	types := map[string][3]string{ // 外気、地盤、室の間に接する面の surfaceType
+		"WALL":    {"ExteriorWall", "UndergroundWall", "InteriorWall"},
+		"ROOF":    {"Roof", "UndergroundCeiling", "Ceiling"},
+		"CEILING": {"Roof", "UndergroundCeiling", "Ceiling"},
+		"FLOOR":   {"RaisedFloor", "SlabOnGrade", "InteriorFloor"},
+	}
+	used := make(map[string]bool) // 変換した面
+	for _, o := range im.objects["BUILDINGSURFACE:DETAILED"] {
+		name := o.field(0)
+		id := strings.ToUpper(name)
+		zone, ok := im.zones[strings.ToUpper(o.field(3))]
+		if !ok {
+			im.warnf("surface %q: zone %q not found; skipped", name, o.field(3))
+			continue
+		}
+		typ, ok := types[strings.ToUpper(o.field(1))]
+		if !ok {
+			im.warnf("surface %q: Surface Type %q is not supported; skipped", name, o.field(1))
+			continue
+		}
+
+		bc := 4 // Outside Boundary Condition
+		if !isIDFBoundaryCondition(o.field(bc)) {
+			bc = 5 // EnergyPlus 9.6 以降は Space Name がある
+		}
+		s := gbSurface{
+			ID:              name,
+			Name:            name,
+			ConstructionRef: im.construction(o.field(2)),
+			Adjacent:        []gbRef{{Space: zone.name}},
+			Points:          im.points(o, bc+6, zone, true),
+			Openings:        openings[id],
+		}
+		obc, next := strings.ToUpper(o.field(bc)), strings.ToUpper(o.field(bc+1))
+		switch {
+		case obc == "OUTDOORS":
+			s.SurfaceType = typ[0]
+		case obc == "FOUNDATION" || strings.HasPrefix(obc, "GROUND"):
+			s.SurfaceType = typ[1]
+		case obc == "SURFACE" || obc == "ZONE" || obc == "ADIABATIC":
+			s.SurfaceType = typ[2]
+			if obc == "SURFACE" {
+				if used[next] {
+					continue // 対になる面を変換済み
+				}
+				if t, ok := im.surfaces[next]; !ok {
+					im.warnf("surface %q: outside boundary surface %q not found; imported as adiabatic", name, o.field(bc+1))
+				} else {
+					next = strings.ToUpper(t.field(3))
+				}
+			}
+			if z, ok := im.zones[next]; obc != "ADIABATIC" && ok && z != zone {
+				s.Adjacent = append(s.Adjacent, gbRef{Space: z.name})
+			}
+			s.adiabatic = len(s.Adjacent) == 1
+		default:
+			im.warnf("surface %q: Outside Boundary Condition %q is not supported; skipped", name, o.field(bc))
+			continue
+		}
+		used[id] = true
+		im.doc.Campus.Surfaces = append(im.doc.Campus.Surfaces, s)
+	}
+
+	// 日よけなどの障害物
+	for _, sh := range []struct {
+		class    string
+		vertex   int  // 最初の頂点のフィールド
+		building bool // 建物とともに回転するかどうか
+	}{
+		{"SHADING:SITE:DETAILED", 3, false},
+		{"SHADING:BUILDING:DETAILED", 3, true},
+		{"SHADING:ZONE:DETAILED", 4, true},
+	} {
+		for _, o := range im.objects[sh.class] {
+			var zone *idfZone
+			if sh.class == "SHADING:ZONE:DETAILED" {
+				base, ok := im.surfaces[strings.ToUpper(o.field(1))]
+				if !ok {
+					im.warnf("shading %q: base surface %q not found; skipped", o.field(0), o.field(1))
+					continue
+				}
+				zone = im.zones[strings.ToUpper(base.field(3))]
+			}
+			im.doc.Campus.Surfaces = append(im.doc.Campus.Surfaces, gbSurface{
+				ID:          o.field(0),
+				Name:        o.field(0),
+				SurfaceType: "Shade",
+				Points:      im.points(o, sh.vertex, zone, sh.building),
+			})
+		}
+	}
+	return nil
+}
+
+// construction は Construction 名 name を IDF の名前に揃えます。
+func (im *idfImporter) construction(name string) string {
+	if c, ok := im.canonical["CONSTRUCTION/"+strings.ToUpper(name)]; ok {
+		return c
+	}
+	return name
+}
+
+// isIDFBoundaryCondition は s が Outside Boundary Condition の値かどうかを返します。
+func isIDFBoundaryCondition(s string) bool {
+	for _, bc := range idfBoundaryConditions {
+		if strings.EqualFold(s, bc) {
+			return true
+		}
+	}
+	return false
+}
+
+// points はフィールド start 以降の頂点の座標を絶対座標で返します。
+// 相対座標の場合は Zone z（nil の場合を除く）の原点と向き、building の場合は建物の向きにより換算します。
+func (im *idfImporter) points(o idfObject, start int, z *idfZone, building bool) []gbPoint {
+	var pts []gbPoint
+	for i := start; i+2 < len(o.Fields); i += 3 {
+		var p XYZ
+		var ok [3]bool
+		p.X, ok[0] = o.float(i)
+		p.Y, ok[1] = o.float(i + 1)
+		p.Z, ok[2] = o.float(i + 2)
+		if ok != [3]bool{true, true, true} {
+			im.warnf("%s %q: invalid vertex", o.Class, o.field(0))
+			return nil
+		}
+		if im.relative {
+			if z != nil {
+				p = idfRotate(p, z.north)
+				p = XYZ{p.X + z.origin.X, p.Y + z.origin.Y, p.Z + z.origin.Z}
+			}
+			if building {
+				p = idfRotate(p, im.north)
+			}
+		}
+		pts = append(pts, gbPoint{Coordinates: []float64{p.X, p.Y, p.Z}})
+	}
+	if im.clockwise {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	return pts
+}
+
+// idfRotate は座標 p を z 軸まわりに時計回りに deg [°] 回転します。
+func idfRotate(p XYZ, deg float64) XYZ {
+	if deg == 0 {
+		return p
+	}
+	s, c := math.Sincos(-deg * math.Pi / 180)
+	return XYZ{p.X*c - p.Y*s, p.X*s + p.Y*c, p.Z}
+}
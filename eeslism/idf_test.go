@@ -0,0 +1,138 @@
+package eeslism
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestImportIDF は IDF の Zone、面、窓、壁体の変換と、材料定義リストを用いた計算を確認する
+func TestImportIDF(t *testing.T) {
+	f, err := os.Open("testdata/two_zones.idf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, wbmlist, warnings, err := ImportIDF(f, "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\tTwo Zone Office ;\n",
+		"\tsouth a=0 ;\n\tnorth a=180 ;\n\twest a=90 ;\n\tearth a=0 Z=1.5 ;\n\tHor a=0 ;\n\teast a=-90 ;\n",
+		"WALL\n\twbmlist=wbmlist_idf.efl ;\n",
+		"\t-E:Ext_Wall Ei=0.9 Eo=0.9 as=0.92 1_2IN_Gypsum-12.7 8IN_Concrete_HW-203.3 1IN_Stucco-25.3 ;\n",
+		"\t-i:Int_Wall 1_2IN_Gypsum-12.7 Wall_Air_Space-10 1_2IN_Gypsum-12.7 ;\n",
+		"\t-R:Roof Ei=0.9 Eo=0.9 as=0.7 Roof_Insulation-10 1_2IN_Gypsum-12.7 ;\n",
+		"\tDbl_Clr t=0.7 R=0.19 ;\n",
+		"\tWest_Zone Vol=60\n\t\tsouth: -E Ext_Wall 12 rmp=West_South_Wall ;\n\t\t-W Dbl_Clr 3 rmp=West_South_Window ;\n",
+		"\t\t-i Int_Wall 12 r=East_Zone ;\n",
+		// 対になる面は1つにまとめ、室容積は面の座標から求める
+		"\tEast_Zone Vol=48\n\t\t(West_Zone): -i Int_Wall 12 r=West_Zone ;\n",
+		"\t\teast: -E Ext_Wall 9.6 rmp=East_East_Wall ;\n",
+		"\t\t-c Roof 16 ;\n", // Adiabatic
+		// Zone の原点からの相対座標
+		"\tBDP East_East_Wall -xyz 9 0 0 -WA -90 -WB 90 -WH 4 3 ;\n\t\tRMP East_East_Wall Ext_Wall -xyb 0 0 -WH 4 3 ;\n\t\t\tWD East_East_Window -xyr 1 1 -WH 2 1.2 ;\n",
+		"\trect West_Overhang -xyz 1 0 2.6 -WH 2 0.6 -WaWb 0 180 ;\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, b.String())
+		}
+	}
+	for _, want := range []string{
+		"1IN_Stucco\t0.6918\t1555.15\t! 1IN Stucco\n",
+		"Roof_Insulation\t0.0025\t1.2\t! Roof Insulation\n", // 熱抵抗 4 m2K/W の厚さ 10 mm の層
+		"\n! wbmlist.efl\n",
+		"\nali\t",
+	} {
+		if !strings.Contains(string(wbmlist), want) {
+			t.Errorf("wbmlist does not contain %q", want)
+		}
+	}
+	if len(warnings) != 2 || warnings[0] != "1 Wall:Exterior object(s) are not supported; skipped" ||
+		!strings.Contains(warnings[1], `"East South Door"`) {
+		t.Errorf("warnings: %q", warnings)
+	}
+
+	// 変換したモデル（SYSCMP、SYSPTH のない室のみのモデル）を、入力データファイルと同じディレクトリの
+	// 材料定義リストを用いてそのまま計算できる。気象データファイルは eeslism import の --weather と同じく設定する
+	m.Weather = "tokyo_3column_SI.has"
+	b.Reset()
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "SYSCMP") || strings.Contains(b.String(), "SYSPTH") {
+		t.Fatalf("output has a system:\n%s", b.String())
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "office.txt"), b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, IDFWbmlist), wbmlist, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sim := NewSimulation(filepath.Join(dir, "office.txt"), "../Base")
+	out := new(MemorySink)
+	sim.Output = out
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out.Bytes(filepath.Join(dir, "office_mr.es"))), "East_Zone") {
+		t.Error("room output not found")
+	}
+}
+
+// TestImportIDFGeometryRules は頂点の順序、座標系、建物の向きと Space Name の有無を確認する
+func TestImportIDFGeometryRules(t *testing.T) {
+	const materials = `
+Material, M, Smooth, 0.1, 1, 1000, 1000;
+Construction, C, M;
+`
+	tests := []struct {
+		name, idf, want string
+	}{
+		{
+			// North Axis 90 の建物の南面は西を向く
+			"north axis",
+			`Building, B, 90;
+GlobalGeometryRules, UpperLeftCorner, Counterclockwise, Relative;
+Zone, Z, 0, 10, 0, 0;
+BuildingSurface:Detailed, S, Wall, C, Z, Outdoors, , SunExposed, WindExposed, autocalculate, 4,
+  0, 0, 3,  0, 0, 0,  4, 0, 0,  4, 0, 3;`,
+			"\tBDP S -xyz 0 -10 0 -WA 90 -WB 90 -WH 4 3 ;\n",
+		},
+		{
+			// 時計回りの頂点、絶対座標（Zone の原点は用いない）、Space Name（EnergyPlus 9.6 以降）
+			"clockwise world",
+			`GlobalGeometryRules, UpperLeftCorner, Clockwise, World;
+Zone, Z, 0, 10, 0, 0;
+BuildingSurface:Detailed, S, Wall, C, Z, , Outdoors, , SunExposed, WindExposed, autocalculate, 4,
+  4, 0, 3,  4, 0, 0,  0, 0, 0,  0, 0, 3;`,
+			"\tBDP S -xyz 0 0 0 -WA 0 -WB 90 -WH 4 3 ;\n",
+		},
+	}
+	for _, tt := range tests {
+		m, _, _, err := ImportIDF(strings.NewReader(materials+tt.idf), "")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var b bytes.Buffer
+		if _, err := m.WriteTo(&b); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), tt.want) {
+			t.Errorf("%s: output does not contain %q:\n%s", tt.name, tt.want, b.String())
+		}
+	}
+
+	if _, _, _, err := ImportIDF(strings.NewReader(materials), ""); err == nil || err.Error() != "IDF: no Zone" {
+		t.Errorf("error %v, want IDF: no Zone", err)
+	}
+}
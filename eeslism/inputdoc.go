@@ -0,0 +1,860 @@
+/*
+inputdoc.go (Input Document in JSON/YAML)
+
+入力データファイルを JSON、YAML で表す InputDocument を定義します。
+形式は format/JSON.md と format/input.schema.json を参照してください。
+
+入力データファイルは、データセット（GDAT、ROOM など）と、`;` で終わる論理行の並びです。
+InputDocument はこの構造をそのまま表します。GDAT の FILE、RUN、PRINT、スケジュール（%s、%sn）、
+WALL、ROOM、EQPCAT、SYSCMP、SYSPTH の論理行はデータセットごとの型を持つオブジェクトで表し（ref: inputdoc_sections.go）、
+その他の論理行は先頭の名前、`キーワード=値` のパラメータ、その前後のトークンに分けて保持します。
+データセットの外の `*` と、入力の終わりの `;` のない論理行もそのまま保持します。
+パーサーが読み取るトークンの並びと注釈文（`!` 以降）は失われず、
+入力データファイル → JSON/YAML → 入力データファイルの変換で計算結果は変わりません。
+空白、空行、注釈文の位置（論理行の途中の注釈文は論理行の前に移動する）は保持しません。
+
+拡張子が .json、.yaml、.yml の入力データファイルは、Eesprera が入力データファイルの書式に変換して読み込みます。
+*/
+package eeslism
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// InputDocument は入力データファイルを JSON、YAML で表すデータです。
+type InputDocument struct {
+	Sections []*DocSection `json:"sections" yaml:"sections"`
+}
+
+// DocSection はデータセットです。
+//
+// Name が空の場合は、データセットの外の論理行（`%s`、`%sn` のスケジュール、WEEK など）の並び、
+// または Comment のみの注釈行です。
+type DocSection struct {
+	Name    string `json:"section,omitempty" yaml:"section,omitempty"` // データセット名（GDAT、ROOM など）
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"` // データセット名の行の注釈文
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`     // TITLE の表題
+
+	// 論理行。ROOM、COORDNT 以外のデータセットとデータセットの外の論理行
+	Statements []*Statement `json:"statements,omitempty" yaml:"statements,omitempty"`
+
+	// `*` で終わる論理行のまとまり。ROOM の室、COORDNT の建物面（BDP）
+	Blocks [][]*Statement `json:"blocks,omitempty" yaml:"blocks,omitempty"`
+}
+
+// Statement は `;` で終わる論理行です。
+//
+// データセットごとの型を持つ論理行は File、Run などのいずれか1つ（ROOM の室の最初の論理行は Room と Surface）で表します。
+// その他の論理行のトークンは Name、Args、Params、Opts の順に並びます。`キーワード=値` のトークンが連続しない場合や、
+// 同じキーワードが繰り返される場合は、全てのトークンを Tokens に保持します。
+// トークンがなく Comment のみの場合は注釈行です。
+// Directive は前処理の指令の行（`#include`、`$define`）で、トークンを持ちません。ref: eepreproc.go
+// End はデータセットの外の `*` の行で、トークンを持ちません。
+// Unterminated は入力の終わりの `;` のない論理行（`END` など）です。
+type Statement struct {
+	File        *DocFile        `json:"file,omitempty" yaml:"file,omitempty"`               // GDAT の FILE
+	Run         *DocRun         `json:"run,omitempty" yaml:"run,omitempty"`                 // GDAT の RUN
+	Print       *DocPrint       `json:"print,omitempty" yaml:"print,omitempty"`             // GDAT の PRINT
+	DaySchedule *DocDaySchedule `json:"daySchedule,omitempty" yaml:"daySchedule,omitempty"` // %s -v、%s -s
+	Season      *DocSeason      `json:"season,omitempty" yaml:"season,omitempty"`           // %s -ssn
+	Weekdays    *DocWeekdays    `json:"weekdays,omitempty" yaml:"weekdays,omitempty"`       // %s -wkd
+	Schedule    *DocSchedule    `json:"schedule,omitempty" yaml:"schedule,omitempty"`       // %sn
+	Wall        *DocWall        `json:"wall,omitempty" yaml:"wall,omitempty"`               // WALL
+	Room        *DocRoom        `json:"room,omitempty" yaml:"room,omitempty"`               // ROOM の室
+	Surface     *DocSurface     `json:"surface,omitempty" yaml:"surface,omitempty"`         // ROOM の部位
+	Equipment   *DocEquipment   `json:"equipment,omitempty" yaml:"equipment,omitempty"`     // EQPCAT
+	Component   *DocComponent   `json:"component,omitempty" yaml:"component,omitempty"`     // SYSCMP
+	Path        *DocPath        `json:"path,omitempty" yaml:"path,omitempty"`               // SYSPTH
+
+	Name      string    `json:"name,omitempty" yaml:"name,omitempty"`           // 先頭のトークン（`キーワード=値` でない場合）
+	Args      []string  `json:"args,omitempty" yaml:"args,omitempty"`           // Name と Params の間のトークン
+	Params    ParamList `json:"params,omitempty" yaml:"params,omitempty"`       // `キーワード=値` のトークン
+	Opts      []string  `json:"opts,omitempty" yaml:"opts,omitempty"`           // Params の後のトークン
+	Tokens    []string  `json:"tokens,omitempty" yaml:"tokens,omitempty"`       // 上記に分けられない場合の全てのトークン
+	Directive string    `json:"directive,omitempty" yaml:"directive,omitempty"` // 前処理の指令の行
+	Comment   string    `json:"comment,omitempty" yaml:"comment,omitempty"`
+
+	End          bool `json:"end,omitempty" yaml:"end,omitempty"`                   // データセットの外の `*`
+	Unterminated bool `json:"unterminated,omitempty" yaml:"unterminated,omitempty"` // `;` で終わらない
+}
+
+// Param は `キーワード=値` のトークンです。
+type Param struct {
+	Key, Value string
+}
+
+// ParamList は順序を保持する `キーワード=値` の並びです。JSON、YAML ではオブジェクトとして表します。
+type ParamList []Param
+
+// blockSections は `*` で終わるまとまりを繰り返すデータセットです。空のまとまりで終わります。
+var blockSections = []string{"ROOM", "COORDNT"}
+
+// paramKey は `キーワード=値` のトークン tok のキーワードを返します。該当しない場合は空を返します。
+// 条件式（`(Tr>=20)` など）や比較演算子を含むトークンは該当しません。
+func paramKey(tok string) string {
+	i := strings.IndexByte(tok, '=')
+	if i <= 0 || strings.ContainsAny(tok[:i], "()<>!=") || strings.HasPrefix(tok[i:], "==") {
+		return ""
+	}
+	return tok[:i]
+}
+
+// NewStatement は論理行のトークン tokens（`;` を除く）から Statement を作成します。
+func NewStatement(tokens []string) *Statement {
+	st := new(Statement)
+	i := 0
+	if len(tokens) > 0 && paramKey(tokens[0]) == "" {
+		st.Name = tokens[0]
+		i = 1
+	}
+	for ; i < len(tokens) && paramKey(tokens[i]) == ""; i++ {
+		st.Args = append(st.Args, tokens[i])
+	}
+	for ; i < len(tokens); i++ {
+		key := paramKey(tokens[i])
+		if key == "" {
+			break
+		}
+		if slices.ContainsFunc(st.Params, func(p Param) bool { return p.Key == key }) {
+			return &Statement{Tokens: tokens}
+		}
+		st.Params = append(st.Params, Param{key, tokens[i][len(key)+1:]})
+	}
+	for ; i < len(tokens); i++ {
+		if paramKey(tokens[i]) != "" {
+			return &Statement{Tokens: tokens}
+		}
+		st.Opts = append(st.Opts, tokens[i])
+	}
+	return st
+}
+
+// tokens は論理行のトークン（`;` を除く）を返します。
+func (st *Statement) tokens() []string {
+	if st.Tokens != nil {
+		return st.Tokens
+	}
+	var tokens []string
+	if typed := st.typed(); len(typed) > 0 {
+		for _, t := range typed {
+			tokens = append(tokens, t.tokens()...)
+		}
+		return tokens
+	}
+	if st.Name != "" {
+		tokens = append(tokens, st.Name)
+	}
+	tokens = append(tokens, st.Args...)
+	for _, p := range st.Params {
+		tokens = append(tokens, p.Key+"="+p.Value)
+	}
+	return append(tokens, st.Opts...)
+}
+
+// Param はキーワード key の値を返します。
+func (st *Statement) Param(key string) (string, bool) {
+	for _, p := range st.Params {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// isComment は st が注釈行かどうかを返します。
+func (st *Statement) isComment() bool {
+	return len(st.tokens()) == 0 && st.Directive == "" && !st.End && st.Comment != ""
+}
+
+// isDirective は入力データファイルの行の注釈文を除いた部分 code が前処理の指令かどうかを返します。
+func isDirective(code string) bool {
+	fields := strings.Fields(code)
+	return len(fields) > 0 && (fields[0] == "#include" || fields[0] == "$define")
+}
+
+// ParseInputDocument は入力データファイルの内容を読み込みます。
+func ParseInputDocument(r io.Reader) (*InputDocument, error) {
+	return parseInputDocument(r, nil)
+}
+
+// srcPos は入力データファイルのトークンの位置です。Line、Col は1から数え、Col はバイト単位です。
+type srcPos struct {
+	Line, Col int
+}
+
+// docPositions は InputDocument の論理行、データセットの入力データファイルでの位置です。
+type docPositions struct {
+	stmts    map[*Statement][]srcPos // 論理行のトークン（tokens() の順）の位置
+	sections map[*DocSection]srcPos  // データセット名の位置
+}
+
+// parseInputDocument は入力データファイルの内容を読み込みます。pos が nil でない場合はトークンの位置を記録します。
+func parseInputDocument(r io.Reader, pos *docPositions) (*InputDocument, error) {
+	p := &docParser{doc: new(InputDocument), pos: pos}
+	if pos != nil {
+		pos.stmts = make(map[*Statement][]srcPos)
+		pos.sections = make(map[*DocSection]srcPos)
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for p.lineNo = 1; sc.Scan(); p.lineNo++ {
+		if err := p.line(sc.Text()); err != nil {
+			return nil, fmt.Errorf("eeslism: line %d: %w", p.lineNo, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	p.finish()
+	return p.doc, nil
+}
+
+// docParser は入力データファイルを1行ずつ InputDocument に変換します。
+type docParser struct {
+	doc *InputDocument
+	pos *docPositions // nil の場合は位置を記録しない
+
+	sec        *DocSection  // 読み取り中のデータセット。nil の場合はデータセットの外
+	top        *DocSection  // データセットの外の論理行の並び
+	block      []*Statement // 読み取り中のまとまり（ROOM、COORDNT）
+	inBlock    bool         // block に論理行があるかどうか
+	pending    []string     // 読み取り中の論理行のトークン
+	pendingPos []srcPos     // pending の位置
+	inner      []string     // 読み取り中の論理行の途中の注釈文
+	lastEnded  *string      // この行で終わった論理行、データセット名の注釈文
+	lineNo     int          // 読み取り中の行番号
+}
+
+// fieldIndex は s を空白で区切ったトークンと、その先頭のバイト位置を返します（strings.Fields と同じ区切り）。
+func fieldIndex(s string) ([]string, []int) {
+	var fields []string
+	var offsets []int
+	start := -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fields, offsets = append(fields, s[start:i]), append(offsets, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields, offsets = append(fields, s[start:]), append(offsets, start)
+	}
+	return fields, offsets
+}
+
+// line は入力データファイルの1行を読み取ります。
+func (p *docParser) line(text string) error {
+	code, comment, hasComment := text, "", false
+	for i := 0; i < len(text); i++ {
+		if text[i] == '!' {
+			if i+1 < len(text) && text[i+1] == '=' {
+				i++
+				continue
+			}
+			code, comment, hasComment = text[:i], strings.TrimSpace(text[i+1:]), true
+			break
+		}
+	}
+
+	p.lastEnded = nil
+	if len(p.pending) == 0 && isDirective(code) {
+		p.add(&Statement{Directive: strings.TrimSpace(code), Comment: comment})
+		return nil
+	}
+	fields, offsets := fieldIndex(code)
+	for k, f := range fields {
+		at := srcPos{p.lineNo, offsets[k] + 1}
+		switch {
+		case strings.HasSuffix(f, ";"):
+			if f != ";" {
+				p.token(f[:len(f)-1], k == 0, at)
+			}
+			p.token(";", k == 0 && f == ";", srcPos{at.Line, at.Col + len(f) - 1})
+		case strings.ContainsRune(f, ';'):
+			return fmt.Errorf("invalid position of `;` in %q", f)
+		default:
+			p.token(f, k == 0, at)
+		}
+	}
+
+	if !hasComment || comment == "" {
+		return nil
+	}
+	switch {
+	case len(p.pending) > 0 || (p.sec != nil && p.sec.Name == "TITLE"):
+		p.inner = append(p.inner, comment)
+	case p.lastEnded != nil && *p.lastEnded == "":
+		*p.lastEnded = comment
+	default:
+		p.add(&Statement{Comment: comment})
+	}
+	return nil
+}
+
+// token は位置 at のトークン tok を読み取ります。first は行の先頭のトークンかどうかです。
+func (p *docParser) token(tok string, first bool, at srcPos) {
+	switch {
+	case len(p.pending) > 0 || (p.sec != nil && p.sec.Name == "TITLE"):
+		// 論理行の途中
+	case tok == "*" && first:
+		p.end()
+		return
+	case p.sec == nil && slices.Contains(inputSections, tok):
+		p.top = nil
+		p.sec = &DocSection{Name: tok}
+		p.doc.Sections = append(p.doc.Sections, p.sec)
+		p.lastEnded = &p.sec.Comment
+		if p.pos != nil {
+			p.pos.sections[p.sec] = at
+		}
+		return
+	}
+
+	if tok != ";" {
+		p.pending = append(p.pending, tok)
+		p.pendingPos = append(p.pendingPos, at)
+		return
+	}
+	if p.sec != nil && p.sec.Name == "TITLE" {
+		p.sec.Title = strings.Join(p.pending, " ")
+		p.pending, p.pendingPos = nil, nil
+		p.lastEnded = &p.sec.Comment
+		p.sec = nil
+		p.flushInner()
+		return
+	}
+	st := p.statement(p.pending)
+	if p.pos != nil {
+		p.pos.stmts[st] = p.pendingPos
+	}
+	p.pending, p.pendingPos = nil, nil
+	p.flushInner()
+	p.add(st)
+	p.lastEnded = &st.Comment
+}
+
+// statement は読み取り中のデータセットの論理行のトークン tokens から Statement を作成します。
+// データセットごとの型で表せる論理行は型を持つオブジェクトとします。
+func (p *docParser) statement(tokens []string) *Statement {
+	section := ""
+	if p.sec != nil {
+		section = p.sec.Name
+	}
+	if st := parseTyped(section, tokens, !p.inBlock); st != nil {
+		return st
+	}
+	return NewStatement(tokens)
+}
+
+// flushInner は論理行の途中の注釈文を注釈行として加えます。
+func (p *docParser) flushInner() {
+	for _, c := range p.inner {
+		p.add(&Statement{Comment: c})
+	}
+	p.inner = nil
+}
+
+// add は論理行または注釈行 st を読み取り中のデータセットに加えます。
+func (p *docParser) add(st *Statement) {
+	switch {
+	case p.sec == nil:
+		if st.isComment() {
+			p.top = nil
+			p.doc.Sections = append(p.doc.Sections, &DocSection{Comment: st.Comment})
+			return
+		}
+		if p.top == nil {
+			p.top = new(DocSection)
+			p.doc.Sections = append(p.doc.Sections, p.top)
+		}
+		p.top.Statements = append(p.top.Statements, st)
+	case slices.Contains(blockSections, p.sec.Name):
+		p.block = append(p.block, st)
+		if !st.isComment() {
+			p.inBlock = true
+		}
+	default:
+		p.sec.Statements = append(p.sec.Statements, st)
+	}
+}
+
+// end は行の先頭の `*` を読み取ります。
+func (p *docParser) end() {
+	switch {
+	case p.sec == nil:
+		// データセットの外の `*` はパーサーが読み飛ばすが、入力データファイルの書式に戻すために保持する
+		st := &Statement{End: true}
+		p.add(st)
+		p.lastEnded = &st.Comment
+	case slices.Contains(blockSections, p.sec.Name) && p.inBlock:
+		p.sec.Blocks = append(p.sec.Blocks, p.block)
+		p.block, p.inBlock = nil, false
+	default:
+		// データセットの終わり。論理行のないまとまりの注釈文はデータセットの後に置く
+		comments := p.block
+		p.block, p.inBlock = nil, false
+		p.sec = nil
+		for _, st := range comments {
+			p.add(st)
+		}
+	}
+}
+
+// finish は入力の終わりで読み取り中の論理行、データセットを閉じます。
+func (p *docParser) finish() {
+	if len(p.pending) > 0 {
+		p.token(";", false, srcPos{p.lineNo, 1})
+		if st := p.last(); st != nil {
+			st.Unterminated = true
+		}
+	}
+	p.flushInner()
+	if p.sec != nil && p.inBlock {
+		p.end()
+	}
+	if p.sec != nil {
+		p.end()
+	}
+}
+
+// last は最後に加えた論理行を返します。
+func (p *docParser) last() *Statement {
+	var sts []*Statement
+	switch {
+	case p.sec == nil && p.top != nil:
+		sts = p.top.Statements
+	case p.sec == nil:
+		return nil
+	case slices.Contains(blockSections, p.sec.Name):
+		sts = p.block
+	default:
+		sts = p.sec.Statements
+	}
+	if len(sts) == 0 {
+		return nil
+	}
+	return sts[len(sts)-1]
+}
+
+// WriteTo は入力データファイルの書式で w に出力します。
+func (d *InputDocument) WriteTo(w io.Writer) (int64, error) {
+	mw := &modelWriter{w: w}
+	comment := func(c string) string {
+		if c == "" {
+			return ""
+		}
+		return " ! " + c
+	}
+	statement := func(indent string, st *Statement) {
+		if st.isComment() {
+			mw.printf("%s! %s\n", indent, st.Comment)
+			return
+		}
+		if st.Directive != "" {
+			mw.printf("%s%s%s\n", indent, st.Directive, comment(st.Comment))
+			return
+		}
+		if st.End {
+			mw.printf("%s*%s\n", indent, comment(st.Comment))
+			return
+		}
+		tokens := st.tokens()
+		if !st.Unterminated {
+			tokens = append(tokens, ";")
+		}
+		mw.printf("%s%s%s\n", indent, strings.Join(tokens, " "), comment(st.Comment))
+	}
+
+	for _, sec := range d.Sections {
+		switch {
+		case sec.Name == "":
+			if sec.Comment != "" {
+				mw.printf("! %s\n", sec.Comment)
+			}
+			for _, st := range sec.Statements {
+				statement("", st)
+			}
+			continue
+		case sec.Name == "TITLE":
+			mw.printf("TITLE%s\n\t%s ;\n\n", comment(sec.Comment), sec.Title)
+			continue
+		}
+
+		mw.printf("%s%s\n", sec.Name, comment(sec.Comment))
+		for _, st := range sec.Statements {
+			statement("\t", st)
+		}
+		for _, b := range sec.Blocks {
+			for _, st := range b {
+				statement("\t", st)
+			}
+			mw.printf("\t*\n")
+		}
+		mw.printf("*\n\n")
+	}
+	return mw.n, mw.err
+}
+
+// String は入力データファイルの書式の文字列を返します。
+func (d *InputDocument) String() string {
+	var b strings.Builder
+	d.WriteTo(&b)
+	return b.String()
+}
+
+// Simulation は d を入力データファイル name として読み込むシミュレーションを作成します。
+// name は計算結果のファイル名の基になります。ファイルシステムには書き込みません。
+func (d *InputDocument) Simulation(name, efl_path string) *Simulation {
+	sim := NewSimulation(name, efl_path)
+	sim.FS = overlayFS{files: map[string][]byte{name: []byte(d.String())}, base: osFS{}}
+	return sim
+}
+
+// InputFormat はファイル名 name の拡張子から入力データの書式（"json"、"yaml"、"text"）を返します。
+func InputFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "text"
+}
+
+// ReadInputDocument は書式 format（"json"、"yaml"、"text"）の入力データを読み込みます。
+func ReadInputDocument(r io.Reader, format string) (*InputDocument, error) {
+	d := new(InputDocument)
+	switch format {
+	case "text", "":
+		return ParseInputDocument(r)
+	case "json":
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(d); err != nil {
+			return nil, fmt.Errorf("eeslism: json: %w", err)
+		}
+	case "yaml":
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(d); err != nil {
+			return nil, fmt.Errorf("eeslism: yaml: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("eeslism: unknown input format %q", format)
+	}
+	if err := d.check(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Write は書式 format（"json"、"yaml"、"text"）で w に出力します。
+func (d *InputDocument) Write(w io.Writer, format string) error {
+	switch format {
+	case "text", "":
+		_, err := d.WriteTo(w)
+		return err
+	case "json":
+		return d.writeJSON(w)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("eeslism: unknown input format %q", format)
+}
+
+// check は JSON、YAML から読み込んだ InputDocument を検査します。
+// トークンは空白、`;`、改行を含まず、データセット名の行の先頭の `*` にならないこととします。
+// 型を持つ論理行はデータセットの書式に合うこと（ref: checkTyped）、
+// `*`（end）はデータセットの外、`;` のない論理行（unterminated）は入力の最後に限ります。
+func (d *InputDocument) check() error {
+	checkTokens := func(sec string, tokens []string) error {
+		for _, tok := range tokens {
+			if tok == "" || strings.ContainsAny(tok, " \t\r\n;!") {
+				return &InputError{Section: sec, Keyword: tok, Msg: "invalid token"}
+			}
+		}
+		return nil
+	}
+	var last *Statement // 入力の最後の論理行
+	checkStatements := func(sec string, sts []*Statement) error {
+		first := true // ROOM の室の最初の論理行
+		for _, st := range sts {
+			if st == nil {
+				return &InputError{Section: sec, Msg: "null statement"}
+			}
+			if last != nil && last.Unterminated {
+				return &InputError{Section: sec, Msg: "unterminated statement must be the last statement"}
+			}
+			last = st
+			if st.End && (sec != "" || st.Directive != "" || len(st.tokens()) > 0 || st.Unterminated) {
+				return &InputError{Section: sec, Keyword: "*", Msg: "end is only allowed outside a section and without tokens"}
+			}
+			if st.Unterminated && len(st.tokens()) == 0 {
+				return &InputError{Section: sec, Msg: "unterminated statement must have tokens"}
+			}
+			if err := st.checkTyped(sec, first); err != nil {
+				return err
+			}
+			if !st.isComment() {
+				first = false
+			}
+			if strings.ContainsAny(st.Comment, "\r\n") {
+				return &InputError{Section: sec, Keyword: st.Comment, Msg: "comment must be a single line"}
+			}
+			if st.Directive != "" && (!isDirective(st.Directive) || strings.ContainsAny(st.Directive, "\r\n!") || len(st.tokens()) > 0) {
+				return &InputError{Section: sec, Keyword: st.Directive, Msg: "directive must be a single #include or $define line without tokens"}
+			}
+			if st.Tokens != nil && (st.Name != "" || st.Args != nil || st.Params != nil || st.Opts != nil) {
+				return &InputError{Section: sec, Keyword: st.Name, Msg: "tokens cannot be combined with name, args, params or opts"}
+			}
+			if st.Name != "" && paramKey(st.Name) != "" {
+				return &InputError{Section: sec, Keyword: st.Name, Msg: "name must not be key=value"}
+			}
+			for _, p := range st.Params {
+				if paramKey(p.Key+"=") != p.Key {
+					return &InputError{Section: sec, Keyword: p.Key, Msg: "invalid parameter name"}
+				}
+			}
+			tokens := st.tokens()
+			if err := checkTokens(sec, tokens); err != nil {
+				return err
+			}
+			if len(tokens) > 0 && tokens[0] == "*" {
+				return &InputError{Section: sec, Keyword: "*", Msg: "statement must not start with *"}
+			}
+		}
+		return nil
+	}
+
+	for _, sec := range d.Sections {
+		if sec == nil {
+			return &InputError{Msg: "null section"}
+		}
+		if sec.Name != "" && !slices.Contains(inputSections, sec.Name) {
+			return &InputError{Section: sec.Name, Msg: "unknown section"}
+		}
+		if strings.ContainsAny(sec.Comment+sec.Title, "\r\n") || strings.ContainsAny(sec.Title, ";!") {
+			return &InputError{Section: sec.Name, Msg: "invalid title or comment"}
+		}
+		if sec.Title != "" && sec.Name != "TITLE" {
+			return &InputError{Section: sec.Name, Msg: "title is only allowed in TITLE"}
+		}
+		if len(sec.Blocks) > 0 && !slices.Contains(blockSections, sec.Name) {
+			return &InputError{Section: sec.Name, Msg: "blocks are only allowed in ROOM and COORDNT"}
+		}
+		if len(sec.Statements) > 0 && (sec.Name == "TITLE" || slices.Contains(blockSections, sec.Name)) {
+			return &InputError{Section: sec.Name, Msg: "use title or blocks instead of statements"}
+		}
+		if err := checkStatements(sec.Name, sec.Statements); err != nil {
+			return err
+		}
+		for _, b := range sec.Blocks {
+			if err := checkStatements(sec.Name, b); err != nil {
+				return err
+			}
+		}
+		if sec.Name == "" {
+			for _, st := range sec.Statements {
+				if tokens := st.tokens(); len(tokens) > 0 && slices.Contains(inputSections, tokens[0]) {
+					return &InputError{Keyword: tokens[0], Msg: "statement outside a section must not start with a section name"}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeJSON は論理行を1行ずつにした JSON を w に出力します。
+func (d *InputDocument) writeJSON(w io.Writer) error {
+	var b bytes.Buffer
+	marshal := func(v interface{}) {
+		b.Write(marshalJSON(v))
+	}
+	statements := func(indent string, sts []*Statement) {
+		b.WriteString("[")
+		for i, st := range sts {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n" + indent + "  ")
+			marshal(st)
+		}
+		b.WriteString("\n" + indent + "]")
+	}
+
+	b.WriteString("{\n  \"sections\": [")
+	for i, sec := range d.Sections {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n    {")
+		sep := ""
+		field := func(key string) {
+			b.WriteString(sep + "\n      \"" + key + "\": ")
+			sep = ","
+		}
+		if sec.Name != "" {
+			field("section")
+			marshal(sec.Name)
+		}
+		if sec.Comment != "" {
+			field("comment")
+			marshal(sec.Comment)
+		}
+		if sec.Title != "" {
+			field("title")
+			marshal(sec.Title)
+		}
+		if len(sec.Statements) > 0 {
+			field("statements")
+			statements("      ", sec.Statements)
+		}
+		if len(sec.Blocks) > 0 {
+			field("blocks")
+			b.WriteString("[")
+			for k, blk := range sec.Blocks {
+				if k > 0 {
+					b.WriteString(",")
+				}
+				b.WriteString("\n        ")
+				statements("        ", blk)
+			}
+			b.WriteString("\n      ]")
+		}
+		b.WriteString("\n    }")
+	}
+	b.WriteString("\n  ]\n}\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// marshalJSON は `<`、`>`、`&` をエスケープしない JSON を返します。
+func marshalJSON(v interface{}) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+// MarshalJSON はキーワードの順に並べた JSON のオブジェクトを返します。
+func (l ParamList) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, p := range l {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(marshalJSON(p.Key))
+		b.WriteByte(':')
+		b.Write(marshalJSON(p.Value))
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON は JSON のオブジェクトをキーワードの順に読み込みます。値は文字列または数値です。
+func (l *ParamList) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("params must be an object")
+	}
+	*l = ParamList{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := t.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		switch v := v.(type) {
+		case string:
+			*l = append(*l, Param{key, v})
+		case json.Number:
+			*l = append(*l, Param{key, v.String()})
+		default:
+			return fmt.Errorf("params.%s must be a string or a number", key)
+		}
+	}
+	return nil
+}
+
+// MarshalYAML はキーワードの順に並べた YAML のマッピングを返します。
+func (l ParamList) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	for _, p := range l {
+		var k, v yaml.Node
+		if err := k.Encode(p.Key); err != nil {
+			return nil, err
+		}
+		if err := v.Encode(p.Value); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, &k, &v)
+	}
+	return n, nil
+}
+
+// UnmarshalYAML は YAML のマッピングをキーワードの順に読み込みます。値はスカラーの表記のままとします。
+func (l *ParamList) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: params must be a mapping", n.Line)
+	}
+	*l = ParamList{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind != yaml.ScalarNode || v.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: params must map names to scalar values", k.Line)
+		}
+		*l = append(*l, Param{k.Value, v.Value})
+	}
+	return nil
+}
+
+// MarshalYAML は論理行を1行のフロースタイルのマッピングで返します。
+func (st *Statement) MarshalYAML() (interface{}, error) {
+	type plain Statement
+	n := new(yaml.Node)
+	if err := n.Encode((*plain)(st)); err != nil {
+		return nil, err
+	}
+	n.Style = yaml.FlowStyle
+	return n, nil
+}
+
+// UnmarshalYAML は論理行のマッピングを読み込みます。
+func (st *Statement) UnmarshalYAML(n *yaml.Node) error {
+	type plain Statement
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: statement must be a mapping", n.Line)
+	}
+	if err := checkYAMLFields(n, reflect.TypeOf(plain{})); err != nil {
+		return err
+	}
+	return n.Decode((*plain)(st))
+}
@@ -0,0 +1,1089 @@
+/*
+inputdoc_sections.go (Typed Statements of the Input Document)
+
+InputDocument の論理行のうち、次のデータセットの論理行をデータセットごとの型を持つオブジェクトで表します。
+
+  - GDAT: FILE (DocFile)、RUN (DocRun)、PRINT (DocPrint)
+  - SCHTB、SCHNM とデータセットの外: %s -v、%s -s (DocDaySchedule)、%s -ssn (DocSeason)、%s -wkd (DocWeekdays)、%sn (DocSchedule)
+  - WALL: 壁体 (DocWall)
+  - ROOM: 室 (DocRoom) と部位 (DocSurface)
+  - EQPCAT: 機器カタログ (DocEquipment)
+  - SYSCMP: システム要素 (DocComponent)
+  - SYSPTH: システム経路 (DocPath)
+
+型を持つオブジェクトは、各データセットのパーサー（Gdata、Schtable、Schdata、Walldata、Roomdata、Eqcadata、
+Compodata、Pathdata）が読み取る論理行のトークンに変換されます。
+
+入力データファイルから読み込む場合は、論理行のトークンを型に分け、再びトークンに変換して元の論理行と一致する場合に
+限り型を持つオブジェクトとします。キーワードの順序が標準と異なる場合や、型で表せない指定を含む場合は、
+汎用の論理行（name、args、params、opts、tokens）のままとします。したがって、変換でトークンの並びは変わりません。
+*/
+package eeslism
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// Number は数値のトークンです。入力データファイルの表記（`0.50`、`6.67e-3` など）を保持します。
+// JSON、YAML では数値として表します。JSON の数値の表記でない場合（`.5` など）は文字列とします。
+type Number string
+
+// docNumberRe は数値の表記です。Inf、NaN などは含みません。
+var docNumberRe = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// valid は n が数値の表記かどうかを返します。
+func (n Number) valid() bool {
+	return docNumberRe.MatchString(string(n))
+}
+
+// MarshalJSON は数値の表記をそのまま返します。
+func (n Number) MarshalJSON() ([]byte, error) {
+	if n.valid() && json.Valid([]byte(n)) {
+		return []byte(n), nil
+	}
+	return marshalJSON(string(n)), nil
+}
+
+// UnmarshalJSON は数値または文字列を読み込みます。
+func (n *Number) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*n = Number(s)
+		return nil
+	}
+	var v json.Number
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.New("number must be a number or a string")
+	}
+	*n = Number(v)
+	return nil
+}
+
+// MarshalYAML は数値の表記をそのまま返します。
+func (n Number) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: string(n)}
+	if !n.valid() {
+		node.Tag = "!!str"
+	}
+	return node, nil
+}
+
+// UnmarshalYAML はスカラーの表記をそのまま読み込みます。
+func (n *Number) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: number must be a scalar", node.Line)
+	}
+	*n = Number(node.Value)
+	return nil
+}
+
+// Keyword は `キーワード=値` のトークン、または `*s` などの指定のトークンです。
+type Keyword struct {
+	Key, Value string
+	Flag       bool // `キーワード=値` でない指定。Key がトークン
+}
+
+// Keywords は順序を保持する `キーワード=値` と指定の並びです。
+// JSON、YAML ではオブジェクトとして表し、`キーワード=値` は値（文字列または数値）、指定は true とします。
+type Keywords []Keyword
+
+// parseKeywords は tokens を Keywords に分けます。flag が true を返すトークンを指定とします。
+// 指定でも `キーワード=値` でもないトークン、または同じキーワードを含む場合は false を返します。
+func parseKeywords(tokens []string, flag func(string) bool) (Keywords, bool) {
+	var l Keywords
+	for _, tok := range tokens {
+		k := Keyword{Key: paramKey(tok)}
+		switch {
+		case k.Key != "":
+			k.Value = tok[len(k.Key)+1:]
+		case flag(tok):
+			k.Key, k.Flag = tok, true
+		default:
+			return nil, false
+		}
+		if slices.ContainsFunc(l, func(p Keyword) bool { return p.Key == k.Key }) {
+			return nil, false
+		}
+		l = append(l, k)
+	}
+	return l, true
+}
+
+// tokens は Keywords のトークンを返します。
+func (l Keywords) tokens() []string {
+	tokens := make([]string, len(l))
+	for i, k := range l {
+		if k.Flag {
+			tokens[i] = k.Key
+		} else {
+			tokens[i] = k.Key + "=" + k.Value
+		}
+	}
+	return tokens
+}
+
+// MarshalJSON は順序を保持した JSON のオブジェクトを返します。
+func (l Keywords) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range l {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(marshalJSON(k.Key))
+		b.WriteByte(':')
+		if k.Flag {
+			b.WriteString("true")
+		} else {
+			b.Write(marshalJSON(k.Value))
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON は JSON のオブジェクトを順に読み込みます。値は文字列、数値または true です。
+func (l *Keywords) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("params must be an object")
+	}
+	*l = Keywords{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := t.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		switch v := v.(type) {
+		case string:
+			*l = append(*l, Keyword{Key: key, Value: v})
+		case json.Number:
+			*l = append(*l, Keyword{Key: key, Value: v.String()})
+		case bool:
+			if !v {
+				return fmt.Errorf("params.%s: only true is allowed", key)
+			}
+			*l = append(*l, Keyword{Key: key, Flag: true})
+		default:
+			return fmt.Errorf("params.%s must be a string, a number or true", key)
+		}
+	}
+	return nil
+}
+
+// MarshalYAML は順序を保持した YAML のマッピングを返します。
+func (l Keywords) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	for _, k := range l {
+		var key, v yaml.Node
+		if err := key.Encode(k.Key); err != nil {
+			return nil, err
+		}
+		if k.Flag {
+			v = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+		} else if err := v.Encode(k.Value); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, &key, &v)
+	}
+	return n, nil
+}
+
+// UnmarshalYAML は YAML のマッピングを順に読み込みます。値はスカラーの表記のままとし、true は指定とします。
+func (l *Keywords) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: params must be a mapping", n.Line)
+	}
+	*l = Keywords{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind != yaml.ScalarNode || v.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: params must map names to scalar values", k.Line)
+		}
+		switch {
+		case v.ShortTag() == "!!bool" && v.Value == "true":
+			*l = append(*l, Keyword{Key: k.Value, Flag: true})
+		case v.ShortTag() == "!!bool":
+			return fmt.Errorf("line %d: params.%s: only true is allowed", v.Line, k.Value)
+		default:
+			*l = append(*l, Keyword{Key: k.Value, Value: v.Value})
+		}
+	}
+	return nil
+}
+
+// Option は SYSCMP の `-キーワード` とその後の値のトークンです。
+type Option struct {
+	Key    string // `-` を除くキーワード
+	Values []string
+}
+
+// OptionList は順序を保持する Option の並びです。
+// JSON、YAML ではオブジェクトとして表し、値がない場合は true、1つの場合は文字列、2つ以上の場合は配列とします。
+type OptionList []Option
+
+// MarshalJSON は順序を保持した JSON のオブジェクトを返します。
+func (l OptionList) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, o := range l {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(marshalJSON(o.Key))
+		b.WriteByte(':')
+		switch len(o.Values) {
+		case 0:
+			b.WriteString("true")
+		case 1:
+			b.Write(marshalJSON(o.Values[0]))
+		default:
+			b.Write(marshalJSON(o.Values))
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON は JSON のオブジェクトを順に読み込みます。
+func (l *OptionList) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return errors.New("options must be an object")
+	}
+	*l = OptionList{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		o := Option{Key: t.(string)}
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		switch v := v.(type) {
+		case bool:
+			if !v {
+				return fmt.Errorf("options.%s: only true is allowed", o.Key)
+			}
+		case string:
+			o.Values = []string{v}
+		case json.Number:
+			o.Values = []string{v.String()}
+		case []interface{}:
+			for _, e := range v {
+				switch e := e.(type) {
+				case string:
+					o.Values = append(o.Values, e)
+				case json.Number:
+					o.Values = append(o.Values, e.String())
+				default:
+					return fmt.Errorf("options.%s must be an array of strings", o.Key)
+				}
+			}
+		default:
+			return fmt.Errorf("options.%s must be true, a string or an array", o.Key)
+		}
+		*l = append(*l, o)
+	}
+	return nil
+}
+
+// MarshalYAML は順序を保持した YAML のマッピングを返します。
+func (l OptionList) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	for _, o := range l {
+		var k, v yaml.Node
+		if err := k.Encode(o.Key); err != nil {
+			return nil, err
+		}
+		var err error
+		switch len(o.Values) {
+		case 0:
+			v = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+		case 1:
+			err = v.Encode(o.Values[0])
+		default:
+			err = v.Encode(o.Values)
+			v.Style = yaml.FlowStyle
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, &k, &v)
+	}
+	return n, nil
+}
+
+// UnmarshalYAML は YAML のマッピングを順に読み込みます。
+func (l *OptionList) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: options must be a mapping", n.Line)
+	}
+	*l = OptionList{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: option names must be scalars", k.Line)
+		}
+		o := Option{Key: k.Value}
+		switch {
+		case v.Kind == yaml.ScalarNode && v.ShortTag() == "!!bool":
+			if v.Value != "true" {
+				return fmt.Errorf("line %d: options.%s: only true is allowed", v.Line, o.Key)
+			}
+		case v.Kind == yaml.ScalarNode:
+			o.Values = []string{v.Value}
+		case v.Kind == yaml.SequenceNode:
+			for _, e := range v.Content {
+				if e.Kind != yaml.ScalarNode {
+					return fmt.Errorf("line %d: options.%s must be a sequence of scalars", e.Line, o.Key)
+				}
+				o.Values = append(o.Values, e.Value)
+			}
+		default:
+			return fmt.Errorf("line %d: options.%s must be true, a scalar or a sequence", v.Line, o.Key)
+		}
+		*l = append(*l, o)
+	}
+	return nil
+}
+
+// DocFile は GDAT の FILE です。
+type DocFile struct {
+	W         string `json:"w,omitempty" yaml:"w,omitempty"`                 // 気象データファイル名 (w=)
+	Out       string `json:"out,omitempty" yaml:"out,omitempty"`             // 計算結果出力ファイルセット名 (out=)
+	Station   Number `json:"station,omitempty" yaml:"station,omitempty"`     // 拡張アメダスの地点番号 (station=)
+	Skyrd     bool   `json:"skyrd,omitempty" yaml:"skyrd,omitempty"`         // 気象データは夜間放射量で定義されている (-skyrd)
+	Intgtsupw bool   `json:"intgtsupw,omitempty" yaml:"intgtsupw,omitempty"` // 給水温度を補間する (-intgtsupw)
+}
+
+func parseDocFile(tokens []string) *DocFile {
+	f := new(DocFile)
+	for _, tok := range tokens[1:] {
+		switch key := paramKey(tok); {
+		case key == "w":
+			f.W = tok[2:]
+		case key == "out":
+			f.Out = tok[4:]
+		case key == "station" && Number(tok[8:]).valid():
+			f.Station = Number(tok[8:])
+		case tok == "-skyrd":
+			f.Skyrd = true
+		case tok == "-intgtsupw":
+			f.Intgtsupw = true
+		default:
+			return nil
+		}
+	}
+	return f
+}
+
+func (f *DocFile) tokens() []string {
+	tokens := []string{"FILE"}
+	if f.W != "" {
+		tokens = append(tokens, "w="+f.W)
+	}
+	if f.Out != "" {
+		tokens = append(tokens, "out="+f.Out)
+	}
+	if f.Station != "" {
+		tokens = append(tokens, "station="+string(f.Station))
+	}
+	if f.Skyrd {
+		tokens = append(tokens, "-skyrd")
+	}
+	if f.Intgtsupw {
+		tokens = append(tokens, "-intgtsupw")
+	}
+	return tokens
+}
+
+// DocRun は GDAT の RUN です。日付は `月/日`、実暦による計算の場合は `年/月/日` です。
+type DocRun struct {
+	Warmup   string    `json:"warmup,omitempty" yaml:"warmup,omitempty"`     // 助走計算開始日 (`(月/日)`)
+	Periodic string    `json:"periodic,omitempty" yaml:"periodic,omitempty"` // 周期定常計算を行う日 (-periodic)
+	Start    string    `json:"start,omitempty" yaml:"start,omitempty"`       // 計算開始日
+	End      string    `json:"end,omitempty" yaml:"end,omitempty"`           // 計算終了日
+	Params   ParamList `json:"params,omitempty" yaml:"params,omitempty"`     // Tinit=、dTime=、Stime=、MaxIterate=、RepeatDays=
+}
+
+func parseDocRun(tokens []string) *DocRun {
+	r := new(DocRun)
+	i := 1
+	if i < len(tokens) && strings.HasPrefix(tokens[i], "(") && strings.HasSuffix(tokens[i], ")") {
+		r.Warmup = tokens[i][1 : len(tokens[i])-1]
+		i++
+	}
+	if i+1 < len(tokens) && tokens[i] == "-periodic" {
+		r.Periodic = tokens[i+1]
+		i += 2
+	}
+	if i < len(tokens) && paramKey(tokens[i]) == "" {
+		var ok bool
+		if r.Start, r.End, ok = strings.Cut(tokens[i], "-"); !ok {
+			return nil
+		}
+		i++
+	}
+	for ; i < len(tokens); i++ {
+		key := paramKey(tokens[i])
+		if key == "" {
+			return nil
+		}
+		r.Params = append(r.Params, Param{key, tokens[i][len(key)+1:]})
+	}
+	return r
+}
+
+func (r *DocRun) tokens() []string {
+	tokens := []string{"RUN"}
+	if r.Warmup != "" {
+		tokens = append(tokens, "("+r.Warmup+")")
+	}
+	if r.Periodic != "" {
+		tokens = append(tokens, "-periodic", r.Periodic)
+	}
+	if r.Start != "" || r.End != "" {
+		tokens = append(tokens, r.Start+"-"+r.End)
+	}
+	for _, p := range r.Params {
+		tokens = append(tokens, p.Key+"="+p.Value)
+	}
+	return tokens
+}
+
+// DocPrint は GDAT の PRINT です。
+type DocPrint struct {
+	Days   []string `json:"days,omitempty" yaml:"days,omitempty,flow"`     // 毎時計算結果の出力日（`月/日`、`月/日-月/日`）
+	Output []string `json:"output,omitempty" yaml:"output,omitempty,flow"` // 出力指定（*wd、*rev、*pmv、*helm、*log、*debug）
+}
+
+func parseDocPrint(tokens []string) *DocPrint {
+	p := new(DocPrint)
+	for _, tok := range tokens[1:] {
+		if strings.HasPrefix(tok, "*") {
+			p.Output = append(p.Output, tok)
+		} else if len(p.Output) == 0 && paramKey(tok) == "" {
+			p.Days = append(p.Days, tok)
+		} else {
+			return nil
+		}
+	}
+	return p
+}
+
+func (p *DocPrint) tokens() []string {
+	return append(append([]string{"PRINT"}, p.Days...), p.Output...)
+}
+
+// DocDaySchedule は1日の設定値スケジュール (`%s -v`)、または切換スケジュール (`%s -s`) です。
+type DocDaySchedule struct {
+	Name    string              `json:"name" yaml:"name"`
+	Switch  bool                `json:"switch,omitempty" yaml:"switch,omitempty"` // 切換スケジュール。false の場合は設定値スケジュール
+	Periods []DocSchedulePeriod `json:"periods,omitempty" yaml:"periods,omitempty"`
+}
+
+// DocSchedulePeriod はスケジュールの時間帯 `開始時分-(値)-終了時分` です。
+type DocSchedulePeriod struct {
+	Start string `json:"start" yaml:"start"`                     // 開始時分（hhmm）
+	End   string `json:"end" yaml:"end"`                         // 終了時分（hhmm）
+	Value Number `json:"value,omitempty" yaml:"value,omitempty"` // 設定値（設定値スケジュール）
+	Mode  string `json:"mode,omitempty" yaml:"mode,omitempty"`   // モード（切換スケジュール）
+}
+
+// docPeriodRe は `開始時分-(値)-終了時分` です。
+var docPeriodRe = regexp.MustCompile(`^([0-9]+)-\((.+)\)-([0-9]+)$`)
+
+func parseDocDaySchedule(tokens []string) *DocDaySchedule {
+	d := &DocDaySchedule{Name: tokens[2], Switch: tokens[1] == "-s"}
+	for _, tok := range tokens[3:] {
+		m := docPeriodRe.FindStringSubmatch(tok)
+		if m == nil {
+			return nil
+		}
+		p := DocSchedulePeriod{Start: m[1], End: m[3]}
+		if d.Switch {
+			p.Mode = m[2]
+		} else if p.Value = Number(m[2]); !p.Value.valid() {
+			return nil
+		}
+		d.Periods = append(d.Periods, p)
+	}
+	return d
+}
+
+func (d *DocDaySchedule) tokens() []string {
+	tokens := []string{"%s", "-v", d.Name}
+	if d.Switch {
+		tokens[1] = "-s"
+	}
+	for _, p := range d.Periods {
+		v := string(p.Value)
+		if d.Switch {
+			v = p.Mode
+		}
+		tokens = append(tokens, p.Start+"-("+v+")-"+p.End)
+	}
+	return tokens
+}
+
+// DocSeason は季節設定 (`%s -ssn`) です。
+type DocSeason struct {
+	Name    string      `json:"name" yaml:"name"`
+	Periods []DocPeriod `json:"periods,omitempty" yaml:"periods,omitempty"`
+}
+
+// DocPeriod は `月/日-月/日` の期間です。
+type DocPeriod struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+}
+
+func parseDocSeason(tokens []string) *DocSeason {
+	s := &DocSeason{Name: tokens[2]}
+	for _, tok := range tokens[3:] {
+		start, end, ok := strings.Cut(tok, "-")
+		if !ok {
+			return nil
+		}
+		s.Periods = append(s.Periods, DocPeriod{start, end})
+	}
+	return s
+}
+
+func (s *DocSeason) tokens() []string {
+	tokens := []string{"%s", "-ssn", s.Name}
+	for _, p := range s.Periods {
+		tokens = append(tokens, p.Start+"-"+p.End)
+	}
+	return tokens
+}
+
+// DocWeekdays は曜日設定 (`%s -wkd`) です。
+type DocWeekdays struct {
+	Name string   `json:"name" yaml:"name"`
+	Days []string `json:"days,omitempty" yaml:"days,omitempty,flow"` // 曜日（Mon、Tue、Wed、Thu、Fri、Sat、Sun、Hol）
+}
+
+func (w *DocWeekdays) tokens() []string {
+	return append([]string{"%s", "-wkd", w.Name}, w.Days...)
+}
+
+// DocSchedule は季節、曜日によるスケジュールの組み合わせ (`%sn`) です。ref: eschdata.go
+type DocSchedule struct {
+	Name    string             `json:"name" yaml:"name"`
+	Switch  bool               `json:"switch,omitempty" yaml:"switch,omitempty"` // 切換スケジュール (-s)。false の場合は設定値スケジュール (-v)
+	CSV     string             `json:"csv,omitempty" yaml:"csv,omitempty"`       // CSV の時系列のファイル名 (csv=)
+	Column  string             `json:"col,omitempty" yaml:"col,omitempty"`       // CSV の列名または列番号 (col=)
+	Entries []DocScheduleEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// DocScheduleEntry は `1日のスケジュール名:季節設定名-曜日設定名` です。
+type DocScheduleEntry struct {
+	Day      string `json:"day" yaml:"day"`
+	Season   string `json:"season,omitempty" yaml:"season,omitempty"`
+	Weekdays string `json:"weekdays,omitempty" yaml:"weekdays,omitempty"`
+}
+
+func parseDocSchedule(tokens []string) *DocSchedule {
+	s := &DocSchedule{Name: tokens[2], Switch: tokens[1] == "-s"}
+	for _, tok := range tokens[3:] {
+		switch key := paramKey(tok); {
+		case key == "csv" && len(s.Entries) == 0:
+			s.CSV = tok[4:]
+		case key == "col" && len(s.Entries) == 0:
+			s.Column = tok[4:]
+		case key != "":
+			return nil
+		default:
+			var e DocScheduleEntry
+			var sw string
+			e.Day, sw, _ = strings.Cut(tok, ":")
+			e.Season, e.Weekdays, _ = strings.Cut(sw, "-")
+			s.Entries = append(s.Entries, e)
+		}
+	}
+	return s
+}
+
+func (s *DocSchedule) tokens() []string {
+	tokens := []string{"%sn", "-v", s.Name}
+	if s.Switch {
+		tokens[1] = "-s"
+	}
+	if s.CSV != "" {
+		tokens = append(tokens, "csv="+s.CSV)
+	}
+	if s.Column != "" {
+		tokens = append(tokens, "col="+s.Column)
+	}
+	for _, e := range s.Entries {
+		token := e.Day
+		if e.Season != "" || e.Weekdays != "" {
+			token += ":" + e.Season
+		}
+		if e.Weekdays != "" {
+			token += "-" + e.Weekdays
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// DocWall は壁体 (WALL) です。
+type DocWall struct {
+	Ble    string     `json:"ble,omitempty" yaml:"ble,omitempty"`       // 部位コード（E、R、F、i、c、f）。空の場合は部位を指定しない
+	Name   string     `json:"name,omitempty" yaml:"name,omitempty"`     // 壁体名。空の場合は部位の既定の壁体
+	Params ParamList  `json:"params,omitempty" yaml:"params,omitempty"` // Ei=、Eo=、as= など
+	Layers []DocLayer `json:"layers,omitempty" yaml:"layers,omitempty"` // 層構成（室内側から。屋根、天井は外表面側から）
+}
+
+// DocLayer は壁体の層 `材料コード-厚さ/分割数` です。
+type DocLayer struct {
+	Material  string `json:"material" yaml:"material"`                       // 材料コード。`<P>` などの指定を含む
+	Thickness Number `json:"thickness,omitempty" yaml:"thickness,omitempty"` // 厚さ [mm]
+	Div       Number `json:"div,omitempty" yaml:"div,omitempty"`             // 層内の分割数
+}
+
+func parseDocWall(tokens []string) *DocWall {
+	w := new(DocWall)
+	if s := tokens[0]; strings.HasPrefix(s, "-") {
+		if len(s) < 2 || (len(s) > 2 && s[2] != ':') {
+			return nil
+		}
+		w.Ble = s[1:2]
+		if len(s) > 2 {
+			w.Name = s[3:]
+		}
+	} else {
+		w.Name = s
+	}
+	for _, tok := range tokens[1:] {
+		if key := paramKey(tok); key != "" {
+			if len(w.Layers) > 0 {
+				return nil
+			}
+			w.Params = append(w.Params, Param{key, tok[len(key)+1:]})
+			continue
+		}
+		// Walldata と同じく、最初の `-` で材料コードと厚さを分ける
+		var l DocLayer
+		var size string
+		l.Material, size, _ = strings.Cut(tok, "-")
+		thickness, div, _ := strings.Cut(size, "/")
+		l.Thickness, l.Div = Number(thickness), Number(div)
+		if (l.Thickness != "" && !l.Thickness.valid()) || (l.Div != "" && !l.Div.valid()) {
+			return nil
+		}
+		w.Layers = append(w.Layers, l)
+	}
+	return w
+}
+
+func (w *DocWall) tokens() []string {
+	name := w.Name
+	if w.Ble != "" {
+		name = "-" + w.Ble
+		if w.Name != "" {
+			name += ":" + w.Name
+		}
+	}
+	tokens := []string{name}
+	for _, p := range w.Params {
+		tokens = append(tokens, p.Key+"="+p.Value)
+	}
+	for _, l := range w.Layers {
+		token := l.Material
+		if l.Thickness != "" {
+			token += "-" + string(l.Thickness)
+		}
+		if l.Div != "" {
+			token += "/" + string(l.Div)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// DocRoom は室 (ROOM) の指定です。室の最初の論理行で、最初の部位 (DocSurface) の前に置きます。
+type DocRoom struct {
+	Name   string   `json:"name" yaml:"name"`
+	Params Keywords `json:"params,omitempty" yaml:"params,omitempty"` // Vol=、alc=、*s、*q など
+}
+
+// DocSurface は室の部位 (RMSRF) です。
+type DocSurface struct {
+	Exsrf    string   `json:"exsrf,omitempty" yaml:"exsrf,omitempty"`       // 外表面名 (`外表面名:`)
+	NextRoom string   `json:"nextRoom,omitempty" yaml:"nextRoom,omitempty"` // 隣室名 (`(隣室名):`)
+	Ble      string   `json:"ble" yaml:"ble"`                               // 部位コード（E、R、F、i、c、f、W）
+	Wall     string   `json:"wall,omitempty" yaml:"wall,omitempty"`         // 壁体名または窓名。空の場合は部位の既定の壁体
+	Params   Keywords `json:"params,omitempty" yaml:"params,omitempty"`     // fsol=、alc=、i=、*p など
+	Area     Number   `json:"area,omitempty" yaml:"area,omitempty"`         // 面積 [m2]
+}
+
+// docBleRe は部位コードのトークン（`-E` など）です。
+var docBleRe = regexp.MustCompile(`^-[A-Za-z]$`)
+
+// isSurfaceStart は tok が部位の最初のトークン（`外表面名:`、`(隣室名):`、部位コード）かどうかを返します。
+func isSurfaceStart(tok string) bool {
+	return strings.HasSuffix(tok, ":") || docBleRe.MatchString(tok)
+}
+
+// isFlag は tok が `*s`、`*p` などの指定かどうかを返します。
+func isFlag(tok string) bool {
+	return len(tok) > 1 && tok[0] == '*'
+}
+
+func parseDocRoom(tokens []string) (*DocRoom, []string) {
+	if isSurfaceStart(tokens[0]) || paramKey(tokens[0]) != "" || isFlag(tokens[0]) {
+		return nil, nil
+	}
+	i := slices.IndexFunc(tokens, isSurfaceStart)
+	if i < 0 {
+		return nil, nil
+	}
+	params, ok := parseKeywords(tokens[1:i], isFlag)
+	if !ok {
+		return nil, nil
+	}
+	return &DocRoom{Name: tokens[0], Params: params}, tokens[i:]
+}
+
+func (r *DocRoom) tokens() []string {
+	return append([]string{r.Name}, r.Params.tokens()...)
+}
+
+func parseDocSurface(tokens []string) *DocSurface {
+	sd := new(DocSurface)
+	if len(tokens) > 0 && strings.HasSuffix(tokens[0], ":") {
+		name := strings.TrimSuffix(tokens[0], ":")
+		if strings.HasPrefix(name, "(") && strings.HasSuffix(name, ")") {
+			sd.NextRoom = name[1 : len(name)-1]
+		} else {
+			sd.Exsrf = name
+		}
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 || !docBleRe.MatchString(tokens[0]) {
+		return nil
+	}
+	sd.Ble = tokens[0][1:]
+	tokens = tokens[1:]
+	if len(tokens) > 0 && paramKey(tokens[0]) == "" && !isFlag(tokens[0]) && !Number(tokens[0]).valid() {
+		sd.Wall = tokens[0]
+		tokens = tokens[1:]
+	}
+	if n := len(tokens); n > 0 && Number(tokens[n-1]).valid() {
+		sd.Area = Number(tokens[n-1])
+		tokens = tokens[:n-1]
+	}
+	var ok bool
+	if sd.Params, ok = parseKeywords(tokens, isFlag); !ok {
+		return nil
+	}
+	return sd
+}
+
+func (sd *DocSurface) tokens() []string {
+	var tokens []string
+	if sd.Exsrf != "" {
+		tokens = append(tokens, sd.Exsrf+":")
+	}
+	if sd.NextRoom != "" {
+		tokens = append(tokens, "("+sd.NextRoom+"):")
+	}
+	tokens = append(tokens, "-"+sd.Ble)
+	if sd.Wall != "" {
+		tokens = append(tokens, sd.Wall)
+	}
+	tokens = append(tokens, sd.Params.tokens()...)
+	if sd.Area != "" {
+		tokens = append(tokens, string(sd.Area))
+	}
+	return tokens
+}
+
+// DocEquipment は機器カタログ (EQPCAT) です。
+type DocEquipment struct {
+	Type   string   `json:"type" yaml:"type"` // 機器種別（BOI、REFA、PUMP など）
+	Name   string   `json:"name" yaml:"name"` // カタログ名
+	Params Keywords `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+func parseDocEquipment(tokens []string) *DocEquipment {
+	if len(tokens) < 2 || paramKey(tokens[0]) != "" || paramKey(tokens[1]) != "" {
+		return nil
+	}
+	params, ok := parseKeywords(tokens[2:], func(string) bool { return true })
+	if !ok {
+		return nil
+	}
+	return &DocEquipment{Type: tokens[0], Name: tokens[1], Params: params}
+}
+
+func (e *DocEquipment) tokens() []string {
+	return append([]string{e.Type, e.Name}, e.Params.tokens()...)
+}
+
+// DocComponent はシステム要素 (SYSCMP) です。
+type DocComponent struct {
+	Name    string     `json:"name" yaml:"name"`
+	Options OptionList `json:"options,omitempty" yaml:"options,omitempty"` // -c、-type、-Nin、-room、-env など
+}
+
+func parseDocComponent(tokens []string) *DocComponent {
+	c := &DocComponent{Name: tokens[0]}
+	if strings.HasPrefix(c.Name, "-") {
+		return nil
+	}
+	for _, tok := range tokens[1:] {
+		switch {
+		case len(tok) > 1 && tok[0] == '-':
+			key := tok[1:]
+			if slices.ContainsFunc(c.Options, func(o Option) bool { return o.Key == key }) {
+				return nil
+			}
+			c.Options = append(c.Options, Option{Key: key})
+		case len(c.Options) == 0:
+			return nil
+		default:
+			o := &c.Options[len(c.Options)-1]
+			o.Values = append(o.Values, tok)
+		}
+	}
+	return c
+}
+
+func (c *DocComponent) tokens() []string {
+	tokens := []string{c.Name}
+	for _, o := range c.Options {
+		tokens = append(append(tokens, "-"+o.Key), o.Values...)
+	}
+	return tokens
+}
+
+// DocPath はシステム経路 (SYSPTH) です。
+type DocPath struct {
+	Name     string      `json:"name" yaml:"name"`
+	Sys      string      `json:"sys" yaml:"sys"` // システム分類 (-sys)
+	Fluid    string      `json:"f" yaml:"f"`     // 流体種別 (-f)
+	Branches []DocBranch `json:"branches" yaml:"branches"`
+}
+
+// DocBranch は末端経路 `> 要素 ... >` です。
+type DocBranch struct {
+	Name     string   `json:"name,omitempty" yaml:"name,omitempty"` // 末端経路名 (name=)
+	Flow     string   `json:"flow,omitempty" yaml:"flow,omitempty"` // 流量 [kg/s]。数値、スケジュール名または変数 (`(流量)`)
+	Rate     string   `json:"rate,omitempty" yaml:"rate,omitempty"` // 流量比率。数値またはスケジュール名 (`[流量比率]`)
+	Elements []string `json:"elements" yaml:"elements,flow"`        // 経路上の機器、室
+}
+
+func parseDocPath(tokens []string) *DocPath {
+	if len(tokens) < 5 || tokens[1] != "-sys" || tokens[3] != "-f" || strings.HasPrefix(tokens[0], "-") {
+		return nil
+	}
+	p := &DocPath{Name: tokens[0], Sys: tokens[2], Fluid: tokens[4]}
+	var b *DocBranch
+	for _, tok := range tokens[5:] {
+		switch {
+		case tok == ">" && b == nil:
+			b = new(DocBranch)
+		case tok == ">":
+			p.Branches = append(p.Branches, *b)
+			b = nil
+		case b == nil:
+			return nil
+		case strings.HasPrefix(tok, "name="):
+			b.Name = tok[5:]
+		case strings.HasPrefix(tok, "(") && strings.HasSuffix(tok, ")"):
+			b.Flow = tok[1 : len(tok)-1]
+		case strings.HasPrefix(tok, "[") && strings.HasSuffix(tok, "]"):
+			b.Rate = tok[1 : len(tok)-1]
+		default:
+			b.Elements = append(b.Elements, tok)
+		}
+	}
+	if b != nil || len(p.Branches) == 0 {
+		return nil
+	}
+	return p
+}
+
+func (p *DocPath) tokens() []string {
+	tokens := []string{p.Name, "-sys", p.Sys, "-f", p.Fluid}
+	for _, b := range p.Branches {
+		tokens = append(tokens, ">")
+		if b.Name != "" {
+			tokens = append(tokens, "name="+b.Name)
+		}
+		if b.Flow != "" {
+			tokens = append(tokens, "("+b.Flow+")")
+		}
+		if b.Rate != "" {
+			tokens = append(tokens, "["+b.Rate+"]")
+		}
+		tokens = append(append(tokens, b.Elements...), ">")
+	}
+	return tokens
+}
+
+// docTyped は型を持つ論理行のオブジェクトです。
+type docTyped interface {
+	tokens() []string
+}
+
+// typed は st の型を持つオブジェクトを返します。ROOM の室の最初の論理行は Room、Surface の順です。
+func (st *Statement) typed() []docTyped {
+	var typed []docTyped
+	add := func(ok bool, v docTyped) {
+		if ok {
+			typed = append(typed, v)
+		}
+	}
+	add(st.File != nil, st.File)
+	add(st.Run != nil, st.Run)
+	add(st.Print != nil, st.Print)
+	add(st.DaySchedule != nil, st.DaySchedule)
+	add(st.Season != nil, st.Season)
+	add(st.Weekdays != nil, st.Weekdays)
+	add(st.Schedule != nil, st.Schedule)
+	add(st.Wall != nil, st.Wall)
+	add(st.Room != nil, st.Room)
+	add(st.Surface != nil, st.Surface)
+	add(st.Equipment != nil, st.Equipment)
+	add(st.Component != nil, st.Component)
+	add(st.Path != nil, st.Path)
+	return typed
+}
+
+// parseTyped はデータセット section の論理行のトークン tokens（`;` を除く）を型を持つオブジェクトに分けます。
+// first は ROOM の室の最初の論理行かどうかです。
+// 型に分けられない場合、または型から変換したトークンが tokens と一致しない場合は nil を返します。
+func parseTyped(section string, tokens []string, first bool) *Statement {
+	if len(tokens) == 0 {
+		return nil
+	}
+	st := new(Statement)
+	switch {
+	case (tokens[0] == "%s" || tokens[0] == "%sn") && len(tokens) >= 3:
+		switch {
+		case tokens[0] == "%s" && (tokens[1] == "-v" || tokens[1] == "-s"):
+			st.DaySchedule = parseDocDaySchedule(tokens)
+		case tokens[0] == "%s" && tokens[1] == "-ssn":
+			st.Season = parseDocSeason(tokens)
+		case tokens[0] == "%s" && tokens[1] == "-wkd":
+			st.Weekdays = &DocWeekdays{Name: tokens[2], Days: tokens[3:]}
+		case tokens[0] == "%sn" && (tokens[1] == "-v" || tokens[1] == "-s"):
+			st.Schedule = parseDocSchedule(tokens)
+		}
+	case section == "GDAT" && tokens[0] == "FILE":
+		st.File = parseDocFile(tokens)
+	case section == "GDAT" && tokens[0] == "RUN":
+		st.Run = parseDocRun(tokens)
+	case section == "GDAT" && tokens[0] == "PRINT":
+		st.Print = parseDocPrint(tokens)
+	case section == "WALL" && paramKey(tokens[0]) == "":
+		st.Wall = parseDocWall(tokens)
+	case section == "ROOM" && first:
+		var rest []string
+		if st.Room, rest = parseDocRoom(tokens); st.Room != nil {
+			st.Surface = parseDocSurface(rest)
+		}
+		if st.Surface == nil {
+			return nil
+		}
+	case section == "ROOM":
+		st.Surface = parseDocSurface(tokens)
+	case section == "EQPCAT":
+		st.Equipment = parseDocEquipment(tokens)
+	case section == "SYSCMP":
+		st.Component = parseDocComponent(tokens)
+	case section == "SYSPTH":
+		st.Path = parseDocPath(tokens)
+	}
+	if len(st.typed()) == 0 || !slices.Equal(st.tokens(), tokens) {
+		return nil
+	}
+	return st
+}
+
+// checkTyped は JSON、YAML から読み込んだ型を持つ論理行 st がデータセット section の書式に合うことを検査します。
+// 型から変換したトークンを再び型に分け、同じオブジェクトになることを確かめます。
+func (st *Statement) checkTyped(section string, first bool) error {
+	typed := st.typed()
+	if len(typed) == 0 {
+		return nil
+	}
+	if st.Name != "" || st.Args != nil || st.Params != nil || st.Opts != nil || st.Tokens != nil {
+		return &InputError{Section: section, Msg: "typed statements cannot be combined with name, args, params, opts or tokens"}
+	}
+	tokens := st.tokens()
+	back := parseTyped(section, tokens, first)
+	if back == nil || !reflect.DeepEqual(back.typed(), typed) {
+		return &InputError{Section: section, Keyword: strings.Join(tokens, " "), Msg: "invalid typed statement for " + section}
+	}
+	return nil
+}
+
+// checkYAMLFields は YAML のノード n のマッピングのキーが型 t のフィールド（yaml タグ）であることを再帰的に検査します。
+// KnownFields は UnmarshalYAML の中の Decode には適用されないため、論理行の読み込みで用います。
+func checkYAMLFields(n *yaml.Node, t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()) {
+		return nil
+	}
+	switch {
+	case t.Kind() == reflect.Slice && n.Kind == yaml.SequenceNode:
+		for _, e := range n.Content {
+			if err := checkYAMLFields(e, t.Elem()); err != nil {
+				return err
+			}
+		}
+	case t.Kind() == reflect.Struct && n.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			f, ok := yamlField(t, k.Value)
+			if !ok {
+				return fmt.Errorf("line %d: unknown field %q", k.Line, k.Value)
+			}
+			if err := checkYAMLFields(n.Content[i+1], f.Type); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// yamlField は yaml タグの名前が name の構造体 t のフィールドを返します。
+func yamlField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tag, _, _ := strings.Cut(f.Tag.Get("yaml"), ","); tag == name && name != "" && name != "-" {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,293 @@
+package eeslism
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// inputTokens は入力データファイルの注釈文を除いたトークン（`*`、`;` を含む）を返す
+func inputTokens(s string) []string {
+	var tokens []string
+	for _, line := range strings.Split(s, "\n") {
+		for _, f := range strings.Fields(processLine(line)) {
+			if f != ";" && strings.HasSuffix(f, ";") {
+				tokens = append(tokens, f[:len(f)-1], ";")
+			} else {
+				tokens = append(tokens, f)
+			}
+		}
+	}
+	return tokens
+}
+
+// TestInputDocumentRoundTrip は全ての入力データファイルが 入力データファイル → JSON/YAML → 入力データファイル で変わらないことを確認する
+func TestInputDocumentRoundTrip(t *testing.T) {
+	var files []string
+	for _, root := range []string{"../tests/comparison/testdata", "../samples"} {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && filepath.Ext(path) == ".txt" {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	if len(files) == 0 {
+		t.Skip("no input files")
+	}
+
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := ParseInputDocument(bytes.NewReader(src))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		text := doc.String()
+		if got, want := strings.Join(inputTokens(text), " "), strings.Join(inputTokens(string(src)), " "); got != want {
+			t.Errorf("%s: tokens differ after conversion", file)
+			continue
+		}
+		again, err := ParseInputDocument(strings.NewReader(text))
+		if err != nil || !reflect.DeepEqual(again, doc) {
+			t.Errorf("%s: document differs after re-reading the text (%v)", file, err)
+			continue
+		}
+
+		for _, format := range []string{"json", "yaml"} {
+			var b bytes.Buffer
+			if err := doc.Write(&b, format); err != nil {
+				t.Fatalf("%s: %s: %v", file, format, err)
+			}
+			got, err := ReadInputDocument(&b, format)
+			if err != nil {
+				t.Errorf("%s: %s: %v", file, format, err)
+				continue
+			}
+			if got.String() != text {
+				t.Errorf("%s: %s: text differs after round trip", file, format)
+			}
+			if !reflect.DeepEqual(got, doc) {
+				t.Errorf("%s: %s: document differs after round trip", file, format)
+			}
+		}
+	}
+}
+
+// TestInputDocumentStatement は論理行の分割を確認する
+func TestInputDocumentStatement(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		want   Statement
+	}{
+		{[]string{"SouthWindow", "t=0.65", "B=0.15"}, Statement{Name: "SouthWindow", Params: ParamList{{"t", "0.65"}, {"B", "0.15"}}}},
+		{[]string{"-E:ExtWall", "RC-150", "FPS-50/2"}, Statement{Name: "-E:ExtWall", Args: []string{"RC-150", "FPS-50/2"}}},
+		{[]string{"TestRoom", "Vol=100.0", "south:", "-E", "15.5"}, Statement{Name: "TestRoom", Params: ParamList{{"Vol", "100.0"}}, Opts: []string{"south:", "-E", "15.5"}}},
+		{[]string{"if", "(Tr>=20)", "Boiler1_chmode=off"}, Statement{Name: "if", Args: []string{"(Tr>=20)"}, Params: ParamList{{"Boiler1_chmode", "off"}}}},
+		{[]string{"FILE", "w=a.has", "-skyrd", "f=b"}, Statement{Tokens: []string{"FILE", "w=a.has", "-skyrd", "f=b"}}},
+		{[]string{"x", "a=1", "a=2"}, Statement{Tokens: []string{"x", "a=1", "a=2"}}},
+	}
+	for _, tt := range tests {
+		if got := NewStatement(tt.tokens); !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("NewStatement(%q) = %+v, want %+v", tt.tokens, *got, tt.want)
+		}
+	}
+}
+
+// TestInputDocumentTyped はデータセットごとの型を持つ論理行の読み取りを確認する
+func TestInputDocumentTyped(t *testing.T) {
+	src := `GDAT
+	FILE w=tokyo.has out=result -skyrd ;
+	RUN (12/1) 1/1-12/31 dTime=3600 ;
+	PRINT 1/1-1/3 *wd ;
+	RUN 1/1-12/31 Tinit=20 (12/1) ;
+*
+WALL
+	-E:ExtWall Ei=0.9 RC-150 FPS-50/2 <P> ;
+*
+ROOM
+	Office Vol=100 *s south: -E ExtWall 15.5 ;
+	(Next): -i *p 10 ;
+	*
+*
+EQPCAT
+	BOI Boiler1 Qo=10000 Qmin=1000 ;
+*
+SYSCMP
+	Boiler1 -c Boiler1 -Nin 1 -env Office ;
+*
+SYSPTH
+	HeatPath -sys A -f W > (0.01) Boiler1 Office > ;
+*
+%s -v VentSch 001-(2.50E-02)-700 701-(1.0)-2400 ;
+%s -ssn Winter 11/4-4/21 ;
+%sn -v Vent VentSch:Winter-Weekday VentSch ;
+*
+END`
+	doc, err := ParseInputDocument(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gdat := doc.Sections[0].Statements
+	if want := (&DocFile{W: "tokyo.has", Out: "result", Skyrd: true}); !reflect.DeepEqual(gdat[0].File, want) {
+		t.Errorf("FILE = %+v, want %+v", gdat[0].File, want)
+	}
+	if want := (&DocRun{Warmup: "12/1", Start: "1/1", End: "12/31", Params: ParamList{{"dTime", "3600"}}}); !reflect.DeepEqual(gdat[1].Run, want) {
+		t.Errorf("RUN = %+v, want %+v", gdat[1].Run, want)
+	}
+	if want := (&DocPrint{Days: []string{"1/1-1/3"}, Output: []string{"*wd"}}); !reflect.DeepEqual(gdat[2].Print, want) {
+		t.Errorf("PRINT = %+v, want %+v", gdat[2].Print, want)
+	}
+	// 標準と異なる順序の論理行は汎用の形式のまま
+	if gdat[3].Run != nil || gdat[3].Name != "RUN" {
+		t.Errorf("RUN in non-standard order = %+v, want a generic statement", gdat[3])
+	}
+	wall := &DocWall{Ble: "E", Name: "ExtWall", Params: ParamList{{"Ei", "0.9"}},
+		Layers: []DocLayer{{Material: "RC", Thickness: "150"}, {Material: "FPS", Thickness: "50", Div: "2"}, {Material: "<P>"}}}
+	if got := doc.Sections[1].Statements[0].Wall; !reflect.DeepEqual(got, wall) {
+		t.Errorf("WALL = %+v, want %+v", got, wall)
+	}
+	room := doc.Sections[2].Blocks[0]
+	if want := (&DocRoom{Name: "Office", Params: Keywords{{Key: "Vol", Value: "100"}, {Key: "*s", Flag: true}}}); !reflect.DeepEqual(room[0].Room, want) {
+		t.Errorf("room = %+v, want %+v", room[0].Room, want)
+	}
+	if want := (&DocSurface{Exsrf: "south", Ble: "E", Wall: "ExtWall", Area: "15.5"}); !reflect.DeepEqual(room[0].Surface, want) {
+		t.Errorf("surface = %+v, want %+v", room[0].Surface, want)
+	}
+	if want := (&DocSurface{NextRoom: "Next", Ble: "i", Params: Keywords{{Key: "*p", Flag: true}}, Area: "10"}); !reflect.DeepEqual(room[1].Surface, want) {
+		t.Errorf("surface = %+v, want %+v", room[1].Surface, want)
+	}
+	if got := doc.Sections[3].Statements[0].Equipment; got == nil || got.Type != "BOI" || len(got.Params) != 2 {
+		t.Errorf("EQPCAT = %+v", got)
+	}
+	if want := (&DocComponent{Name: "Boiler1", Options: OptionList{{"c", []string{"Boiler1"}}, {"Nin", []string{"1"}}, {"env", []string{"Office"}}}}); !reflect.DeepEqual(doc.Sections[4].Statements[0].Component, want) {
+		t.Errorf("SYSCMP = %+v, want %+v", doc.Sections[4].Statements[0].Component, want)
+	}
+	if want := (&DocPath{Name: "HeatPath", Sys: "A", Fluid: "W", Branches: []DocBranch{{Flow: "0.01", Elements: []string{"Boiler1", "Office"}}}}); !reflect.DeepEqual(doc.Sections[5].Statements[0].Path, want) {
+		t.Errorf("SYSPTH = %+v, want %+v", doc.Sections[5].Statements[0].Path, want)
+	}
+	top := doc.Sections[6].Statements
+	if want := (&DocDaySchedule{Name: "VentSch", Periods: []DocSchedulePeriod{{Start: "001", End: "700", Value: "2.50E-02"}, {Start: "701", End: "2400", Value: "1.0"}}}); !reflect.DeepEqual(top[0].DaySchedule, want) {
+		t.Errorf("%%s -v = %+v, want %+v", top[0].DaySchedule, want)
+	}
+	if want := (&DocSeason{Name: "Winter", Periods: []DocPeriod{{"11/4", "4/21"}}}); !reflect.DeepEqual(top[1].Season, want) {
+		t.Errorf("%%s -ssn = %+v, want %+v", top[1].Season, want)
+	}
+	if want := (&DocSchedule{Name: "Vent", Entries: []DocScheduleEntry{{Day: "VentSch", Season: "Winter", Weekdays: "Weekday"}, {Day: "VentSch"}}}); !reflect.DeepEqual(top[2].Schedule, want) {
+		t.Errorf("%%sn = %+v, want %+v", top[2].Schedule, want)
+	}
+	if !top[3].End || !top[4].Unterminated || top[4].Name != "END" {
+		t.Errorf("end = %+v, %+v", top[3], top[4])
+	}
+	if got := doc.String(); !strings.HasSuffix(got, "\n*\nEND\n") {
+		t.Errorf("text ends with %q", got[len(got)-20:])
+	}
+
+	var b bytes.Buffer
+	if err := doc.Write(&b, "json"); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"room":{"name":"Office","params":{"Vol":"100","*s":true}}`, `"area":15.5`, `"value":2.50E-02`, `"options":{"c":"Boiler1","Nin":"1","env":"Office"}`} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("json does not contain %s", s)
+		}
+	}
+
+	bad := "sections:\n  - section: WALL\n    statements:\n      - {wall: {name: a, layers: [{material: RC, thick: 150}]}}\n"
+	if _, err := ReadInputDocument(strings.NewReader(bad), "yaml"); err == nil {
+		t.Errorf("yaml with unknown field: expected error")
+	}
+}
+
+// TestInputDocumentJSON は JSON の読み込みと検査を確認する
+func TestInputDocumentJSON(t *testing.T) {
+	input := `{"sections": [
+		{"section": "TITLE", "title": "test"},
+		{"section": "WINDOW", "statements": [
+			{"name": "SouthWindow", "params": {"t": 0.65, "B": "0.15"}, "comment": "南窓"}
+		]},
+		{"statements": [{"tokens": ["%s", "-v", "VentSch", "001-(1.0)-2400"]}]}
+	]}`
+	doc, err := ReadInputDocument(strings.NewReader(input), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "TITLE\n\ttest ;\n\nWINDOW\n\tSouthWindow t=0.65 B=0.15 ; ! 南窓\n*\n\n%s -v VentSch 001-(1.0)-2400 ;\n"
+	if got := doc.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	for _, bad := range []string{
+		`{"sections": [{"section": "NOSUCH"}]}`,
+		`{"sections": [{"section": "WALL", "unknown": 1}]}`,
+		`{"sections": [{"section": "WALL", "statements": [{"name": "a b"}]}]}`,
+		`{"sections": [{"section": "WALL", "statements": [{"name": "a", "params": {"t": true}}]}]}`,
+		`{"sections": [{"section": "WALL", "blocks": [[{"name": "a"}]]}]}`,
+		`{"sections": [{"section": "EXSRF", "statements": [{"wall": {"name": "a"}}]}]}`,
+		`{"sections": [{"section": "WALL", "statements": [{"wall": {"name": "a", "layers": [{"material": "RC", "thickness": "x"}]}}]}]}`,
+		`{"sections": [{"section": "WALL", "statements": [{"wall": {"name": "a"}, "name": "b"}]}]}`,
+		`{"sections": [{"section": "SYSCMP", "statements": [{"component": {"name": "a", "options": {"c": false}}}]}]}`,
+		`{"sections": [{"section": "ROOM", "blocks": [[{"surface": {"ble": "E"}}, {"room": {"name": "a"}, "surface": {"ble": "E"}}]]}]}`,
+		`{"sections": [{"section": "GDAT", "statements": [{"end": true}]}]}`,
+		`{"sections": [{"statements": [{"name": "END", "unterminated": true}, {"name": "x"}]}]}`,
+	} {
+		if _, err := ReadInputDocument(strings.NewReader(bad), "json"); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
+
+// TestInputDocumentSimulation は JSON、YAML の入力データファイルの計算結果が元の入力データファイルと同じことを確認する
+func TestInputDocumentSimulation(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	if _, err := os.Stat(src); err != nil {
+		t.Skip("test input not found")
+	}
+	efl, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	txt := copySimulationInput(t, src, dir)
+	if err := NewSimulation(txt, efl).Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := readSimulationOutputs(t, dir)
+	if len(want) == 0 {
+		t.Fatal("no output files")
+	}
+
+	b, _ := os.ReadFile(txt)
+	doc, err := ParseInputDocument(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ext := range []string{".json", ".yaml"} {
+		sub := t.TempDir()
+		name := filepath.Join(sub, strings.TrimSuffix(filepath.Base(txt), ".txt")+ext)
+		f, err := os.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.Write(f, InputFormat(name)); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		if err := NewSimulation(name, efl).Run(); err != nil {
+			t.Fatalf("%s: %v", ext, err)
+		}
+		got := readSimulationOutputs(t, sub)
+		for fname, w := range want {
+			if strings.ReplaceAll(got[fname], filepath.Base(name), filepath.Base(txt)) != w {
+				t.Errorf("%s: %s differs", ext, fname)
+			}
+		}
+	}
+}
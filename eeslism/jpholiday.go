@@ -0,0 +1,295 @@
+/*
+jpholiday.go (Japanese National Holiday Calendar)
+
+GDAT の CALENDAR で暦年を指定し、その年の曜日と日本の国民の祝日・休日を Simc.Daywk に設定します。
+dayweek.efl（1/1 の曜日と固定の祝日の一覧）、WEEK の代わりに用います。
+
+	CALENDAR year=2025 -jpholiday 8/13-8/15 12/29-1/3 ;
+
+キーワードは次のとおりです。
+
+  - `year=`: 暦年。各日の曜日はこの年の暦によります（2/29 は計算の通日に含まれません）。
+    GDAT RUN に年を指定した実暦による計算では省略でき、各年の曜日と祝日はその年の暦によります。
+  - `-jpholiday`: 国民の祝日に関する法律による祝日を休日（Hol）とします。
+    ハッピーマンデー、春分・秋分の日、振替休日、国民の休日（祝日に挟まれた日）を含みます。
+  - `m/d`、`m/d-m/d`: 会社の休業日など、追加の休日です。12/29-1/3 のように年末年始をまたぐ期間も指定できます。
+*/
+package eeslism
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Holiday は祝日です。
+type Holiday struct {
+	MonthDay
+	Name string
+}
+
+// 祝日を計算できる年の範囲（国民の祝日に関する法律の施行から、春分・秋分の日の近似式の範囲まで）
+const (
+	JPHolidayFirstYear = 1949
+	JPHolidayLastYear  = 2099
+)
+
+// JPHolidays は暦年 year の日本の国民の祝日と休日（振替休日、国民の休日）を日付の順に返します。
+func JPHolidays(year int) ([]Holiday, error) {
+	if year < JPHolidayFirstYear || year > JPHolidayLastYear {
+		return nil, fmt.Errorf("year %d: Japanese holidays are available for %d-%d", year, JPHolidayFirstYear, JPHolidayLastYear)
+	}
+
+	days := map[MonthDay]string{}
+	add := func(m, d int, name string) { days[MonthDay{m, d}] = name }
+	// n 番目の月曜日（ハッピーマンデー）
+	monday := func(m, n int) int {
+		wd := int(time.Date(year, time.Month(m), 1, 0, 0, 0, 0, time.UTC).Weekday())
+		return 1 + (8-wd)%7 + 7*(n-1)
+	}
+
+	add(1, 1, "元日")
+	if year < 2000 {
+		add(1, 15, "成人の日")
+	} else {
+		add(1, monday(1, 2), "成人の日")
+	}
+	if year >= 1967 {
+		add(2, 11, "建国記念の日")
+	}
+	if year >= 2020 {
+		add(2, 23, "天皇誕生日")
+	}
+	add(3, vernalEquinox(year), "春分の日")
+	switch {
+	case year < 1989:
+		add(4, 29, "天皇誕生日")
+	case year < 2007:
+		add(4, 29, "みどりの日")
+	default:
+		add(4, 29, "昭和の日")
+	}
+	add(5, 3, "憲法記念日")
+	if year >= 2007 {
+		add(5, 4, "みどりの日")
+	}
+	add(5, 5, "こどもの日")
+	switch {
+	case year == 2020:
+		add(7, 23, "海の日")
+	case year == 2021:
+		add(7, 22, "海の日")
+	case year >= 2003:
+		add(7, monday(7, 3), "海の日")
+	case year >= 1996:
+		add(7, 20, "海の日")
+	}
+	switch {
+	case year == 2020:
+		add(8, 10, "山の日")
+	case year == 2021:
+		add(8, 8, "山の日")
+	case year >= 2016:
+		add(8, 11, "山の日")
+	}
+	switch {
+	case year >= 2003:
+		add(9, monday(9, 3), "敬老の日")
+	case year >= 1966:
+		add(9, 15, "敬老の日")
+	}
+	add(9, autumnalEquinox(year), "秋分の日")
+	switch {
+	case year == 2020:
+		add(7, 24, "スポーツの日")
+	case year == 2021:
+		add(7, 23, "スポーツの日")
+	case year >= 2022:
+		add(10, monday(10, 2), "スポーツの日")
+	case year >= 2000:
+		add(10, monday(10, 2), "体育の日")
+	case year >= 1966:
+		add(10, 10, "体育の日")
+	}
+	add(11, 3, "文化の日")
+	add(11, 23, "勤労感謝の日")
+	if 1989 <= year && year <= 2018 {
+		add(12, 23, "天皇誕生日")
+	}
+
+	// 皇室の慶弔による祝日
+	for _, h := range []struct {
+		year, m, d int
+		name       string
+	}{
+		{1959, 4, 10, "皇太子明仁親王の結婚の儀"},
+		{1989, 2, 24, "昭和天皇の大喪の礼"},
+		{1990, 11, 12, "即位礼正殿の儀"},
+		{1993, 6, 9, "皇太子徳仁親王の結婚の儀"},
+		{2019, 5, 1, "天皇の即位の日"},
+		{2019, 10, 22, "即位礼正殿の儀"},
+	} {
+		if h.year == year {
+			add(h.m, h.d, h.name)
+		}
+	}
+
+	date := func(md MonthDay) time.Time {
+		return time.Date(year, time.Month(md.Month), md.Day, 0, 0, 0, 0, time.UTC)
+	}
+	monthDay := func(t time.Time) MonthDay { return MonthDay{int(t.Month()), t.Day()} }
+	holidays := make([]MonthDay, 0, len(days))
+	for md := range days {
+		holidays = append(holidays, md)
+	}
+
+	// 国民の休日: 前日と翌日が祝日である日（日曜日を除く。1986年から）
+	if year >= 1986 {
+		for _, md := range holidays {
+			t := date(md).AddDate(0, 0, 1)
+			next := monthDay(t.AddDate(0, 0, 1))
+			if _, ok := days[monthDay(t)]; !ok && t.Weekday() != time.Sunday && t.Year() == year {
+				if _, ok := days[next]; ok {
+					days[monthDay(t)] = "国民の休日"
+				}
+			}
+		}
+	}
+
+	// 振替休日: 日曜日の祝日の後の最初の休日でない日（1973年4月12日から。2006年までは翌日の月曜日のみ）
+	for _, md := range holidays {
+		t := date(md)
+		if t.Weekday() != time.Sunday || t.Before(time.Date(1973, 4, 12, 0, 0, 0, 0, time.UTC)) {
+			continue
+		}
+		for t = t.AddDate(0, 0, 1); t.Year() == year; t = t.AddDate(0, 0, 1) {
+			if _, ok := days[monthDay(t)]; !ok {
+				days[monthDay(t)] = "振替休日"
+				break
+			}
+			if year < 2007 {
+				break
+			}
+		}
+	}
+
+	result := make([]Holiday, 0, len(days))
+	for md, name := range days {
+		result = append(result, Holiday{md, name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return date(result[i].MonthDay).Before(date(result[j].MonthDay))
+	})
+	return result, nil
+}
+
+// vernalEquinox は春分の日（3月の日）を返します。
+func vernalEquinox(year int) int {
+	if year < 1980 {
+		return int(20.8357 + 0.242194*float64(year-1980) - float64((year-1983)/4))
+	}
+	return int(20.8431 + 0.242194*float64(year-1980) - float64((year-1980)/4))
+}
+
+// autumnalEquinox は秋分の日（9月の日）を返します。
+func autumnalEquinox(year int) int {
+	if year < 1980 {
+		return int(23.2588 + 0.242194*float64(year-1980) - float64((year-1983)/4))
+	}
+	return int(23.2488 + 0.242194*float64(year-1980) - float64((year-1980)/4))
+}
+
+/*
+CalendarDayweek (Calendar Year and Holidays)
+
+GDAT の CALENDAR の論理行 fields から、暦年の曜日と休日を daywk（通日ごと。0:月曜日～6:日曜日、7:祝日）に設定し、
+暦年を返します。
+*/
+func CalendarDayweek(fields []string, daywk []int) int {
+	return calendarDayweek(fields, 0, daywk)
+}
+
+// calendarDayweek は CalendarDayweek と同じく曜日と休日を daywk に設定します。
+// year が 0 でない場合は year= によらず year の暦とします（GDAT RUN に年を指定した実暦による計算）。
+func calendarDayweek(fields []string, year int, daywk []int) int {
+	y := 0
+	jpholiday := false
+	var holidays []string
+	for _, s := range fields[1:] {
+		s = strings.TrimSuffix(s, ";")
+		switch {
+		case s == "":
+		case strings.HasPrefix(s, "year="):
+			var err error
+			if y, err = strconv.Atoi(s[5:]); err != nil || y < 1 {
+				panic(&InputError{Section: "GDAT", Keyword: "CALENDAR", Component: s, Msg: "invalid year"})
+			}
+		case s == "-jpholiday":
+			jpholiday = true
+		default:
+			holidays = append(holidays, s)
+		}
+	}
+	if year == 0 {
+		year = y
+	}
+	if year == 0 {
+		panic(&InputError{Section: "GDAT", Keyword: "CALENDAR", Msg: "year= is required"})
+	}
+
+	// 曜日
+	for M := 1; M <= 12; M++ {
+		for D := 1; D <= monthDays[M-1]; D++ {
+			wd := time.Date(year, time.Month(M), D, 0, 0, 0, 0, time.UTC).Weekday()
+			daywk[FNNday(M, D)] = (int(wd) + 6) % 7
+		}
+	}
+
+	// 国民の祝日
+	if jpholiday {
+		hs, err := JPHolidays(year)
+		if err != nil {
+			panic(&InputError{Section: "GDAT", Keyword: "CALENDAR", Component: "-jpholiday", Msg: err.Error()})
+		}
+		for _, h := range hs {
+			if !(h.Month == 2 && h.Day == 29) {
+				daywk[FNNday(h.Month, h.Day)] = 7
+			}
+		}
+	}
+
+	// 追加の休日
+	re := regexp.MustCompile(`^(\d+)/(\d+)(?:-(\d+)/(\d+))?$`)
+	for _, s := range holidays {
+		m := re.FindStringSubmatch(s)
+		if m == nil {
+			panic(&InputError{Section: "GDAT", Keyword: "CALENDAR", Component: s, Msg: "invalid holiday (m/d or m/d-m/d is expected)"})
+		}
+		v := make([]int, 4)
+		for i := range v {
+			v[i], _ = strconv.Atoi(m[i+1])
+		}
+		if m[3] == "" {
+			v[2], v[3] = v[0], v[1]
+		}
+		for i := 0; i < 4; i += 2 {
+			if v[i] < 1 || v[i] > 12 || v[i+1] < 1 || v[i+1] > monthDays[v[i]-1] {
+				panic(&InputError{Section: "GDAT", Keyword: "CALENDAR", Component: s, Msg: "invalid date"})
+			}
+		}
+		ds, de := FNNday(v[0], v[1]), FNNday(v[2], v[3])
+		if de < ds {
+			de += 365
+		}
+		for d := ds; d <= de; d++ {
+			daywk[(d-1)%365+1] = 7
+		}
+	}
+	return year
+}
+
+// 各月の日数（2/29 を除く）
+var monthDays = [12]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
@@ -0,0 +1,127 @@
+package eeslism
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestJPHolidays は祝日、振替休日、国民の休日と特例の年を確認する
+func TestJPHolidays(t *testing.T) {
+	tests := []struct {
+		year int
+		want string
+	}{
+		{2025, "1/1 1/13 2/11 2/23 2/24 3/20 4/29 5/3 5/4 5/5 5/6 7/21 8/11 9/15 9/23 10/13 11/3 11/23 11/24"},
+		// 即位の日と前後の国民の休日
+		{2019, "1/1 1/14 2/11 3/21 4/29 4/30 5/1 5/2 5/3 5/4 5/5 5/6 7/15 8/11 8/12 9/16 9/23 10/14 10/22 11/3 11/4 11/23"},
+		// 東京オリンピックによる移動
+		{2020, "1/1 1/13 2/11 2/23 2/24 3/20 4/29 5/3 5/4 5/5 5/6 7/23 7/24 8/10 9/21 9/22 11/3 11/23"},
+		{2021, "1/1 1/11 2/11 2/23 3/20 4/29 5/3 5/4 5/5 7/22 7/23 8/8 8/9 9/20 9/23 11/3 11/23"},
+		// 敬老の日と秋分の日に挟まれた国民の休日
+		{2026, "1/1 1/12 2/11 2/23 3/20 4/29 5/3 5/4 5/5 5/6 7/20 8/11 9/21 9/22 9/23 10/12 11/3 11/23"},
+		// 2006年までの振替休日は翌日のみ、5/4 は国民の休日
+		{2005, "1/1 1/10 2/11 3/20 3/21 4/29 5/3 5/4 5/5 7/18 9/19 9/23 10/10 11/3 11/23 12/23"},
+		{1985, "1/1 1/15 2/11 3/21 4/29 5/3 5/5 5/6 9/15 9/16 9/23 10/10 11/3 11/4 11/23"},
+	}
+	for _, tt := range tests {
+		hs, err := JPHolidays(tt.year)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(hs))
+		for i, h := range hs {
+			got[i] = h.String()
+		}
+		if s := strings.Join(got, " "); s != tt.want {
+			t.Errorf("%d:\n got %s\nwant %s", tt.year, s, tt.want)
+		}
+	}
+
+	hs, _ := JPHolidays(2025)
+	if hs[1].Name != "成人の日" || hs[4].Name != "振替休日" {
+		t.Errorf("names: %v", hs[:5])
+	}
+	for _, year := range []int{JPHolidayFirstYear - 1, JPHolidayLastYear + 1} {
+		if _, err := JPHolidays(year); err == nil {
+			t.Errorf("%d: should fail", year)
+		}
+	}
+}
+
+// TestCalendarDayweek は暦年の曜日、祝日と年末年始をまたぐ追加の休日を確認する
+func TestCalendarDayweek(t *testing.T) {
+	daywk := make([]int, 366)
+	year := CalendarDayweek(strings.Fields("CALENDAR year=2024 -jpholiday 8/13-8/15 12/29-1/3 ;"), daywk)
+	if year != 2024 {
+		t.Errorf("year %d", year)
+	}
+	for _, tt := range []struct {
+		m, d, want int
+	}{
+		{1, 1, 7},   // 元日
+		{1, 4, 3},   // 木曜日
+		{1, 8, 7},   // 成人の日
+		{2, 28, 2},  // 水曜日
+		{3, 1, 4},   // 閏年の 3/1 は金曜日
+		{8, 12, 7},  // 振替休日
+		{8, 14, 7},  // 追加の休日
+		{8, 16, 4},  // 金曜日
+		{12, 28, 5}, // 土曜日
+		{12, 30, 7}, // 追加の休日
+	} {
+		if got := daywk[FNNday(tt.m, tt.d)]; got != tt.want {
+			t.Errorf("%d/%d: %d, want %d", tt.m, tt.d, got, tt.want)
+		}
+	}
+
+	// 国民の祝日を用いない場合は曜日のみ
+	daywk = make([]int, 366)
+	CalendarDayweek([]string{"CALENDAR", "year=2024", ";"}, daywk)
+	if daywk[1] != 0 || daywk[FNNday(1, 7)] != 6 {
+		t.Errorf("1/1 %d, 1/7 %d", daywk[1], daywk[FNNday(1, 7)])
+	}
+
+	for _, line := range []string{"CALENDAR -jpholiday", "CALENDAR year=1900 -jpholiday", "CALENDAR year=2024 2/30"} {
+		func() {
+			defer func() {
+				if _, ok := recover().(*InputError); !ok {
+					t.Errorf("%s: should panic with InputError", line)
+				}
+			}()
+			CalendarDayweek(strings.Fields(line), make([]int, 366))
+		}()
+	}
+}
+
+// TestModelCalendar は Model の Calendar を入力データの GDAT CALENDAR として読み込めることを確認する
+func TestModelCalendar(t *testing.T) {
+	m := scheduleTestModel()
+	m.Calendar = &Calendar{Year: 2025, JPHoliday: true, Holidays: []MonthDay{{1, 2}, {1, 3}}}
+	var b strings.Builder
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "\tCALENDAR year=2025 -jpholiday 1/2 1/3 ;\n") {
+		t.Errorf("GDAT:\n%s", b.String()[:200])
+	}
+
+	sim, err := m.Simulation(filepath.Join(t.TempDir(), "room.txt"), "../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Output = new(MemorySink)
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	got := fmt.Sprint(sim.Simc.Daywk[1:8])
+	if want := "[7 7 7 5 6 0 1]"; got != want || sim.Simc.Year != 2025 {
+		t.Errorf("year %d, 1/1-1/7 %s, want %s", sim.Simc.Year, got, want)
+	}
+
+	m.Calendar = &Calendar{Year: 1900, JPHoliday: true}
+	if err := m.Check(); err == nil {
+		t.Error("Check should fail for 1900 with -jpholiday")
+	}
+}
@@ -0,0 +1,967 @@
+/*
+lsp.go (Language Server)
+
+入力データファイルを編集するエディタ（VS Code など）のための Language Server Protocol（LSP）のサーバーを定義します。
+標準入出力で JSON-RPC 2.0 のメッセージ（`Content-Length` ヘッダー付き）を受け取り、次の機能を提供します。
+
+  - 診断（textDocument/publishDiagnostics）: 編集のたびに CheckInput で名前の定義と参照を検査し、誤りと警告を表示します。
+    パーサーによる読み込み（Simulation.Check）は行いません。
+  - 補完（textDocument/completion）: データセット名、パラメータ名（`Vent=`、`Inf=`、EQPCAT の機器種別ごとの仕様など）、
+    入力データファイルで定義された壁体名、窓名、室名、スケジュール名、要素名などを、カーソルの位置に応じて候補とします。
+  - 定義へ移動（textDocument/definition）: ROOM の部位の壁体名、窓名から WALL、WINDOW の定義へ、
+    その他の名前（室、スケジュール、要素、機器カタログなど）からその定義へ移動します。
+  - ホバー（textDocument/hover）: データセット名、パラメータ名の説明を入力データ形式の説明（format/*.md）から表示します。
+    名前の場合は定義の論理行を表示します。
+
+文書は全体の同期（TextDocumentSyncKind.Full）で受け取ります。位置の列は UTF-16 の単位です。
+*/
+package eeslism
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
+)
+
+// LanguageServer は入力データファイルの LSP サーバーです。NewLanguageServer で作成し、Serve で通信します。
+type LanguageServer struct {
+	docs *formatDocs // 入力データ形式の説明
+
+	mu       sync.Mutex
+	w        *bufio.Writer
+	files    map[string]string // 開いている文書の URI と内容
+	shutdown bool              // shutdown を受け取ったかどうか
+}
+
+// NewLanguageServer は LSP サーバーを作成します。
+// docs は入力データ形式の説明（format ディレクトリの README.md と各データセットの .md）です。nil の場合はホバーの説明を表示しません。
+func NewLanguageServer(docs fs.FS) *LanguageServer {
+	return &LanguageServer{
+		docs:  loadFormatDocs(docs),
+		files: make(map[string]string),
+	}
+}
+
+// JSON-RPC のメッセージ
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *lspError        `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC、LSP のエラーコード
+const (
+	lspParseError     = -32700
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+)
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"` // 1: Error、2: Warning
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspCompletionItem struct {
+	Label  string `json:"label"`
+	Kind   int    `json:"kind,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// CompletionItemKind
+const (
+	lspKindKeyword   = 14
+	lspKindProperty  = 10
+	lspKindReference = 18
+	lspKindClass     = 7
+)
+
+type lspMarkup struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type lspHover struct {
+	Contents lspMarkup `json:"contents"`
+	Range    lspRange  `json:"range"`
+}
+
+type lspTextDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspPositionParams struct {
+	TextDocument lspTextDocument `json:"textDocument"`
+	Position     lspPosition     `json:"position"`
+}
+
+// Serve は r から要求を読み取り、w に応答を書き出します。exit を受け取るか r が終わると終了します。
+// shutdown の後に exit を受け取った場合は nil を、それ以外はエラーを返します。
+func (s *LanguageServer) Serve(r io.Reader, w io.Writer) error {
+	s.w = bufio.NewWriter(w)
+	br := bufio.NewReader(r)
+	for {
+		body, err := readLSPMessage(br)
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		var msg lspMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.reply(nil, nil, &lspError{lspParseError, err.Error()})
+			continue
+		}
+		if msg.Method == "exit" {
+			if s.shutdown {
+				return nil
+			}
+			return fmt.Errorf("eeslism: lsp: exit without shutdown")
+		}
+		result, rerr := s.handle(msg.Method, msg.Params)
+		if msg.ID != nil {
+			s.reply(msg.ID, result, rerr)
+		}
+	}
+}
+
+// readLSPMessage は `Content-Length` ヘッダー付きのメッセージを1つ読み取ります。
+func readLSPMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, "Content-Length:"); ok {
+			if length, err = strconv.Atoi(strings.TrimSpace(v)); err != nil {
+				return nil, fmt.Errorf("eeslism: lsp: invalid header %q", line)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("eeslism: lsp: missing Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// write はメッセージを1つ書き出します。
+func (s *LanguageServer) write(msg lspMessage) {
+	msg.JSONRPC = "2.0"
+	b, _ := json.Marshal(msg)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n", len(b))
+	s.w.Write(b)
+	s.w.Flush()
+}
+
+// reply は要求 id への応答を書き出します。結果がない場合は null を返します。
+func (s *LanguageServer) reply(id *json.RawMessage, result interface{}, err *lspError) {
+	if id == nil {
+		null := json.RawMessage("null")
+		id = &null
+	}
+	if err != nil {
+		s.write(lspMessage{ID: id, Error: err})
+		return
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	s.write(lspMessage{ID: id, Result: result})
+}
+
+// notify は通知を書き出します。
+func (s *LanguageServer) notify(method string, params interface{}) {
+	b, _ := json.Marshal(params)
+	s.write(lspMessage{Method: method, Params: b})
+}
+
+// handle は要求、通知 method を処理し、結果を返します。
+func (s *LanguageServer) handle(method string, raw json.RawMessage) (interface{}, *lspError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   1,
+				"completionProvider": map[string]interface{}{"triggerCharacters": []string{"=", "(", "-", ","}},
+				"definitionProvider": true,
+				"hoverProvider":      true,
+			},
+			"serverInfo": map[string]string{"name": "eeslism"},
+		}, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var p struct {
+			TextDocument lspTextDocument `json:"textDocument"`
+		}
+		if json.Unmarshal(raw, &p) == nil {
+			s.update(p.TextDocument.URI, p.TextDocument.Text)
+		}
+		return nil, nil
+	case "textDocument/didChange":
+		var p struct {
+			TextDocument   lspTextDocument `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if json.Unmarshal(raw, &p) == nil && len(p.ContentChanges) > 0 {
+			s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var p struct {
+			TextDocument lspTextDocument `json:"textDocument"`
+		}
+		if json.Unmarshal(raw, &p) == nil {
+			delete(s.files, p.TextDocument.URI)
+			s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+				"uri": p.TextDocument.URI, "diagnostics": []lspDiagnostic{}})
+		}
+		return nil, nil
+	case "textDocument/completion", "textDocument/definition", "textDocument/hover":
+		var p lspPositionParams
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, &lspError{lspInvalidParams, err.Error()}
+		}
+		text, ok := s.files[p.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+		a := newLSPAnalysis(p.TextDocument.URI, text)
+		switch method {
+		case "textDocument/completion":
+			return a.completion(p.Position, s.docs), nil
+		case "textDocument/definition":
+			if locs := a.definition(p.Position); len(locs) > 0 {
+				return locs, nil
+			}
+		default:
+			if h := a.hover(p.Position, s.docs); h != nil {
+				return h, nil
+			}
+		}
+		return nil, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "textDocument/didSave", "workspace/didChangeConfiguration":
+		return nil, nil
+	}
+	if strings.HasPrefix(method, "$/") {
+		return nil, nil
+	}
+	return nil, &lspError{lspMethodNotFound, "method not found: " + method}
+}
+
+// update は文書 uri の内容を text とし、診断を通知します。
+func (s *LanguageServer) update(uri, text string) {
+	s.files[uri] = text
+	a := newLSPAnalysis(uri, text)
+	s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+		"uri": uri, "diagnostics": a.diagnostics()})
+}
+
+// lspAnalysis は1つの文書の解析結果です。
+type lspAnalysis struct {
+	uri   string
+	lines []string
+	c     *checker // 名前の定義（collect の結果）。文書を読み込めない場合は nil
+	err   error    // 文書を読み込めない場合のエラー
+}
+
+func newLSPAnalysis(uri, text string) *lspAnalysis {
+	a := &lspAnalysis{uri: uri, lines: strings.Split(text, "\n")}
+	for i, l := range a.lines {
+		a.lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	a.c, a.err = newChecker(uriFileName(uri), []byte(text))
+	if a.c != nil {
+		a.c.collect()
+	}
+	return a
+}
+
+// uriFileName は file:// の URI のファイル名を返します。
+func uriFileName(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Path != "" {
+		return path.Base(u.Path)
+	}
+	return path.Base(uri)
+}
+
+// utf16Col は行 line のバイト位置 col の UTF-16 の位置を返します。
+func utf16Col(line string, col int) int {
+	if col > len(line) {
+		col = len(line)
+	}
+	n := 0
+	for _, r := range line[:col] {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteCol は行 line の UTF-16 の位置 col のバイト位置を返します。
+func byteCol(line string, col int) int {
+	n := 0
+	for i, r := range line {
+		if n >= col {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(line)
+}
+
+// lspRangeOf は行 line（1から）、バイト位置 col（1から）、長さ n のトークンの範囲を返します。
+func (a *lspAnalysis) lspRangeOf(line, col, n int) lspRange {
+	if line < 1 || line > len(a.lines) {
+		return lspRange{}
+	}
+	text := a.lines[line-1]
+	return lspRange{
+		Start: lspPosition{line - 1, utf16Col(text, col-1)},
+		End:   lspPosition{line - 1, utf16Col(text, col-1+n)},
+	}
+}
+
+// diagnostics は文書の検査結果を返します。
+func (a *lspAnalysis) diagnostics() []lspDiagnostic {
+	diags := []lspDiagnostic{}
+	if a.err != nil {
+		line := 1
+		if m := regexp.MustCompile(`line (\d+)`).FindStringSubmatch(a.err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return append(diags, lspDiagnostic{Range: a.lspRangeOf(line, 1, len(a.lineText(line))),
+			Severity: 1, Source: "eeslism", Message: a.err.Error()})
+	}
+	a.c.statements(a.c.checkStatement)
+	a.c.unused()
+	for _, d := range a.c.sorted() {
+		sev := 1
+		if d.Severity == SeverityWarning {
+			sev = 2
+		}
+		msg := d.Msg
+		if d.Section != "" {
+			msg = d.Section + ": " + msg
+		}
+		diags = append(diags, lspDiagnostic{Range: a.lspRangeOf(d.Line, d.Col, d.Len), Severity: sev, Source: "eeslism", Message: msg})
+	}
+	return diags
+}
+
+// lineText は行 line（1から）を返します。
+func (a *lspAnalysis) lineText(line int) string {
+	if line < 1 || line > len(a.lines) {
+		return ""
+	}
+	return a.lines[line-1]
+}
+
+// tokenAt は位置 pos のトークン（空白で区切られた語。`;` は除く）と、行内のバイト位置を返します。
+func (a *lspAnalysis) tokenAt(pos lspPosition) (tok string, start int) {
+	text := a.lineText(pos.Line + 1)
+	if i := commentStart(text); i >= 0 {
+		text = text[:i]
+	}
+	col := byteCol(text, pos.Character)
+	fields, offsets := fieldIndex(text)
+	for k, f := range fields {
+		f = strings.TrimSuffix(f, ";")
+		if offsets[k] <= col && col <= offsets[k]+len(f) && f != "" {
+			return f, offsets[k]
+		}
+	}
+	return "", col
+}
+
+// commentStart は行 text の注釈文 `!` の位置を返します。`!=` は注釈文ではありません。ない場合は -1 です。
+func commentStart(text string) int {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '!' {
+			if i+1 < len(text) && text[i+1] == '=' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// context は位置 pos の直前までを読み取った時点のデータセット名と、読み取り中の論理行のトークンを返します。
+// 入力中の語（pos の直前の空白以外の文字）は含めません。
+func (a *lspAnalysis) context(pos lspPosition) (sec string, pending []string, inBlock bool) {
+	p := &docParser{doc: new(InputDocument)}
+	for i := 0; i < pos.Line && i < len(a.lines); i++ {
+		p.lineNo = i + 1
+		if p.line(a.lines[i]) != nil {
+			p.pending = nil
+		}
+	}
+	text := a.lineText(pos.Line + 1)
+	col := byteCol(text, pos.Character)
+	text = text[:col]
+	for len(text) > 0 {
+		r, n := utf8.DecodeLastRuneInString(text)
+		if r == ' ' || r == '\t' {
+			break
+		}
+		text = text[:len(text)-n]
+	}
+	p.lineNo = pos.Line + 1
+	if p.line(text) != nil {
+		p.pending = nil
+	}
+	if p.sec != nil {
+		sec = p.sec.Name
+	}
+	return sec, p.pending, p.inBlock
+}
+
+// names は種類 kind の名前の一覧を返します。
+func (a *lspAnalysis) names(kind string) []string {
+	var names []string
+	for name, d := range a.c.defs[kind] {
+		if kind == kindWALL {
+			_, name, _ = strings.Cut(name, ":")
+		}
+		if d.ref.st != nil || kind == kindCOMP {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completion は位置 pos の補完の候補を返します。
+func (a *lspAnalysis) completion(pos lspPosition, docs *formatDocs) []lspCompletionItem {
+	items := []lspCompletionItem{}
+	add := func(kind int, detail string, labels ...string) {
+		for _, l := range labels {
+			if !slices.ContainsFunc(items, func(it lspCompletionItem) bool { return it.Label == l }) {
+				items = append(items, lspCompletionItem{Label: l, Kind: kind, Detail: detail})
+			}
+		}
+	}
+	addNames := func(kind string) {
+		if a.c != nil {
+			add(lspKindReference, kindNoun(kind), a.names(kind)...)
+		}
+	}
+
+	sec, pending, inBlock := a.context(pos)
+	word, _ := a.tokenAt(pos)
+	text := a.lineText(pos.Line + 1)
+	if start := byteCol(text, pos.Character); len(word) > 0 {
+		// 入力中の語はカーソルの位置まで
+		_, off := a.tokenAt(pos)
+		if start-off < len(word) {
+			word = word[:start-off]
+		}
+	}
+	key, _, hasValue := strings.Cut(word, "=")
+	last := ""
+	if len(pending) > 0 {
+		last = pending[len(pending)-1]
+	}
+
+	if sec == "" {
+		if len(pending) == 0 {
+			add(lspKindKeyword, "data set", inputSections...)
+			add(lspKindKeyword, "", "END", "%s", "%sn")
+		}
+		return items
+	}
+
+	switch {
+	case hasValue:
+		// `キー=値` の値
+		switch {
+		case sec == "ROOM" && key == "e":
+			addNames(kindEXSRF)
+		case sec == "ROOM" && key == "sb", sec == "WINDOW" && key == "sunbrk":
+			addNames(kindSUNBRK)
+		case sec == "ROOM" && key == "r":
+			addNames(kindROOM)
+		case sec == "ROOM" && key == "sw":
+			addNames(kindSCW)
+		case sec == "ROOM" && key == "PCMFurn":
+			addNames(kindPCM)
+		case sec == "CONTL":
+			addNames(kindSCH)
+			addNames(kindSCW)
+			addNames(kindCOMP)
+			addNames(kindPATH)
+		default:
+			addNames(kindSCH)
+		}
+	case sec == "ROOM" && len(last) == 2 && last[0] == '-' && strings.ContainsRune("ERFicfW", rune(last[1])):
+		if last == "-W" {
+			addNames(kindWINDOW)
+		} else {
+			addNames(kindWALL)
+		}
+	case sec == "ROOM" && last == "if":
+		addNames(kindSCH)
+	case sec == "SYSCMP" && last == "-c":
+		addNames(kindCAT)
+	case sec == "SYSCMP" && (last == "-room" || last == "-roomheff"):
+		addNames(kindROOM)
+	case sec == "SYSCMP" && last == "-exs":
+		addNames(kindEXSRF)
+	case sec == "SYSCMP" && len(pending) > 0:
+		add(lspKindKeyword, "option", "-c", "-type", "-Nin", "-Nout", "-in", "-out", "-L", "-env", "-room", "-roomheff",
+			"-exs", "-hcc", "-pfloor", "-S", "-V", "-Tinit", "-wet", "-control", "-monitor", "-PCMweight")
+	case sec == "SYSPTH" && len(pending) > 0:
+		if slices.Contains(pending, ">") {
+			addNames(kindCOMP)
+		} else {
+			add(lspKindKeyword, "option", "-sys", "-f", ">")
+		}
+	case sec == "CONTL":
+		add(lspKindKeyword, "", "if", "AND", "OR", "LOAD", "-e")
+		add(lspKindReference, "outdoor", envNames...)
+		addNames(kindCOMP)
+		addNames(kindPATH)
+		addNames(kindSCH)
+		addNames(kindSCW)
+	case len(pending) == 0 && (sec == "VENT" || sec == "RAICH" || sec == "RESI" || sec == "APPL"):
+		addNames(kindROOM)
+	case len(pending) == 0 && sec == "EQPCAT" && docs != nil:
+		add(lspKindClass, "equipment", docs.groups("EQPCAT")...)
+	}
+
+	// パラメータ名
+	if !hasValue && docs != nil && (len(pending) > 0 || (sec == "ROOM" && inBlock)) && sec != "CONTL" {
+		group := ""
+		if sec == "EQPCAT" {
+			group = pending[0]
+		}
+		for _, k := range docs.keys(sec, group) {
+			add(lspKindProperty, sec, k+"=")
+		}
+	}
+	return items
+}
+
+// wordAt は位置 pos のトークンのうち、カーソルの位置の名前（英数字、`_`、`.`）を返します。
+func (a *lspAnalysis) wordAt(pos lspPosition) (tok, word string, start int) {
+	tok, off := a.tokenAt(pos)
+	col := byteCol(a.lineText(pos.Line+1), pos.Character) - off
+	for _, m := range wordPattern.FindAllStringIndex(tok, -1) {
+		if m[0] <= col && col <= m[1] {
+			return tok, tok[m[0]:m[1]], off + m[0]
+		}
+	}
+	return tok, "", off
+}
+
+// lookup は名前 word の定義を返します。`要素名_変数名` の場合は要素名の定義も探します。
+func (a *lspAnalysis) lookup(word string) []*checkDef {
+	if a.c == nil || word == "" {
+		return nil
+	}
+	candidates := []string{word}
+	if name, _, ok := strings.Cut(word, "_"); ok {
+		candidates = append(candidates, name)
+	}
+	kinds := make([]string, 0, len(a.c.defs))
+	for kind := range a.c.defs {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var defs []*checkDef
+	for _, name := range candidates {
+		for _, kind := range kinds {
+			for n, d := range a.c.defs[kind] {
+				if kind == kindWALL {
+					_, n, _ = strings.Cut(n, ":")
+				}
+				if n == name && d.ref.st != nil && !slices.Contains(defs, d) {
+					defs = append(defs, d)
+				}
+			}
+		}
+		if len(defs) > 0 {
+			break
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return a.c.pos.stmts[defs[i].ref.st][0].Line < a.c.pos.stmts[defs[j].ref.st][0].Line
+	})
+	return defs
+}
+
+// defLocation は定義 d の位置を返します。
+func (a *lspAnalysis) defLocation(d *checkDef) (lspLocation, bool) {
+	at := a.c.pos.stmts[d.ref.st]
+	if d.ref.i < 0 || d.ref.i >= len(at) {
+		return lspLocation{}, false
+	}
+	return lspLocation{URI: a.uri, Range: a.lspRangeOf(at[d.ref.i].Line, at[d.ref.i].Col, len(d.ref.st.tokens()[d.ref.i]))}, true
+}
+
+// definition は位置 pos の名前の定義の位置を返します。
+func (a *lspAnalysis) definition(pos lspPosition) []lspLocation {
+	_, word, _ := a.wordAt(pos)
+	var locs []lspLocation
+	for _, d := range a.lookup(word) {
+		if loc, ok := a.defLocation(d); ok {
+			locs = append(locs, loc)
+		}
+	}
+	return locs
+}
+
+// hover は位置 pos のデータセット名、パラメータ名、名前の説明を返します。
+func (a *lspAnalysis) hover(pos lspPosition, docs *formatDocs) *lspHover {
+	tok, word, start := a.wordAt(pos)
+	if word == "" {
+		return nil
+	}
+	text := a.lineText(pos.Line + 1)
+	rng := lspRange{
+		Start: lspPosition{pos.Line, utf16Col(text, start)},
+		End:   lspPosition{pos.Line, utf16Col(text, start+len(word))},
+	}
+	result := func(s string) *lspHover {
+		return &lspHover{Contents: lspMarkup{Kind: "markdown", Value: s}, Range: rng}
+	}
+
+	sec, pending, _ := a.context(pos)
+	if docs != nil {
+		// データセット名
+		if sec == "" && tok == word && slices.Contains(inputSections, word) {
+			if s := docs.summary(word); s != "" {
+				return result(s)
+			}
+		}
+		// パラメータ名
+		if key := paramKey(tok); key == word && tok[:len(key)] == word && sec != "" {
+			group := ""
+			if sec == "EQPCAT" && len(pending) > 0 {
+				group = pending[0]
+			}
+			if s := docs.param(sec, group, key); s != "" {
+				return result(s)
+			}
+		}
+		// 機器種別
+		if sec == "EQPCAT" && len(pending) == 0 && tok == word {
+			if s := docs.group("EQPCAT", word); s != "" {
+				return result(s)
+			}
+		}
+	}
+
+	// 名前の定義
+	defs := a.lookup(word)
+	if len(defs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, d := range defs {
+		at := a.c.pos.stmts[d.ref.st]
+		fmt.Fprintf(&b, "%s (line %d)\n```\n%s\n```\n", d.ref.sec, at[0].Line, strings.Join(d.ref.st.tokens(), " "))
+	}
+	return result(b.String())
+}
+
+// formatDocs は入力データ形式の説明（format/*.md）の索引です。
+type formatDocs struct {
+	sections map[string]*formatDoc // データセット名と説明
+}
+
+// formatDoc は1つのデータセットの説明です。
+type formatDoc struct {
+	text    string        // .md の内容
+	summary string        // 見出し、概要、データ形式
+	params  []formatParam // パラメータの表の行
+	keys    []string      // 入力例の `キー=` のキー
+	groups  []string      // `### 種別（説明）` の見出しの種別（EQPCAT の機器種別など）
+}
+
+// formatParam はパラメータの表の1行です。
+type formatParam struct {
+	group string // 見出しの種別
+	name  string
+	unit  string
+	desc  string
+	def   string // デフォルト値
+}
+
+var (
+	formatLinkPattern  = regexp.MustCompile(`\[([A-Z,]+)\]\((\w+)\.md\)`)
+	formatGroupPattern = regexp.MustCompile(`^###\s+([A-Z][A-Z0-9_]*)(?:\s|（|\(|$)`)
+	formatKeyPattern   = regexp.MustCompile(`(?:^|[\s(])([A-Za-z][A-Za-z0-9_]*)=`)
+	formatNamePattern  = regexp.MustCompile(`^[A-Za-z*][\w*]*$`)
+)
+
+// loadFormatDocs は fsys の README.md の一覧から各データセットの説明を読み込みます。
+func loadFormatDocs(fsys fs.FS) *formatDocs {
+	if fsys == nil {
+		return nil
+	}
+	readme, err := fs.ReadFile(fsys, "README.md")
+	if err != nil {
+		return nil
+	}
+	docs := &formatDocs{sections: make(map[string]*formatDoc)}
+	for _, m := range formatLinkPattern.FindAllStringSubmatch(string(readme), -1) {
+		b, err := fs.ReadFile(fsys, m[2]+".md")
+		if err != nil {
+			continue
+		}
+		doc := parseFormatDoc(string(b))
+		for _, name := range strings.Split(m[1], ",") {
+			docs.sections[name] = doc
+		}
+	}
+	return docs
+}
+
+// parseFormatDoc は1つのデータセットの説明を読み取ります。
+func parseFormatDoc(text string) *formatDoc {
+	doc := &formatDoc{text: text}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var summary []string
+	heading, group := "", ""
+	inCode := false
+	unitCol, descCol, defCol := -1, -1, -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "#") {
+			heading = line
+			group = ""
+			if m := formatGroupPattern.FindStringSubmatch(line); m != nil {
+				group = m[1]
+				if !slices.Contains(doc.groups, group) {
+					doc.groups = append(doc.groups, group)
+				}
+			}
+		}
+		if i == 0 || strings.HasPrefix(heading, "## 概要") || strings.HasPrefix(heading, "## データ形式") {
+			summary = append(summary, line)
+		}
+
+		if inCode && !strings.HasPrefix(line, "```") {
+			code := line
+			if k := commentStart(code); k >= 0 {
+				code = code[:k]
+			}
+			for _, m := range formatKeyPattern.FindAllStringSubmatch(code, -1) {
+				if !slices.Contains(doc.keys, m[1]) {
+					doc.keys = append(doc.keys, m[1])
+				}
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+		for k := range cells {
+			cells[k] = strings.TrimSpace(cells[k])
+		}
+		if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(strings.TrimLeft(lines[i+1], "|")), ":") ||
+			i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(strings.TrimLeft(lines[i+1], "|")), "-") {
+			// 見出し行
+			unitCol, descCol, defCol = -1, -1, -1
+			for k, c := range cells {
+				switch {
+				case c == "単位":
+					unitCol = k
+				case c == "説明":
+					descCol = k
+				case strings.HasPrefix(c, "デフォルト") || c == "既定値":
+					defCol = k
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(cells[0], ":") || strings.HasPrefix(cells[0], "-") || descCol < 0 || descCol >= len(cells) {
+			continue
+		}
+		cell := func(k int) string {
+			if k < 0 || k >= len(cells) {
+				return ""
+			}
+			return cells[k]
+		}
+		for _, name := range strings.Split(strings.Trim(cells[0], "`"), ",") {
+			name = strings.TrimSuffix(strings.TrimSpace(name), "=")
+			if !formatNamePattern.MatchString(name) {
+				continue
+			}
+			doc.params = append(doc.params, formatParam{group: group, name: name,
+				unit: cell(unitCol), desc: cell(descCol), def: cell(defCol)})
+		}
+	}
+	doc.summary = strings.TrimSpace(strings.Join(summary, "\n"))
+	return doc
+}
+
+// summary はデータセット sec の説明（見出し、概要、データ形式）を返します。
+func (d *formatDocs) summary(sec string) string {
+	if doc := d.sections[sec]; doc != nil {
+		return doc.summary
+	}
+	return ""
+}
+
+// keys はデータセット sec のパラメータ名を返します。group が空でない場合は種別 group のパラメータ名を返します。
+func (d *formatDocs) keys(sec, group string) []string {
+	doc := d.sections[sec]
+	if doc == nil {
+		return nil
+	}
+	if group == "" {
+		return doc.keys
+	}
+	var keys []string
+	for _, p := range doc.params {
+		if p.group == group && !slices.Contains(keys, p.name) && !strings.Contains(p.name, "*") {
+			keys = append(keys, p.name)
+		}
+	}
+	return keys
+}
+
+// groups はデータセット sec の種別（EQPCAT の機器種別など）を返します。
+func (d *formatDocs) groups(sec string) []string {
+	if doc := d.sections[sec]; doc != nil {
+		return doc.groups
+	}
+	return nil
+}
+
+// param はデータセット sec（種別 group）のパラメータ key の説明を返します。
+func (d *formatDocs) param(sec, group, key string) string {
+	doc := d.sections[sec]
+	if doc == nil {
+		return ""
+	}
+	var found *formatParam
+	for i, p := range doc.params {
+		if p.name != key {
+			continue
+		}
+		if p.group == group {
+			found = &doc.params[i]
+			break
+		}
+		if found == nil {
+			found = &doc.params[i]
+		}
+	}
+	if found == nil {
+		// 表にないパラメータはデータ形式、入力例の行を示す
+		inCode := false
+		for _, line := range strings.Split(doc.text, "\n") {
+			if strings.HasPrefix(line, "```") {
+				inCode = !inCode
+				continue
+			}
+			if !inCode {
+				continue
+			}
+			for _, m := range formatKeyPattern.FindAllStringSubmatch(line, -1) {
+				if m[1] == key {
+					return fmt.Sprintf("**%s** (%s)\n```\n%s\n```", key, sec, strings.TrimSpace(line))
+				}
+			}
+		}
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**", found.name)
+	if found.unit != "" && found.unit != "-" {
+		fmt.Fprintf(&b, " [%s]", found.unit)
+	}
+	fmt.Fprintf(&b, " (%s", sec)
+	if found.group != "" {
+		fmt.Fprintf(&b, " %s", found.group)
+	}
+	fmt.Fprintf(&b, ")\n\n%s", found.desc)
+	if found.def != "" && found.def != "-" {
+		fmt.Fprintf(&b, "\n\nデフォルト値: %s", found.def)
+	}
+	return b.String()
+}
+
+// group はデータセット sec の種別 group の見出しとパラメータの一覧を返します。
+func (d *formatDocs) group(sec, group string) string {
+	doc := d.sections[sec]
+	if doc == nil || !slices.Contains(doc.groups, group) {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(doc.text, "\n") {
+		if m := formatGroupPattern.FindStringSubmatch(line); m != nil && m[1] == group {
+			fmt.Fprintf(&b, "**%s**\n", strings.TrimSpace(strings.TrimLeft(line, "#")))
+			break
+		}
+	}
+	for _, p := range doc.params {
+		if p.group == group {
+			fmt.Fprintf(&b, "\n- `%s` %s", p.name, p.desc)
+		}
+	}
+	return b.String()
+}
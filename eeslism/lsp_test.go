@@ -0,0 +1,163 @@
+package eeslism
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// lspSession は要求 reqs を順に送り、shutdown、exit で終わる LSP の通信を行い、応答と通知を返す
+func lspSession(t *testing.T, reqs ...map[string]interface{}) (results map[int]json.RawMessage, notes []lspMessage) {
+	t.Helper()
+	var in bytes.Buffer
+	send := func(msg map[string]interface{}) {
+		msg["jsonrpc"] = "2.0"
+		b, _ := json.Marshal(msg)
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	}
+	send(map[string]interface{}{"id": 0, "method": "initialize", "params": map[string]interface{}{}})
+	for _, r := range reqs {
+		send(r)
+	}
+	send(map[string]interface{}{"id": 999, "method": "shutdown"})
+	send(map[string]interface{}{"method": "exit"})
+
+	var out bytes.Buffer
+	if err := NewLanguageServer(os.DirFS("../format")).Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	results = make(map[int]json.RawMessage)
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readLSPMessage(r)
+		if err != nil {
+			break
+		}
+		var msg struct {
+			lspMessage
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Error != nil {
+			t.Errorf("error response: %+v", msg.Error)
+		}
+		if msg.ID != nil {
+			var id int
+			json.Unmarshal(*msg.ID, &id)
+			results[id] = msg.Result
+		} else {
+			notes = append(notes, msg.lspMessage)
+		}
+	}
+	return results, notes
+}
+
+// lspAt は文書の位置 line、character（0から）の要求を作る
+func lspAt(id int, method string, line, character int) map[string]interface{} {
+	return map[string]interface{}{"id": id, "method": method, "params": map[string]interface{}{
+		"textDocument": map[string]string{"uri": "file:///work/room.txt"},
+		"position":     map[string]int{"line": line, "character": character},
+	}}
+}
+
+// TestLanguageServer は診断、補完、定義へ移動、ホバーを確認する
+func TestLanguageServer(t *testing.T) {
+	open := map[string]interface{}{"method": "textDocument/didOpen", "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": "file:///work/room.txt", "languageId": "eeslism", "version": 1, "text": checkModel},
+	}}
+	results, notes := lspSession(t,
+		open,
+		lspAt(1, "textDocument/completion", 23, 7),   // VENT の室名の後
+		lspAt(2, "textDocument/completion", 18, 12),  // ROOM の -E の後
+		lspAt(3, "textDocument/completion", 26, 9),   // EQPCAT の BOI の仕様
+		lspAt(4, "textDocument/completion", 12, 0),   // データセットの外
+		lspAt(5, "textDocument/definition", 19, 11),  // ROOM の壁体名 Roof
+		lspAt(6, "textDocument/definition", 36, 16),  // CONTL の Boiler1_Tout
+		lspAt(7, "textDocument/hover", 23, 9),        // Vent=
+		lspAt(8, "textDocument/hover", 26, 10),       // EQPCAT BOI の Qo=
+		lspAt(9, "textDocument/hover", 16, 2),        // ROOM
+		lspAt(10, "textDocument/completion", 33, 11), // SYSPTH の要素名
+	)
+
+	// 診断
+	if len(notes) != 1 || notes[0].Method != "textDocument/publishDiagnostics" {
+		t.Fatalf("notifications: %+v", notes)
+	}
+	var diag struct {
+		Diagnostics []lspDiagnostic `json:"diagnostics"`
+	}
+	json.Unmarshal(notes[0].Params, &diag)
+	var errs []string
+	for _, d := range diag.Diagnostics {
+		if d.Severity == 1 {
+			errs = append(errs, fmt.Sprintf("%d:%d-%d", d.Range.Start.Line, d.Range.Start.Character, d.Range.End.Character))
+		}
+	}
+	if got := strings.Join(errs, " "); got != "18:12-18 23:7-27 33:11-17 37:1-14" {
+		t.Errorf("diagnostics: %s", got)
+	}
+
+	labels := func(id int) []string {
+		var items []lspCompletionItem
+		if err := json.Unmarshal(results[id], &items); err != nil {
+			t.Fatalf("%d: %v %s", id, err, results[id])
+		}
+		var l []string
+		for _, it := range items {
+			l = append(l, it.Label)
+		}
+		return l
+	}
+	for id, want := range map[int][]string{
+		1:  {"Vent=", "Inf="},
+		2:  {"ExtWall", "Roof"},
+		3:  {"Qo=", "eff=", "Qmin="},
+		4:  {"ROOM", "WALL", "SYSPTH", "END"},
+		10: {"Boiler1", "Room1", "_OA"},
+	} {
+		got := labels(id)
+		for _, w := range want {
+			if !strings.Contains(" "+strings.Join(got, " ")+" ", " "+w+" ") {
+				t.Errorf("completion %d: %q not in %v", id, w, got)
+			}
+		}
+	}
+
+	var locs []lspLocation
+	json.Unmarshal(results[5], &locs)
+	if len(locs) != 1 || locs[0].Range.Start != (lspPosition{14, 1}) {
+		t.Errorf("definition of Roof: %s", results[5])
+	}
+	locs = nil
+	json.Unmarshal(results[6], &locs)
+	if len(locs) != 1 || locs[0].Range.Start != (lspPosition{29, 1}) {
+		t.Errorf("definition of Boiler1: %s", results[6])
+	}
+
+	for id, want := range map[int]string{7: "Vent=(", 8: "定格能力", 9: "# ROOM"} {
+		var h lspHover
+		json.Unmarshal(results[id], &h)
+		if !strings.Contains(h.Contents.Value, want) {
+			t.Errorf("hover %d: %q not in %q", id, want, h.Contents.Value)
+		}
+	}
+}
+
+// TestLanguageServerPositions は UTF-16 の列とバイト位置の変換を確認する
+func TestLanguageServerPositions(t *testing.T) {
+	line := "\t室 名 Vent=(0.5,Occ)"
+	col := strings.Index(line, "Vent")
+	if got := utf16Col(line, col); got != 5 {
+		t.Errorf("utf16Col = %d", got)
+	}
+	if got := byteCol(line, 5); got != col {
+		t.Errorf("byteCol = %d, want %d", got, col)
+	}
+}
@@ -23,14 +23,12 @@ func Test_Sample(t *testing.T) {
 	// バイナリ全体を巻き添えにするため、入力ファイル側が修正されるまでスキップする。
 	// （入力ファイルの修正は別タスク。testdata の radiant_floor.txt と同一の欠陥）
 	t.Skip("sample radiant_floor_heating.txt has unresolved interior-wall area (A=-999) causing os.Exit — input fix tracked separately")
-	resetPrintStates()
 	Entry("../samples/radiant_floor_heating.txt", "../Base")
 }
 
 // Test_PCMWall_Summer は夏季のPCM壁体シミュレーションをテストする
 // PCMは常に液体状態（室温 > 25°C）
 func Test_PCMWall_Summer(t *testing.T) {
-	resetPrintStates()
 	Entry("../tests/comparison/testdata/L3_system/pcm_wall/pcm_wall_test.txt", "../Base")
 
 	// 出力ファイルの存在確認
@@ -67,7 +65,6 @@ func Test_PCMWall_Summer(t *testing.T) {
 // Test_PCMWall_PhaseChange はPCM相変化が発生する条件でのシミュレーションをテストする
 // PCMは固体↔液体の遷移を繰り返す（室温: 21-25°C）
 func Test_PCMWall_PhaseChange(t *testing.T) {
-	resetPrintStates()
 	Entry("../tests/comparison/testdata/L3_system/pcm_wall/pcm_wall_phase_change_test.txt", "../Base")
 
 	// 出力ファイルの存在確認
@@ -124,12 +124,12 @@ func Boidata(s string, boica *BOICA) int {
 	return id
 }
 
-func (eqcat *EQCAT) Boicaint(Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL) {
+func (eqcat *EQCAT) Boicaint(Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Exsf *EXSFS, Schdl *SCHDL, Ferr io.Writer) {
 	for _, Boica := range eqcat.Boica {
-		if idx, err := idsch(Boica.Qostr, Schdl.Sch, ""); err == nil {
+		if idx, err := idsch(Boica.Qostr, Schdl.Sch, "", Ferr); err == nil {
 			Boica.Qo = &Schdl.Val[idx]
 		} else {
-			Boica.Qo = envptr(Boica.Qostr, Simc, nil, nil, nil)
+			Boica.Qo = envptr(Boica.Qostr, Simc, nil, nil, nil, Ferr)
 		}
 	}
 }
@@ -171,7 +171,7 @@ Boicfv (Boiler Characteristic Function Value Calculation)
 この関数は、ボイラーの運転特性を詳細にモデル化し、
 建物の熱負荷変動に対する熱源設備の応答をシミュレーションするために不可欠な役割を果たします。
 */
-func Boicfv(Boi []*BOI) {
+func Boicfv(Boi []*BOI, Ferr io.Writer) {
 	var cG, Qocat, Temp float64
 
 	for _, boi := range Boi {
@@ -200,7 +200,7 @@ func Boicfv(Boi []*BOI) {
 
 			boi.D1 = 0.0
 
-			cG = Spcheat(Eo1.Fluid) * Eo1.G
+			cG = Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 			boi.cG = cG
 			Eo1.Coeffo = cG
 
@@ -266,7 +266,7 @@ Boiene (Boiler Energy Calculation)
 建物の熱負荷変動に対する熱源設備の応答、
 およびエネルギー消費量を正確にシミュレーションするために不可欠な役割を果たします。
 */
-func Boiene(Boi []*BOI, BOIreset *int) {
+func Boiene(Boi []*BOI, BOIreset *int, Ferr io.Writer) {
 	for i, boi := range Boi {
 		boi.Tin = boi.Cmp.Elins[0].Sysvin
 		Qmin := boi.Cat.Qmin
@@ -324,7 +324,7 @@ func Boiene(Boi []*BOI, BOIreset *int) {
 			}
 
 			if reset == 1 {
-				Boicfv(Boi[i : i+1])
+				Boicfv(Boi[i:i+1], Ferr)
 				(*BOIreset)++
 			}
 
@@ -553,7 +553,7 @@ boiday (Boiler Daily and Monthly Data Aggregation)
 この関数は、建物のエネルギー消費量を多角的に分析し、
 運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func boiday(Mon, Day, ttmm int, Boi []*BOI, Nday, SimDayend int) {
+func boiday(Mon, Day, ttmm int, Boi []*BOI, Nday, SimDayend int, Cff_kWh float64) {
 	var Mo, tt int
 
 	Mo = Mon - 1
@@ -561,15 +561,15 @@ func boiday(Mon, Day, ttmm int, Boi []*BOI, Nday, SimDayend int) {
 	for _, boi := range Boi {
 		// 日集計
 		svdaysum(int64(ttmm), boi.Cmp.Control, boi.Tin, &boi.Tidy)
-		qdaysum(int64(ttmm), boi.Cmp.Control, boi.Q, &boi.Qdy)
-		edaysum(ttmm, boi.Cmp.Control, boi.E, &boi.Edy)
-		edaysum(ttmm, boi.Cmp.Control, boi.Ph, &boi.Phdy)
+		qdaysum(int64(ttmm), boi.Cmp.Control, boi.Q, &boi.Qdy, Cff_kWh)
+		edaysum(ttmm, boi.Cmp.Control, boi.E, &boi.Edy, Cff_kWh)
+		edaysum(ttmm, boi.Cmp.Control, boi.Ph, &boi.Phdy, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.Tin, &boi.mTidy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.Q, &boi.mQdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.E, &boi.mEdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.Ph, &boi.mPhdy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.Q, &boi.mQdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.E, &boi.mEdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, boi.Cmp.Control, boi.Ph, &boi.mPhdy, Nday, SimDayend, Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, boi.Cmp.Control, boi.E, &boi.MtEdy[Mo][tt])
@@ -665,7 +665,7 @@ func boimonprt(fo io.Writer, id int, Boi []*BOI) {
 	}
 }
 
-func boimtprt(fo io.Writer, id int, Boi []*BOI, Mo int, tt int) {
+func boimtprt(fo io.Writer, id int, Boi []*BOI, Mo int, tt int, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(Boi) > 0 {
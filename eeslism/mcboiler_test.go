@@ -224,7 +224,7 @@ func TestBoicfv(t *testing.T) {
 		origDo1, origD1_1 := boi1.Do, boi1.D1
 		origDo2, origD1_2 := boi2.Do, boi2.D1
 
-		Boicfv(bois)
+		Boicfv(bois, nil)
 
 		// Check that coefficients were calculated
 		if boi1.Do == origDo1 && boi1.D1 == origD1_1 {
@@ -256,7 +256,7 @@ func TestBoicfv_EmptyArray(t *testing.T) {
 	var bois []*BOI
 
 	// Should not panic
-	Boicfv(bois)
+	Boicfv(bois, nil)
 }
 
 func TestBoicfv_NilCatalog(t *testing.T) {
@@ -312,7 +312,7 @@ func TestBoiene(t *testing.T) {
 		// Store original energy value
 		origE := boi.E
 
-		Boiene(bois, &boiReset)
+		Boiene(bois, &boiReset, nil)
 
 		// Check that energy consumption was calculated
 		if boi.E == origE {
@@ -337,7 +337,7 @@ func TestBoiene(t *testing.T) {
 		// Set control to OFF to simulate zero heat output
 		boi.Cmp.Elouts[0].Control = OFF_SW
 
-		Boiene(bois, &boiReset)
+		Boiene(bois, &boiReset, nil)
 
 		// Energy consumption should be zero when control is OFF
 		if boi.E != 0.0 {
@@ -393,7 +393,7 @@ func TestBoiene_UnlimitedCapacity(t *testing.T) {
 	bois := []*BOI{boi}
 	boiReset := 0
 
-	Boiene(bois, &boiReset)
+	Boiene(bois, &boiReset, nil)
 
 	// Should handle unlimited capacity without issues
 	if boi.E <= 0 {
@@ -447,7 +447,7 @@ func TestBoiene_MinimumOutput(t *testing.T) {
 	bois := []*BOI{boi}
 	boiReset := 0
 
-	Boiene(bois, &boiReset)
+	Boiene(bois, &boiReset, nil)
 
 	// Behavior depends on implementation, but should handle minimum output constraint
 	// Energy consumption should be calculated appropriately
@@ -515,11 +515,11 @@ func TestBoiDataIntegration(t *testing.T) {
 	bois := []*BOI{boi}
 	
 	// Calculate coefficients
-	Boicfv(bois)
+	Boicfv(bois, nil)
 	
 	// Calculate energy consumption
 	boiReset := 0
-	Boiene(bois, &boiReset)
+	Boiene(bois, &boiReset, nil)
 
 	// Verify energy calculation
 	if boi.E <= 0 {
@@ -727,7 +727,7 @@ func TestBoiday(t *testing.T) {
 		// Simulate aggregation at several time steps
 		times := []int{900, 1000, 1100, 1200, 1300, 1400}
 		for _, ttmm := range times {
-			boiday(1, 15, ttmm, bois, 31, 365)
+			boiday(1, 15, ttmm, bois, 31, 365, 0.0)
 		}
 
 		// Verify daily aggregation was performed
@@ -764,11 +764,11 @@ func TestBoiday(t *testing.T) {
 		boidyint(bois)
 
 		// Simulate some time steps
-		boiday(1, 15, 1000, bois, 31, 365)
-		boiday(1, 15, 1100, bois, 31, 365)
+		boiday(1, 15, 1000, bois, 31, 365, 0.0)
+		boiday(1, 15, 1100, bois, 31, 365, 0.0)
 
 		// End of day (ttmm = 2400 triggers average calculation for SVDAY)
-		boiday(1, 15, 2400, bois, 31, 365)
+		boiday(1, 15, 2400, bois, 31, 365, 0.0)
 
 		// Verify end-of-day processing
 		// At 2400, svdaysum divides M by Hrs to get average
@@ -799,7 +799,7 @@ func TestBoiday(t *testing.T) {
 		boimonint(bois)
 
 		// Simulate aggregation
-		boiday(1, 15, 1200, bois, 31, 365)
+		boiday(1, 15, 1200, bois, 31, 365, 0.0)
 
 		// Verify monthly aggregation was performed
 		if boi.mTidy.Hrs != 1 {
@@ -829,7 +829,7 @@ func TestBoiday(t *testing.T) {
 		boidyint(bois)
 
 		// Simulate aggregation
-		boiday(1, 15, 1200, bois, 31, 365)
+		boiday(1, 15, 1200, bois, 31, 365, 0.0)
 
 		// Verify no aggregation when control is OFF
 		if boi.Tidy.Hrs != 0 {
@@ -842,7 +842,7 @@ func TestBoiday(t *testing.T) {
 
 	t.Run("EmptyList", func(t *testing.T) {
 		// Should not panic with empty list
-		boiday(1, 15, 1200, []*BOI{}, 31, 365)
+		boiday(1, 15, 1200, []*BOI{}, 31, 365, 0.0)
 	})
 
 	t.Run("MultipleBoilers", func(t *testing.T) {
@@ -861,7 +861,7 @@ func TestBoiday(t *testing.T) {
 		bois := []*BOI{boi1, boi2}
 
 		boidyint(bois)
-		boiday(1, 15, 1200, bois, 31, 365)
+		boiday(1, 15, 1200, bois, 31, 365, 0.0)
 
 		// Verify both boilers were aggregated
 		for i, boi := range bois {
@@ -173,14 +173,14 @@ Desiint (Desiccant Initialization)
 室内温湿度環境の予測、潜熱負荷の処理、
 およびエネルギー消費量予測を行うための重要な初期設定と検証機能を提供します。
 */
-func Desiint(Desi []*DESI, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
+func Desiint(Desi []*DESI, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Ferr io.Writer) {
 	var Err string
 	var Desica *DESICA
 
 	for _, desi := range Desi {
 
 		if desi.Cmp.Envname != "" {
-			desi.Tenv = envptr(desi.Cmp.Envname, Simc, Compnt, Wd, nil)
+			desi.Tenv = envptr(desi.Cmp.Envname, Simc, Compnt, Wd, nil, Ferr)
 		} else {
 			desi.Room = roomptr(desi.Cmp.Roomname, Compnt)
 		}
@@ -189,23 +189,23 @@ func Desiint(Desi []*DESI, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
 
 		if Desica.Uad < 0.0 {
 			Err = fmt.Sprintf("Name=%s  Uad=%.4g", Desica.name, Desica.Uad)
-			Eprint("Desiint", Err)
+			Eprint("Desiint", Err, Ferr)
 		}
 		if Desica.A < 0.0 {
 			Err = fmt.Sprintf("Name=%s  A=%.4g", Desica.name, Desica.A)
-			Eprint("Desiint", Err)
+			Eprint("Desiint", Err, Ferr)
 		}
 		if Desica.r < 0.0 {
 			Err = fmt.Sprintf("Name=%s  r=%.4g", Desica.name, Desica.r)
-			Eprint("Desiint", Err)
+			Eprint("Desiint", Err, Ferr)
 		}
 		if Desica.rows < 0.0 {
 			Err = fmt.Sprintf("Name=%s  rows=%.4g", Desica.name, Desica.rows)
-			Eprint("Desiint", Err)
+			Eprint("Desiint", Err, Ferr)
 		}
 		if Desica.ms < 0.0 {
 			Err = fmt.Sprintf("Name=%s  ms=%.4g", Desica.name, Desica.ms)
-			Eprint("Desiint", Err)
+			Eprint("Desiint", Err, Ferr)
 		}
 
 		// 初期温度、出入口温度の初期化
@@ -266,7 +266,7 @@ Desicfv (Desiccant Characteristic Function Value Calculation)
 室内温湿度環境の予測、潜熱負荷の処理、
 およびエネルギー消費量予測を行うための重要な役割を果たします。
 */
-func Desicfv(Desi []*DESI) {
+func Desicfv(Desi []*DESI, DTM float64, Ferr io.Writer) {
 	var Eo1 *ELOUT
 	var h, i, j float64
 	var Te, hsa, hsad, hAsa, hdAsa float64
@@ -291,7 +291,7 @@ func Desicfv(Desi []*DESI) {
 
 		Eo1 = desi.Cmp.Elouts[0]
 		// 熱容量流量の計算
-		desi.CG = Spcheat(Eo1.Fluid) * Eo1.G
+		desi.CG = Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 
 		// シリカゲルと槽内空気の対流熱伝達率の計算
 		if Eo1.Cmp.Control == OFF_SW {
@@ -340,7 +340,7 @@ func Desicfv(Desi []*DESI) {
 		U[4*N+4] = i
 
 		// 逆行列の計算
-		Matinv(U, N, N, "<Desicfv U>")
+		Matinv(U, N, N, "<Desicfv U>", Ferr)
 
 		// 行列のコピー
 		matinit(desi.UX, N2)
@@ -592,7 +592,7 @@ Desiday (Desiccant Daily Data Aggregation)
 この関数は、デシカント空調機の運転状況と熱湿気交換量を日単位で詳細に分析し、
 空調システムの運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func Desiday(Mon, Day, ttmm int, Desi []*DESI, Nday, SimDayend int) {
+func Desiday(Mon, Day, ttmm int, Desi []*DESI, Nday, SimDayend int, Cff_kWh float64) {
 	// Mo := Mon - 1
 	// tt := ConvertHour(ttmm)
 
@@ -604,10 +604,10 @@ func Desiday(Mon, Day, ttmm int, Desi []*DESI, Nday, SimDayend int) {
 		svdaysum(int64(ttmm), desi.Cmp.Control, desi.Xain, &desi.xidy)
 		svdaysum(int64(ttmm), desi.Cmp.Control, desi.Xaout, &desi.xody)
 		svdaysum(int64(ttmm), desi.Cmp.Control, desi.Xsold, &desi.xsdy)
-		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Qs, &desi.Qsdy)
-		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Ql, &desi.Qldy)
-		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Qt, &desi.Qtdy)
-		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Qloss, &desi.Qlsdy)
+		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Qs, &desi.Qsdy, Cff_kWh)
+		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Ql, &desi.Qldy, Cff_kWh)
+		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Qt, &desi.Qtdy, Cff_kWh)
+		qdaysum(int64(ttmm), desi.Cmp.Control, desi.Qloss, &desi.Qlsdy, Cff_kWh)
 	}
 }
 
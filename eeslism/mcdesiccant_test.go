@@ -17,7 +17,7 @@ func TestDesiint(t *testing.T) {
 			}
 		}()
 
-		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT())
+		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT(), nil)
 
 		// Verify initialization
 		if desi.Cat != nil {
@@ -38,7 +38,7 @@ func TestDesiint(t *testing.T) {
 			}
 		}()
 
-		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT())
+		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT(), nil)
 
 		// Verify regenerative configuration
 		t.Log("Regenerative desiccant system initialized")
@@ -57,7 +57,7 @@ func TestDesiint(t *testing.T) {
 			}
 		}()
 
-		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT())
+		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT(), nil)
 
 		// Verify solid desiccant configuration
 		t.Log("Solid desiccant system initialized")
@@ -79,7 +79,7 @@ func TestDesiint(t *testing.T) {
 			}
 		}()
 
-		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT())
+		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT(), nil)
 		t.Log("Multiple DESI initialization completed successfully")
 	})
 
@@ -93,7 +93,7 @@ func TestDesiint(t *testing.T) {
 			}
 		}()
 
-		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT())
+		Desiint(desis, createBasicSIMCONTL(), createBasicCOMPNT(), createBasicWDAT(), nil)
 		t.Log("Empty DESI list handled successfully")
 	})
 }
@@ -111,7 +111,7 @@ func TestDesicfv(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 
 		// Verify coefficient calculations
 		if desi.Cmp != nil && len(desi.Cmp.Elouts) > 0 {
@@ -132,7 +132,7 @@ func TestDesicfv(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 
 		// Verify dehumidification coefficients
 		t.Log("Dehumidification coefficient calculation verified")
@@ -151,7 +151,7 @@ func TestDesicfv(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 
 		// Verify regeneration coefficients
 		t.Log("Regeneration coefficient calculation verified")
@@ -170,7 +170,7 @@ func TestDesicfv(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 		t.Log("Off control coefficient calculation completed successfully")
 	})
 }
@@ -314,7 +314,7 @@ func TestDESI_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 		Desiene(desis)
 
 		// Verify humidity ranges are physically reasonable
@@ -334,7 +334,7 @@ func TestDESI_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 		Desiene(desis)
 
 		// Verify temperature ranges are physically reasonable
@@ -354,7 +354,7 @@ func TestDESI_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 		Desiene(desis)
 
 		// Verify efficiency values are within reasonable range
@@ -377,7 +377,7 @@ func TestDESI_PerformanceCharacteristics(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 		Desiene(desis)
 
 		// Calculate and verify dehumidification effectiveness
@@ -397,7 +397,7 @@ func TestDESI_PerformanceCharacteristics(t *testing.T) {
 			}
 		}()
 
-		Desicfv(desis)
+		Desicfv(desis, 3600.0, nil)
 		Desiene(desis)
 
 		// Verify regeneration performance
@@ -18,7 +18,9 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
+	"io/fs"
 	"strings"
 )
 
@@ -56,7 +58,7 @@ Eqcadata (Equipment Catalog Data Input)
 システム全体のエネルギー消費量予測、省エネルギー対策の検討、
 および最適な設備システム設計を行うための重要な役割を果たします。
 */
-func Eqcadata(f *EeTokens, Eqcat *EQCAT, efl_path string) {
+func Eqcadata(f *EeTokens, Eqcat *EQCAT, efl fs.FS, Ferr io.Writer) {
 	if Eqcat == nil {
 		panic("Eqcat is nil")
 	}
@@ -87,10 +89,10 @@ func Eqcadata(f *EeTokens, Eqcat *EQCAT, efl_path string) {
 	Eqcat.Evacca = make([]*EVACCA, 0)
 
 	// 圧縮機特性リストを reflist.efl から読み取る
-	Eqcat.Rfcmp = Refcmpdat(efl_path)
+	Eqcat.Rfcmp = Refcmpdat(efl, Ferr)
 
 	// ポンプ・ファンの部分負荷特性の近似式係数 を pumpfanlst.efl から読み取る
-	Eqcat.Pfcmp = PFcmpdata(efl_path)
+	Eqcat.Pfcmp = PFcmpdata(efl, Ferr)
 
 	E = fmt.Sprintf(ERRFMT, dsn)
 
@@ -155,7 +157,8 @@ func Eqcadata(f *EeTokens, Eqcat *EQCAT, efl_path string) {
 		} else if eqpType == REFACOMP_TYPE {
 			Refaca := new(REFACA)
 			Refaca.name = ""
-			ReadCatalogData(f, func(ss string) int { return Refadata(ss, Refaca, Eqcat.Rfcmp) }, s, E)
+			var hpch *HPCH
+			ReadCatalogData(f, func(ss string) int { return Refadata(ss, Refaca, Eqcat.Rfcmp, &hpch) }, s, E)
 			Eqcat.Refaca = append(Eqcat.Refaca, Refaca)
 		} else if eqpType == PIPEDUCT_TYPE || eqpType == DUCT_TYPE {
 			Pipeca := new(PIPECA)
@@ -97,19 +97,19 @@ func Evacdata(s string, Evacca *EVACCA) int {
 
 /* ------------------------------------------------------ */
 // 初期設定（入力漏れのチェック、変数用メモリの確保）
-func Evacint(Evac []*EVAC) {
+func Evacint(Evac []*EVAC, Ferr io.Writer) {
 	for _, evac := range Evac {
 		cat := evac.Cat
 
 		// 入力漏れのチェック
 		if cat.N < 0 {
 			msg := fmt.Sprintf("Name=%s catname=%s 分割数が未定義です", evac.Name, cat.Name)
-			Eprint("<Evacint>", msg)
+			Eprint("<Evacint>", msg, Ferr)
 		}
 		if cat.Adry < 0.0 || cat.Awet < 0.0 || (cat.Nlayer < 0 && (cat.hdry < 0.0 || cat.hwet < 0.0)) {
 			msg := fmt.Sprintf("Name=%s catname=%s Adry=%.1g Awet=%.1g hdry=%.1g hwet=%.1g\n",
 				evac.Name, cat.Name, cat.Adry, cat.Awet, cat.hdry, cat.hwet)
-			Eprint("<Evacint>", msg)
+			Eprint("<Evacint>", msg, Ferr)
 		}
 
 		// 面積を分割後の面積に変更
@@ -280,7 +280,7 @@ func EvacNu(AR, Re float64) float64 {
 }
 
 // 要素方程式の係数計算
-func Evaccfv(Evac []*EVAC) {
+func Evaccfv(Evac []*EVAC, Ferr io.Writer) {
 	for _, evac := range Evac {
 		EvpFlg := make([]float64, evac.Cat.N)
 		if evac.Cmp.Control != OFF_SW {
@@ -374,7 +374,7 @@ func Evaccfv(Evac []*EVAC) {
 				}
 			}
 
-			Matinv(U, N, N, "Evaccfv U") // 行列Uの逆行列を計算
+			Matinv(U, N, N, "Evaccfv U", Ferr) // 行列Uの逆行列を計算
 			matinit(evac.UX, N2)         // 行列の初期化
 			matcpy(U, evac.UX, N2)       // 行列のコピー
 
@@ -127,7 +127,7 @@ func TestEvacint(t *testing.T) {
 			}
 		}()
 
-		Evacint(evacs)
+		Evacint(evacs, nil)
 
 		// Verify initialization
 		if evac.Cat != nil {
@@ -148,7 +148,7 @@ func TestEvacint(t *testing.T) {
 			}
 		}()
 
-		Evacint(evacs)
+		Evacint(evacs, nil)
 
 		// Verify direct evaporative configuration
 		t.Log("Direct evaporative cooling system initialized")
@@ -167,7 +167,7 @@ func TestEvacint(t *testing.T) {
 			}
 		}()
 
-		Evacint(evacs)
+		Evacint(evacs, nil)
 
 		// Verify indirect evaporative configuration
 		t.Log("Indirect evaporative cooling system initialized")
@@ -189,7 +189,7 @@ func TestEvacint(t *testing.T) {
 			}
 		}()
 
-		Evacint(evacs)
+		Evacint(evacs, nil)
 		t.Log("Multiple EVAC initialization completed successfully")
 	})
 
@@ -203,7 +203,7 @@ func TestEvacint(t *testing.T) {
 			}
 		}()
 
-		Evacint(evacs)
+		Evacint(evacs, nil)
 		t.Log("Empty EVAC list handled successfully")
 	})
 }
@@ -221,7 +221,7 @@ func TestEvaccfv(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 
 		// Verify coefficient calculations
 		if evac.Cmp != nil && len(evac.Cmp.Elouts) > 0 {
@@ -242,7 +242,7 @@ func TestEvaccfv(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 
 		// Verify direct evaporative coefficients
 		t.Log("Direct evaporative coefficient calculation verified")
@@ -261,7 +261,7 @@ func TestEvaccfv(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 
 		// Verify indirect evaporative coefficients
 		t.Log("Indirect evaporative coefficient calculation verified")
@@ -280,7 +280,7 @@ func TestEvaccfv(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 		t.Log("Off control coefficient calculation completed successfully")
 	})
 }
@@ -431,7 +431,7 @@ func TestEVAC_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 		var evacreset int
 		Evacene(evacs, &evacreset)
 
@@ -453,7 +453,7 @@ func TestEVAC_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 		var evacreset int
 		Evacene(evacs, &evacreset)
 
@@ -475,7 +475,7 @@ func TestEVAC_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 		var evacreset int
 		Evacene(evacs, &evacreset)
 
@@ -499,7 +499,7 @@ func TestEVAC_PerformanceCharacteristics(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 		var evacreset int
 		Evacene(evacs, &evacreset)
 
@@ -520,7 +520,7 @@ func TestEVAC_PerformanceCharacteristics(t *testing.T) {
 			}
 		}()
 
-		Evaccfv(evacs)
+		Evaccfv(evacs, nil)
 		var evacreset int
 		Evacene(evacs, &evacreset)
 
@@ -598,7 +598,7 @@ func createIndirectEvaporativeEVAC() *EVAC {
 func createCoefficientTestEVAC() *EVAC {
 	evac := createBasicEVAC()
 	// Initialize with Evacint to allocate memory
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up for coefficient calculation
 	for i := range evac.Cmp.Elouts {
 		evac.Cmp.Elouts[i].G = 0.5
@@ -609,7 +609,7 @@ func createCoefficientTestEVAC() *EVAC {
 
 func createDirectEvaporativeCoefficientEVAC() *EVAC {
 	evac := createDirectEvaporativeEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	for i := range evac.Cmp.Elouts {
 		evac.Cmp.Elouts[i].G = 0.5
 		evac.Cmp.Elouts[i].Fluid = AIR_FLD
@@ -619,7 +619,7 @@ func createDirectEvaporativeCoefficientEVAC() *EVAC {
 
 func createIndirectEvaporativeCoefficientEVAC() *EVAC {
 	evac := createIndirectEvaporativeEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	for i := range evac.Cmp.Elouts {
 		evac.Cmp.Elouts[i].G = 0.5
 		evac.Cmp.Elouts[i].Fluid = AIR_FLD
@@ -639,7 +639,7 @@ func createOffControlEVAC() *EVAC {
 func createEnergyTestEVAC() *EVAC {
 	evac := createBasicEVAC()
 	// Initialize with Evacint to allocate memory
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up for energy calculation with realistic values
 	evac.Tdryi = 35.0   // Hot dry side inlet air temperature
 	evac.Xdryi = 0.008  // Low dry side inlet humidity (dry air)
@@ -688,7 +688,7 @@ func createOffControlEnergyEVAC() *EVAC {
 
 func createTemperatureValidationEVAC() *EVAC {
 	evac := createBasicEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up realistic temperature conditions for evaporative cooling
 	evac.Tdryi = 40.0   // Hot dry side inlet air
 	evac.Tdryo = 28.0   // Cooled dry side outlet air
@@ -699,7 +699,7 @@ func createTemperatureValidationEVAC() *EVAC {
 
 func createHumidityValidationEVAC() *EVAC {
 	evac := createBasicEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up realistic humidity conditions
 	evac.Xdryi = 0.005  // Dry side inlet air
 	evac.Xdryo = 0.012  // Dry side outlet air (humidified)
@@ -710,7 +710,7 @@ func createHumidityValidationEVAC() *EVAC {
 
 func createEffectivenessValidationEVAC() *EVAC {
 	evac := createBasicEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up for effectiveness validation
 	evac.Gdry = 0.5
 	evac.Gwet = 0.5
@@ -719,7 +719,7 @@ func createEffectivenessValidationEVAC() *EVAC {
 
 func createCoolingEffectivenessTestEVAC() *EVAC {
 	evac := createBasicEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up for cooling effectiveness testing
 	evac.Tdryi = 38.0   // Hot dry side inlet
 	evac.Tdryo = 26.0   // Cooled dry side outlet
@@ -730,7 +730,7 @@ func createCoolingEffectivenessTestEVAC() *EVAC {
 
 func createWaterConsumptionEVAC() *EVAC {
 	evac := createBasicEVAC()
-	Evacint([]*EVAC{evac})
+	Evacint([]*EVAC{evac}, nil)
 	// Set up for water consumption calculation
 	evac.Xdryi = 0.006  // Dry side inlet air
 	evac.Xdryo = 0.014  // Dry side outlet air (humidified)
@@ -182,7 +182,7 @@ Hcccfv (Heating/Cooling Coil Characteristic Function Value Calculation)
 空調システムの熱負荷計算、室内温湿度環境の予測、
 およびエネルギー消費量予測を行うための重要な役割を果たします。
 */
-func Hcccfv(_hcc []*HCC) {
+func Hcccfv(_hcc []*HCC, Ferr io.Writer) {
 	for _, hcc := range _hcc {
 		hcc.Ga = 0.0
 		hcc.Gw = 0.0
@@ -207,7 +207,7 @@ func Hcccfv(_hcc []*HCC) {
 
 		// 排気量・排気熱量
 		hcc.Ga = eo_ta.G                        // 排気量
-		hcc.cGa = Spcheat(eo_ta.Fluid) * hcc.Ga // 排気熱量
+		hcc.cGa = Spcheat(eo_ta.Fluid, Ferr) * hcc.Ga // 排気熱量
 		if hcc.Ga > 0.0 {
 			AirSW = ON_SW
 		} else {
@@ -216,7 +216,7 @@ func Hcccfv(_hcc []*HCC) {
 
 		// 排水量・排水熱量
 		hcc.Gw = eo_tw.G                        // 排水量
-		hcc.cGw = Spcheat(eo_tw.Fluid) * hcc.Gw // 排水熱量
+		hcc.cGw = Spcheat(eo_tw.Fluid, Ferr) * hcc.Gw // 排水熱量
 		if hcc.Gw > 0.0 {
 			WaterSW = ON_SW
 		} else {
@@ -296,7 +296,7 @@ Hccdwreset (Heating/Cooling Coil Dry/Wet Reset)
 空調システムの熱負荷計算、特に潜熱負荷の処理、
 および室内温湿度環境の予測精度を向上させるために不可欠な役割を果たします。
 */
-func Hccdwreset(Hcc []*HCC, DWreset *int) {
+func Hccdwreset(Hcc []*HCC, DWreset *int, Ferr io.Writer) {
 	for i, hcc := range Hcc {
 		xain := hcc.Cmp.Elins[1].Sysvin // <給気>絶対湿度 [kg/kg]
 		Twin := hcc.Cmp.Elins[2].Sysvin // <給水>温水の温度 [C]
@@ -316,7 +316,7 @@ func Hccdwreset(Hcc []*HCC, DWreset *int) {
 
 			if reset {
 				(*DWreset)++
-				Hcccfv(Hcc[i : i+1])
+				Hcccfv(Hcc[i:i+1], Ferr)
 			}
 		}
 	}
@@ -520,23 +520,23 @@ hccday (Heating/Cooling Coil Daily and Monthly Data Aggregation)
 この関数は、冷温水コイルの運転状況と熱交換量を多角的に分析し、
 空調システムの運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func hccday(Mon, Day, ttmm int, Hcc []*HCC, Nday, SimDayend int) {
+func hccday(Mon, Day, ttmm int, Hcc []*HCC, Nday, SimDayend int, Cff_kWh float64) {
 	for _, hcc := range Hcc {
 		// 日集計
 		svdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Tain, &hcc.Taidy)
 		svdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Xain, &hcc.xaidy)
 		svdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Twin, &hcc.Twidy)
-		qdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Qs, &hcc.Qdys)
-		qdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Ql, &hcc.Qdyl)
-		qdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Qt, &hcc.Qdyt)
+		qdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Qs, &hcc.Qdys, Cff_kWh)
+		qdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Ql, &hcc.Qdyl, Cff_kWh)
+		qdaysum(int64(ttmm), hcc.Cmp.Control, hcc.Qt, &hcc.Qdyt, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Tain, &hcc.mTaidy, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Xain, &hcc.mxaidy, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Twin, &hcc.mTwidy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Qs, &hcc.mQdys, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Ql, &hcc.mQdyl, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Qt, &hcc.mQdyt, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Qs, &hcc.mQdys, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Ql, &hcc.mQdyl, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, hcc.Cmp.Control, hcc.Qt, &hcc.mQdyt, Nday, SimDayend, Cff_kWh)
 	}
 }
 
@@ -239,7 +239,7 @@ func TestHcccfv(t *testing.T) {
 		origEt := hcc.Et
 		origEx := hcc.Ex
 
-		Hcccfv(hccs)
+		Hcccfv(hccs, nil)
 
 		// Check that coefficients were calculated
 		if hcc.Et == origEt && hcc.Ex == origEx {
@@ -74,7 +74,7 @@ func Hclelm(Hcload []*HCLOAD) {
 
 /* ルームエアコン（事業主基準モデル）機器仕様の入力処理 */
 
-func rmacdat(Hcld *HCLOAD) {
+func rmacdat(Hcld *HCLOAD, Ferr io.Writer) {
 	const (
 		ERRFMT = "%s (rmacdat)"
 		SCHAR  = 256
@@ -104,7 +104,7 @@ func rmacdat(Hcld *HCLOAD) {
 
 		keyValue := strings.SplitN(string(s), "=", 2)
 		if len(keyValue) != 2 {
-			Eprint("<rmacdat>", string(s))
+			Eprint("<rmacdat>", string(s), Ferr)
 			continue
 		}
 
@@ -135,7 +135,7 @@ func rmacdat(Hcld *HCLOAD) {
 				panic(err)
 			}
 		default:
-			Eprint("<rmacdat>", key)
+			Eprint("<rmacdat>", key, Ferr)
 		}
 	}
 
@@ -151,7 +151,7 @@ func rmacdat(Hcld *HCLOAD) {
 
 /* ルームエアコン（電中研モデル）機器仕様の入力処理 */
 
-func rmacddat(Hcld *HCLOAD) {
+func rmacddat(Hcld *HCLOAD, Ferr io.Writer) {
 	//Err := fmt.Sprintf(ERRFMT, "(rmacddat)")
 
 	ss := Hcld.Cmp.Tparm
@@ -204,10 +204,10 @@ func rmacddat(Hcld *HCLOAD) {
 			case "Go":
 				Hcld.Go, _ = strconv.ParseFloat(value, 64)
 			default:
-				Eprint("<rmacddat>", key)
+				Eprint("<rmacddat>", key, Ferr)
 			}
 		} else {
-			Eprint("<rmacddat>", s)
+			Eprint("<rmacddat>", s, Ferr)
 		}
 	}
 
@@ -268,7 +268,7 @@ func rmacddat(Hcld *HCLOAD) {
 		}
 
 		// Uの逆行列の計算
-		Matinv(U, 3, 3, "<rmacddat> UX")
+		Matinv(U, 3, 3, "<rmacddat> UX", Ferr)
 
 		// 回帰係数の計算
 		//Hcld.Rc = make([]float64, 3)
@@ -335,7 +335,7 @@ func rmacddat(Hcld *HCLOAD) {
 		}
 
 		// Uの逆行列の計算
-		Matinv(U, 3, 3, "<rmacddat> UX")
+		Matinv(U, 3, 3, "<rmacddat> UX", Ferr)
 
 		// 回帰係数の計算
 		//Hcld.Rh = make([]float64, 3)
@@ -410,7 +410,7 @@ func FNTevph(Tco, Qc, Eh, cao, Go, xco float64) float64 {
 // +--------+ ---> [OUT 1]
 // | HCLOAD | ---> [OUT 2]
 // +--------+ ---> [OUT 3] 冷温水コイル想定時のみ
-func Hcldcfv(_Hcload []*HCLOAD) {
+func Hcldcfv(_Hcload []*HCLOAD, Ferr io.Writer) {
 	var f0, f1 float64
 
 	Tout15 := 15.0
@@ -433,7 +433,7 @@ func Hcldcfv(_Hcload []*HCLOAD) {
 
 		if Eo1.Control != OFF_SW {
 			Hcload.Ga = Eo1.G
-			Hcload.CGa = Spcheat(Eo1.Fluid) * Hcload.Ga
+			Hcload.CGa = Spcheat(Eo1.Fluid, Ferr) * Hcload.Ga
 
 			Eo1.Coeffo = Hcload.CGa
 			Eo1.Co = 0.0
@@ -460,7 +460,7 @@ func Hcldcfv(_Hcload []*HCLOAD) {
 			Eo3 := Hcload.Cmp.Elouts[2]
 			if Eo3.Control != OFF_SW {
 				Hcload.Gw = Eo3.G
-				Hcload.CGw = Spcheat(Eo3.Fluid) * Hcload.Gw
+				Hcload.CGw = Spcheat(Eo3.Fluid, Ferr) * Hcload.Gw
 				rGa := Ro * Hcload.Ga
 
 				Eo3.Coeffo = Hcload.CGw
@@ -904,7 +904,7 @@ func hcldmonint(_Hcload []*HCLOAD) {
 	}
 }
 
-func hcldday(Mon, Day, ttmm, Nday, SimDayend int, _Hcload []*HCLOAD) {
+func hcldday(Mon, Day, ttmm, Nday, SimDayend int, _Hcload []*HCLOAD, Cff_kWh float64) {
 	Mo := Mon - 1
 	tt := ConvertHour(ttmm)
 
@@ -914,23 +914,23 @@ func hcldday(Mon, Day, ttmm, Nday, SimDayend int, _Hcload []*HCLOAD) {
 		svdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Tain, &Hcload.Taidy)
 		svdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Xain, &Hcload.xaidy)
 
-		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Qs, &Hcload.Qdys)
-		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Ql, &Hcload.Qdyl)
-		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Qt, &Hcload.Qdyt)
+		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Qs, &Hcload.Qdys, Cff_kWh)
+		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Ql, &Hcload.Qdyl, Cff_kWh)
+		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Qt, &Hcload.Qdyt, Cff_kWh)
 
-		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Qfusoku, &Hcload.Qdyfusoku)
-		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Ele, &Hcload.Edy)
+		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Qfusoku, &Hcload.Qdyfusoku, Cff_kWh)
+		qdaysum(int64(ttmm), Hcload.Cmp.Control, Hcload.Ele, &Hcload.Edy, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Tain, &Hcload.mTaidy, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Xain, &Hcload.mxaidy, Nday, SimDayend)
 
-		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Qs, &Hcload.mQdys, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Ql, &Hcload.mQdyl, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Qt, &Hcload.mQdyt, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Qs, &Hcload.mQdys, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Ql, &Hcload.mQdyl, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Qt, &Hcload.mQdyt, Nday, SimDayend, Cff_kWh)
 
-		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Qfusoku, &Hcload.mQdyfusoku, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Ele, &Hcload.mEdy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Qfusoku, &Hcload.mQdyfusoku, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Ele, &Hcload.mEdy, Nday, SimDayend, Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, Hcload.Cmp.Control, Hcload.Ele, &Hcload.mtEdy[Mo][tt])
@@ -1057,7 +1057,7 @@ func hcldmonprt(fo io.Writer, id int, _Hcload []*HCLOAD) {
 	}
 }
 
-func hcldmtprt(fo io.Writer, id, Mo, tt int, _Hcload []*HCLOAD) {
+func hcldmtprt(fo io.Writer, id, Mo, tt int, _Hcload []*HCLOAD, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(_Hcload) > 0 {
@@ -80,7 +80,7 @@ func TestHcldcfv(t *testing.T) {
 			}
 		}()
 
-		Hcldcfv(hcloads)
+		Hcldcfv(hcloads, nil)
 
 		// Verify coefficient calculations
 		if hcload.CGa <= 0 {
@@ -104,7 +104,7 @@ func TestHcldcfv(t *testing.T) {
 			}
 		}()
 
-		Hcldcfv(hcloads)
+		Hcldcfv(hcloads, nil)
 		t.Log("Wet mode coefficient calculation completed successfully")
 	})
 
@@ -119,7 +119,7 @@ func TestHcldcfv(t *testing.T) {
 			}
 		}()
 
-		Hcldcfv(hcloads)
+		Hcldcfv(hcloads, nil)
 
 		// Verify water coil specific coefficients
 		if hcload.Type == HCLoadType_W && hcload.CGw <= 0 {
@@ -225,7 +225,7 @@ func TestRmacdat(t *testing.T) {
 			}
 		}()
 
-		rmacdat(hcload)
+		rmacdat(hcload, nil)
 
 		// Verify RMAC data processing
 		if hcload.Qc >= 0 {
@@ -251,7 +251,7 @@ func TestRmacdat(t *testing.T) {
 			}
 		}()
 
-		rmacdat(hcload)
+		rmacdat(hcload, nil)
 
 		// Verify heating RMAC data
 		if hcload.Qh <= 0 {
@@ -277,7 +277,7 @@ func TestRmacddat(t *testing.T) {
 			}
 		}()
 
-		rmacddat(hcload)
+		rmacddat(hcload, nil)
 
 		// Verify detailed RMAC calculations
 		if hcload.Qc < 0 && hcload.COPc > 0 {
@@ -300,7 +300,7 @@ func TestRmacddat(t *testing.T) {
 			}
 		}()
 
-		rmacddat(hcload)
+		rmacddat(hcload, nil)
 
 		// Verify regression coefficients are calculated
 		if hcload.Qc < 0 {
@@ -340,7 +340,7 @@ func TestHCLOAD_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Hcldcfv(hcloads)
+		Hcldcfv(hcloads, nil)
 		Hcldene(hcloads, &LDrest, wd)
 
 		// Verify physical relationships
@@ -366,7 +366,7 @@ func TestHCLOAD_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		rmacdat(hcload)
+		rmacdat(hcload, nil)
 
 		// Verify COP values are within reasonable ranges
 		if hcload.COPc > 0 {
@@ -122,7 +122,7 @@ Hexcfv (Heat Exchanger Characteristic Function Value Calculation)
 熱回収システムや熱源システムの設計、熱負荷計算、
 およびエネルギー消費量予測を行うための重要な役割を果たします。
 */
-func Hexcfv(Hex []*HEX) {
+func Hexcfv(Hex []*HEX, Ferr io.Writer) {
 	for _, hex := range Hex {
 
 		// 計算準備
@@ -145,13 +145,13 @@ func Hexcfv(Hex []*HEX) {
 
 			if hex.Eff < 0.0 {
 				errMsg := fmt.Sprintf("Name=%s  eff=%.4g", hex.Cmp.Name, hex.Eff)
-				Eprint("Hexcfv", errMsg)
+				Eprint("Hexcfv", errMsg, Ferr)
 			}
 
 			eoh := hex.Cmp.Elouts[1]
 			eoc := hex.Cmp.Elouts[0]
-			hex.CGc = Spcheat(eoc.Fluid) * eoc.G
-			hex.CGh = Spcheat(eoh.Fluid) * eoh.G
+			hex.CGc = Spcheat(eoc.Fluid, Ferr) * eoc.G
+			hex.CGh = Spcheat(eoh.Fluid, Ferr) * eoh.G
 
 			if hex.Etype == 'k' {
 				hex.Eff = FNhccet(hex.CGc, hex.CGh, hex.Cat.KA)
@@ -345,19 +345,19 @@ hexday (Heat Exchanger Daily and Monthly Data Aggregation)
 この関数は、熱交換器の運転状況と熱交換量を多角的に分析し、
 熱回収システムの運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func hexday(Mon, Day, ttmm int, Hex []*HEX, Nday, SimDayend int) {
+func hexday(Mon, Day, ttmm int, Hex []*HEX, Nday, SimDayend int, Cff_kWh float64) {
 	for _, hex := range Hex {
 		// 日集計
 		svdaysum(int64(ttmm), hex.Cmp.Control, hex.Tcin, &hex.Tcidy)
 		svdaysum(int64(ttmm), hex.Cmp.Control, hex.Thin, &hex.Thidy)
-		qdaysum(int64(ttmm), hex.Cmp.Control, hex.Qci, &hex.Qcidy)
-		qdaysum(int64(ttmm), hex.Cmp.Control, hex.Qhi, &hex.Qhidy)
+		qdaysum(int64(ttmm), hex.Cmp.Control, hex.Qci, &hex.Qcidy, Cff_kWh)
+		qdaysum(int64(ttmm), hex.Cmp.Control, hex.Qhi, &hex.Qhidy, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, hex.Cmp.Control, hex.Tcin, &hex.MTcidy, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, hex.Cmp.Control, hex.Thin, &hex.MThidy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, hex.Cmp.Control, hex.Qci, &hex.MQcidy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, hex.Cmp.Control, hex.Qhi, &hex.MQhidy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, hex.Cmp.Control, hex.Qci, &hex.MQcidy, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, hex.Cmp.Control, hex.Qhi, &hex.MQhidy, Nday, SimDayend, Cff_kWh)
 	}
 }
 
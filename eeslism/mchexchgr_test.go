@@ -162,7 +162,7 @@ func TestHexcfv(t *testing.T) {
 		hex.Id = 0 // First run, needs initialization
 
 		hexs := []*HEX{hex}
-		Hexcfv(hexs)
+		Hexcfv(hexs, nil)
 
 		// Check Etype is set to 'e' for fixed efficiency
 		if hex.Etype != 'e' {
@@ -180,8 +180,8 @@ func TestHexcfv(t *testing.T) {
 		}
 
 		// Check CGc and CGh are calculated
-		expectedCGc := Spcheat(WATER_FLD) * 0.5
-		expectedCGh := Spcheat(WATER_FLD) * 0.8
+		expectedCGc := Spcheat(WATER_FLD, nil) * 0.5
+		expectedCGh := Spcheat(WATER_FLD, nil) * 0.8
 		if math.Abs(hex.CGc-expectedCGc) > 1e-6 {
 			t.Errorf("CGc = %f, want %f", hex.CGc, expectedCGc)
 		}
@@ -211,7 +211,7 @@ func TestHexcfv(t *testing.T) {
 		hex.Id = 0
 
 		hexs := []*HEX{hex}
-		Hexcfv(hexs)
+		Hexcfv(hexs, nil)
 
 		// Check Etype is set to 'k' for KA-based efficiency
 		if hex.Etype != 'k' {
@@ -231,7 +231,7 @@ func TestHexcfv(t *testing.T) {
 		hex.Etype = 'e'
 
 		hexs := []*HEX{hex}
-		Hexcfv(hexs)
+		Hexcfv(hexs, nil)
 
 		// Id should remain 1
 		if hex.Id != 1 {
@@ -250,7 +250,7 @@ func TestHexcfv(t *testing.T) {
 		initialCGh := hex.CGh
 
 		hexs := []*HEX{hex}
-		Hexcfv(hexs)
+		Hexcfv(hexs, nil)
 
 		// When Control is OFF_SW, coefficients should not be calculated
 		if hex.CGc != initialCGc || hex.CGh != initialCGh {
@@ -261,7 +261,7 @@ func TestHexcfv(t *testing.T) {
 	t.Run("EmptyList", func(t *testing.T) {
 		var hexs []*HEX
 		// Should not panic with empty list
-		Hexcfv(hexs)
+		Hexcfv(hexs, nil)
 	})
 
 	t.Run("MultipleHEX", func(t *testing.T) {
@@ -274,7 +274,7 @@ func TestHexcfv(t *testing.T) {
 		hex2.Id = 0
 
 		hexs := []*HEX{hex1, hex2}
-		Hexcfv(hexs)
+		Hexcfv(hexs, nil)
 
 		// Both should be initialized
 		if hex1.Id != 1 || hex2.Id != 1 {
@@ -293,8 +293,8 @@ func TestHexcfv(t *testing.T) {
 func TestHexene(t *testing.T) {
 	t.Run("BasicEnergyCalculation", func(t *testing.T) {
 		hex := createBasicHEX()
-		hex.CGc = Spcheat(WATER_FLD) * 0.5
-		hex.CGh = Spcheat(WATER_FLD) * 0.8
+		hex.CGc = Spcheat(WATER_FLD, nil) * 0.5
+		hex.CGh = Spcheat(WATER_FLD, nil) * 0.8
 		hex.Cmp.Elins[0].Sysvin = 10.0  // Cold inlet
 		hex.Cmp.Elins[1].Sysvin = 50.0  // Hot inlet
 		hex.Cmp.Elouts[0].Sysv = 20.0   // Cold outlet
@@ -368,16 +368,16 @@ func TestHexene(t *testing.T) {
 
 	t.Run("MultipleHEX", func(t *testing.T) {
 		hex1 := createBasicHEX()
-		hex1.CGc = Spcheat(WATER_FLD) * 0.5
-		hex1.CGh = Spcheat(WATER_FLD) * 0.8
+		hex1.CGc = Spcheat(WATER_FLD, nil) * 0.5
+		hex1.CGh = Spcheat(WATER_FLD, nil) * 0.8
 		hex1.Cmp.Elins[0].Sysvin = 10.0
 		hex1.Cmp.Elins[1].Sysvin = 50.0
 		hex1.Cmp.Elouts[0].Sysv = 20.0
 		hex1.Cmp.Elouts[1].Sysv = 35.0
 
 		hex2 := createBasicHEX()
-		hex2.CGc = Spcheat(WATER_FLD) * 1.0
-		hex2.CGh = Spcheat(WATER_FLD) * 1.0
+		hex2.CGc = Spcheat(WATER_FLD, nil) * 1.0
+		hex2.CGh = Spcheat(WATER_FLD, nil) * 1.0
 		hex2.Cmp.Elins[0].Sysvin = 5.0
 		hex2.Cmp.Elins[1].Sysvin = 60.0
 		hex2.Cmp.Elouts[0].Sysv = 30.0
@@ -478,7 +478,7 @@ func TestHexday(t *testing.T) {
 			hex.Thin = 45.0 + float64(ttmm)/500.0
 			hex.Qci = 4000.0 + float64(ttmm)
 			hex.Qhi = -4000.0 - float64(ttmm)
-			hexday(1, 1, ttmm, hexs, 1, 365)
+			hexday(1, 1, ttmm, hexs, 1, 365, 0.0)
 		}
 
 		// Verify aggregation results
@@ -504,7 +504,7 @@ func TestHexday(t *testing.T) {
 		hexmonint(hexs)
 
 		// Simulate end of day
-		hexday(1, 1, 2400, hexs, 1, 365)
+		hexday(1, 1, 2400, hexs, 1, 365, 0.0)
 
 		t.Log("Monthly aggregation test completed")
 	})
@@ -513,7 +513,7 @@ func TestHexday(t *testing.T) {
 		var hexs []*HEX
 
 		// Should not panic with empty list
-		hexday(1, 1, 100, hexs, 1, 365)
+		hexday(1, 1, 100, hexs, 1, 365, 0.0)
 	})
 }
 
@@ -17,90 +17,92 @@
 
 package eeslism
 
+import "io"
+
 // システム使用機器の初期設定
-func (Eqsys *EQSYS) Mecsinit(Simc *SIMCONTL, Compnt []*COMPNT, Exsf []*EXSF, Wd *WDAT, Rmvls *RMVLS) {
+func (Eqsys *EQSYS) Mecsinit(Simc *SIMCONTL, Compnt []*COMPNT, Exsf []*EXSF, Wd *WDAT, Rmvls *RMVLS, DTM float64, Ferr io.Writer) {
 	// ヒートポンプ
 	Refaint(Eqsys.Refa, Wd, Compnt)
 
 	// 太陽熱集熱器
-	Collint(Eqsys.Coll, Exsf, Wd)
+	Collint(Eqsys.Coll, Exsf, Wd, Ferr)
 
 	// 配管・ダクト
-	Pipeint(Eqsys.Pipe, Simc, Compnt, Wd)
+	Pipeint(Eqsys.Pipe, Simc, Compnt, Wd, Ferr)
 
 	// 蓄熱槽
-	Stankint(Eqsys.Stank, Simc, Compnt, Wd)
+	Stankint(Eqsys.Stank, Simc, Compnt, Wd, DTM, Ferr)
 
 	// 定流量ポンプ、変流量ポンプおよび太陽電池駆動ポンプ
-	Pumpint(Eqsys.Pump, Exsf)
+	Pumpint(Eqsys.Pump, Exsf, Ferr)
 
 	// 電気蓄熱暖房器
-	Stheatint(Eqsys.Stheat, Simc, Compnt, Wd, Rmvls.PCM)
+	Stheatint(Eqsys.Stheat, Simc, Compnt, Wd, Rmvls.PCM, Ferr)
 
 	// 境界条件設定用仮想機器
-	Flinint(Eqsys.Flin, Simc, Compnt, Wd)
+	Flinint(Eqsys.Flin, Simc, Compnt, Wd, Ferr)
 
 	// VAVユニット
 	VWVint(Eqsys.Vav, Compnt)
 
 	// 全熱交換器
-	Thexint(Eqsys.Thex)
+	Thexint(Eqsys.Thex, Ferr)
 
 	// 太陽電池
-	PVint(Eqsys.PVcmp, Exsf, Wd)
+	PVint(Eqsys.PVcmp, Exsf, Wd, Ferr)
 
 	// デシカント槽
-	Desiint(Eqsys.Desi, Simc, Compnt, Wd)
+	Desiint(Eqsys.Desi, Simc, Compnt, Wd, Ferr)
 
 	// 気化冷却器
-	Evacint(Eqsys.Evac)
+	Evacint(Eqsys.Evac, Ferr)
 }
 
 // システム使用機器特性式係数の計算
-func (Eqsys *EQSYS) Mecscf() {
+func (Eqsys *EQSYS) Mecscf(DTM float64, debug bool, Ferr io.Writer) {
 	// 合流要素
 	Cnvrgcfv(Eqsys.Cnvrg)
 
 	// 冷温水コイル
 	Hccdwint(Eqsys.Hcc)
-	Hcccfv(Eqsys.Hcc)
+	Hcccfv(Eqsys.Hcc, Ferr)
 
 	// ボイラー
-	Boicfv(Eqsys.Boi)
+	Boicfv(Eqsys.Boi, Ferr)
 
 	// 太陽熱集熱器
-	Collcfv(Eqsys.Coll)
+	Collcfv(Eqsys.Coll, Ferr)
 
 	// ヒートポンプ
-	Refacfv(Eqsys.Refa)
+	Refacfv(Eqsys.Refa, Ferr)
 
 	// 配管
-	Pipecfv(Eqsys.Pipe)
+	Pipecfv(Eqsys.Pipe, Ferr)
 
 	// 熱交換器
-	Hexcfv(Eqsys.Hex)
+	Hexcfv(Eqsys.Hex, Ferr)
 
 	// 定流量ポンプ、変流量ポンプおよび太陽電池駆動ポンプ
-	Pumpcfv(Eqsys.Pump)
+	Pumpcfv(Eqsys.Pump, Ferr)
 
 	// VAVユニット
-	VAVcfv(Eqsys.Vav)
+	VAVcfv(Eqsys.Vav, Ferr)
 
 	// 蓄熱槽
-	Stheatcfv(Eqsys.Stheat)
+	Stheatcfv(Eqsys.Stheat, DTM, Ferr)
 
 	// 全熱交換器
-	Thexcfv(Eqsys.Thex)
+	Thexcfv(Eqsys.Thex, debug, Ferr)
 
 	// デシカント槽
-	Desicfv(Eqsys.Desi)
+	Desicfv(Eqsys.Desi, DTM, Ferr)
 
 	// 気化冷却器
-	Evaccfv(Eqsys.Evac)
+	Evaccfv(Eqsys.Evac, Ferr)
 }
 
 // システム使用機器の供給熱量、エネルギーの計算
-func (Eqsys *EQSYS) Mecsene() {
+func (Eqsys *EQSYS) Mecsene(DTM float64, Ferr io.Writer) {
 	// 冷温水コイル
 	Hccene(Eqsys.Hcc)
 
@@ -123,7 +125,7 @@ func (Eqsys *EQSYS) Mecsene() {
 	Pumpene(Eqsys.Pump)
 
 	// 電気蓄熱暖房器
-	Stheatene(Eqsys.Stheat)
+	Stheatene(Eqsys.Stheat, DTM)
 
 	// デシカント槽
 	Desiene(Eqsys.Desi)
@@ -132,7 +134,7 @@ func (Eqsys *EQSYS) Mecsene() {
 	Thexene(Eqsys.Thex)
 
 	// カロリーメータ
-	Qmeasene(Eqsys.Qmeas)
+	Qmeasene(Eqsys.Qmeas, Ferr)
 
 	// 太陽電池
 	PVene(Eqsys.PVcmp)
@@ -22,7 +22,7 @@ func TestMecsinit(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
 	})
 
 	t.Run("BasicSystem", func(t *testing.T) {
@@ -35,7 +35,7 @@ func TestMecsinit(t *testing.T) {
 		rmvls := createBasicRMVLS()
 
 		// Execute initialization
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
 
 		// Verify initialization was successful
 		// (Add specific verification based on expected behavior)
@@ -80,7 +80,7 @@ func TestMecsinit(t *testing.T) {
 			}
 		}()
 		
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
 		t.Log("Partial system initialization completed")
 	})
 }
@@ -97,14 +97,14 @@ func TestMecscf(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecscf()
+		eqsys.Mecscf(3600.0, false, nil)
 	})
 
 	t.Run("BasicSystem", func(t *testing.T) {
 		eqsys := createBasicEQSYS()
 
 		// Execute coefficient calculation
-		eqsys.Mecscf()
+		eqsys.Mecscf(3600.0, false, nil)
 
 		// Verify coefficients were calculated
 		// (Add specific verification based on expected behavior)
@@ -121,7 +121,7 @@ func TestMecscf(t *testing.T) {
 			}
 		}()
 		
-		eqsys.Mecscf()
+		eqsys.Mecscf(3600.0, false, nil)
 
 		// Verify all component coefficients were calculated
 		t.Log("System coefficient calculation completed")
@@ -140,14 +140,14 @@ func TestMecsene(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecsene()
+		eqsys.Mecsene(3600.0, nil)
 	})
 
 	t.Run("BasicSystem", func(t *testing.T) {
 		eqsys := createBasicEQSYS()
 
 		// Execute energy calculation
-		eqsys.Mecsene()
+		eqsys.Mecsene(3600.0, nil)
 
 		// Verify energy calculations were performed
 		// (Add specific verification based on expected behavior)
@@ -164,7 +164,7 @@ func TestMecsene(t *testing.T) {
 			}
 		}()
 		
-		eqsys.Mecsene()
+		eqsys.Mecsene(3600.0, nil)
 
 		// Verify energy balance
 		// (Add energy balance verification)
@@ -190,9 +190,9 @@ func TestMecsys_EnergyBalance(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
-		eqsys.Mecscf()
-		eqsys.Mecsene()
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
+		eqsys.Mecscf(3600.0, false, nil)
+		eqsys.Mecsene(3600.0, nil)
 
 		// Basic energy conservation checks (conceptual verification)
 		// Since we're using empty systems, we verify the functions execute without error
@@ -231,9 +231,9 @@ func TestMecsys_EnergyBalance(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
-		eqsys.Mecscf()
-		eqsys.Mecsene()
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
+		eqsys.Mecscf(3600.0, false, nil)
+		eqsys.Mecsene(3600.0, nil)
 
 		// Test thermodynamic principles with mock data
 		testTemperatureRelations := func(tIn, tOut, heatLoad float64) bool {
@@ -276,17 +276,17 @@ func TestMecsys_Performance(t *testing.T) {
 
 		// Measure initialization time
 		startTime := getCurrentTime()
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
 		initTime := getCurrentTime() - startTime
 
 		// Measure coefficient calculation time
 		startTime = getCurrentTime()
-		eqsys.Mecscf()
+		eqsys.Mecscf(3600.0, false, nil)
 		cfvTime := getCurrentTime() - startTime
 
 		// Measure energy calculation time
 		startTime = getCurrentTime()
-		eqsys.Mecsene()
+		eqsys.Mecsene(3600.0, nil)
 		eneTime := getCurrentTime() - startTime
 
 		t.Logf("Performance benchmark - Init: %.3fms, Cfv: %.3fms, Ene: %.3fms", 
@@ -323,9 +323,9 @@ func TestMecsys_Performance(t *testing.T) {
 
 		// Initialize system multiple times to test memory patterns
 		for i := 0; i < 5; i++ {
-			eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
-			eqsys.Mecscf()
-			eqsys.Mecsene()
+			eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
+			eqsys.Mecscf(3600.0, false, nil)
+			eqsys.Mecsene(3600.0, nil)
 		}
 
 		// Verify no memory leaks (basic check)
@@ -352,9 +352,9 @@ func TestMecsys_ErrorHandling(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
-		eqsys.Mecscf()
-		eqsys.Mecsene()
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
+		eqsys.Mecscf(3600.0, false, nil)
+		eqsys.Mecsene(3600.0, nil)
 
 		t.Log("Invalid input handling test completed")
 	})
@@ -374,9 +374,9 @@ func TestMecsys_ErrorHandling(t *testing.T) {
 			}
 		}()
 
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
-		eqsys.Mecscf()
-		eqsys.Mecsene()
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
+		eqsys.Mecscf(3600.0, false, nil)
+		eqsys.Mecsene(3600.0, nil)
 
 		t.Log("Extreme boundary conditions test completed")
 	})
@@ -400,9 +400,9 @@ func TestEQSYS_FullCycle(t *testing.T) {
 			}
 		}()
 		
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
-		eqsys.Mecscf()
-		eqsys.Mecsene()
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
+		eqsys.Mecscf(3600.0, false, nil)
+		eqsys.Mecsene(3600.0, nil)
 
 		// Verify system state after complete cycle
 		t.Log("Complete system cycle executed successfully")
@@ -418,12 +418,12 @@ func TestEQSYS_FullCycle(t *testing.T) {
 		rmvls := createBasicRMVLS()
 
 		// Initialize once
-		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls)
+		eqsys.Mecsinit(simc, compnt, exsf, wd, rmvls, 3600.0, nil)
 
 		// Multiple coefficient and energy calculations
 		for i := 0; i < 5; i++ {
-			eqsys.Mecscf()
-			eqsys.Mecsene()
+			eqsys.Mecscf(3600.0, false, nil)
+			eqsys.Mecsene(3600.0, nil)
 		}
 
 		t.Log("Multiple iterations completed successfully")
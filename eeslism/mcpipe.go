@@ -70,7 +70,7 @@ func Pipedata(cattype EqpType, s string, Pipeca *PIPECA) int {
 
 /*  管長・ダクト長、周囲温度設定 */
 
-func Pipeint(Pipe []*PIPE, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
+func Pipeint(Pipe []*PIPE, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, Ferr io.Writer) {
 	for _, pipe := range Pipe {
 		if pipe.Cmp.Ivparm != nil {
 			pipe.L = *pipe.Cmp.Ivparm
@@ -79,19 +79,19 @@ func Pipeint(Pipe []*PIPE, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
 		}
 
 		if pipe.Cmp.Envname != "" {
-			pipe.Tenv = envptr(pipe.Cmp.Envname, Simc, Compnt, Wd, nil)
+			pipe.Tenv = envptr(pipe.Cmp.Envname, Simc, Compnt, Wd, nil, Ferr)
 		} else {
 			pipe.Room = roomptr(pipe.Cmp.Roomname, Compnt)
 		}
 
 		if pipe.Cat.Ko < 0.0 {
 			Err := fmt.Sprintf("Name=%s  Ko=%.4g", pipe.Cmp.Name, pipe.Cat.Ko)
-			Eprint("Pipeint", Err)
+			Eprint("Pipeint", Err, Ferr)
 		}
 
 		if pipe.L < 0.0 {
 			Err := fmt.Sprintf("Name=%s  L=%.4g", pipe.Cmp.Name, pipe.L)
-			Eprint("Pipeint", Err)
+			Eprint("Pipeint", Err, Ferr)
 		}
 	}
 }
@@ -105,7 +105,7 @@ func Pipeint(Pipe []*PIPE, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
 //	| PIPE |
 //
 // [IN 2] ---> +------+ ---> [OUT 2] 湿度 (DUCT_PDTのみ)
-func Pipecfv(Pipe []*PIPE) {
+func Pipecfv(Pipe []*PIPE, Ferr io.Writer) {
 	for _, pipe := range Pipe {
 		Te := 0.0
 		if pipe.Cmp.Control != OFF_SW {
@@ -115,12 +115,12 @@ func Pipecfv(Pipe []*PIPE) {
 				Te = pipe.Room.Tot
 			} else {
 				Err := fmt.Sprintf("Undefined Pipe Environment  name=%s", pipe.Name)
-				Eprint("<Pipecfv>", Err)
+				Eprint("<Pipecfv>", Err, Ferr)
 			}
 			pipe.Ko = pipe.Cat.Ko
 
 			Eo1 := pipe.Cmp.Elouts[0]
-			cG := Spcheat(Eo1.Fluid) * Eo1.G
+			cG := Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 			pipe.Ep = 1.0 - math.Exp(-(pipe.Ko*pipe.L)/cG)
 			pipe.D1 = cG * pipe.Ep
 			pipe.Do = pipe.D1 * Te
@@ -270,15 +270,15 @@ func pipemonint(Pipe []*PIPE) {
 	}
 }
 
-func pipeday(Mon int, Day int, ttmm int, Pipe []*PIPE, Nday int, SimDayend int) {
+func pipeday(Mon int, Day int, ttmm int, Pipe []*PIPE, Nday int, SimDayend int, Cff_kWh float64) {
 	for _, pipe := range Pipe {
 		// 日集計
 		svdaysum(int64(ttmm), pipe.Cmp.Elouts[0].Control, pipe.Tin, &pipe.Tidy)
-		qdaysum(int64(ttmm), pipe.Cmp.Elouts[0].Control, pipe.Q, &pipe.Qdy)
+		qdaysum(int64(ttmm), pipe.Cmp.Elouts[0].Control, pipe.Q, &pipe.Qdy, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, pipe.Cmp.Elouts[0].Control, pipe.Tin, &pipe.MTidy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, pipe.Cmp.Elouts[0].Control, pipe.Q, &pipe.MQdy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, pipe.Cmp.Elouts[0].Control, pipe.Q, &pipe.MQdy, Nday, SimDayend, Cff_kWh)
 	}
 }
 
@@ -195,7 +195,7 @@ func TestPipeint(t *testing.T) {
 		compnts := []*COMPNT{pipe.Cmp, roomCmp}
 		wd := createBasicWDAT()
 
-		Pipeint(pipes, simc, compnts, wd)
+		Pipeint(pipes, simc, compnts, wd, nil)
 
 		if pipe.L != 15.0 {
 			t.Errorf("L = %f, want 15.0", pipe.L)
@@ -213,7 +213,7 @@ func TestPipeint(t *testing.T) {
 		compnts := []*COMPNT{pipe.Cmp}
 		wd := createBasicWDAT()
 
-		Pipeint(pipes, simc, compnts, wd)
+		Pipeint(pipes, simc, compnts, wd, nil)
 
 		// L should be FNAN (-999) when Ivparm is nil
 		if pipe.L != FNAN {
@@ -228,7 +228,7 @@ func TestPipeint(t *testing.T) {
 		wd := createBasicWDAT()
 
 		// Should not panic with empty list
-		Pipeint(pipes, simc, compnts, wd)
+		Pipeint(pipes, simc, compnts, wd, nil)
 	})
 
 	t.Run("MultiplePipes", func(t *testing.T) {
@@ -247,7 +247,7 @@ func TestPipeint(t *testing.T) {
 		compnts := []*COMPNT{pipe1.Cmp, pipe2.Cmp}
 		wd := createBasicWDAT()
 
-		Pipeint(pipes, simc, compnts, wd)
+		Pipeint(pipes, simc, compnts, wd, nil)
 
 		if pipe1.L != 10.0 {
 			t.Errorf("pipe1.L = %f, want 10.0", pipe1.L)
@@ -270,7 +270,7 @@ func TestPipecfv(t *testing.T) {
 		pipe.Cmp.Elouts[0].G = 0.5 // 0.5 kg/s
 
 		pipes := []*PIPE{pipe}
-		Pipecfv(pipes)
+		Pipecfv(pipes, nil)
 
 		// Ko should be set from Cat
 		if pipe.Ko != 5.0 {
@@ -278,7 +278,7 @@ func TestPipecfv(t *testing.T) {
 		}
 
 		// cG = Spcheat(WATER_FLD) * G = 4186 * 0.5 = 2093
-		cG := Spcheat(WATER_FLD) * 0.5
+		cG := Spcheat(WATER_FLD, nil) * 0.5
 		expectedEp := 1.0 - math.Exp(-(pipe.Ko*pipe.L)/cG)
 		if math.Abs(pipe.Ep-expectedEp) > 1e-6 {
 			t.Errorf("Ep = %f, want %f", pipe.Ep, expectedEp)
@@ -322,10 +322,10 @@ func TestPipecfv(t *testing.T) {
 		pipe.Cmp.Elouts[0].G = 1.0
 
 		pipes := []*PIPE{pipe}
-		Pipecfv(pipes)
+		Pipecfv(pipes, nil)
 
 		// Environment temperature should come from Room.Tot
-		cG := Spcheat(WATER_FLD) * 1.0
+		cG := Spcheat(WATER_FLD, nil) * 1.0
 		expectedEp := 1.0 - math.Exp(-(pipe.Ko*pipe.L)/cG)
 		expectedD1 := cG * expectedEp
 		expectedDo := expectedD1 * 22.0 // Room temperature
@@ -344,7 +344,7 @@ func TestPipecfv(t *testing.T) {
 		pipe.Cat.Ko = 3.0
 
 		pipes := []*PIPE{pipe}
-		Pipecfv(pipes)
+		Pipecfv(pipes, nil)
 
 		// Check that second output (humidity) is set for DUCT_PDT
 		elout2 := pipe.Cmp.Elouts[1]
@@ -369,7 +369,7 @@ func TestPipecfv(t *testing.T) {
 		initialKo := pipe.Ko
 
 		pipes := []*PIPE{pipe}
-		Pipecfv(pipes)
+		Pipecfv(pipes, nil)
 
 		// When Control is OFF_SW, no calculations should be performed
 		// Ko should remain at initial value (not set from Cat)
@@ -381,7 +381,7 @@ func TestPipecfv(t *testing.T) {
 	t.Run("EmptyList", func(t *testing.T) {
 		var pipes []*PIPE
 		// Should not panic with empty list
-		Pipecfv(pipes)
+		Pipecfv(pipes, nil)
 	})
 }
 
@@ -687,7 +687,7 @@ func TestPipeday(t *testing.T) {
 		for ttmm := 100; ttmm <= 2300; ttmm += 100 {
 			pipe.Tin = 45.0 + float64(ttmm)/1000.0
 			pipe.Q = 400.0 + float64(ttmm)/10.0
-			pipeday(1, 1, ttmm, pipes, 1, 365)
+			pipeday(1, 1, ttmm, pipes, 1, 365, 0.0)
 		}
 
 		// Verify aggregation results
@@ -711,7 +711,7 @@ func TestPipeday(t *testing.T) {
 		pipemonint(pipes)
 
 		// Simulate end of day (Nday=1, SimDayend=365, day end ttmm=2400)
-		pipeday(1, 1, 2400, pipes, 1, 365)
+		pipeday(1, 1, 2400, pipes, 1, 365, 0.0)
 
 		// Monthly aggregation should have been triggered
 		t.Log("Monthly aggregation test completed")
@@ -721,6 +721,6 @@ func TestPipeday(t *testing.T) {
 		var pipes []*PIPE
 
 		// Should not panic with empty list
-		pipeday(1, 1, 100, pipes, 1, 365)
+		pipeday(1, 1, 100, pipes, 1, 365, 0.0)
 	})
 }
@@ -20,9 +20,8 @@ package eeslism
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -172,7 +171,7 @@ Pumpint (Pump Initialization)
 この関数は、太陽電池ポンプのような再生可能エネルギーを利用した熱搬送システムのモデル化において、
 日射量とポンプ運転の連動を正確に表現するための重要な役割を果たします。
 */
-func Pumpint(Pump []*PUMP, Exs []*EXSF) {
+func Pumpint(Pump []*PUMP, Exs []*EXSF, Ferr io.Writer) {
 	for _, p := range Pump {
 		if p.Cat.Type == "P" {
 			p.Sol = nil
@@ -183,7 +182,7 @@ func Pumpint(Pump []*PUMP, Exs []*EXSF) {
 				}
 			}
 			if p.Sol == nil {
-				Eprint("Pumpint", p.Cmp.Exsname)
+				Eprint("Pumpint", p.Cmp.Exsname, Ferr)
 			}
 		}
 	}
@@ -222,12 +221,12 @@ Pumpflow (Pump Flow Rate Calculation)
 熱搬送システム全体の熱供給能力、エネルギー消費量、
 および熱負荷への応答をシミュレーションするために不可欠な役割を果たします。
 */
-func (eqsys *EQSYS) Pumpflow() {
+func (eqsys *EQSYS) Pumpflow(debug bool) {
 	for i, p := range eqsys.Pump {
 		if p.Cat.Type == "P" {
 			S := p.Sol.Iw
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("<Pumpflow> i=%d S=%f Ic=%f a0=%f a1=%e a2=%e\n",
 					i, S, p.Cat.val[3], p.Cat.val[0],
 					p.Cat.val[1], p.Cat.val[2])
@@ -249,7 +248,7 @@ func (eqsys *EQSYS) Pumpflow() {
 				p.E = 0.0
 			}
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("<Pumpflow>  control=%c G=%f E=%f\n",
 					p.Cmp.Control, p.G, p.E)
 			}
@@ -290,14 +289,14 @@ Pumpcfv (Pump/Fan Characteristic Function Value Calculation)
 熱搬送システム全体の熱供給能力、エネルギー消費量、
 および熱負荷への応答をシミュレーションするために不可欠な役割を果たします。
 */
-func Pumpcfv(Pump []*PUMP) {
+func Pumpcfv(Pump []*PUMP, Ferr io.Writer) {
 	for _, p := range Pump {
 		if p.Cmp.Control != OFF_SW {
 			Eo1 := p.Cmp.Elouts[0]
-			cG := Spcheat(Eo1.Fluid) * Eo1.G
+			cG := Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 			p.CG = cG
 			Eo1.Coeffo = cG
-			p.PLC = PumpFanPLC(Eo1.G/p.G, p)
+			p.PLC = PumpFanPLC(Eo1.G/p.G, p, Ferr)
 			Eo1.Co = p.Cat.qef * p.E * p.PLC
 			Eo1.Coeffin[0] = -cG
 
@@ -345,7 +344,7 @@ PumpFanPLC (Pump/Fan Partial Load Characteristic Curve)
 熱搬送システムや空調システムの省エネルギー設計、
 および運用改善のための意思決定に不可欠な役割を果たします。
 */
-func PumpFanPLC(XQ float64, Pump *PUMP) float64 {
+func PumpFanPLC(XQ float64, Pump *PUMP, Ferr io.Writer) float64 {
 	var Buff, dQ float64
 	var i int
 	cat := Pump.Cat
@@ -354,7 +353,7 @@ func PumpFanPLC(XQ float64, Pump *PUMP) float64 {
 
 	if cat.pfcmp == nil {
 		Err := fmt.Sprintf("<PumpFanPLC>  PFtype=%c  type=%s", cat.pftype, cat.Type)
-		Eprint("PUMP oir FAN", string(Err[:]))
+		Eprint("PUMP oir FAN", string(Err[:]), Ferr)
 		Buff = 0.0
 	} else {
 		Buff = 0.0
@@ -538,20 +537,20 @@ pumpday (Pump/Fan Daily and Monthly Data Aggregation)
 この関数は、ポンプやファンの運転状況とエネルギー消費量を多角的に分析し、
 熱搬送システムの運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func pumpday(Mon, Day, ttmm int, Pump []*PUMP, Nday, SimDayend int) {
+func pumpday(Mon, Day, ttmm int, Pump []*PUMP, Nday, SimDayend int, Cff_kWh float64) {
 	Mo := Mon - 1
 	tt := ConvertHour(ttmm)
 
 	for _, p := range Pump {
 		// 日集計
-		edaysum(ttmm, p.Cmp.Elouts[0].Control, p.Q, &p.Qdy)
-		edaysum(ttmm, p.Cmp.Elouts[0].Control, p.E, &p.Edy)
-		edaysum(ttmm, p.Cmp.Elouts[0].Control, p.G, &p.Gdy)
+		edaysum(ttmm, p.Cmp.Elouts[0].Control, p.Q, &p.Qdy, Cff_kWh)
+		edaysum(ttmm, p.Cmp.Elouts[0].Control, p.E, &p.Edy, Cff_kWh)
+		edaysum(ttmm, p.Cmp.Elouts[0].Control, p.G, &p.Gdy, Cff_kWh)
 
 		// 月集計
-		emonsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.Q, &p.MQdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.E, &p.MEdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.G, &p.MGdy, Nday, SimDayend)
+		emonsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.Q, &p.MQdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.E, &p.MEdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.G, &p.MGdy, Nday, SimDayend, Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, p.Cmp.Elouts[0].Control, p.E, &p.MtEdy[Mo][tt])
@@ -617,7 +616,7 @@ func pumpmonprt(fo io.Writer, id int, Pump []*PUMP) {
 		}
 	}
 }
-func pumpmtprt(fo io.Writer, id int, Pump []*PUMP, Mo, tt int) {
+func pumpmtprt(fo io.Writer, id int, Pump []*PUMP, Mo, tt int, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(Pump) > 0 {
@@ -675,19 +674,18 @@ PFcmpdata (Pump/Fan Characteristic Data)
 熱搬送システムや空調システムの省エネルギー設計、
 および運用改善のための意思決定に不可欠な役割を果たします。
 */
-func PFcmpdata(efl_path string) []*PFCMP {
-	pumpfanlstPath := filepath.Join(efl_path, "pumpfanlst.efl")
-	fl, err := os.Open(pumpfanlstPath)
+func PFcmpdata(efl fs.FS, Ferr io.Writer) []*PFCMP {
+	fl, err := efl.Open("pumpfanlst.efl")
 	if err != nil {
 		// ファイルが見つからない場合は空のリストを返す
 		return make([]*PFCMP, 0)
 	}
 	defer fl.Close()
 
-	return _PFcmpdata(fl)
+	return _PFcmpdata(fl, Ferr)
 }
 
-func _PFcmpdata(fl *os.File) []*PFCMP {
+func _PFcmpdata(fl io.Reader, Ferr io.Writer) []*PFCMP {
 	var s string
 	var c rune
 	var i int
@@ -723,7 +721,7 @@ func _PFcmpdata(fl *os.File) []*PFCMP {
 			} else if s == string(FAN_TYPE) {
 				pfcmp.pftype = FAN_PF
 			} else {
-				Eprint("<pumpfanlst.efl>", s)
+				Eprint("<pumpfanlst.efl>", s, Ferr)
 			}
 
 			_, err = fmt.Fscanf(fl, "%s", &s)
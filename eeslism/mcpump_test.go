@@ -79,7 +79,7 @@ func TestPumpFanPLC(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := PumpFanPLC(tt.XQ, pump)
+			result := PumpFanPLC(tt.XQ, pump, nil)
 			if math.Abs(result-tt.expected) > tt.tolerance {
 				t.Errorf("PumpFanPLC(%v) = %v, want %v ± %v", tt.XQ, result, tt.expected, tt.tolerance)
 			}
@@ -105,7 +105,7 @@ func TestPumpFanPLC_NilPfcmp(t *testing.T) {
 		G:    0.1,
 	}
 
-	result := PumpFanPLC(0.5, pump)
+	result := PumpFanPLC(0.5, pump, nil)
 
 	// Should return 0.0 when pfcmp is nil
 	if result != 0.0 {
@@ -294,7 +294,7 @@ func TestPumpFanPLC_PolynomialCalculation(t *testing.T) {
 		Cat: pumpca,
 	}
 
-	result := PumpFanPLC(0.5, pump)
+	result := PumpFanPLC(0.5, pump, nil)
 	expected := 1.0 // Should be 1.0 regardless of input
 
 	if math.Abs(result-expected) > 1e-10 {
@@ -304,7 +304,7 @@ func TestPumpFanPLC_PolynomialCalculation(t *testing.T) {
 	// Test linear function: f(x) = x
 	pfcmp.dblcoeff = [5]float64{0.0, 1.0, 0.0, 0.0, 0.0}
 
-	result = PumpFanPLC(0.8, pump)
+	result = PumpFanPLC(0.8, pump, nil)
 	expected = 0.8
 
 	if math.Abs(result-expected) > 1e-10 {
@@ -314,7 +314,7 @@ func TestPumpFanPLC_PolynomialCalculation(t *testing.T) {
 	// Test quadratic function: f(x) = x^2
 	pfcmp.dblcoeff = [5]float64{0.0, 0.0, 1.0, 0.0, 0.0}
 
-	result = PumpFanPLC(0.6, pump)
+	result = PumpFanPLC(0.6, pump, nil)
 	expected = 0.36 // 0.6^2 = 0.36
 
 	if math.Abs(result-expected) > 1e-10 {
@@ -340,7 +340,7 @@ func TestPumpFanPLC_BoundaryConditions(t *testing.T) {
 	}
 
 	// Test minimum boundary (should clamp to 0.25)
-	result := PumpFanPLC(0.1, pump)
+	result := PumpFanPLC(0.1, pump, nil)
 	expected := 0.25
 
 	if math.Abs(result-expected) > 1e-10 {
@@ -348,7 +348,7 @@ func TestPumpFanPLC_BoundaryConditions(t *testing.T) {
 	}
 
 	// Test maximum boundary (should clamp to 1.0)
-	result = PumpFanPLC(1.5, pump)
+	result = PumpFanPLC(1.5, pump, nil)
 	expected = 1.0
 
 	if math.Abs(result-expected) > 1e-10 {
@@ -356,14 +356,14 @@ func TestPumpFanPLC_BoundaryConditions(t *testing.T) {
 	}
 
 	// Test exact boundaries
-	result = PumpFanPLC(0.25, pump)
+	result = PumpFanPLC(0.25, pump, nil)
 	expected = 0.25
 
 	if math.Abs(result-expected) > 1e-10 {
 		t.Errorf("PumpFanPLC(0.25) = %v, want %v", result, expected)
 	}
 
-	result = PumpFanPLC(1.0, pump)
+	result = PumpFanPLC(1.0, pump, nil)
 	expected = 1.0
 
 	if math.Abs(result-expected) > 1e-10 {
@@ -385,7 +385,7 @@ func TestPumpAdvancedFeatures(t *testing.T) {
 
 		// Simulate pump operation (using existing test patterns)
 		if pump.Cat != nil && pump.Cat.pfcmp != nil {
-			result := PumpFanPLC(0.8, pump) // 80% speed
+			result := PumpFanPLC(0.8, pump, nil) // 80% speed
 			t.Logf("Variable speed pump at 80%% speed - PLC result: %.3f", result)
 		}
 
@@ -406,7 +406,7 @@ func TestPumpAdvancedFeatures(t *testing.T) {
 		loadPoints := []float64{0.25, 0.5, 0.75, 1.0}
 		for _, load := range loadPoints {
 			if pump.Cat != nil && pump.Cat.pfcmp != nil {
-				plc := PumpFanPLC(load, pump)
+				plc := PumpFanPLC(load, pump, nil)
 				t.Logf("Load: %.2f, PLC: %.3f", load, plc)
 			}
 		}
@@ -428,7 +428,7 @@ func TestPumpAdvancedFeatures(t *testing.T) {
 		operatingPoints := []float64{0.2, 0.4, 0.6, 0.8, 1.0}
 		for _, point := range operatingPoints {
 			if pump.Cat != nil && pump.Cat.pfcmp != nil {
-				plc := PumpFanPLC(point, pump)
+				plc := PumpFanPLC(point, pump, nil)
 				t.Logf("Operating point: %.1f, PLC: %.3f", point, plc)
 			}
 		}
@@ -452,12 +452,12 @@ func TestPumpSystemIntegration(t *testing.T) {
 
 		// Test individual pump performance
 		if pump1.Cat != nil && pump1.Cat.pfcmp != nil {
-			plc1 := PumpFanPLC(0.8, pump1)
+			plc1 := PumpFanPLC(0.8, pump1, nil)
 			t.Logf("Pump1 at 80%% load - PLC: %.3f", plc1)
 		}
 		
 		if pump2.Cat != nil && pump2.Cat.pfcmp != nil {
-			plc2 := PumpFanPLC(0.6, pump2)
+			plc2 := PumpFanPLC(0.6, pump2, nil)
 			t.Logf("Pump2 at 60%% load - PLC: %.3f", plc2)
 		}
 
@@ -477,7 +477,7 @@ func TestPumpSystemIntegration(t *testing.T) {
 		// Test constant speed control
 		if pump.Cat != nil && pump.Cat.Type == "C" {
 			t.Log("Testing constant speed control")
-			plc := PumpFanPLC(1.0, pump)
+			plc := PumpFanPLC(1.0, pump, nil)
 			t.Logf("Constant speed PLC: %.3f", plc)
 		}
 
@@ -527,11 +527,11 @@ func TestPumpcfv(t *testing.T) {
 		}
 
 		// Run Pumpcfv
-		Pumpcfv([]*PUMP{pump})
+		Pumpcfv([]*PUMP{pump}, nil)
 
 		// Check results
 		// cG = Spcheat(WATER_FLD) * G = 4186 * 1.0 = 4186
-		expectedCG := Spcheat(WATER_FLD) * 1.0
+		expectedCG := Spcheat(WATER_FLD, nil) * 1.0
 		if pump.CG != expectedCG {
 			t.Errorf("CG = %v, want %v", pump.CG, expectedCG)
 		}
@@ -582,7 +582,7 @@ func TestPumpcfv(t *testing.T) {
 		}
 
 		// Run Pumpcfv
-		Pumpcfv([]*PUMP{pump})
+		Pumpcfv([]*PUMP{pump}, nil)
 
 		// When OFF, G and E should be set to 0
 		if pump.G != 0.0 {
@@ -636,10 +636,10 @@ func TestPumpcfv(t *testing.T) {
 		}
 
 		// Run Pumpcfv
-		Pumpcfv([]*PUMP{fan})
+		Pumpcfv([]*PUMP{fan}, nil)
 
 		// Check air-specific calculations
-		expectedCG := Spcheat(AIR_FLD) * 0.5
+		expectedCG := Spcheat(AIR_FLD, nil) * 0.5
 		if fan.CG != expectedCG {
 			t.Errorf("Fan CG = %v, want %v", fan.CG, expectedCG)
 		}
@@ -691,7 +691,7 @@ func TestPumpcfv(t *testing.T) {
 			}
 		}
 
-		Pumpcfv(pumps)
+		Pumpcfv(pumps, nil)
 
 		for i, p := range pumps {
 			if p.CG <= 0 {
@@ -1056,7 +1056,7 @@ func TestPumpday(t *testing.T) {
 		// Simulate multiple time steps
 		times := []int{900, 1000, 1100, 1200}
 		for _, ttmm := range times {
-			pumpday(7, 15, ttmm, pumps, 31, 365)
+			pumpday(7, 15, ttmm, pumps, 31, 365, 0.0)
 		}
 
 		// After 4 time steps, verify aggregation
@@ -1107,7 +1107,7 @@ func TestPumpday(t *testing.T) {
 		pumps := []*PUMP{pump}
 
 		pumpdyint(pumps)
-		pumpday(7, 15, 1200, pumps, 31, 365)
+		pumpday(7, 15, 1200, pumps, 31, 365, 0.0)
 
 		// OFF control should not aggregate
 		if pump.Qdy.Hrs != 0 {
@@ -1154,7 +1154,7 @@ func TestPumpday(t *testing.T) {
 		pumpmonint(pumps)
 
 		// Simulate calls at end of day to trigger monthly aggregation
-		pumpday(7, 31, 2400, pumps, 31, 365)
+		pumpday(7, 31, 2400, pumps, 31, 365, 0.0)
 
 		// Monthly aggregation should happen at end of day
 		// After single call, Hrs should be 1 for daily
@@ -1165,7 +1165,7 @@ func TestPumpday(t *testing.T) {
 
 	t.Run("EmptyList", func(t *testing.T) {
 		// Should not panic with empty list
-		pumpday(7, 15, 1200, []*PUMP{}, 31, 365)
+		pumpday(7, 15, 1200, []*PUMP{}, 31, 365, 0.0)
 	})
 
 	t.Run("MultiplePumps", func(t *testing.T) {
@@ -1205,7 +1205,7 @@ func TestPumpday(t *testing.T) {
 		pumpdyint(pumps)
 
 		// Call pumpday
-		pumpday(7, 15, 1200, pumps, 31, 365)
+		pumpday(7, 15, 1200, pumps, 31, 365, 0.0)
 
 		// Verify each pump aggregates independently
 		for i, pump := range pumps {
@@ -1257,7 +1257,7 @@ func TestPumpday(t *testing.T) {
 		// Test cross-tabulation: MtEdy[Mo][tt]
 		// Mo = Month - 1, tt = ConvertHour(ttmm)
 		// For July (Mon=7), Mo=6, For 12:00, tt depends on ConvertHour
-		pumpday(7, 15, 1200, pumps, 31, 365)
+		pumpday(7, 15, 1200, pumps, 31, 365, 0.0)
 
 		// Cross-tabulation array should have values
 		// MtEdy[6][xx] where xx = ConvertHour(1200)
@@ -108,7 +108,7 @@ func PVcadata(s string, PVca *PVCA) int {
 
 /*  初期設定 */
 
-func PVint(PV []*PV, Exs []*EXSF, Wd *WDAT) {
+func PVint(PV []*PV, Exs []*EXSF, Wd *WDAT, Ferr io.Writer) {
 	Err := ""
 
 	for i := range PV {
@@ -124,47 +124,47 @@ func PVint(PV []*PV, Exs []*EXSF, Wd *WDAT) {
 		}
 
 		if PV[i].Sol == nil {
-			Eprint("PVint", PV[i].Cmp.Exsname)
+			Eprint("PVint", PV[i].Cmp.Exsname, Ferr)
 		}
 
 		if PV[i].Cat.KHD < 0.0 {
 			Err = fmt.Sprintf("Name=%s KHD=%.4g", PV[i].Cmp.Name, PV[i].Cat.KHD)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].Cat.KPD < 0.0 {
 			Err = fmt.Sprintf("Name=%s KHD=%.4g", PV[i].Cmp.Name, PV[i].Cat.KPD)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].Cat.KPM < 0.0 {
 			Err = fmt.Sprintf("Name=%s KPM=%.4g", PV[i].Cmp.Name, PV[i].Cat.KPM)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].Cat.KPA < 0.0 {
 			Err = fmt.Sprintf("Name=%s KPA=%.4g", PV[i].Cmp.Name, PV[i].Cat.KPA)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].Cat.effINO < 0.0 {
 			Err = fmt.Sprintf("Name=%s EffInv=%.4g", PV[i].Cmp.Name, PV[i].Cat.effINO)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].Cat.apmax > 0.0 {
 			Err = fmt.Sprintf("Name=%s apmax=%.4g", PV[i].Cmp.Name, PV[i].Cat.apmax)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].PVcap < 0.0 {
 			Err = fmt.Sprintf("Name=%s PVcap=%.4g", PV[i].Cmp.Name, PV[i].PVcap)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		if PV[i].Area < 0.0 {
 			Err = fmt.Sprintf("Name=%s Area=%.4g", PV[i].Cmp.Name, PV[i].Area)
-			Eprint("PVint", Err)
+			Eprint("PVint", Err, Ferr)
 		}
 
 		// 計算途中で変化しない各種補正係数の積
@@ -243,7 +243,7 @@ func PVmonint(PV []*PV) {
 	}
 }
 
-func PVday(Mon int, Day int, ttmm int, PV []*PV, Nday int, SimDayend int) {
+func PVday(Mon int, Day int, ttmm int, PV []*PV, Nday int, SimDayend int, Cff_kWh float64) {
 	Mo := Mon - 1
 	tt := ConvertHour(ttmm)
 
@@ -256,7 +256,7 @@ func PVday(Mon int, Day int, ttmm int, PV []*PV, Nday int, SimDayend int) {
 		}
 
 		// 日間集計
-		qdaysum(int64(ttmm), sw, PV[i].Power, &PV[i].Edy)
+		qdaysum(int64(ttmm), sw, PV[i].Power, &PV[i].Edy, Cff_kWh)
 
 		if *PV[i].I > 0.0 {
 			sw = ON_SW
@@ -264,14 +264,14 @@ func PVday(Mon int, Day int, ttmm int, PV []*PV, Nday int, SimDayend int) {
 			sw = OFF_SW
 		}
 		// 時間集計
-		edaysum(ttmm, sw, *PV[i].I, &PV[i].Soldy)
+		edaysum(ttmm, sw, *PV[i].I, &PV[i].Soldy, Cff_kWh)
 
 		// 月間集計
 		sw = OFF_SW
 		if PV[i].Power > 0.0 {
 			sw = ON_SW
 		}
-		qmonsum(Mon, Day, ttmm, sw, PV[i].Power, &PV[i].mEdy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, sw, PV[i].Power, &PV[i].mEdy, Nday, SimDayend, Cff_kWh)
 
 		if *PV[i].I > 0.0 {
 			sw = ON_SW
@@ -279,7 +279,7 @@ func PVday(Mon int, Day int, ttmm int, PV []*PV, Nday int, SimDayend int) {
 			sw = OFF_SW
 		}
 		// 時間集計
-		emonsum(Mon, Day, ttmm, sw, *PV[i].I, &PV[i].mSoldy, Nday, SimDayend)
+		emonsum(Mon, Day, ttmm, sw, *PV[i].I, &PV[i].mSoldy, Nday, SimDayend, Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, sw, PV[i].Power, &PV[i].mtEdy[Mo][tt])
@@ -338,7 +338,7 @@ func PVmonprt(fo io.Writer, id int, PV []*PV) {
 	}
 }
 
-func PVmtprt(fo io.Writer, id int, PV []*PV, Mo int, tt int) {
+func PVmtprt(fo io.Writer, id int, PV []*PV, Mo int, tt int, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(PV) > 0 {
@@ -198,7 +198,7 @@ func TestPVint(t *testing.T) {
 		// Store original values
 		origKConst := pv.KConst
 
-		PVint(pvs, exss, wd)
+		PVint(pvs, exss, wd, nil)
 
 		// Check that weather data pointers were set
 		if pv.Ta != &wd.T {
@@ -791,7 +791,7 @@ func TestPVday(t *testing.T) {
 		// Simulate multiple time steps
 		times := []int{900, 1000, 1100, 1200}
 		for _, ttmm := range times {
-			PVday(7, 15, ttmm, pvs, 31, 365)
+			PVday(7, 15, ttmm, pvs, 31, 365, 0.0)
 		}
 
 		// After 4 time steps with positive power, Edy.Hhr should be 4
@@ -835,7 +835,7 @@ func TestPVday(t *testing.T) {
 		pvs := []*PV{pv}
 
 		PVdyint(pvs)
-		PVday(7, 15, 1200, pvs, 31, 365)
+		PVday(7, 15, 1200, pvs, 31, 365, 0.0)
 
 		// No power = no aggregation
 		if pv.Edy.Hhr != 0 {
@@ -877,7 +877,7 @@ func TestPVday(t *testing.T) {
 		pvs := []*PV{pv}
 
 		PVdyint(pvs)
-		PVday(7, 15, 1200, pvs, 31, 365)
+		PVday(7, 15, 1200, pvs, 31, 365, 0.0)
 
 		// No power = no power aggregation
 		if pv.Edy.Hhr != 0 {
@@ -891,7 +891,7 @@ func TestPVday(t *testing.T) {
 
 	t.Run("EmptyList", func(t *testing.T) {
 		// Should not panic with empty list
-		PVday(7, 15, 1200, []*PV{}, 31, 365)
+		PVday(7, 15, 1200, []*PV{}, 31, 365, 0.0)
 	})
 
 	t.Run("MultiplePV", func(t *testing.T) {
@@ -926,7 +926,7 @@ func TestPVday(t *testing.T) {
 		}
 
 		PVdyint(pvs)
-		PVday(7, 15, 1200, pvs, 31, 365)
+		PVday(7, 15, 1200, pvs, 31, 365, 0.0)
 
 		// Verify each PV has independent aggregation
 		for i, pv := range pvs {
@@ -969,7 +969,7 @@ func TestPVday(t *testing.T) {
 		PVmonint(pvs)
 
 		// Call at end of month
-		PVday(7, 31, 2400, pvs, 31, 365)
+		PVday(7, 31, 2400, pvs, 31, 365, 0.0)
 
 		// Daily values should be aggregated
 		if pv.Edy.Hhr != 1 {
@@ -1010,7 +1010,7 @@ func TestPVday(t *testing.T) {
 		PVmonint(pvs)
 
 		// Test cross-tabulation: mtEdy[Mo][tt]
-		PVday(7, 15, 1200, pvs, 31, 365)
+		PVday(7, 15, 1200, pvs, 31, 365, 0.0)
 
 		// Cross-tabulation should have values at Mo=6, tt=ConvertHour(1200)
 		tt := ConvertHour(1200)
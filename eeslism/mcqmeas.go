@@ -104,14 +104,14 @@ func Qmeaselm(Qmeas []*QMEAS) {
 	}
 }
 
-func Qmeasene(Qmeas []*QMEAS) {
+func Qmeasene(Qmeas []*QMEAS, Ferr io.Writer) {
 	for _, qmeas := range Qmeas {
 		PG := qmeas.PlistG
 		Ph := qmeas.PlistTh
 		Pc := qmeas.PlistTc
 
 		if PG.Control != OFF_SW && Ph.Control != OFF_SW && Pc.Control != OFF_SW {
-			qmeas.Qs = Spcheat(PG.Mpath.Fluid) * *qmeas.G * (*qmeas.Th - *qmeas.Tc)
+			qmeas.Qs = Spcheat(PG.Mpath.Fluid, Ferr) * *qmeas.G * (*qmeas.Th - *qmeas.Tc)
 
 			if qmeas.Plistxc != nil {
 				qmeas.Ql = Ro * *qmeas.G * (*qmeas.Xh - *qmeas.Xc)
@@ -198,7 +198,7 @@ func Qmeasmonint(Qmeas []*QMEAS) {
 	}
 }
 
-func Qmeasday(Mon, Day, ttmm int, Qmeas []*QMEAS, Nday, SimDayend int) {
+func Qmeasday(Mon, Day, ttmm int, Qmeas []*QMEAS, Nday, SimDayend int, Cff_kWh float64) {
 	for _, qmeas := range Qmeas {
 		// 日次集計
 		svdaysum(int64(ttmm), qmeas.PlistG.Control, *qmeas.Th, &qmeas.Thdy) // 温度
@@ -212,9 +212,9 @@ func Qmeasday(Mon, Day, ttmm int, Qmeas []*QMEAS, Nday, SimDayend int) {
 			svdaysum(int64(ttmm), qmeas.PlistG.Control, *qmeas.Xc, &qmeas.xcdy) // 入口湿度
 		}
 
-		qdaysum(int64(ttmm), qmeas.PlistG.Control, qmeas.Qs, &qmeas.Qdys) // 流量
-		qdaysum(int64(ttmm), qmeas.PlistG.Control, qmeas.Ql, &qmeas.Qdyl) // 湿度流量
-		qdaysum(int64(ttmm), qmeas.PlistG.Control, qmeas.Qt, &qmeas.Qdyt) // 総熱量
+		qdaysum(int64(ttmm), qmeas.PlistG.Control, qmeas.Qs, &qmeas.Qdys, Cff_kWh) // 流量
+		qdaysum(int64(ttmm), qmeas.PlistG.Control, qmeas.Ql, &qmeas.Qdyl, Cff_kWh) // 湿度流量
+		qdaysum(int64(ttmm), qmeas.PlistG.Control, qmeas.Qt, &qmeas.Qdyt, Cff_kWh) // 総熱量
 
 		// 月次集計
 		svmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, *qmeas.Th, &qmeas.mThdy, Nday, SimDayend) // 温度
@@ -228,9 +228,9 @@ func Qmeasday(Mon, Day, ttmm int, Qmeas []*QMEAS, Nday, SimDayend int) {
 			svmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, *qmeas.Xc, &qmeas.mxcdy, Nday, SimDayend) // 入口湿度
 		}
 
-		qmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, qmeas.Qs, &qmeas.mQdys, Nday, SimDayend) // 流量
-		qmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, qmeas.Ql, &qmeas.mQdyl, Nday, SimDayend) // 湿度流量
-		qmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, qmeas.Qt, &qmeas.mQdyt, Nday, SimDayend) // 総熱量
+		qmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, qmeas.Qs, &qmeas.mQdys, Nday, SimDayend, Cff_kWh) // 流量
+		qmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, qmeas.Ql, &qmeas.mQdyl, Nday, SimDayend, Cff_kWh) // 湿度流量
+		qmonsum(Mon, Day, ttmm, qmeas.PlistG.Control, qmeas.Qt, &qmeas.mQdyt, Nday, SimDayend, Cff_kWh) // 総熱量
 	}
 }
 
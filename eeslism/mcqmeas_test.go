@@ -109,7 +109,7 @@ func TestQmeasene(t *testing.T) {
 			}
 		}()
 
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify energy calculations
 		t.Logf("Energy calculation results - Qs: %.1f W, Ql: %.1f W, Qt: %.1f W", 
@@ -129,7 +129,7 @@ func TestQmeasene(t *testing.T) {
 			}
 		}()
 
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify flow measurement calculations
 		if qmeas.G != nil && *qmeas.G > 0 {
@@ -150,7 +150,7 @@ func TestQmeasene(t *testing.T) {
 			}
 		}()
 
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify temperature measurement calculations
 		if qmeas.Th != nil && *qmeas.Th > 0 {
@@ -171,7 +171,7 @@ func TestQmeasene(t *testing.T) {
 			}
 		}()
 
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify heat transfer calculations
 		if qmeas.Qt != 0 {
@@ -192,7 +192,7 @@ func TestQmeasene(t *testing.T) {
 			}
 		}()
 
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify energy balance (Qs + Ql = Qt)
 		totalEnergy := qmeas.Qs + qmeas.Ql
@@ -221,7 +221,7 @@ func TestQmeasene(t *testing.T) {
 			}
 		}()
 
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify energy values when OFF
 		if qmeas.Qs == 0.0 && qmeas.Ql == 0.0 && qmeas.Qt == 0.0 {
@@ -246,7 +246,7 @@ func TestQMEAS_PhysicalValidation(t *testing.T) {
 		}()
 
 		Qmeaselm(qmeass)
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify flow rate ranges are physically reasonable
 		if qmeas.G != nil && *qmeas.G >= 0 {
@@ -270,7 +270,7 @@ func TestQMEAS_PhysicalValidation(t *testing.T) {
 		}()
 
 		Qmeaselm(qmeass)
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify temperature ranges are physically reasonable
 		if qmeas.Th != nil && *qmeas.Th > -50.0 && *qmeas.Th < 150.0 {
@@ -294,7 +294,7 @@ func TestQMEAS_PhysicalValidation(t *testing.T) {
 		}()
 
 		Qmeaselm(qmeass)
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify measurement consistency
 		t.Log("Measurement consistency verified")
@@ -317,7 +317,7 @@ func TestQMEAS_PerformanceCharacteristics(t *testing.T) {
 		}()
 
 		Qmeaselm(qmeass)
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify measurement accuracy
 		t.Log("Measurement accuracy test completed")
@@ -337,7 +337,7 @@ func TestQMEAS_PerformanceCharacteristics(t *testing.T) {
 		}()
 
 		Qmeaselm(qmeass)
-		Qmeasene(qmeass)
+		Qmeasene(qmeass, nil)
 
 		// Verify response characteristics
 		t.Log("Response characteristics test completed")
@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -30,10 +29,11 @@ import (
 	圧縮式冷凍機
 
 機器仕様入力
-*/
-var __Refadata_hpch *HPCH
 
-func Refadata(s string, Refaca *REFACA, Rfcmp []*RFCMP) int {
+hpch は直前の m=C/m=H で指定された運転モードの特性で、
+同じ機器仕様の以降の項目の設定先となります。
+*/
+func Refadata(s string, Refaca *REFACA, Rfcmp []*RFCMP, hpch **HPCH) int {
 	var c ControlSWType
 	var dt float64
 	var id int
@@ -92,10 +92,10 @@ func Refadata(s string, Refaca *REFACA, Rfcmp []*RFCMP) int {
 				Refaca.mode[Refaca.Nmode] = c
 				if c == COOLING_SW {
 					Refaca.cool = new(HPCH)
-					__Refadata_hpch = Refaca.cool
+					*hpch = Refaca.cool
 				} else if c == HEATING_SW {
 					Refaca.heat = new(HPCH)
-					__Refadata_hpch = Refaca.heat
+					*hpch = Refaca.heat
 				}
 				Refaca.Nmode++
 			}
@@ -104,40 +104,40 @@ func Refadata(s string, Refaca *REFACA, Rfcmp []*RFCMP) int {
 			dt, _ = strconv.ParseFloat(valstr, 64)
 			switch {
 			case strings.HasPrefix(s, "Qo"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Qo = dt
+				if *hpch != nil {
+					(*hpch).Qo = dt
 				}
 			case strings.HasPrefix(s, "Go"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Go = dt
+				if *hpch != nil {
+					(*hpch).Go = dt
 				}
 			case strings.HasPrefix(s, "Two"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Two = dt
+				if *hpch != nil {
+					(*hpch).Two = dt
 				}
 			case strings.HasPrefix(s, "eo"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.eo = dt
+				if *hpch != nil {
+					(*hpch).eo = dt
 				}
 			case strings.HasPrefix(s, "Qex"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Qex = dt
+				if *hpch != nil {
+					(*hpch).Qex = dt
 				}
 			case strings.HasPrefix(s, "Gex"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Gex = dt
+				if *hpch != nil {
+					(*hpch).Gex = dt
 				}
 			case strings.HasPrefix(s, "Tex"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Tex = dt
+				if *hpch != nil {
+					(*hpch).Tex = dt
 				}
 			case strings.HasPrefix(s, "eex"):
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.eex = dt
+				if *hpch != nil {
+					(*hpch).eex = dt
 				}
 			case s[0] == 'W':
-				if __Refadata_hpch != nil {
-					__Refadata_hpch.Wo = dt
+				if *hpch != nil {
+					(*hpch).Wo = dt
 				}
 			case strings.HasPrefix(s, "Ph"):
 				Refaca.Ph = dt
@@ -230,12 +230,12 @@ func Refaint(Refa []*REFA, Wd *WDAT, Compnt []*COMPNT) {
 // [IN 1] ---> | REFA | --> [OUT 1]
 //
 //	+------+
-func Refacfv(Refa []*REFA) {
+func Refacfv(Refa []*REFA, Ferr io.Writer) {
 	for _, refa := range Refa {
 		if refa.Cmp.Control != OFF_SW {
 			Eo1 := refa.Cmp.Elouts[0]
 
-			cG := Spcheat(Eo1.Fluid) * Eo1.G
+			cG := Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 			refa.cG = cG
 			Eo1.Coeffo = cG
 
@@ -252,8 +252,8 @@ func Refacfv(Refa []*REFA) {
 					} else {
 						s := fmt.Sprintf("xxxxx refacoeff xxx stop xx  %s chmode=%c  monitor=%s",
 							refa.Name, refa.Chmode, refa.Cmp.Elouts[0].Emonitr.Cmp.Name)
-						Eprint("<Refacfv>", s)
-						os.Exit(EXIT_REFA)
+						Eprint("<Refacfv>", s, Ferr)
+						panic(&ConvergenceError{Section: "SYSCMP", Keyword: "Refacfv", Component: refa.Name, Msg: s, Code: EXIT_REFA})
 					}
 				}
 			}
@@ -300,7 +300,7 @@ func refacoeff(Refa *REFA, err *int) {
 /* ------------------------------------------------------------- */
 
 // 冷却熱量/加熱量、エネルギーの計算
-func Refaene(Refa []*REFA, LDreset *int) {
+func Refaene(Refa []*REFA, LDreset *int, Ferr io.Writer) {
 	var err, reset int
 	var Emax float64
 	var Eo *ELOUT
@@ -340,7 +340,7 @@ func Refaene(Refa []*REFA, LDreset *int) {
 								reset = maxcapreset(refa.Q, refa.Qmax, refa.Chmode, Eo)
 							}
 							if reset != 0 {
-								Refacfv(Refa[i : i+1])
+								Refacfv(Refa[i:i+1], Ferr)
 								(*LDreset)++
 							}
 						}
@@ -466,22 +466,22 @@ func refamonint(Refa []*REFA) {
 	}
 }
 
-func refaday(Mon int, Day int, ttmm int, Refa []*REFA, Nday int, SimDayend int) {
+func refaday(Mon int, Day int, ttmm int, Refa []*REFA, Nday int, SimDayend int, Cff_kWh float64) {
 	Mo := Mon - 1
 	tt := ConvertHour(ttmm)
 	for _, refa := range Refa {
 
 		// 日集計
 		svdaysum(int64(ttmm), refa.Cmp.Control, refa.Tin, &refa.Tidy)
-		qdaysum(int64(ttmm), refa.Cmp.Control, refa.Q, &refa.Qdy)
-		edaysum(ttmm, refa.Cmp.Control, refa.E, &refa.Edy)
-		edaysum(ttmm, refa.Cmp.Control, refa.Ph, &refa.Phdy)
+		qdaysum(int64(ttmm), refa.Cmp.Control, refa.Q, &refa.Qdy, Cff_kWh)
+		edaysum(ttmm, refa.Cmp.Control, refa.E, &refa.Edy, Cff_kWh)
+		edaysum(ttmm, refa.Cmp.Control, refa.Ph, &refa.Phdy, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.Tin, &refa.mTidy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.Q, &refa.mQdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.E, &refa.mEdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.Ph, &refa.mPhdy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.Q, &refa.mQdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.E, &refa.mEdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, refa.Cmp.Control, refa.Ph, &refa.mPhdy, Nday, SimDayend, Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, refa.Cmp.Control, refa.E, &refa.mtEdy[Mo][tt])
@@ -577,7 +577,7 @@ func refamonprt(fo io.Writer, id int, Refa []*REFA) {
 	}
 }
 
-func refamtprt(fo io.Writer, id int, Refa []*REFA, Mo int, tt int) {
+func refamtprt(fo io.Writer, id int, Refa []*REFA, Mo int, tt int, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(Refa) > 0 {
@@ -138,7 +138,7 @@ func TestRefacfv(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 
 		// Verify coefficient calculations
 		if refa.Cmp != nil && len(refa.Cmp.Elouts) > 0 {
@@ -159,7 +159,7 @@ func TestRefacfv(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 
 		// Verify cooling mode coefficients
 		if refa.Cat != nil && len(refa.Cat.mode) > 0 {
@@ -182,7 +182,7 @@ func TestRefacfv(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 
 		// Verify heating mode coefficients
 		if refa.Cat != nil && len(refa.Cat.mode) > 0 {
@@ -205,7 +205,7 @@ func TestRefacfv(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 		t.Log("Off control coefficient calculation completed successfully")
 	})
 }
@@ -224,7 +224,7 @@ func TestRefaene(t *testing.T) {
 		}()
 
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify energy calculations
 		t.Logf("Energy calculation results - Q: %.1f W, E: %.1f W", refa.Q, refa.E)
@@ -244,7 +244,7 @@ func TestRefaene(t *testing.T) {
 		}()
 
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify cooling energy calculations
 		if refa.Q < 0 { // Cooling should be negative
@@ -266,7 +266,7 @@ func TestRefaene(t *testing.T) {
 		}()
 
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify heating energy calculations
 		if refa.Q > 0 { // Heating should be positive
@@ -288,7 +288,7 @@ func TestRefaene(t *testing.T) {
 		}()
 
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify COP calculations
 		if refa.Q != 0 && refa.E != 0 {
@@ -316,7 +316,7 @@ func TestRefaene(t *testing.T) {
 		}()
 
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify energy balance (Q = thermal output, E = electrical input)
 		if refa.Q != 0 && refa.E != 0 {
@@ -340,7 +340,7 @@ func TestRefaene(t *testing.T) {
 		}()
 
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify all energy values are zero when OFF
 		if refa.Q == 0.0 && refa.E == 0.0 {
@@ -364,9 +364,9 @@ func TestREFA_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify COP ranges for different modes
 		if refa.Q != 0 && refa.E != 0 {
@@ -404,9 +404,9 @@ func TestREFA_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify temperature ranges are physically reasonable
 		if refa.Cat != nil && refa.Cat.awtyp == 'a' {
@@ -431,9 +431,9 @@ func TestREFA_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Refacfv(refas)
+		Refacfv(refas, nil)
 		var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 
 		// Verify capacity is within design limits
 		if refa.Q != 0 {
@@ -471,9 +471,9 @@ func TestREFA_PerformanceCharacteristics(t *testing.T) {
 				refa.Cmp.Elouts[0].G = factor * 2.0 // Assume 2.0 kg/s full load
 			}
 			
-			Refacfv(refas)
+			Refacfv(refas, nil)
 			var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 			
 			if refa.Q != 0 && refa.E != 0 {
 				cop := absValue(refa.Q / refa.E)
@@ -508,9 +508,9 @@ func TestREFA_PerformanceCharacteristics(t *testing.T) {
 
 		for _, season := range seasons {
 			// Set seasonal conditions (simplified)
-			Refacfv(refas)
+			Refacfv(refas, nil)
 			var LDrest int
-		Refaene(refas, &LDrest)
+		Refaene(refas, &LDrest, nil)
 			
 			if refa.Q != 0 && refa.E != 0 {
 				cop := absValue(refa.Q / refa.E)
@@ -999,7 +999,7 @@ func TestRefaday(t *testing.T) {
 		// Simulate multiple time steps
 		times := []int{900, 1000, 1100, 1200}
 		for _, ttmm := range times {
-			refaday(7, 15, ttmm, refas, 31, 365)
+			refaday(7, 15, ttmm, refas, 31, 365, 0.0)
 		}
 
 		// After 4 time steps, verify aggregation
@@ -1028,7 +1028,7 @@ func TestRefaday(t *testing.T) {
 		refas := []*REFA{refa}
 
 		refadyint(refas)
-		refaday(7, 15, 1200, refas, 31, 365)
+		refaday(7, 15, 1200, refas, 31, 365, 0.0)
 
 		// Heating should aggregate to Hhr
 		if refa.Qdy.Hhr != 1 {
@@ -1047,7 +1047,7 @@ func TestRefaday(t *testing.T) {
 		refas := []*REFA{refa}
 
 		refadyint(refas)
-		refaday(7, 15, 1200, refas, 31, 365)
+		refaday(7, 15, 1200, refas, 31, 365, 0.0)
 
 		// OFF control should not aggregate
 		if refa.Tidy.Hrs != 0 {
@@ -1072,7 +1072,7 @@ func TestRefaday(t *testing.T) {
 		refamonint(refas)
 
 		// Call at end of month
-		refaday(7, 31, 2400, refas, 31, 365)
+		refaday(7, 31, 2400, refas, 31, 365, 0.0)
 
 		// Daily values should be aggregated
 		if refa.Tidy.Hrs != 1 {
@@ -1082,7 +1082,7 @@ func TestRefaday(t *testing.T) {
 
 	t.Run("EmptyList", func(t *testing.T) {
 		// Should not panic with empty list
-		refaday(7, 15, 1200, []*REFA{}, 31, 365)
+		refaday(7, 15, 1200, []*REFA{}, 31, 365, 0.0)
 	})
 
 	t.Run("MultipleRefa", func(t *testing.T) {
@@ -1098,7 +1098,7 @@ func TestRefaday(t *testing.T) {
 		}
 
 		refadyint(refas)
-		refaday(7, 15, 1200, refas, 31, 365)
+		refaday(7, 15, 1200, refas, 31, 365, 0.0)
 
 		// Verify each refa has independent aggregation
 		for i, refa := range refas {
@@ -1126,7 +1126,7 @@ func TestRefaday(t *testing.T) {
 
 		// Test cross-tabulation: mtEdy[Mo][tt] and mtPhdy[Mo][tt]
 		// Mo = Month - 1, tt = ConvertHour(ttmm)
-		refaday(7, 15, 1200, refas, 31, 365)
+		refaday(7, 15, 1200, refas, 31, 365, 0.0)
 
 		// Cross-tabulation should have values at Mo=6, tt=ConvertHour(1200)
 		tt := ConvertHour(1200)
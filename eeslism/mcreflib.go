@@ -19,18 +19,17 @@ package eeslism
 
 import (
 	"bufio"
-	"os"
-	"path/filepath"
+	"io"
+	"io/fs"
 	"strings"
 )
 
 // 圧縮式冷凍機定格特性入力
 // reflist.efl ファイルから読み取ります。
-func Refcmpdat(efl_path string) []*RFCMP {
-	reflistPath := filepath.Join(efl_path, "reflist.efl")
-	frf, err := os.Open(reflistPath)
+func Refcmpdat(efl fs.FS, Ferr io.Writer) []*RFCMP {
+	frf, err := efl.Open("reflist.efl")
 	if err != nil {
-		Eprint(" file ", "reflist.efl")
+		Eprint(" file ", "reflist.efl", Ferr)
 		// ファイルが見つからない場合は空のスライスを返す
 		return make([]*RFCMP, 0)
 	}
@@ -39,7 +38,7 @@ func Refcmpdat(efl_path string) []*RFCMP {
 	return _Refcmpdat(frf)
 }
 
-func _Refcmpdat(frf *os.File) []*RFCMP {
+func _Refcmpdat(frf io.Reader) []*RFCMP {
 	Rfcmp := make([]*RFCMP, 0)
 
 	// ファイル全体を読み込んでトークンに分割（C版のfscanfと同様の動作）
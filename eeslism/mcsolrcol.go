@@ -78,7 +78,7 @@ func Colldata(typeStr EqpType, s string, Collca *COLLCA) int {
 
 /*  初期設定 */
 
-func Collint(Coll []*COLL, Exs []*EXSF, Wd *WDAT) {
+func Collint(Coll []*COLL, Exs []*EXSF, Wd *WDAT, Ferr io.Writer) {
 	for _, coll := range Coll {
 		coll.Ta = &Wd.T
 		coll.sol = nil
@@ -88,24 +88,24 @@ func Collint(Coll []*COLL, Exs []*EXSF, Wd *WDAT) {
 			}
 		}
 		if coll.sol == nil {
-			Eprint("Collint", coll.Cmp.Exsname)
+			Eprint("Collint", coll.Cmp.Exsname, Ferr)
 		}
 
 		if coll.Cat.b0 < 0.0 {
 			Err := fmt.Sprintf("Name=%s b0=%.4g", coll.Cmp.Name, coll.Cat.b0)
-			Eprint("Collint", Err)
+			Eprint("Collint", Err, Ferr)
 		}
 		if coll.Cat.b1 < 0.0 {
 			Err := fmt.Sprintf("Name=%s b1=%.4g", coll.Cmp.Name, coll.Cat.b1)
-			Eprint("Collint", Err)
+			Eprint("Collint", Err, Ferr)
 		}
 		if coll.Cat.Ac < 0.0 {
 			Err := fmt.Sprintf("Name=%s Ac=%.4g", coll.Cmp.Name, coll.Cat.Ac)
-			Eprint("Collint", Err)
+			Eprint("Collint", Err, Ferr)
 		}
 		if coll.Cat.Ag < 0.0 {
 			Err := fmt.Sprintf("Name=%s Ag=%.4g", coll.Cmp.Name, coll.Cat.Ag)
-			Eprint("Collint", Err)
+			Eprint("Collint", Err, Ferr)
 		}
 
 		// 総合熱損失係数[W/(m2･K)]の計算
@@ -128,13 +128,13 @@ func CalcCollTe(Coll []*COLL) {
 // +------+ ---> [OUT 1]
 // | COLL |
 // +------+ ---> [OUT 2] ACOLLECTOR_PDTのみ
-func Collcfv(Coll []*COLL) {
+func Collcfv(Coll []*COLL, Ferr io.Writer) {
 	for _, coll := range Coll {
 		// 制御用の相当外気温度（現在時刻）は計算済みなのでここでは計算しない
 		if coll.Cmp.Control != OFF_SW {
 			Eo1 := coll.Cmp.Elouts[0]
 			Kcw := coll.Cat.b1
-			cG := Spcheat(Eo1.Fluid) * Eo1.G
+			cG := Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 			coll.ec = 1.0 - mathExp(-Kcw*coll.Cmp.Ac/cG)
 			coll.D1 = cG * coll.ec
 			coll.Do = coll.D1 * coll.Te
@@ -246,31 +246,31 @@ func collmonint(Coll []*COLL) {
 	}
 }
 
-func collday(Mon, Day, ttmm int, Coll []*COLL, Nday, SimDayend int) {
+func collday(Mon, Day, ttmm int, Coll []*COLL, Nday, SimDayend int, Cff_kWh float64) {
 	var sw ControlSWType
 
 	for _, coll := range Coll {
 		// 日次集計
-		svdaysum(int64(ttmm), coll.Cmp.Control, coll.Tin, &coll.Tidy) // 温度の日次集計
-		qdaysum(int64(ttmm), coll.Cmp.Control, coll.Q, &coll.Qdy)     // 熱量の日次集計
+		svdaysum(int64(ttmm), coll.Cmp.Control, coll.Tin, &coll.Tidy)      // 温度の日次集計
+		qdaysum(int64(ttmm), coll.Cmp.Control, coll.Q, &coll.Qdy, Cff_kWh) // 熱量の日次集計
 
 		if coll.Sol > 0.0 {
 			sw = ON_SW
 		} else {
 			sw = OFF_SW
 		}
-		edaysum(ttmm, sw, coll.Sol, &coll.Soldy)
+		edaysum(ttmm, sw, coll.Sol, &coll.Soldy, Cff_kWh)
 
 		// 月次集計
-		svmonsum(Mon, Day, ttmm, coll.Cmp.Control, coll.Tin, &coll.mTidy, Nday, SimDayend) // 温度の月次集計
-		qmonsum(Mon, Day, ttmm, coll.Cmp.Control, coll.Q, &coll.mQdy, Nday, SimDayend)     // 熱量の月次集計
+		svmonsum(Mon, Day, ttmm, coll.Cmp.Control, coll.Tin, &coll.mTidy, Nday, SimDayend)      // 温度の月次集計
+		qmonsum(Mon, Day, ttmm, coll.Cmp.Control, coll.Q, &coll.mQdy, Nday, SimDayend, Cff_kWh) // 熱量の月次集計
 
 		if coll.Sol > 0.0 {
 			sw = ON_SW
 		} else {
 			sw = OFF_SW
 		}
-		emonsum(Mon, Day, ttmm, sw, coll.Sol, &coll.mSoldy, Nday, SimDayend)
+		emonsum(Mon, Day, ttmm, sw, coll.Sol, &coll.mSoldy, Nday, SimDayend, Cff_kWh)
 	}
 }
 
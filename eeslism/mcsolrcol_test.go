@@ -206,7 +206,7 @@ func TestCollcfv(t *testing.T) {
 		origD1 := coll.D1
 		origEc := coll.ec
 
-		Collcfv(colls)
+		Collcfv(colls, nil)
 
 		// Check that coefficients were calculated
 		if coll.Do == origDo && coll.D1 == origD1 && coll.ec == origEc {
@@ -694,7 +694,7 @@ func TestCollday(t *testing.T) {
 		// Simulate aggregation at several time steps
 		times := []int{900, 1000, 1100, 1200}
 		for _, ttmm := range times {
-			collday(7, 15, ttmm, colls, 31, 365)
+			collday(7, 15, ttmm, colls, 31, 365, 0.0)
 		}
 
 		// Verify daily aggregation was performed
@@ -718,7 +718,7 @@ func TestCollday(t *testing.T) {
 		colls := []*COLL{coll}
 
 		colldyint(colls)
-		collday(7, 15, 1200, colls, 31, 365)
+		collday(7, 15, 1200, colls, 31, 365, 0.0)
 
 		// Solar should not be aggregated when Sol = 0
 		if coll.Soldy.Hrs != 0 {
@@ -735,7 +735,7 @@ func TestCollday(t *testing.T) {
 		colls := []*COLL{coll}
 
 		colldyint(colls)
-		collday(7, 15, 1200, colls, 31, 365)
+		collday(7, 15, 1200, colls, 31, 365, 0.0)
 
 		// Temperature and heat should not be aggregated when OFF
 		if coll.Tidy.Hrs != 0 {
@@ -748,6 +748,6 @@ func TestCollday(t *testing.T) {
 	})
 
 	t.Run("EmptyList", func(t *testing.T) {
-		collday(7, 15, 1200, []*COLL{}, 31, 365)
+		collday(7, 15, 1200, []*COLL{}, 31, 365, 0.0)
 	})
 }
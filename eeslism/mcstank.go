@@ -334,7 +334,7 @@ Stankint (Storage Tank Initialization)
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な初期設定機能を提供します。
 */
-func Stankint(Stank []*STANK, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
+func Stankint(Stank []*STANK, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, DTM float64, Ferr io.Writer) {
 	var s, ss, Err, E string
 	var mrk rune
 	var Tso float64
@@ -399,25 +399,25 @@ func Stankint(Stank []*STANK, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT) {
 			}
 		}
 
-		stank.Tenv = envptr(stank.Cmp.Envname, Simc, Compnt, Wd, nil)
+		stank.Tenv = envptr(stank.Cmp.Envname, Simc, Compnt, Wd, nil, Ferr)
 		stoint(stank.Ndiv, stank.Cat.Vol, stank.Cat.KAside, stank.Cat.KAtop, stank.Cat.KAbtm,
-			stank.Dvol, stank.Mdt, stank.KS, stank.Tss, stank.Tssold, &stank.Jva, &stank.Jvb)
+			stank.Dvol, stank.Mdt, stank.KS, stank.Tss, stank.Tssold, &stank.Jva, &stank.Jvb, DTM)
 
 		if stank.Cat.Vol < 0.0 {
 			Err = fmt.Sprintf("Name=%s  Vol=%.4g", stank.Cmp.Name, stank.Cat.Vol)
-			Eprint(E, Err)
+			Eprint(E, Err, Ferr)
 		}
 		if stank.Cat.KAside < 0.0 {
 			Err = fmt.Sprintf("Name=%s  KAside=%.4g", stank.Cmp.Name, stank.Cat.KAside)
-			Eprint(E, Err)
+			Eprint(E, Err, Ferr)
 		}
 		if stank.Cat.KAtop < 0.0 {
 			Err = fmt.Sprintf("Name=%s  KAtop=%.4g", stank.Cmp.Name, stank.Cat.KAtop)
-			Eprint(E, Err)
+			Eprint(E, Err, Ferr)
 		}
 		if stank.Cat.KAbtm < 0.0 {
 			Err = fmt.Sprintf("Name=%s  KAbtm=%.4g", stank.Cmp.Name, stank.Cat.KAbtm)
-			Eprint(E, Err)
+			Eprint(E, Err, Ferr)
 		}
 	}
 }
@@ -454,7 +454,7 @@ Stankcfv (Storage Tank Characteristic Function Value Calculation)
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func Stankcfv(Stank []*STANK) {
+func Stankcfv(Stank []*STANK, Ferr io.Writer) {
 	for _, stank := range Stank {
 		for j := 0; j < stank.Nin; j++ {
 			elin := stank.Cmp.Elins[j]
@@ -466,7 +466,7 @@ func Stankcfv(Stank []*STANK) {
 			if elin.Lpath.Batch {
 				*cGwin = 0.0
 			} else {
-				*cGwin = Spcheat('W') * elin.Lpath.G
+				*cGwin = Spcheat('W', Ferr) * elin.Lpath.G
 			}
 
 			// 内蔵熱交のKAが入力されている場合
@@ -492,7 +492,7 @@ func Stankcfv(Stank []*STANK) {
 		stofc(stank.Ndiv, stank.Nin, stank.Jin,
 			stank.Jout, stank.Ihex, stank.Ihxeff, stank.Jva, stank.Jvb,
 			stank.Mdt, stank.KS, stank.Cat.gxr, stank.Tenv,
-			stank.Tssold, stank.CGwin, stank.EGwin, stank.B, stank.R, stank.D, stank.Fg)
+			stank.Tssold, stank.CGwin, stank.EGwin, stank.B, stank.R, stank.D, stank.Fg, Ferr)
 
 		fgIdx := 0
 		for j := 0; j < stank.Nin; j++ {
@@ -832,7 +832,7 @@ func stankmonint(Stank []*STANK) {
 }
 
 // 日集計、月集計
-func stankday(Mon, Day, ttmm int, Stank []*STANK, Nday, SimDayend int) {
+func stankday(Mon, Day, ttmm int, Stank []*STANK, Nday, SimDayend int, Cff_kWh float64) {
 	for _, stank := range Stank {
 
 		// 日集計
@@ -851,7 +851,7 @@ func stankday(Mon, Day, ttmm int, Stank []*STANK, Nday, SimDayend int) {
 			Ei := stank.Cmp.Elins[j]
 			S := &stank.Stkdy[j]
 			svdaysum(int64(ttmm), Ei.Lpath.Control, stank.Twin[j], &S.Tidy)
-			qdaysum(int64(ttmm), Ei.Lpath.Control, stank.Q[j], &S.Qdy)
+			qdaysum(int64(ttmm), Ei.Lpath.Control, stank.Q[j], &S.Qdy, Cff_kWh)
 		}
 
 		// 月集計
@@ -865,13 +865,13 @@ func stankday(Mon, Day, ttmm int, Stank []*STANK, Nday, SimDayend int) {
 			Ei := stank.Cmp.Elins[j]
 			S := &stank.Mstkdy[j]
 			svmonsum(Mon, Day, ttmm, Ei.Lpath.Control, stank.Twin[j], &S.Tidy, Nday, SimDayend)
-			qmonsum(Mon, Day, ttmm, Ei.Lpath.Control, stank.Q[j], &S.Qdy, Nday, SimDayend)
+			qmonsum(Mon, Day, ttmm, Ei.Lpath.Control, stank.Q[j], &S.Qdy, Nday, SimDayend, Cff_kWh)
 		}
 	}
 }
 
 // 日集計の出力
-func stankdyprt(fo io.Writer, id int, Stank []*STANK) {
+func stankdyprt(fo io.Writer, id int, Stank []*STANK, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(Stank) > 0 {
@@ -930,7 +930,7 @@ func stankdyprt(fo io.Writer, id int, Stank []*STANK) {
 }
 
 // 月集計の出力
-func stankmonprt(fo io.Writer, id int, Stank []*STANK) {
+func stankmonprt(fo io.Writer, id int, Stank []*STANK, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(Stank) > 0 {
@@ -22,7 +22,7 @@ func TestStankint(t *testing.T) {
 			}
 		}()
 
-		Stankint(stanks, simc, compnt, wd)
+		Stankint(stanks, simc, compnt, wd, 3600.0, nil)
 
 		// Verify initialization
 		if stank.Cmp != nil {
@@ -49,7 +49,7 @@ func TestStankint(t *testing.T) {
 			}
 		}()
 
-		Stankint(stanks, simc, compnt, wd)
+		Stankint(stanks, simc, compnt, wd, 3600.0, nil)
 		t.Log("Multiple STANK initialization completed successfully")
 	})
 
@@ -66,7 +66,7 @@ func TestStankint(t *testing.T) {
 			}
 		}()
 
-		Stankint(stanks, simc, compnt, wd)
+		Stankint(stanks, simc, compnt, wd, 3600.0, nil)
 		t.Log("Empty STANK list handled successfully")
 	})
 
@@ -84,7 +84,7 @@ func TestStankint(t *testing.T) {
 			}
 		}()
 
-		Stankint(stanks, simc, compnt, wd)
+		Stankint(stanks, simc, compnt, wd, 3600.0, nil)
 
 		// Verify stratification setup
 		if stank.Ndiv > 1 {
@@ -108,7 +108,7 @@ func TestStankcfv(t *testing.T) {
 			}
 		}()
 
-		Stankcfv(stanks)
+		Stankcfv(stanks, nil)
 
 		// Verify coefficient calculations
 		if stank.Cmp != nil && len(stank.Cmp.Elouts) > 0 {
@@ -129,7 +129,7 @@ func TestStankcfv(t *testing.T) {
 			}
 		}()
 
-		Stankcfv(stanks)
+		Stankcfv(stanks, nil)
 		t.Log("Stratified coefficient calculation completed successfully")
 	})
 
@@ -144,7 +144,7 @@ func TestStankcfv(t *testing.T) {
 			}
 		}()
 
-		Stankcfv(stanks)
+		Stankcfv(stanks, nil)
 
 		// Verify heat loss coefficients
 		t.Log("Heat loss coefficient calculation verified")
@@ -360,7 +360,7 @@ func TestSTANK_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Stankcfv(stanks)
+		Stankcfv(stanks, nil)
 		Stankene(stanks)
 
 		// Verify heat loss is reasonable
@@ -423,7 +423,7 @@ func TestSTANK_NumericalStability(t *testing.T) {
 
 		// Run multiple iterations to test stability
 		for i := 0; i < 10; i++ {
-			Stankcfv(stanks)
+			Stankcfv(stanks, nil)
 			Stankene(stanks)
 		}
 
@@ -782,7 +782,7 @@ func TestStankdyprt(t *testing.T) {
 
 	t.Run("Header1_id0", func(t *testing.T) {
 		var buf bytes.Buffer
-		stankdyprt(&buf, 0, stanks)
+		stankdyprt(&buf, 0, stanks, 0.0)
 		output := buf.String()
 
 		if !strings.Contains(output, string(STANK_TYPE)) {
@@ -792,7 +792,7 @@ func TestStankdyprt(t *testing.T) {
 
 	t.Run("Header2_id1", func(t *testing.T) {
 		var buf bytes.Buffer
-		stankdyprt(&buf, 1, stanks)
+		stankdyprt(&buf, 1, stanks, 0.0)
 		output := buf.String()
 
 		// Check for daily aggregation item names
@@ -806,7 +806,7 @@ func TestStankdyprt(t *testing.T) {
 
 	t.Run("Data_default", func(t *testing.T) {
 		var buf bytes.Buffer
-		stankdyprt(&buf, 99, stanks)
+		stankdyprt(&buf, 99, stanks, 0.0)
 		output := buf.String()
 
 		if output == "" {
@@ -832,7 +832,7 @@ func TestStankmonprt(t *testing.T) {
 
 	t.Run("Header1_id0", func(t *testing.T) {
 		var buf bytes.Buffer
-		stankmonprt(&buf, 0, stanks)
+		stankmonprt(&buf, 0, stanks, 0.0)
 		output := buf.String()
 
 		if !strings.Contains(output, string(STANK_TYPE)) {
@@ -842,7 +842,7 @@ func TestStankmonprt(t *testing.T) {
 
 	t.Run("Data_default", func(t *testing.T) {
 		var buf bytes.Buffer
-		stankmonprt(&buf, 99, stanks)
+		stankmonprt(&buf, 99, stanks, 0.0)
 		output := buf.String()
 
 		if output == "" {
@@ -952,7 +952,7 @@ func TestStankday(t *testing.T) {
 		// Simulate multiple time steps
 		times := []int{900, 1000, 1100, 1200}
 		for _, ttmm := range times {
-			stankday(7, 15, ttmm, stanks, 31, 365)
+			stankday(7, 15, ttmm, stanks, 31, 365, 0.0)
 		}
 
 		// After 4 time steps, verify aggregation values
@@ -1019,7 +1019,7 @@ func TestStankday(t *testing.T) {
 
 		// Call stankday at end of day (ttmm=2400 equivalent, Day=Nday)
 		// Nday=31 means 31 days in the month
-		stankday(7, 31, 2400, stanks, 31, 365)
+		stankday(7, 31, 2400, stanks, 31, 365, 0.0)
 
 		// Monthly values should be copied at end of day
 		if stank.MQlossdy == 0.0 && stank.Qlossdy > 0.0 {
@@ -1061,7 +1061,7 @@ func TestStankday(t *testing.T) {
 		stankdyint(stanks)
 
 		// Call stankday
-		stankday(1, 15, 1200, stanks, 31, 365)
+		stankday(1, 15, 1200, stanks, 31, 365, 0.0)
 
 		// Tank temperature should still be aggregated (uses ON_SW always)
 		if stank.Stkdy[0].Tsdy.Hrs != 1 {
@@ -1076,7 +1076,7 @@ func TestStankday(t *testing.T) {
 
 	t.Run("EmptyList", func(t *testing.T) {
 		// Should not panic with empty list
-		stankday(1, 15, 1200, []*STANK{}, 31, 365)
+		stankday(1, 15, 1200, []*STANK{}, 31, 365, 0.0)
 	})
 
 	t.Run("MultipleStanks", func(t *testing.T) {
@@ -1111,7 +1111,7 @@ func TestStankday(t *testing.T) {
 		stankdyint(stanks)
 
 		// Call stankday for all
-		stankday(7, 15, 1200, stanks, 31, 365)
+		stankday(7, 15, 1200, stanks, 31, 365, 0.0)
 
 		// Verify each stank has independent aggregation
 		for i, stank := range stanks {
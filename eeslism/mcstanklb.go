@@ -17,6 +17,8 @@
 
 package eeslism
 
+import "io"
+
 const TSTOLE = 0.04
 
 /*
@@ -52,7 +54,7 @@ stoint (Storage Tank Initialization for Layers)
 および省エネルギー対策の検討を行うための重要な初期設定機能を提供します。
 */
 func stoint(N int, Vol float64, KAside float64, KAtop float64, KAbtm float64,
-	dvol, Mdt, KS, Tss, Tssold []float64, Jva, Jvb *int) {
+	dvol, Mdt, KS, Tss, Tssold []float64, Jva, Jvb *int, DTM float64) {
 
 	for i := 0; i < N; i++ {
 		dvol[i] = Vol / float64(N)
@@ -101,7 +103,7 @@ stofc (Storage Tank Coefficient Calculation)
 */
 func stofc(N, Nin int, Jcin, Jcout []int,
 	ihex []rune, ihxeff []float64, Jva, Jvb int, Mdt, KS []float64,
-	gxr float64, Tenv *float64, Tssold, cGwin, EGwin, B, R, d, fg []float64) {
+	gxr float64, Tenv *float64, Tssold, cGwin, EGwin, B, R, d, fg []float64, Ferr io.Writer) {
 	N2 := N * N
 	for j := 0; j < N2; j++ {
 		B[j] = 0.0
@@ -148,7 +150,7 @@ func stofc(N, Nin int, Jcin, Jcout []int,
 	B[0] += mathAbs(B[1])
 	B[N*N-1] += mathAbs(B[N*N-2])
 
-	Matinv(B, N, N, "<stofc>")
+	Matinv(B, N, N, "<stofc>", Ferr)
 	Matmalv(B, R, N, N, d)
 
 	fgIndex := 0
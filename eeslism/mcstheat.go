@@ -19,7 +19,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -131,11 +130,11 @@ Stheatint (Sensible Heat Storage Initialization)
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な初期設定機能を提供します。
 */
-func Stheatint(_stheat []*STHEAT, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, _PCM []*PCM) {
+func Stheatint(_stheat []*STHEAT, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, _PCM []*PCM, Ferr io.Writer) {
 	for i := range _stheat {
 		stheat := _stheat[i]
 		if stheat.Cmp.Envname != "" {
-			stheat.Tenv = envptr(stheat.Cmp.Envname, Simc, Compnt, Wd, nil)
+			stheat.Tenv = envptr(stheat.Cmp.Envname, Simc, Compnt, Wd, nil, Ferr)
 		} else {
 			stheat.Room = roomptr(stheat.Cmp.Roomname, Compnt)
 		}
@@ -148,8 +147,8 @@ func Stheatint(_stheat []*STHEAT, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, _P
 			}
 			if stheat.Pcm == nil {
 				Err := fmt.Sprintf("STHEAT %s のPCM=%sが見つかりません", stheat.Name, stheat.Cat.PCMName)
-				Eprint(Err, "<Stheatint>")
-				os.Exit(1)
+				Eprint(Err, "<Stheatint>", Ferr)
+				panic(&InputError{Section: "SYSCMP", Keyword: stheat.Cat.PCMName, Component: stheat.Name, Msg: "PCM undefined in PCM"})
 			}
 		}
 
@@ -157,19 +156,19 @@ func Stheatint(_stheat []*STHEAT, Simc *SIMCONTL, Compnt []*COMPNT, Wd *WDAT, _P
 
 		if st.Q < 0.0 {
 			Err := fmt.Sprintf("Name=%s  Q=%.4g", stheat.Name, st.Q)
-			Eprint("Stheatinit", Err)
+			Eprint("Stheatinit", Err, Ferr)
 		}
 		if stheat.Pcm == nil && st.Hcap < 0.0 {
 			Err := fmt.Sprintf("Name=%s  Hcap=%.4g", stheat.Name, st.Hcap)
-			Eprint("Stheatinit", Err)
+			Eprint("Stheatinit", Err, Ferr)
 		}
 		if st.KA < 0.0 {
 			Err := fmt.Sprintf("Name=%s  KA=%.4g", stheat.Name, st.KA)
-			Eprint("Stheatinit", Err)
+			Eprint("Stheatinit", Err, Ferr)
 		}
 		if st.Eff < 0.0 {
 			Err := fmt.Sprintf("Name=%s  eff=%.4g", stheat.Name, st.Eff)
-			Eprint("Stheatinit", Err)
+			Eprint("Stheatinit", Err, Ferr)
 		}
 
 		var err error
@@ -225,7 +224,7 @@ Stheatcfv (Sensible Heat Storage Characteristic Function Value Calculation)
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func Stheatcfv(_stheat []*STHEAT) {
+func Stheatcfv(_stheat []*STHEAT, DTM float64, Ferr io.Writer) {
 	for i := range _stheat {
 		stheat := _stheat[i]
 
@@ -239,7 +238,7 @@ func Stheatcfv(_stheat []*STHEAT) {
 
 		Eo1 := stheat.Cmp.Elouts[0]
 		eff := stheat.Cat.Eff
-		stheat.CG = Spcheat(Eo1.Fluid) * Eo1.G
+		stheat.CG = Spcheat(Eo1.Fluid, Ferr) * Eo1.G
 		KA := stheat.Cat.KA
 		Tsold := stheat.Tsold
 		pcm := stheat.Pcm
@@ -321,7 +320,7 @@ Stheatene (Sensible Heat Storage Energy Calculation)
 熱負荷平準化、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func Stheatene(_stheat []*STHEAT) {
+func Stheatene(_stheat []*STHEAT, DTM float64) {
 	var elo *ELOUT
 	var cat *STHEATCA
 	var Te float64
@@ -558,7 +557,7 @@ stheatday (Sensible Heat Storage Daily and Monthly Data Aggregation)
 この関数は、顕熱蓄熱式暖房器の運転状況と熱交換量を多角的に分析し、
 蓄熱システムの運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func stheatday(Mon, Day, ttmm int, stheat []*STHEAT, Nday, SimDayend int) {
+func stheatday(Mon, Day, ttmm int, stheat []*STHEAT, Nday, SimDayend int, Cff_kWh float64) {
 	Mo := Mon - 1
 	tt := ConvertHour(ttmm)
 
@@ -569,8 +568,8 @@ func stheatday(Mon, Day, ttmm int, stheat []*STHEAT, Nday, SimDayend int) {
 		svdaysum(int64(ttmm), stheat[i].Cmp.Control, stheat[i].Tin, &stheat[i].Tidy)
 		svdaysum(int64(ttmm), stheat[i].Cmp.Control, stheat[i].Tout, &stheat[i].Tody)
 		svdaysum(int64(ttmm), stheat[i].Cmp.Control, stheat[i].Ts, &stheat[i].Tsdy)
-		qdaysum(int64(ttmm), stheat[i].Cmp.Control, stheat[i].Q, &stheat[i].Qdy)
-		edaysum(ttmm, stheat[i].Cmp.Control, stheat[i].E, &stheat[i].Edy)
+		qdaysum(int64(ttmm), stheat[i].Cmp.Control, stheat[i].Q, &stheat[i].Qdy, Cff_kWh)
+		edaysum(ttmm, stheat[i].Cmp.Control, stheat[i].E, &stheat[i].Edy, Cff_kWh)
 
 		// 月集計
 		stheat[i].MQlossdy += stheat[i].Qls
@@ -578,15 +577,15 @@ func stheatday(Mon, Day, ttmm int, stheat []*STHEAT, Nday, SimDayend int) {
 		svmonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].Tin, &stheat[i].MTidy, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].Tout, &stheat[i].MTody, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].Ts, &stheat[i].MTsdy, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].Q, &stheat[i].MQdy, Nday, SimDayend)
-		emonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].E, &stheat[i].MEdy, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].Q, &stheat[i].MQdy, Nday, SimDayend, Cff_kWh)
+		emonsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].E, &stheat[i].MEdy, Nday, SimDayend, Cff_kWh)
 
 		// 月・時刻のクロス集計
 		emtsum(Mon, Day, ttmm, stheat[i].Cmp.Control, stheat[i].E, &stheat[i].MtEdy[Mo][tt])
 	}
 }
 
-func stheatdyprt(fo io.Writer, id int, stheat []*STHEAT) {
+func stheatdyprt(fo io.Writer, id int, stheat []*STHEAT, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(stheat) > 0 {
@@ -641,7 +640,7 @@ func stheatdyprt(fo io.Writer, id int, stheat []*STHEAT) {
 	}
 }
 
-func stheatmonprt(fo io.Writer, id int, stheat []*STHEAT) {
+func stheatmonprt(fo io.Writer, id int, stheat []*STHEAT, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(stheat) > 0 {
@@ -696,7 +695,7 @@ func stheatmonprt(fo io.Writer, id int, stheat []*STHEAT) {
 	}
 }
 
-func stheatmtprt(fo io.Writer, id int, stheat []*STHEAT, Mo, tt int) {
+func stheatmtprt(fo io.Writer, id int, stheat []*STHEAT, Mo, tt int, Cff_kWh float64) {
 	switch id {
 	case 0:
 		if len(stheat) > 0 {
@@ -150,7 +150,7 @@ func TestStheatint(t *testing.T) {
 		stheat := &STHEAT{Name: "TestSTHEAT", Cat: stheatca, Cmp: cmp}
 		stheats := []*STHEAT{stheat}
 
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 
 		if stheat.Tenv == nil {
 			t.Error("Tenv should be set when Envname is numeric")
@@ -184,7 +184,7 @@ func TestStheatint(t *testing.T) {
 		roomCmp := &COMPNT{Name: "TestRoom", Eqp: room}
 		compnts := []*COMPNT{roomCmp}
 
-		Stheatint(stheats, nil, compnts, nil, nil)
+		Stheatint(stheats, nil, compnts, nil, nil, nil)
 
 		if stheat.Room == nil {
 			t.Error("Room should be set when Roomname is provided")
@@ -219,7 +219,7 @@ func TestStheatint(t *testing.T) {
 		pcm := &PCM{Name: "TestPCM", Ql: 10000.0}
 		pcms := []*PCM{pcm}
 
-		Stheatint(stheats, nil, nil, nil, pcms)
+		Stheatint(stheats, nil, nil, nil, pcms, nil)
 
 		if stheat.Pcm == nil {
 			t.Error("Pcm should be set when PCMName is provided")
@@ -248,7 +248,7 @@ func TestStheatint(t *testing.T) {
 		stheat := &STHEAT{Name: "TestSTHEAT", Cat: stheatca, Cmp: cmp}
 		stheats := []*STHEAT{stheat}
 
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 
 		if stheat.MPCM != 100.0 {
 			t.Errorf("MPCM = %f, want 100.0", stheat.MPCM)
@@ -258,7 +258,7 @@ func TestStheatint(t *testing.T) {
 	t.Run("EmptyList", func(t *testing.T) {
 		// Empty list should not panic
 		stheats := []*STHEAT{}
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 	})
 
 	t.Run("NegativeQ_Warning", func(t *testing.T) {
@@ -281,7 +281,7 @@ func TestStheatint(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		// Should not panic, just print warning
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 	})
 
 	t.Run("NegativeHcap_Warning", func(t *testing.T) {
@@ -304,7 +304,7 @@ func TestStheatint(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		// Should not panic, just print warning
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 	})
 
 	t.Run("NegativeKA_Warning", func(t *testing.T) {
@@ -327,7 +327,7 @@ func TestStheatint(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		// Should not panic, just print warning
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 	})
 
 	t.Run("NegativeEff_Warning", func(t *testing.T) {
@@ -350,7 +350,7 @@ func TestStheatint(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		// Should not panic, just print warning
-		Stheatint(stheats, nil, nil, nil, nil)
+		Stheatint(stheats, nil, nil, nil, nil, nil)
 	})
 }
 
@@ -425,10 +425,10 @@ func TestStheatcfv(t *testing.T) {
 		stheat.Cmp.Envname = "" // No environment name
 
 		stheats := []*STHEAT{stheat}
-		Stheatcfv(stheats)
+		Stheatcfv(stheats, 3600.0, nil)
 
 		// Check CG is calculated
-		expectedCG := Spcheat(AIRa_FLD) * 0.1
+		expectedCG := Spcheat(AIRa_FLD, nil) * 0.1
 		if math.Abs(stheat.CG-expectedCG) > 1e-6 {
 			t.Errorf("CG = %f, want %f", stheat.CG, expectedCG)
 		}
@@ -458,7 +458,7 @@ func TestStheatcfv(t *testing.T) {
 		stheat.Room = nil
 
 		stheats := []*STHEAT{stheat}
-		Stheatcfv(stheats)
+		Stheatcfv(stheats, 3600.0, nil)
 
 		// Check Hcap is set from Cat (no PCM)
 		if stheat.Hcap != 50000.0 {
@@ -475,7 +475,7 @@ func TestStheatcfv(t *testing.T) {
 		}
 
 		stheats := []*STHEAT{stheat}
-		Stheatcfv(stheats)
+		Stheatcfv(stheats, 3600.0, nil)
 
 		// E should be 0 when Control is OFF_SW
 		if stheat.E != 0.0 {
@@ -492,7 +492,7 @@ func TestStheatcfv(t *testing.T) {
 		}
 
 		stheats := []*STHEAT{stheat}
-		Stheatcfv(stheats)
+		Stheatcfv(stheats, 3600.0, nil)
 
 		// When Elout Control is OFF_SW, coefficients should be set differently
 		elout := stheat.Cmp.Elouts[0]
@@ -510,7 +510,7 @@ func TestStheatcfv(t *testing.T) {
 	t.Run("EmptyList", func(t *testing.T) {
 		var stheats []*STHEAT
 		// Should not panic with empty list
-		Stheatcfv(stheats)
+		Stheatcfv(stheats, 3600.0, nil)
 	})
 }
 
@@ -524,13 +524,13 @@ func TestStheatene(t *testing.T) {
 			Qeqp: 0.0,
 		}
 		stheat.Cmp.Envname = ""
-		stheat.CG = Spcheat(AIRa_FLD) * 0.1 // Pre-calculated
+		stheat.CG = Spcheat(AIRa_FLD, nil) * 0.1 // Pre-calculated
 		stheat.Hcap = 50000.0
 		stheat.E = 3000.0
 		stheat.Tsold = 30.0
 
 		stheats := []*STHEAT{stheat}
-		Stheatene(stheats)
+		Stheatene(stheats, 3600.0)
 
 		// Check Tin is set from input
 		if stheat.Tin != 20.0 {
@@ -566,14 +566,14 @@ func TestStheatene(t *testing.T) {
 			Qeqp: 0.0,
 		}
 		stheat.Cmp.Envname = ""
-		stheat.CG = Spcheat(AIRa_FLD) * 0.1
+		stheat.CG = Spcheat(AIRa_FLD, nil) * 0.1
 		stheat.Hcap = 50000.0
 		stheat.E = 3000.0
 		stheat.Tsold = 30.0
 
 		initialQeqp := stheat.Room.Qeqp
 		stheats := []*STHEAT{stheat}
-		Stheatene(stheats)
+		Stheatene(stheats, 3600.0)
 
 		// Room.Qeqp should be updated with -Qls
 		if stheat.Room.Qeqp == initialQeqp && stheat.Qls != 0.0 {
@@ -584,7 +584,7 @@ func TestStheatene(t *testing.T) {
 	t.Run("EmptyList", func(t *testing.T) {
 		var stheats []*STHEAT
 		// Should not panic with empty list
-		Stheatene(stheats)
+		Stheatene(stheats, 3600.0)
 	})
 }
 
@@ -686,7 +686,7 @@ func TestStheatday(t *testing.T) {
 			stheat.E = 2500.0 + float64(ttmm)/5.0
 			stheat.Qls = 80.0 + float64(ttmm)/100.0
 			stheat.Qsto = 400.0 + float64(ttmm)/20.0
-			stheatday(1, 1, ttmm, stheats, 1, 365)
+			stheatday(1, 1, ttmm, stheats, 1, 365, 0.0)
 		}
 
 		// Verify aggregation results
@@ -718,7 +718,7 @@ func TestStheatday(t *testing.T) {
 		stheatmonint(stheats)
 
 		// Simulate end of day
-		stheatday(1, 1, 2400, stheats, 1, 365)
+		stheatday(1, 1, 2400, stheats, 1, 365, 0.0)
 
 		t.Log("Monthly aggregation test completed")
 	})
@@ -727,7 +727,7 @@ func TestStheatday(t *testing.T) {
 		var stheats []*STHEAT
 
 		// Should not panic with empty list
-		stheatday(1, 1, 100, stheats, 1, 365)
+		stheatday(1, 1, 100, stheats, 1, 365, 0.0)
 	})
 }
 
@@ -738,7 +738,7 @@ func TestStheatdyprt(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		var buf bytes.Buffer
-		stheatdyprt(&buf, 0, stheats)
+		stheatdyprt(&buf, 0, stheats, 0.0)
 		output := buf.String()
 
 		if !strings.Contains(output, string(STHEAT_TYPE)) {
@@ -758,7 +758,7 @@ func TestStheatdyprt(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		var buf bytes.Buffer
-		stheatdyprt(&buf, 99, stheats)
+		stheatdyprt(&buf, 99, stheats, 0.0)
 		output := buf.String()
 
 		if len(output) == 0 {
@@ -774,7 +774,7 @@ func TestStheatmonprt(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		var buf bytes.Buffer
-		stheatmonprt(&buf, 0, stheats)
+		stheatmonprt(&buf, 0, stheats, 0.0)
 		output := buf.String()
 
 		if !strings.Contains(output, string(STHEAT_TYPE)) {
@@ -794,7 +794,7 @@ func TestStheatmonprt(t *testing.T) {
 		stheats := []*STHEAT{stheat}
 
 		var buf bytes.Buffer
-		stheatmonprt(&buf, 99, stheats)
+		stheatmonprt(&buf, 99, stheats, 0.0)
 		output := buf.String()
 
 		if len(output) == 0 {
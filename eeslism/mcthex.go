@@ -111,7 +111,7 @@ Thexint (Total Heat Exchanger Initialization)
 換気システムにおける熱回収の設計、熱負荷計算、
 およびエネルギー消費量予測を行うための基礎的な役割を果たします。
 */
-func Thexint(Thex []*THEX) {
+func Thexint(Thex []*THEX, Ferr io.Writer) {
 	for _, thex := range Thex {
 		if thex.Cat.eh < 0.0 {
 			thex.Type = 't'
@@ -122,7 +122,7 @@ func Thexint(Thex []*THEX) {
 
 		if thex.Cat.et < 0.0 {
 			s := fmt.Sprintf("Name=%s catname=%s et=%f", thex.Name, thex.Cat.Name, thex.Cat.et)
-			Eprint("<Thexint>", s)
+			Eprint("<Thexint>", s, Ferr)
 		}
 
 		thex.Xeinold = FNXtr(26.0, 50.0)
@@ -279,7 +279,7 @@ Thexcfv (Total Heat Exchanger Characteristic Function Value Calculation)
 換気システムにおける熱回収の設計、熱負荷計算、
 およびエネルギー消費量予測を行うための重要な役割を果たします。
 */
-func Thexcfv(Thex []*THEX) {
+func Thexcfv(Thex []*THEX, debug bool, Ferr io.Writer) {
 	var Eoet, Eoot, Eoex, Eoox *ELOUT
 	var etCGmin, ehGmin, Aeout, Aein, Aoout, Aoin float64
 
@@ -296,12 +296,12 @@ func Thexcfv(Thex []*THEX) {
 			thex.Ge = Eoet.G
 			thex.Go = Eoot.G
 
-			if DEBUG {
+			if debug {
 				fmt.Printf("<Thexcfv>  %s Ge=%f Go=%f\n", thex.Cmp.Name, thex.Ge, thex.Go)
 			}
 
-			thex.CGe = Spcheat(Eoet.Fluid) * thex.Ge
-			thex.CGo = Spcheat(Eoot.Fluid) * thex.Go
+			thex.CGe = Spcheat(Eoet.Fluid, Ferr) * thex.Ge
+			thex.CGo = Spcheat(Eoot.Fluid, Ferr) * thex.Go
 			etCGmin = thex.ET * math.Min(thex.CGe, thex.CGo)
 			ehGmin = thex.EH * math.Min(thex.Ge, thex.Go)
 
@@ -612,7 +612,7 @@ Thexday (Total Heat Exchanger Daily and Monthly Data Aggregation)
 この関数は、全熱交換器の運転状況と熱湿気交換量を多角的に分析し、
 換気システムの運用改善や省エネルギー対策の効果評価を行うための重要なデータ集計機能を提供します。
 */
-func Thexday(Mon, Day, ttmm int, Thex []*THEX, Nday, SimDayend int) {
+func Thexday(Mon, Day, ttmm int, Thex []*THEX, Nday, SimDayend int, Cff_kWh float64) {
 	for _, thex := range Thex {
 		// 日集計
 		svdaysum(int64(ttmm), thex.Cmp.Control, thex.Tein, &thex.Teidy)
@@ -625,13 +625,13 @@ func Thexday(Mon, Day, ttmm int, Thex []*THEX, Nday, SimDayend int) {
 		svdaysum(int64(ttmm), thex.Cmp.Control, thex.Xoin, &thex.Xoidy)
 		svdaysum(int64(ttmm), thex.Cmp.Control, thex.Xoout, &thex.Xoody)
 
-		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qes, &thex.Qdyes)
-		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qel, &thex.Qdyel)
-		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qet, &thex.Qdyet)
+		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qes, &thex.Qdyes, Cff_kWh)
+		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qel, &thex.Qdyel, Cff_kWh)
+		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qet, &thex.Qdyet, Cff_kWh)
 
-		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qos, &thex.Qdyos)
-		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qol, &thex.Qdyol)
-		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qot, &thex.Qdyot)
+		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qos, &thex.Qdyos, Cff_kWh)
+		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qol, &thex.Qdyol, Cff_kWh)
+		qdaysum(int64(ttmm), thex.Cmp.Control, thex.Qot, &thex.Qdyot, Cff_kWh)
 
 		// 月集計
 		svmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Tein, &thex.MTeidy, Nday, SimDayend)
@@ -644,13 +644,13 @@ func Thexday(Mon, Day, ttmm int, Thex []*THEX, Nday, SimDayend int) {
 		svmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Xoin, &thex.MXoidy, Nday, SimDayend)
 		svmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Xoout, &thex.MXoody, Nday, SimDayend)
 
-		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qes, &thex.MQdyes, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qel, &thex.MQdyel, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qet, &thex.MQdyet, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qes, &thex.MQdyes, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qel, &thex.MQdyel, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qet, &thex.MQdyet, Nday, SimDayend, Cff_kWh)
 
-		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qos, &thex.MQdyos, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qol, &thex.MQdyol, Nday, SimDayend)
-		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qot, &thex.MQdyot, Nday, SimDayend)
+		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qos, &thex.MQdyos, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qol, &thex.MQdyol, Nday, SimDayend, Cff_kWh)
+		qmonsum(Mon, Day, ttmm, thex.Cmp.Control, thex.Qot, &thex.MQdyot, Nday, SimDayend, Cff_kWh)
 	}
 }
 
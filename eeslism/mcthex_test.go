@@ -19,7 +19,7 @@ func TestThexint(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
+		Thexint(thexs, nil)
 
 		// Verify initialization
 		if thex.Type == 't' || thex.Type == 'h' {
@@ -40,7 +40,7 @@ func TestThexint(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
+		Thexint(thexs, nil)
 
 		// Verify sensible-only configuration
 		if thex.Type == 't' {
@@ -61,7 +61,7 @@ func TestThexint(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
+		Thexint(thexs, nil)
 
 		// Verify total heat exchanger configuration
 		if thex.Type == 'h' {
@@ -85,7 +85,7 @@ func TestThexint(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
+		Thexint(thexs, nil)
 		t.Log("Multiple THEX initialization completed successfully")
 	})
 
@@ -99,7 +99,7 @@ func TestThexint(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
+		Thexint(thexs, nil)
 		t.Log("Empty THEX list handled successfully")
 	})
 }
@@ -117,7 +117,7 @@ func TestThexcfv(t *testing.T) {
 			}
 		}()
 
-		Thexcfv(thexs)
+		Thexcfv(thexs, false, nil)
 
 		// Verify coefficient calculations
 		if thex.ET >= 0 && thex.EH >= 0 {
@@ -138,7 +138,7 @@ func TestThexcfv(t *testing.T) {
 			}
 		}()
 
-		Thexcfv(thexs)
+		Thexcfv(thexs, false, nil)
 
 		// Verify sensible-only coefficients
 		if thex.Type == 't' {
@@ -159,7 +159,7 @@ func TestThexcfv(t *testing.T) {
 			}
 		}()
 
-		Thexcfv(thexs)
+		Thexcfv(thexs, false, nil)
 
 		// Verify total heat coefficients
 		if thex.Type == 'h' {
@@ -180,7 +180,7 @@ func TestThexcfv(t *testing.T) {
 			}
 		}()
 
-		Thexcfv(thexs)
+		Thexcfv(thexs, false, nil)
 		t.Log("Off control coefficient calculation completed successfully")
 	})
 }
@@ -316,8 +316,8 @@ func TestTHEX_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
-		Thexcfv(thexs)
+		Thexint(thexs, nil)
+		Thexcfv(thexs, false, nil)
 
 		// Verify efficiency ranges are physically reasonable
 		if thex.ET > 0 {
@@ -349,7 +349,7 @@ func TestTHEX_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Thexcfv(thexs)
+		Thexcfv(thexs, false, nil)
 		Thexene(thexs)
 
 		// Verify temperature relationships
@@ -373,8 +373,8 @@ func TestTHEX_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
-		Thexcfv(thexs)
+		Thexint(thexs, nil)
+		Thexcfv(thexs, false, nil)
 		Thexene(thexs)
 
 		// Verify humidity ranges are physically reasonable
@@ -404,8 +404,8 @@ func TestTHEX_PerformanceCharacteristics(t *testing.T) {
 			}
 		}()
 
-		Thexint(thexs)
-		Thexcfv(thexs)
+		Thexint(thexs, nil)
+		Thexcfv(thexs, false, nil)
 		Thexene(thexs)
 
 		// Calculate and verify heat recovery effectiveness
@@ -431,7 +431,7 @@ func TestTHEX_PerformanceCharacteristics(t *testing.T) {
 			}
 		}()
 
-		Thexcfv(thexs)
+		Thexcfv(thexs, false, nil)
 
 		// Verify flow rates
 		if thex.Ge > 0 && thex.Go > 0 {
@@ -1069,7 +1069,7 @@ func TestThexday(t *testing.T) {
 		// Simulate multiple time steps
 		times := []int{900, 1000, 1100, 1200}
 		for _, ttmm := range times {
-			Thexday(7, 15, ttmm, thexs, 31, 365)
+			Thexday(7, 15, ttmm, thexs, 31, 365, 0.0)
 		}
 
 		// After 4 time steps, verify aggregation
@@ -1104,7 +1104,7 @@ func TestThexday(t *testing.T) {
 		thexs := []*THEX{thex}
 
 		Thexdyint(thexs)
-		Thexday(7, 15, 1200, thexs, 31, 365)
+		Thexday(7, 15, 1200, thexs, 31, 365, 0.0)
 
 		// OFF control should not aggregate
 		if thex.Teidy.Hrs != 0 {
@@ -1125,7 +1125,7 @@ func TestThexday(t *testing.T) {
 		Thexmonint(thexs)
 
 		// Call at end of month (Day=Nday)
-		Thexday(7, 31, 2400, thexs, 31, 365)
+		Thexday(7, 31, 2400, thexs, 31, 365, 0.0)
 
 		// Daily values should be aggregated
 		if thex.Teidy.Hrs != 1 {
@@ -1135,7 +1135,7 @@ func TestThexday(t *testing.T) {
 
 	t.Run("EmptyList", func(t *testing.T) {
 		// Should not panic with empty list
-		Thexday(7, 15, 1200, []*THEX{}, 31, 365)
+		Thexday(7, 15, 1200, []*THEX{}, 31, 365, 0.0)
 	})
 
 	t.Run("MultipleThex", func(t *testing.T) {
@@ -1149,7 +1149,7 @@ func TestThexday(t *testing.T) {
 		}
 
 		Thexdyint(thexs)
-		Thexday(7, 15, 1200, thexs, 31, 365)
+		Thexday(7, 15, 1200, thexs, 31, 365, 0.0)
 
 		// Verify each thex has independent aggregation
 		for i, thex := range thexs {
@@ -1181,7 +1181,7 @@ func TestThexday(t *testing.T) {
 		thexs := []*THEX{thex}
 
 		Thexdyint(thexs)
-		Thexday(7, 15, 1200, thexs, 31, 365)
+		Thexday(7, 15, 1200, thexs, 31, 365, 0.0)
 
 		// Verify all temperature fields aggregated
 		if thex.Teidy.Hrs != 1 {
@@ -20,6 +20,7 @@
 package eeslism
 
 import (
+	"io"
 	"errors"
 	"fmt"
 	"math"
@@ -76,7 +77,7 @@ func Valvinit(Valv []*VALV, Mpath []*MPATH) {
 	}
 }
 
-func Valvene(Valv []*VALV, Valvreset *int) {
+func Valvene(Valv []*VALV, Valvreset *int, debug bool) {
 	var etype EqpType
 	var T1, T2 float64
 	var Vcb *VALV
@@ -100,7 +101,7 @@ func Valvene(Valv []*VALV, Valvreset *int) {
 					Vcb.Plist.Gcalc = (1.0 - r) * *v.MGo
 					(*Valvreset)++
 
-					if DEBUG {
+					if debug {
 						fmt.Printf("<Valvene> Valvname=%s G=%f\n", v.Name, v.Plist.G)
 						fmt.Printf("    T1=%.1f T2=%.1f Tset=%.1f\n", T1, T2, *v.Tset)
 					}
@@ -116,7 +117,7 @@ func Valvene(Valv []*VALV, Valvreset *int) {
 
 /************************************************************************/
 
-func ValvControl(fi *EeTokens, Compnt []*COMPNT, Schdl *SCHDL, Simc *SIMCONTL, Wd *WDAT, vptr *VPTR) {
+func ValvControl(fi *EeTokens, Compnt []*COMPNT, Schdl *SCHDL, Simc *SIMCONTL, Wd *WDAT, vptr *VPTR, Ferr io.Writer) {
 	var s string
 	var Valv, Vb *VALV
 	var Vc *COMPNT
@@ -132,7 +133,7 @@ func ValvControl(fi *EeTokens, Compnt []*COMPNT, Schdl *SCHDL, Simc *SIMCONTL, W
 
 	Vc = Compntptr(s, Compnt)
 	if Vc == nil {
-		Eprint("<CONTRL>", s)
+		Eprint("<CONTRL>", s, Ferr)
 	}
 
 	vptr.Ptr = &Vc.Control
@@ -152,18 +153,18 @@ func ValvControl(fi *EeTokens, Compnt []*COMPNT, Schdl *SCHDL, Simc *SIMCONTL, W
 			s = fi.GetToken()
 			ad = fi.GetPos()
 
-			if k, err = idsch(s, Schdl.Sch, ""); err == nil {
+			if k, err = idsch(s, Schdl.Sch, "", Ferr); err == nil {
 				Valv.Xinit = &Schdl.Val[k]
 			} else {
-				Valv.Xinit = envptr(s, Simc, Compnt, Wd, nil)
+				Valv.Xinit = envptr(s, Simc, Compnt, Wd, nil, Ferr)
 			}
 		} else if s == "-Tout" {
 			s = fi.GetToken()
 			ad = fi.GetPos()
-			if k, err = idsch(s, Schdl.Sch, ""); err == nil {
+			if k, err = idsch(s, Schdl.Sch, "", Ferr); err == nil {
 				Valv.Tset = &Schdl.Val[k]
 			} else {
-				Valv.Tset = envptr(s, Simc, Compnt, Wd, nil)
+				Valv.Tset = envptr(s, Simc, Compnt, Wd, nil, Ferr)
 			}
 
 			Pelm = Valv.Plist.Pelm[len(Valv.Plist.Pelm)-1]
@@ -116,23 +116,23 @@ func VWVint(VAVs []*VAV, Compn []*COMPNT) {
 // /  | VAV |
 //
 //	+-----+ ---> [OUT 2] 湿度 (VAV_PDTのみ)
-func VAVcfv(vav []*VAV) {
+func VAVcfv(vav []*VAV, Ferr io.Writer) {
 	for _, v := range vav {
 		Eo1 := v.Cmp.Elouts[0]
 
 		if v.Cmp.Control != OFF_SW && Eo1.Control != OFF_SW {
 			if v.Cat.Gmax < 0.0 {
 				Err := fmt.Sprintf("Name=%s  Gmax=%.5g", v.Name, v.Cat.Gmax)
-				Eprint("VAVcfv", Err)
+				Eprint("VAVcfv", Err, Ferr)
 			}
 			if v.Cat.Gmin < 0.0 {
 				Err := fmt.Sprintf("Name=%s  Gmin=%.5g", v.Name, v.Cat.Gmin)
-				Eprint("VAVcfv", Err)
+				Eprint("VAVcfv", Err, Ferr)
 			}
 
 			if v.Count == 0 {
 				v.G = Eo1.G
-				v.CG = Spcheat(Eo1.Fluid) * v.G
+				v.CG = Spcheat(Eo1.Fluid, Ferr) * v.G
 
 				Eo1.Coeffo = v.CG
 				Eo1.Co = 0.0
@@ -159,7 +159,7 @@ func VAVcfv(vav []*VAV) {
 /* VAVコントローラ再熱部分の計算 */
 /*---- Satoh Debug VAV  2000/11/27 ----*/
 /*******************/
-func VAVene(vav []*VAV, VAVrest *int) {
+func VAVene(vav []*VAV, VAVrest *int, Ferr io.Writer) {
 	var rest int
 	var elo *ELOUT
 	var Tr, Go, dTset float64
@@ -177,7 +177,7 @@ func VAVene(vav []*VAV, VAVrest *int) {
 			if v.Cat.Type == VAV_PDT {
 				Tr = v.Cmp.Elouts[0].Emonitr.Sysv
 
-				v.Q = Spcheat(elo.Fluid) * Go * (v.Tout - Tr)
+				v.Q = Spcheat(elo.Fluid, Ferr) * Go * (v.Tout - Tr)
 
 				if mathAbs(v.Tin-Tr) > 1.0e-3 {
 					v.G = (v.Tout - Tr) / (v.Tin - Tr) * Go
@@ -217,7 +217,7 @@ func VAVene(vav []*VAV, VAVrest *int) {
 				}
 
 				if v.Mon == 'h' || v.Mon == 'f' {
-					v.G = v.Q / (Spcheat(elo.Fluid) * dTset)
+					v.G = v.Q / (Spcheat(elo.Fluid, Ferr) * dTset)
 				} else if v.Mon == 'c' {
 					v.G = FNVWVG(v)
 				}
@@ -132,7 +132,7 @@ func TestVAVcfv(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 
 		// Verify coefficient calculations
 		if vav.Cmp != nil && len(vav.Cmp.Elouts) > 0 {
@@ -153,7 +153,7 @@ func TestVAVcfv(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 
 		// Verify cooling mode coefficients
 		t.Log("Cooling mode coefficient calculation verified")
@@ -172,7 +172,7 @@ func TestVAVcfv(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 
 		// Verify heating mode coefficients
 		t.Log("Heating mode coefficient calculation verified")
@@ -191,7 +191,7 @@ func TestVAVcfv(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 
 		// Verify variable flow coefficients
 		t.Log("Variable flow coefficient calculation verified")
@@ -210,7 +210,7 @@ func TestVAVcfv(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 		t.Log("Off control coefficient calculation completed successfully")
 	})
 
@@ -220,7 +220,7 @@ func TestVAVcfv(t *testing.T) {
 		vav.Count = 1 // Non-zero count
 		vavs := []*VAV{vav}
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 
 		// When Count != 0, Coeffo should be 1.0
 		if vav.Cmp.Elouts[0].Coeffo != 1.0 {
@@ -238,7 +238,7 @@ func TestVAVcfv(t *testing.T) {
 		vav.Cat.Type = VAV_PDT // Set PDT type
 		vavs := []*VAV{vav}
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 
 		// Both outputs should be configured
 		if vav.Cmp.Elouts[1].Coeffo != 1.0 {
@@ -262,7 +262,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify energy calculations
 		t.Logf("Energy calculation results - Heat: Q=%.1f", vav.Q)
@@ -283,7 +283,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify cooling energy calculations
 		if vav.Q < 0 { // Cooling should be negative
@@ -305,7 +305,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify heating energy calculations
 		if vav.Q > 0 { // Heating should be positive
@@ -327,7 +327,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify reheat energy calculations
 		t.Log("Reheat energy calculation verified")
@@ -347,7 +347,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify variable flow energy calculations
 		t.Log("Variable flow energy calculation verified")
@@ -367,7 +367,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify energy balance
 		if vav.Cmp.Control == ON_SW {
@@ -390,7 +390,7 @@ func TestVAVene(t *testing.T) {
 		}()
 
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify all energy values are zero when OFF
 		if vav.Q == 0.0 && vav.G == 0.0 {
@@ -414,9 +414,9 @@ func TestVAV_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify flow rate ranges are physically reasonable
 		t.Log("Flow rate validation completed - variable flow rates checked")
@@ -435,9 +435,9 @@ func TestVAV_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify temperature ranges are physically reasonable
 		t.Log("Temperature validation completed - supply and room temperatures checked")
@@ -456,9 +456,9 @@ func TestVAV_PhysicalValidation(t *testing.T) {
 			}
 		}()
 
-		VAVcfv(vavs)
+		VAVcfv(vavs, nil)
 		var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 
 		// Verify control logic is working properly
 		t.Log("Control validation completed - VAV control logic checked")
@@ -488,9 +488,9 @@ func TestVAV_PerformanceCharacteristics(t *testing.T) {
 				vav.Cmp.Elouts[0].G = factor * 2.0 // Assume 2.0 kg/s full load
 			}
 			
-			VAVcfv(vavs)
+			VAVcfv(vavs, nil)
 			var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 			
 			t.Logf("Load factor: %.1f, Flow: %.2f kg/s, Energy: %.1f W", 
 				factor, factor*2.0, vav.Q)
@@ -513,9 +513,9 @@ func TestVAV_PerformanceCharacteristics(t *testing.T) {
 		// Simulate control sequence: cooling → minimum flow → reheat
 		controlSteps := []string{"cooling", "minimum_flow", "reheat"}
 		for _, step := range controlSteps {
-			VAVcfv(vavs)
+			VAVcfv(vavs, nil)
 			var VAVrest int
-		VAVene(vavs, &VAVrest)
+		VAVene(vavs, &VAVrest, nil)
 			
 			t.Logf("Control step: %s, Energy: %.1f W", step, vav.Q)
 		}
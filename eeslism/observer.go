@@ -0,0 +1,79 @@
+/*
+observer.go (Simulation Observer)
+
+計算結果をファイル出力（Eeprinth、Eeprintd、Eeprintm）とは別に、
+計算中のプロセス内で受け取るためのオブザーバーを定義します。
+
+オブザーバーは Simulation.AddObserver で登録し、以下の時点で呼び出されます。
+  - OnTimestep: 各時間ステップの計算と時刻別の結果出力の後（助走期間を含む）
+  - OnDayEnd: 日集計の出力の後（助走期間を除く）
+  - OnMonthEnd: 月集計の出力の後（月末日とシミュレーション期間の最終日）
+  - OnRunEnd: Finalize で月－時刻別集計値を出力した後
+
+Snapshot の Rmvls、Eqsys などは計算中の値を指しており、次の時間ステップで更新されます。
+コールバックの外で参照する場合は、必要な値をコピーしてください。
+*/
+package eeslism
+
+// Observer はシミュレーションの計算結果を受け取るインターフェースです。
+type Observer interface {
+	OnTimestep(s *Snapshot)
+	OnDayEnd(s *Snapshot)
+	OnMonthEnd(s *Snapshot)
+	OnRunEnd(s *Snapshot)
+}
+
+// Snapshot はオブザーバーに渡す計算結果です。
+type Snapshot struct {
+	Daytm DAYTM  // 日付・時刻。助走期間は Daytm.Ddpri が 0
+	Wd    *WDAT  // 気象データ。OnDayEnd では日集計、OnMonthEnd では月集計
+	Rmvls *RMVLS // 室、壁体、放射パネルなど建物の計算結果
+	Eqsys *EQSYS // システム要素（機器）の計算結果
+}
+
+// ObserverFuncs は関数で Observer を実装します。nil の関数は呼び出されません。
+type ObserverFuncs struct {
+	Timestep func(s *Snapshot)
+	DayEnd   func(s *Snapshot)
+	MonthEnd func(s *Snapshot)
+	RunEnd   func(s *Snapshot)
+}
+
+func (f *ObserverFuncs) OnTimestep(s *Snapshot) {
+	if f.Timestep != nil {
+		f.Timestep(s)
+	}
+}
+
+func (f *ObserverFuncs) OnDayEnd(s *Snapshot) {
+	if f.DayEnd != nil {
+		f.DayEnd(s)
+	}
+}
+
+func (f *ObserverFuncs) OnMonthEnd(s *Snapshot) {
+	if f.MonthEnd != nil {
+		f.MonthEnd(s)
+	}
+}
+
+func (f *ObserverFuncs) OnRunEnd(s *Snapshot) {
+	if f.RunEnd != nil {
+		f.RunEnd(s)
+	}
+}
+
+// AddObserver はオブザーバーを登録します。Run または Init の前に呼び出してください。
+func (sim *Simulation) AddObserver(o Observer) {
+	sim.observers = append(sim.observers, o)
+}
+
+// snapshot はオブザーバーに渡す計算結果を作成します。
+func (sim *Simulation) snapshot(Wd *WDAT) *Snapshot {
+	return &Snapshot{
+		Daytm: sim.Daytm,
+		Wd:    Wd,
+		Rmvls: sim.Rmvls,
+		Eqsys: sim.Eqsys,
+	}
+}
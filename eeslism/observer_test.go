@@ -0,0 +1,53 @@
+package eeslism
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestSimulation_Observer はオブザーバーが各時間ステップ、日末、月末、計算終了時に呼び出されることを確認する
+func TestSimulation_Observer(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+	var steps, days, months, runs int
+	var lastDay DAYTM
+	var maxTr float64
+	sim.AddObserver(&ObserverFuncs{
+		Timestep: func(s *Snapshot) {
+			steps++
+			if s.Wd.T != sim.Wd.T {
+				t.Errorf("Wd.T = %g, want %g", s.Wd.T, sim.Wd.T)
+			}
+			if Tr := s.Rmvls.Room[0].Tr; Tr > maxTr {
+				maxTr = Tr
+			}
+		},
+		DayEnd: func(s *Snapshot) {
+			days++
+			lastDay = s.Daytm
+			if len(s.Eqsys.Boi) != 1 {
+				t.Errorf("len(Eqsys.Boi) = %d, want 1", len(s.Eqsys.Boi))
+			}
+		},
+		MonthEnd: func(s *Snapshot) { months++ },
+		RunEnd:   func(s *Snapshot) { runs++ },
+	})
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if steps != 7*24 || days != 7 || months != 1 || runs != 1 {
+		t.Errorf("steps, days, months, runs = %d, %d, %d, %d, want 168, 7, 1, 1", steps, days, months, runs)
+	}
+	if lastDay.Mon != 1 || lastDay.Day != 7 {
+		t.Errorf("last day = %d/%d, want 1/7", lastDay.Mon, lastDay.Day)
+	}
+	if maxTr == 0 {
+		t.Error("room temperature is not passed to the observer")
+	}
+}
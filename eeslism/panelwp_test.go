@@ -57,7 +57,7 @@ func TestPanelwp(t *testing.T) {
 				t.Error("Expected panic for nil rdpnl")
 			}
 		}()
-		panelwp(nil)
+		panelwp(nil, nil)
 	})
 
 	t.Run("NilComponentCheck", func(t *testing.T) {
@@ -68,7 +68,7 @@ func TestPanelwp(t *testing.T) {
 				t.Error("Expected panic for nil cmp")
 			}
 		}()
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 	})
 
 	t.Run("NilEloutsCheck", func(t *testing.T) {
@@ -79,7 +79,7 @@ func TestPanelwp(t *testing.T) {
 				t.Error("Expected panic for nil Elouts")
 			}
 		}()
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 	})
 
 	t.Run("InvalidEloutsLengthCheck", func(t *testing.T) {
@@ -90,14 +90,14 @@ func TestPanelwp(t *testing.T) {
 				t.Error("Expected panic for empty Elouts")
 			}
 		}()
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 	})
 
 	t.Run("ControlOFF", func(t *testing.T) {
 		rdpnl := createTestRDPNL()
 		rdpnl.cmp.Elouts[0].Control = OFF_SW
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		if rdpnl.cG != 0.0 {
 			t.Errorf("Expected cG=0 when control is OFF, got %f", rdpnl.cG)
@@ -118,9 +118,9 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.sd[0].A = 10.0        // 面積 10m2
 		rdpnl.sd[0].mw.wall.WallType = WallType_P
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
-		expectedCG := 0.1 * Spcheat(WATER_FLD)
+		expectedCG := 0.1 * Spcheat(WATER_FLD, nil)
 		if math.Abs(rdpnl.cG-expectedCG) > 1e-10 {
 			t.Errorf("Expected cG=%f, got %f", expectedCG, rdpnl.cG)
 		}
@@ -140,9 +140,9 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.sd[0].mw.wall.Kc = 100.0
 		rdpnl.sd[0].mw.wall.Kcd = 50.0
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
-		expectedCG := 0.1 * Spcheat(WATER_FLD)
+		expectedCG := 0.1 * Spcheat(WATER_FLD, nil)
 		if math.Abs(rdpnl.cG-expectedCG) > 1e-10 {
 			t.Errorf("Expected cG=%f, got %f", expectedCG, rdpnl.cG)
 		}
@@ -167,10 +167,10 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.sd[0].dblKc = 120.0            // 表面固有の値
 		rdpnl.sd[0].dblKcd = 60.0
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// chrRinputがtrueの場合、sd.dblKc, sd.dblKcdが使用される
-		expectedCG := 0.1 * Spcheat(WATER_FLD)
+		expectedCG := 0.1 * Spcheat(WATER_FLD, nil)
 		expectedEc := 1.0 - math.Exp(-rdpnl.sd[0].dblKc*rdpnl.sd[0].A/expectedCG)
 		expectedWp := rdpnl.sd[0].dblKcd * expectedCG * expectedEc / (rdpnl.sd[0].dblKc * rdpnl.sd[0].A)
 
@@ -190,7 +190,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.effpnl = 0.8
 		rdpnl.sd[0].mw.wall.WallType = WallType_P
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// Wpが変化したので、マークが'*'に設定されるはず
 		if rdpnl.sd[0].mrk != '*' {
@@ -220,7 +220,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.sd[0].mw.wall.WallType = WallType_P
 
 		// 最初の計算
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 		firstWp := rdpnl.Wp
 
 		// マークをリセット
@@ -228,7 +228,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.rm[0].mrk = ' '
 
 		// 同じ条件で再計算
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// Wpが変化していないので、マークは変更されないはず
 		if rdpnl.sd[0].mrk == '*' {
@@ -249,7 +249,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.Wpold = 0.0
 		rdpnl.cmp.Elouts[0].Control = OFF_SW // OFFでもPCMフラグがあれば処理される
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// PCMフラグが立っていれば、Wpが変化しなくてもマークが設定される
 		if rdpnl.sd[0].mrk != '*' {
@@ -266,7 +266,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.cmp.Elouts[0].G = 0.1
 		rdpnl.cmp.Elins[0].Upv = nil // nilポインタ
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// Upvがnilの場合、制御がONでも流量は0になるはず
 		if rdpnl.cG != 0.0 {
@@ -285,7 +285,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.sd[0].mw.wall.WallType = WallType_P
 
 		// 最初の計算
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// Wpoldを微小量だけ変更（許容誤差以下）
 		rdpnl.Wpold = rdpnl.Wp + WPTOLE/2
@@ -293,7 +293,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.rm[0].mrk = ' '
 
 		// 再計算
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// 許容誤差以下の変化なので、マークは変更されないはず
 		if rdpnl.sd[0].mrk == '*' {
@@ -312,7 +312,7 @@ func TestPanelwp(t *testing.T) {
 		rdpnl.effpnl = 0.01           // 非常に低い効率
 		rdpnl.sd[0].A = 1000.0        // 大きな面積
 
-		panelwp(rdpnl)
+		panelwp(rdpnl, nil)
 
 		// 計算が正常に完了し、値が有限であることを確認
 		if math.IsNaN(rdpnl.Wp) || math.IsInf(rdpnl.Wp, 0) {
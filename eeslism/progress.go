@@ -0,0 +1,40 @@
+/*
+progress.go (Simulation Progress)
+
+長時間の計算の進捗状況を報告するための型を定義します。
+Simulation.ProgressFunc を設定すると、各時間ステップの計算の後に Progress が渡されます。
+*/
+package eeslism
+
+// Progress はシミュレーションの進捗状況です。
+type Progress struct {
+	Daytm    DAYTM   // 計算した時間ステップの日付・時刻
+	Warmup   bool    // 助走期間かどうか
+	Step     int     // 計算済みの時間ステップ数（助走期間を含む）
+	Steps    int     // 全時間ステップ数（助走期間を含む）
+	Fraction float64 // 計算済みの割合（0〜1）
+
+	// 直前の時間ステップの収束計算の繰り返し回数
+	LoopCount   int // 再計算が必要な機器のループ（上限 LoopMax）の最大繰り返し回数
+	LoopMax     int
+	VAVCount    int // VAV 計算繰り返しループ（上限 VAVCountMax）の繰り返し回数
+	VAVCountMax int
+}
+
+// progress は現在の進捗状況を返します。
+func (sim *Simulation) progress() Progress {
+	p := Progress{
+		Daytm:       sim.Daytm,
+		Warmup:      sim.nday < sim.Simc.Daystart,
+		Step:        sim.nstep,
+		Steps:       sim.nsteps,
+		LoopCount:   sim.loopCount,
+		LoopMax:     sim.loopMax,
+		VAVCount:    sim.vavCount,
+		VAVCountMax: sim.vavCountMax,
+	}
+	if p.Steps > 0 {
+		p.Fraction = float64(p.Step) / float64(p.Steps)
+	}
+	return p
+}
@@ -0,0 +1,73 @@
+package eeslism
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestSimulation_Progress は進捗状況が各時間ステップの後に報告されることを確認する
+func TestSimulation_Progress(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+	var last Progress
+	n := 0
+	sim.ProgressFunc = func(p Progress) {
+		n++
+		if p.Step != n {
+			t.Errorf("Step = %d, want %d", p.Step, n)
+		}
+		if p.LoopCount < 1 || p.LoopCount > p.LoopMax || p.VAVCount < 1 || p.VAVCount > p.VAVCountMax {
+			t.Errorf("LoopCount = %d/%d, VAVCount = %d/%d", p.LoopCount, p.LoopMax, p.VAVCount, p.VAVCountMax)
+		}
+		last = p
+	}
+	if err := sim.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 7*24 || last.Steps != 7*24 || last.Fraction != 1 {
+		t.Errorf("%d calls, Steps = %d, Fraction = %g", n, last.Steps, last.Fraction)
+	}
+	if last.Daytm.Mon != 1 || last.Daytm.Day != 7 || last.Warmup {
+		t.Errorf("last Daytm = %d/%d (warmup %v)", last.Daytm.Mon, last.Daytm.Day, last.Warmup)
+	}
+}
+
+// TestSimulation_RunContext はキャンセルで計算が中断され、それまでの計算結果が出力されることを確認する
+func TestSimulation_RunContext(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	sim := NewSimulation(copySimulationInput(t, src, dir), eflPath)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	steps := 0
+	sim.ProgressFunc = func(p Progress) {
+		steps = p.Step
+		if p.Step == 30 {
+			cancel()
+		}
+	}
+	if err := sim.RunContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if steps != 30 {
+		t.Errorf("%d steps, want 30", steps)
+	}
+	if len(readSimulationOutputs(t, dir)) == 0 {
+		t.Error("No output files written")
+	}
+	if err := sim.Finalize(); err == nil {
+		t.Error("Finalize after cancellation: expected error")
+	}
+}
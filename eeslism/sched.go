@@ -69,6 +69,15 @@ type SCH struct /*スケジュ－ル*/
 	name string
 	Type rune
 	day  [366]int //インデックス0は使用しない
+	csv  *SCHCSV  // CSV の時系列によるスケジュール (csv=)。ref: eschcsv.go
+	defs []schdef // 季節、曜日による一日のスケジュールの指定。曜日を変更する場合に day を作り直す
+}
+
+// 季節、曜日による一日のスケジュールの指定 (SCHNM)
+type schdef struct {
+	is    int   // 季節設定の番号。-1 の場合は季節の指定なし
+	wkday *WKDY // 曜日設定。nil の場合は曜日の指定なし
+	sc    int   // 一日の設定値スケジュール（-v）または切換スケジュール（-s）の番号
 }
 
 // 一日の設定値、切換スケジュールおよび季節、曜日の指定
@@ -0,0 +1,517 @@
+/*
+server.go (HTTP Job Server)
+
+入力データファイルを HTTP で受け付けて計算し、計算結果のファイルを返すジョブサーバーを定義します。
+ジョブは Workers 個の計算を同時に行うワーカーで順に計算します。
+
+  - POST   /jobs                    ジョブの登録
+  - GET    /jobs                    ジョブの一覧
+  - GET    /jobs/{id}               ジョブの状態と進捗状況
+  - DELETE /jobs/{id}               計算中のジョブの中断、または終了したジョブの削除
+  - GET    /jobs/{id}/files/{name}  計算結果のファイル
+  - GET    /jobs/{id}/results.zip   全ての計算結果のファイル（zip）
+  - GET    /jobs/{id}/results.json  全ての計算結果のファイル（ファイル名と内容の JSON）
+
+ジョブの登録は multipart/form-data の input（入力データファイル、必須）、weather（気象データファイル）、
+file（その他の参照ファイル、複数可）で送信します。multipart 以外の場合は本文を入力データファイルとします（名前は ?name=）。
+計算結果のファイルはジョブが終了（done、failed、canceled）した後に取得できます。
+終了したジョブは Retention の期間の後、または終了したジョブが MaxJobs を超えた場合に古いものから削除します。
+送信したデータが MaxUpload を超える場合は 413 を応答します。
+入力データファイルから参照するファイルは、登録時に送信したファイルのみを読み込みます。
+*/
+package eeslism
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ジョブの状態
+const (
+	JobQueued   = "queued"   // 計算待ち
+	JobRunning  = "running"  // 計算中
+	JobDone     = "done"     // 計算終了
+	JobFailed   = "failed"   // エラーで終了
+	JobCanceled = "canceled" // 中断
+)
+
+// ServerOptions はジョブサーバーの設定です。
+type ServerOptions struct {
+	EflPath   string // EFLファイルのディレクトリ。空の場合は埋め込みの Base を用いる
+	Workers   int    // 同時に計算するジョブの数。0 以下の場合は1
+	QueueSize int    // 計算待ちのジョブの上限。0 以下の場合は64
+	MaxUpload int64  // 登録時に送信できるデータの上限 [byte]。0 以下の場合は64MB
+
+	Retention time.Duration // 終了したジョブを保持する期間。0 以下の場合は1時間
+	MaxJobs   int           // 保持する終了したジョブの上限。0 以下の場合は100
+}
+
+// Server は HTTP のジョブサーバーです。NewServer で作成し、http.Handler として用います。
+type Server struct {
+	opts  ServerOptions
+	mux   *http.ServeMux
+	queue chan *job
+	wg    sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*job
+	seq  int // 登録順
+
+	now func() time.Time // 現在時刻（テスト用）
+}
+
+// job はサーバーに登録された1つの計算です。
+type job struct {
+	id      string
+	seq     int
+	name    string            // 入力データファイル名
+	files   map[string][]byte // 入力データファイルと参照ファイル
+	weather map[string][]byte // 気象データファイル
+	out     *MemorySink
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	state    string
+	err      error
+	progress Progress
+	created  time.Time
+	started  time.Time
+	finished time.Time
+}
+
+// JobStatus はジョブの状態です。GET /jobs/{id} の応答に用います。
+type JobStatus struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	State    string       `json:"state"`
+	Error    string       `json:"error,omitempty"`
+	Progress *JobProgress `json:"progress,omitempty"`
+	Files    []string     `json:"files,omitempty"`
+	Created  time.Time    `json:"created"`
+	Started  *time.Time   `json:"started,omitempty"`
+	Finished *time.Time   `json:"finished,omitempty"`
+}
+
+// JobProgress は計算中のジョブの進捗状況です。
+type JobProgress struct {
+	Date     string  `json:"date"` // 計算した時間ステップの日時 `mm/dd hh:mm`
+	Warmup   bool    `json:"warmup"`
+	Step     int     `json:"step"`
+	Steps    int     `json:"steps"`
+	Fraction float64 `json:"fraction"`
+}
+
+// NewServer はジョブサーバーを作成し、ワーカーを開始します。
+func NewServer(opts ServerOptions) *Server {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 64
+	}
+	if opts.MaxUpload <= 0 {
+		opts.MaxUpload = 64 << 20
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = time.Hour
+	}
+	if opts.MaxJobs <= 0 {
+		opts.MaxJobs = 100
+	}
+
+	s := &Server{
+		opts:  opts,
+		mux:   http.NewServeMux(),
+		queue: make(chan *job, opts.QueueSize),
+		jobs:  make(map[string]*job),
+		now:   time.Now,
+	}
+	s.mux.HandleFunc("POST /jobs", s.handleSubmit)
+	s.mux.HandleFunc("GET /jobs", s.handleList)
+	s.mux.HandleFunc("GET /jobs/{id}", s.handleStatus)
+	s.mux.HandleFunc("DELETE /jobs/{id}", s.handleDelete)
+	s.mux.HandleFunc("GET /jobs/{id}/files/{name}", s.handleFile)
+	s.mux.HandleFunc("GET /jobs/{id}/results.zip", s.handleZip)
+	s.mux.HandleFunc("GET /jobs/{id}/results.json", s.handleJSON)
+
+	for i := 0; i < opts.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker(s.queue)
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.prune()
+	s.mux.ServeHTTP(w, r)
+}
+
+// prune は Retention の期間を過ぎた終了したジョブと、MaxJobs を超える古い終了したジョブを削除します。
+func (s *Server) prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	var finished []*job
+	for id, j := range s.jobs {
+		j.mu.Lock()
+		t := j.finished
+		j.mu.Unlock()
+		switch {
+		case t.IsZero():
+		case now.Sub(t) >= s.opts.Retention:
+			delete(s.jobs, id)
+		default:
+			finished = append(finished, j)
+		}
+	}
+	if n := len(finished) - s.opts.MaxJobs; n > 0 {
+		sort.Slice(finished, func(a, b int) bool { return finished[a].seq < finished[b].seq })
+		for _, j := range finished[:n] {
+			delete(s.jobs, j.id)
+		}
+	}
+}
+
+// Close は計算中、計算待ちのジョブを中断し、ワーカーの終了を待ちます。
+// Close の後にジョブを登録することはできません。
+func (s *Server) Close() {
+	s.mu.Lock()
+	for _, j := range s.jobs {
+		j.cancel()
+	}
+	close(s.queue)
+	s.queue = nil
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// worker は計算待ちのジョブを順に計算します。
+func (s *Server) worker(queue <-chan *job) {
+	defer s.wg.Done()
+	for j := range queue {
+		s.run(j)
+	}
+}
+
+// run はジョブ j を計算します。
+func (s *Server) run(j *job) {
+	j.mu.Lock()
+	if err := j.ctx.Err(); err != nil {
+		// 計算待ちの間に中断された
+		j.state, j.err, j.finished = JobCanceled, err, s.now()
+		j.mu.Unlock()
+		return
+	}
+	j.state, j.started = JobRunning, s.now()
+	j.mu.Unlock()
+
+	sim := NewSimulation(j.name, s.opts.EflPath)
+	sim.FS = overlayFS{files: j.files}
+	sim.EflFS = overlayFS{files: j.weather, base: eflFS(s.opts.EflPath)}
+	sim.Output = j.out
+	sim.ProgressFunc = func(p Progress) {
+		j.mu.Lock()
+		j.progress = p
+		j.mu.Unlock()
+	}
+	err := sim.RunContext(j.ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.finished = s.now()
+	switch {
+	case err == nil:
+		j.state = JobDone
+	case errors.Is(err, context.Canceled):
+		j.state, j.err = JobCanceled, err
+	default:
+		j.state, j.err = JobFailed, err
+	}
+}
+
+// status はジョブの状態を返します。
+func (j *job) status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	st := JobStatus{ID: j.id, Name: j.name, State: j.state, Created: j.created}
+	if j.err != nil {
+		st.Error = j.err.Error()
+	}
+	if !j.started.IsZero() {
+		started := j.started
+		st.Started = &started
+		p := j.progress
+		st.Progress = &JobProgress{
+			Date:     fmt.Sprintf("%02d/%02d %02d:%02d", p.Daytm.Mon, p.Daytm.Day, p.Daytm.Ttmm/100, p.Daytm.Ttmm%100),
+			Warmup:   p.Warmup,
+			Step:     p.Step,
+			Steps:    p.Steps,
+			Fraction: p.Fraction,
+		}
+	}
+	if !j.finished.IsZero() {
+		finished := j.finished
+		st.Finished = &finished
+		st.Files = j.out.Names()
+	}
+	return st
+}
+
+// isFinished はジョブが終了しているかどうかを返します。
+func (j *job) isFinished() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return !j.finished.IsZero()
+}
+
+// fileName はアップロードされたファイル名からディレクトリを除いた名前を返します。
+func fileName(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" || name == "." || name == "/" || name == ".." {
+		return "", fmt.Errorf("invalid file name %q", name)
+	}
+	return name, nil
+}
+
+// handleSubmit はジョブを登録します。
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxUpload)
+	j := &job{
+		files:   make(map[string][]byte),
+		weather: make(map[string][]byte),
+		out:     new(MemorySink),
+		state:   JobQueued,
+		created: s.now(),
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				readError(w, err)
+				return
+			}
+			name, err := fileName(part.FileName())
+			if err != nil {
+				httpError(w, http.StatusBadRequest, fmt.Errorf("%s: %w", part.FormName(), err))
+				return
+			}
+			data, err := io.ReadAll(part)
+			if err != nil {
+				readError(w, err)
+				return
+			}
+			switch part.FormName() {
+			case "input":
+				j.name = name
+				j.files[name] = data
+			case "weather":
+				j.weather[name] = data
+			case "file":
+				j.files[name] = data
+			default:
+				httpError(w, http.StatusBadRequest, fmt.Errorf("unknown form field %q", part.FormName()))
+				return
+			}
+		}
+	} else {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			name = "input.txt"
+		}
+		name, err := fileName(name)
+		if err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			readError(w, err)
+			return
+		}
+		j.name = name
+		j.files[name] = data
+	}
+	if j.name == "" {
+		httpError(w, http.StatusBadRequest, errors.New("no input data file"))
+		return
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+	j.id = hex.EncodeToString(id)
+	j.ctx, j.cancel = context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	if s.queue == nil {
+		s.mu.Unlock()
+		httpError(w, http.StatusServiceUnavailable, errors.New("server is closed"))
+		return
+	}
+	select {
+	case s.queue <- j:
+	default:
+		s.mu.Unlock()
+		httpError(w, http.StatusServiceUnavailable, errors.New("too many queued jobs"))
+		return
+	}
+	s.seq++
+	j.seq = s.seq
+	s.jobs[j.id] = j
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/jobs/"+j.id)
+	writeJSON(w, http.StatusAccepted, j.status())
+}
+
+// handleList はジョブの一覧を登録順に返します。
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.mu.Unlock()
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].seq < jobs[b].seq })
+
+	list := make([]JobStatus, len(jobs))
+	for i, j := range jobs {
+		list[i] = j.status()
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// lookup は URL のジョブを返します。ジョブがない場合は 404 を応答して nil を返します。
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) *job {
+	s.mu.Lock()
+	j := s.jobs[r.PathValue("id")]
+	s.mu.Unlock()
+	if j == nil {
+		httpError(w, http.StatusNotFound, errors.New("job not found"))
+	}
+	return j
+}
+
+// finishedJob は計算結果を取得できるジョブを返します。
+// 終了していない場合は 409 を応答して nil を返します。
+func (s *Server) finishedJob(w http.ResponseWriter, r *http.Request) *job {
+	j := s.lookup(w, r)
+	if j != nil && !j.isFinished() {
+		httpError(w, http.StatusConflict, errors.New("job is not finished"))
+		return nil
+	}
+	return j
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if j := s.lookup(w, r); j != nil {
+		writeJSON(w, http.StatusOK, j.status())
+	}
+}
+
+// handleDelete は終了していないジョブを中断し、終了したジョブを削除します。
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+	if !j.isFinished() {
+		j.cancel()
+		writeJSON(w, http.StatusAccepted, j.status())
+		return
+	}
+	s.mu.Lock()
+	delete(s.jobs, j.id)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	j := s.finishedJob(w, r)
+	if j == nil {
+		return
+	}
+	data := j.out.Bytes(r.PathValue("name"))
+	if data == nil {
+		httpError(w, http.StatusNotFound, errors.New("file not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(data)
+}
+
+func (s *Server) handleZip(w http.ResponseWriter, r *http.Request) {
+	j := s.finishedJob(w, r)
+	if j == nil {
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.TrimSuffix(j.name, path.Ext(j.name))+".zip"))
+	zw := zip.NewWriter(w)
+	for _, name := range j.out.Names() {
+		f, err := zw.Create(name)
+		if err != nil {
+			return
+		}
+		f.Write(j.out.Bytes(name))
+	}
+	zw.Close()
+}
+
+func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+	j := s.finishedJob(w, r)
+	if j == nil {
+		return
+	}
+	files := make(map[string]string)
+	for _, name := range j.out.Names() {
+		files[name] = string(j.out.Bytes(name))
+	}
+	writeJSON(w, http.StatusOK, files)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// readError は送信されたデータの読み込みのエラー err を応答します。MaxUpload を超えた場合は 413 とします。
+func readError(w http.ResponseWriter, err error) {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		httpError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	httpError(w, http.StatusBadRequest, err)
+}
@@ -0,0 +1,290 @@
+package eeslism
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestServer はジョブの登録から計算結果の取得までを確認する
+func TestServer(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	input, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(ServerOptions{Workers: 2})
+	defer s.Close()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("input", filepath.Base(src))
+	fw.Write(input)
+	mw.Close()
+	resp, err := http.Post(ts.URL+"/jobs", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st JobStatus
+	json.NewDecoder(resp.Body).Decode(&st)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || st.ID == "" || st.Name != filepath.Base(src) {
+		t.Fatalf("POST /jobs = %d %+v", resp.StatusCode, st)
+	}
+
+	// 計算の終了を待つ
+	deadline := time.Now().Add(time.Minute)
+	for st.Finished == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", st)
+		}
+		time.Sleep(50 * time.Millisecond)
+		resp, err := http.Get(ts.URL + "/jobs/" + st.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st = JobStatus{}
+		json.NewDecoder(resp.Body).Decode(&st)
+		resp.Body.Close()
+	}
+	if st.State != JobDone || st.Progress == nil || st.Progress.Fraction != 1 {
+		t.Fatalf("job = %+v", st)
+	}
+
+	// 計算結果はテキストファイルとして計算した場合と同じ
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := NewSimulation(copySimulationInput(t, src, dir), eflPath).Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := readSimulationOutputs(t, dir)
+
+	resp, err = http.Get(ts.URL + "/jobs/" + st.ID + "/results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files map[string]string
+	json.NewDecoder(resp.Body).Decode(&files)
+	resp.Body.Close()
+	if len(files) < len(want) {
+		t.Errorf("results.json has %d files, want at least %d", len(files), len(want))
+	}
+	for name, data := range want {
+		if files[name] != strings.ReplaceAll(data, "<dir>\\", "") {
+			t.Errorf("%s differs from the file output", name)
+		}
+	}
+
+	resp, err = http.Get(ts.URL + "/jobs/" + st.ID + "/results.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zb bytes.Buffer
+	zb.ReadFrom(resp.Body)
+	resp.Body.Close()
+	zr, err := zip.NewReader(bytes.NewReader(zb.Bytes()), int64(zb.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != len(files) {
+		t.Errorf("results.zip has %d files, want %d", len(zr.File), len(files))
+	}
+
+	resp, err = http.Get(ts.URL + "/jobs/" + st.ID + "/files/" + st.Files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET files/%s = %d", st.Files[0], resp.StatusCode)
+	}
+
+	// 入力データファイルのないジョブは登録しない
+	resp, err = http.Post(ts.URL+"/jobs", mw.FormDataContentType(), bytes.NewReader(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /jobs without input = %d, want 400", resp.StatusCode)
+	}
+
+	// 終了したジョブは削除する
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/jobs/"+st.ID, nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	resp, _ = http.Get(ts.URL + "/jobs/" + st.ID)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET deleted job = %d, want 404", resp.StatusCode)
+	}
+}
+
+// TestServer_Limits は送信できるデータの上限と、終了したジョブの保持期間、保持数を確認する
+func TestServer_Limits(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewServer(ServerOptions{MaxUpload: 1024, Retention: time.Hour, MaxJobs: 2})
+	defer s.Close()
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	// 上限を超えるデータは 413
+	large := strings.Repeat("!\n", 1024)
+	resp, err := http.Post(ts.URL+"/jobs?name=room.txt", "text/plain", strings.NewReader(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("POST /jobs (text) = %d, want 413", resp.StatusCode)
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("input", "room.txt")
+	fw.Write([]byte(large))
+	mw.Close()
+	resp, err = http.Post(ts.URL+"/jobs", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("POST /jobs (multipart) = %d, want 413", resp.StatusCode)
+	}
+
+	// 計算できない入力データファイルのジョブを登録し、終了を待つ
+	get := func(url string, v any) int {
+		t.Helper()
+		resp, err := http.Get(ts.URL + url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		json.NewDecoder(resp.Body).Decode(v)
+		return resp.StatusCode
+	}
+	var ids []string
+	for i := 0; i < 3; i++ {
+		resp, err := http.Post(ts.URL+"/jobs", "text/plain", strings.NewReader("GDAT\n\tRUN ;\n*\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var st JobStatus
+		json.NewDecoder(resp.Body).Decode(&st)
+		resp.Body.Close()
+		deadline := time.Now().Add(time.Minute)
+		for st.Finished == nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("job did not finish: %+v", st)
+			}
+			time.Sleep(10 * time.Millisecond)
+			get("/jobs/"+st.ID, &st)
+		}
+		ids = append(ids, st.ID)
+	}
+
+	// 保持数を超えた古いジョブから削除する
+	var list []JobStatus
+	get("/jobs", &list)
+	if len(list) != 2 || list[0].ID != ids[1] || list[1].ID != ids[2] {
+		t.Errorf("jobs = %+v, want %v", list, ids[1:])
+	}
+	if code := get("/jobs/"+ids[0], &JobStatus{}); code != http.StatusNotFound {
+		t.Errorf("GET the oldest job = %d, want 404", code)
+	}
+
+	// 保持期間を過ぎたジョブは削除する
+	mu.Lock()
+	now = now.Add(time.Hour)
+	mu.Unlock()
+	list = nil
+	get("/jobs", &list)
+	if len(list) != 0 {
+		t.Errorf("jobs after the retention = %+v", list)
+	}
+}
+
+// TestServer_EflTraversal は EFLファイルのディレクトリの外を指す気象データファイル名のジョブが、
+// ファイルを読み込まずに入力データの誤りとなることを確認する
+func TestServer_EflTraversal(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(secret, []byte("TOPSECRET 1 2 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rel, err := filepath.Rel(eflPath, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input = []byte(strings.Replace(string(input), "w=tokyo_3column_SI.has", "w="+filepath.ToSlash(rel), 1))
+
+	s := NewServer(ServerOptions{EflPath: eflPath})
+	defer s.Close()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/jobs?name=room.txt", "text/plain", bytes.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st JobStatus
+	json.NewDecoder(resp.Body).Decode(&st)
+	resp.Body.Close()
+	deadline := time.Now().Add(time.Minute)
+	for st.Finished == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish: %+v", st)
+		}
+		time.Sleep(10 * time.Millisecond)
+		resp, err := http.Get(ts.URL + "/jobs/" + st.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st = JobStatus{}
+		json.NewDecoder(resp.Body).Decode(&st)
+		resp.Body.Close()
+	}
+	if st.State != JobFailed || strings.Contains(st.Error, "TOP") {
+		t.Errorf("job = %s %q, want failed without the file contents", st.State, st.Error)
+	}
+	s.mu.Lock()
+	j := s.jobs[st.ID]
+	s.mu.Unlock()
+	var ie *InputError
+	if !errors.As(j.err, &ie) || ie.Section != "GDAT" || ie.Keyword != "FILE" {
+		t.Errorf("err = %#v, want InputError of GDAT FILE", j.err)
+	}
+}
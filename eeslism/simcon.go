@@ -38,6 +38,8 @@ package eeslism
 
 import (
 	"io"
+	"io/fs"
+	"time"
 )
 
 const EEVERSION = "ES4.6"
@@ -99,11 +101,18 @@ type SIMCONTL struct {
 	Unitdy     string        //
 	Timeid     []rune        // 時間別計算値出力識別子 ?
 	Helmkey    rune          // 要素別熱取得、熱損失計算 'y'
-	Wdtype     rune          // 気象データファイル種別 'H':HASP標準形式　'E':VCFILE入力形式 */
+	Debug      bool          // デバッグ出力 (GDAT.PRINT *debug)
+	Wdtype     rune          // 気象データファイル種別 'H':HASP標準形式　'E':VCFILE入力形式　'P':EPW　'A':拡張アメダス　'C':CSV (GDAT.WCSV) */
 	Perio      rune          // 周期定常計算の時'y'
 	Fwdata     io.ReadSeeker // 気象データファイルのファイルポインタ
 	Fwdata2    io.ReadSeeker // 気象データファイルのファイルポインタ(なぜ2つあるのか?)
 	Ftsupw     []byte        // 給水温度データのファイル(バイナリ)
+	Station    int           // 拡張アメダスの地点番号 (GDAT FILE station=)。ref: amedas.go
+	Wcsv       *WCSV         // CSV の気象データ (GDAT.WCSV)。ref: wcsv.go
+	FS         fs.FS         // 入力データファイル等の読み込み元 ref: eefs.go
+	RefDir     string        // 入力データファイルから参照するファイルを探すディレクトリ。空の場合は File のディレクトリ
+	EflFS      fs.FS         // EFLファイル、気象データファイルの読み込み元
+	Output     OutputSink    // 出力ファイルの書き出し先
 	Daystartx  int           // 助走計算開始日
 	Daystart   int           // 本計算開始日
 	Dayend     int           // 計算終了日
@@ -120,6 +129,11 @@ type SIMCONTL struct {
 	DTm        int           // 計算時間間隔 [s] (GDAT.RUN.dTime)
 	Sttmm      int           // 計算開始時刻 (GDAT.RUN.Stime)
 	MaxIterate int           // 最大収束回数 (GDAT.RUN.MaxIterate)
+	Year       int           // 暦年 (GDAT.CALENDAR.year)。0 の場合は指定なし
+	BaseYear   int           // 実暦による計算の基準年（通日 1 の年。GDAT.RUN に年を指定）。0 の場合は 365 日の標準年による計算。ref: calendar.go
+	Calendar   []string      // GDAT.CALENDAR の論理行。実暦による計算では各年の曜日と休日の設定に用いる
+
+	runStartx, runStart, runEnd time.Time // 実暦による計算の助走計算開始日、計算開始日、計算終了日 (GDAT.RUN)
 }
 
 // 出力ファイルの設定情報
@@ -0,0 +1,186 @@
+/*
+simstate.go (Simulation State Access)
+
+Step と Step の間に計算の状態を参照・上書きするための関数を定義します。
+
+変数は CONTL データと同じ名前で指定します。
+  - 気象データ: `Ta`、`xa`、`RHa`、`ha`、`Twsup`、`Ihol`
+  - 外表面: `<外表面名>_Idre` など
+  - スケジュール: 設定値スケジュール名、切換スケジュール名
+  - 経路: `<経路名>`（発停）、`<経路名>_G`（流量）
+  - システム要素: `<要素名>_Taout`、`<室名>_Tr`、`<機器名>_control` など
+
+上書きした値は Release を呼び出すまで以降の全ての時間ステップに適用されます。
+各時間ステップでは、気象データの読み込み後、スケジュールの設定後、
+CONTL の制御の設定後にそれぞれ上書きするため、
+気象データやスケジュール、制御による値よりも上書きした値が優先されます。
+*/
+package eeslism
+
+import (
+	"errors"
+	"fmt"
+)
+
+// override は Step の間に上書きされた値を表します。
+type override struct {
+	name string
+	v    *float64       // 数値の上書き先
+	s    *ControlSWType // 切替の上書き先
+	path VPTR           // 経路の発停を上書きする場合の経路
+	val  float64
+	sw   ControlSWType
+}
+
+// lookup は CONTL データと同じ名前 name の変数のポインターを返します。
+func (sim *Simulation) lookup(name string) (vptr, vpath VPTR, err error) {
+	if !sim.initialized || sim.Schdl == nil {
+		return vptr, vpath, errors.New("eeslism: simulation is not initialized")
+	}
+
+	// 名前の解析中のパニック（添字の範囲外など）は名前の誤りとして扱う
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("eeslism: unknown variable %q", name)
+		}
+	}()
+
+	vptr, vpath, err = ctlvptr(name, sim.Simc, sim.Compnt, sim.Mpath, &sim.Wd, &sim.Exsf, sim.Schdl, sim.Ferr)
+	if err != nil || vptr.Ptr == nil {
+		return vptr, vpath, fmt.Errorf("eeslism: unknown variable %q", name)
+	}
+	return vptr, vpath, nil
+}
+
+// Value は変数 name の現在の値を返します。
+func (sim *Simulation) Value(name string) (float64, error) {
+	vptr, _, err := sim.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if vptr.Type != VAL_CTYPE {
+		return 0, fmt.Errorf("eeslism: %q is not a value", name)
+	}
+	return *vptr.Ptr.(*float64), nil
+}
+
+// SetValue は変数 name の値を v で上書きします。
+func (sim *Simulation) SetValue(name string, v float64) error {
+	vptr, _, err := sim.lookup(name)
+	if err != nil {
+		return err
+	}
+	if vptr.Type != VAL_CTYPE {
+		return fmt.Errorf("eeslism: %q is not a value", name)
+	}
+	sim.setOverride(&override{name: name, v: vptr.Ptr.(*float64), val: v})
+	return nil
+}
+
+// Switch は経路やシステム要素の発停、切換スケジュールなど切替変数 name の現在の値を返します。
+func (sim *Simulation) Switch(name string) (ControlSWType, error) {
+	vptr, _, err := sim.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if vptr.Type != SW_CTYPE {
+		return 0, fmt.Errorf("eeslism: %q is not a switch", name)
+	}
+	return *vptr.Ptr.(*ControlSWType), nil
+}
+
+// SetSwitch は切替変数 name の値を sw で上書きします。
+// 経路の発停を上書きした場合は、CONTL と同様に経路上のシステム要素の発停も設定されます。
+func (sim *Simulation) SetSwitch(name string, sw ControlSWType) error {
+	vptr, vpath, err := sim.lookup(name)
+	if err != nil {
+		return err
+	}
+	if vptr.Type != SW_CTYPE {
+		return fmt.Errorf("eeslism: %q is not a switch", name)
+	}
+	sim.setOverride(&override{name: name, s: vptr.Ptr.(*ControlSWType), path: vpath, sw: sw})
+	return nil
+}
+
+// Setpoint は CONTL の LOAD で指定した負荷計算の設定値 name の現在の値を返します。
+func (sim *Simulation) Setpoint(name string) (float64, error) {
+	Cst, err := sim.ldptr(name)
+	if err != nil {
+		return 0, err
+	}
+	return *Cst.Lft.V, nil
+}
+
+// SetSetpoint は CONTL の LOAD で指定した負荷計算の設定値 name を v で上書きします。
+// name は LOAD の左辺と同じ名前（例: 室温の設定値は `<室名>_Tr`）で指定します。
+func (sim *Simulation) SetSetpoint(name string, v float64) error {
+	Cst, err := sim.ldptr(name)
+	if err != nil {
+		return err
+	}
+	sim.setOverride(&override{name: name, v: Cst.Lft.V, val: v})
+	return nil
+}
+
+// ldptr は LOAD で name を設定する CONTL の設定を返します。
+func (sim *Simulation) ldptr(name string) (*CTLST, error) {
+	if !sim.initialized {
+		return nil, errors.New("eeslism: simulation is not initialized")
+	}
+	for _, Contl := range sim.Contl {
+		if Cst := Contl.Cst; Cst != nil && Cst.Ldname == name && Cst.Type == VAL_CTYPE {
+			return Cst, nil
+		}
+	}
+	return nil, fmt.Errorf("eeslism: no LOAD setpoint %q in CONTL", name)
+}
+
+// Release は name の上書きを解除します。以降は気象データやスケジュール、制御による値に戻ります。
+func (sim *Simulation) Release(name string) {
+	n := 0
+	for _, o := range sim.overrides {
+		if o.name != name {
+			sim.overrides[n] = o
+			n++
+		}
+	}
+	sim.overrides = sim.overrides[:n]
+}
+
+// setOverride は上書きを登録し、直ちに適用します。同じ上書き先の上書きは置き換えます。
+func (sim *Simulation) setOverride(o *override) {
+	for i, p := range sim.overrides {
+		if p.v == o.v && p.s == o.s {
+			sim.overrides[i] = o
+			o.apply()
+			return
+		}
+	}
+	sim.overrides = append(sim.overrides, o)
+	o.apply()
+}
+
+// applyOverrides は Step の間に上書きされた値を設定します。
+func (sim *Simulation) applyOverrides() {
+	for _, o := range sim.overrides {
+		o.apply()
+	}
+}
+
+func (o *override) apply() {
+	if o.v != nil {
+		*o.v = o.val
+		return
+	}
+
+	*o.s = o.sw
+	switch o.path.Type {
+	case MAIN_CPTYPE:
+		Mp := o.path.Ptr.(*MPATH)
+		Mp.Control = o.sw
+		Mp.mpathschd(o.sw)
+	case LOCAL_CPTYPE:
+		o.path.Ptr.(*PLIST).lpathscdd(o.sw)
+	}
+}
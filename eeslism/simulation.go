@@ -0,0 +1,251 @@
+package eeslism
+
+import (
+	"io"
+	"io/fs"
+)
+
+/*
+Simulation (Simulation Run)
+
+1つの入力データファイルに対するシミュレーション実行の状態を保持します。
+
+C版のEESLISMでは、計算時間間隔（dTM）やログファイル（ferr）などをグローバル変数で、
+出力ファイルのヘッダ出力判定や日集計の前回日付などを関数内のstatic変数で保持していました。
+Go版でもこれをパッケージ変数として移植していましたが、
+そのままでは同一プロセス内で複数のモデルを計算することができません。
+
+Simulation はこれらの実行時状態を全て所有します。
+異なる Simulation は状態を共有しないため、
+パラメトリックスタディなどで複数のモデルを同一プロセス内で並行して計算できます。
+
+C版のstatic変数に由来するフィールドは、由来が分かるように
+`__関数名_変数名` の名前をそのまま用いています。
+
+デバッグ出力（GDAT PRINT の `*debug`）のON/OFFも `SIMCONTL.Debug` として計算ごとに保持します。
+*/
+type Simulation struct {
+	InFile  string // 入力データファイル名
+	EflPath string // EFLファイルのディレクトリ。空の場合は埋め込みの Base を用いる
+
+	// 入出力先。nil の場合はファイルシステムを用いる。Run または Init の前に設定します。ref: eefs.go
+	FS     fs.FS      // 入力データファイル等の読み込み元。InFile はこの FS 内のパス
+	EflFS  fs.FS      // EFLファイル、気象データファイルの読み込み元。EflPath より優先する
+	Output OutputSink // 出力ファイルの書き出し先
+
+	// 入力データファイルから参照するファイル（SCHNM の csv=、GDAT WCSV の file=）を探すディレクトリ。
+	// 空の場合は InFile のディレクトリ。入力データファイルを別のディレクトリにコピーして計算する場合に、
+	// 元のディレクトリを指定します。
+	RefDir string
+
+	Defines map[string]string // 入力データファイルの `$define` より優先するパラメータ。ref: eepreproc.go
+
+	// 文字コード（"auto"、"utf-8"、"shift_jis"）。ref: eeencoding.go
+	Encoding       string // 入力データファイル、EFLファイル等の文字コード。空の場合は "auto"
+	OutputEncoding string // 出力ファイルの文字コード。空の場合は "utf-8"
+
+	Ferr    io.Writer // ログファイル（GDAT PRINT *log 指定時のみ。未指定時は nil）
+	DTM     float64   // 計算時間間隔 [s]
+	Cff_kWh float64   // [W]を計算時間間隔で積算した値を[kWh]に換算する係数
+	dayprn  bool      // 当日が詳細出力日かどうか
+	Fbmlist string    // 壁材料リストファイル名 ref: wbmlist.md
+
+	rand *glibcRand // モンテカルロ法の乱数生成器
+
+	// 計算の状態。Init で設定され、Step の間に参照できます。
+	Daytm  DAYTM     // 計算中の日付・時刻
+	Simc   *SIMCONTL // シミュレーション設定
+	Loc    *LOCAT    // 地域情報
+	Wd     WDAT      // 気象データ
+	Exsf   EXSFS     // 外表面
+	Rmvls  *RMVLS    // 室、壁体
+	Eqcat  *EQCAT    // 機器カタログ
+	Eqsys  *EQSYS    // システム使用機器
+	Schdl  *SCHDL    // スケジュール
+	Compnt []*COMPNT // システム要素
+	Mpath  []*MPATH  // システム経路
+	Contl  []*CONTL  // 制御
+
+	elout       []*ELOUT
+	elin        []*ELIN
+	syseq       SYSEQ
+	plist       []*PLIST
+	pelm        []*PELM
+	ctlif       []*CTLIF
+	ctlst       []*CTLST
+	flout       []*FLOUT
+	wdd, wdm    WDAT      // 気象データの日集計、月集計
+	soldy       []float64 // 日射量の日集計
+	solmon      []float64 // 日射量の月集計
+	loopMax     int       // 最大収束回数
+	vavCountMax int       // VAV計算の最大繰り返し回数
+
+	// 日ループ、時刻ループ
+	nday    int // 計算日（助走期間を含む通日）
+	day     int // 通日
+	mt      int // 当日の時間ステップ番号
+	mta     int // 当日の最初の時間ステップ番号
+	mtb     int // 1日の時間ステップ数
+	tt      int // 時
+	mm      int // 分
+	dminute int // 計算時間間隔 [min]
+
+	// 日影・形態係数計算 (higuchi add)
+	bdp                  []*BBDP
+	op, lp, mp           []*P_MENN // OP面(受光面)、LP面(被受光面)、MP面(OP+OPW)
+	opn, lpn, mpn        int
+	monten               int     // モンテカルロ法の際の射出数
+	de                   float64 // 壁面の分割による微小四角形の辺の長さ
+	wap                  []float64
+	wip                  [][]float64
+	gp                   [][]XYZ        // 地面の代表点の座標
+	gpn                  int            // 地面の代表点の数
+	uop, ulp, ullp, ulmp []*bekt        // opから見たop、opから見たlp、lpから見たlp、lpから見たmpの位置
+	fp1                  io.WriteCloser // _shadow.gchi : MPの影面積の出力
+	fp2                  io.WriteCloser // _I.gchi : MPの日射量の出力
+	fp3                  io.WriteCloser // _lwr.gchi : MPの長波長放射量の出力
+	fp4                  io.WriteCloser // _ffactor.gchi : MPの形態係数の出力
+	sdstr                []*SHADSTR
+	datintvl             int // 影計算の間隔
+	dcnt                 int
+
+	initialized bool  // Init 済み
+	finalized   bool  // Finalize 済み
+	closed      bool  // 出力ファイルを閉じた
+	err         error // Init、Step で発生したエラー
+
+	overrides []*override // Step の間に上書きされた値 ref: simstate.go
+	observers []Observer  // 計算結果を受け取るオブザーバー ref: observer.go
+
+	// ProgressFunc は各時間ステップの計算の後に進捗状況を受け取る関数です。ref: progress.go
+	// 設定した場合は、日付の標準出力への表示は行いません。
+	ProgressFunc func(p Progress)
+
+	// GeometryShadow が true の場合は、詳細出力日の毎時の日影面積率を記録し、
+	// WriteGLTF で書き出す glTF に時刻ごとのシーンとして含めます。ref: gltf.go
+	GeometryShadow bool
+	shadowFrames   []shadowFrame
+
+	nstep, nsteps       int // 計算済みの時間ステップ数、全時間ステップ数
+	loopCount, vavCount int // 直前の時間ステップの収束計算、VAV 計算の繰り返し回数
+
+	// spline.go
+	__Intgtsup_ic int
+
+	// blroomday.go
+	__Roomday_oldday int
+	__Roomday_oldMon int
+	__Rmdyprint_id   int
+	__Rmmonprint_id  int
+
+	// wdprint.go
+	__Wdtsum_oldday int
+	__Wdtsum_oldMon int
+	__Wdtsum_hrs    int // 日累計回数
+	__Wdtsum_hrsm   int // 月累計回数
+	__Wdtsum_cffWh  float64
+
+	// blrmprint.go
+	__Rmsfprint_ic  int
+	__Rmsfqprint_ic int
+	__Rmsfaprint_ic int
+	__Dysfprint_ic  int
+	__Shdprint_ic   int
+	__Wallprint_ic  int
+	__PCMprint_ic   int
+	__Qrmprint_ic   int
+	__Dyqrmprint_ic int
+	__Qrmsum_oldday int
+
+	// eeprint_s.go
+	__Eeprintd_ic  int
+	__Wdtdprint_ic int
+	__Wdtprint_ic  int
+	__Wdtmprint_ic int
+
+	// blrmaceqcf.go
+	__Rmhtrcf_count int
+
+	// eecmpday_s.go
+	__Compoday_OldDay int
+	__Compoday_OldMon int
+	__Compodyprt_id   int
+	__Compomonprt_id  int
+	__Compomtprt_id   int
+
+	// blrmqprt.go
+	__Rmpnlprint_id int
+
+	// eevcdat.go
+	__Vcfinput_Mon  int
+	__Vcfinput_Day  int
+	__Vcfinput_Time int
+
+	// eepthprt.go
+	__Pathprint_id int
+
+	// eecmpprt_s.go
+	__Hcmpprint_id int
+	__Hstkprint_id int
+
+	// bltcomfrt.go
+	__Rmotset_Pint int
+	__Fotinit_init int
+
+	// blhelm.go
+	__Helmprint_id     int
+	__Helmsurfprint_id int
+	__Helmdy_oldday    int
+	__Helmdyprint_id   int
+
+	// wdread.go
+	__Weatherdt_ptt    int
+	__Weatherdt_nc     int
+	__Weatherdt_decl   float64
+	__Weatherdt_E      float64
+	__Weatherdt_tas    float64
+	__Weatherdt_timedg float64
+	__Weatherdt_dt     [7][25]float64
+	__Weatherdt_dtL    [7][25]float64
+	__gtsupw_ic        int
+	__hspwdread_ic     int
+	__hspwdread_recl   int
+	__epwwdread_epw    *EPW
+	__amdwdread_amd    *AMEDAS
+
+	// blsrprint.go
+	__Pmvprint_count  int
+	__Rmevprint_count int
+}
+
+// NewSimulation は入力データファイル InFile と EFLディレクトリ efl_path に対する
+// シミュレーションを作成します。static変数由来のフィールドはC版の初期値で初期化されます。
+func NewSimulation(InFile string, efl_path string) *Simulation {
+	return &Simulation{
+		InFile:  InFile,
+		EflPath: efl_path,
+
+		rand: newGlibcRand(),
+
+		__Roomday_oldday: INAN,
+		__Roomday_oldMon: INAN,
+		__Fotinit_init:   'i',
+		__Helmdy_oldday:  -1,
+		__Weatherdt_ptt:  25,
+	}
+}
+
+// dlog は当日が詳細出力日でログファイルが開かれている場合にログファイルを返します。
+// それ以外の場合は nil を返します。
+func (sim *Simulation) dlog() io.Writer {
+	if sim.dayprn {
+		return sim.Ferr
+	}
+	return nil
+}
+
+// debug はデバッグ出力（GDAT PRINT の *debug）を行う場合に true を返します。
+// 入力データの読み込み前は false です。
+func (sim *Simulation) debug() bool {
+	return sim.Simc != nil && sim.Simc.Debug
+}
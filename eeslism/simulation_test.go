@@ -0,0 +1,301 @@
+package eeslism
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// copySimulationInput は入力データファイルを dir にコピーし、コピー先のパスを返す
+func copySimulationInput(t *testing.T, src, dir string) string {
+	t.Helper()
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", src, err)
+	}
+	dst := filepath.Join(dir, filepath.Base(src))
+	if err := os.WriteFile(dst, b, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", dst, err)
+	}
+	return dst
+}
+
+// readSimulationOutputs は dir 内の .es ファイルを読み込む。
+// ヘッダに含まれる入力ファイルのパス（区切り文字は `\`）は dir に依存しないよう置き換える。
+func readSimulationOutputs(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.es"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]string, len(files))
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[filepath.Base(f)] = strings.ReplaceAll(string(b), strings.ReplaceAll(dir, "/", "\\"), "<dir>")
+	}
+	return out
+}
+
+// TestSimulation_Concurrent は同一プロセス内で並行に実行した Simulation が
+// 単独で実行した場合と同じ結果を出力することを確認する
+func TestSimulation_Concurrent(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_full/simple_room_full_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 単独実行
+	refDir := t.TempDir()
+	NewSimulation(copySimulationInput(t, src, refDir), eflPath).Run()
+	ref := readSimulationOutputs(t, refDir)
+	if len(ref) == 0 {
+		t.Fatal("No output files generated")
+	}
+
+	// 並行実行
+	const n = 3
+	dirs := make([]string, n)
+	sims := make([]*Simulation, n)
+	for i := range dirs {
+		dirs[i] = t.TempDir()
+		sims[i] = NewSimulation(copySimulationInput(t, src, dirs[i]), eflPath)
+	}
+
+	var wg sync.WaitGroup
+	for _, sim := range sims {
+		wg.Add(1)
+		go func(sim *Simulation) {
+			defer wg.Done()
+			sim.Run()
+		}(sim)
+	}
+	wg.Wait()
+
+	for i, dir := range dirs {
+		got := readSimulationOutputs(t, dir)
+		if len(got) != len(ref) {
+			t.Errorf("run %d: %d output files, want %d", i, len(got), len(ref))
+		}
+		for name, want := range ref {
+			if got[name] != want {
+				t.Errorf("run %d: %s differs from sequential run", i, name)
+			}
+		}
+	}
+}
+
+// TestSimulation_Errors は入力データの誤りでプロセスを終了せず、型付きのエラーを返すことを確認する
+func TestSimulation_Errors(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_full/simple_room_full_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("missing input file", func(t *testing.T) {
+		err := Entry(filepath.Join(t.TempDir(), "nonexistent.txt"), eflPath)
+		var ie *InputError
+		if !errors.As(err, &ie) {
+			t.Fatalf("err = %v, want *InputError", err)
+		}
+		if ie.ExitCode() != 1 {
+			t.Errorf("ExitCode() = %d, want 1", ie.ExitCode())
+		}
+	})
+
+	t.Run("missing weather file", func(t *testing.T) {
+		dir := t.TempDir()
+		b, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = []byte(strings.Replace(string(b), "w=tokyo_3column_SI.has", "w=nonexistent.has", 1))
+		input := filepath.Join(dir, "input.txt")
+		if err := os.WriteFile(input, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		err = Entry(input, eflPath)
+		var we *WeatherError
+		if !errors.As(err, &we) {
+			t.Fatalf("err = %v, want *WeatherError", err)
+		}
+		if we.ExitCode() != EXIT_WFILE {
+			t.Errorf("ExitCode() = %d, want %d", we.ExitCode(), EXIT_WFILE)
+		}
+	})
+}
+
+// TestSimulation_OutputOnError は計算の途中でエラーとなった場合に、
+// エラーまでの計算結果が出力ファイルに書き出されることを確認する
+func TestSimulation_OutputOnError(t *testing.T) {
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1/1 の気象データのみで 1/2 まで計算し、1/2 1:00 でエラーとする
+	dir := t.TempDir()
+	var b strings.Builder
+	b.WriteString("Time,Temp,x,DNI,DHI,Cloud,Wind,Dir\n")
+	for tt := 1; tt <= 24; tt++ {
+		fmt.Fprintf(&b, "2023/1/1 %d:00,5,0.003,0,0,5,2,4\n", tt)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "site.csv"), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "input.txt")
+	in := `GDAT
+	WCSV file=site.csv time=Time Lat=35.7 Lon=139.8 Ls=135
+		T=Temp x=x Idn=DNI Isky=DHI CC=Cloud Wv=Wind Wdre=Dir:16 ;
+	RUN (1/1) 1/1-1/2 ;
+	PRINT *wd ;
+*
+`
+	if err := os.WriteFile(input, []byte(in), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewSimulation(input, eflPath).Run()
+	var we *WeatherError
+	if !errors.As(err, &we) {
+		t.Fatalf("err = %v, want *WeatherError", err)
+	}
+	out, err := os.ReadFile(filepath.Join(dir, "input_dwd.es"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(out); !strings.Contains(s, "\n1\t1\t5.0\t") || !strings.HasSuffix(s, "-999\n") {
+		t.Errorf("input_dwd.es:\n%s", s)
+	}
+}
+
+// TestSimulation_Step は Init/Step/Finalize による実行が Run と同じ結果を出力することを確認する
+func TestSimulation_Step(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refDir := t.TempDir()
+	if err := NewSimulation(copySimulationInput(t, src, refDir), eflPath).Run(); err != nil {
+		t.Fatal(err)
+	}
+	ref := readSimulationOutputs(t, refDir)
+	if len(ref) == 0 {
+		t.Fatal("No output files generated")
+	}
+
+	dir := t.TempDir()
+	sim := NewSimulation(copySimulationInput(t, src, dir), eflPath)
+	if err := sim.Step(); err == nil {
+		t.Error("Step before Init: expected error")
+	}
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for !sim.Done() {
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 7*24 {
+		t.Errorf("%d steps, want %d", n, 7*24)
+	}
+	if err := sim.Step(); err == nil {
+		t.Error("Step after the end of the period: expected error")
+	}
+	if err := sim.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readSimulationOutputs(t, dir)
+	for name, want := range ref {
+		if got[name] != want {
+			t.Errorf("%s differs from Run", name)
+		}
+	}
+}
+
+// TestSimulation_Override は Step の間に上書きした値が以降の時間ステップに適用されることを確認する
+func TestSimulation_Override(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSim := func() *Simulation {
+		sim := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+		if err := sim.Init(); err != nil {
+			t.Fatal(err)
+		}
+		return sim
+	}
+
+	ref, sim := newSim(), newSim()
+	if err := sim.SetValue("Ta", 30.0); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetSwitch("HeatPath", ON_SW); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SetSetpoint("Boiler1_Tout", 60.0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := ref.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if Ta, _ := sim.Value("Ta"); Ta != 30.0 {
+		t.Errorf("Ta = %g, want 30", Ta)
+	}
+	if sw, _ := sim.Switch("HeatPath"); sw != ON_SW {
+		t.Errorf("HeatPath = %c, want %c", sw, ON_SW)
+	}
+	if Tout, _ := sim.Value("Boiler1_Twout"); Tout != 60.0 {
+		t.Errorf("Boiler1_Twout = %g, want 60", Tout)
+	}
+	Tr, _ := sim.Value("TestRoom_Tr")
+	refTr, _ := ref.Value("TestRoom_Tr")
+	if Tr <= refTr {
+		t.Errorf("TestRoom_Tr = %g, want higher than %g", Tr, refTr)
+	}
+
+	// 上書きを解除すると気象データの値に戻る
+	sim.Release("Ta")
+	if err := ref.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.Step(); err != nil {
+		t.Fatal(err)
+	}
+	Ta, _ := sim.Value("Ta")
+	refTa, _ := ref.Value("Ta")
+	if Ta != refTa {
+		t.Errorf("Ta after Release = %g, want %g", Ta, refTa)
+	}
+
+	if err := sim.SetValue("NoSuchVariable", 0); err == nil {
+		t.Error("SetValue of unknown variable: expected error")
+	}
+	if err := sim.SetSetpoint("TestRoom_Tr", 20); err == nil {
+		t.Error("SetSetpoint without LOAD: expected error")
+	}
+}
@@ -1,7 +1,5 @@
 package eeslism
 
-var __Intgtsup_ic int = 0
-
 /*
 Intgtsup (Interpolate Ground Temperature for Supply Water)
 
@@ -29,13 +27,13 @@ Intgtsup (Interpolate Ground Temperature for Supply Water)
 給水温度の季節変動を正確にモデル化し、
 給湯負荷計算や、地中熱利用システムの性能評価を行うための重要な役割を果たします。
 */
-func Intgtsup(Nday int, Tsupw []float64) float64 {
+func (sim *Simulation) Intgtsup(Nday int, Tsupw []float64) float64 {
 	var h, b, d, g, u [13]float64
 	var r, x, y [14]float64
 	var n, Mo int
 	var y1 float64
 
-	if __Intgtsup_ic == 0 {
+	if sim.__Intgtsup_ic == 0 {
 		n = 13
 		x[0], x[13] = -15.0, 380.0
 		for Mo = 1; Mo <= 12; Mo++ {
@@ -44,7 +42,7 @@ func Intgtsup(Nday int, Tsupw []float64) float64 {
 			y[0], y[13] = Tsupw[11], Tsupw[0]
 		}
 
-		__Intgtsup_ic = 1
+		sim.__Intgtsup_ic = 1
 	}
 
 	y1 = spline(n, x[:], y[:], float64(Nday), h[:], b[:], d[:], g[:], u[:], r[:])
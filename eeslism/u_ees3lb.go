@@ -2,8 +2,7 @@ package eeslism
 
 import (
 	"fmt"
-
-	"os"
+	"io"
 	"unicode"
 )
 
@@ -20,16 +19,14 @@ func isstrdigit(s string) bool {
 }
 
 /* 入力データエラーの出力 */
-func Errprint(err int, key string, s string) {
+// 標準出力と、ログファイル Ferr（GDAT PRINT *log 指定時。nil の場合は出力しない）に出力します。
+func Errprint(err int, key string, s string, Ferr io.Writer) {
 	if err != 0 {
-		fmt.Printf(ERRFMTA, key, s)
-		if Ferr != nil {
-			fmt.Fprintf(Ferr, ERRFMTA, key, s)
-		}
+		Eprint(key, s, Ferr)
 	}
 }
 
-func Eprint(key string, s string) {
+func Eprint(key string, s string, Ferr io.Writer) {
 	fmt.Printf(ERRFMTA, key, s)
 	if Ferr != nil {
 		fmt.Fprintf(Ferr, ERRFMTA, key, s)
@@ -37,20 +34,9 @@ func Eprint(key string, s string) {
 }
 
 /* データの記憶域確保時のエラー出力 */
-func Ercalloc(n int, errkey string) {
+func Ercalloc(n int, errkey string, Ferr io.Writer) {
 	s := fmt.Sprintf(" -- calloc   n=%d", n)
-	Eprint(errkey, s)
-}
-
-func Preexit() {
-	var NSTOP int
-	fmt.Printf("Press Hit Return Key .......\n")
-	if NSTOP == 0 {
-		var buf [1]byte
-		os.Stdin.Read(buf[:])
-	} else {
-		os.Exit(1)
-	}
+	Eprint(errkey, s, Ferr)
 }
 
 func Lineardiv(A, B, dt float64) float64 {
@@ -2,6 +2,7 @@ package eeslism
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -99,20 +100,14 @@ func TestIsstrdigit(t *testing.T) {
 }
 
 func TestErrprint(t *testing.T) {
-	// Store original Ferr
-	origFerr := Ferr
-	defer func() { Ferr = origFerr }()
-
 	// Capture stdout
 	oldStdout := os.Stdout
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	// Set Ferr to nil for testing (since it's *os.File)
-	Ferr = nil
-
 	// Test with error condition
-	Errprint(1, "TEST_KEY", "test error message")
+	var log strings.Builder
+	Errprint(1, "TEST_KEY", "test error message", &log)
 
 	// Restore stdout and read captured output
 	w.Close()
@@ -128,24 +123,20 @@ func TestErrprint(t *testing.T) {
 		t.Errorf("Errprint stdout = %q, want %q", stdoutStr, expectedStdout)
 	}
 
-	// Note: Ferr is set to nil, so no file output to check
+	// Check log file output
+	if log.String() != expectedStdout {
+		t.Errorf("Errprint log = %q, want %q", log.String(), expectedStdout)
+	}
 }
 
 func TestErrprint_NoError(t *testing.T) {
-	// Store original Ferr
-	origFerr := Ferr
-	defer func() { Ferr = origFerr }()
-
 	// Capture stdout
 	oldStdout := os.Stdout
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	// Set Ferr to nil for testing (since it's *os.File)
-	Ferr = nil
-
 	// Test with no error condition
-	Errprint(0, "TEST_KEY", "test error message")
+	Errprint(0, "TEST_KEY", "test error message", nil)
 
 	// Restore stdout and read captured output
 	w.Close()
@@ -159,25 +150,17 @@ func TestErrprint_NoError(t *testing.T) {
 	if stdoutStr != "" {
 		t.Errorf("Errprint with err=0 should produce no output, got %q", stdoutStr)
 	}
-
-	// Note: Ferr is set to nil, so no file output to check
 }
 
 func TestEprint(t *testing.T) {
-	// Store original Ferr
-	origFerr := Ferr
-	defer func() { Ferr = origFerr }()
-
 	// Capture stdout
 	oldStdout := os.Stdout
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	// Set Ferr to nil for testing (since it's *os.File)
-	Ferr = nil
-
 	// Test Eprint
-	Eprint("TEST_KEY", "test error message")
+	var log strings.Builder
+	Eprint("TEST_KEY", "test error message", &log)
 
 	// Restore stdout and read captured output
 	w.Close()
@@ -193,24 +176,20 @@ func TestEprint(t *testing.T) {
 		t.Errorf("Eprint stdout = %q, want %q", stdoutStr, expectedOutput)
 	}
 
-	// Note: Ferr is set to nil, so no file output to check
+	// Check log file output
+	if log.String() != expectedOutput {
+		t.Errorf("Eprint log = %q, want %q", log.String(), expectedOutput)
+	}
 }
 
 func TestErcalloc(t *testing.T) {
-	// Store original Ferr
-	origFerr := Ferr
-	defer func() { Ferr = origFerr }()
-
 	// Capture stdout
 	oldStdout := os.Stdout
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	// Set Ferr to nil for testing (since it's *os.File)
-	Ferr = nil
-
 	// Test Ercalloc
-	Ercalloc(100, "MEMORY_ERROR")
+	Ercalloc(100, "MEMORY_ERROR", nil)
 
 	// Restore stdout and read captured output
 	w.Close()
@@ -225,8 +204,6 @@ func TestErcalloc(t *testing.T) {
 	if stdoutStr != expectedOutput {
 		t.Errorf("Ercalloc stdout = %q, want %q", stdoutStr, expectedOutput)
 	}
-
-	// Note: Ferr is set to nil, so no file output to check
 }
 
 func TestLineardiv(t *testing.T) {
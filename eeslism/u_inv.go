@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"math"
-	"os"
 )
 
 ///* ------------------------------------------------
@@ -15,7 +14,7 @@ import (
 
 /* ========= ガウスジョルダン法の関数====================== */
 
-func Matinv(a []float64, n, m int, s string) {
+func Matinv(a []float64, n, m int, s string, Ferr io.Writer) {
 	row := make([]int, m)
 	mattemp := make([]float64, m*m)
 
@@ -39,9 +38,8 @@ func Matinv(a []float64, n, m int, s string) {
 				E = fmt.Sprintf("対角要素に０があります  matrix=%dx%d  i=%d", m, m, ipv)
 			}
 			Matprint("%.2g  ", m, mattemp)
-			Eprint("<matinv>", E)
-			Preexit()
-			os.Exit(EXIT_MATINV)
+			Eprint("<matinv>", E, Ferr)
+			panic(&ConvergenceError{Keyword: "Matinv", Component: s, Msg: E, Code: EXIT_MATINV})
 		}
 		row[ipv] = pivot_row
 
@@ -157,8 +155,7 @@ func Gausei(A, C []float64, m, n int, B []float64) {
 			}
 
 			fmt.Println("収束せず")
-			Preexit()
-			os.Exit(EXIT_MATINV)
+			panic(&ConvergenceError{Keyword: "Gausei", Msg: "not converged", Code: EXIT_MATINV})
 		}
 
 		k++
@@ -180,7 +177,7 @@ func Gausei(A, C []float64, m, n int, B []float64) {
 //		 参考文献：C言語による科学技術計算サブルーチンライブラリ
 //		 pp.104-106
 //	  ----------------------------------------------------- */
-func Gauss(A, C, B []float64, m, n int) {
+func Gauss(A, C, B []float64, m, n int, Ferr io.Writer) {
 	num := make([]int, m)
 	pivot := make([]int, m)
 	wfs := make([]float64, m*m)
@@ -209,9 +206,8 @@ func Gauss(A, C, B []float64, m, n int) {
 		}
 
 		if pv == 0.0 {
-			Eprint("<gauss>", "対角要素に 0 があります")
-			Preexit()
-			os.Exit(EXIT_MATINV)
+			Eprint("<gauss>", "対角要素に 0 があります", Ferr)
+			panic(&ConvergenceError{Keyword: "Gauss", Msg: "zero diagonal element", Code: EXIT_MATINV})
 		}
 
 		for j := k; j < m; j++ {
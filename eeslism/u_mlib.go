@@ -1,6 +1,7 @@
 package eeslism
 
 import (
+	"io"
 	"fmt"
 )
 
@@ -10,11 +11,11 @@ var mapCx = map[FliudType]float64{
 }
 
 // 水、空気の比熱
-func Spcheat(fluid FliudType) float64 {
+func Spcheat(fluid FliudType, Ferr io.Writer) float64 {
 	C, ok := mapCx[fluid]
 	if !ok {
 		s := fmt.Sprintf("xxx fluid='%c'", fluid)
-		Eprint("<spcheat>", s)
+		Eprint("<spcheat>", s, Ferr)
 		return -9999.0
 	}
 	return C
@@ -24,7 +24,7 @@ func TestSpcheat(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := Spcheat(tt.fluid)
+			result := Spcheat(tt.fluid, nil)
 			if result != tt.expected {
 				t.Errorf("Spcheat(%c) = %v, want %v", tt.fluid, result, tt.expected)
 			}
@@ -36,7 +36,7 @@ func TestSpcheat_InvalidFluid(t *testing.T) {
 	// Test with invalid fluid type
 	invalidFluid := FliudType('Z') // Invalid fluid type
 	
-	result := Spcheat(invalidFluid)
+	result := Spcheat(invalidFluid, nil)
 	
 	// Should return error value
 	expected := -9999.0
@@ -54,7 +54,7 @@ func TestSpcheat_AllValidFluids(t *testing.T) {
 
 	for fluid, expectedValue := range validFluids {
 		t.Run(string(fluid), func(t *testing.T) {
-			result := Spcheat(fluid)
+			result := Spcheat(fluid, nil)
 			if result != expectedValue {
 				t.Errorf("Spcheat(%c) = %v, want %v", fluid, result, expectedValue)
 			}
@@ -71,7 +71,7 @@ func TestSpcheat_PhysicalConstraints(t *testing.T) {
 	// Test that returned values are within reasonable physical ranges
 	
 	t.Run("water specific heat range", func(t *testing.T) {
-		result := Spcheat(WATER_FLD)
+		result := Spcheat(WATER_FLD, nil)
 		// Water specific heat should be around 4186 J/(kg·K) at room temperature
 		if result < 4000 || result > 5000 {
 			t.Errorf("Water specific heat %v J/(kg·K) is outside reasonable range [4000, 5000]", result)
@@ -79,7 +79,7 @@ func TestSpcheat_PhysicalConstraints(t *testing.T) {
 	})
 
 	t.Run("air specific heat range", func(t *testing.T) {
-		result := Spcheat(AIRa_FLD)
+		result := Spcheat(AIRa_FLD, nil)
 		// Air specific heat should be around 1005 J/(kg·K) at room temperature
 		if result < 900 || result > 1100 {
 			t.Errorf("Air specific heat %v J/(kg·K) is outside reasonable range [900, 1100]", result)
@@ -91,14 +91,14 @@ func TestSpcheat_ConsistencyWithConstants(t *testing.T) {
 	// Test that the function returns values consistent with global constants
 	
 	t.Run("consistency with Ca constant", func(t *testing.T) {
-		result := Spcheat(AIRa_FLD)
+		result := Spcheat(AIRa_FLD, nil)
 		if result != Ca {
 			t.Errorf("Spcheat(AIRa_FLD) = %v, should equal Ca constant = %v", result, Ca)
 		}
 	})
 
 	t.Run("consistency with Cw constant", func(t *testing.T) {
-		result := Spcheat(WATER_FLD)
+		result := Spcheat(WATER_FLD, nil)
 		if result != Cw {
 			t.Errorf("Spcheat(WATER_FLD) = %v, should equal Cw constant = %v", result, Cw)
 		}
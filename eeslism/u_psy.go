@@ -64,6 +64,11 @@ func Psyint() {
 	}
 }
 
+// 物理定数は単位系のみで決まり、実行ごとに変わらないため、パッケージ初期化時に設定します。
+func init() {
+	Psyint()
+}
+
 /*
 Poset (Set Atmospheric Pressure)
 
@@ -41,15 +41,15 @@ Sunint (Sun Initialization)
 パッシブソーラー設計や日射遮蔽計画、
 そして再生可能エネルギーシステムの導入検討を行うための基礎的な役割を果たします。
 */
-func Sunint() {
+func (Loc *LOCAT) Sunint() {
 	var Rd float64 = math.Pi / 180.0
-	Slat = mathSin(Lat * Rd)
-	Clat = mathCos(Lat * Rd)
-	Tlat = mathTan(Lat * Rd)
+	Loc.Slat = mathSin(Loc.Lat * Rd)
+	Loc.Clat = mathCos(Loc.Lat * Rd)
+	Loc.Tlat = mathTan(Loc.Lat * Rd)
 	if UNIT == "SI" {
-		Isc = 1370.0
+		Loc.Isc = 1370.0
 	} else {
-		Isc = 1178.0
+		Loc.Isc = 1178.0
 	}
 }
 
@@ -144,8 +144,8 @@ FNSro (Function for Extraterrestrial Solar Radiation)
 パッシブソーラー設計、日射遮蔽計画、
 そして太陽光発電システムの発電量予測を行うための基礎的な役割を果たします。
 */
-func FNSro(N int) float64 {
-	return Isc * (1.0 + 0.033*mathCos(2.0*math.Pi*float64(N)/365.0))
+func (Loc *LOCAT) FNSro(N int) float64 {
+	return Loc.Isc * (1.0 + 0.033*mathCos(2.0*math.Pi*float64(N)/365.0))
 }
 
 /*
@@ -231,9 +231,9 @@ FNTtd (Function for Day Length)
 パッシブソーラー設計、日射遮蔽計画、
 そして日影計算を行うための基礎的な役割を果たします。
 */
-func FNTtd(Decl float64) float64 {
+func (Loc *LOCAT) FNTtd(Decl float64) float64 {
 	var Cws, Ttd float64
-	Cws = -Tlat * mathTan(Decl)
+	Cws = -Loc.Tlat * mathTan(Decl)
 	if 1.0 > Cws && Cws > -1.0 {
 		Ttd = 7.6394 * mathAcos(Cws)
 	} else {
@@ -246,9 +246,6 @@ func FNTtd(Decl float64) float64 {
 	return Ttd
 }
 
-var __Solpos_Sdecl, __Solpos_Sld, __Solpos_Cld float64
-var __Solpos_Ttprev float64 = 25.0
-
 /*
 Solpos (Solar Position Calculation)
 
@@ -280,23 +277,23 @@ Solpos (Solar Position Calculation)
 パッシブソーラー設計、日射遮蔽計画、
 昼光利用、そして太陽光発電システムの発電量予測を行うための基礎的な役割を果たします。
 */
-func Solpos(Ttas float64, Decl float64) (Sh float64, Sw float64, Ss float64, solh float64, solA float64) {
+func (Loc *LOCAT) Solpos(Ttas float64, Decl float64) (Sh float64, Sw float64, Ss float64, solh float64, solA float64) {
 	const PI float64 = math.Pi
 	var Ch, Ca, Sa, W float64
 
-	if Ttas < __Solpos_Ttprev {
-		__Solpos_Sdecl = mathSin(Decl)
-		__Solpos_Sld = Slat * __Solpos_Sdecl
-		__Solpos_Cld = Clat * mathCos(Decl)
+	if Ttas < Loc.__Solpos_Ttprev {
+		Loc.__Solpos_Sdecl = mathSin(Decl)
+		Loc.__Solpos_Sld = Loc.Slat * Loc.__Solpos_Sdecl
+		Loc.__Solpos_Cld = Loc.Clat * mathCos(Decl)
 	}
 
 	W = (Ttas - 12.0) * 0.2618
-	Sh = __Solpos_Sld + __Solpos_Cld*mathCos(W)
+	Sh = Loc.__Solpos_Sld + Loc.__Solpos_Cld*mathCos(W)
 	solh = mathAsin(Sh) / PI * 180.0
 
 	if Sh > 0.0 {
 		Ch = mathSqrt(1.0 - Sh*Sh)
-		Ca = (Sh*Slat - __Solpos_Sdecl) / (Ch * Clat)
+		Ca = (Sh*Loc.Slat - Loc.__Solpos_Sdecl) / (Ch * Loc.Clat)
 		var fW0 float64
 		if W > 0.0 {
 			fW0 = 1.0
@@ -315,7 +312,7 @@ func Solpos(Ttas float64, Decl float64) (Sh float64, Sw float64, Ss float64, sol
 		solA = 0.0
 	}
 
-	__Solpos_Ttprev = Ttas
+	Loc.__Solpos_Ttprev = Ttas
 
 	return Sh, Sw, Ss, solh, solA
 }
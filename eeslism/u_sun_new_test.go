@@ -6,27 +6,12 @@ import (
 )
 
 func TestSunint(t *testing.T) {
-	// Store original values
-	origLat := Lat
-	origSlat := Slat
-	origClat := Clat
-	origTlat := Tlat
-	origIsc := Isc
-
-	// Restore original values after test
-	defer func() {
-		Lat = origLat
-		Slat = origSlat
-		Clat = origClat
-		Tlat = origTlat
-		Isc = origIsc
-	}()
-
 	t.Run("SI unit initialization", func(t *testing.T) {
 		// Set test latitude (Tokyo: approximately 35.7°N)
-		Lat = 35.7
+		Loc := NewLOCAT()
+		Loc.Lat = 35.7
 
-		Sunint()
+		Loc.Sunint()
 
 		// Check trigonometric values
 		expectedSlat := math.Sin(35.7 * math.Pi / 180.0)
@@ -35,17 +20,17 @@ func TestSunint(t *testing.T) {
 		expectedIsc := 1370.0 // SI unit
 
 		tolerance := 1e-6
-		if math.Abs(Slat-expectedSlat) > tolerance {
-			t.Errorf("Sunint() Slat = %v, want %v", Slat, expectedSlat)
+		if math.Abs(Loc.Slat-expectedSlat) > tolerance {
+			t.Errorf("Sunint() Slat = %v, want %v", Loc.Slat, expectedSlat)
 		}
-		if math.Abs(Clat-expectedClat) > tolerance {
-			t.Errorf("Sunint() Clat = %v, want %v", Clat, expectedClat)
+		if math.Abs(Loc.Clat-expectedClat) > tolerance {
+			t.Errorf("Sunint() Clat = %v, want %v", Loc.Clat, expectedClat)
 		}
-		if math.Abs(Tlat-expectedTlat) > tolerance {
-			t.Errorf("Sunint() Tlat = %v, want %v", Tlat, expectedTlat)
+		if math.Abs(Loc.Tlat-expectedTlat) > tolerance {
+			t.Errorf("Sunint() Tlat = %v, want %v", Loc.Tlat, expectedTlat)
 		}
-		if math.Abs(Isc-expectedIsc) > tolerance {
-			t.Errorf("Sunint() Isc = %v, want %v", Isc, expectedIsc)
+		if math.Abs(Loc.Isc-expectedIsc) > tolerance {
+			t.Errorf("Sunint() Isc = %v, want %v", Loc.Isc, expectedIsc)
 		}
 	})
 }
@@ -148,7 +133,9 @@ func TestFNE(t *testing.T) {
 
 func TestFNSro(t *testing.T) {
 	// Initialize solar constants
-	Sunint()
+	Loc := NewLOCAT()
+	Loc.Lat = 35.7
+	Loc.Sunint()
 
 	tests := []struct {
 		name      string
@@ -184,7 +171,7 @@ func TestFNSro(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := FNSro(tt.day)
+			result := Loc.FNSro(tt.day)
 			if math.Abs(result-tt.expected) > tt.tolerance {
 				t.Errorf("FNSro(%d) = %v, want %v ± %v", tt.day, result, tt.expected, tt.tolerance)
 			}
@@ -323,30 +310,24 @@ func TestSolarCalculationConsistency(t *testing.T) {
 }
 
 func TestFNTtd(t *testing.T) {
-	// FNTtd uses the global Tlat set by Sunint().
-	// Save and restore globals so this test does not affect others.
-	origLat := Lat
-	origTlat := Tlat
-	defer func() {
-		Lat = origLat
-		Tlat = origTlat
-	}()
+	// FNTtd uses Tlat set by Sunint().
+	Loc := NewLOCAT()
 
 	t.Run("Tokyo latitude spring equinox 12h", func(t *testing.T) {
-		Lat = 35.7
-		Sunint()
+		Loc.Lat = 35.7
+		Loc.Sunint()
 		// Decl=0 → Cws=0 → Ttd = 7.6394 * acos(0) ≈ 12.0
-		Ttd := FNTtd(0.0)
+		Ttd := Loc.FNTtd(0.0)
 		if math.Abs(Ttd-12.0) > 0.01 {
 			t.Errorf("equinox day length = %.4f, want ≈12.0", Ttd)
 		}
 	})
 
 	t.Run("Tokyo latitude summer solstice ~14.42h", func(t *testing.T) {
-		Lat = 35.7
-		Sunint()
+		Loc.Lat = 35.7
+		Loc.Sunint()
 		Decl := 23.45 * math.Pi / 180.0
-		Ttd := FNTtd(Decl)
+		Ttd := Loc.FNTtd(Decl)
 		// Expected ≈ 14.42 hours (calculated via python)
 		if math.Abs(Ttd-14.42) > 0.05 {
 			t.Errorf("summer solstice day length = %.4f, want ≈14.42", Ttd)
@@ -354,10 +335,10 @@ func TestFNTtd(t *testing.T) {
 	})
 
 	t.Run("Tokyo latitude winter solstice ~9.58h", func(t *testing.T) {
-		Lat = 35.7
-		Sunint()
+		Loc.Lat = 35.7
+		Loc.Sunint()
 		Decl := -23.45 * math.Pi / 180.0
-		Ttd := FNTtd(Decl)
+		Ttd := Loc.FNTtd(Decl)
 		// Expected ≈ 9.58 hours
 		if math.Abs(Ttd-9.58) > 0.05 {
 			t.Errorf("winter solstice day length = %.4f, want ≈9.58", Ttd)
@@ -365,10 +346,10 @@ func TestFNTtd(t *testing.T) {
 	})
 
 	t.Run("Polar day Lat=80 Decl=20deg returns 24h", func(t *testing.T) {
-		Lat = 80.0
-		Sunint()
+		Loc.Lat = 80.0
+		Loc.Sunint()
 		Decl := 20.0 * math.Pi / 180.0
-		Ttd := FNTtd(Decl)
+		Ttd := Loc.FNTtd(Decl)
 		// Cws = -tan(80°)*tan(20°) ≈ -2.06 ≤ -1 → white night → 24h
 		if Ttd != 24.0 {
 			t.Errorf("polar day: got %.4f, want 24.0", Ttd)
@@ -376,10 +357,10 @@ func TestFNTtd(t *testing.T) {
 	})
 
 	t.Run("Polar night Lat=80 Decl=-20deg returns 0h", func(t *testing.T) {
-		Lat = 80.0
-		Sunint()
+		Loc.Lat = 80.0
+		Loc.Sunint()
 		Decl := -20.0 * math.Pi / 180.0
-		Ttd := FNTtd(Decl)
+		Ttd := Loc.FNTtd(Decl)
 		// Cws = -tan(80°)*tan(-20°) ≈ +2.06 ≥ 1 → polar night → 0h
 		if Ttd != 0.0 {
 			t.Errorf("polar night: got %.4f, want 0.0", Ttd)
@@ -387,10 +368,10 @@ func TestFNTtd(t *testing.T) {
 	})
 
 	t.Run("summer > winter day length", func(t *testing.T) {
-		Lat = 35.7
-		Sunint()
-		summer := FNTtd(23.45 * math.Pi / 180.0)
-		winter := FNTtd(-23.45 * math.Pi / 180.0)
+		Loc.Lat = 35.7
+		Loc.Sunint()
+		summer := Loc.FNTtd(23.45 * math.Pi / 180.0)
+		winter := Loc.FNTtd(-23.45 * math.Pi / 180.0)
 		if summer <= winter {
 			t.Errorf("summer (%.4f) should be longer than winter (%.4f)", summer, winter)
 		}
@@ -637,6 +637,8 @@ func NewLOCAT() *LOCAT {
 
 	matinitx(L.Twsup[:], 12, FNAN)
 
+	L.__Solpos_Ttprev = 25.0
+
 	return L
 }
 
@@ -0,0 +1,732 @@
+/*
+wcsv.go (CSV Weather Data)
+
+実測の気象データなどの CSV ファイルを、列と単位の対応を GDAT の WCSV で指定して気象データとします。
+気象データファイル種別は 'C' となります。
+
+	GDAT
+	    WCSV file=site.csv time=Timestamp tfmt=%Y/%m/%d_%H:%M conv=end maxgap=3600
+	        Lat=35.68 Lon=139.77 Ls=135
+	        T=Temp:C RH=Humidity:% Ihor=GHI:kWh/m2 Isky=DHI:kWh/m2 CC=Cloud Wv=Wind:m/s Wdre=WindDir:deg ;
+
+キーワードは次のとおりです。
+
+  - file=: CSV ファイル名。入力データファイルと同じディレクトリ、次に EFL のディレクトリから探します（ref: SIMCONTL.readRef）。
+  - time=: 日時の列。見出し行の列名、または 1 から始まる列番号です。日付と時刻が別の列の場合は Date+Time のように + で結びます。
+  - header=: 1行目が見出し行か (yes、no)。省略した場合は、列名で列を指定したとき、または1行目の日時の列が
+    日時でないときに見出し行とします。気象要素の値が空欄や NA であっても見出し行とはしません。
+  - tfmt=: 日時の書式。%Y（年）、%m（月）、%d（日）、%H（時）、%M（分）、%S（秒）の順序を表し、区切り文字は任意です。
+    既定値は %Y/%m/%d_%H:%M で、秒は省略できます。24:00 は翌日の 0:00 とします。
+  - conv=: 各行の値の時刻。end（既定値）は日時で終わる区間の値、start は日時から始まる区間の値です。
+  - maxgap=: 直線補間する欠測の最大の長さ [s]（既定値 3600）。行の抜け、空欄、数値でない値を欠測とし、
+    これより長い欠測がある場合はエラーとします。
+  - year=: 計算開始日の通日 1 とする年。既定値は最初の行の年です。実暦による計算（ref: calendar.go）では用いず、
+    各計算日の年月日の行を用います。
+  - Lat=、Lon=、Ls=: 緯度、経度、標準子午線 [deg]（必須）。
+  - Tgrav=、DTgr=、daymx=: 地中温度の係数。省略した場合は気温から求めます（ref: wdGround）。
+  - 要素=列[:単位]: 気象要素の列と単位。要素と単位は次のとおりです（単位の 1 つ目が既定値）。
+    T（気温）: C、K、F
+    x（絶対湿度）: kg/kg、g/kg
+    RH（相対湿度）: %、-（0～1）
+    Tdp（露点温度）: C、K、F
+    Idn、Isky、Ihor（法線面直達日射、水平面天空日射、水平面全天日射）: W/m2、kW/m2、Wh/m2、kWh/m2、MJ/m2、kcal/m2h
+    RN（夜間放射）: 日射と同じ
+    CC（雲量）: -（0～10）、%
+    Wv（風速）: m/s、km/h
+    Wdre（風向）: deg（北から時計回り）、16（16方位）
+
+T、湿度（x、RH、Tdp のいずれか）、日射（Idn、Isky、Ihor のうち2つ）、CC または RN、Wv は必須です。
+Ihor を指定した場合は、各時間ステップの太陽高度から Idn または Isky を求めます。
+
+計算時間間隔（GDAT RUN dTime）が CSV の間隔より短い場合は前後の値を直線補間し、長い場合は時間ステップ内の値を平均します。
+風向は時間ステップの終わりを含む区間の値とします。2/29 の行は実暦による計算でのみ用います。
+*/
+package eeslism
+
+import (
+	"io"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV の気象データ (GDAT WCSV)
+type WCSV struct {
+	File    string       // CSV ファイル名 (file=)
+	Time    []string     // 日時の列 (time=)
+	Tfmt    string       // 日時の書式 (tfmt=)
+	Start   bool         // 各行の値が日時から始まる区間の値 (conv=start)
+	MaxGap  int          // 直線補間する欠測の最大の長さ [s] (maxgap=)
+	Year    int          // 計算開始日の通日 1 とする年 (year=)。0 の場合は最初の行の年
+	Header  int          // 1行目が見出し行か (header=)。1: yes、-1: no、0: 列名と日時の列から判定
+	Loc     LOCAT        // 緯度、経度、標準子午線、地中温度の係数
+	Columns []WCSVColumn // 気象要素の列
+
+	step int              // CSV の間隔 [s]
+	t0   int              // 最初の値の時刻（Year の 1/1 0:00 からの秒数）
+	val  [wcsvN][]float64 // 要素ごとの値（等間隔。欠測は補間済み）
+	has  [wcsvN]bool      // 列を指定した要素
+}
+
+// CSV の気象要素の列
+type WCSVColumn struct {
+	Field  string // 気象要素 (T, x, RH, Tdp, Idn, Isky, Ihor, CC, RN, Wv, Wdre)
+	Column string // 列名または列番号
+	Unit   string // 単位
+}
+
+// CSV の気象要素
+const (
+	wcsvT = iota
+	wcsvX
+	wcsvRH
+	wcsvTdp
+	wcsvIdn
+	wcsvIsky
+	wcsvIhor
+	wcsvCC
+	wcsvRN
+	wcsvWv
+	wcsvWdre
+	wcsvN
+)
+
+var wcsvFields = [wcsvN]string{"T", "x", "RH", "Tdp", "Idn", "Isky", "Ihor", "CC", "RN", "Wv", "Wdre"}
+
+// wcsvUnits は気象要素ごとの単位と、単位の値を CSV の間隔 step [s] で換算する関数です。先頭の単位が既定値です。
+var wcsvUnits = func() [wcsvN][]wcsvUnit {
+	temp := []wcsvUnit{
+		{"C", func(v, _ float64) float64 { return v }},
+		{"K", func(v, _ float64) float64 { return v - 273.15 }},
+		{"F", func(v, _ float64) float64 { return (v - 32.0) / 1.8 }},
+	}
+	rad := []wcsvUnit{
+		{"W/m2", func(v, _ float64) float64 { return v }},
+		{"kW/m2", func(v, _ float64) float64 { return v * 1000.0 }},
+		{"Wh/m2", func(v, step float64) float64 { return v * 3600.0 / step }},
+		{"kWh/m2", func(v, step float64) float64 { return v * 3.6e6 / step }},
+		{"MJ/m2", func(v, step float64) float64 { return v * 1.0e6 / step }},
+		{"kcal/m2h", func(v, _ float64) float64 { return v / 0.86 }},
+	}
+	var u [wcsvN][]wcsvUnit
+	u[wcsvT], u[wcsvTdp] = temp, temp
+	u[wcsvX] = []wcsvUnit{
+		{"kg/kg", func(v, _ float64) float64 { return v }},
+		{"g/kg", func(v, _ float64) float64 { return v / 1000.0 }},
+	}
+	u[wcsvRH] = []wcsvUnit{
+		{"%", func(v, _ float64) float64 { return v }},
+		{"-", func(v, _ float64) float64 { return v * 100.0 }},
+	}
+	u[wcsvIdn], u[wcsvIsky], u[wcsvIhor], u[wcsvRN] = rad, rad, rad, rad
+	u[wcsvCC] = []wcsvUnit{
+		{"-", func(v, _ float64) float64 { return v }},
+		{"%", func(v, _ float64) float64 { return v / 10.0 }},
+	}
+	u[wcsvWv] = []wcsvUnit{
+		{"m/s", func(v, _ float64) float64 { return v }},
+		{"km/h", func(v, _ float64) float64 { return v / 3.6 }},
+	}
+	u[wcsvWdre] = []wcsvUnit{
+		{"deg", func(v, _ float64) float64 { return v }},
+		{"16", func(v, _ float64) float64 { return v * 22.5 }},
+	}
+	return u
+}()
+
+type wcsvUnit struct {
+	name string
+	conv func(v, step float64) float64
+}
+
+// ParseWCSV は GDAT の WCSV の論理行 fields を読み取ります。
+func ParseWCSV(fields []string) (*WCSV, error) {
+	c := &WCSV{Tfmt: "%Y/%m/%d_%H:%M", MaxGap: 3600}
+	c.Loc.Lat, c.Loc.Lon, c.Loc.Ls = FNAN, FNAN, FNAN
+	c.Loc.Tgrav, c.Loc.DTgr, c.Loc.Daymxert = FNAN, FNAN, INAN
+	for _, s := range fields[1:] {
+		s = strings.TrimSuffix(s, ";")
+		if s == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid keyword %q", s)
+		}
+		number := func() (float64, error) {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %q is not a number", key, value)
+			}
+			return v, nil
+		}
+		var err error
+		switch key {
+		case "file":
+			c.File = value
+		case "time":
+			c.Time = strings.Split(value, "+")
+		case "tfmt":
+			c.Tfmt = value
+		case "conv":
+			if value != "end" && value != "start" {
+				return nil, fmt.Errorf("conv=%s: end or start is allowed", value)
+			}
+			c.Start = value == "start"
+		case "maxgap":
+			if c.MaxGap, err = strconv.Atoi(value); err != nil || c.MaxGap < 0 {
+				return nil, fmt.Errorf("maxgap=%s: seconds are required", value)
+			}
+		case "header":
+			if value != "yes" && value != "no" {
+				return nil, fmt.Errorf("header=%s: yes or no is allowed", value)
+			}
+			c.Header = 1
+			if value == "no" {
+				c.Header = -1
+			}
+		case "year":
+			if c.Year, err = strconv.Atoi(value); err != nil || c.Year < 1 {
+				return nil, fmt.Errorf("year=%s: invalid year", value)
+			}
+		case "Lat":
+			c.Loc.Lat, err = number()
+		case "Lon":
+			c.Loc.Lon, err = number()
+		case "Ls":
+			c.Loc.Ls, err = number()
+		case "Tgrav":
+			c.Loc.Tgrav, err = number()
+		case "DTgr":
+			c.Loc.DTgr, err = number()
+		case "daymx":
+			var v float64
+			v, err = number()
+			c.Loc.Daymxert = int(v)
+		default:
+			f := wcsvField(key)
+			if f < 0 {
+				return nil, fmt.Errorf("unknown keyword %q", key)
+			}
+			col, unit, _ := strings.Cut(value, ":")
+			if unit == "" {
+				unit = wcsvUnits[f][0].name
+			}
+			if wcsvUnitOf(f, unit) == nil {
+				return nil, fmt.Errorf("%s: unknown unit %q", key, unit)
+			}
+			c.Columns = append(c.Columns, WCSVColumn{Field: key, Column: col, Unit: unit})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.File == "" {
+		return nil, fmt.Errorf("file= is required")
+	}
+	if c.Time == nil {
+		return nil, fmt.Errorf("time= is required")
+	}
+	if c.Loc.Lat == FNAN || c.Loc.Lon == FNAN || c.Loc.Ls == FNAN {
+		return nil, fmt.Errorf("Lat=, Lon= and Ls= are required")
+	}
+	if _, err := parseTfmt(c.Tfmt); err != nil {
+		return nil, err
+	}
+	var has [wcsvN]bool
+	for _, col := range c.Columns {
+		has[wcsvField(col.Field)] = true
+	}
+	nrad := 0
+	for _, f := range []int{wcsvIdn, wcsvIsky, wcsvIhor} {
+		if has[f] {
+			nrad++
+		}
+	}
+	switch {
+	case !has[wcsvT]:
+		return nil, fmt.Errorf("T is required")
+	case !has[wcsvX] && !has[wcsvRH] && !has[wcsvTdp]:
+		return nil, fmt.Errorf("x, RH or Tdp is required")
+	case nrad != 2:
+		return nil, fmt.Errorf("two of Idn, Isky and Ihor are required")
+	case !has[wcsvCC] && !has[wcsvRN]:
+		return nil, fmt.Errorf("CC or RN is required")
+	case !has[wcsvWv]:
+		return nil, fmt.Errorf("Wv is required")
+	}
+	return c, nil
+}
+
+// wcsvField は気象要素 name の番号を返します。ない場合は -1 を返します。
+func wcsvField(name string) int {
+	for f, s := range wcsvFields {
+		if s == name {
+			return f
+		}
+	}
+	return -1
+}
+
+// wcsvUnitOf は気象要素 f の単位 name を返します。ない場合は nil を返します。
+func wcsvUnitOf(f int, name string) *wcsvUnit {
+	for i := range wcsvUnits[f] {
+		if wcsvUnits[f][i].name == name {
+			return &wcsvUnits[f][i]
+		}
+	}
+	return nil
+}
+
+// parseTfmt は日時の書式の %Y などの順序を返します。
+func parseTfmt(tfmt string) ([]byte, error) {
+	var d []byte
+	for i := 0; i < len(tfmt); i++ {
+		if tfmt[i] != '%' {
+			continue
+		}
+		if i+1 >= len(tfmt) || !strings.ContainsRune("YmdHMS", rune(tfmt[i+1])) {
+			return nil, fmt.Errorf("tfmt=%s: %%Y, %%m, %%d, %%H, %%M and %%S are allowed", tfmt)
+		}
+		i++
+		d = append(d, tfmt[i])
+	}
+	for _, c := range []byte("YmdH") {
+		if !strings.ContainsRune(string(d), rune(c)) {
+			return nil, fmt.Errorf("tfmt=%s: %%%c is required", tfmt, c)
+		}
+	}
+	return d, nil
+}
+
+// parseTimestamp は日時 s を書式の順序 dirs で読み取ります。
+// 区切り文字のない日時は %Y を4桁、それ以外を2桁とします。
+func parseTimestamp(s string, dirs []byte) (time.Time, error) {
+	var groups []string
+	for _, g := range strings.FieldsFunc(s, func(r rune) bool { return r < '0' || r > '9' }) {
+		groups = append(groups, g)
+	}
+	if len(groups) < len(dirs) {
+		// 20230101 0010 などの区切りのない日時
+		var split []string
+		for _, d := range dirs {
+			if len(groups) == 0 {
+				break
+			}
+			w := 2
+			if d == 'Y' {
+				w = 4
+			}
+			if g := groups[0]; len(g) > w {
+				split = append(split, g[:w])
+				groups[0] = g[w:]
+			} else {
+				split = append(split, g)
+				groups = groups[1:]
+			}
+		}
+		if len(groups) > 0 {
+			return time.Time{}, fmt.Errorf("%q does not match the timestamp format", s)
+		}
+		groups = split
+	}
+	n := len(groups)
+	if n != len(dirs) && !(n == len(dirs)-1 && dirs[len(dirs)-1] == 'S') {
+		return time.Time{}, fmt.Errorf("%q does not match the timestamp format", s)
+	}
+	v := map[byte]int{}
+	for i, g := range groups {
+		v[dirs[i]], _ = strconv.Atoi(g)
+	}
+	mon, day := v['m'], v['d']
+	date := time.Date(v['Y'], time.Month(mon), day, 0, 0, 0, 0, time.UTC)
+	if mon < 1 || mon > 12 || date.Day() != day || v['H'] > 24 || v['M'] > 59 || v['S'] > 59 {
+		return time.Time{}, fmt.Errorf("%q is not a valid timestamp", s)
+	}
+	return date.Add(time.Duration(v['H']*3600+v['M']*60+v['S']) * time.Second), nil
+}
+
+// wcsvSeconds は日時 t の year の 1/1 0:00 からの秒数を返します。
+func wcsvSeconds(year int, t time.Time) int {
+	return int(t.Sub(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)) / time.Second)
+}
+
+// wcsvTimeString は year の 1/1 0:00 からの秒数 t を日時の文字列とします。
+func wcsvTimeString(year, t int) string {
+	d := time.Date(year, time.January, 1, 0, 0, t, 0, time.UTC)
+	return fmt.Sprintf("%d/%d/%d %02d:%02d", d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute())
+}
+
+// Load は CSV の行 rows から気象要素の時系列を読み込みます。
+func (c *WCSV) Load(rows [][]string) error {
+	if len(rows) == 0 {
+		return fmt.Errorf("no rows")
+	}
+	dirs, _ := parseTfmt(c.Tfmt)
+	c.val, c.has = [wcsvN][]float64{}, [wcsvN]bool{}
+	for _, col := range c.Columns {
+		c.has[wcsvField(col.Field)] = true
+	}
+
+	// 列の検索。header= を省略した場合は、列名で指定したとき、または1行目の日時の列が日時でないときに
+	// 1行目を見出し行とする。気象要素の列の値（空欄、NA など）では判定しない
+	header := c.Header > 0
+	find := func(name string) (int, error) {
+		if n, err := strconv.Atoi(name); err == nil && n >= 1 {
+			return n - 1, nil
+		}
+		if c.Header < 0 {
+			return -1, fmt.Errorf("column %q: column numbers are required with header=no", name)
+		}
+		for i, s := range rows[0] {
+			if strings.TrimSpace(s) == name {
+				header = true
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("column %q is not found", name)
+	}
+	tcol := make([]int, len(c.Time))
+	for i, name := range c.Time {
+		var err error
+		if tcol[i], err = find(name); err != nil {
+			return err
+		}
+	}
+	if c.Header == 0 && !header {
+		ts := make([]string, len(tcol))
+		for k, i := range tcol {
+			if i < len(rows[0]) {
+				ts[k] = strings.TrimSpace(rows[0][i])
+			}
+		}
+		_, err := parseTimestamp(strings.Join(ts, " "), dirs)
+		header = err != nil
+	}
+	var vcol [wcsvN]int
+	for _, col := range c.Columns {
+		f := wcsvField(col.Field)
+		var err error
+		if vcol[f], err = find(col.Column); err != nil {
+			return err
+		}
+	}
+	line0 := 1
+	if header {
+		rows = rows[1:]
+		line0 = 2
+	}
+
+	// 日時と値の読み取り
+	type record struct {
+		line int
+		t    int
+		v    [wcsvN]float64
+	}
+	var recs []record
+	for i, row := range rows {
+		cell := func(k int) string {
+			if k < len(row) {
+				return strings.TrimSpace(row[k])
+			}
+			return ""
+		}
+		ts := make([]string, len(tcol))
+		for k := range tcol {
+			ts[k] = cell(tcol[k])
+		}
+		tm, err := parseTimestamp(strings.Join(ts, " "), dirs)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", line0+i, err)
+		}
+		if c.Year == 0 {
+			c.Year = tm.Year()
+		}
+		r := record{line: line0 + i, t: wcsvSeconds(c.Year, tm)}
+		for f := range r.v {
+			r.v[f] = math.NaN()
+			if c.has[f] {
+				if v, err := strconv.ParseFloat(cell(vcol[f]), 64); err == nil {
+					r.v[f] = v
+				}
+			}
+		}
+		if n := len(recs); n > 0 && r.t <= recs[n-1].t {
+			return fmt.Errorf("line %d: %s is not after the previous row", r.line, wcsvTimeString(c.Year, r.t))
+		}
+		recs = append(recs, r)
+	}
+	if len(recs) < 2 {
+		return fmt.Errorf("at least two rows are required")
+	}
+
+	// 間隔は最も多い行の間隔とし、行の抜けは欠測とする
+	count := map[int]int{}
+	for i := 1; i < len(recs); i++ {
+		count[recs[i].t-recs[i-1].t]++
+	}
+	steps := make([]int, 0, len(count))
+	for d := range count {
+		steps = append(steps, d)
+	}
+	sort.Slice(steps, func(i, j int) bool {
+		return count[steps[i]] > count[steps[j]] || count[steps[i]] == count[steps[j]] && steps[i] < steps[j]
+	})
+	c.step = steps[0]
+	for i := 1; i < len(recs); i++ {
+		if (recs[i].t-recs[0].t)%c.step != 0 {
+			return fmt.Errorf("line %d: %s is not on the %d s interval", recs[i].line, wcsvTimeString(c.Year, recs[i].t), c.step)
+		}
+	}
+
+	c.t0 = recs[0].t
+	n := (recs[len(recs)-1].t-c.t0)/c.step + 1
+	for f := range c.val {
+		if !c.has[f] {
+			continue
+		}
+		c.val[f] = make([]float64, n)
+		for k := range c.val[f] {
+			c.val[f][k] = math.NaN()
+		}
+		for _, r := range recs {
+			c.val[f][(r.t-c.t0)/c.step] = r.v[f]
+		}
+		if err := c.fillGaps(f); err != nil {
+			return err
+		}
+		u := wcsvUnitOf(f, c.unit(f))
+		for k, v := range c.val[f] {
+			c.val[f][k] = u.conv(v, float64(c.step))
+		}
+	}
+	if c.Start {
+		c.t0 += c.step
+	}
+
+	// 湿度は絶対湿度とする
+	if !c.has[wcsvX] {
+		c.val[wcsvX] = make([]float64, n)
+		for k := range c.val[wcsvX] {
+			if c.has[wcsvTdp] {
+				c.val[wcsvX][k] = FNXp(FNPws(c.val[wcsvTdp][k]))
+			} else {
+				c.val[wcsvX][k] = FNXtr(c.val[wcsvT][k], c.val[wcsvRH][k])
+			}
+		}
+		c.has[wcsvX] = true
+	}
+	return nil
+}
+
+// unit は気象要素 f の単位を返します。
+func (c *WCSV) unit(f int) string {
+	for _, col := range c.Columns {
+		if wcsvField(col.Field) == f {
+			return col.Unit
+		}
+	}
+	return wcsvUnits[f][0].name
+}
+
+// fillGaps は気象要素 f の欠測を前後の値で直線補間します。MaxGap より長い欠測と、最初、最後の欠測はエラーとします。
+func (c *WCSV) fillGaps(f int) error {
+	v := c.val[f]
+	nfill := 0
+	for k := 0; k < len(v); k++ {
+		if !math.IsNaN(v[k]) {
+			continue
+		}
+		e := k
+		for e < len(v) && math.IsNaN(v[e]) {
+			e++
+		}
+		// 欠測は k～e-1 の値
+		if k == 0 || e == len(v) || (e-k)*c.step > c.MaxGap {
+			return fmt.Errorf("%s: missing from %s to %s", wcsvFields[f],
+				wcsvTimeString(c.Year, c.t0+k*c.step), wcsvTimeString(c.Year, c.t0+(e-1)*c.step))
+		}
+		for i := k; i < e; i++ {
+			r := float64(i-k+1) / float64(e-k+1)
+			v[i] = v[k-1]*(1-r) + v[e]*r
+		}
+		nfill += e - k
+		k = e
+	}
+	if nfill > 0 {
+		fmt.Printf("<WCSV> %s: %d missing values are interpolated\n", wcsvFields[f], nfill)
+	}
+	return nil
+}
+
+// value は t [s] で終わる dtm [s] の時間ステップの気象要素 f の値を返します。
+func (c *WCSV) value(f, t, dtm int) (float64, bool) {
+	v := c.val[f]
+	if f == wcsvWdre {
+		// 風向は時刻 t を含む区間の値
+		k := int(math.Ceil(float64(t-c.t0) / float64(c.step)))
+		if k < 0 || k >= len(v) {
+			return 0, false
+		}
+		return v[k], true
+	}
+	if dtm > c.step {
+		// 時間ステップ内の値の平均
+		k0 := int(math.Floor(float64(t-dtm-c.t0)/float64(c.step))) + 1
+		k1 := int(math.Floor(float64(t-c.t0) / float64(c.step)))
+		if k0 < 0 || k1 >= len(v) {
+			return 0, false
+		}
+		sum := 0.0
+		for k := k0; k <= k1; k++ {
+			sum += v[k]
+		}
+		return sum / float64(k1-k0+1), true
+	}
+	x := float64(t-c.t0) / float64(c.step)
+	k := int(math.Floor(x))
+	r := x - float64(k)
+	if k < 0 || k >= len(v) || (r > 0 && k+1 >= len(v)) {
+		return 0, false
+	}
+	if r == 0 {
+		return v[k], true
+	}
+	return v[k]*(1-r) + v[k+1]*r, true
+}
+
+// SetLocation は緯度、経度、標準子午線と地中温度の係数、給水温度を Loc に設定します。
+func (c *WCSV) SetLocation(Loc *LOCAT) {
+	Loc.Lat, Loc.Lon, Loc.Ls = c.Loc.Lat, c.Loc.Lon, c.Loc.Ls
+	if Loc.Name == "" {
+		Loc.Name = c.File
+	}
+
+	// 日平均気温。通日は標準年の通日（2/29 は 2/28）とする
+	stdDay := func(d int) int {
+		t := time.Date(c.Year, time.January, d+1, 0, 0, 0, 0, time.UTC)
+		return standardDay(int(t.Month()), t.Day())
+	}
+	var nday []int
+	var Td []float64
+	T := c.val[wcsvT]
+	for d := int(math.Ceil(float64(c.t0) / 86400)); (d+1)*86400 <= c.t0+(len(T)-1)*c.step; d++ {
+		k0 := (d*86400-c.t0)/c.step + 1
+		k1 := ((d+1)*86400 - c.t0) / c.step
+		s := 0.0
+		for k := k0; k <= k1; k++ {
+			s += T[k]
+		}
+		nday = append(nday, stdDay(d))
+		Td = append(Td, s/float64(k1-k0+1))
+	}
+	if len(Td) == 0 {
+		Td = append(Td, c.val[wcsvT][0])
+		nday = append(nday, stdDay(int(math.Floor(float64(c.t0)/86400))))
+	}
+	wdGround(Loc, nday, Td)
+	if c.Loc.Tgrav != FNAN {
+		Loc.Tgrav = c.Loc.Tgrav
+	}
+	if c.Loc.DTgr != FNAN {
+		Loc.DTgr = c.Loc.DTgr
+	}
+	if c.Loc.Daymxert != INAN {
+		Loc.Daymxert = c.Loc.Daymxert
+	}
+}
+
+/*
+wcsvinput (CSV Weather Data Input)
+
+この関数は、CSV の気象データ（`Simc.Wcsv`）から、計算日 `nday`、時刻 `Daytm.Ttmm` で終わる時間ステップの
+気象データを `WDPT` に設定し、`Wdflinput` により `WDAT` とします。
+
+建築環境工学的な観点:
+  - **実測気象データによる検証**: 建物の実測値とシミュレーションを比較する際には、
+    同じ期間に現地で計測した気象データを用いる必要があります。
+  - **全天日射の直散分離**: 水平面全天日射量 `Ihor` と一方の日射成分から、
+    太陽高度の正弦 `Wd.Sh` を用いてもう一方の成分を求めます。
+    太陽高度が低い時刻（`Sh` < 0.05）は全天日射をすべて天空日射とします。
+*/
+func (sim *Simulation) wcsvinput(Simc *SIMCONTL, Daytm *DAYTM, Wd *WDAT) {
+	c := Simc.Wcsv
+
+	// 計算日の年。実暦による計算以外では通日 1 を year= の 1/1 とし、365 日ごとに翌年とする
+	year := Daytm.Year
+	if Simc.BaseYear == 0 {
+		nday := sim.nday
+		if Simc.Perio == 'y' {
+			nday = Simc.Daystart
+		}
+		year = c.Year + (nday-1)/365
+	}
+	date := time.Date(year, time.Month(Daytm.Mon), Daytm.Day, 0, 0, 0, 0, time.UTC)
+	t := wcsvSeconds(c.Year, date) + Daytm.Ttmm/100*3600 + Daytm.Ttmm%100*60
+
+	var v [wcsvN]float64
+	for f := range v {
+		if !c.has[f] {
+			continue
+		}
+		var ok bool
+		if v[f], ok = c.value(f, t, Simc.DTm); !ok {
+			s := fmt.Sprintf("no data at %s", wcsvTimeString(c.Year, t))
+			Eprint("<wcsvinput>", s, sim.Ferr)
+			panic(&WeatherError{Section: "GDAT", Keyword: "WCSV", Component: c.File, Msg: s, Code: EXIT_WFILE})
+		}
+	}
+
+	switch {
+	case !c.has[wcsvIhor]:
+	case Wd.Sh < 0.05:
+		v[wcsvIdn], v[wcsvIsky] = 0.0, v[wcsvIhor]
+	case c.has[wcsvIsky]:
+		v[wcsvIdn] = math.Max(0.0, (v[wcsvIhor]-v[wcsvIsky])/Wd.Sh)
+	default:
+		v[wcsvIsky] = math.Max(0.0, v[wcsvIhor]-v[wcsvIdn]*Wd.Sh)
+	}
+	if c.has[wcsvWdre] {
+		v[wcsvWdre] = wdre16(v[wcsvWdre], v[wcsvWv])
+	}
+
+	wp := &Simc.Wdpt
+	*wp = WDPT{
+		Ta:   v[wcsvT : wcsvT+1],
+		Xa:   v[wcsvX : wcsvX+1],
+		Idn:  v[wcsvIdn : wcsvIdn+1],
+		Isky: v[wcsvIsky : wcsvIsky+1],
+		Wv:   v[wcsvWv : wcsvWv+1],
+	}
+	if c.has[wcsvCC] {
+		wp.Cc = v[wcsvCC : wcsvCC+1]
+	}
+	if c.has[wcsvRN] {
+		wp.Rn = v[wcsvRN : wcsvRN+1]
+	}
+	if c.has[wcsvWdre] {
+		wp.Wdre = v[wcsvWdre : wcsvWdre+1]
+	}
+	Wdflinput(wp, Wd)
+}
+
+// wcsvopen は GDAT の WCSV で指定した CSV ファイルを読み込み、地点情報を Loc に設定します。
+func (Simc *SIMCONTL) wcsvopen(Ferr io.Writer) {
+	c := Simc.Wcsv
+	b, err := Simc.readRef(c.File)
+	if err == nil {
+		var rows [][]string
+		if rows, err = readCSVRows(b); err == nil {
+			err = c.Load(rows)
+		}
+	}
+	if err != nil {
+		Eprint("<wcsvopen>", err.Error(), Ferr)
+		panic(&WeatherError{Section: "GDAT", Keyword: "WCSV", Component: c.File, Msg: err.Error(), Code: EXIT_WFILE})
+	}
+	c.SetLocation(Simc.Loc)
+}
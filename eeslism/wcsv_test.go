@@ -0,0 +1,245 @@
+package eeslism
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseWCSV は WCSV の既定値と必須のキーワードを確認する
+func TestParseWCSV(t *testing.T) {
+	base := "WCSV file=a.csv time=Date+Time Lat=35 Lon=139 Ls=135 T=Temp:K RH=3 Ihor=GHI Isky=DHI:kWh/m2 CC=4 Wv=5 ;"
+	c, err := ParseWCSV(strings.Fields(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.File != "a.csv" || fmt.Sprint(c.Time) != "[Date Time]" || c.Tfmt != "%Y/%m/%d_%H:%M" || c.MaxGap != 3600 || c.Start {
+		t.Errorf("WCSV = %+v", c)
+	}
+	if got := fmt.Sprint(c.Columns); got != "[{T Temp K} {RH 3 %} {Ihor GHI W/m2} {Isky DHI kWh/m2} {CC 4 -} {Wv 5 m/s}]" {
+		t.Errorf("Columns = %s", got)
+	}
+
+	for _, s := range []string{
+		strings.Replace(base, "file=a.csv ", "", 1),
+		strings.Replace(base, "time=Date+Time ", "", 1),
+		strings.Replace(base, "Ls=135 ", "", 1),
+		strings.Replace(base, "T=Temp:K ", "", 1),
+		strings.Replace(base, "RH=3 ", "", 1),
+		strings.Replace(base, "Isky=DHI:kWh/m2 ", "", 1),
+		strings.Replace(base, "CC=4 ", "", 1),
+		strings.Replace(base, "Wv=5 ", "", 1),
+		strings.Replace(base, "Temp:K", "Temp:degC", 1),
+		strings.Replace(base, "CC=4", "Cloud=4", 1),
+		base + " conv=middle",
+		base + " header=1",
+		base + " tfmt=%d/%m_%H:%M",
+	} {
+		if _, err := ParseWCSV(strings.Fields(s)); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
+
+// TestWCSVLoad は日時の書式、単位の換算、欠測の補間、計算時間間隔への補間と平均を確認する
+func TestWCSVLoad(t *testing.T) {
+	parse := func(s string) *WCSV {
+		t.Helper()
+		c, err := ParseWCSV(strings.Fields("WCSV file=a.csv Lat=35 Lon=139 Ls=135 " + s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	// 10分間隔。1/1 0:10 から 1/2 0:00 まで
+	rows := func(head []string, cells func(k int) []string) [][]string {
+		r := [][]string{head}
+		for k := 1; k <= 144; k++ {
+			r = append(r, cells(k))
+		}
+		return r
+	}
+	stamp := func(k int) string {
+		return fmt.Sprintf("2023-01-%02d %02d:%02d", 1+k*10/1440, k*10%1440/60, k*10%60)
+	}
+	values := func(k int) []string {
+		return []string{fmt.Sprint(273.15 + float64(k)), "50", fmt.Sprint(k), "2", "0", "5", "4"}
+	}
+	head := []string{"Timestamp", "Temp", "RH", "GHI", "Wv", "Isky", "CC", "Dir"}
+	c := parse("time=Timestamp tfmt=%Y-%m-%d_%H:%M T=Temp:K RH=RH Ihor=GHI:Wh/m2 Isky=Isky CC=CC Wv=Wv Wdre=Dir:16")
+	err := c.Load(rows(head, func(k int) []string { return append([]string{stamp(k)}, values(k)...) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.step != 600 || c.t0 != 600 || len(c.val[wcsvT]) != 144 || c.Year != 2023 {
+		t.Fatalf("step %d t0 %d n %d year %d", c.step, c.t0, len(c.val[wcsvT]), c.Year)
+	}
+	for _, tt := range []struct {
+		f, t, dtm int
+		want      float64
+	}{
+		{wcsvT, 3600, 3600, 3.5},   // 0:10～1:00 の平均
+		{wcsvT, 900, 300, 1.5},     // 0:10 と 0:20 の間の直線補間
+		{wcsvIhor, 3600, 3600, 21}, // Wh/m2 (10分) → W/m2
+		{wcsvWdre, 900, 300, 90},   // 16方位の 4 は 90°
+	} {
+		got, ok := c.value(tt.f, tt.t, tt.dtm)
+		if !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s t=%d dtm=%d: %g, want %g", wcsvFields[tt.f], tt.t, tt.dtm, got, tt.want)
+		}
+	}
+	if x := c.val[wcsvX][0]; x != FNXtr(1, 50) {
+		t.Errorf("x = %g, want %g (RH 50%%)", x, FNXtr(1, 50))
+	}
+	if _, ok := c.value(wcsvT, 600, 3600); ok {
+		t.Error("value before the first row: expected no data")
+	}
+	if _, ok := c.value(wcsvT, 86400+600, 600); ok {
+		t.Error("value after the last row: expected no data")
+	}
+
+	// 日付と時刻の列、conv=start、露点温度、行の抜けと空欄の補間
+	c = parse("time=1+2 tfmt=%d.%m.%Y_%H%M conv=start maxgap=1200 T=3:F Tdp=4 Ihor=5:kW/m2 Wv=6 Isky=7 CC=8")
+	var r [][]string
+	for k := 0; k < 144; k++ {
+		if k == 5 || k == 6 {
+			continue
+		}
+		row := []string{fmt.Sprintf("01.01.2023"), fmt.Sprintf("%02d%02d", k*10/60, k*10%60), "32", "0", "0.5", "2", "5", "4"}
+		if k == 10 {
+			row[2] = "NA"
+		}
+		r = append(r, row)
+	}
+	if err := c.Load(r); err != nil {
+		t.Fatal(err)
+	}
+	if c.t0 != 600 || c.val[wcsvT][0] != 0 || c.val[wcsvX][0] != FNXp(FNPws(0)) || c.val[wcsvIhor][5] != 500 {
+		t.Errorf("t0 %d T %g x %g Ihor %g", c.t0, c.val[wcsvT][0], c.val[wcsvX][0], c.val[wcsvIhor][5])
+	}
+
+	// 見出し行。列番号の場合は1行目の日時の列で判定する
+	head = []string{"Date", "Time", "T", "Tdp", "Ihor", "Wv", "Isky", "CC"}
+	for _, h := range []string{"", "header=yes "} {
+		c = parse(h + "time=1+2 tfmt=%d.%m.%Y_%H%M conv=start maxgap=1200 T=3:F Tdp=4 Ihor=5:kW/m2 Wv=6 Isky=7 CC=8")
+		if err := c.Load(append([][]string{head}, r...)); err != nil || c.t0 != 600 || len(c.val[wcsvT]) != 144 {
+			t.Errorf("%sheader row: %v, t0 %d n %d", h, err, c.t0, len(c.val[wcsvT]))
+		}
+	}
+	c = parse("header=no time=1+2 tfmt=%d.%m.%Y_%H%M conv=start maxgap=1200 T=3:F Tdp=4 Ihor=5:kW/m2 Wv=6 Isky=7 CC=8")
+	if err := c.Load(append([][]string{head}, r...)); err == nil {
+		t.Error("header=no with the header row: expected error")
+	}
+	c = parse("header=no time=Date+Time T=3:F Tdp=4 Ihor=5:kW/m2 Wv=6 Isky=7 CC=8")
+	if err := c.Load(append([][]string{head}, r...)); err == nil {
+		t.Error("header=no with the column names: expected error")
+	}
+	c = parse("time=1+2 tfmt=%d.%m.%Y_%H%M conv=start maxgap=1200 T=3:F Tdp=4 Ihor=5:kW/m2 Wv=6 Isky=7 CC=8")
+
+	// 補間できない欠測。最初の行の空欄、NA は見出し行としない
+	for name, r := range map[string][][]string{
+		"long gap":     append(append([][]string{}, r[:3]...), r[6:]...),
+		"first row":    append([][]string{{"01.01.2023", "0000", "", "0", "0.5", "2", "5", "4"}}, r[1:]...),
+		"first row NA": append([][]string{{"01.01.2023", "0000", "NA", "0", "0.5", "2", "5", "4"}}, r[1:]...),
+		"last row":     append(append([][]string{}, r...), []string{"02.01.2023", "0000", "", "0", "0.5", "2", "5", "4"}),
+		"not sorted":   append([][]string{r[1]}, r...),
+		"interval":     append([][]string{{"01.01.2023", "0003", "32", "0", "0.5", "2", "5", "4"}}, r...),
+		"timestamp":    append(append([][]string{r[0]}, []string{"2023-01-01", "00:10", "32", "0", "0.5", "2", "5", "4"}), r[1:]...),
+	} {
+		if err := c.Load(r); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		c = parse("time=1+2 tfmt=%d.%m.%Y_%H%M conv=start maxgap=1200 T=3:F Tdp=4 Ihor=5:kW/m2 Wv=6 Isky=7 CC=8")
+	}
+}
+
+// TestSimulation_WCSV は HASP 形式の気象データを CSV にした計算の気象データが HASP 形式の場合と一致することを確認する
+func TestSimulation_WCSV(t *testing.T) {
+	src := "../tests/comparison/testdata/L1_basic/simple_room_schedule/simple_room_schedule_test.txt"
+	eflPath, err := filepath.Abs("../Base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasp, err := os.ReadFile(filepath.Join(eflPath, "tokyo_3column_SI.has"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1/1 1:00 から 1/7 24:00 までの1時間値。気温は K、日射は Wh/m2、風向は16方位
+	var b strings.Builder
+	b.WriteString("Time,Temp,x,DNI,DHI,Cloud,Wind,Dir\n")
+	sim := NewSimulation("", "")
+	var Loc LOCAT
+	fp := bytes.NewReader(hasp)
+	for nday := 1; nday <= 7; nday++ {
+		var dt [7][25]float64
+		sim.hspwdread(fp, nday, &Loc, &dt)
+		for tt := 1; tt <= 24; tt++ {
+			Wd := WDAT{RNtype: 'C'}
+			dt2wdata(&Wd, tt, dt)
+			fmt.Fprintf(&b, "2023/1/%d %d:00,%.10g,%.10g,%.10g,%.10g,%g,%g,%g\n", nday, tt, Wd.T+273.15, Wd.X, Wd.Idn, Wd.Isky, Wd.CC, Wd.Wv, Wd.Wdre)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "site.csv"), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	in, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wcsv := fmt.Sprintf("WCSV file=site.csv time=Time Lat=%g Lon=%g Ls=%g\n\t\tT=Temp:K x=x Idn=DNI:Wh/m2 Isky=DHI:Wh/m2 CC=Cloud Wv=Wind Wdre=Dir:16 ;", Loc.Lat, Loc.Lon, Loc.Ls)
+	input := filepath.Join(dir, "input.txt")
+	in = []byte(strings.Replace(string(in), "FILE w=tokyo_3column_SI.has ;", wcsv, 1))
+	if err := os.WriteFile(input, in, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := NewSimulation(copySimulationInput(t, src, t.TempDir()), eflPath)
+	sim = NewSimulation(input, eflPath)
+	for _, s := range []*Simulation{ref, sim} {
+		if err := s.Init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sim.Simc.Wdtype != 'C' || sim.Simc.Wfname != "site.csv" {
+		t.Fatalf("Wdtype = %c, Wfname = %s", sim.Simc.Wdtype, sim.Simc.Wfname)
+	}
+	for !ref.Done() {
+		if err := ref.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sim.Step(); err != nil {
+			t.Fatal(err)
+		}
+		r, w := ref.Wd, sim.Wd
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"T", w.T, r.T}, {"X", w.X, r.X}, {"Idn", w.Idn, r.Idn}, {"Isky", w.Isky, r.Isky}, {"Ihor", w.Ihor, r.Ihor},
+			{"RN", w.RN, r.RN}, {"Rsky", w.Rsky, r.Rsky}, {"Wv", w.Wv, r.Wv}, {"Wdre", w.Wdre, r.Wdre},
+		} {
+			if math.Abs(v.got-v.want) > 1e-6*math.Max(1, math.Abs(v.want)) {
+				t.Fatalf("%d/%d %d: %s = %g, want %g", sim.Daytm.Mon, sim.Daytm.Day, sim.Daytm.Tt, v.name, v.got, v.want)
+			}
+		}
+	}
+	if err := sim.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	// CSV の期間外
+	in = []byte(strings.Replace(string(in), "RUN (1/1) 1/1-1/7", "RUN 1/1-1/8", 1))
+	if err := os.WriteFile(input, in, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSimulation(input, eflPath).Run(); err == nil || !strings.Contains(err.Error(), "no data at 2023/1/8 01:00") {
+		t.Errorf("err = %v, want no data at 2023/1/8 01:00", err)
+	}
+}
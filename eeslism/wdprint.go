@@ -5,11 +5,6 @@ import (
 	"io"
 )
 
-var __Wdtsum_oldday, __Wdtsum_oldMon int
-var __Wdtsum_hrs int  // 日累計回数
-var __Wdtsum_hrsm int // 月累計回数
-var __Wdtsum_cffWh float64
-
 /*
 Wdtsum (Weather Data Time Series Summation)
 
@@ -37,13 +32,13 @@ Wdtsum (Weather Data Time Series Summation)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func Wdtsum(Mon int, Day int, Nday int, ttmm int, Wd *WDAT, Exs []*EXSF,
+func (sim *Simulation) Wdtsum(Mon int, Day int, Nday int, ttmm int, Wd *WDAT, Exs []*EXSF,
 	Wdd *WDAT, Wdm *WDAT, Soldy []float64, Solmon []float64, Simc *SIMCONTL) {
 
 	// 日集計の初期化
-	if Nday != __Wdtsum_oldday {
-		__Wdtsum_cffWh = Cff_kWh * 1000.0
-		__Wdtsum_hrs = 0
+	if Nday != sim.__Wdtsum_oldday {
+		sim.__Wdtsum_cffWh = sim.Cff_kWh * 1000.0
+		sim.__Wdtsum_hrs = 0
 		Wdd.T = 0.0
 		Wdd.X = 0.0
 		Wdd.Wv = 0.0
@@ -56,12 +51,12 @@ func Wdtsum(Mon int, Day int, Nday int, ttmm int, Wd *WDAT, Exs []*EXSF,
 		}
 
 	}
-	__Wdtsum_oldday = Nday
+	sim.__Wdtsum_oldday = Nday
 
 	// 月集計の初期化
-	if Mon != __Wdtsum_oldMon {
-		__Wdtsum_cffWh = Cff_kWh * 1000.0
-		__Wdtsum_hrsm = 0
+	if Mon != sim.__Wdtsum_oldMon {
+		sim.__Wdtsum_cffWh = sim.Cff_kWh * 1000.0
+		sim.__Wdtsum_hrsm = 0
 		Wdm.T = 0.0
 		Wdm.X = 0.0
 		Wdm.Wv = 0.0
@@ -73,11 +68,11 @@ func Wdtsum(Mon int, Day int, Nday int, ttmm int, Wd *WDAT, Exs []*EXSF,
 			Solmon[i] = 0.0
 		}
 
-		__Wdtsum_oldMon = Mon
+		sim.__Wdtsum_oldMon = Mon
 	}
 
 	// 日集計
-	__Wdtsum_hrs++
+	sim.__Wdtsum_hrs++
 	Wdd.T += Wd.T
 	Wdd.X += Wd.X
 	Wdd.Wv += Wd.Wv
@@ -94,7 +89,7 @@ func Wdtsum(Mon int, Day int, Nday int, ttmm int, Wd *WDAT, Exs []*EXSF,
 	}
 
 	// 月集計
-	__Wdtsum_hrsm++
+	sim.__Wdtsum_hrsm++
 	Wdm.T += Wd.T
 	Wdm.X += Wd.X
 	Wdm.Wv += Wd.Wv
@@ -113,47 +108,51 @@ func Wdtsum(Mon int, Day int, Nday int, ttmm int, Wd *WDAT, Exs []*EXSF,
 	// 日の終わりの処理
 	if ttmm == 2400 {
 		// 気温、絶対湿度、風速を平均値に変換
-		Wdd.T /= float64(__Wdtsum_hrs)
-		Wdd.X /= float64(__Wdtsum_hrs)
-		Wdd.Wv /= float64(__Wdtsum_hrs)
+		Wdd.T /= float64(sim.__Wdtsum_hrs)
+		Wdd.X /= float64(sim.__Wdtsum_hrs)
+		Wdd.Wv /= float64(sim.__Wdtsum_hrs)
 
 		// 日射、ふく射を単位変換
-		Wdd.Idn *= __Wdtsum_cffWh
-		Wdd.Isky *= __Wdtsum_cffWh
-		Wdd.RN *= __Wdtsum_cffWh
+		Wdd.Idn *= sim.__Wdtsum_cffWh
+		Wdd.Isky *= sim.__Wdtsum_cffWh
+		Wdd.RN *= sim.__Wdtsum_cffWh
 
 		// 外表面ごとの日射量または温度
 		for i, e := range Exs {
 			if e.Typ != EXSFType_E && e.Typ != EXSFType_e {
 				// 日射量の単位変換
-				Soldy[i] *= __Wdtsum_cffWh
+				Soldy[i] *= sim.__Wdtsum_cffWh
 			} else {
 				// 温度を平均化
-				Soldy[i] /= float64(__Wdtsum_hrs)
+				Soldy[i] /= float64(sim.__Wdtsum_hrs)
 			}
 		}
 	}
 
 	// 月の終わりの処理
-	if IsEndDay(Mon, Day, Nday, Simc.Dayend) && __Wdtsum_hrsm > 0 && ttmm == 2400 {
+	dayend := Simc.Dayend
+	if Simc.BaseYear > 0 {
+		dayend = Simc.dateKey(Simc.Dayend)
+	}
+	if IsEndDay(Mon, Day, Nday, dayend) && sim.__Wdtsum_hrsm > 0 && ttmm == 2400 {
 		// 気温、絶対湿度、風速を平均値に変換
-		Wdm.T /= float64(__Wdtsum_hrsm)
-		Wdm.X /= float64(__Wdtsum_hrsm)
-		Wdm.Wv /= float64(__Wdtsum_hrsm)
+		Wdm.T /= float64(sim.__Wdtsum_hrsm)
+		Wdm.X /= float64(sim.__Wdtsum_hrsm)
+		Wdm.Wv /= float64(sim.__Wdtsum_hrsm)
 
 		// 日射、ふく射を単位変換
-		Wdm.Idn *= __Wdtsum_cffWh
-		Wdm.Isky *= __Wdtsum_cffWh
-		Wdm.RN *= __Wdtsum_cffWh
+		Wdm.Idn *= sim.__Wdtsum_cffWh
+		Wdm.Isky *= sim.__Wdtsum_cffWh
+		Wdm.RN *= sim.__Wdtsum_cffWh
 
 		// 外表面ごとの日射量または温度
 		for i, e := range Exs {
 			if e.Typ != EXSFType_E && e.Typ != EXSFType_e {
 				// 日射量の単位変換
-				Solmon[i] *= __Wdtsum_cffWh
+				Solmon[i] *= sim.__Wdtsum_cffWh
 			} else {
 				// 温度を平均化
-				Solmon[i] /= float64(__Wdtsum_hrsm)
+				Solmon[i] /= float64(sim.__Wdtsum_hrsm)
 			}
 		}
 	}
@@ -182,11 +181,12 @@ Wdtdprint (Weather Data Daily Print)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要なデータ出力機能を提供します。
 */
-func Wdtdprint(fo io.Writer, title string, Mon int, Day int, Wdd *WDAT, Exs []*EXSF, Soldy []float64) {
-	if __Wdtdprint_ic == 0 {
-		__Wdtdprint_ic++
+func (sim *Simulation) Wdtdprint(fo io.Writer, title string, Mon int, Day int, Wdd *WDAT, Exs []*EXSF, Soldy []float64) {
+	if sim.__Wdtdprint_ic == 0 {
+		sim.__Wdtdprint_ic++
 		fmt.Fprintf(fo, "%s;\n %d\n", title, len(Exs))
 
+		yearttlprint(fo, sim.Simc)
 		fmt.Fprintf(fo, "Mo\tNd\tWd_T\tWd_x\tWd_Wv\tWd_RN\tWd_Idn\tWd_Isky\t")
 		for _, e := range Exs {
 			fmt.Fprintf(fo, "%s[%c]\t", e.Name, e.Typ)
@@ -194,6 +194,7 @@ func Wdtdprint(fo io.Writer, title string, Mon int, Day int, Wdd *WDAT, Exs []*E
 		fmt.Fprintf(fo, "\n")
 	}
 
+	sim.yearprint(fo, sim.Simc, "\t")
 	fmt.Fprintf(fo, "%d\t%d\t", Mon, Day)
 	fmt.Fprintf(fo, "%.1f\t%.4f\t%.1f\t%.2f\t%.2f\t%4.2f", Wdd.T, Wdd.X, Wdd.Wv, Wdd.RN/1000., Wdd.Idn/1000., Wdd.Isky/1000.)
 
@@ -233,25 +234,25 @@ Wdtprint (Weather Data Time Series Print)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要なデータ出力機能を提供します。
 */
-func Wdtprint(fo io.Writer, title string, Mon, Day int, time float64, Wd *WDAT, Exsfst *EXSFS) {
+func (sim *Simulation) Wdtprint(fo io.Writer, title string, Mon, Day int, time float64, Wd *WDAT, Exsfst *EXSFS) {
 	var Nexs, i int
 	Nexs = len(Exsfst.Exs)
 
-	if DEBUG {
+	if sim.debug() {
 		fmt.Printf("N=%d\t%d/%d\t%.2f\n", Nexs, Mon, Day, time)
 		fmt.Printf("%s;\n %d\n", title, Nexs)
 	}
 
 	// ヘッダー部の出力
-	if __Wdtprint_ic == 0 {
-		__Wdtprint_ic++
+	if sim.__Wdtprint_ic == 0 {
+		sim.__Wdtprint_ic++
 		fmt.Fprintf(fo, "%s;\n %d\n", title, Nexs)
 		fmt.Fprintf(fo, "Mon\tDay\tTime\tWd_T\tWd_x\tWd_RH\tWd_Wv\t")
 		fmt.Fprintf(fo, "Wd_Wdre\tWd_RN\tWd_Idn\tWd_Isky\tsolh\tsolA\t")
 
 		for i = 0; i < Nexs; i++ {
 			e := Exsfst.Exs[i]
-			if DEBUG {
+			if sim.debug() {
 				fmt.Printf("%s[%c]\t", e.Name, e.Typ)
 			}
 
@@ -304,11 +305,12 @@ Wdtmprint (Weather Data Monthly Print)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要なデータ出力機能を提供します。
 */
-func Wdtmprint(fo io.Writer, title string, Mon, Day int, Wdm *WDAT, Exs []*EXSF, Solmon []float64) {
-	if __Wdtmprint_ic == 0 {
-		__Wdtmprint_ic++
+func (sim *Simulation) Wdtmprint(fo io.Writer, title string, Mon, Day int, Wdm *WDAT, Exs []*EXSF, Solmon []float64) {
+	if sim.__Wdtmprint_ic == 0 {
+		sim.__Wdtmprint_ic++
 		fmt.Fprintf(fo, "%s;\n%d\n", title, len(Exs))
 
+		yearttlprint(fo, sim.Simc)
 		fmt.Fprintf(fo, "Mo\tNd\tWd_T\tWd_x\tWd_Wv\tWd_RN\tWd_Idn\tWd_Isky\t")
 		for _, e := range Exs {
 			fmt.Fprintf(fo, "%s[%c]\t", e.Name, e.Typ)
@@ -316,6 +318,7 @@ func Wdtmprint(fo io.Writer, title string, Mon, Day int, Wdm *WDAT, Exs []*EXSF,
 		fmt.Fprintln(fo)
 	}
 
+	sim.yearprint(fo, sim.Simc, "\t")
 	fmt.Fprintf(fo, "%d\t%d\t", Mon, Day)
 	fmt.Fprintf(fo, "%.1f\t%.4f\t%.1f\t%.2f\t%.2f\t%4.2f",
 		Wdm.T, Wdm.X, Wdm.Wv, Wdm.RN/1000., Wdm.Idn/1000., Wdm.Isky/1000.)
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"math"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -60,15 +59,6 @@ func monthday(mo int, dayo int) (int, int) {
 }
 
 /*  気象デ－タの入力     */
-var (
-	Lat, Slat, Clat, Tlat, Lon, Ls, Isc float64
-)
-
-var __Weatherdt_ptt int = 25
-var __Weatherdt_nc int = 0
-var __Weatherdt_decl, __Weatherdt_E, __Weatherdt_tas, __Weatherdt_timedg float64
-var __Weatherdt_dt [7][25]float64
-var __Weatherdt_dtL [7][25]float64
 
 /*
 Weatherdt (Weather Data Processing)
@@ -81,7 +71,7 @@ Weatherdt (Weather Data Processing)
   - **気象データの読み込みと処理**: 建物のエネルギーシミュレーションでは、
     外気温度、湿度、日射量などの気象データが不可欠です。
     この関数は、`Simc.Wdtype`（気象データファイル種別）に応じて、
-    HASP標準形式（`hspwdread`）またはVCFILE形式（`Wdflinput`）から気象データを読み込みます。
+    HASP標準形式（`hspwdread`）、EPW（`epwwdread`）またはVCFILE形式（`Wdflinput`）から気象データを読み込みます。
   - **太陽位置の計算**: `FNDecl`（赤緯）、`FNE`（均時差）、`FNTtas`（真太陽時）、
     `Solpos`（太陽高度角、方位角）などの関数を呼び出し、
     現在の時刻における太陽位置を正確に計算します。
@@ -102,75 +92,85 @@ Weatherdt (Weather Data Processing)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func Weatherdt(Simc *SIMCONTL, Daytm *DAYTM, Loc *LOCAT, Wd *WDAT, Exs []*EXSF, EarthSrfFlg bool) {
+func (sim *Simulation) Weatherdt(Simc *SIMCONTL, Daytm *DAYTM, Loc *LOCAT, Wd *WDAT, Exs []*EXSF, EarthSrfFlg bool) {
 	var tt, Mon, Day int
 
 	tt = Daytm.Tt
 
-	if tt < __Weatherdt_ptt {
-		if Simc.Wdtype == 'H' {
+	if tt < sim.__Weatherdt_ptt {
+		if Simc.Wdtype == 'H' || Simc.Wdtype == 'P' || Simc.Wdtype == 'A' {
 			if Simc.DTm < 3600 {
-				_, Mon, Day, _ = hspwdread(Simc.Fwdata, Daytm.DayOfYear-1, Loc, &__Weatherdt_dtL)
+				dayL := Daytm.DayOfYear - 1
+				if Daytm.Mon == 2 && Daytm.Day == 29 {
+					dayL = Daytm.DayOfYear // 前日は 2/28
+				}
+				_, Mon, Day, _ = sim.wdread(Simc, Simc.Fwdata, dayL, Loc, &sim.__Weatherdt_dtL)
 				fmt.Printf("Mon=%d  Day=%d\n", Mon, Day)
 			}
 
-			_, Mon, Day, _ = hspwdread(Simc.Fwdata, Daytm.DayOfYear, Loc, &__Weatherdt_dt)
-			if Daytm.Mon != Mon || Daytm.Day != Day {
+			_, Mon, Day, _ = sim.wdread(Simc, Simc.Fwdata, Daytm.DayOfYear, Loc, &sim.__Weatherdt_dt)
+			// 実暦による計算の 2/29 は 2/28 の気象データを用いる
+			if Daytm.Mon != Mon || Daytm.Day != Day && !(Daytm.Mon == 2 && Daytm.Day == 29 && Day == 28) {
 				s := fmt.Sprintf("loop Mon/Day=%d/%d - data Mon/Day=%d/%d", Daytm.Mon, Daytm.Day, Mon, Day)
-				Eprint("<Weatherdt>", s)
-				Preexit()
-				os.Exit(EXIT_MOND)
+				Eprint("<Weatherdt>", s, sim.Ferr)
+				panic(&WeatherError{Section: "GDAT", Keyword: "Weatherdt", Component: Simc.Wfname, Msg: s, Code: EXIT_MOND})
 			}
 		}
 
-		if __Weatherdt_nc == 0 {
-			Lat = Loc.Lat
-			Lon = Loc.Lon
-			Ls = Loc.Ls
-			Sunint()
-			Psyint()
-			if Simc.Wdtype == 'H' {
-				gtsupw(Simc.Ftsupw, Loc.Name, &(Loc.Daymxert), &(Loc.Tgrav), &(Loc.DTgr), &Loc.Twsup)
+		if sim.__Weatherdt_nc == 0 {
+			Loc.Sunint()
+			if Simc.Wdtype == 'P' || Simc.Wdtype == 'A' || Simc.Wdtype == 'C' {
+				if Simc.Wdtype == 'P' {
+					sim.__epwwdread_epw.ground(Loc)
+				} else if Simc.Wdtype == 'A' {
+					sim.__amdwdread_amd.ground(Loc)
+				}
+				sim.Intgtsup(1, Loc.Twsup[:])
+			} else if Simc.Wdtype == 'H' {
+				sim.gtsupw(Simc.Ftsupw, Loc.Name, &(Loc.Daymxert), &(Loc.Tgrav), &(Loc.DTgr), &Loc.Twsup)
 
-				Intgtsup(1, Loc.Twsup[:])
+				sim.Intgtsup(1, Loc.Twsup[:])
 			}
 
 			if EarthSrfFlg {
 				Wd.EarthSurface = make([]float64, 366*25)
-				EarthSrfTempInit(Simc, Loc, Wd)
+				sim.EarthSrfTempInit(Simc, Loc, Wd)
 			}
-			__Weatherdt_nc = 1
+			sim.__Weatherdt_nc = 1
 		}
 
-		__Weatherdt_decl = FNDecl(Daytm.DayOfYear)
-		__Weatherdt_E = FNE(Daytm.DayOfYear)
+		sim.__Weatherdt_decl = FNDecl(Daytm.DayOfYear)
+		sim.__Weatherdt_E = FNE(Daytm.DayOfYear)
 
 		if Wd.Intgtsupw == 'N' {
 			Wd.Twsup = Loc.Twsup[Daytm.Mon-1]
 		} else {
-			Wd.Twsup = Intgtsup(Daytm.DayOfYear, Loc.Twsup[:])
+			Wd.Twsup = sim.Intgtsup(Daytm.DayOfYear, Loc.Twsup[:])
 		}
 	}
 
 	Exsfter(Daytm.DayOfYear, Loc.Daymxert, Loc.Tgrav, Loc.DTgr, Exs, Wd, tt)
-	__Weatherdt_timedg = float64(Daytm.Tt) + math.Mod(float64(Daytm.Ttmm), 100.0)/60.0
+	sim.__Weatherdt_timedg = float64(Daytm.Tt) + math.Mod(float64(Daytm.Ttmm), 100.0)/60.0
 
-	__Weatherdt_tas = FNTtas(__Weatherdt_timedg, __Weatherdt_E, Lon, Ls)
-	Wd.Sh, Wd.Sw, Wd.Ss, Wd.Solh, Wd.SolA = Solpos(__Weatherdt_tas, __Weatherdt_decl)
+	sim.__Weatherdt_tas = FNTtas(sim.__Weatherdt_timedg, sim.__Weatherdt_E, Loc.Lon, Loc.Ls)
+	Wd.Sh, Wd.Sw, Wd.Ss, Wd.Solh, Wd.SolA = Loc.Solpos(sim.__Weatherdt_tas, sim.__Weatherdt_decl)
 
-	if Simc.Wdtype == 'H' {
+	if Simc.Wdtype == 'H' || Simc.Wdtype == 'P' || Simc.Wdtype == 'A' {
 		// 計算時間間隔が1時間未満の場合には直線補完する
 		if Simc.DTm < 3600 {
-			wdatadiv(Daytm, Wd, __Weatherdt_dt, __Weatherdt_dtL)
+			wdatadiv(Daytm, Wd, sim.__Weatherdt_dt, sim.__Weatherdt_dtL)
 		} else {
-			dt2wdata(Wd, tt, __Weatherdt_dt)
+			dt2wdata(Wd, tt, sim.__Weatherdt_dt)
 		}
+	} else if Simc.Wdtype == 'C' {
+		// CSV の気象データの読み込み
+		sim.wcsvinput(Simc, Daytm, Wd)
 	} else {
 		// VCFILE形式の気象データの読み込み
 		Wdflinput(&Simc.Wdpt, Wd)
 	}
 
-	if DEBUG {
+	if Simc.Debug {
 		fmt.Println("\n\n<Weatherdt>  ***** Wdata *****\n\n=================")
 		fmt.Printf("\tT=%.1f\n\tx=%.4f\n\tRH=%.0f\n", Wd.T, Wd.X, Wd.RH)
 		fmt.Printf("\tIdn=%.0f\n\tIsky=%.0f\n\tIhor=%.0f\n", Wd.Idn, Wd.Isky, Wd.Ihor)
@@ -178,19 +178,17 @@ func Weatherdt(Simc *SIMCONTL, Daytm *DAYTM, Loc *LOCAT, Wd *WDAT, Exs []*EXSF,
 		fmt.Printf("\th=%.0f\n==================\n", Wd.H)
 	}
 
-	if dayprn && Ferr != nil {
-		fmt.Fprintln(Ferr, "\n\n<Weatherdt>  ***** Wdata *****\n\n=================")
-		fmt.Fprintf(Ferr, "\tT=%.1f\n\tx=%.4f\n\tRH=%.0f\n", Wd.T, Wd.X, Wd.RH)
-		fmt.Fprintf(Ferr, "\tIdn=%.0f\n\tIsky=%.0f\n\tIhor=%.0f\n", Wd.Idn, Wd.Isky, Wd.Ihor)
-		fmt.Fprintf(Ferr, "\tRN=%.0f\n\tCC=%.0f\n\tWdre=%.0f\n\tWv=%.1f\n", Wd.RN, Wd.CC, Wd.Wdre, Wd.Wv)
-		fmt.Fprintf(Ferr, "\th=%.0f\n==================\n", Wd.H)
+	if sim.dayprn && sim.Ferr != nil {
+		fmt.Fprintln(sim.Ferr, "\n\n<Weatherdt>  ***** Wdata *****\n\n=================")
+		fmt.Fprintf(sim.Ferr, "\tT=%.1f\n\tx=%.4f\n\tRH=%.0f\n", Wd.T, Wd.X, Wd.RH)
+		fmt.Fprintf(sim.Ferr, "\tIdn=%.0f\n\tIsky=%.0f\n\tIhor=%.0f\n", Wd.Idn, Wd.Isky, Wd.Ihor)
+		fmt.Fprintf(sim.Ferr, "\tRN=%.0f\n\tCC=%.0f\n\tWdre=%.0f\n\tWv=%.1f\n", Wd.RN, Wd.CC, Wd.Wdre, Wd.Wv)
+		fmt.Fprintf(sim.Ferr, "\th=%.0f\n==================\n", Wd.H)
 	}
 
-	__Weatherdt_ptt = tt
+	sim.__Weatherdt_ptt = tt
 }
 
-var __gtsupw_ic int
-
 /*
 gtsupw (Ground Temperature for Supply Water)
 
@@ -219,11 +217,11 @@ gtsupw (Ground Temperature for Supply Water)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func gtsupw(fp []byte, loc string, nmx *int, Tgrav, DTgr *float64, Tsupw *[12]float64) {
+func (sim *Simulation) gtsupw(fp []byte, loc string, nmx *int, Tgrav, DTgr *float64, Tsupw *[12]float64) {
 	var flg int
 	var s string
 
-	if __gtsupw_ic == 0 {
+	if sim.__gtsupw_ic == 0 {
 		reader := strings.NewReader(string(fp))
 		scanner := bufio.NewScanner(reader)
 		scanner.Split(bufio.ScanWords)
@@ -240,8 +238,8 @@ func gtsupw(fp []byte, loc string, nmx *int, Tgrav, DTgr *float64, Tsupw *[12]fl
 		// Check error
 		if scanner.Err() != nil {
 			E := fmt.Sprintf("supw.eflに%sが登録されていません。\n", s)
-			Eprint("<gtsupw>", E)
-			os.Exit(EXIT_GTSUPW)
+			Eprint("<gtsupw>", E, sim.Ferr)
+			panic(&WeatherError{Section: "GDAT", Keyword: loc, Component: "supw.efl", Msg: "location not registered", Code: EXIT_GTSUPW})
 		}
 
 		// Read data of 12 months and nmx, Tgrav, DTgr
@@ -259,19 +257,17 @@ func gtsupw(fp []byte, loc string, nmx *int, Tgrav, DTgr *float64, Tsupw *[12]fl
 		*Tgrav = values[13]
 		*DTgr = values[14]
 
-		__gtsupw_ic = 1
+		sim.__gtsupw_ic = 1
 
 		if flg == 0 {
 			E := fmt.Sprintf("supw.eflに%sが登録されていません。\n", s)
-			Eprint("<gtsupw>", E)
-			os.Exit(1)
+			Eprint("<gtsupw>", E, sim.Ferr)
+			panic(&WeatherError{Section: "GDAT", Keyword: loc, Component: "supw.efl", Msg: "location not registered", Code: EXIT_GTSUPW})
 		}
 	}
 }
 
 /*   HASP標準気象デ－タファイルからの入力   */
-var __hspwdread_ic int
-var __hspwdread_recl int
 
 /*
 hspwdread (HASP Weather Data Read)
@@ -301,7 +297,7 @@ hspwdread (HASP Weather Data Read)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func hspwdread(fp io.ReadSeeker, nday int, Loc *LOCAT, dt *[7][25]float64) (year int, mon int, day int, wkdy int) {
+func (sim *Simulation) hspwdread(fp io.ReadSeeker, nday int, Loc *LOCAT, dt *[7][25]float64) (year int, mon int, day int, wkdy int) {
 	var d, a, b, c float64
 	var k, t int
 	Data := [24][3]byte{}
@@ -315,30 +311,30 @@ func hspwdread(fp io.ReadSeeker, nday int, Loc *LOCAT, dt *[7][25]float64) (year
 		nday = nday + 365
 	}
 
-	if __hspwdread_ic == 0 {
+	if sim.__hspwdread_ic == 0 {
 		// NOTE: 独自のHASPフォーマットになっている
 		fmt.Fscanf(fp, "%s %f %f %f %f %f %f ", &s, &Loc.Lat, &Loc.Lon, &Loc.Ls, &a, &b, &c)
 		Loc.Name = s
 
-		if Ferr != nil {
-			fmt.Fprintf(Ferr, "\n------> <hspwdread> \n")
-			fmt.Fprintf(Ferr, "\nName=%s\tLat=%.4g\tLon=%.4g\tLs=%.4g\ta=%.4g\tb=%.4g\tc=%.4g\n",
+		if sim.Ferr != nil {
+			fmt.Fprintf(sim.Ferr, "\n------> <hspwdread> \n")
+			fmt.Fprintf(sim.Ferr, "\nName=%s\tLat=%.4g\tLon=%.4g\tLs=%.4g\ta=%.4g\tb=%.4g\tc=%.4g\n",
 				Loc.Name, Loc.Lat, Loc.Lon, Loc.Ls, a, b, c)
 		}
 
 		//改行コードの判定
 		fp.Seek(0, io.SeekEnd)
 		fsize, _ := fp.Seek(0, io.SeekCurrent)
-		__hspwdread_recl = int(fsize / 2556)
+		sim.__hspwdread_recl = int(fsize / 2556)
 		//recl=82 -> Windows (CRLF)
 		//recl=81 -> Linux/Mac(CR or LF)
 	}
 
-	if __hspwdread_ic != nday {
-		fp.Seek(int64(__hspwdread_recl*7*(nday-1)+__hspwdread_recl), 0)
+	if sim.__hspwdread_ic != nday {
+		fp.Seek(int64(sim.__hspwdread_recl*7*(nday-1)+sim.__hspwdread_recl), 0)
 	}
 
-	if __hspwdread_ic > 0 {
+	if sim.__hspwdread_ic > 0 {
 		for k = 0; k < 7; k++ {
 			dt[k][0] = dt[k][24]
 		}
@@ -381,14 +377,14 @@ func hspwdread(fp io.ReadSeeker, nday int, Loc *LOCAT, dt *[7][25]float64) (year
 	day, _ = strconv.Atoi(strings.TrimSpace(string(Day[:])))
 	wkdy, _ = strconv.Atoi(strings.TrimSpace(string(Wk[:])))
 
-	if __hspwdread_ic == 0 {
+	if sim.__hspwdread_ic == 0 {
 		for k = 0; k < 7; k++ {
 			dt[k][0] = dt[k][1]
 		}
 	}
-	__hspwdread_ic = nday + 1
+	sim.__hspwdread_ic = nday + 1
 
-	if DEBUG {
+	if sim.debug() {
 		for t = 0; t < 25; t++ {
 			fmt.Printf("%2d %5.1f %6.4f %5.0f %5.0f %2.0f %2.0f %5.1f\n", t, dt[0][t], dt[1][t], dt[2][t], dt[3][t], dt[4][t], dt[5][t], dt[6][t])
 		}
@@ -397,6 +393,54 @@ func hspwdread(fp io.ReadSeeker, nday int, Loc *LOCAT, dt *[7][25]float64) (year
 	return year, mon, day, wkdy
 }
 
+// wdGround は日平均気温 Td（通日 nday の値）から月平均給水温度と地中温度の係数を求めます。
+// supw.efl に地点がない気象データ（EPW、CSV）に用います。
+//   - 給水温度: 月平均気温。ただし 5℃ 以上とし、値のない月は年平均気温とする
+//   - nmax: 日平均気温が年最高の日の通日
+//   - Tgro: 年平均気温
+//   - Dtgr: 日平均気温の年較差
+func wdGround(Loc *LOCAT, nday []int, Td []float64) {
+	var msum [12]float64
+	var mn [12]int
+	var ysum float64
+	dmax, dmin := -math.MaxFloat64, math.MaxFloat64
+	for i, T := range Td {
+		m := 11
+		for m > 0 && nday[i] < FNNday(m+1, 1) {
+			m--
+		}
+		msum[m] += T
+		mn[m]++
+		ysum += T
+		if T > dmax {
+			dmax = T
+			Loc.Daymxert = nday[i]
+		}
+		dmin = math.Min(dmin, T)
+	}
+	Loc.Tgrav = ysum / float64(len(Td))
+	Loc.DTgr = dmax - dmin
+	for m := range Loc.Twsup {
+		Loc.Twsup[m] = Loc.Tgrav
+		if mn[m] > 0 {
+			Loc.Twsup[m] = msum[m] / float64(mn[m])
+		}
+		Loc.Twsup[m] = math.Max(5.0, Loc.Twsup[m])
+	}
+}
+
+// wdre16 は北から時計回りの風向 deg [deg] を 16方位（1:NNE ～ 16:N）とします。風速 Wv が 0 の場合は 0（静穏）とします。
+func wdre16(deg, Wv float64) float64 {
+	if Wv <= 0.0 {
+		return 0.0
+	}
+	n := math.Round(math.Mod(math.Mod(deg, 360.0)+360.0, 360.0) / 22.5)
+	if n == 0.0 {
+		n = 16.0
+	}
+	return n
+}
+
 /*
 dt2wdata (Data to Weather Data Conversion)
 
@@ -565,7 +609,7 @@ EarthSrfTempInit (Earth Surface Temperature Initialization)
 熱負荷計算、エネルギー消費量予測、
 および省エネルギー対策の検討を行うための重要な役割を果たします。
 */
-func EarthSrfTempInit(Simc *SIMCONTL, Loc *LOCAT, Wd *WDAT) {
+func (sim *Simulation) EarthSrfTempInit(Simc *SIMCONTL, Loc *LOCAT, Wd *WDAT) {
 	var decl, E, ac, Te, tas float64
 	var year, nday, tt int
 	//var wkdy, Year, Mon, Day int
@@ -615,7 +659,7 @@ func EarthSrfTempInit(Simc *SIMCONTL, Loc *LOCAT, Wd *WDAT) {
 
 	//matfprint("%.2lf ", 20,U )
 	// Uの逆行列の計算
-	Matinv(U, 20, 20, "<EarthSrfTempInit>")
+	Matinv(U, 20, 20, "<EarthSrfTempInit>", sim.Ferr)
 
 	// １年目は助走期間
 	for year = 0; year < 2; year++ {
@@ -623,14 +667,14 @@ func EarthSrfTempInit(Simc *SIMCONTL, Loc *LOCAT, Wd *WDAT) {
 			decl = FNDecl(nday)
 			E = FNE(nday)
 
-			hspwdread(Simc.Fwdata2, nday, Loc, &dt)
+			sim.wdread(Simc, Simc.Fwdata2, nday, Loc, &dt)
 
 			for tt = 1; tt <= 24; tt++ {
 				matinit(T, 20)
 
-				tas = FNTtas(float64(tt), E, Lon, Ls)
+				tas = FNTtas(float64(tt), E, Loc.Lon, Loc.Ls)
 				var Sh float64
-				Sh, _, _, Wd.Solh, Wd.Solh = Solpos(tas, decl)
+				Sh, _, _, Wd.Solh, Wd.Solh = Loc.Solpos(tas, decl)
 				dt2wdata(Wd, tt, dt)
 
 				Ihol = Wd.Idn*Sh + Wd.Isky
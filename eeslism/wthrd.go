@@ -39,6 +39,7 @@ type LOCAT struct {
 	Lat  float64 // 緯度[deg]
 	Lon  float64 // 経度[deg]
 	Ls   float64 // 標準子午線[deg]
+	Elev float64 // 標高[m]（EPW、拡張アメダスの場合のみ）
 
 	// 地中温度計算用
 	Daymxert int
@@ -47,6 +48,18 @@ type LOCAT struct {
 
 	// 月毎の給水温度
 	Twsup [12]float64
+
+	// 太陽位置計算用の定数（Sunintで設定）
+	Slat float64 // 緯度の正弦
+	Clat float64 // 緯度の余弦
+	Tlat float64 // 緯度の正接
+	Isc  float64 // 太陽定数[W/m2]
+
+	// Solposで前回計算した赤緯関連の値
+	__Solpos_Sdecl  float64
+	__Solpos_Sld    float64
+	__Solpos_Cld    float64
+	__Solpos_Ttprev float64
 }
 
 // 気象デ－タ
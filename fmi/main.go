@@ -0,0 +1,438 @@
+/*
+fmi パッケージは、EESLISM を FMI 2.0 Co-Simulation の FMU として実行するための共有ライブラリです。
+
+	go build -buildmode=c-shared -o eeslism_fmu.so ./fmi
+
+でビルドし、`eeslism fmu` で入力データファイルとともに FMU に格納します。
+計算は eeslism.FMUInstance が行い、このパッケージは FMI 2.0 の C の関数を提供します。
+
+FMU の状態の保存・復元、方向微分、入力の微分値の設定には対応していません。
+*/
+package main
+
+/*
+#include <stdlib.h>
+#include <stddef.h>
+
+typedef void*        fmi2Component;
+typedef void*        fmi2ComponentEnvironment;
+typedef void*        fmi2FMUstate;
+typedef unsigned int fmi2ValueReference;
+typedef double       fmi2Real;
+typedef int          fmi2Integer;
+typedef int          fmi2Boolean;
+typedef char         fmi2Char;
+typedef const fmi2Char* fmi2String;
+typedef char         fmi2Byte;
+
+typedef enum { fmi2OK, fmi2Warning, fmi2Discard, fmi2Error, fmi2Fatal, fmi2Pending } fmi2Status;
+typedef enum { fmi2ModelExchange, fmi2CoSimulation } fmi2Type;
+typedef enum { fmi2DoStepStatus, fmi2PendingStatus, fmi2LastSuccessfulTime, fmi2Terminated } fmi2StatusKind;
+
+typedef void (*fmi2CallbackLogger)(fmi2ComponentEnvironment, fmi2String, fmi2Status, fmi2String, fmi2String, ...);
+typedef void* (*fmi2CallbackAllocateMemory)(size_t, size_t);
+typedef void (*fmi2CallbackFreeMemory)(void*);
+typedef void (*fmi2StepFinished)(fmi2ComponentEnvironment, fmi2Status);
+
+typedef struct {
+	const fmi2CallbackLogger         logger;
+	const fmi2CallbackAllocateMemory allocateMemory;
+	const fmi2CallbackFreeMemory     freeMemory;
+	const fmi2StepFinished           stepFinished;
+	const fmi2ComponentEnvironment   componentEnvironment;
+} fmi2CallbackFunctions;
+
+static void fmiLog(const fmi2CallbackFunctions *f, fmi2String instanceName, fmi2Status status, fmi2String msg) {
+	if (f != NULL && f->logger != NULL) {
+		f->logger(f->componentEnvironment, instanceName, status, "logError", "%s", msg);
+	}
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"github.com/archlabjp/eeslism-go/eeslism"
+)
+
+// component は fmi2Instantiate で作成したインスタンスです。
+type component struct {
+	name      *C.char
+	functions *C.fmi2CallbackFunctions
+	fi        *eeslism.FMUInstance
+	time      float64
+	stopped   bool
+}
+
+var (
+	mu         sync.Mutex
+	components = make(map[C.fmi2Component]*component)
+)
+
+func lookup(c C.fmi2Component) *component {
+	mu.Lock()
+	defer mu.Unlock()
+	return components[c]
+}
+
+// status はエラーをログに出力して FMI の状態を返します。
+func (comp *component) status(err error) C.fmi2Status {
+	if err == nil {
+		return C.fmi2OK
+	}
+	msg := C.CString(err.Error())
+	defer C.free(unsafe.Pointer(msg))
+	C.fmiLog(comp.functions, comp.name, C.fmi2Error, msg)
+	return C.fmi2Error
+}
+
+// resourcesDir は fmuResourceLocation（file: の URI）をディレクトリ名に変換します。
+func resourcesDir(location string) string {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "file" {
+		return location
+	}
+	p := u.Path
+	if u.Opaque != "" {
+		p, _ = url.PathUnescape(u.Opaque)
+	}
+	// Windows の file:///C:/... 形式
+	if len(p) > 2 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
+
+//export fmi2GetTypesPlatform
+func fmi2GetTypesPlatform() *C.char {
+	return typesPlatform
+}
+
+//export fmi2GetVersion
+func fmi2GetVersion() *C.char {
+	return version
+}
+
+var (
+	typesPlatform = C.CString("default")
+	version       = C.CString("2.0")
+)
+
+//export fmi2SetDebugLogging
+func fmi2SetDebugLogging(c C.fmi2Component, loggingOn C.fmi2Boolean, nCategories C.size_t, categories *C.fmi2String) C.fmi2Status {
+	return C.fmi2OK
+}
+
+//export fmi2Instantiate
+func fmi2Instantiate(instanceName C.fmi2String, fmuType C.fmi2Type, fmuGUID C.fmi2String, fmuResourceLocation C.fmi2String, functions *C.fmi2CallbackFunctions, visible C.fmi2Boolean, loggingOn C.fmi2Boolean) C.fmi2Component {
+	comp := &component{
+		name:      C.CString(C.GoString(instanceName)),
+		functions: functions,
+	}
+	if fmuType != C.fmi2CoSimulation {
+		comp.status(errors.New("eeslism: only co-simulation is supported"))
+		C.free(unsafe.Pointer(comp.name))
+		return nil
+	}
+
+	fi, err := eeslism.NewFMUInstance(resourcesDir(C.GoString(fmuResourceLocation)), C.GoString(fmuGUID))
+	if err != nil {
+		comp.status(err)
+		C.free(unsafe.Pointer(comp.name))
+		return nil
+	}
+	comp.fi = fi
+
+	// C 側に渡すハンドル
+	c := C.fmi2Component(C.malloc(1))
+	mu.Lock()
+	components[c] = comp
+	mu.Unlock()
+	return c
+}
+
+//export fmi2FreeInstance
+func fmi2FreeInstance(c C.fmi2Component) {
+	mu.Lock()
+	comp := components[c]
+	delete(components, c)
+	mu.Unlock()
+	if comp == nil {
+		return
+	}
+	comp.fi.Free()
+	C.free(unsafe.Pointer(comp.name))
+	C.free(unsafe.Pointer(c))
+}
+
+//export fmi2SetupExperiment
+func fmi2SetupExperiment(c C.fmi2Component, toleranceDefined C.fmi2Boolean, tolerance C.fmi2Real, startTime C.fmi2Real, stopTimeDefined C.fmi2Boolean, stopTime C.fmi2Real) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	if startTime != 0 {
+		return comp.status(errors.New("eeslism: startTime must be 0"))
+	}
+	return C.fmi2OK
+}
+
+//export fmi2EnterInitializationMode
+func fmi2EnterInitializationMode(c C.fmi2Component) C.fmi2Status {
+	if lookup(c) == nil {
+		return C.fmi2Error
+	}
+	return C.fmi2OK
+}
+
+//export fmi2ExitInitializationMode
+func fmi2ExitInitializationMode(c C.fmi2Component) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	return comp.status(comp.fi.Init())
+}
+
+//export fmi2Terminate
+func fmi2Terminate(c C.fmi2Component) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	return comp.status(comp.fi.Terminate())
+}
+
+//export fmi2Reset
+func fmi2Reset(c C.fmi2Component) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	return comp.status(errors.New("eeslism: fmi2Reset is not supported"))
+}
+
+//export fmi2GetReal
+func fmi2GetReal(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2Real) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	vrs := unsafe.Slice(vr, nvr)
+	values := unsafe.Slice(value, nvr)
+	for i := range vrs {
+		v, err := comp.fi.Get(uint32(vrs[i]))
+		if err != nil {
+			return comp.status(err)
+		}
+		values[i] = C.fmi2Real(v)
+	}
+	return C.fmi2OK
+}
+
+//export fmi2GetInteger
+func fmi2GetInteger(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2Integer) C.fmi2Status {
+	return unsupported(c, nvr, "integer")
+}
+
+//export fmi2GetBoolean
+func fmi2GetBoolean(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2Boolean) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	vrs := unsafe.Slice(vr, nvr)
+	values := unsafe.Slice(value, nvr)
+	for i := range vrs {
+		v, err := comp.fi.Get(uint32(vrs[i]))
+		if err != nil {
+			return comp.status(err)
+		}
+		values[i] = 0
+		if v != 0 {
+			values[i] = 1
+		}
+	}
+	return C.fmi2OK
+}
+
+//export fmi2GetString
+func fmi2GetString(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2String) C.fmi2Status {
+	return unsupported(c, nvr, "string")
+}
+
+//export fmi2SetReal
+func fmi2SetReal(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2Real) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	vrs := unsafe.Slice(vr, nvr)
+	values := unsafe.Slice(value, nvr)
+	for i := range vrs {
+		if err := comp.fi.Set(uint32(vrs[i]), float64(values[i])); err != nil {
+			return comp.status(err)
+		}
+	}
+	return C.fmi2OK
+}
+
+//export fmi2SetInteger
+func fmi2SetInteger(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2Integer) C.fmi2Status {
+	return unsupported(c, nvr, "integer")
+}
+
+//export fmi2SetBoolean
+func fmi2SetBoolean(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2Boolean) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	vrs := unsafe.Slice(vr, nvr)
+	values := unsafe.Slice(value, nvr)
+	for i := range vrs {
+		x := 0.0
+		if values[i] != 0 {
+			x = 1
+		}
+		if err := comp.fi.Set(uint32(vrs[i]), x); err != nil {
+			return comp.status(err)
+		}
+	}
+	return C.fmi2OK
+}
+
+//export fmi2SetString
+func fmi2SetString(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, value *C.fmi2String) C.fmi2Status {
+	return unsupported(c, nvr, "string")
+}
+
+//export fmi2GetFMUstate
+func fmi2GetFMUstate(c C.fmi2Component, state *C.fmi2FMUstate) C.fmi2Status {
+	return notSupported(c, "fmi2GetFMUstate")
+}
+
+//export fmi2SetFMUstate
+func fmi2SetFMUstate(c C.fmi2Component, state C.fmi2FMUstate) C.fmi2Status {
+	return notSupported(c, "fmi2SetFMUstate")
+}
+
+//export fmi2FreeFMUstate
+func fmi2FreeFMUstate(c C.fmi2Component, state *C.fmi2FMUstate) C.fmi2Status {
+	return notSupported(c, "fmi2FreeFMUstate")
+}
+
+//export fmi2SerializedFMUstateSize
+func fmi2SerializedFMUstateSize(c C.fmi2Component, state C.fmi2FMUstate, size *C.size_t) C.fmi2Status {
+	return notSupported(c, "fmi2SerializedFMUstateSize")
+}
+
+//export fmi2SerializeFMUstate
+func fmi2SerializeFMUstate(c C.fmi2Component, state C.fmi2FMUstate, serializedState *C.fmi2Byte, size C.size_t) C.fmi2Status {
+	return notSupported(c, "fmi2SerializeFMUstate")
+}
+
+//export fmi2DeSerializeFMUstate
+func fmi2DeSerializeFMUstate(c C.fmi2Component, serializedState *C.fmi2Byte, size C.size_t, state *C.fmi2FMUstate) C.fmi2Status {
+	return notSupported(c, "fmi2DeSerializeFMUstate")
+}
+
+//export fmi2GetDirectionalDerivative
+func fmi2GetDirectionalDerivative(c C.fmi2Component, vUnknownRef *C.fmi2ValueReference, nUnknown C.size_t, vKnownRef *C.fmi2ValueReference, nKnown C.size_t, dvKnown *C.fmi2Real, dvUnknown *C.fmi2Real) C.fmi2Status {
+	return notSupported(c, "fmi2GetDirectionalDerivative")
+}
+
+//export fmi2SetRealInputDerivatives
+func fmi2SetRealInputDerivatives(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, order *C.fmi2Integer, value *C.fmi2Real) C.fmi2Status {
+	return notSupported(c, "fmi2SetRealInputDerivatives")
+}
+
+//export fmi2GetRealOutputDerivatives
+func fmi2GetRealOutputDerivatives(c C.fmi2Component, vr *C.fmi2ValueReference, nvr C.size_t, order *C.fmi2Integer, value *C.fmi2Real) C.fmi2Status {
+	return notSupported(c, "fmi2GetRealOutputDerivatives")
+}
+
+//export fmi2DoStep
+func fmi2DoStep(c C.fmi2Component, currentCommunicationPoint C.fmi2Real, communicationStepSize C.fmi2Real, noSetFMUStatePriorToCurrentPoint C.fmi2Boolean) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	err := comp.fi.DoStep(float64(currentCommunicationPoint), float64(communicationStepSize))
+	if err != nil {
+		comp.stopped = true
+		return comp.status(err)
+	}
+	comp.time = float64(currentCommunicationPoint + communicationStepSize)
+	return C.fmi2OK
+}
+
+//export fmi2CancelStep
+func fmi2CancelStep(c C.fmi2Component) C.fmi2Status {
+	return notSupported(c, "fmi2CancelStep")
+}
+
+//export fmi2GetStatus
+func fmi2GetStatus(c C.fmi2Component, s C.fmi2StatusKind, value *C.fmi2Status) C.fmi2Status {
+	return notSupported(c, "fmi2GetStatus")
+}
+
+//export fmi2GetRealStatus
+func fmi2GetRealStatus(c C.fmi2Component, s C.fmi2StatusKind, value *C.fmi2Real) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil || s != C.fmi2LastSuccessfulTime {
+		return C.fmi2Discard
+	}
+	*value = C.fmi2Real(comp.time)
+	return C.fmi2OK
+}
+
+//export fmi2GetIntegerStatus
+func fmi2GetIntegerStatus(c C.fmi2Component, s C.fmi2StatusKind, value *C.fmi2Integer) C.fmi2Status {
+	return C.fmi2Discard
+}
+
+//export fmi2GetBooleanStatus
+func fmi2GetBooleanStatus(c C.fmi2Component, s C.fmi2StatusKind, value *C.fmi2Boolean) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil || s != C.fmi2Terminated {
+		return C.fmi2Discard
+	}
+	*value = 0
+	if comp.stopped {
+		*value = 1
+	}
+	return C.fmi2OK
+}
+
+//export fmi2GetStringStatus
+func fmi2GetStringStatus(c C.fmi2Component, s C.fmi2StatusKind, value *C.fmi2String) C.fmi2Status {
+	return C.fmi2Discard
+}
+
+// unsupported は Integer、String の変数に対する呼び出しの状態を返します。この FMU にはこれらの変数はありません。
+func unsupported(c C.fmi2Component, nvr C.size_t, typ string) C.fmi2Status {
+	if nvr == 0 {
+		return C.fmi2OK
+	}
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	return comp.status(errors.New("eeslism: the FMU has no " + typ + " variables"))
+}
+
+func notSupported(c C.fmi2Component, name string) C.fmi2Status {
+	comp := lookup(c)
+	if comp == nil {
+		return C.fmi2Error
+	}
+	return comp.status(errors.New("eeslism: " + name + " is not supported"))
+}
+
+func main() {}
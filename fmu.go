@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+fmuMain (FMU Export Command)
+
+`eeslism fmu` サブコマンドです。入力データファイルと EFL ファイルを
+FMI 2.0 Co-Simulation の FMU（zip ファイル）に書き出します。
+
+入出力変数は CONTL データと同じ名前で指定します。
+例: `eeslism fmu --input Ta --setpoint Room_Tr --output Room_Tr input.txt`
+
+  - `--input`: FMU の入力変数（気象データ、経路の発停など）
+  - `--setpoint`: LOAD で指定した負荷計算の設定値を入力変数にする（FMU の変数名は `LOAD.<名前>`）
+  - `--output`: FMU の出力変数
+  - `--lib`: `go build -buildmode=c-shared ./fmi` でビルドした共有ライブラリ。
+    省略した場合は go コマンドでビルドします。
+*/
+func fmuMain(args []string) {
+	parser := argparse.NewParser("eeslism fmu", "Export an input data file as an FMI 2.0 Co-Simulation FMU")
+
+	filename := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Input data file name"})
+
+	efl_path := parser.String("", "efl", &argparse.Options{
+		Default: "Base",
+		Help:    "EFLファイルのディレクトリ"})
+
+	inputs := parser.StringList("", "input", &argparse.Options{
+		Help: "FMUの入力変数（CONTLと同じ変数名）"})
+
+	setpoints := parser.StringList("", "setpoint", &argparse.Options{
+		Help: "FMUの入力変数にするLOADの設定値（例: Room_Tr）"})
+
+	outputs := parser.StringList("", "output", &argparse.Options{
+		Help: "FMUの出力変数（CONTLと同じ変数名）"})
+
+	fmu := parser.String("o", "fmu", &argparse.Options{
+		Help: "出力するFMUのファイル名"})
+
+	lib := parser.String("", "lib", &argparse.Options{
+		Help: "-buildmode=c-sharedでビルドしたfmiパッケージの共有ライブラリ"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	opt := eeslism.FMUOptions{
+		InFile:  *filename,
+		EflPath: eflPath(*efl_path),
+		Output:  *fmu,
+		Library: *lib,
+	}
+	for _, name := range *inputs {
+		opt.Variables = append(opt.Variables, eeslism.FMUVariable{Name: name, Causality: "input"})
+	}
+	for _, name := range *setpoints {
+		opt.Variables = append(opt.Variables, eeslism.FMUVariable{Name: name, Kind: eeslism.FMUSetpoint, Causality: "input"})
+	}
+	for _, name := range *outputs {
+		opt.Variables = append(opt.Variables, eeslism.FMUVariable{Name: name, Causality: "output"})
+	}
+
+	exitOnError(eeslism.ExportFMU(opt))
+}
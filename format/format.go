@@ -0,0 +1,13 @@
+/*
+Package format は入力データ形式の説明（各データセットの .md）を実行ファイルに埋め込みます。
+
+`eeslism lsp` は、この FS の説明をエディタのホバーと補完に用います。
+*/
+package format
+
+import "embed"
+
+// FS は format ディレクトリの入力データ形式の説明です。
+//
+//go:embed *.md
+var FS embed.FS
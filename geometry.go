@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+geometryMain (Shading Geometry Export Command)
+
+`eeslism geometry` サブコマンドです。入力データファイルの COORDNT、OBS、TREE、POLYGON の面を
+座標変換した後の形状で glTF または Wavefront OBJ に書き出します（eeslism.Simulation.WriteGLTF、WriteOBJ）。
+例: `eeslism geometry house.txt house.gltf`
+
+  - 出力の形式は出力ファイルの拡張子で決まります（.gltf: glTF、.obj: OBJ と同じ名前の .mtl）。
+  - 既定では入力データの読み込み（Init）のみ行い、計算と出力ファイルの書き出しは行いません。
+  - `--shadow`: 計算を最後まで行い、詳細出力日（GDAT PRINT）の毎時の日影面積率を
+    時刻ごとのシーンの頂点色として glTF に含めます。
+  - `--efl`、`--encoding`、`-D NAME=VALUE` は計算の場合と同じです。
+*/
+func geometryMain(args []string) {
+	parser := argparse.NewParser("eeslism geometry", "Export the shading geometry to glTF or Wavefront OBJ")
+
+	filename := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Input data file name"})
+
+	output := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Output file (.gltf, .obj)"})
+
+	efl_path := parser.String("", "efl", &argparse.Options{
+		Default: "Base",
+		Help:    "EFLファイルのディレクトリ"})
+
+	shadow := parser.Flag("", "shadow", &argparse.Options{
+		Help: "計算を行い、詳細出力日の毎時の日影面積率を glTF に含める"})
+
+	encoding := parser.String("", "encoding", &argparse.Options{
+		Default: "auto",
+		Help:    "入力データファイル、EFLファイルの文字コード（auto、utf-8、shift_jis）"})
+
+	defines := parser.StringList("D", "define", &argparse.Options{
+		Help: "パラメータの値 NAME=VALUE（入力データファイルの $define より優先する）"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	ext := strings.ToLower(filepath.Ext(*output))
+	if ext != ".gltf" && ext != ".obj" {
+		exitOnError(fmt.Errorf("%s: unsupported output format (.gltf or .obj is expected)", *output))
+	}
+	if *shadow && ext != ".gltf" {
+		exitOnError(fmt.Errorf("--shadow is supported only for .gltf"))
+	}
+
+	sim := eeslism.NewSimulation(*filename, eflPath(*efl_path))
+	var err error
+	sim.Defines, err = parseDefines(*defines)
+	exitOnError(err)
+	sim.Encoding, sim.GeometryShadow = *encoding, *shadow
+
+	if *shadow {
+		exitOnError(sim.Run())
+	} else {
+		// 計算を行わないため、Init で作成する出力ファイルは書き出さない
+		sim.Output = new(eeslism.MemorySink)
+		exitOnError(sim.Init())
+	}
+
+	if ext == ".gltf" {
+		exitOnError(writeFile(*output, sim.WriteGLTF))
+		return
+	}
+	mtl := strings.TrimSuffix(*output, filepath.Ext(*output)) + ".mtl"
+	exitOnError(writeFile(mtl, func(m io.Writer) error {
+		return writeFile(*output, func(obj io.Writer) error {
+			return sim.WriteOBJ(obj, m, filepath.Base(mtl))
+		})
+	}))
+}
+
+// writeFile はファイル name を作成し、write で書き出します。write がエラーを返した場合はファイルを削除します。
+func writeFile(name string, write func(w io.Writer) error) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(name)
+		return err
+	}
+	return f.Close()
+}
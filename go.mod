@@ -10,6 +10,8 @@ require (
 	github.com/akamensky/argparse v1.4.0
 	github.com/google/go-cmp v0.6.0
 	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b
+	golang.org/x/text v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
 	gotest.tools v2.2.0+incompatible
 )
 
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+importMain (Model Import Command)
+
+`eeslism import` サブコマンドです。BIM ツールなどが書き出したモデルを入力データファイルに変換します。
+例: `eeslism import gbxml house.xml house.txt`、`eeslism import idf office.idf office.txt`
+
+  - `gbxml`: gbXML の Space、Surface、Construction を ROOM、EXSRF、WALL、WINDOW、COORDNT、OBS に変換します
+    （eeslism.ImportGbXML）。
+  - `idf`: EnergyPlus の IDF の Zone、BuildingSurface:Detailed、FenestrationSurface:Detailed、Construction を
+    同様に変換します（eeslism.ImportIDF）。IDF の材料は材料定義リストとして、出力ファイルと同じディレクトリの
+    `<出力ファイル名>_wbmlist.efl`（`--wbmlist` で指定可）に書き出し、WALL の wbmlist= で参照します。
+  - 変換の際の注意（wbmlist.efl に対応する材料がないなど）は標準エラー出力に表示します。
+  - 出力ファイル名が `-` の場合は標準出力に書き出します。`idf` の場合は `--wbmlist` が必要です。
+  - 変換した入力データファイルには機器 (SYSCMP) がありません。計算の前に気象データファイル、計算期間、
+    機器を設定してください。`--weather` は気象データファイル名 (FILE w=) を設定します。
+*/
+func importMain(args []string) {
+	parser := argparse.NewParser("eeslism import", "Import a building model into an input data file")
+
+	format := parser.SelectorPositional([]string{"gbxml", "idf"}, &argparse.Options{
+		Required: true,
+		Help:     "Format of the input file (gbxml, idf)"})
+
+	input := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Input file"})
+
+	output := parser.StringPositional(&argparse.Options{
+		Required: true,
+		Help:     "Output input data file or - for standard output"})
+
+	efl_path := parser.String("", "efl", &argparse.Options{
+		Default: "Base",
+		Help:    "EFLファイルのディレクトリ（材料を wbmlist.efl と照合する）"})
+
+	weather := parser.String("", "weather", &argparse.Options{
+		Help: "気象データファイル名 (FILE w=)"})
+
+	wbmlistFile := parser.String("", "wbmlist", &argparse.Options{
+		Help: "idf: 材料定義リストの出力ファイル名（既定は <出力ファイル名>_wbmlist.efl）"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	in, err := os.Open(*input)
+	exitOnError(err)
+	defer in.Close()
+
+	var m *eeslism.Model
+	var wbmlist []byte
+	var warnings []string
+	switch *format {
+	case "gbxml":
+		m, warnings, err = eeslism.ImportGbXML(in, eflPath(*efl_path))
+	case "idf":
+		if *wbmlistFile == "" {
+			if *output == "-" {
+				exitOnError(fmt.Errorf("eeslism import idf: --wbmlist is required when writing to standard output"))
+			}
+			*wbmlistFile = strings.TrimSuffix(*output, filepath.Ext(*output)) + "_wbmlist.efl"
+		}
+		m, wbmlist, warnings, err = eeslism.ImportIDF(in, eflPath(*efl_path))
+	}
+	exitOnError(err)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", *input, w)
+	}
+	if *weather != "" {
+		m.Weather = *weather
+	}
+	if wbmlist != nil {
+		// 材料定義リストは入力データファイルのディレクトリから探す
+		exitOnError(os.WriteFile(*wbmlistFile, wbmlist, 0o644))
+		m.Wbmlist = filepath.Base(*wbmlistFile)
+		if *output != "-" {
+			if rel, err := filepath.Rel(filepath.Dir(*output), *wbmlistFile); err == nil {
+				m.Wbmlist = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		out, err := os.Create(*output)
+		exitOnError(err)
+		defer out.Close()
+		w = out
+	}
+	_, err = m.WriteTo(w)
+	exitOnError(err)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+	"github.com/archlabjp/eeslism-go/format"
+)
+
+/*
+lspMain (Language Server Command)
+
+`eeslism lsp` サブコマンドです。入力データファイルを編集するエディタのための
+Language Server Protocol のサーバー（`eeslism.LanguageServer`）を標準入出力で起動します。
+エディタの設定で、入力データファイルの言語サーバーのコマンドを `eeslism lsp` とします。
+
+診断、データセット名・パラメータ名・定義された名前の補完、壁体名などから定義への移動、
+入力データ形式の説明（埋め込みの format/*.md）のホバーを提供します。
+標準出力は LSP の通信に用いるため、パッケージの表示は標準エラー出力に書き出します。
+*/
+func lspMain(args []string) {
+	parser := argparse.NewParser("eeslism lsp", "Run the language server for input data files on standard input/output")
+
+	parser.Flag("", "stdio", &argparse.Options{
+		Help: "標準入出力で通信する（既定）"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	exitOnError(eeslism.NewLanguageServer(format.FS).Serve(os.Stdin, stdout))
+}
@@ -2,9 +2,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/akamensky/argparse"
 	eeslism "github.com/archlabjp/eeslism-go/eeslism"
@@ -23,12 +27,31 @@ main (Main Entry Point of EESLISM Go)
     シミュレーションに必要な全ての情報が記述されたファイルを指定します。
   - `efl_path`: EFL（Energy Flow Language）ファイルのディレクトリ。気象データファイルや、
     機器のカタログデータなどが格納されたディレクトリを指定します。
+    既定の`Base`ディレクトリがない場合は、実行ファイルに埋め込まれた Base を用います。
   これらの引数は、シミュレーションの入力条件を定義し、
   様々な建物のエネルギー性能を評価するための柔軟性を提供します。
-- **シミュレーションの実行**: `eeslism.Entry(*filename, *efl_path)` を呼び出すことで、
+- **シミュレーションの実行**: `eeslism.NewSimulation(*filename, *efl_path)` の `RunContext(ctx)` を呼び出すことで、
   実際のエネルギーシミュレーションが開始されます。
-  `eeslism.Entry`関数は、入力データの読み込み、モデルの初期化、
+  `RunContext`関数は、入力データの読み込み、モデルの初期化、
   時間ステップごとの計算ループ、そして結果の出力といった一連のプロセスを統括します。
+- **パラメータ**: `-D NAME=VALUE`（繰り返し可）は、入力データファイルの `$define NAME` より優先する
+  パラメータの値です（eeslism.Simulation.Defines）。1つの値だけを変えた計算に用います。
+- **文字コード**: `--encoding` は入力データファイル、EFLファイル、VCFILE などの文字コードです。
+  既定の `auto` では UTF-8 として正しくないファイルを Shift_JIS とみなします。
+  `--output-encoding` は出力ファイルの文字コード（既定は `utf-8`）です。
+- **サブコマンド**: 第1引数が `fmu` の場合は、入力データファイルを FMU に書き出します（`fmuMain`）。
+  `batch` の場合は、値を置き換えた複数のケースを並行して計算します（`batchMain`）。
+  `serve` の場合は、HTTP で計算を受け付けるジョブサーバーを起動します（`serveMain`）。
+  `convert` の場合は、入力データファイルを JSON、YAML に、またはその逆に変換します（`convertMain`）。
+  `check` の場合は、入力データファイルを計算せずに検査し、誤りを行番号とともに表示します（`checkMain`）。
+  `lsp` の場合は、エディタのための Language Server を標準入出力で起動します（`lspMain`）。
+  `import` の場合は、gbXML、EnergyPlus の IDF のモデルを入力データファイルに変換します（`importMain`）。
+  `geometry` の場合は、日影計算の面を glTF、OBJ に書き出します（`geometryMain`）。
+- **中断**: Ctrl-C（SIGINT）を受け取ると時間ステップの間で計算を中断し、
+  それまでの計算結果を出力ファイルに書き出して終了します。
+- **終了コード**: 入力データの誤りなどでシミュレーションを継続できない場合、
+  `RunContext`はエラーを返します。エラーメッセージを標準エラー出力に表示し、
+  エラーが持つ終了コード（C版の`EXIT_*`に対応）でプログラムを終了します。
 - **ログ出力**: `log.SetFlags(log.Lmicroseconds)` は、
   ログメッセージにマイクロ秒単位のタイムスタンプを含める設定です。
   これは、シミュレーションの実行時間や、
@@ -42,6 +65,36 @@ main (Main Entry Point of EESLISM Go)
 func main() {
 	log.SetFlags(log.Lmicroseconds)
 
+	// サブコマンド
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fmu":
+			fmuMain(os.Args[1:])
+			return
+		case "batch":
+			batchMain(os.Args[1:])
+			return
+		case "serve":
+			serveMain(os.Args[1:])
+			return
+		case "convert":
+			convertMain(os.Args[1:])
+			return
+		case "check":
+			checkMain(os.Args[1:])
+			return
+		case "lsp":
+			lspMain(os.Args[1:])
+			return
+		case "import":
+			importMain(os.Args[1:])
+			return
+		case "geometry":
+			geometryMain(os.Args[1:])
+			return
+		}
+	}
+
 	// コマンドライン引数の処理
 	parser := argparse.NewParser("EESLISIM Go", "a general-purpose simulation program for building thermal-environmental control systems consisting of both buildings and facilities")
 
@@ -51,16 +104,77 @@ func main() {
 
 	efl_path := parser.String("", "efl", &argparse.Options{
 		Default: "Base",
-		Help:    "EFLファイルのディレクトリ"})
+		Help:    "EFLファイルのディレクトリ（ディレクトリがない場合は組み込みのBaseを用いる）"})
+
+	defines := parser.StringList("D", "define", &argparse.Options{
+		Help: "パラメータの値 NAME=VALUE（入力データファイルの $define より優先する）"})
+
+	encoding := parser.String("", "encoding", &argparse.Options{
+		Default: "auto",
+		Help:    "入力データファイル、EFLファイルの文字コード（auto、utf-8、shift_jis）"})
+
+	outputEncoding := parser.String("", "output-encoding", &argparse.Options{
+		Default: "utf-8",
+		Help:    "出力ファイルの文字コード（utf-8、shift_jis）"})
 
 	err := parser.Parse(os.Args)
 	if err != nil {
 		fmt.Print(parser.Usage(err))
 	}
 
+	sim := eeslism.NewSimulation(*filename, eflPath(*efl_path))
+	sim.Defines, err = parseDefines(*defines)
+	exitOnError(err)
+	sim.Encoding, sim.OutputEncoding = *encoding, *outputEncoding
+
 	// if len(*efl_path) > 0 {
 	// 	os.Chdir(*efl_path)
 	// }
 
-	eeslism.Entry(*filename, *efl_path)
+	// Ctrl-C で中断した場合も、それまでの計算結果を出力する
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	exitOnError(sim.RunContext(ctx))
+}
+
+// parseDefines は `-D NAME=VALUE` の並びをパラメータの値にします。
+func parseDefines(defs []string) (map[string]string, error) {
+	if len(defs) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(defs))
+	for _, d := range defs {
+		name, value, ok := strings.Cut(d, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid -D %q: NAME=VALUE is expected", d)
+		}
+		m[strings.TrimSpace(name)] = value
+	}
+	return m, nil
+}
+
+// eflPath は EFLファイルのディレクトリを返します。
+// 既定の Base ディレクトリがない場合は空文字列を返し、実行ファイルに埋め込まれた Base を用います。
+func eflPath(dir string) string {
+	if dir == "Base" {
+		if _, err := os.Stat(dir); err != nil {
+			return ""
+		}
+	}
+	return dir
+}
+
+// exitOnError はエラーを標準エラー出力に表示し、エラーが持つ終了コードでプログラムを終了します。
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+	code := 1
+	var ec interface{ ExitCode() int }
+	if errors.As(err, &ec) {
+		code = ec.ExitCode()
+	}
+	os.Exit(code)
 }
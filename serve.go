@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/akamensky/argparse"
+	eeslism "github.com/archlabjp/eeslism-go/eeslism"
+)
+
+/*
+serveMain (HTTP Job Server Command)
+
+`eeslism serve` サブコマンドです。入力データファイルを HTTP で受け付けて計算する
+ジョブサーバー（`eeslism.Server`）を起動します。
+例: `eeslism serve --addr localhost:8080 -j 4`
+
+  - `--addr`: 待ち受けるアドレス
+  - `-j`: 同時に計算するジョブの数。省略した場合は CPU 数
+  - `--queue`: 計算待ちのジョブの上限
+  - `--retention`、`--max-jobs`: 終了したジョブを保持する期間と数の上限
+
+Ctrl-C で受け付けを停止し、計算中のジョブを中断して終了します。
+*/
+func serveMain(args []string) {
+	parser := argparse.NewParser("eeslism serve", "Run simulations submitted over HTTP")
+
+	addr := parser.String("", "addr", &argparse.Options{
+		Default: "localhost:8080",
+		Help:    "待ち受けるアドレス"})
+
+	efl_path := parser.String("", "efl", &argparse.Options{
+		Default: "Base",
+		Help:    "EFLファイルのディレクトリ"})
+
+	workers := parser.Int("j", "jobs", &argparse.Options{
+		Default: runtime.NumCPU(),
+		Help:    "同時に計算するジョブの数"})
+
+	queue := parser.Int("", "queue", &argparse.Options{
+		Default: 64,
+		Help:    "計算待ちのジョブの上限"})
+
+	retention := parser.String("", "retention", &argparse.Options{
+		Default: "1h",
+		Help:    "終了したジョブを保持する期間（例: 30m、24h）"})
+
+	maxJobs := parser.Int("", "max-jobs", &argparse.Options{
+		Default: 100,
+		Help:    "保持する終了したジョブの上限"})
+
+	if err := parser.Parse(args); err != nil {
+		fmt.Print(parser.Usage(err))
+		os.Exit(2)
+	}
+
+	keep, err := time.ParseDuration(*retention)
+	if err != nil {
+		fmt.Print(parser.Usage(fmt.Errorf("--retention: %w", err)))
+		os.Exit(2)
+	}
+
+	s := eeslism.NewServer(eeslism.ServerOptions{
+		EflPath:   eflPath(*efl_path),
+		Workers:   *workers,
+		QueueSize: *queue,
+		Retention: keep,
+		MaxJobs:   *maxJobs,
+	})
+	srv := &http.Server{Addr: *addr, Handler: s}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		exitOnError(err)
+	}
+	s.Close()
+}
@@ -0,0 +1,281 @@
+//go:build js && wasm
+
+/*
+wasm パッケージは、EESLISM をブラウザなどの JavaScript から実行するための WebAssembly モジュールです。
+
+	GOOS=js GOARCH=wasm go build -o eeslism.wasm ./wasm
+
+でビルドし、Go の wasm_exec.js とともに読み込むと、グローバルオブジェクト `eeslism` に
+`run(options)` が登録されます。`run` は計算を開始し、計算結果で解決される Promise を返します。
+
+options:
+  - input: 入力データファイルの内容（必須）
+  - inputName: 入力データファイル名。計算結果のファイル名の基になる（省略時は "input.txt"）
+  - weather: 気象データファイルの内容
+  - weatherName: 気象データファイル名（省略時は入力データファイルの `FILE w=` の名前）
+  - efl: EFL ファイル名と内容のオブジェクト。省略時は埋め込みの Base を用いる
+  - files: 入力データファイルから参照するその他のファイル名と内容のオブジェクト
+  - onProgress: 進捗状況を受け取る関数。計算済みの割合が 1% 進むごとに呼ばれる
+  - signal: 計算を中断する AbortSignal
+
+計算結果は `{files, tables}` で、files は全ての計算結果のファイル名と内容、
+tables は表形式のファイル（`_rm`、`_sc`、`_dr` など）の列ごとの値（eeslism.OutputTable）です。
+入力データ、気象データの誤りの場合、Promise は section、keyword、component を持つ Error で拒否されます。
+中断した場合の Error の name は "AbortError" です。
+*/
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"math"
+	"regexp"
+	"strings"
+	"syscall/js"
+	"time"
+
+	base "github.com/archlabjp/eeslism-go/Base"
+	"github.com/archlabjp/eeslism-go/eeslism"
+)
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("run", js.FuncOf(run))
+	api.Set("version", eeslism.EEVERSION)
+	js.Global().Set("eeslism", api)
+
+	// 関数が呼ばれるまで終了しない
+	select {}
+}
+
+// weatherPattern は入力データファイルの GDAT の気象データファイル名です。
+var weatherPattern = regexp.MustCompile(`\bFILE\s+w=([^\s;]+)`)
+
+// run は JavaScript の eeslism.run(options) です。
+func run(this js.Value, args []js.Value) interface{} {
+	promise := js.Global().Get("Promise")
+	if len(args) < 1 || args[0].Type() != js.TypeObject {
+		return promise.Call("reject", jsError(errors.New("eeslism.run: options object is required")))
+	}
+	opts := args[0]
+
+	executor := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resolve, reject := args[0], args[1]
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var onAbort js.Func
+		if signal := opts.Get("signal"); signal.Truthy() {
+			if signal.Get("aborted").Bool() {
+				cancel()
+			}
+			onAbort = js.FuncOf(func(js.Value, []js.Value) interface{} {
+				cancel()
+				return nil
+			})
+			signal.Call("addEventListener", "abort", onAbort)
+		}
+
+		// JavaScript の呼び出しから戻るため、計算は goroutine で行う
+		go func() {
+			defer cancel()
+			if onAbort.Truthy() {
+				defer func() {
+					opts.Get("signal").Call("removeEventListener", "abort", onAbort)
+					onAbort.Release()
+				}()
+			}
+			result, err := simulate(ctx, opts)
+			if err != nil {
+				reject.Invoke(jsError(err))
+				return
+			}
+			resolve.Invoke(result)
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return promise.New(executor)
+}
+
+// simulate は options の入力データで計算し、計算結果の JavaScript のオブジェクトを返します。
+func simulate(ctx context.Context, opts js.Value) (js.Value, error) {
+	input := opts.Get("input")
+	if input.Type() != js.TypeString {
+		return js.Undefined(), errors.New("eeslism.run: input must be a string")
+	}
+	name := stringOption(opts, "inputName", "input.txt")
+
+	files := map[string][]byte{name: []byte(input.String())}
+	addFiles(files, opts.Get("files"))
+
+	var efl fs.FS = base.FS
+	if lib := opts.Get("efl"); lib.Truthy() {
+		m := map[string][]byte{}
+		addFiles(m, lib)
+		efl = eeslism.OverlayFS(m, nil)
+	}
+	if weather := opts.Get("weather"); weather.Type() == js.TypeString {
+		wname := stringOption(opts, "weatherName", "")
+		if wname == "" {
+			m := weatherPattern.FindStringSubmatch(input.String())
+			if m == nil {
+				return js.Undefined(), errors.New("eeslism.run: weatherName is required when the input has no FILE w=")
+			}
+			wname = m[1]
+		}
+		efl = eeslism.OverlayFS(map[string][]byte{wname: []byte(weather.String())}, efl)
+	}
+
+	sim := eeslism.NewSimulation(name, "")
+	sim.FS = eeslism.OverlayFS(files, nil)
+	sim.EflFS = efl
+	out := new(eeslism.MemorySink)
+	sim.Output = out
+
+	onProgress := opts.Get("onProgress")
+	percent := -1
+	sim.ProgressFunc = func(p eeslism.Progress) {
+		if n := int(p.Fraction * 100); n != percent {
+			percent = n
+			if onProgress.Type() == js.TypeFunction {
+				onProgress.Invoke(progressObject(p))
+			}
+			// ブラウザが画面を更新できるように JavaScript のイベントループに戻る
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if err := sim.RunContext(ctx); err != nil {
+		return js.Undefined(), err
+	}
+
+	result := js.Global().Get("Object").New()
+	resultFiles := js.Global().Get("Object").New()
+	tables := js.Global().Get("Object").New()
+	for _, fname := range out.Names() {
+		data := out.Bytes(fname)
+		resultFiles.Set(fname, string(data))
+		if t, err := eeslism.ReadOutputTable(bytes.NewReader(data)); err == nil {
+			tables.Set(fname, tableObject(t))
+		}
+	}
+	result.Set("files", resultFiles)
+	result.Set("tables", tables)
+	return result, nil
+}
+
+// stringOption は文字列のオプション key を返します。指定がない場合は def を返します。
+func stringOption(opts js.Value, key, def string) string {
+	if v := opts.Get(key); v.Type() == js.TypeString && v.String() != "" {
+		return v.String()
+	}
+	return def
+}
+
+// addFiles は JavaScript のオブジェクト obj のファイル名と内容を m に加えます。
+func addFiles(m map[string][]byte, obj js.Value) {
+	if obj.Type() != js.TypeObject {
+		return
+	}
+	keys := js.Global().Get("Object").Call("keys", obj)
+	for i := 0; i < keys.Length(); i++ {
+		name := keys.Index(i).String()
+		m[strings.TrimPrefix(name, "./")] = []byte(obj.Get(name).String())
+	}
+}
+
+// progressObject は進捗状況の JavaScript のオブジェクトを返します。
+func progressObject(p eeslism.Progress) js.Value {
+	return js.ValueOf(map[string]interface{}{
+		"mon":      p.Daytm.Mon,
+		"day":      p.Daytm.Day,
+		"time":     p.Daytm.Ttmm,
+		"warmup":   p.Warmup,
+		"step":     p.Step,
+		"steps":    p.Steps,
+		"fraction": p.Fraction,
+	})
+}
+
+// tableObject は計算結果の表の JavaScript のオブジェクトを返します。
+// 数値の列は Float64Array、書式が c の列は文字列の配列です。
+func tableObject(t *eeslism.OutputTable) js.Value {
+	obj := js.ValueOf(map[string]interface{}{
+		"title":   t.Title,
+		"weather": t.Weather,
+		"tid":     t.Tid,
+		"timeid":  t.Timeid,
+		"dtm":     t.Dtm,
+	})
+	if t.Mon != nil {
+		obj.Set("mon", intArray(t.Mon))
+	}
+	if t.Day != nil {
+		obj.Set("day", intArray(t.Day))
+	}
+	if t.Time != nil {
+		obj.Set("time", float64Array(t.Time))
+	}
+	columns := js.Global().Get("Array").New(len(t.Columns))
+	for i, c := range t.Columns {
+		col := js.ValueOf(map[string]interface{}{
+			"name":   c.Name,
+			"unit":   c.Unit,
+			"format": c.Format,
+		})
+		if c.Format == "c" {
+			text := make([]interface{}, len(c.Text))
+			for j, s := range c.Text {
+				text[j] = s
+			}
+			col.Set("values", text)
+		} else {
+			col.Set("values", float64Array(c.Values))
+		}
+		columns.SetIndex(i, col)
+	}
+	obj.Set("columns", columns)
+	return obj
+}
+
+// float64Array は xs を JavaScript の Float64Array にコピーします。
+func float64Array(xs []float64) js.Value {
+	b := make([]byte, 8*len(xs))
+	for i, x := range xs {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(x))
+	}
+	u8 := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(u8, b)
+	return js.Global().Get("Float64Array").New(u8.Get("buffer"))
+}
+
+func intArray(xs []int) js.Value {
+	a := make([]interface{}, len(xs))
+	for i, x := range xs {
+		a[i] = x
+	}
+	return js.ValueOf(a)
+}
+
+// jsError は err の JavaScript の Error を返します。入力データ、気象データの誤りの場合は位置を加えます。
+func jsError(err error) js.Value {
+	e := js.Global().Get("Error").New(err.Error())
+	var ie *eeslism.InputError
+	var we *eeslism.WeatherError
+	if errors.As(err, &ie) {
+		e.Set("section", ie.Section)
+		e.Set("keyword", ie.Keyword)
+		e.Set("component", ie.Component)
+	} else if errors.As(err, &we) {
+		e.Set("section", we.Section)
+		e.Set("keyword", we.Keyword)
+		e.Set("component", we.Component)
+	}
+	if errors.Is(err, context.Canceled) {
+		e.Set("name", "AbortError")
+	}
+	return e
+}